@@ -84,6 +84,7 @@ func main() {
 		cfg.Security.SessionMaxAgeSecs,
 		cfg.Security.OAuthStateMaxAgeSecs,
 	)
+	sessionManager.SetSlidingExpiration(cfg.Security.SessionRefreshWindowSecs, cfg.Security.SessionAbsoluteMaxAgeSecs)
 
 	// As of #79 the Google OAuth client_id and client_secret are
 	// stored per-source in the database, so the global env-var
@@ -100,23 +101,27 @@ func main() {
 	// config at sync time from the credentials stored on each Google
 	// source row, so no instance-level OAuth config is passed in.
 	syncEngine := caldav.NewSyncEngine(database, encryptor)
+	syncEngine.SetDBLockRetry(cfg.Database.MaxLockRetries,
+		time.Duration(cfg.Database.LockBackoffBaseMs)*time.Millisecond)
+	syncEngine.SetDeleteConfirmations(cfg.Sync.DeleteConfirmations)
+	syncEngine.SetAuthFailureThreshold(cfg.Sync.AuthFailureThreshold)
+	syncEngine.SetConnectionTestRetry(cfg.Sync.ConnectAttempts,
+		time.Duration(cfg.Sync.ConnectBackoffMs)*time.Millisecond)
+	syncEngine.SetUserAgent(cfg.CalDAV.UserAgent)
+	syncEngine.SetEventLimits(cfg.CalDAV.MaxEventsPerCalendar, cfg.CalDAV.MaxEventBytes)
+	syncEngine.SetMaxResponseBytes(cfg.CalDAV.MaxResponseBytes)
+	syncEngine.SetHTTP2(!cfg.CalDAV.DisableHTTP2)
+	syncEngine.SetProxy(cfg.Proxy.URL, cfg.Proxy.NoProxy)
+	syncEngine.SetRecurrencePolicy(caldav.RecurrencePolicy(cfg.Sync.RecurrencePolicy))
+	syncEngine.SetEncodeFailurePolicy(caldav.EncodeFailurePolicy(cfg.Sync.EncodeFailurePolicy))
+	syncEngine.SetCreateDestCalendar(cfg.Sync.CreateDestCalendar)
+	syncEngine.SetWarningLimits(cfg.Sync.WarningFailCount, cfg.Sync.WarningFailPercent)
+	syncEngine.SetVanishedCalendarPolicy(caldav.VanishedCalendarPolicy(cfg.Sync.VanishedCalendarPolicy))
+	syncEngine.SetDeadLetterThreshold(cfg.Sync.DeadLetterAfter)
+	syncEngine.SetDeletionBackups(cfg.Backup.Enabled)
 
 	// Initialize notifier for alerts
-	notifyCfg := &notify.Config{
-		WebhookEnabled:  cfg.Alerts.WebhookEnabled,
-		WebhookURL:      cfg.Alerts.WebhookURL,
-		EmailEnabled:    cfg.Alerts.EmailEnabled,
-		SMTPHost:        cfg.Alerts.SMTPHost,
-		SMTPPort:        cfg.Alerts.SMTPPort,
-		SMTPUsername:    cfg.Alerts.SMTPUsername,
-		SMTPPassword:    cfg.Alerts.SMTPPassword,
-		SMTPFrom:        cfg.Alerts.SMTPFrom,
-		SMTPTo:          cfg.Alerts.SMTPTo,
-		SMTPTLS:         cfg.Alerts.SMTPTLS,
-		CooldownPeriod:  time.Duration(cfg.Alerts.CooldownMinutes) * time.Minute,
-		MaxSendAttempts: cfg.Alerts.MaxSendAttempts,
-		InitialBackoff:  time.Duration(cfg.Alerts.InitialBackoffMS) * time.Millisecond,
-	}
+	notifyCfg := web.NotifyConfig(cfg.Alerts, cfg.Server.BaseURL)
 
 	// Validate notification config if any alerts are enabled
 	if notifyCfg.WebhookEnabled || notifyCfg.EmailEnabled {
@@ -126,6 +131,10 @@ func main() {
 	}
 
 	notifier := notify.New(notifyCfg)
+	if err := notifier.SetProxy(cfg.Proxy.URL, cfg.Proxy.NoProxy); err != nil {
+		log.Fatalf("Invalid proxy configuration: %v", err)
+	}
+	syncEngine.SetNotifier(notifier)
 
 	if notifier.IsEnabled() {
 		log.Printf("Alert notifications enabled (webhook: %v, email: %v, cooldown: %d min)",
@@ -133,7 +142,10 @@ func main() {
 	}
 
 	// Initialize scheduler with configurable log retention
-	sched := scheduler.New(database, syncEngine, notifier, cfg.LogRetentionDays)
+	sched := scheduler.New(database, syncEngine, notifier, cfg.Sync.LogRetentionDays)
+	sched.SetMaxConcurrentSyncs(cfg.Sync.MaxConcurrent)
+	sched.SetMaxSyncDuration(time.Duration(cfg.Sync.MaxDurationSecs) * time.Second)
+	sched.SetHealthLogInterval(time.Duration(cfg.Sync.HealthLogMinutes) * time.Minute)
 
 	// Initialize automated backup if enabled
 	if cfg.Backup.Enabled {
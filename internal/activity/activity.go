@@ -7,38 +7,79 @@ import (
 
 // SyncActivity represents the current state of a sync operation.
 type SyncActivity struct {
-	SourceID        string    `json:"source_id"`
-	SourceName      string    `json:"source_name"`
-	Status          string    `json:"status"` // "running", "completed", "error"
-	CurrentCalendar string    `json:"current_calendar,omitempty"`
-	TotalCalendars  int       `json:"total_calendars"`
-	Calendarssynced int       `json:"calendars_synced"`
-	EventsProcessed int       `json:"events_processed"`
-	EventsCreated   int       `json:"events_created"`
-	EventsUpdated   int       `json:"events_updated"`
-	EventsDeleted   int       `json:"events_deleted"`
-	EventsSkipped   int       `json:"events_skipped"`
-	StartedAt       time.Time `json:"started_at"`
+	SourceID        string     `json:"source_id"`
+	SourceName      string     `json:"source_name"`
+	Status          string     `json:"status"` // "running", "completed", "error"
+	CurrentCalendar string     `json:"current_calendar,omitempty"`
+	TotalCalendars  int        `json:"total_calendars"`
+	Calendarssynced int        `json:"calendars_synced"`
+	EventsProcessed int        `json:"events_processed"`
+	EventsCreated   int        `json:"events_created"`
+	EventsUpdated   int        `json:"events_updated"`
+	EventsDeleted   int        `json:"events_deleted"`
+	EventsSkipped   int        `json:"events_skipped"`
+	StartedAt       time.Time  `json:"started_at"`
 	CompletedAt     *time.Time `json:"completed_at,omitempty"`
-	Duration        string    `json:"duration,omitempty"`
-	Message         string    `json:"message,omitempty"`
-	Errors          []string  `json:"errors,omitempty"`
+	Duration        string     `json:"duration,omitempty"`
+	Message         string     `json:"message,omitempty"`
+	Errors          []string   `json:"errors,omitempty"`
+}
+
+// RunSummary is what the tracker keeps of one finished sync.
+type RunSummary struct {
+	Status          string        `json:"status"` // "completed", "partial" or "error"
+	EventsProcessed int           `json:"events_processed"`
+	EventsCreated   int           `json:"events_created"`
+	EventsUpdated   int           `json:"events_updated"`
+	EventsDeleted   int           `json:"events_deleted"`
+	EventsSkipped   int           `json:"events_skipped"`
+	Duration        time.Duration `json:"duration"`
+	FinishedAt      time.Time     `json:"finished_at"`
+}
+
+// runRing holds the last len(runs) RunSummaries of one source,
+// overwriting the oldest once full.
+type runRing struct {
+	runs  []RunSummary
+	next  int // index the next run is written to
+	count int
+}
+
+func (r *runRing) add(run RunSummary) {
+	r.runs[r.next] = run
+	r.next = (r.next + 1) % len(r.runs)
+	if r.count < len(r.runs) {
+		r.count++
+	}
+}
+
+// newestFirst returns a copy of the held runs, most recent first.
+func (r *runRing) newestFirst() []RunSummary {
+	out := make([]RunSummary, r.count)
+	for i := range out {
+		out[i] = r.runs[(r.next-1-i+len(r.runs))%len(r.runs)]
+	}
+	return out
 }
 
 // Tracker tracks sync activity across all sources.
 type Tracker struct {
-	mu              sync.RWMutex
-	active          map[string]*SyncActivity // sourceID -> activity
-	recent          []*SyncActivity          // Recently completed syncs
-	maxRecentSyncs  int
+	mu               sync.RWMutex
+	active           map[string]*SyncActivity // sourceID -> activity
+	recent           []*SyncActivity          // Recently completed syncs
+	maxRecentSyncs   int
+	runs             map[string]*runRing // sourceID -> finished runs
+	maxRunsPerSource int
 }
 
 // NewTracker creates a new activity tracker.
 func NewTracker() *Tracker {
 	return &Tracker{
-		active:         make(map[string]*SyncActivity),
-		recent:         make([]*SyncActivity, 0),
-		maxRecentSyncs: 20, // Keep last 20 completed syncs
+		active:           make(map[string]*SyncActivity),
+		recent:           make([]*SyncActivity, 0),
+		maxRecentSyncs:   20, // Keep last 20 completed syncs
+		runs:             make(map[string]*runRing),
+		maxRunsPerSource: 10,
 	}
 }
 
@@ -122,6 +163,22 @@ func (t *Tracker) FinishSync(sourceID string, success bool, message string, erro
 		activity.Status = "error"
 	}
 
+	ring, ok := t.runs[sourceID]
+	if !ok {
+		ring = &runRing{runs: make([]RunSummary, t.maxRunsPerSource)}
+		t.runs[sourceID] = ring
+	}
+	ring.add(RunSummary{
+		Status:          activity.Status,
+		EventsProcessed: activity.EventsProcessed,
+		EventsCreated:   activity.EventsCreated,
+		EventsUpdated:   activity.EventsUpdated,
+		EventsDeleted:   activity.EventsDeleted,
+		EventsSkipped:   activity.EventsSkipped,
+		Duration:        now.Sub(activity.StartedAt),
+		FinishedAt:      now,
+	})
+
 	// Move to recent list
 	t.recent = append([]*SyncActivity{activity}, t.recent...)
 	if len(t.recent) > t.maxRecentSyncs {
@@ -160,6 +217,20 @@ func (t *Tracker) GetRecent() []*SyncActivity {
 	return result
 }
 
+// RecentRuns returns the last completed syncs of sourceID, most recent
+// first. The tracker keeps up to 10 per source, in memory only, so the
+// list starts empty after a restart.
+func (t *Tracker) RecentRuns(sourceID string) []RunSummary {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	ring, ok := t.runs[sourceID]
+	if !ok {
+		return []RunSummary{}
+	}
+	return ring.newestFirst()
+}
+
 // GetAll returns both active and recent syncs.
 func (t *Tracker) GetAll() map[string]interface{} {
 	return map[string]interface{}{
@@ -175,3 +246,48 @@ func (t *Tracker) IsSourceSyncing(sourceID string) bool {
 	_, exists := t.active[sourceID]
 	return exists
 }
+
+// Snapshot is a point-in-time copy of one source's sync progress. It
+// holds no pointers into the tracker, so callers may keep or modify it.
+type Snapshot struct {
+	SourceID        string    `json:"source_id"`
+	Status          string    `json:"status"` // "running" or "idle"
+	Running         bool      `json:"running"`
+	CurrentCalendar string    `json:"current_calendar,omitempty"`
+	CalendarIndex   int       `json:"calendar_index"`
+	TotalCalendars  int       `json:"total_calendars"`
+	EventsProcessed int       `json:"events_processed"`
+	EventsCreated   int       `json:"events_created"`
+	EventsUpdated   int       `json:"events_updated"`
+	EventsDeleted   int       `json:"events_deleted"`
+	EventsSkipped   int       `json:"events_skipped"`
+	StartedAt       time.Time `json:"started_at,omitzero"`
+	Duration        string    `json:"duration,omitempty"`
+}
+
+// Snapshot returns the progress of the sync running for sourceID, or an
+// idle snapshot with only SourceID and Status set when there is none.
+func (t *Tracker) Snapshot(sourceID string) Snapshot {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	activity, exists := t.active[sourceID]
+	if !exists {
+		return Snapshot{SourceID: sourceID, Status: "idle"}
+	}
+	return Snapshot{
+		SourceID:        sourceID,
+		Status:          "running",
+		Running:         true,
+		CurrentCalendar: activity.CurrentCalendar,
+		CalendarIndex:   activity.Calendarssynced,
+		TotalCalendars:  activity.TotalCalendars,
+		EventsProcessed: activity.EventsProcessed,
+		EventsCreated:   activity.EventsCreated,
+		EventsUpdated:   activity.EventsUpdated,
+		EventsDeleted:   activity.EventsDeleted,
+		EventsSkipped:   activity.EventsSkipped,
+		StartedAt:       activity.StartedAt,
+		Duration:        time.Since(activity.StartedAt).Round(time.Millisecond).String(),
+	}
+}
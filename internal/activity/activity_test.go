@@ -0,0 +1,112 @@
+package activity
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestTrackerSnapshot(t *testing.T) {
+	tr := NewTracker()
+
+	idle := tr.Snapshot("src-1")
+	if idle.Running || idle.Status != "idle" || idle.SourceID != "src-1" || !idle.StartedAt.IsZero() {
+		t.Errorf("expected an idle snapshot, got %+v", idle)
+	}
+
+	tr.StartSync("src-1", "Work", 3)
+	tr.UpdateCalendar("src-1", "Team", 2)
+	tr.IncrementProgress("src-1", 1, 2, 0, 4, 7)
+	snap := tr.Snapshot("src-1")
+	if !snap.Running || snap.Status != "running" || snap.CurrentCalendar != "Team" ||
+		snap.CalendarIndex != 2 || snap.TotalCalendars != 3 {
+		t.Errorf("unexpected running snapshot %+v", snap)
+	}
+	if snap.EventsCreated != 1 || snap.EventsUpdated != 2 || snap.EventsSkipped != 4 || snap.EventsProcessed != 7 {
+		t.Errorf("unexpected counts in %+v", snap)
+	}
+	if snap.StartedAt.IsZero() || snap.Duration == "" {
+		t.Errorf("expected start time and duration, got %+v", snap)
+	}
+
+	// The snapshot is a copy: later progress does not show through.
+	tr.IncrementProgress("src-1", 5, 0, 0, 0, 5)
+	if snap.EventsCreated != 1 {
+		t.Error("snapshot changed after further progress")
+	}
+	if other := tr.Snapshot("src-2"); other.Running {
+		t.Errorf("expected another source to be idle, got %+v", other)
+	}
+
+	tr.FinishSync("src-1", true, "", nil)
+	if got := tr.Snapshot("src-1"); got.Running || got.Status != "idle" {
+		t.Errorf("expected idle after the sync finished, got %+v", got)
+	}
+}
+
+func TestTrackerRecentRuns(t *testing.T) {
+	tr := NewTracker()
+	if runs := tr.RecentRuns("src-1"); len(runs) != 0 {
+		t.Fatalf("expected no runs before any sync finished, got %+v", runs)
+	}
+
+	total := tr.maxRunsPerSource + 3
+	for i := 1; i <= total; i++ {
+		tr.StartSync("src-1", "Work", 1)
+		tr.UpdateProgress("src-1", i, 0, 0, 0, i)
+		tr.FinishSync("src-1", i%2 == 0, "", nil)
+	}
+	tr.StartSync("src-2", "Home", 1)
+	tr.FinishSync("src-2", true, "", []string{"one calendar failed"})
+
+	runs := tr.RecentRuns("src-1")
+	if len(runs) != tr.maxRunsPerSource {
+		t.Fatalf("expected the last %d runs, got %d", tr.maxRunsPerSource, len(runs))
+	}
+	for i, run := range runs {
+		want := total - i
+		if run.EventsCreated != want {
+			t.Errorf("run %d: expected sync #%d, got #%d", i, want, run.EventsCreated)
+		}
+		wantStatus := "error"
+		if want%2 == 0 {
+			wantStatus = "completed"
+		}
+		if run.Status != wantStatus || run.FinishedAt.IsZero() {
+			t.Errorf("run %d: unexpected summary %+v", i, run)
+		}
+	}
+
+	// The returned slice is a copy.
+	runs[0].EventsCreated = -1
+	if tr.RecentRuns("src-1")[0].EventsCreated != total {
+		t.Error("modifying RecentRuns' result changed the tracker")
+	}
+
+	if other := tr.RecentRuns("src-2"); len(other) != 1 || other[0].Status != "partial" {
+		t.Errorf("expected one partial run for src-2, got %+v", other)
+	}
+}
+
+func TestTrackerRecentRuns_Concurrent(t *testing.T) {
+	tr := NewTracker()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				tr.StartSync(id, id, 1)
+				tr.FinishSync(id, true, "", nil)
+				_ = tr.RecentRuns(id)
+			}
+		}(fmt.Sprintf("src-%d", i%3))
+	}
+	wg.Wait()
+
+	for i := 0; i < 3; i++ {
+		if runs := tr.RecentRuns(fmt.Sprintf("src-%d", i)); len(runs) != tr.maxRunsPerSource {
+			t.Errorf("src-%d: expected %d runs, got %d", i, tr.maxRunsPerSource, len(runs))
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package auth
+
+import "github.com/gin-gonic/gin"
+
+// Error codes the auth middleware answers with. The web package
+// re-exports them alongside the rest of the API's codes.
+const (
+	ErrCodeUnauthorized = "UNAUTHORIZED"
+	ErrCodeForbidden    = "FORBIDDEN"
+)
+
+// APIError is the body of every error response from the JSON API.
+// Code is stable and meant for programs; Message is for people and
+// may be reworded.
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details any    `json:"details,omitempty"`
+	// Error repeats Message for clients written against the earlier
+	// {"error": "..."} body.
+	Error string `json:"error"`
+}
+
+// AbortWithError stops the handler chain with an APIError carrying
+// the given status, code and message. Middleware in this package and
+// in web use it so every error body has the same shape.
+func AbortWithError(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, APIError{Code: code, Message: message, Error: message})
+}
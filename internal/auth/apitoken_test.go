@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGenerateAPIToken(t *testing.T) {
+	token, hash, err := GenerateAPIToken()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(token) < 40 || token[:len(apiTokenPrefix)] != apiTokenPrefix {
+		t.Errorf("unexpected token format: %q", token)
+	}
+	if hash != HashAPIToken(token) || hash == token {
+		t.Error("hash must be the SHA-256 of the token, not the token itself")
+	}
+
+	other, _, _ := GenerateAPIToken()
+	if other == token {
+		t.Error("expected unique tokens")
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"Bearer abc", "abc"},
+		{"bearer abc", "abc"},
+		{"Basic dXNlcjpwYXNz", ""},
+		{"Bearer", ""},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.header != "" {
+			r.Header.Set("Authorization", tt.header)
+		}
+		if got := bearerToken(r); got != tt.want {
+			t.Errorf("bearerToken(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"crypto/subtle"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -25,12 +26,33 @@ func RequireAuth(sm *SessionManager) gin.HandlerFunc {
 			return
 		}
 
+		sm.refresh(c, session)
+
 		// Store session data in context for handlers to use
 		c.Set(ContextKeySession, session)
 		c.Next()
 	}
 }
 
+// RequireAdmin is a middleware that lets only instance administrators
+// through. It runs after RequireAuth or RequireAuthOrToken, and
+// answers 401 without a session and 403 for a session without
+// IsAdmin.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		session := GetCurrentUser(c)
+		if session == nil {
+			AbortWithError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+			return
+		}
+		if !session.IsAdmin {
+			AbortWithError(c, http.StatusForbidden, ErrCodeForbidden, "Administrator access required")
+			return
+		}
+		c.Next()
+	}
+}
+
 // GetCurrentUser retrieves the current user's session data from the Gin context.
 func GetCurrentUser(c *gin.Context) *SessionData {
 	session, exists := c.Get(ContextKeySession)
@@ -67,7 +89,7 @@ func ValidateCSRF(sm *SessionManager) gin.HandlerFunc {
 		// Get session
 		session, err := sm.Get(c.Request)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "session required"})
+			AbortWithError(c, http.StatusForbidden, ErrCodeForbidden, "session required")
 			return
 		}
 
@@ -77,27 +99,8 @@ func ValidateCSRF(sm *SessionManager) gin.HandlerFunc {
 			csrfToken = c.GetHeader("X-CSRF-Token")
 		}
 
-		// Validate token with a constant-time comparison so the
-		// comparison loop's timing does not reveal the token
-		// byte-by-byte. Go's default `!=` on strings short-circuits
-		// on the first mismatching byte, which in theory lets an
-		// attacker measure response latency and deduce prefix
-		// length. In practice the CSRF token is 32 random bytes
-		// behind HTTPS and the network jitter swamps any timing
-		// signal, so this fix is defense-in-depth rather than a
-		// practical exploit fix — but subtle.ConstantTimeCompare
-		// is free and the wrong default is the kind of thing that
-		// will come up in a security audit or the next static
-		// analyzer pass. Matches the standard library guidance
-		// for comparing MACs and tokens. (#111)
-		//
-		// subtle.ConstantTimeCompare requires equal-length inputs
-		// and panics on nil; the csrfToken == "" short-circuit
-		// handles the zero-length case, and we convert to []byte
-		// so the comparison operates on raw bytes rather than
-		// Go's string-interning fast path.
-		if csrfToken == "" || subtle.ConstantTimeCompare([]byte(csrfToken), []byte(session.CSRFToken)) != 1 {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "invalid CSRF token"})
+		if !CSRFTokenMatches(csrfToken, session.CSRFToken) {
+			AbortWithError(c, http.StatusForbidden, ErrCodeForbidden, "invalid CSRF token")
 			return
 		}
 
@@ -105,13 +108,75 @@ func ValidateCSRF(sm *SessionManager) gin.HandlerFunc {
 	}
 }
 
+// ValidateAPICSRF is the /api counterpart of ValidateCSRF. It runs
+// after RequireAuthOrToken and requires state-changing requests to
+// carry the session's CSRF token in the X-CSRF-Token header (the SPA
+// reads it from /api/auth/status). Requests authenticated with an API
+// token carry no cookie for a forged request to ride on, so they are
+// exempt.
+func ValidateAPICSRF() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet ||
+			c.Request.Method == http.MethodHead ||
+			c.Request.Method == http.MethodOptions ||
+			IsTokenAuth(c) {
+			c.Next()
+			return
+		}
+
+		session := GetCurrentUser(c)
+		if session == nil {
+			AbortWithError(c, http.StatusForbidden, ErrCodeForbidden, "session required")
+			return
+		}
+		if !CSRFTokenMatches(c.GetHeader("X-CSRF-Token"), session.CSRFToken) {
+			AbortWithError(c, http.StatusForbidden, ErrCodeForbidden, "invalid CSRF token")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// CSRFTokenMatches reports whether a request's CSRF token is the
+// session's. An empty token never matches.
+//
+// The comparison is constant-time so the comparison loop's timing
+// does not reveal the token byte-by-byte. Go's default `!=` on strings
+// short-circuits on the first mismatching byte, which in theory lets
+// an attacker measure response latency and deduce prefix length. In
+// practice the CSRF token is 32 random bytes behind HTTPS and the
+// network jitter swamps any timing signal, so this is
+// defense-in-depth rather than a practical exploit fix — but
+// subtle.ConstantTimeCompare is free and the wrong default is the kind
+// of thing that will come up in a security audit or the next static
+// analyzer pass. Matches the standard library guidance for comparing
+// MACs and tokens. (#111)
+//
+// subtle.ConstantTimeCompare requires equal-length inputs and panics
+// on nil; the empty-token short-circuit handles the zero-length case,
+// and we convert to []byte so the comparison operates on raw bytes
+// rather than Go's string-interning fast path.
+func CSRFTokenMatches(got, want string) bool {
+	return got != "" && want != "" && subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
+
 // OptionalAuth is a middleware that loads session data if available but doesn't require it.
 func OptionalAuth(sm *SessionManager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		session, err := sm.Get(c.Request)
 		if err == nil {
+			sm.refresh(c, session)
 			c.Set(ContextKeySession, session)
 		}
 		c.Next()
 	}
 }
+
+// refresh extends an active session's expiry; see
+// SessionManager.Refresh. A failure leaves the session as it was.
+func (sm *SessionManager) refresh(c *gin.Context, session *SessionData) {
+	if _, err := sm.Refresh(c.Writer, c.Request, session); err != nil {
+		log.Printf("Failed to refresh session of user %s: %v", session.UserID, err)
+	}
+}
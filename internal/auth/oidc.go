@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"golang.org/x/oauth2"
@@ -24,6 +25,39 @@ type OIDCClaims struct {
 	EmailVerified bool   `json:"email_verified"`
 	Name          string `json:"name"`
 	AvatarURL     string `json:"avatarUrl"`
+
+	// raw holds every claim of the ID token, for HasClaimValue.
+	raw map[string]any
+}
+
+// HasClaimValue reports whether the claim name is value or, for a list
+// claim such as "groups", contains it. A dotted name walks into nested
+// objects, e.g. "realm_access.roles". Values are compared exactly.
+func (c *OIDCClaims) HasClaimValue(name, value string) bool {
+	if name == "" {
+		return false
+	}
+	var claim any = c.raw
+	for _, key := range strings.Split(name, ".") {
+		obj, ok := claim.(map[string]any)
+		if !ok {
+			return false
+		}
+		if claim, ok = obj[key]; !ok {
+			return false
+		}
+	}
+	switch v := claim.(type) {
+	case string:
+		return v == value
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok && s == value {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // OIDCProvider handles OIDC authentication.
@@ -89,6 +123,9 @@ func (p *OIDCProvider) VerifyIDToken(ctx context.Context, token *oauth2.Token) (
 	if err := idToken.Claims(&claims); err != nil {
 		return nil, fmt.Errorf("%w: failed to parse claims: %w", ErrTokenVerify, err)
 	}
+	if err := idToken.Claims(&claims.raw); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse claims: %w", ErrTokenVerify, err)
+	}
 
 	if claims.Email == "" {
 		return nil, ErrMissingEmail
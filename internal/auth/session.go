@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/sessions"
 )
@@ -66,13 +67,27 @@ type SessionData struct {
 	Name      string `json:"name"`
 	Picture   string `json:"picture"`
 	CSRFToken string `json:"csrf_token"`
+	// IsAdmin is set at login for instance administrators; see
+	// RequireAdmin.
+	IsAdmin bool `json:"is_admin"`
+	// IssuedAt is when the user signed in and ExpiresAt when the
+	// session ends unless Refresh extends it. Set by Set; zero for a
+	// session issued before they were recorded.
+	IssuedAt  time.Time `json:"-"`
+	ExpiresAt time.Time `json:"-"`
 }
 
 // SessionManager manages user sessions.
 type SessionManager struct {
 	store            *sessions.CookieStore
 	secure           bool
+	maxAge           time.Duration
 	oauthStateMaxAge int // OAuth state timeout in seconds
+	// refreshWindow and absoluteMaxAge configure sliding expiration;
+	// see SetSlidingExpiration.
+	refreshWindow  time.Duration
+	absoluteMaxAge time.Duration
+	now            func() time.Time
 }
 
 // NewSessionManager creates a new session manager.
@@ -95,10 +110,44 @@ func NewSessionManager(secret string, secure bool, sessionMaxAgeSecs, oauthState
 	return &SessionManager{
 		store:            store,
 		secure:           secure,
+		maxAge:           time.Duration(sessionMaxAgeSecs) * time.Second,
 		oauthStateMaxAge: oauthStateMaxAgeSecs,
+		now:              time.Now,
 	}
 }
 
+// SetSlidingExpiration makes Refresh re-issue a session with a fresh
+// max-age once it is within refreshWindowSecs of expiring, so active
+// users aren't signed out mid-work. No session outlives
+// absoluteMaxAgeSecs from sign-in, however active. A refresh window of
+// 0 turns sliding off; an absolute max age of 0 removes the cap.
+// Wired from SESSION_REFRESH_WINDOW_SECS and
+// SESSION_ABSOLUTE_MAX_AGE_SECS; call before serving requests.
+func (sm *SessionManager) SetSlidingExpiration(refreshWindowSecs, absoluteMaxAgeSecs int) {
+	sm.refreshWindow = time.Duration(refreshWindowSecs) * time.Second
+	sm.absoluteMaxAge = time.Duration(absoluteMaxAgeSecs) * time.Second
+}
+
+// Refresh re-issues data's session cookie with a fresh max-age when it
+// is within the refresh window of expiring, never past the absolute
+// cap. It reports whether the session was re-issued.
+func (sm *SessionManager) Refresh(w http.ResponseWriter, r *http.Request, data *SessionData) (bool, error) {
+	if sm.refreshWindow <= 0 {
+		return false, nil
+	}
+	now := sm.now()
+	if !data.ExpiresAt.IsZero() && data.ExpiresAt.Sub(now) > sm.refreshWindow {
+		return false, nil
+	}
+	if sm.absoluteMaxAge > 0 && !data.IssuedAt.IsZero() && !data.ExpiresAt.Before(data.IssuedAt.Add(sm.absoluteMaxAge)) {
+		return false, nil // already runs to the cap
+	}
+	if err := sm.Set(w, r, data); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // Get retrieves the session data from the request.
 func (sm *SessionManager) Get(r *http.Request) (*SessionData, error) {
 	session, err := sm.store.Get(r, sessionName)
@@ -125,6 +174,24 @@ func (sm *SessionManager) Get(r *http.Request) (*SessionData, error) {
 	if v, ok := session.Values["csrf_token"].(string); ok {
 		csrfToken = v
 	}
+	isAdmin, _ := session.Values["is_admin"].(bool)
+
+	// The cookie's own max-age is up to the browser; a replayed cookie
+	// past either limit is refused here.
+	var issuedAt, expiresAt time.Time
+	now := sm.now()
+	if v, ok := session.Values["issued_at"].(int64); ok {
+		issuedAt = time.Unix(v, 0)
+		if sm.absoluteMaxAge > 0 && !now.Before(issuedAt.Add(sm.absoluteMaxAge)) {
+			return nil, ErrSessionNotFound
+		}
+	}
+	if v, ok := session.Values["expires_at"].(int64); ok {
+		expiresAt = time.Unix(v, 0)
+		if !now.Before(expiresAt) {
+			return nil, ErrSessionNotFound
+		}
+	}
 
 	return &SessionData{
 		UserID:    userID,
@@ -132,6 +199,9 @@ func (sm *SessionManager) Get(r *http.Request) (*SessionData, error) {
 		Name:      name,
 		Picture:   picture,
 		CSRFToken: csrfToken,
+		IsAdmin:   isAdmin,
+		IssuedAt:  issuedAt,
+		ExpiresAt: expiresAt,
 	}, nil
 }
 
@@ -160,6 +230,23 @@ func (sm *SessionManager) Set(w http.ResponseWriter, r *http.Request, data *Sess
 	session.Values["name"] = data.Name
 	session.Values["picture"] = data.Picture
 	session.Values["csrf_token"] = data.CSRFToken
+	session.Values["is_admin"] = data.IsAdmin
+
+	// A new sign-in starts the clock; a refresh keeps its IssuedAt.
+	now := sm.now()
+	if data.IssuedAt.IsZero() {
+		data.IssuedAt = now
+	}
+	expiresAt := now.Add(sm.maxAge)
+	if sm.absoluteMaxAge > 0 {
+		if limit := data.IssuedAt.Add(sm.absoluteMaxAge); expiresAt.After(limit) {
+			expiresAt = limit
+		}
+	}
+	data.ExpiresAt = expiresAt
+	session.Values["issued_at"] = data.IssuedAt.Unix()
+	session.Values["expires_at"] = expiresAt.Unix()
+	session.Options.MaxAge = int(expiresAt.Sub(now) / time.Second)
 
 	return session.Save(r, w)
 }
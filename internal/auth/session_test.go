@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -143,6 +144,96 @@ func TestSessionManager(t *testing.T) {
 	})
 }
 
+func TestSlidingExpiration(t *testing.T) {
+	sm := NewSessionManager("test-secret-key-at-least-32-chars", false, 3600, 300)
+	sm.SetSlidingExpiration(600, 4*3600)
+	start := time.Unix(1_800_000_000, 0)
+	now := start
+	sm.now = func() time.Time { return now }
+
+	signIn := httptest.NewRecorder()
+	if err := sm.Set(signIn, httptest.NewRequest(http.MethodGet, "/", nil), &SessionData{UserID: "user-123"}); err != nil {
+		t.Fatalf("failed to set session: %v", err)
+	}
+	cookies := signIn.Result().Cookies()
+
+	// request makes an authenticated request at the current time,
+	// carrying over any re-issued cookie the way a browser would.
+	request := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/protected", nil)
+		for _, cookie := range cookies {
+			c.Request.AddCookie(cookie)
+		}
+		RequireAuth(sm)(c)
+		if reissued := w.Result().Cookies(); len(reissued) > 0 {
+			cookies = reissued
+		}
+		return w
+	}
+
+	t.Run("early use leaves the expiry alone", func(t *testing.T) {
+		now = start.Add(10 * time.Minute)
+		if w := request(); w.Code == http.StatusFound || len(w.Result().Cookies()) != 0 {
+			t.Errorf("expected the session accepted without re-issuing, got %d with %d cookies", w.Code, len(w.Result().Cookies()))
+		}
+	})
+
+	t.Run("an active session's expiry extends", func(t *testing.T) {
+		// Within ten minutes of the hour, each use pushes expiry out
+		// another hour; the session stays valid well past the first.
+		for i := 1; i <= 3; i++ {
+			now = start.Add(time.Duration(i) * 55 * time.Minute)
+			if w := request(); w.Code == http.StatusFound {
+				t.Fatalf("use %d: expected the session accepted", i)
+			}
+		}
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		for _, cookie := range cookies {
+			r.AddCookie(cookie)
+		}
+		session, err := sm.Get(r)
+		if err != nil {
+			t.Fatalf("failed to get session: %v", err)
+		}
+		if want := now.Add(time.Hour); !session.ExpiresAt.Equal(want) {
+			t.Errorf("expected expiry %v, got %v", want, session.ExpiresAt)
+		}
+		if !session.IssuedAt.Equal(start) {
+			t.Errorf("expected the sign-in time kept, got %v", session.IssuedAt)
+		}
+	})
+
+	t.Run("the absolute cap still forces re-auth", func(t *testing.T) {
+		for now = start.Add(3 * time.Hour); now.Before(start.Add(4 * time.Hour)); now = now.Add(5 * time.Minute) {
+			if w := request(); w.Code == http.StatusFound {
+				t.Fatalf("expected the session accepted before the cap, rejected at %v", now.Sub(start))
+			}
+		}
+		now = start.Add(4 * time.Hour)
+		if w := request(); w.Code != http.StatusFound {
+			t.Errorf("expected a redirect to login at the cap, got %d", w.Code)
+		}
+	})
+
+	t.Run("an idle session expires", func(t *testing.T) {
+		sm.now = func() time.Time { return start }
+		idle := httptest.NewRecorder()
+		if err := sm.Set(idle, httptest.NewRequest(http.MethodGet, "/", nil), &SessionData{UserID: "user-123"}); err != nil {
+			t.Fatalf("failed to set session: %v", err)
+		}
+		sm.now = func() time.Time { return start.Add(time.Hour) }
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		for _, cookie := range idle.Result().Cookies() {
+			r.AddCookie(cookie)
+		}
+		if _, err := sm.Get(r); err != ErrSessionNotFound {
+			t.Errorf("expected ErrSessionNotFound after the max age, got %v", err)
+		}
+	})
+}
+
 func TestOAuthState(t *testing.T) {
 	sm := NewSessionManager("test-secret-key-at-least-32-chars", false, 86400, 300)
 
@@ -323,6 +414,56 @@ func TestRequireAuth(t *testing.T) {
 	})
 }
 
+func TestRequireAdmin(t *testing.T) {
+	run := func(session *SessionData) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/admin/alerts/reload", nil)
+		if session != nil {
+			c.Set(ContextKeySession, session)
+		}
+		RequireAdmin()(c)
+		return w
+	}
+
+	t.Run("admin session passes", func(t *testing.T) {
+		if w := run(&SessionData{UserID: "user-123", IsAdmin: true}); w.Code != http.StatusOK {
+			t.Errorf("expected the request through, got %d", w.Code)
+		}
+	})
+
+	t.Run("session without the admin claim gets 403", func(t *testing.T) {
+		if w := run(&SessionData{UserID: "user-123"}); w.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("no session gets 401", func(t *testing.T) {
+		if w := run(nil); w.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", w.Code)
+		}
+	})
+
+	t.Run("IsAdmin survives the session cookie", func(t *testing.T) {
+		sm := NewSessionManager("test-secret-key-at-least-32-chars", false, 86400, 300)
+		w := httptest.NewRecorder()
+		if err := sm.Set(w, httptest.NewRequest(http.MethodGet, "/", nil), &SessionData{UserID: "user-123", IsAdmin: true}); err != nil {
+			t.Fatalf("failed to set session: %v", err)
+		}
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		for _, cookie := range w.Result().Cookies() {
+			r.AddCookie(cookie)
+		}
+		session, err := sm.Get(r)
+		if err != nil {
+			t.Fatalf("failed to get session: %v", err)
+		}
+		if !session.IsAdmin {
+			t.Error("expected IsAdmin to round-trip")
+		}
+	})
+}
+
 func TestOptionalAuth(t *testing.T) {
 	sm := NewSessionManager("test-secret-key-at-least-32-chars", false, 86400, 300)
 
@@ -552,6 +693,87 @@ func TestValidateCSRF(t *testing.T) {
 	})
 }
 
+func TestValidateAPICSRF(t *testing.T) {
+	run := func(method, header string, session *SessionData, tokenAuth bool) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(method, "/api/sources/src-1/toggle", nil)
+		if header != "" {
+			c.Request.Header.Set("X-CSRF-Token", header)
+		}
+		if session != nil {
+			c.Set(ContextKeySession, session)
+		}
+		if tokenAuth {
+			c.Set(ContextKeyAPIToken, true)
+		}
+		ValidateAPICSRF()(c)
+		return w
+	}
+	session := &SessionData{UserID: "user-123", CSRFToken: "session-token"}
+
+	tests := []struct {
+		name      string
+		method    string
+		header    string
+		session   *SessionData
+		tokenAuth bool
+		want      int
+	}{
+		{"valid token", http.MethodPost, "session-token", session, false, http.StatusOK},
+		{"missing token", http.MethodPost, "", session, false, http.StatusForbidden},
+		{"mismatched token", http.MethodDelete, "other-token", session, false, http.StatusForbidden},
+		{"token of a different length", http.MethodPut, "session", session, false, http.StatusForbidden},
+		{"session without a token", http.MethodPost, "", &SessionData{UserID: "user-123"}, false, http.StatusForbidden},
+		{"no session", http.MethodPost, "session-token", nil, false, http.StatusForbidden},
+		{"safe method needs no token", http.MethodGet, "", session, false, http.StatusOK},
+		{"API token auth is exempt", http.MethodPost, "", &SessionData{UserID: "user-123"}, true, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := run(tt.method, tt.header, tt.session, tt.tokenAuth); w.Code != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, w.Code)
+			}
+		})
+	}
+}
+
+func TestCSRFTokenRotatesOnLogin(t *testing.T) {
+	sm := NewSessionManager("test-secret-key-at-least-32-chars", false, 86400, 300)
+
+	first := httptest.NewRecorder()
+	if err := sm.Set(first, httptest.NewRequest(http.MethodGet, "/", nil), &SessionData{UserID: "user-123"}); err != nil {
+		t.Fatalf("failed to set session: %v", err)
+	}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range first.Result().Cookies() {
+		r.AddCookie(cookie)
+	}
+	before, err := sm.Get(r)
+	if err != nil {
+		t.Fatalf("failed to get session: %v", err)
+	}
+
+	// Signing in again over the existing session, as the OIDC
+	// callback does, issues a new token.
+	again := &SessionData{UserID: "user-123"}
+	if err := sm.Set(httptest.NewRecorder(), r, again); err != nil {
+		t.Fatalf("failed to set session: %v", err)
+	}
+	if again.CSRFToken == "" || again.CSRFToken == before.CSRFToken {
+		t.Errorf("expected a fresh CSRF token on login, got %q (was %q)", again.CSRFToken, before.CSRFToken)
+	}
+
+	// A refresh keeps it.
+	kept := before.CSRFToken
+	if err := sm.Set(httptest.NewRecorder(), r, before); err != nil {
+		t.Fatalf("failed to set session: %v", err)
+	}
+	if before.CSRFToken != kept {
+		t.Errorf("expected re-saving a session to keep its token, got %q", before.CSRFToken)
+	}
+}
+
 func TestSessionData(t *testing.T) {
 	t.Run("struct has expected fields", func(t *testing.T) {
 		data := SessionData{
@@ -668,6 +890,36 @@ func TestOIDCClaims(t *testing.T) {
 	})
 }
 
+func TestOIDCClaims_HasClaimValue(t *testing.T) {
+	claims := &OIDCClaims{raw: map[string]any{
+		"groups":       []any{"staff", "calbridge-admin"},
+		"role":         "calbridge-admin",
+		"realm_access": map[string]any{"roles": []any{"calbridge-admin"}},
+		"count":        float64(1),
+	}}
+	tests := []struct {
+		name, claim, value string
+		want               bool
+	}{
+		{"list containing the value", "groups", "calbridge-admin", true},
+		{"list without the value", "groups", "calbridge-ops", false},
+		{"string claim", "role", "calbridge-admin", true},
+		{"nested claim", "realm_access.roles", "calbridge-admin", true},
+		{"missing claim", "roles", "calbridge-admin", false},
+		{"path through a non-object", "role.name", "calbridge-admin", false},
+		{"non-string claim", "count", "1", false},
+		{"values compare exactly", "groups", "Calbridge-Admin", false},
+		{"no claim configured", "", "calbridge-admin", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := claims.HasClaimValue(tt.claim, tt.value); got != tt.want {
+				t.Errorf("HasClaimValue(%q, %q) = %v, want %v", tt.claim, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetCurrentUserEdgeCases(t *testing.T) {
 	t.Run("returns nil for wrong type in context", func(t *testing.T) {
 		w := httptest.NewRecorder()
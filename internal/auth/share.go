@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrShareTokenInvalid = errors.New("invalid share token")
+	ErrShareTokenExpired = errors.New("share token expired")
+)
+
+// shareTokenContext separates share-link MACs from anything else that
+// might one day be signed with the session secret.
+const shareTokenContext = "calbridgesync-share-v1"
+
+// SignShareToken returns a URL-safe token binding linkID to expiresAt,
+// signed with HMAC-SHA256 under secret (the session secret). The token
+// is "<payload>.<mac>", where payload is base64url("linkID.unixExpiry").
+func SignShareToken(secret, linkID string, expiresAt time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString(
+		[]byte(linkID + "." + strconv.FormatInt(expiresAt.Unix(), 10)),
+	)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(shareMAC(secret, payload))
+}
+
+// VerifyShareToken checks token's signature and expiry against now and
+// returns the share link ID and expiry it carries. The caller must
+// still confirm the link exists, since deleting the row is how links
+// are revoked.
+func VerifyShareToken(secret, token string, now time.Time) (linkID string, expiresAt time.Time, err error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", time.Time{}, ErrShareTokenInvalid
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, shareMAC(secret, payload)) {
+		return "", time.Time{}, ErrShareTokenInvalid
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", time.Time{}, ErrShareTokenInvalid
+	}
+	linkID, expiry, ok := strings.Cut(string(raw), ".")
+	if !ok || linkID == "" {
+		return "", time.Time{}, ErrShareTokenInvalid
+	}
+	unix, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return "", time.Time{}, ErrShareTokenInvalid
+	}
+	expiresAt = time.Unix(unix, 0).UTC()
+	if !now.Before(expiresAt) {
+		return "", time.Time{}, ErrShareTokenExpired
+	}
+	return linkID, expiresAt, nil
+}
+
+func shareMAC(secret, payload string) []byte {
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write([]byte(shareTokenContext))
+	m.Write([]byte{0})
+	m.Write([]byte(payload))
+	return m.Sum(nil)
+}
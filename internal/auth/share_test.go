@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShareToken(t *testing.T) {
+	const secret = "test-secret-key-at-least-32-chars"
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	expires := now.Add(24 * time.Hour)
+
+	t.Run("round trips", func(t *testing.T) {
+		token := SignShareToken(secret, "link-1", expires)
+		id, gotExpiry, err := VerifyShareToken(secret, token, now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id != "link-1" || !gotExpiry.Equal(expires) {
+			t.Errorf("got (%q, %v), want (link-1, %v)", id, gotExpiry, expires)
+		}
+	})
+
+	t.Run("rejects wrong secret", func(t *testing.T) {
+		token := SignShareToken(secret, "link-1", expires)
+		if _, _, err := VerifyShareToken("another-secret-key-at-least-32-chars", token, now); !errors.Is(err, ErrShareTokenInvalid) {
+			t.Errorf("expected ErrShareTokenInvalid, got %v", err)
+		}
+	})
+
+	t.Run("rejects tampered payload", func(t *testing.T) {
+		token := SignShareToken(secret, "link-1", expires)
+		_, sig, _ := strings.Cut(token, ".")
+		forged := strings.Split(SignShareToken(secret, "link-2", expires.Add(365*24*time.Hour)), ".")[0]
+		if _, _, err := VerifyShareToken(secret, forged+"."+sig, now); !errors.Is(err, ErrShareTokenInvalid) {
+			t.Errorf("expected ErrShareTokenInvalid, got %v", err)
+		}
+	})
+
+	t.Run("rejects malformed tokens", func(t *testing.T) {
+		for _, token := range []string{"", "no-dot", ".", "abc.def", "!!!.???"} {
+			if _, _, err := VerifyShareToken(secret, token, now); !errors.Is(err, ErrShareTokenInvalid) {
+				t.Errorf("VerifyShareToken(%q): expected ErrShareTokenInvalid, got %v", token, err)
+			}
+		}
+	})
+
+	t.Run("rejects expired token", func(t *testing.T) {
+		token := SignShareToken(secret, "link-1", expires)
+		if _, _, err := VerifyShareToken(secret, token, expires); !errors.Is(err, ErrShareTokenExpired) {
+			t.Errorf("expected ErrShareTokenExpired at expiry, got %v", err)
+		}
+	})
+}
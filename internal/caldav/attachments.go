@@ -0,0 +1,59 @@
+package caldav
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/emersion/go-ical"
+)
+
+// maxInlineAttachmentBytes is the decoded size past which an inline
+// attachment is dropped for sources with SyncAttachments off. Anything
+// this big is usually a scanned PDF or photo that bloats every sync
+// and that several destinations refuse anyway.
+const maxInlineAttachmentBytes = 256 * 1024
+
+// isInlineAttachment reports whether prop carries its content inline
+// (ENCODING=BASE64;VALUE=BINARY) rather than pointing at a URI.
+func isInlineAttachment(prop ical.Prop) bool {
+	return strings.EqualFold(prop.Params.Get(ical.ParamEncoding), "BASE64") ||
+		strings.EqualFold(prop.Params.Get(ical.ParamValue), string(ical.ValueBinary))
+}
+
+// stripLargeAttachments returns a transform that drops inline ATTACH
+// properties whose decoded content exceeds limit bytes from every
+// component. URI attachments and smaller inline ones pass through
+// untouched, as does everything when limit <= 0.
+func stripLargeAttachments(limit int) EventTransform {
+	return func(cal *ical.Calendar) error {
+		if limit <= 0 {
+			return nil
+		}
+		for _, comp := range cal.Children {
+			stripComponentAttachments(comp, limit)
+		}
+		return nil
+	}
+}
+
+// stripComponentAttachments applies stripLargeAttachments to comp and
+// its subcomponents (VALARMs may carry attachments too).
+func stripComponentAttachments(comp *ical.Component, limit int) {
+	if attachments, ok := comp.Props[ical.PropAttach]; ok {
+		kept := attachments[:0]
+		for _, prop := range attachments {
+			if isInlineAttachment(prop) && base64.StdEncoding.DecodedLen(len(prop.Value)) > limit {
+				continue
+			}
+			kept = append(kept, prop)
+		}
+		if len(kept) == 0 {
+			delete(comp.Props, ical.PropAttach)
+		} else {
+			comp.Props[ical.PropAttach] = kept
+		}
+	}
+	for _, child := range comp.Children {
+		stripComponentAttachments(child, limit)
+	}
+}
@@ -0,0 +1,107 @@
+package caldav
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/emersion/go-ical"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+const uriAttach = "ATTACH;FMTTYPE=application/pdf:https://files.example.com/agenda.pdf"
+
+// inlineAttach returns an inline ATTACH property carrying n bytes.
+func inlineAttach(n int) string {
+	return "ATTACH;FMTTYPE=image/png;ENCODING=BASE64;VALUE=BINARY:" +
+		base64.StdEncoding.EncodeToString([]byte(strings.Repeat("x", n)))
+}
+
+// eventAttachments parses data and returns its VEVENT's ATTACH values.
+func eventAttachments(t *testing.T, data string) []ical.Prop {
+	t.Helper()
+	cal, err := parseICalendar(data)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	for _, comp := range cal.Children {
+		if comp.Name == ical.CompEvent {
+			return comp.Props.Values(ical.PropAttach)
+		}
+	}
+	t.Fatal("no VEVENT")
+	return nil
+}
+
+func TestAttachments_SurviveEncode(t *testing.T) {
+	small := inlineAttach(64)
+	cal, err := parseICalendar(tzEvent("DTSTART:20260115T090000Z", uriAttach, small))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	encoded, err := encodeCalendar(cal)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	attachments := eventAttachments(t, encoded)
+	if len(attachments) != 2 {
+		t.Fatalf("expected both attachments after encoding, got %d", len(attachments))
+	}
+	uri, inline := attachments[0], attachments[1]
+	if isInlineAttachment(uri) {
+		uri, inline = inline, uri
+	}
+	if uri.Value != "https://files.example.com/agenda.pdf" || uri.Params.Get("FMTTYPE") != "application/pdf" {
+		t.Errorf("URI attachment changed: %+v", uri)
+	}
+	if !isInlineAttachment(inline) || inline.Value != strings.TrimPrefix(small, "ATTACH;FMTTYPE=image/png;ENCODING=BASE64;VALUE=BINARY:") {
+		t.Errorf("inline attachment changed: %+v", inline)
+	}
+}
+
+func TestStripLargeAttachments(t *testing.T) {
+	data := tzEvent("DTSTART:20260115T090000Z", uriAttach, inlineAttach(64), inlineAttach(4096))
+
+	t.Run("oversized inline attachment is stripped", func(t *testing.T) {
+		got, err := applyTransforms(data, []EventTransform{stripLargeAttachments(1024)})
+		if err != nil {
+			t.Fatalf("applyTransforms: %v", err)
+		}
+		attachments := eventAttachments(t, got)
+		if len(attachments) != 2 {
+			t.Fatalf("expected the URI and small inline attachment to remain, got %d", len(attachments))
+		}
+		for _, a := range attachments {
+			if isInlineAttachment(a) && base64.StdEncoding.DecodedLen(len(a.Value)) > 1024 {
+				t.Errorf("oversized attachment kept: %d bytes", len(a.Value))
+			}
+		}
+		if !strings.Contains(got, "https://files.example.com/agenda.pdf") {
+			t.Error("expected the URI attachment to be preserved")
+		}
+	})
+
+	t.Run("no limit keeps everything", func(t *testing.T) {
+		got, err := applyTransforms(data, []EventTransform{stripLargeAttachments(0)})
+		if err != nil || got != data {
+			t.Errorf("expected passthrough, got %v", err)
+		}
+	})
+
+	t.Run("source setting", func(t *testing.T) {
+		pipeline, _ := buildTransformPipeline(&db.Source{SyncAttachments: true})
+		if len(pipeline) != 0 {
+			t.Errorf("expected SyncAttachments to keep every attachment, got %d transforms", len(pipeline))
+		}
+		pipeline, _ = buildTransformPipeline(&db.Source{})
+		big := tzEvent("DTSTART:20260115T090000Z", uriAttach, inlineAttach(maxInlineAttachmentBytes+1))
+		got, err := applyTransforms(big, pipeline)
+		if err != nil {
+			t.Fatalf("applyTransforms: %v", err)
+		}
+		if attachments := eventAttachments(t, got); len(attachments) != 1 || isInlineAttachment(attachments[0]) {
+			t.Errorf("expected only the URI attachment to remain, got %+v", attachments)
+		}
+	})
+}
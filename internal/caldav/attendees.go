@@ -0,0 +1,166 @@
+package caldav
+
+import "strings"
+
+// icalFoldWidth is the RFC 5545 §3.1 recommended maximum line length
+// in octets, excluding the CRLF. Lines we rewrite are refolded to it.
+const icalFoldWidth = 75
+
+// rewriteAttendees rewrites ORGANIZER and ATTENDEE calendar addresses
+// according to mapping (from → to). Used when mirroring between two
+// accounts owned by the same person: without it the destination sees
+// the source account as organizer and may not recognise its own owner
+// as an attendee.
+//
+// Keys and values are bare addresses or mailto: URIs; matching is
+// case-insensitive and ignores the mailto: scheme, so "Alice@Work"
+// matches "mailto:alice@work". The rewritten value keeps the scheme
+// prefix the original used. Unmapped addresses, parameters (CN,
+// PARTSTAT, ...) and every other line are left byte-for-byte intact.
+//
+// Like sanitizeAlarms this works on the raw text rather than a go-ical
+// round trip so untouched properties keep their exact formatting.
+func rewriteAttendees(data string, mapping map[string]string) string {
+	if data == "" || len(mapping) == 0 {
+		return data
+	}
+	if !strings.Contains(data, "ORGANIZER") && !strings.Contains(data, "ATTENDEE") {
+		return data
+	}
+
+	normalized := make(map[string]string, len(mapping))
+	for from, to := range mapping {
+		from = normalizeCalAddress(from)
+		to = strings.TrimSpace(to)
+		if from == "" || to == "" {
+			continue
+		}
+		normalized[from] = to
+	}
+	if len(normalized) == 0 {
+		return data
+	}
+
+	lineEnd := "\n"
+	if strings.Contains(data, "\r\n") {
+		lineEnd = "\r\n"
+	}
+	lines := strings.Split(data, lineEnd)
+
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if !isAddressProperty(line) {
+			out = append(out, line)
+			continue
+		}
+
+		// Unfold continuation lines (leading space or tab) so the
+		// value can be matched as a whole.
+		j := i + 1
+		unfolded := line
+		for j < len(lines) && len(lines[j]) > 0 && (lines[j][0] == ' ' || lines[j][0] == '\t') {
+			unfolded += lines[j][1:]
+			j++
+		}
+
+		rewritten, changed := rewriteAddressLine(unfolded, normalized)
+		if !changed {
+			out = append(out, lines[i:j]...)
+		} else {
+			out = append(out, foldICalLine(rewritten, lineEnd))
+		}
+		i = j - 1
+	}
+
+	return strings.Join(out, lineEnd)
+}
+
+// isAddressProperty reports whether line starts an ORGANIZER or
+// ATTENDEE property.
+func isAddressProperty(line string) bool {
+	for _, name := range []string{"ORGANIZER", "ATTENDEE"} {
+		if len(line) > len(name) && strings.EqualFold(line[:len(name)], name) {
+			if c := line[len(name)]; c == ':' || c == ';' {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rewriteAddressLine replaces the value of an unfolded ORGANIZER or
+// ATTENDEE line if it matches a key in mapping (already normalized).
+func rewriteAddressLine(line string, mapping map[string]string) (string, bool) {
+	sep := valueSeparatorIndex(line)
+	if sep < 0 {
+		return line, false
+	}
+	value := line[sep+1:]
+	to, ok := mapping[normalizeCalAddress(value)]
+	if !ok {
+		return line, false
+	}
+
+	// Keep the original scheme prefix (if any) unless the replacement
+	// already carries one.
+	if !hasMailtoPrefix(to) && hasMailtoPrefix(strings.TrimSpace(value)) {
+		to = strings.TrimSpace(value)[:len("mailto:")] + to
+	}
+	return line[:sep+1] + to, true
+}
+
+// valueSeparatorIndex returns the index of the colon separating the
+// property name/parameters from the value, skipping colons inside
+// quoted parameter values (e.g. CN="Smith: Alice").
+func valueSeparatorIndex(line string) int {
+	inQuotes := false
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case ':':
+			if !inQuotes {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// normalizeCalAddress lowercases an address and strips a leading
+// mailto: scheme so map keys and event values compare equal.
+func normalizeCalAddress(addr string) string {
+	addr = strings.TrimSpace(addr)
+	if hasMailtoPrefix(addr) {
+		addr = addr[len("mailto:"):]
+	}
+	return strings.ToLower(addr)
+}
+
+func hasMailtoPrefix(s string) bool {
+	return len(s) >= len("mailto:") && strings.EqualFold(s[:len("mailto:")], "mailto:")
+}
+
+// foldICalLine folds a content line at icalFoldWidth octets, never
+// splitting a multi-byte UTF-8 sequence.
+func foldICalLine(line, lineEnd string) string {
+	if len(line) <= icalFoldWidth {
+		return line
+	}
+	var b strings.Builder
+	width := icalFoldWidth
+	for len(line) > width {
+		cut := width
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString(lineEnd)
+		b.WriteByte(' ')
+		line = line[cut:]
+		width = icalFoldWidth - 1 // continuation lines start with a space
+	}
+	b.WriteString(line)
+	return b.String()
+}
@@ -0,0 +1,87 @@
+package caldav
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRewriteAttendees_OrganizerMatchesMapping(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:evt-1\r\n" +
+		"ORGANIZER;CN=Alice:mailto:alice@work.example\r\n" +
+		"ATTENDEE;CN=Alice;PARTSTAT=ACCEPTED:mailto:Alice@Work.Example\r\n" +
+		"ATTENDEE;CN=Bob;PARTSTAT=NEEDS-ACTION:mailto:bob@work.example\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	mapping := map[string]string{"alice@work.example": "alice@home.example"}
+
+	got := rewriteAttendees(data, mapping)
+
+	if !strings.Contains(got, "ORGANIZER;CN=Alice:mailto:alice@home.example\r\n") {
+		t.Errorf("expected ORGANIZER rewritten, got:\n%s", got)
+	}
+	if !strings.Contains(got, "ATTENDEE;CN=Alice;PARTSTAT=ACCEPTED:mailto:alice@home.example\r\n") {
+		t.Errorf("expected case-insensitive ATTENDEE match rewritten, got:\n%s", got)
+	}
+	if !strings.Contains(got, "ATTENDEE;CN=Bob;PARTSTAT=NEEDS-ACTION:mailto:bob@work.example\r\n") {
+		t.Errorf("expected unmapped ATTENDEE untouched, got:\n%s", got)
+	}
+	if strings.Contains(got, "alice@work.example") {
+		t.Errorf("expected no remaining source address, got:\n%s", got)
+	}
+}
+
+func TestRewriteAttendees_OrganizerNotInMappingUnchanged(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:evt-2\r\n" +
+		"ORGANIZER;CN=Carol:mailto:carol@other.example\r\n" +
+		"ATTENDEE:mailto:dave@other.example\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	mapping := map[string]string{"mailto:alice@work.example": "mailto:alice@home.example"}
+
+	if got := rewriteAttendees(data, mapping); got != data {
+		t.Errorf("expected data unchanged, got:\n%s", got)
+	}
+}
+
+func TestRewriteAttendees_EmptyMappingIsNoop(t *testing.T) {
+	data := "BEGIN:VEVENT\nORGANIZER:mailto:alice@work.example\nEND:VEVENT\n"
+	if got := rewriteAttendees(data, nil); got != data {
+		t.Errorf("expected nil mapping to be a no-op, got %q", got)
+	}
+}
+
+func TestRewriteAttendees_QuotedColonInParameter(t *testing.T) {
+	data := "BEGIN:VEVENT\n" +
+		"ORGANIZER;CN=\"Smith: Alice\":mailto:alice@work.example\n" +
+		"END:VEVENT\n"
+	got := rewriteAttendees(data, map[string]string{"alice@work.example": "alice@home.example"})
+	want := "ORGANIZER;CN=\"Smith: Alice\":mailto:alice@home.example\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("expected %q in output, got:\n%s", want, got)
+	}
+	if strings.Contains(got, "\r\n") {
+		t.Error("expected LF line endings preserved")
+	}
+}
+
+func TestRewriteAttendees_FoldedLine(t *testing.T) {
+	data := "BEGIN:VEVENT\r\n" +
+		"ATTENDEE;CN=Alice Example With A Long Display Name;PARTSTAT=ACCEPTED;ROLE=R\r\n" +
+		" EQ-PARTICIPANT:mailto:alice@work.example\r\n" +
+		"END:VEVENT\r\n"
+	got := rewriteAttendees(data, map[string]string{"alice@work.example": "alice@home.example"})
+
+	unfolded := strings.ReplaceAll(got, "\r\n ", "")
+	if !strings.Contains(unfolded, "ROLE=REQ-PARTICIPANT:mailto:alice@home.example\r\n") {
+		t.Errorf("expected folded ATTENDEE rewritten, got:\n%s", got)
+	}
+	for _, line := range strings.Split(got, "\r\n") {
+		if len(line) > icalFoldWidth {
+			t.Errorf("line exceeds %d octets after rewrite: %q", icalFoldWidth, line)
+		}
+	}
+}
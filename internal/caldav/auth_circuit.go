@@ -0,0 +1,239 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+	"github.com/macjediwizard/calbridgesync/internal/notify"
+)
+
+const (
+	// defaultAuthFailureThreshold is how many consecutive syncs must be
+	// rejected with an auth failure before a source's circuit opens.
+	defaultAuthFailureThreshold = 3
+
+	// authProbeBaseDelay is how long an open circuit waits before its
+	// first probe; each failed probe doubles the wait up to
+	// authProbeMaxDelay.
+	authProbeBaseDelay = 5 * time.Minute
+	authProbeMaxDelay  = 6 * time.Hour
+)
+
+// authCircuitState is one source's breaker.
+type authCircuitState struct {
+	failures  int
+	open      bool
+	notified  bool
+	nextProbe time.Time
+	delay     time.Duration
+}
+
+// authCircuit stops the engine from hammering a server that keeps
+// rejecting a source's credentials. After threshold consecutive auth
+// failures the source's circuit opens: scheduled syncs are skipped
+// except for an occasional probe, spaced with exponential backoff. A
+// probe whose connection tests pass closes the circuit. State is kept
+// in memory only, so a restart starts every source closed.
+type authCircuit struct {
+	mu        sync.Mutex
+	states    map[string]*authCircuitState
+	threshold int
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	now       func() time.Time
+}
+
+func newAuthCircuit(threshold int) *authCircuit {
+	return &authCircuit{
+		states:    make(map[string]*authCircuitState),
+		threshold: threshold,
+		baseDelay: authProbeBaseDelay,
+		maxDelay:  authProbeMaxDelay,
+		now:       time.Now,
+	}
+}
+
+// allow reports whether a sync of sourceID may go ahead. When the
+// circuit is open and the next probe isn't due yet it returns false
+// and the time the probe becomes due.
+func (ac *authCircuit) allow(sourceID string) (bool, time.Time) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	st, ok := ac.states[sourceID]
+	if !ok || !st.open || !ac.now().Before(st.nextProbe) {
+		return true, time.Time{}
+	}
+	return false, st.nextProbe
+}
+
+// isOpen reports whether sourceID's circuit is open.
+func (ac *authCircuit) isOpen(sourceID string) bool {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	st, ok := ac.states[sourceID]
+	return ok && st.open
+}
+
+// recordFailure counts an auth failure for sourceID. It returns whether
+// the circuit is open afterwards and whether this failure is the one
+// that opened it (the caller notifies the user exactly then). A failed
+// probe doubles the wait before the next one.
+func (ac *authCircuit) recordFailure(sourceID string) (open, opened bool) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	if ac.threshold <= 0 {
+		return false, false
+	}
+	st, ok := ac.states[sourceID]
+	if !ok {
+		st = &authCircuitState{}
+		ac.states[sourceID] = st
+	}
+	st.failures++
+
+	switch {
+	case st.open:
+		st.delay *= 2
+		if st.delay > ac.maxDelay {
+			st.delay = ac.maxDelay
+		}
+	case st.failures >= ac.threshold:
+		st.open = true
+		st.delay = ac.baseDelay
+	default:
+		return false, false
+	}
+	st.nextProbe = ac.now().Add(st.delay)
+	opened = !st.notified
+	st.notified = true
+	return true, opened
+}
+
+// recordSuccess closes sourceID's circuit and resets its failure count.
+// It reports whether the circuit had been open.
+func (ac *authCircuit) recordSuccess(sourceID string) bool {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	st, ok := ac.states[sourceID]
+	delete(ac.states, sourceID)
+	return ok && st.open
+}
+
+// SetAuthFailureThreshold sets how many consecutive syncs of a source
+// must fail authentication before its circuit opens. 0 disables the
+// breaker. Wired from SYNC_AUTH_FAILURE_THRESHOLD; call before the
+// scheduler starts.
+func (se *SyncEngine) SetAuthFailureThreshold(n int) {
+	se.authCircuit.mu.Lock()
+	defer se.authCircuit.mu.Unlock()
+	se.authCircuit.threshold = n
+}
+
+// ResetAuthCircuit closes sourceID's auth circuit so the next sync runs
+// immediately. Called when a source's credentials are edited.
+func (se *SyncEngine) ResetAuthCircuit(sourceID string) {
+	se.authCircuit.recordSuccess(sourceID)
+}
+
+// AuthCircuitOpen reports whether sourceID's syncs are currently being
+// held back by repeated auth failures.
+func (se *SyncEngine) AuthCircuitOpen(sourceID string) bool {
+	return se.authCircuit.isOpen(sourceID)
+}
+
+// isAuthFailure reports whether err means the server rejected the
+// credentials, as opposed to being unreachable or misbehaving.
+func isAuthFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrAuthFailed) {
+		return true
+	}
+	msg := err.Error()
+	for _, marker := range []string{"401", "403", "Unauthorized", "Forbidden"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// skipForAuthCircuit returns the result for a sync that was not run
+// because source's circuit is open, or nil if the sync may proceed.
+// Skipped syncs leave the source's status and sync log untouched.
+func (se *SyncEngine) skipForAuthCircuit(source *db.Source, result *SyncResult) *SyncResult {
+	ok, nextProbe := se.authCircuit.allow(source.ID)
+	if ok {
+		return nil
+	}
+	result.AuthCircuitOpen = true
+	result.Message = fmt.Sprintf("Sync skipped: credentials were rejected repeatedly; next check at %s",
+		nextProbe.UTC().Format(time.RFC3339))
+	result.Errors = append(result.Errors, result.Message)
+	return result
+}
+
+// recordConnectionTest feeds the outcome of a sync's connection tests
+// into source's auth circuit. A pass closes the circuit; an auth
+// failure counts towards opening it and, when it opens, alerts the
+// source's owner once. Other failures leave the circuit as it is.
+func (se *SyncEngine) recordConnectionTest(source *db.Source, result *SyncResult, err error) {
+	if err == nil {
+		if se.authCircuit.recordSuccess(source.ID) {
+			log.Printf("Auth circuit closed for source %s: connection test passed", source.Name)
+		}
+		return
+	}
+	if !isAuthFailure(err) {
+		return
+	}
+	open, opened := se.authCircuit.recordFailure(source.ID)
+	result.AuthCircuitOpen = open
+	if !opened {
+		return
+	}
+	log.Printf("Auth circuit opened for source %s: credentials rejected on consecutive syncs", source.Name)
+	se.sendAuthCircuitAlert(source)
+}
+
+// sendAuthCircuitAlert tells a source's owner that its syncs are
+// paused until the credentials work again.
+func (se *SyncEngine) sendAuthCircuitAlert(source *db.Source) {
+	if se.notifier == nil || !se.notifier.IsEnabled() {
+		return
+	}
+	userEmail, prefs := se.alertRecipient(source)
+	se.notifier.SendSyncFailureAlertWithPrefs(context.Background(), source.ID, source.Name, userEmail,
+		fmt.Sprintf("Syncing paused for source '%s': credentials were rejected", source.Name),
+		"The server rejected the stored credentials on several consecutive syncs. "+
+			"Syncs are paused and retried periodically; re-enter the credentials in the web UI to resume.",
+		prefs)
+}
+
+// alertRecipient returns the email address of a source's owner and
+// their alert preferences, narrowed by the source's own settings.
+func (se *SyncEngine) alertRecipient(source *db.Source) (string, *notify.UserPreferences) {
+	userEmail := ""
+	if user, err := se.db.GetUserByID(source.UserID); err == nil {
+		userEmail = user.Email
+	}
+	var prefs *notify.UserPreferences
+	if p, err := se.db.GetUserAlertPreferences(source.UserID); err == nil && p != nil {
+		prefs = &notify.UserPreferences{
+			EmailEnabled:    p.EmailEnabled,
+			WebhookEnabled:  p.WebhookEnabled,
+			WebhookURL:      p.WebhookURL,
+			CooldownMinutes: p.CooldownMinutes,
+		}
+	}
+	return userEmail, prefs.ForSource(source.AlertEmailEnabled, source.AlertWebhookEnabled)
+}
@@ -0,0 +1,211 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/macjediwizard/calbridgesync/internal/crypto"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func TestAuthCircuit_OpensAfterRepeatedAuthFailuresAndClosesOnSuccess(t *testing.T) {
+	srcSrv := httptest.NewServer(&nextcloudMock{})
+	defer srcSrv.Close()
+
+	// The destination rejects every request while reject is set and
+	// otherwise answers principal discovery like a healthy server.
+	var reject atomic.Bool
+	var destRequests atomic.Int32
+	reject.Store(true)
+	destMock := &nextcloudMock{}
+	destSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		destRequests.Add(1)
+		if reject.Load() {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		destMock.ServeHTTP(w, r)
+	}))
+	defer destSrv.Close()
+
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	encryptor, err := crypto.NewEncryptor(make([]byte, 32))
+	if err != nil {
+		t.Fatalf("NewEncryptor: %v", err)
+	}
+	encrypt := func(s string) string {
+		enc, err := encryptor.Encrypt(s)
+		if err != nil {
+			t.Fatalf("Encrypt: %v", err)
+		}
+		return enc
+	}
+	user, err := database.GetOrCreateUser("breaker@example.com", "Breaker")
+	if err != nil {
+		t.Fatalf("GetOrCreateUser: %v", err)
+	}
+	source := &db.Source{
+		UserID:           user.ID,
+		Name:             "Expired",
+		SourceType:       db.SourceTypeCustom,
+		SourceURL:        srcSrv.URL + "/remote.php/dav/",
+		SourceUsername:   "alice",
+		SourcePassword:   encrypt("source-pass"),
+		DestURL:          destSrv.URL + "/remote.php/dav/",
+		DestUsername:     "alice",
+		DestPassword:     encrypt("expired-pass"),
+		SyncInterval:     60,
+		SyncDirection:    db.SyncDirectionOneWay,
+		ConflictStrategy: db.ConflictSourceWins,
+		Enabled:          true,
+	}
+	if err := database.CreateSource(source); err != nil {
+		t.Fatalf("CreateSource: %v", err)
+	}
+
+	se := NewSyncEngine(database, encryptor)
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	se.authCircuit.now = func() time.Time { return clock }
+
+	status := func() db.SyncStatus {
+		t.Helper()
+		s, err := database.GetSourceByID(source.ID)
+		if err != nil {
+			t.Fatalf("GetSourceByID: %v", err)
+		}
+		return s.LastSyncStatus
+	}
+
+	for i := 1; i < defaultAuthFailureThreshold; i++ {
+		result := se.SyncSource(context.Background(), source)
+		if result.AuthCircuitOpen {
+			t.Fatalf("sync %d: circuit opened before the threshold", i)
+		}
+		if got := status(); got != db.SyncStatusError {
+			t.Errorf("sync %d: expected status %q, got %q", i, db.SyncStatusError, got)
+		}
+	}
+
+	result := se.SyncSource(context.Background(), source)
+	if !result.AuthCircuitOpen || !se.AuthCircuitOpen(source.ID) {
+		t.Fatalf("expected the circuit to open after %d auth failures: %+v", defaultAuthFailureThreshold, result)
+	}
+	if got := status(); got != db.SyncStatusAuthError {
+		t.Errorf("expected status %q, got %q", db.SyncStatusAuthError, got)
+	}
+
+	t.Run("open circuit skips syncs", func(t *testing.T) {
+		before := destRequests.Load()
+		result := se.SyncSource(context.Background(), source)
+		if result.Success || !result.AuthCircuitOpen {
+			t.Errorf("expected a skipped sync, got %+v", result)
+		}
+		if n := destRequests.Load(); n != before {
+			t.Errorf("expected no destination requests while open, got %d", n-before)
+		}
+		if got := status(); got != db.SyncStatusAuthError {
+			t.Errorf("expected status to stay %q, got %q", db.SyncStatusAuthError, got)
+		}
+	})
+
+	t.Run("failed probe backs off", func(t *testing.T) {
+		clock = clock.Add(authProbeBaseDelay)
+		before := destRequests.Load()
+		result := se.SyncSource(context.Background(), source)
+		if destRequests.Load() == before {
+			t.Fatal("expected the due probe to contact the destination")
+		}
+		if !result.AuthCircuitOpen {
+			t.Errorf("expected the circuit to stay open after a rejected probe")
+		}
+		if ok, next := se.authCircuit.allow(source.ID); ok || next.Sub(clock) != 2*authProbeBaseDelay {
+			t.Errorf("expected the next probe in %v, got allow=%v next=%v", 2*authProbeBaseDelay, ok, next.Sub(clock))
+		}
+	})
+
+	t.Run("successful probe closes", func(t *testing.T) {
+		reject.Store(false)
+		clock = clock.Add(2 * authProbeBaseDelay)
+		result := se.SyncSource(context.Background(), source)
+		if result.AuthCircuitOpen || se.AuthCircuitOpen(source.ID) {
+			t.Fatalf("expected the circuit to close once the connection test passed: %+v", result)
+		}
+		if got := status(); got == db.SyncStatusAuthError {
+			t.Errorf("expected status to leave %q", db.SyncStatusAuthError)
+		}
+	})
+}
+
+func TestAuthCircuit_Backoff(t *testing.T) {
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ac := newAuthCircuit(2)
+	ac.now = func() time.Time { return clock }
+
+	if open, opened := ac.recordFailure("s"); open || opened {
+		t.Fatalf("first failure: expected closed, got open=%v opened=%v", open, opened)
+	}
+	if open, opened := ac.recordFailure("s"); !open || !opened {
+		t.Fatalf("second failure: expected the circuit to open, got open=%v opened=%v", open, opened)
+	}
+
+	want := authProbeBaseDelay
+	for i := 0; i < 10; i++ {
+		if ok, next := ac.allow("s"); ok || next.Sub(clock) != want {
+			t.Fatalf("probe %d: expected next probe in %v, got allow=%v in %v", i, want, ok, next.Sub(clock))
+		}
+		clock = clock.Add(want)
+		if ok, _ := ac.allow("s"); !ok {
+			t.Fatalf("probe %d: expected the probe to be allowed once due", i)
+		}
+		if _, opened := ac.recordFailure("s"); opened {
+			t.Fatalf("probe %d: a failed probe must not notify again", i)
+		}
+		want = min(2*want, authProbeMaxDelay)
+	}
+
+	if !ac.recordSuccess("s") || ac.isOpen("s") {
+		t.Error("expected success to close the circuit")
+	}
+	if open, _ := ac.recordFailure("s"); open {
+		t.Error("expected the failure count to reset on success")
+	}
+}
+
+func TestAuthCircuit_Disabled(t *testing.T) {
+	ac := newAuthCircuit(0)
+	for i := 0; i < 5; i++ {
+		if open, _ := ac.recordFailure("s"); open {
+			t.Fatal("a zero threshold must never open the circuit")
+		}
+	}
+}
+
+func TestIsAuthFailure(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{ErrAuthFailed, true},
+		{fmt.Errorf("%w: 401 Unauthorized", ErrConnectionFailed), true},
+		{errors.New("403 Forbidden"), true},
+		{fmt.Errorf("%w: 503 Service Unavailable", ErrConnectionFailed), false},
+		{errors.New("dial tcp: connection refused"), false},
+	}
+	for _, tt := range tests {
+		if got := isAuthFailure(tt.err); got != tt.want {
+			t.Errorf("isAuthFailure(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
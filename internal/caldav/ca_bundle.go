@@ -0,0 +1,83 @@
+package caldav
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// MaxCABundleBytes bounds a source's PEM CA bundle.
+const MaxCABundleBytes = 256 * 1024
+
+// ValidateCABundle reports whether bundle is usable as a source's CA
+// bundle: one or more PEM CERTIFICATE blocks, each a parseable X.509
+// certificate, and nothing else. An empty bundle is valid and means
+// the system trust store alone.
+func ValidateCABundle(bundle string) error {
+	_, err := parseCABundle(bundle)
+	return err
+}
+
+func parseCABundle(bundle string) ([]*x509.Certificate, error) {
+	if len(bundle) > MaxCABundleBytes {
+		return nil, fmt.Errorf("CA bundle is larger than %d bytes", MaxCABundleBytes)
+	}
+	var certs []*x509.Certificate
+	rest := []byte(bundle)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("CA bundle block %d is a %s, not a CERTIFICATE", len(certs)+1, block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("CA bundle certificate %d: %w", len(certs)+1, err)
+		}
+		certs = append(certs, cert)
+	}
+	if strings.TrimSpace(string(rest)) != "" {
+		return nil, errors.New("CA bundle has data that isn't PEM")
+	}
+	if bundle != "" && len(certs) == 0 {
+		return nil, errors.New("CA bundle contains no certificates")
+	}
+	return certs, nil
+}
+
+// WithCABundle trusts the certificates in bundle, a PEM CA bundle, in
+// addition to the system trust store, for servers behind an internal
+// CA. Verification stays on; this only widens what a chain may lead
+// to. An empty bundle changes nothing, and one that doesn't parse is
+// logged and ignored, leaving the system roots alone: the client then
+// fails verification as it would without the bundle.
+func WithCABundle(bundle string) ClientOption {
+	return func(c *Client) {
+		if bundle == "" {
+			return
+		}
+		t := c.transport()
+		if t == nil {
+			return
+		}
+		certs, err := parseCABundle(bundle)
+		if err != nil {
+			log.Printf("Ignoring CA bundle for %s: %v", c.baseURL, err)
+			return
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		for _, cert := range certs {
+			pool.AddCert(cert)
+		}
+		t.TLSClientConfig.RootCAs = pool
+	}
+}
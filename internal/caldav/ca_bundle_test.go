@@ -0,0 +1,139 @@
+package caldav
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCA is a throwaway certificate authority.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  string
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate: %v", err)
+	}
+	return &testCA{cert: cert, key: key, pem: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))}
+}
+
+// server starts a TLS server for 127.0.0.1 whose certificate ca signed.
+func (ca *testCA) server(t *testing.T) *httptest.Server {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestWithCABundle(t *testing.T) {
+	internal := newTestCA(t, "Internal CA")
+	other := newTestCA(t, "Other CA")
+	trusted := internal.server(t)
+	untrusted := other.server(t)
+
+	get := func(t *testing.T, srv *httptest.Server, opts ...ClientOption) error {
+		t.Helper()
+		client, err := NewClient(srv.URL+"/", "user", "pass", opts...)
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		resp, err := client.httpClient.Get(srv.URL + "/")
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	if err := get(t, trusted); err == nil {
+		t.Error("expected a server behind an internal CA to fail verification without the bundle")
+	}
+	if err := get(t, trusted, WithCABundle(internal.pem)); err != nil {
+		t.Errorf("expected the bundle's CA to be trusted, got %v", err)
+	}
+	var unknownAuthority x509.UnknownAuthorityError
+	if err := get(t, untrusted, WithCABundle(internal.pem)); !errors.As(err, &unknownAuthority) {
+		t.Errorf("expected a certificate from another CA to be rejected, got %v", err)
+	}
+	if err := get(t, untrusted, WithCABundle(internal.pem+other.pem)); err != nil {
+		t.Errorf("expected every CA in a bundle to be trusted, got %v", err)
+	}
+}
+
+func TestValidateCABundle(t *testing.T) {
+	ca := newTestCA(t, "Internal CA")
+	key := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("x")}))
+	tests := []struct {
+		name    string
+		bundle  string
+		wantErr bool
+	}{
+		{"empty", "", false},
+		{"one certificate", ca.pem, false},
+		{"two certificates", ca.pem + "\n" + ca.pem, false},
+		{"not PEM", "hello", true},
+		{"trailing garbage", ca.pem + "junk", true},
+		{"private key", key, true},
+		{"corrupt certificate", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("x")})), true},
+		{"too large", strings.Repeat(ca.pem, MaxCABundleBytes/len(ca.pem)+1), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCABundle(tt.bundle); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCABundle = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
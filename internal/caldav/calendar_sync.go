@@ -0,0 +1,81 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// ErrUnknownCalendar is returned by SyncCalendar when the path is not
+// one of the calendars the source syncs.
+var ErrUnknownCalendar = errors.New("calendar is not synced by this source")
+
+type calendarScopeKeyType struct{}
+
+var calendarScopeKey = calendarScopeKeyType{}
+
+// calendarScope narrows a SyncSource pass to the calendar at path.
+// SyncSource records in checked and matched whether it got as far as
+// discovery and whether the path was among the calendars found there.
+// With forceCheck set the calendar is synced even when its CTag or
+// last-modified date says nothing changed.
+type calendarScope struct {
+	path       string
+	forceCheck bool
+	checked    bool
+	matched    bool
+}
+
+func calendarScopeFrom(ctx context.Context) *calendarScope {
+	scope, _ := ctx.Value(calendarScopeKey).(*calendarScope)
+	return scope
+}
+
+// scopedCalendarPath returns the path a calendar-scoped sync is limited
+// to, or "" for a sync of the whole source.
+func scopedCalendarPath(ctx context.Context) string {
+	if scope := calendarScopeFrom(ctx); scope != nil {
+		return scope.path
+	}
+	return ""
+}
+
+// forcesCheck reports whether ctx is a calendar-scoped sync that must
+// not skip its calendar as unchanged.
+func forcesCheck(ctx context.Context) bool {
+	scope := calendarScopeFrom(ctx)
+	return scope != nil && scope.forceCheck
+}
+
+// narrow returns the calendar in calendars at s.path. Paths compare
+// loosely, as for MULTIGET: percent-escapes and trailing slashes don't
+// matter.
+func (s *calendarScope) narrow(calendars []Calendar) []Calendar {
+	s.checked = true
+	want := normalizeMultiGetPath(s.path)
+	for _, cal := range calendars {
+		if normalizeMultiGetPath(cal.Path) == want {
+			s.matched = true
+			return []Calendar{cal}
+		}
+	}
+	return nil
+}
+
+// SyncCalendar syncs only the calendar at calendarPath, which must be
+// one SyncSource would sync: discovered on the source and, when the
+// source has a selection, selected. It exists for debugging a single
+// calendar, so the source's sync status and log are left as they were,
+// and the calendar is checked even when its CTag says it is unchanged.
+// ErrUnknownCalendar comes back with the result when the path isn't
+// one of the source's calendars; a sync that fails before discovery
+// reports that in the result alone.
+func (se *SyncEngine) SyncCalendar(ctx context.Context, source *db.Source, calendarPath string) (*SyncResult, error) {
+	scope := &calendarScope{path: calendarPath, forceCheck: true}
+	result := se.SyncSource(context.WithValue(ctx, calendarScopeKey, scope), source)
+	if scope.checked && !scope.matched {
+		return result, ErrUnknownCalendar
+	}
+	return result, nil
+}
@@ -0,0 +1,110 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func TestSyncCalendar(t *testing.T) {
+	t.Run("syncs only the named calendar", func(t *testing.T) {
+		f := newSelfLoopFixture(t)
+		source := f.createSource(t, "One calendar", twoCalendarServer(t).URL+"/dav/", twoCalendarServer(t).URL+"/dav/")
+		before, err := f.database.GetSourceByID(source.ID)
+		if err != nil {
+			t.Fatalf("GetSourceByID: %v", err)
+		}
+
+		// Broken fails every request, so the sync only succeeds if
+		// it is left out.
+		result, err := f.engine.SyncCalendar(context.Background(), source, "/dav/calendars/work")
+		if err != nil {
+			t.Fatalf("SyncCalendar: %v", err)
+		}
+		if !result.Success || result.CalendarsSynced != 1 {
+			t.Errorf("expected only Work to sync, got success=%v calendars=%d errors=%v warnings=%v",
+				result.Success, result.CalendarsSynced, result.Errors, result.Warnings)
+		}
+		if result.Calendar != "/dav/calendars/work" {
+			t.Errorf("expected the result to name the calendar, got %q", result.Calendar)
+		}
+
+		stored, err := f.database.GetSourceByID(source.ID)
+		if err != nil {
+			t.Fatalf("GetSourceByID: %v", err)
+		}
+		if stored.LastSyncAt != nil || stored.LastSyncStatus != before.LastSyncStatus {
+			t.Errorf("a calendar sync should leave the source status alone, got %q at %v",
+				stored.LastSyncStatus, stored.LastSyncAt)
+		}
+		if f.engine.GetActivityTracker().IsSourceSyncing(source.ID) {
+			t.Error("expected the source idle in the activity tracker after the calendar sync")
+		}
+	})
+
+	t.Run("unknown calendar is refused", func(t *testing.T) {
+		f := newSelfLoopFixture(t)
+		source := f.createSource(t, "Unknown calendar", twoCalendarServer(t).URL+"/dav/", twoCalendarServer(t).URL+"/dav/")
+
+		result, err := f.engine.SyncCalendar(context.Background(), source, "/dav/calendars/personal/")
+		if !errors.Is(err, ErrUnknownCalendar) {
+			t.Fatalf("expected ErrUnknownCalendar, got %v", err)
+		}
+		if result.Success || result.CalendarsSynced != 0 {
+			t.Errorf("expected nothing synced, got success=%v calendars=%d", result.Success, result.CalendarsSynced)
+		}
+		if f.engine.GetActivityTracker().IsSourceSyncing(source.ID) {
+			t.Error("expected the source idle in the activity tracker after a refused calendar sync")
+		}
+	})
+
+	t.Run("unselected calendar is refused", func(t *testing.T) {
+		f := newSelfLoopFixture(t)
+		source := f.createSource(t, "Selection", twoCalendarServer(t).URL+"/dav/", twoCalendarServer(t).URL+"/dav/")
+		source.SelectedCalendars = []db.CalendarConfig{{Path: "/dav/calendars/work/", Name: "Work"}}
+
+		if _, err := f.engine.SyncCalendar(context.Background(), source, "/dav/calendars/broken/"); !errors.Is(err, ErrUnknownCalendar) {
+			t.Errorf("expected ErrUnknownCalendar for a calendar outside the selection, got %v", err)
+		}
+	})
+}
+
+func TestSyncCalendar_IgnoresUnchangedCTag(t *testing.T) {
+	src := &discoveryStore{store: newCalendarStore(), ctag: "ctag-1"}
+	dest := &discoveryStore{store: newCalendarStore()}
+	srcSrv := httptest.NewServer(src)
+	defer srcSrv.Close()
+	destSrv := httptest.NewServer(dest)
+	defer destSrv.Close()
+	src.store.set("/dav/calendars/cal/one.ics", mergeICS("UID:one@example.com\r\nDTSTAMP:20260101T000000Z\r\n"+
+		"DTSTART:20260105T100000Z\r\nSUMMARY:One\r\n"))
+	// Keeps the destination listing non-empty once one.ics is gone.
+	dest.store.set("/dav/calendars/cal/other.ics", mergeICS("UID:other@example.com\r\nDTSTAMP:20260101T000000Z\r\n"+
+		"DTSTART:20260107T100000Z\r\nSUMMARY:Other\r\n"))
+
+	f := newSelfLoopFixture(t)
+	source := f.createSource(t, "Scoped", srcSrv.URL+"/dav/", destSrv.URL+"/dav/calendars/cal/")
+	source.SyncDirection = db.SyncDirectionOneWay
+	if result := f.engine.SyncSource(context.Background(), source); len(result.Errors) > 0 {
+		t.Fatalf("sync failed: %v", result.Errors)
+	}
+
+	// The copy goes missing on the destination; the source, and so its
+	// CTag, stays as it was. A regular sync skips the calendar.
+	delete(dest.store.data, "/dav/calendars/cal/one.ics")
+	if result := f.engine.SyncSource(context.Background(), source); result.Created != 0 {
+		t.Fatalf("expected the unchanged calendar to be skipped, got %d created", result.Created)
+	}
+
+	result, err := f.engine.SyncCalendar(context.Background(), source, "/dav/calendars/cal/")
+	if err != nil {
+		t.Fatalf("SyncCalendar: %v", err)
+	}
+	if result.Created != 1 {
+		t.Errorf("expected the calendar sync to check the calendar and restore the copy, got %d created (errors %v)",
+			result.Created, result.Errors)
+	}
+}
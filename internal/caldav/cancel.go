@@ -0,0 +1,43 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrSyncCanceled is the cancellation cause for a sync stopped on
+// request (POST /api/sources/:id/cancel), as opposed to a timeout or a
+// shutdown. A sync whose context is canceled with it is recorded with
+// the canceled status rather than as a failure.
+var ErrSyncCanceled = errors.New("canceled by user")
+
+// ErrSyncTimedOut is the cancellation cause for a sync that ran past
+// its maximum duration (see scheduler.SetMaxSyncDuration). Unlike a
+// sync canceled on request it is recorded as a failure, with TimedOut
+// set on its result.
+var ErrSyncTimedOut = errors.New("sync exceeded its maximum duration")
+
+// markCanceled sets result.Canceled when ctx was canceled with
+// ErrSyncCanceled, and makes sure the cancellation is among its errors
+// even if it landed after the last per-event check.
+func markCanceled(ctx context.Context, result *SyncResult) {
+	if errors.Is(context.Cause(ctx), ErrSyncCanceled) {
+		syncCanceled(ctx, result)
+		result.Canceled = true
+	}
+}
+
+// markTimedOut fails result with TimedOut set when ctx ran out with
+// ErrSyncTimedOut, whichever step the sync stopped at: a timeout while
+// connecting ends the sync as surely as one between events.
+func markTimedOut(ctx context.Context, result *SyncResult) {
+	if !errors.Is(context.Cause(ctx), ErrSyncTimedOut) {
+		return
+	}
+	syncCanceled(ctx, result)
+	result.TimedOut = true
+	result.Success = false
+	result.Message = fmt.Sprintf("Sync timed out: %d created, %d updated, %d deleted before stopping",
+		result.Created, result.Updated, result.Deleted)
+}
@@ -0,0 +1,174 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// TestSyncEventsToDestination_StopsOnCancel cancels the context from
+// inside the first PUT and checks the forward loop stops there, the
+// result reports the cancellation, and the one event that did land is
+// still recorded in synced_events.
+func TestSyncEventsToDestination_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	puts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut:
+			mu.Lock()
+			puts++
+			mu.Unlock()
+			w.Header().Set("ETag", `"1"`)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == "REPORT" || (r.Method == "PROPFIND" && r.Header.Get("Depth") == "1"):
+			w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+			w.WriteHeader(http.StatusMultiStatus)
+			_, _ = w.Write([]byte(`<?xml version="1.0"?><d:multistatus xmlns:d="DAV:"></d:multistatus>`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	user, err := database.GetOrCreateUser("cancel@example.com", "Cancel")
+	if err != nil {
+		t.Fatalf("GetOrCreateUser: %v", err)
+	}
+	source := &db.Source{
+		UserID:           user.ID,
+		Name:             "Cancel",
+		SourceType:       db.SourceTypeCustom,
+		SourceURL:        "https://source.example.com/cal/",
+		SourceUsername:   "user",
+		SourcePassword:   "encrypted",
+		DestURL:          srv.URL + "/dest/",
+		DestUsername:     "dest",
+		DestPassword:     "encrypted",
+		SyncInterval:     3600,
+		SyncDirection:    db.SyncDirectionOneWay,
+		ConflictStrategy: db.ConflictSourceWins,
+		Enabled:          true,
+	}
+	if err := database.CreateSource(source); err != nil {
+		t.Fatalf("CreateSource: %v", err)
+	}
+
+	destClient, err := NewClient(srv.URL+"/dest/", "dest", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	// Cancel once the first PUT's response is in hand, i.e. between
+	// events, the way a shutdown signal would usually land.
+	base := destClient.httpClient.Transport
+	destClient.httpClient.Transport = cancelAfterPut{base: base, cancel: cancel}
+
+	var sourceEvents []Event
+	for i := 0; i < 5; i++ {
+		uid := fmt.Sprintf("event-%d@example.com", i)
+		sourceEvents = append(sourceEvents, Event{
+			Path: "/cal/" + uid + ".ics",
+			ETag: `"src"`,
+			UID:  uid,
+			Data: "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VEVENT\r\n" +
+				"UID:" + uid + "\r\nDTSTAMP:20260101T000000Z\r\n" +
+				fmt.Sprintf("DTSTART:2026010%dT100000Z\r\n", i+1) +
+				fmt.Sprintf("SUMMARY:Event %d\r\n", i) +
+				"END:VEVENT\r\nEND:VCALENDAR\r\n",
+		})
+	}
+
+	se := NewSyncEngine(database, nil)
+	calendar := Calendar{Path: "/cal/", Name: "Cal"}
+	result := se.syncEventsToDestination(ctx, source, nil, destClient, sourceEvents, calendar, 1, db.SyncDirectionOneWay)
+
+	if puts != 1 {
+		t.Errorf("PUTs = %d, want 1 (loop should stop after cancellation)", puts)
+	}
+	if result.Created != 1 {
+		t.Errorf("Created = %d, want 1 (warnings: %v)", result.Created, result.Warnings)
+	}
+	var found bool
+	for _, e := range result.Errors {
+		if strings.HasPrefix(e, "sync canceled") {
+			found = true
+		}
+	}
+	if !found || len(result.Errors) != 1 {
+		t.Errorf("Errors = %v, want a single sync canceled error", result.Errors)
+	}
+
+	synced, err := database.GetSyncedEvents(source.ID, calendar.Path)
+	if err != nil {
+		t.Fatalf("GetSyncedEvents: %v", err)
+	}
+	if len(synced) != 1 || synced[0].EventUID != "event-0@example.com" {
+		t.Errorf("synced_events = %+v, want only the event written before cancellation", synced)
+	}
+}
+
+// cancelAfterPut is an http.RoundTripper that cancels a context after
+// each completed PUT.
+type cancelAfterPut struct {
+	base   http.RoundTripper
+	cancel context.CancelFunc
+}
+
+func (c cancelAfterPut) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := c.base.RoundTrip(req)
+	if err == nil && req.Method == http.MethodPut {
+		c.cancel()
+	}
+	return resp, err
+}
+
+// TestSyncSource_CanceledByUser cancels a sync with ErrSyncCanceled
+// once it reaches the Work calendar and checks the run is recorded as
+// canceled rather than failed.
+func TestSyncSource_CanceledByUser(t *testing.T) {
+	f := newSelfLoopFixture(t)
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	calendars := twoCalendarServer(t).Config.Handler
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/dav/calendars/work") {
+			cancel(ErrSyncCanceled)
+		}
+		calendars.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	source := f.createSource(t, "Canceled", srv.URL+"/dav/", twoCalendarServer(t).URL+"/dav/")
+
+	result := f.engine.SyncSource(ctx, source)
+
+	if !result.Canceled || result.Success {
+		t.Fatalf("expected a canceled result, got success=%v canceled=%v errors=%v",
+			result.Success, result.Canceled, result.Errors)
+	}
+	if !strings.HasPrefix(result.Message, "Sync canceled") {
+		t.Errorf("expected a canceled message, got %q", result.Message)
+	}
+	stored, err := f.database.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("GetSourceByID: %v", err)
+	}
+	if stored.LastSyncStatus != db.SyncStatusCanceled {
+		t.Errorf("expected status %q, got %q", db.SyncStatusCanceled, stored.LastSyncStatus)
+	}
+}
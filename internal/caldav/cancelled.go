@@ -0,0 +1,84 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// statusCancelled is the STATUS value organizers set on an event that
+// won't take place (RFC 5545 §3.8.1.11).
+const statusCancelled = "CANCELLED"
+
+// eventStatus returns the upper-cased STATUS of data's first VEVENT,
+// or "" when it has none or data can't be parsed.
+func eventStatus(data string) string {
+	if data == "" {
+		return ""
+	}
+	cal, err := parseICalendar(data)
+	if err != nil {
+		return ""
+	}
+	entries, journal := calendarEntries(cal)
+	if journal || len(entries) == 0 {
+		return ""
+	}
+	prop := entries[0].Props.Get("STATUS")
+	if prop == nil {
+		return ""
+	}
+	return strings.ToUpper(strings.TrimSpace(prop.Value))
+}
+
+// isCancelled reports whether data is an event marked STATUS:CANCELLED.
+func isCancelled(data string) bool {
+	return eventStatus(data) == statusCancelled
+}
+
+// dropCancelled removes cancelled events from events when the source
+// skips them, and returns the ones it removed.
+func dropCancelled(events []Event, source *db.Source) (kept, cancelled []Event) {
+	if !source.SkipCancelled {
+		return events, nil
+	}
+	kept = make([]Event, 0, len(events))
+	for _, e := range events {
+		if isCancelled(e.Data) {
+			cancelled = append(cancelled, e)
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	return kept, cancelled
+}
+
+// deleteCancelledFromDest deletes the destination copies of cancelled
+// events that an earlier sync wrote, before the events were cancelled,
+// and drops them from destEventMap so the passes after it leave them
+// alone. Destination events this source didn't write are never
+// touched.
+func (se *SyncEngine) deleteCancelledFromDest(ctx context.Context, source *db.Source, destClient *Client, calendarPath, destCalendarPath string,
+	cancelled []Event, destEventMap map[string]Event, previouslySynced map[string]*db.SyncedEvent, result *SyncResult) {
+	for _, e := range cancelled {
+		if syncCanceled(ctx, result) {
+			return
+		}
+		key := e.MatchKey()
+		destEvent, onDest := destEventMap[key]
+		if !onDest || previouslySynced[key] == nil {
+			continue
+		}
+		delete(destEventMap, key)
+		log.Printf("Event %s was cancelled on the source, deleting from destination", key)
+		se.backupDeletedEvent(ctx, source, destClient, calendarPath, destCalendarPath, destEvent, result)
+		if err := performDeletionAndCleanup(ctx, destClient, se.db, destEvent.Path, source.ID, calendarPath, key); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to delete cancelled event from dest: %v", err))
+		} else {
+			result.Deleted++
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package caldav
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func statusICS(uid, status string) string {
+	ics := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VEVENT\r\n" +
+		"UID:" + uid + "\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:" + uid + "\r\n"
+	if status != "" {
+		ics += "STATUS:" + status + "\r\n"
+	}
+	return ics + "END:VEVENT\r\nEND:VCALENDAR\r\n"
+}
+
+func TestEventStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"cancelled", statusICS("a@example.com", "CANCELLED"), "CANCELLED"},
+		{"lower case", statusICS("a@example.com", "cancelled"), "CANCELLED"},
+		{"confirmed", statusICS("a@example.com", "CONFIRMED"), "CONFIRMED"},
+		{"no status", statusICS("a@example.com", ""), ""},
+		{"journal", journalICS, ""},
+		{"empty", "", ""},
+		{"unparseable", "not a calendar", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := eventStatus(tt.data); got != tt.want {
+				t.Errorf("eventStatus = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncEventsToDestination_SkipCancelled(t *testing.T) {
+	for _, skip := range []bool{true, false} {
+		name := "skipped when enabled"
+		if !skip {
+			name = "mirrored by default"
+		}
+		t.Run(name, func(t *testing.T) {
+			destStore := newCalendarStore()
+			destSrv := httptest.NewServer(destStore)
+			defer destSrv.Close()
+			// Synced while still on, and cancelled on the source since.
+			destStore.set("/dest/offsite@example.com.ics", statusICS("offsite@example.com", ""))
+
+			database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+			if err != nil {
+				t.Fatalf("db.New: %v", err)
+			}
+			defer database.Close()
+			user, err := database.GetOrCreateUser("cancel@example.com", "Cancel")
+			if err != nil {
+				t.Fatalf("GetOrCreateUser: %v", err)
+			}
+			source := &db.Source{
+				UserID:           user.ID,
+				Name:             "Work",
+				SourceType:       db.SourceTypeCustom,
+				SourceURL:        "https://source.example.com/cal/",
+				SourceUsername:   "user",
+				SourcePassword:   "encrypted",
+				DestURL:          destSrv.URL + "/dest/",
+				DestUsername:     "dest",
+				DestPassword:     "encrypted",
+				SyncInterval:     3600,
+				SyncDirection:    db.SyncDirectionOneWay,
+				ConflictStrategy: db.ConflictSourceWins,
+				Enabled:          true,
+				SkipCancelled:    skip,
+			}
+			if err := database.CreateSource(source); err != nil {
+				t.Fatalf("CreateSource: %v", err)
+			}
+			if err := database.UpsertSyncedEvent(&db.SyncedEvent{
+				SourceID: source.ID, CalendarHref: "/cal/", EventUID: "offsite@example.com", SourceETag: `"old"`,
+			}); err != nil {
+				t.Fatalf("UpsertSyncedEvent: %v", err)
+			}
+			destClient, err := NewClient(destSrv.URL+"/dest/", "dest", "pass")
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+
+			sourceEvents := []Event{
+				{Path: "/cal/review.ics", ETag: `"1"`, UID: "review@example.com", Data: statusICS("review@example.com", "CONFIRMED")},
+				{Path: "/cal/party.ics", ETag: `"1"`, UID: "party@example.com", Data: statusICS("party@example.com", "CANCELLED")},
+				{Path: "/cal/offsite.ics", ETag: `"2"`, UID: "offsite@example.com", Data: statusICS("offsite@example.com", "CANCELLED")},
+			}
+
+			se := NewSyncEngine(database, nil)
+			result := se.syncEventsToDestination(context.Background(), source, nil, destClient, sourceEvents,
+				Calendar{Path: "/cal/", Name: "Work"}, 1, db.SyncDirectionOneWay)
+			if len(result.Errors) > 0 {
+				t.Fatalf("unexpected errors: %v", result.Errors)
+			}
+
+			destStore.mu.Lock()
+			defer destStore.mu.Unlock()
+			if _, ok := destStore.data["/dest/review@example.com.ics"]; !ok {
+				t.Errorf("expected the confirmed event to be created, destination holds %v", destStore.etags)
+			}
+			_, partyCopied := destStore.data["/dest/party@example.com.ics"]
+			_, offsiteKept := destStore.data["/dest/offsite@example.com.ics"]
+			if skip {
+				if partyCopied {
+					t.Error("expected the cancelled event not to be created")
+				}
+				if offsiteKept {
+					t.Error("expected the copy of the event cancelled since the last sync to be deleted")
+				}
+				if result.Created != 1 || result.Deleted != 1 {
+					t.Errorf("expected 1 created and 1 deleted, got %d/%d (warnings %v)", result.Created, result.Deleted, result.Warnings)
+				}
+				synced, err := database.GetSyncedEvents(source.ID, "/cal/")
+				if err != nil {
+					t.Fatalf("GetSyncedEvents: %v", err)
+				}
+				for _, s := range synced {
+					if s.EventUID == "offsite@example.com" || s.EventUID == "party@example.com" {
+						t.Errorf("expected no tracking row for cancelled event %s", s.EventUID)
+					}
+				}
+			} else {
+				if !partyCopied || !offsiteKept {
+					t.Errorf("expected cancelled events to be mirrored, destination holds %v", destStore.etags)
+				}
+				if result.Created != 2 || result.Deleted != 0 {
+					t.Errorf("expected 2 created and none deleted, got %d/%d (warnings %v)", result.Created, result.Deleted, result.Warnings)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package caldav
+
+import (
+	"strings"
+
+	"github.com/emersion/go-ical"
+)
+
+// eventHasCategory reports whether any VEVENT in data carries a
+// CATEGORIES value matching one of cats. Matching is case-insensitive
+// and ignores surrounding whitespace; a CATEGORIES property may list
+// several comma-separated values and may appear more than once.
+// Uncategorized or unparseable events never match.
+func eventHasCategory(data string, cats []string) bool {
+	if data == "" || len(cats) == 0 {
+		return false
+	}
+	want := make(map[string]bool, len(cats))
+	for _, c := range cats {
+		if c = strings.ToLower(strings.TrimSpace(c)); c != "" {
+			want[c] = true
+		}
+	}
+	if len(want) == 0 {
+		return false
+	}
+
+	cal, err := parseICalendar(data)
+	if err != nil {
+		return false
+	}
+	for _, child := range cal.Children {
+		if child.Name != ical.CompEvent {
+			continue
+		}
+		for _, prop := range child.Props.Values(ical.PropCategories) {
+			values, err := prop.TextList()
+			if err != nil {
+				continue
+			}
+			for _, v := range values {
+				if want[strings.ToLower(strings.TrimSpace(v))] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// filterEventsByCategory keeps the events matching cats. An empty cats
+// list disables the filter.
+func filterEventsByCategory(events []Event, cats []string) []Event {
+	if len(cats) == 0 {
+		return events
+	}
+	filtered := make([]Event, 0, len(events))
+	for _, e := range events {
+		if eventHasCategory(e.Data, cats) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
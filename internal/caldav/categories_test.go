@@ -0,0 +1,57 @@
+package caldav
+
+import (
+	"testing"
+)
+
+// categorizedEvent builds a single-VEVENT calendar with the given raw
+// CATEGORIES lines.
+func categorizedEvent(uid string, categoryLines ...string) string {
+	data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VEVENT\r\n" +
+		"UID:" + uid + "\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:" + uid + "\r\n"
+	for _, line := range categoryLines {
+		data += line + "\r\n"
+	}
+	return data + "END:VEVENT\r\nEND:VCALENDAR\r\n"
+}
+
+func TestEventHasCategory(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		data string
+		cats []string
+		want bool
+	}{
+		{"single match", categorizedEvent("a", "CATEGORIES:Work"), []string{"Work"}, true},
+		{"match in list", categorizedEvent("b", "CATEGORIES:Personal,Work,Travel"), []string{"Work"}, true},
+		{"case and whitespace", categorizedEvent("c", "CATEGORIES:work"), []string{" WORK "}, true},
+		{"second property", categorizedEvent("d", "CATEGORIES:Personal", "CATEGORIES:Work"), []string{"Work"}, true},
+		{"any of several filters", categorizedEvent("e", "CATEGORIES:Travel"), []string{"Work", "Travel"}, true},
+		{"no match", categorizedEvent("f", "CATEGORIES:Personal,Family"), []string{"Work"}, false},
+		{"substring is not a match", categorizedEvent("g", "CATEGORIES:Workshop"), []string{"Work"}, false},
+		{"uncategorized", categorizedEvent("h"), []string{"Work"}, false},
+		{"unparseable", "not a calendar", []string{"Work"}, false},
+		{"no filter", categorizedEvent("i", "CATEGORIES:Work"), nil, false},
+	} {
+		if got := eventHasCategory(tt.data, tt.cats); got != tt.want {
+			t.Errorf("%s: eventHasCategory = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFilterEventsByCategory(t *testing.T) {
+	events := []Event{
+		{UID: "work", Data: categorizedEvent("work", "CATEGORIES:Work")},
+		{UID: "personal", Data: categorizedEvent("personal", "CATEGORIES:Personal")},
+		{UID: "none", Data: categorizedEvent("none")},
+	}
+
+	if got := filterEventsByCategory(events, nil); len(got) != len(events) {
+		t.Errorf("expected no filtering without categories, got %d events", len(got))
+	}
+
+	got := filterEventsByCategory(events, []string{"Work"})
+	if len(got) != 1 || got[0].UID != "work" {
+		t.Errorf("expected only the Work event, got %v", eventUIDs(got))
+	}
+}
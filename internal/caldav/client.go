@@ -13,11 +13,14 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/emersion/go-ical"
 	"github.com/emersion/go-webdav"
 	"github.com/emersion/go-webdav/caldav"
+	"golang.org/x/oauth2"
+	"golang.org/x/time/rate"
 )
 
 var (
@@ -33,6 +36,32 @@ var (
 	// warnings, which is wrong — they are skips, not errors. Use
 	// errors.Is(err, ErrEventSkipped) to distinguish.
 	ErrEventSkipped = errors.New("event skipped")
+	// ErrDestReadOnly is returned by PutEvent (wrapped with
+	// ErrEventSkipped) and DeleteEvent on a client for a destination
+	// the source marks read-only; nothing was sent to the server.
+	ErrDestReadOnly = errors.New("destination is read-only")
+	// ErrTooManyEvents means a calendar lists more events than the
+	// client's cap (WithEventLimits); the calendar is not synced.
+	ErrTooManyEvents = errors.New("too many events")
+	// ErrEventTooLarge means a single event's iCalendar body exceeds
+	// the client's cap. It is always wrapped with ErrMalformedContent
+	// so the event is skipped and recorded like any other bad event.
+	ErrEventTooLarge = errors.New("event too large")
+	// ErrWriteNotVerified means a PUT the server accepted could not be
+	// read back intact (see VerifyEvent).
+	ErrWriteNotVerified = errors.New("write not verified")
+	// ErrEventExists is returned by PutEventIfNoneMatch when the server
+	// already has a resource at the event's path (412 Precondition
+	// Failed); nothing was written.
+	ErrEventExists = errors.New("event already exists")
+	// ErrResponseTooLarge means a server's response body exceeded the
+	// client's cap (WithMaxResponseBytes); the read fails instead of
+	// returning a truncated document.
+	ErrResponseTooLarge = errors.New("response too large")
+	// ErrInvalidSyncToken means the server rejected a WebDAV-Sync token
+	// (the DAV:valid-sync-token precondition); only a full sync can
+	// recover.
+	ErrInvalidSyncToken = errors.New("sync token invalid")
 )
 
 // dryRunContextKey is a context key that, when present, causes
@@ -61,18 +90,17 @@ const (
 	defaultTimeout = 300 * time.Second // 5 minutes default for slow CalDAV servers like iCloud
 	minTLSVersion  = tls.VersionTLS12
 
-	// maxCalDAVResponseSize caps every io.ReadAll over a CalDAV
-	// response body so a misbehaving or malicious CalDAV server
-	// cannot force the daemon to allocate unbounded memory. The
-	// cap is generous (200 MB) — real PROPFIND / REPORT responses
-	// for a 5000-event calendar are typically in the low single-
-	// digit MB range, so this is five to ten orders of magnitude
-	// larger than any legitimate response we'd expect. The point
-	// is to catch the runaway case (infinite response, slowloris-
-	// style trickle, zip bomb) without false-flagging any real
-	// user workload. Matches the existing maxICSResponseSize
-	// convention in ics_client.go (which uses 50 MB for ICS feeds). (#119)
-	maxCalDAVResponseSize = 200 * 1024 * 1024
+	// maxIdleConns is the idle connection pool of one client. A client
+	// only ever talks to its own server, so the whole pool is allowed
+	// on that host; Go's default of 2 per host would have every
+	// request past the second open a fresh TLS connection.
+	maxIdleConns = 10
+
+	// defaultMaxResponseBytes caps every CalDAV response body a client
+	// reads (see WithMaxResponseBytes). Real PROPFIND / REPORT responses
+	// for a 5000-event calendar are a few MB, so this only trips on a
+	// runaway or hostile server, never on a legitimate workload. (#119)
+	defaultMaxResponseBytes = 64 << 20
 )
 
 // Calendar represents a CalDAV calendar.
@@ -93,6 +121,14 @@ type Event struct {
 	UID       string `json:"uid"`
 	Summary   string `json:"summary"`
 	StartTime string `json:"start_time"` // DTSTART value for deduplication
+	// RecurrenceID is set only for a detached override: a resource
+	// whose VEVENTs all carry RECURRENCE-ID and which shares its UID
+	// with the series master stored in another resource. Normalized
+	// like StartTime.
+	RecurrenceID string `json:"recurrence_id,omitempty"`
+	// Journal is set when the resource holds VJOURNAL entries and no
+	// VEVENT. Journals only sync for sources with SyncJournals set.
+	Journal bool `json:"journal,omitempty"`
 }
 
 // DedupeKey returns a key for deduplication based on summary and start time.
@@ -100,6 +136,54 @@ func (e *Event) DedupeKey() string {
 	return e.Summary + "|" + e.StartTime
 }
 
+// MatchKey returns the key events are matched and tracked under: the
+// UID, qualified with the RECURRENCE-ID for detached overrides so a
+// master and its overridden occurrences don't collapse into one map
+// entry. It is also the resource name PutEvent writes to.
+func (e *Event) MatchKey() string {
+	if e.RecurrenceID == "" {
+		return e.UID
+	}
+	return e.UID + "_" + e.RecurrenceID
+}
+
+// calendarEntries returns the VEVENTs in cal, or its VJOURNALs (and
+// journal=true) when it has no VEVENT. The sync reads UID, SUMMARY and
+// DTSTART from these.
+func calendarEntries(cal *ical.Calendar) (entries []*ical.Component, journal bool) {
+	for _, child := range cal.Children {
+		if child.Name == ical.CompEvent {
+			entries = append(entries, child)
+		}
+	}
+	if len(entries) > 0 {
+		return entries, false
+	}
+	for _, child := range cal.Children {
+		if child.Name == ical.CompJournal {
+			entries = append(entries, child)
+		}
+	}
+	return entries, len(entries) > 0
+}
+
+// detachedRecurrenceID returns the normalized RECURRENCE-ID of a
+// calendar object that holds only override instances (no master
+// VEVENT), or "" for ordinary objects.
+func detachedRecurrenceID(cal *ical.Calendar) string {
+	recurrenceID := ""
+	for _, evt := range cal.Events() {
+		rid := evt.Props.Get(ical.PropRecurrenceID)
+		if rid == nil {
+			return ""
+		}
+		if recurrenceID == "" {
+			recurrenceID = normalizeStartTime(rid)
+		}
+	}
+	return recurrenceID
+}
+
 // MalformedEventInfo contains information about a corrupted calendar event.
 type MalformedEventInfo struct {
 	Path         string
@@ -143,48 +227,329 @@ type Client struct {
 	password     string
 	httpClient   *http.Client
 	caldavClient *caldav.Client
+	// limiter paces per-event requests (GetEvent, PutEvent,
+	// DeleteEvent). Nil means unthrottled.
+	limiter *rate.Limiter
+	// maxEvents and maxEventBytes guard against pathological
+	// calendars; see WithEventLimits. Zero means no limit.
+	maxEvents     int
+	maxEventBytes int
+	// maxResponseBytes bounds every response body the client reads;
+	// see WithMaxResponseBytes.
+	maxResponseBytes int64
+	// userAgent and customHeaders are stamped onto every outgoing
+	// request by headerTransport; see installHeaderTransport.
+	userAgent     string
+	customHeaders map[string]string
+	// blockedWrites is non-nil on a read-only view (see readOnlyView):
+	// PutEvent and DeleteEvent count themselves here instead of
+	// writing.
+	blockedWrites *atomic.Int64
+	// multiGetRejected is set once the server refuses
+	// calendar-multiget; see multiGetSupported.
+	multiGetRejected *atomic.Bool
+	// clock tracks the server's clock from response Date headers; see
+	// ClockOffset.
+	clock *serverClock
+	// encodeFailurePolicy is what PutEvent does with an event the
+	// encoder refuses; see WithEncodeFailurePolicy.
+	encodeFailurePolicy EncodeFailurePolicy
 }
 
-// NewClient creates a new CalDAV client.
-func NewClient(baseURL, username, password string) (*Client, error) {
-	if baseURL == "" {
-		return nil, fmt.Errorf("%w: base URL is required", ErrConnectionFailed)
+// readOnlyView returns a copy of c that shares its connections but
+// refuses every PutEvent and DeleteEvent, counting each in blocked.
+func (c *Client) readOnlyView(blocked *atomic.Int64) *Client {
+	view := *c
+	view.blockedWrites = blocked
+	return &view
+}
+
+// ClientOption configures optional Client behavior at construction.
+type ClientOption func(*Client)
+
+// WithMaxRequestsPerSecond paces GetEvent, PutEvent and DeleteEvent
+// through a token bucket so a sync never exceeds rps per-event requests
+// per second against this server. iCloud and Google throttle bursty
+// CalDAV clients hard; staying under their limit is faster overall than
+// tripping it. rps <= 0 leaves the client unthrottled.
+func WithMaxRequestsPerSecond(rps float64) ClientOption {
+	return func(c *Client) {
+		if rps > 0 {
+			c.limiter = rate.NewLimiter(rate.Limit(rps), 1)
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent sent on every request. Empty keeps
+// Go's default.
+func WithUserAgent(ua string) ClientOption {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// WithCustomHeaders adds extra headers to every request, e.g. for a
+// corporate proxy. A User-Agent entry overrides WithUserAgent; an
+// Authorization entry is ignored so credentials can't be clobbered.
+func WithCustomHeaders(headers map[string]string) ClientOption {
+	return func(c *Client) {
+		c.customHeaders = headers
+	}
+}
+
+// WithEventLimits caps how many events a single calendar may list
+// (GetEvents fails with ErrTooManyEvents above it) and how large one
+// event's iCalendar body may be (the event is skipped as malformed).
+// Zero disables either limit.
+func WithEventLimits(maxEvents, maxEventBytes int) ClientOption {
+	return func(c *Client) {
+		c.maxEvents = maxEvents
+		c.maxEventBytes = maxEventBytes
+	}
+}
+
+// WithHTTP2 controls whether the client negotiates HTTP/2 with servers
+// that offer it. It is on by default; turn it off for servers or
+// proxies that misbehave on h2, leaving the client on HTTP/1.1.
+func WithHTTP2(enabled bool) ClientOption {
+	return func(c *Client) {
+		t := c.transport()
+		if t == nil || enabled {
+			return
+		}
+		t.ForceAttemptHTTP2 = false
+		// A non-nil, empty TLSNextProto keeps the transport from
+		// upgrading to h2 via ALPN.
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
 	}
+}
 
-	transport := &http.Transport{
+// newTransport returns the HTTP transport every CalDAV client starts
+// from, Basic Auth and OAuth alike, so both behave the same at the
+// network layer.
+func newTransport() *http.Transport {
+	return &http.Transport{
 		TLSClientConfig: &tls.Config{
 			MinVersion: minTLSVersion,
 		},
-		MaxIdleConns:        10,
+		MaxIdleConns:        maxIdleConns,
+		MaxIdleConnsPerHost: maxIdleConns,
 		IdleConnTimeout:     30 * time.Second,
 		TLSHandshakeTimeout: 10 * time.Second,
+		// Setting TLSClientConfig turns off Go's automatic HTTP/2, so
+		// ask for it explicitly. WithHTTP2(false) reverts this.
+		ForceAttemptHTTP2: true,
+	}
+}
+
+// transport returns the *http.Transport beneath c's header and OAuth
+// wrappers, or nil if there isn't one.
+func (c *Client) transport() *http.Transport {
+	rt := c.httpClient.Transport
+	for {
+		switch t := rt.(type) {
+		case *http.Transport:
+			return t
+		case *headerTransport:
+			rt = t.base
+		case *oauth2.Transport:
+			rt = t.Base
+		default:
+			return nil
+		}
+	}
+}
+
+// WithMaxResponseBytes caps how much of any one response body the
+// client will read; past it the read fails with ErrResponseTooLarge.
+// n <= 0 keeps the default of 64 MiB.
+func WithMaxResponseBytes(n int64) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxResponseBytes = n
+		}
+	}
+}
+
+// checkEventCount returns ErrTooManyEvents if n exceeds the client's
+// per-calendar cap.
+func (c *Client) checkEventCount(calendarPath string, n int) error {
+	if c.maxEvents > 0 && n > c.maxEvents {
+		return fmt.Errorf("%w: calendar %s lists %d events, limit is %d",
+			ErrTooManyEvents, calendarPath, n, c.maxEvents)
+	}
+	return nil
+}
+
+// checkEventSize returns a malformed-content error if data exceeds the
+// client's per-event size cap.
+func (c *Client) checkEventSize(eventPath, data string) error {
+	if c.maxEventBytes > 0 && len(data) > c.maxEventBytes {
+		return fmt.Errorf("%w: %w: %s is %d bytes, limit is %d",
+			ErrMalformedContent, ErrEventTooLarge, eventPath, len(data), c.maxEventBytes)
+	}
+	return nil
+}
+
+// headerTransport injects fixed headers into each request before
+// handing it to the wrapped RoundTripper.
+type headerTransport struct {
+	base      http.RoundTripper
+	userAgent string
+	headers   map[string]string
+}
+
+// RoundTrip implements http.RoundTripper. The request is cloned, as
+// RoundTrippers must not modify the caller's request.
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if t.userAgent != "" {
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// limitedBody reads through an io.LimitReader set one byte past max, so
+// it can tell a body of exactly max bytes from a longer one. The longer
+// one fails with ErrResponseTooLarge rather than ending in a silent,
+// truncated EOF.
+type limitedBody struct {
+	io.ReadCloser
+	r    io.Reader
+	max  int64
+	read int64
+	url  string
+}
+
+func newLimitedBody(body io.ReadCloser, max int64, url string) *limitedBody {
+	return &limitedBody{ReadCloser: body, r: io.LimitReader(body, max+1), max: max, url: url}
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.read > b.max {
+		return 0, b.tooLarge()
+	}
+	n, err := b.r.Read(p)
+	b.read += int64(n)
+	if b.read > b.max {
+		// Hold back the extra byte; it only exists to detect overflow.
+		return n - int(b.read-b.max), b.tooLarge()
+	}
+	return n, err
+}
+
+func (b *limitedBody) tooLarge() error {
+	return fmt.Errorf("%w: %s sent more than %d bytes", ErrResponseTooLarge, b.url, b.max)
+}
+
+// limitedHTTPClient bounds the response bodies of the requests
+// go-webdav makes for us (GetEvent, calendar-query, discovery), which
+// it reads itself. It also feeds each response's Date header to clock.
+type limitedHTTPClient struct {
+	base  webdav.HTTPClient
+	max   int64
+	clock *serverClock
+}
+
+// Do implements webdav.HTTPClient.
+func (l *limitedHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	sent := time.Now()
+	resp, err := l.base.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	l.clock.observe(resp, sent)
+	resp.Body = newLimitedBody(resp.Body, l.max, req.URL.Redacted())
+	return resp, nil
+}
+
+// readBody reads a response to a request made directly through
+// httpClient, under the same cap as limitedHTTPClient. (#119)
+func (c *Client) readBody(resp *http.Response) ([]byte, error) {
+	return io.ReadAll(newLimitedBody(resp.Body, c.maxResponseBytes, resp.Request.URL.Redacted()))
+}
+
+// installHeaderTransport wraps the HTTP client's transport once all
+// options have been applied. Wrapping outermost means basic auth is
+// already on the request and an OAuth transport adds its bearer token
+// afterwards, so dropping Authorization here is enough to keep custom
+// headers from replacing credentials either way.
+func (c *Client) installHeaderTransport() {
+	headers := make(map[string]string, len(c.customHeaders))
+	for name, value := range c.customHeaders {
+		key := http.CanonicalHeaderKey(name)
+		if key == "Authorization" {
+			log.Printf("Ignoring custom Authorization header for %s", c.baseURL)
+			continue
+		}
+		headers[key] = value
+	}
+	if c.userAgent == "" && len(headers) == 0 {
+		return
+	}
+	c.httpClient.Transport = &headerTransport{
+		base:      c.httpClient.Transport,
+		userAgent: c.userAgent,
+		headers:   headers,
+	}
+}
+
+// waitForSlot blocks until the request limiter allows another request,
+// or ctx is done. No-op for unthrottled clients.
+func (c *Client) waitForSlot(ctx context.Context) error {
+	if c.limiter == nil {
+		return nil
+	}
+	return c.limiter.Wait(ctx)
+}
+
+// NewClient creates a new CalDAV client.
+func NewClient(baseURL, username, password string, opts ...ClientOption) (*Client, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("%w: base URL is required", ErrConnectionFailed)
+	}
+	username, password, err := normalizeCredentials(baseURL, username, password)
+	if err != nil {
+		return nil, err
 	}
 
 	httpClient := &http.Client{
 		Timeout:   defaultTimeout,
-		Transport: transport,
+		Transport: newTransport(),
+	}
+
+	client := &Client{
+		baseURL:          baseURL,
+		username:         username,
+		password:         password,
+		httpClient:       httpClient,
+		maxResponseBytes: defaultMaxResponseBytes,
+		multiGetRejected: new(atomic.Bool),
+		clock:            new(serverClock),
+	}
+	for _, opt := range opts {
+		opt(client)
 	}
 
-	caldavClient, err := caldav.NewClient(
-		webdav.HTTPClientWithBasicAuth(httpClient, username, password),
-		baseURL,
-	)
+	caldavClient, err := caldav.NewClient(&limitedHTTPClient{
+		base:  webdav.HTTPClientWithBasicAuth(httpClient, username, password),
+		max:   client.maxResponseBytes,
+		clock: client.clock,
+	}, baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("%w: failed to create CalDAV client: %w", ErrConnectionFailed, err)
 	}
-
-	return &Client{
-		baseURL:      baseURL,
-		username:     username,
-		password:     password,
-		httpClient:   httpClient,
-		caldavClient: caldavClient,
-	}, nil
+	client.caldavClient = caldavClient
+	client.installHeaderTransport()
+	return client, nil
 }
 
 // TestConnection tests the connection to the CalDAV server.
 func (c *Client) TestConnection(ctx context.Context) error {
-	_, err := c.caldavClient.FindCurrentUserPrincipal(ctx)
+	_, err := c.findCurrentUserPrincipal(ctx)
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrConnectionFailed, err)
 	}
@@ -200,15 +565,12 @@ func (c *Client) TestConnectionGoogle(ctx context.Context) error {
 }
 
 // FindCalendars discovers all calendars for the current user.
+// Principal discovery falls back to /.well-known/caldav when the base
+// URL can't answer it (see findCurrentUserPrincipal).
 func (c *Client) FindCalendars(ctx context.Context) ([]Calendar, error) {
-	principal, err := c.caldavClient.FindCurrentUserPrincipal(ctx)
+	homeSet, err := c.calendarHomeSet(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("%w: failed to find principal: %w", ErrConnectionFailed, err)
-	}
-
-	homeSet, err := c.caldavClient.FindCalendarHomeSet(ctx, principal)
-	if err != nil {
-		return nil, fmt.Errorf("%w: failed to find home set: %w", ErrConnectionFailed, err)
+		return nil, err
 	}
 
 	cals, err := c.caldavClient.FindCalendars(ctx, homeSet)
@@ -256,13 +618,17 @@ func (c *Client) FindCalendarsGoogle(ctx context.Context) ([]Calendar, error) {
 	}}, nil
 }
 
-// GetEvents retrieves all events from a calendar.
+// GetEvents retrieves all events from a calendar, or under a cap from
+// withEventCap the ones that start last.
 // If collector is provided, malformed events will be recorded there.
 func (c *Client) GetEvents(ctx context.Context, calendarPath string, collector *MalformedEventCollector) ([]Event, error) {
 	// Try the standard calendar-query first
-	events, err := c.getEventsViaQuery(ctx, calendarPath)
+	events, err := c.getEventsViaQuery(ctx, calendarPath, collector)
 	if err == nil && len(events) > 0 {
-		return events, nil
+		return latestEvents(events, eventCap(ctx)), nil
+	}
+	if errors.Is(err, ErrTooManyEvents) {
+		return nil, err
 	}
 
 	// If query failed (412, etc.) OR returned 0 events, fall back to PROPFIND
@@ -272,26 +638,49 @@ func (c *Client) GetEvents(ctx context.Context, calendarPath string, collector *
 	} else {
 		log.Printf("Calendar query returned 0 events, trying PROPFIND fallback for path: %s", calendarPath)
 	}
-	return c.getEventsViaPropfind(ctx, calendarPath, collector)
+	events, err = c.getEventsViaPropfind(ctx, calendarPath, collector)
+	if err != nil {
+		return nil, err
+	}
+	return latestEvents(events, eventCap(ctx)), nil
 }
 
 // getEventsViaQuery uses REPORT calendar-query to get events.
-func (c *Client) getEventsViaQuery(ctx context.Context, calendarPath string) ([]Event, error) {
+// Oversized events are dropped and recorded in collector.
+func (c *Client) getEventsViaQuery(ctx context.Context, calendarPath string, collector *MalformedEventCollector) ([]Event, error) {
 	query := &caldav.CalendarQuery{
 		CompRequest: caldav.CalendarCompRequest{
 			Name: "VCALENDAR",
 			Comps: []caldav.CalendarCompRequest{
 				{Name: "VEVENT"},
+				{Name: "VJOURNAL"},
 			},
 		},
 	}
 
-	objects, err := c.caldavClient.QueryCalendar(ctx, calendarPath, query)
+	objects, err := c.queryCalendar(ctx, calendarPath, query)
 	if err != nil {
 		return nil, fmt.Errorf("%w: failed to query calendar: %w", ErrConnectionFailed, err)
 	}
+	fillObjectUIDs(ctx, objects)
+	fillObjectDTStamps(objects)
+	if err := c.checkEventCount(calendarPath, len(objects)); err != nil {
+		return nil, err
+	}
 
-	return c.objectsToEvents(objects), nil
+	events := c.objectsToEvents(objects)
+	kept := events[:0]
+	for _, event := range events {
+		if err := c.checkEventSize(event.Path, event.Data); err != nil {
+			log.Printf("Skipping oversized event: %v", err)
+			if collector != nil {
+				collector.Add(event.Path, err.Error())
+			}
+			continue
+		}
+		kept = append(kept, event)
+	}
+	return kept, nil
 }
 
 // getEventsViaPropfind uses PROPFIND to list calendar objects, then fetches each one.
@@ -302,50 +691,30 @@ func (c *Client) getEventsViaPropfind(ctx context.Context, calendarPath string,
 
 // getEventsViaList lists calendar contents and fetches events using batch MULTIGET.
 func (c *Client) getEventsViaList(ctx context.Context, calendarPath string, collector *MalformedEventCollector) ([]Event, error) {
-	// Build the full URL - calendarPath might be absolute or relative
-	fullURL := c.buildURL(calendarPath)
-
-	// Make a simple PROPFIND request to list contents
-	req, err := http.NewRequestWithContext(ctx, "PROPFIND", fullURL, strings.NewReader(`<?xml version="1.0" encoding="utf-8" ?>
-<D:propfind xmlns:D="DAV:">
-  <D:prop>
-    <D:getetag/>
-    <D:getcontenttype/>
-  </D:prop>
-</D:propfind>`))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.SetBasicAuth(c.username, c.password)
-	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
-	req.Header.Set("Depth", "1")
-
-	resp, err := c.httpClient.Do(req)
+	eventPaths, collections, err := c.listCollection(ctx, calendarPath)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %w", ErrConnectionFailed, err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusMultiStatus && resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%w: unexpected status %d", ErrInvalidResponse, resp.StatusCode)
+		return nil, err
 	}
 
-	// Parse the multistatus response to get event paths. Wrapped
-	// in io.LimitReader so a malicious or misbehaving CalDAV
-	// server cannot force an unbounded allocation. (#119)
-	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCalDAVResponseSize))
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+	// Some servers nest calendar collections, so Depth: 1 on the parent
+	// lists only the children. Only descend when the top level had no
+	// events of its own; a calendar with attachments folders or similar
+	// must not pull in unrelated resources.
+	if len(eventPaths) == 0 && len(collections) > 0 {
+		log.Printf("No events directly under %s; checking %d sub-collection(s)", calendarPath, len(collections))
+		visited := map[string]bool{normalizeMultiGetPath(calendarPath): true}
+		eventPaths = c.listNestedEventPaths(ctx, collections, 1, visited)
+		log.Printf("Found %d event paths in sub-collections of %s", len(eventPaths), calendarPath)
 	}
 
-	log.Printf("PROPFIND response for %s: status=%d, body_len=%d", fullURL, resp.StatusCode, len(body))
-	eventPaths := parseEventPaths(body, calendarPath)
-	log.Printf("Parsed %d event paths from PROPFIND response (calendarPath=%s)", len(eventPaths), calendarPath)
-
 	if len(eventPaths) == 0 {
 		return []Event{}, nil
 	}
+	// Refuse before fetching anything: the cap exists so a calendar
+	// advertising millions of resources never gets loaded.
+	if err := c.checkEventCount(calendarPath, len(eventPaths)); err != nil {
+		return nil, err
+	}
 
 	// Use batch MULTIGET to fetch events efficiently (50 events per batch)
 	const batchSize = 50
@@ -363,11 +732,22 @@ func (c *Client) getEventsViaList(ctx context.Context, calendarPath string, coll
 
 		log.Printf("Fetching events batch: %d-%d of %d (%.0f%%)", batchStart+1, batchEnd, total, float64(batchEnd)/float64(total)*100)
 
-		// Try MULTIGET for this batch
-		batchEvents, malformed, empty, err := c.getEventsBatch(ctx, calendarPath, batchPaths, collector)
-		if err != nil {
-			// If MULTIGET fails, fall back to individual fetches for this batch
-			log.Printf("MULTIGET failed, falling back to individual fetches: %v", err)
+		// Try MULTIGET for this batch unless the server has already
+		// rejected it; fall back to individual fetches otherwise
+		var batchEvents []Event
+		var malformed, empty int
+		fetched := false
+		if c.multiGetSupported() {
+			var err error
+			batchEvents, malformed, empty, err = c.getEventsBatch(ctx, calendarPath, batchPaths, collector)
+			if err != nil {
+				log.Printf("MULTIGET failed, falling back to individual fetches: %v", err)
+				c.noteMultiGetFailure(err)
+			} else {
+				fetched = true
+			}
+		}
+		if !fetched {
 			batchEvents, malformed, empty = c.getEventsIndividually(ctx, batchPaths, collector)
 		}
 
@@ -387,6 +767,90 @@ func (c *Client) getEventsViaList(ctx context.Context, calendarPath string, coll
 	return events, nil
 }
 
+// maxNestedCollectionDepth caps how many collection levels below the
+// calendar listNestedEventPaths will descend.
+const maxNestedCollectionDepth = 3
+
+// listCollection runs a Depth: 1 PROPFIND on collectionPath and returns
+// the calendar object and child collection paths it lists.
+func (c *Client) listCollection(ctx context.Context, collectionPath string) (eventPaths, collections []string, err error) {
+	// Build the full URL - collectionPath might be absolute or relative
+	fullURL := c.buildURL(collectionPath)
+
+	// Make a simple PROPFIND request to list contents
+	req, err := http.NewRequestWithContext(ctx, "PROPFIND", fullURL, strings.NewReader(`<?xml version="1.0" encoding="utf-8" ?>
+<D:propfind xmlns:D="DAV:">
+  <D:prop>
+    <D:getetag/>
+    <D:getcontenttype/>
+    <D:resourcetype/>
+  </D:prop>
+</D:propfind>`))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "1")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrConnectionFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMultiStatus && resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("%w: unexpected status %d", ErrInvalidResponse, resp.StatusCode)
+	}
+
+	// Parse the multistatus response to get event paths. Bounded
+	// so a malicious or misbehaving CalDAV server cannot force an
+	// unbounded allocation. (#119)
+	body, err := c.readBody(resp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	log.Printf("PROPFIND response for %s: status=%d, body_len=%d", fullURL, resp.StatusCode, len(body))
+	eventPaths, collections = parseCollectionListing(body, collectionPath)
+	log.Printf("Parsed %d event paths and %d sub-collections from PROPFIND response (path=%s)", len(eventPaths), len(collections), collectionPath)
+	return eventPaths, collections, nil
+}
+
+// listNestedEventPaths lists each collection at Depth: 1 and gathers
+// their event paths, descending further into a collection only when it
+// holds no events itself. depth is the level of collections; recursion
+// stops at maxNestedCollectionDepth. visited guards against servers
+// that list an ancestor (or the collection itself) as a child.
+// Listing errors are logged and skipped so one unreadable
+// sub-collection doesn't hide the rest.
+func (c *Client) listNestedEventPaths(ctx context.Context, collections []string, depth int, visited map[string]bool) []string {
+	var eventPaths []string
+	for _, collection := range collections {
+		key := normalizeMultiGetPath(collection)
+		if visited[key] {
+			continue
+		}
+		visited[key] = true
+
+		paths, children, err := c.listCollection(ctx, collection)
+		if err != nil {
+			log.Printf("Failed to list sub-collection %s: %v", collection, err)
+			continue
+		}
+		if len(paths) == 0 && len(children) > 0 {
+			if depth < maxNestedCollectionDepth {
+				paths = c.listNestedEventPaths(ctx, children, depth+1, visited)
+			} else {
+				log.Printf("Not descending below %s: nested collection depth limit (%d) reached", collection, maxNestedCollectionDepth)
+			}
+		}
+		eventPaths = append(eventPaths, paths...)
+	}
+	return eventPaths
+}
+
 // normalizeMultiGetPath returns a canonical form of a CalDAV object path
 // suitable for equality comparison between a request path (which may be
 // URL-decoded by parseEventPaths) and a response path (which may be
@@ -450,6 +914,7 @@ func (c *Client) getEventsBatch(ctx context.Context, calendarPath string, paths
 			Name: "VCALENDAR",
 			Comps: []caldav.CalendarCompRequest{
 				{Name: "VEVENT"},
+				{Name: "VJOURNAL"},
 			},
 		},
 	}
@@ -458,6 +923,8 @@ func (c *Client) getEventsBatch(ctx context.Context, calendarPath string, paths
 	if err != nil {
 		return nil, 0, 0, fmt.Errorf("MULTIGET failed: %w", err)
 	}
+	fillObjectUIDs(ctx, objects)
+	fillObjectDTStamps(objects)
 
 	events := make([]Event, 0, len(objects))
 	skippedMalformed := 0
@@ -489,9 +956,19 @@ func (c *Client) getEventsBatch(ctx context.Context, calendarPath string, paths
 			skippedMalformed++
 			continue
 		}
+		if sizeErr := c.checkEventSize(obj.Path, data); sizeErr != nil {
+			if collector != nil {
+				collector.Add(obj.Path, sizeErr.Error())
+			}
+			skippedMalformed++
+			continue
+		}
 		event.Data = data
+		event.RecurrenceID = detachedRecurrenceID(obj.Data)
 
-		for _, evt := range obj.Data.Events() {
+		var entries []*ical.Component
+		entries, event.Journal = calendarEntries(obj.Data)
+		for _, evt := range entries {
 			if uid, err := evt.Props.Text(ical.PropUID); err == nil {
 				event.UID = uid
 			}
@@ -592,13 +1069,16 @@ func (c *Client) objectsToEvents(objects []caldav.CalendarObject) []Event {
 		}
 
 		event := Event{
-			Path: obj.Path,
-			ETag: obj.ETag,
-			Data: data,
+			Path:         obj.Path,
+			ETag:         obj.ETag,
+			Data:         data,
+			RecurrenceID: detachedRecurrenceID(obj.Data),
 		}
 
 		// Extract UID, Summary, and StartTime from events
-		for _, evt := range obj.Data.Events() {
+		var entries []*ical.Component
+		entries, event.Journal = calendarEntries(obj.Data)
+		for _, evt := range entries {
 			if uid, err := evt.Props.Text(ical.PropUID); err == nil {
 				event.UID = uid
 			}
@@ -618,13 +1098,25 @@ func (c *Client) objectsToEvents(objects []caldav.CalendarObject) []Event {
 
 // parseEventPaths extracts .ics file paths from a PROPFIND multistatus response.
 func parseEventPaths(body []byte, basePath string) []string {
+	paths, _ := parseCollectionListing(body, basePath)
+	return paths
+}
+
+// parseCollectionListing splits a Depth: 1 PROPFIND multistatus into
+// calendar object paths and child collection paths. A response whose
+// resourcetype is a collection is never treated as an event, even if
+// the server labels it text/calendar.
+func parseCollectionListing(body []byte, basePath string) (eventPaths, collections []string) {
 	type propfindResponse struct {
 		XMLName   xml.Name `xml:"DAV: multistatus"`
 		Responses []struct {
 			Href     string `xml:"href"`
 			PropStat struct {
 				Prop struct {
-					ContentType string `xml:"getcontenttype"`
+					ContentType  string `xml:"getcontenttype"`
+					ResourceType struct {
+						Collection *struct{} `xml:"collection"`
+					} `xml:"resourcetype"`
 				} `xml:"prop"`
 				Status string `xml:"status"`
 			} `xml:"propstat"`
@@ -640,32 +1132,36 @@ func parseEventPaths(body []byte, basePath string) []string {
 		} else {
 			log.Printf("parseEventPaths: response body: %s", string(body))
 		}
-		return nil
+		return nil, nil
 	}
 
 	log.Printf("parseEventPaths: found %d responses in multistatus (basePath=%s)", len(ms.Responses), basePath)
-	paths := make([]string, 0)
+	eventPaths = make([]string, 0)
 	for _, resp := range ms.Responses {
 		// Skip the collection itself
 		if resp.Href == basePath || resp.Href+"/" == basePath || basePath+"/" == resp.Href {
 			log.Printf("parseEventPaths: skipping collection path: %s", resp.Href)
 			continue
 		}
+		// URL-decode the path to avoid double-encoding when making requests
+		decodedPath, err := url.PathUnescape(resp.Href)
+		if err != nil {
+			// If decoding fails, use original path
+			decodedPath = resp.Href
+		}
+		if resp.PropStat.Prop.ResourceType.Collection != nil {
+			collections = append(collections, decodedPath)
+			continue
+		}
 		// Check if it's a calendar object (ends with .ics or has calendar content type)
 		if strings.HasSuffix(resp.Href, ".ics") ||
 			strings.Contains(resp.PropStat.Prop.ContentType, "calendar") {
-			// URL-decode the path to avoid double-encoding when making requests
-			decodedPath, err := url.PathUnescape(resp.Href)
-			if err != nil {
-				// If decoding fails, use original path
-				decodedPath = resp.Href
-			}
-			paths = append(paths, decodedPath)
+			eventPaths = append(eventPaths, decodedPath)
 		} else {
 			log.Printf("parseEventPaths: skipping non-event: href=%s contentType=%s", resp.Href, resp.PropStat.Prop.ContentType)
 		}
 	}
-	return paths
+	return eventPaths, collections
 }
 
 // IsGoogleURL reports whether a CalDAV base URL points at Google's
@@ -679,41 +1175,70 @@ func IsGoogleURL(baseURL string) bool {
 // GetCalendarPath returns the path portion of the client's base URL.
 // This is useful when the client is configured for a specific calendar.
 func (c *Client) GetCalendarPath() string {
-	if idx := strings.Index(c.baseURL, "://"); idx != -1 {
-		rest := c.baseURL[idx+3:]
-		if slashIdx := strings.Index(rest, "/"); slashIdx != -1 {
-			return rest[slashIdx:]
-		}
+	if _, path := splitBaseURL(c.baseURL); path != "" {
+		return path
 	}
 	return "/"
 }
 
 // buildURL constructs the full URL for a path.
-// If path is absolute (starts with /), extract host from baseURL and combine.
-// Otherwise, append path to baseURL.
+// If path is absolute (starts with /), it is resolved against the
+// scheme+host of baseURL, re-inserting any reverse-proxy path prefix
+// the server doesn't know about (see proxyPrefixFor). Otherwise, path
+// is appended to baseURL.
 func (c *Client) buildURL(path string) string {
 	if path == "" {
 		return c.baseURL
 	}
 
-	// If path is absolute, use just the scheme+host from baseURL
 	if strings.HasPrefix(path, "/") {
-		// Parse baseURL to get scheme and host
-		if idx := strings.Index(c.baseURL, "://"); idx != -1 {
-			rest := c.baseURL[idx+3:]
-			if slashIdx := strings.Index(rest, "/"); slashIdx != -1 {
-				// baseURL has a path, use scheme://host + path
-				return c.baseURL[:idx+3] + rest[:slashIdx] + path
-			}
-		}
-		// baseURL is just scheme://host, append path
-		return strings.TrimSuffix(c.baseURL, "/") + path
+		origin, basePath := splitBaseURL(c.baseURL)
+		return origin + proxyPrefixFor(basePath, path) + path
 	}
 
 	// Relative path - append to baseURL
 	return strings.TrimSuffix(c.baseURL, "/") + "/" + path
 }
 
+// splitBaseURL splits a base URL into its scheme://host origin and its
+// path ("" when the URL has no path).
+func splitBaseURL(baseURL string) (origin, path string) {
+	if idx := strings.Index(baseURL, "://"); idx != -1 {
+		rest := baseURL[idx+3:]
+		if slashIdx := strings.Index(rest, "/"); slashIdx != -1 {
+			return baseURL[:idx+3+slashIdx], rest[slashIdx:]
+		}
+	}
+	return strings.TrimSuffix(baseURL, "/"), ""
+}
+
+// proxyPrefixFor returns the path prefix a reverse proxy adds in front
+// of the CalDAV server, as inferred from the configured base path and
+// an absolute href the server returned.
+//
+// A server behind a proxy mounted at /cal-proxy returns hrefs like
+// "/remote.php/dav/calendars/u/work/" while the user configured
+// "https://host/cal-proxy/remote.php/dav/calendars/u/personal/". The
+// href's first segment ("remote.php") is located in the base path and
+// everything before it ("/cal-proxy") is the prefix to re-insert.
+//
+// Returns "" when the href already starts where the base path starts
+// (it includes the prefix, or there is none) or when the href shares
+// nothing with the base path (a genuine domain-root href).
+func proxyPrefixFor(basePath, href string) string {
+	baseSegs := strings.Split(strings.Trim(basePath, "/"), "/")
+	first := strings.SplitN(strings.TrimPrefix(href, "/"), "/", 2)[0]
+	if first == "" || len(baseSegs) == 0 || baseSegs[0] == first {
+		return ""
+	}
+	for i := 1; i < len(baseSegs); i++ {
+		if baseSegs[i] == first {
+			return "/" + strings.Join(baseSegs[:i], "/")
+		}
+	}
+	return ""
+}
+
 // IsMalformedError checks if an error is a malformed content error.
 func IsMalformedError(err error) bool {
 	if errors.Is(err, ErrMalformedContent) {
@@ -728,8 +1253,15 @@ func IsMalformedError(err error) bool {
 
 // GetEvent retrieves a single event by path.
 func (c *Client) GetEvent(ctx context.Context, eventPath string) (*Event, error) {
+	if err := c.waitForSlot(ctx); err != nil {
+		return nil, err
+	}
 	obj, err := c.caldavClient.GetCalendarObject(ctx, eventPath)
 	if err != nil {
+		// An oversized body is skipped like any other bad event.
+		if errors.Is(err, ErrResponseTooLarge) {
+			return nil, fmt.Errorf("%w: %w", ErrMalformedContent, err)
+		}
 		// Check for malformed content errors from the iCal parser
 		errStr := err.Error()
 		if strings.Contains(errStr, "malformed") ||
@@ -746,6 +1278,10 @@ func (c *Client) GetEvent(ctx context.Context, eventPath string) (*Event, error)
 	}
 
 	if obj.Data != nil {
+		if generatesMissingUIDs(ctx) {
+			fillMissingUIDs(obj.Data)
+		}
+		fillMissingDTStamps(obj.Data, time.Now())
 		data, encErr := encodeCalendar(obj.Data)
 		if encErr != nil {
 			// Encode failure is a form of malformed content. Return an
@@ -755,9 +1291,15 @@ func (c *Client) GetEvent(ctx context.Context, eventPath string) (*Event, error)
 			// Event{Data: ""} that downstream code would treat as valid.
 			return nil, encErr
 		}
+		if err := c.checkEventSize(eventPath, data); err != nil {
+			return nil, err
+		}
 		event.Data = data
+		event.RecurrenceID = detachedRecurrenceID(obj.Data)
 
-		for _, evt := range obj.Data.Events() {
+		var entries []*ical.Component
+		entries, event.Journal = calendarEntries(obj.Data)
+		for _, evt := range entries {
 			if uid, err := evt.Props.Text(ical.PropUID); err == nil {
 				event.UID = uid
 			}
@@ -786,6 +1328,12 @@ func (c *Client) GetEvent(ctx context.Context, eventPath string) (*Event, error)
 //     write). Callers should surface these in result.Warnings or
 //     result.Errors as appropriate.
 func (c *Client) PutEvent(ctx context.Context, calendarPath string, event *Event) error {
+	return c.putEvent(ctx, calendarPath, event, false)
+}
+
+// putEvent is PutEvent; with create set, the PUT only succeeds if the
+// resource doesn't exist yet (see PutEventIfNoneMatch).
+func (c *Client) putEvent(ctx context.Context, calendarPath string, event *Event, create bool) error {
 	// Dry-run: return nil without writing. The caller's bookkeeping
 	// (result.Created++, result.Updated++) proceeds as normal,
 	// producing an accurate preview of what WOULD happen. (#150)
@@ -795,6 +1343,10 @@ func (c *Client) PutEvent(ctx context.Context, calendarPath string, event *Event
 		}
 		return nil
 	}
+	if c.blockedWrites != nil {
+		c.blockedWrites.Add(1)
+		return fmt.Errorf("%w: %w", ErrEventSkipped, ErrDestReadOnly)
+	}
 
 	// Skip events with empty data. This is NOT a success — we did not
 	// write anything. Previously this returned nil, which made the
@@ -814,30 +1366,53 @@ func (c *Client) PutEvent(ctx context.Context, calendarPath string, event *Event
 	// Determine the path for this event on this server
 	// If event.Path is from a different server (doesn't start with calendarPath),
 	// we need to construct a new path using the UID
-	path := event.Path
-	if path == "" || !strings.HasPrefix(path, calendarPath) {
-		// Construct path from calendar path and UID
-		if event.UID == "" {
-			// Try to extract UID from calendar data
-			for _, evt := range cal.Events() {
-				if uid, err := evt.Props.Text(ical.PropUID); err == nil {
-					event.UID = uid
-					break
-				}
+	path := putPath(calendarPath, event)
+	if path == "" {
+		// Try to extract UID from calendar data
+		entries, _ := calendarEntries(cal)
+		for _, evt := range entries {
+			if uid, err := evt.Props.Text(ical.PropUID); err == nil {
+				event.UID = uid
+				break
 			}
 		}
-		if event.UID != "" {
-			path = strings.TrimSuffix(calendarPath, "/") + "/" + event.UID + ".ics"
-		} else {
-			// Skip events without UID — can't construct a valid path. Same
-			// honesty contract as the empty-data case above: return a
-			// wrapped sentinel so the caller counts this as a skip.
-			log.Printf("PutEvent: skipping event without UID (summary: %s)", event.Summary)
-			return fmt.Errorf("%w: no UID extractable from event data (summary: %s)",
-				ErrEventSkipped, event.Summary)
+		path = putPath(calendarPath, event)
+	}
+	if path == "" {
+		// Skip events without UID — can't construct a valid path. Same
+		// honesty contract as the empty-data case above: return a
+		// wrapped sentinel so the caller counts this as a skip.
+		log.Printf("PutEvent: skipping event without UID (summary: %s)", event.Summary)
+		return fmt.Errorf("%w: no UID extractable from event data (summary: %s)",
+			ErrEventSkipped, event.Summary)
+	}
+
+	// The library re-encodes cal for the PUT. A missing DTSTAMP is the
+	// usual reason the encoder refuses it and is filled in; beyond
+	// that, the policy decides between the original data and a skip,
+	// which callers record as malformed.
+	fillMissingDTStamps(cal, time.Now())
+	encoded, encErr := encodeCalendar(cal)
+	if encErr != nil && c.encodeFailurePolicy != EncodeFailureRaw {
+		log.Printf("PutEvent: skipping event that fails to encode (UID: %s): %v", event.UID, encErr)
+		return fmt.Errorf("%w: %w", ErrEventSkipped, encErr)
+	}
+
+	if err := c.waitForSlot(ctx); err != nil {
+		return err
+	}
+	if create {
+		if encErr != nil {
+			log.Printf("PutEvent: event fails to encode, creating original data at path %s: %v", path, encErr)
+			encoded = event.Data
 		}
+		log.Printf("PutEvent: creating at path %s", path)
+		return c.putNewEvent(ctx, path, encoded)
+	}
+	if encErr != nil {
+		log.Printf("PutEvent: event fails to encode, putting original data to path %s: %v", path, encErr)
+		return c.putRawEvent(ctx, path, event.Data)
 	}
-
 	log.Printf("PutEvent: putting to path %s", path)
 	_, err = c.caldavClient.PutCalendarObject(ctx, path, cal)
 	if err != nil {
@@ -866,6 +1441,42 @@ func (c *Client) PutEvent(ctx context.Context, calendarPath string, event *Event
 	return nil
 }
 
+// putPath returns the resource PutEvent writes event to: event.Path if
+// it already lives under calendarPath, otherwise a name built from the
+// event's match key. Empty when the event has neither.
+func putPath(calendarPath string, event *Event) string {
+	if event.Path != "" && strings.HasPrefix(event.Path, calendarPath) {
+		return event.Path
+	}
+	if event.UID == "" {
+		return ""
+	}
+	return strings.TrimSuffix(calendarPath, "/") + "/" + event.MatchKey() + ".ics"
+}
+
+// VerifyEvent re-reads an event PutEvent just wrote and checks that
+// the server actually stored it under the expected UID. Some servers
+// answer a PUT with 2xx and then drop the body; this is how we notice.
+// Call it with the same calendarPath and event passed to PutEvent.
+func (c *Client) VerifyEvent(ctx context.Context, calendarPath string, event *Event) error {
+	path := putPath(calendarPath, event)
+	if path == "" {
+		return fmt.Errorf("%w: event has no path or UID", ErrWriteNotVerified)
+	}
+	got, err := c.GetEvent(ctx, path)
+	if err != nil {
+		return fmt.Errorf("%w: read-back of %s failed: %w", ErrWriteNotVerified, path, err)
+	}
+	if got.Data == "" || got.UID == "" {
+		return fmt.Errorf("%w: read-back of %s returned no event", ErrWriteNotVerified, path)
+	}
+	if got.UID != event.UID {
+		return fmt.Errorf("%w: read-back of %s returned UID %q, want %q",
+			ErrWriteNotVerified, path, got.UID, event.UID)
+	}
+	return nil
+}
+
 // retryPutWithBumpedSequence attempts a second PUT after a 403 by reading
 // the destination's current SEQUENCE for this UID and bumping the outbound
 // payload to be strictly greater. Returns nil on successful retry, non-nil
@@ -907,7 +1518,7 @@ func (c *Client) fetchRawEvent(ctx context.Context, path string) (string, error)
 	if resp.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("unexpected status %d fetching %s", resp.StatusCode, path)
 	}
-	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCalDAVResponseSize))
+	body, err := c.readBody(resp)
 	if err != nil {
 		return "", err
 	}
@@ -973,6 +1584,13 @@ func (c *Client) DeleteEvent(ctx context.Context, eventPath string) error {
 	if IsDryRun(ctx) {
 		return nil
 	}
+	if c.blockedWrites != nil {
+		c.blockedWrites.Add(1)
+		return ErrDestReadOnly
+	}
+	if err := c.waitForSlot(ctx); err != nil {
+		return err
+	}
 	err := c.caldavClient.RemoveAll(ctx, eventPath)
 	if err != nil {
 		return fmt.Errorf("%w: failed to delete event: %w", ErrConnectionFailed, err)
@@ -1037,19 +1655,14 @@ func normalizeStartTime(prop *ical.Prop) string {
 
 	// Check for TZID parameter
 	if tzidParam := prop.Params.Get("TZID"); tzidParam != "" {
-		// Try to load the timezone - first try standard IANA name
-		loc, err := time.LoadLocation(tzidParam)
-		if err != nil {
-			// Try to parse GMT offset format (e.g., "GMT-0400", "GMT+0530")
-			loc = parseGMTOffset(tzidParam)
-			if loc == nil {
-				// Try the go-ical library method as fallback
-				t, err := prop.DateTime(time.UTC)
-				if err == nil {
-					return t.UTC().Format("20060102T150405Z")
-				}
-				return value
+		loc := resolveTZID(tzidParam)
+		if loc == nil {
+			// Try the go-ical library method as fallback
+			t, err := prop.DateTime(time.UTC)
+			if err == nil {
+				return t.UTC().Format("20060102T150405Z")
 			}
+			return value
 		}
 
 		// Parse the datetime in the specified timezone
@@ -1073,6 +1686,15 @@ func normalizeStartTime(prop *ical.Prop) string {
 	return value
 }
 
+// resolveTZID maps a TZID parameter to a location: an IANA name first,
+// then a GMT/UTC offset form. Returns nil if neither applies.
+func resolveTZID(tzid string) *time.Location {
+	if loc, err := time.LoadLocation(tzid); err == nil {
+		return loc
+	}
+	return parseGMTOffset(tzid)
+}
+
 // parseGMTOffset parses timezone strings like "GMT-0400", "GMT+0530", "UTC+05:30"
 // and returns a fixed timezone location.
 func parseGMTOffset(tzid string) *time.Location {
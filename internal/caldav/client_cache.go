@@ -0,0 +1,143 @@
+package caldav
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+const (
+	// clientCacheTTL is how long a cached Client may sit unused before
+	// it is dropped and its idle connections closed.
+	clientCacheTTL = 15 * time.Minute
+
+	// cachedClientIdleConnTimeout replaces NewClient's 30s idle timeout
+	// for cached clients so keep-alive connections survive the gap
+	// between frequent sync cycles. Matches net/http's default; most
+	// servers close idle connections on their side well before longer
+	// gaps anyway.
+	cachedClientIdleConnTimeout = 90 * time.Second
+)
+
+// clientCacheKey identifies the client one source uses for one CalDAV
+// account. Clients aren't shared between sources: each carries its own
+// rate limiter and per-server state. fingerprint covers everything
+// else the client was built from (password, pacing, User-Agent, custom
+// headers, CA bundle, proxy), so a source's changed credentials or
+// settings get a new entry rather than a client built from old ones.
+type clientCacheKey struct {
+	sourceID    string
+	url         string
+	username    string
+	fingerprint string
+}
+
+// sameAccount reports whether k and o are one source's clients for one
+// account, whatever they were built from.
+func (k clientCacheKey) sameAccount(o clientCacheKey) bool {
+	return k.sourceID == o.sourceID && k.url == o.url && k.username == o.username
+}
+
+type clientCacheEntry struct {
+	client   *Client
+	lastUsed time.Time
+}
+
+// clientCache reuses Basic Auth CalDAV clients across sync cycles so
+// their transports keep TLS connections alive instead of re-handshaking
+// on every sync. OAuth clients are not cached: their token source is
+// bound to the context they were created with.
+type clientCache struct {
+	mu      sync.Mutex
+	entries map[clientCacheKey]*clientCacheEntry
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+func newClientCache(ttl time.Duration) *clientCache {
+	return &clientCache{
+		entries: make(map[clientCacheKey]*clientCacheEntry),
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
+
+// get returns the cached client for key, otherwise builds a new one
+// with create and caches that, replacing the source's client for the
+// same account built from older settings. Entries idle longer than the
+// TTL are evicted on every call.
+func (cc *clientCache) get(key clientCacheKey, create func() (*Client, error)) (*Client, error) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	now := cc.now()
+	for k, e := range cc.entries {
+		if now.Sub(e.lastUsed) > cc.ttl {
+			e.client.httpClient.CloseIdleConnections()
+			delete(cc.entries, k)
+		}
+	}
+
+	if e, ok := cc.entries[key]; ok {
+		e.lastUsed = now
+		return e.client, nil
+	}
+	for k, e := range cc.entries {
+		if k.sameAccount(key) {
+			e.client.httpClient.CloseIdleConnections()
+			delete(cc.entries, k)
+		}
+	}
+
+	client, err := create()
+	if err != nil {
+		return nil, err
+	}
+	cc.entries[key] = &clientCacheEntry{client: client, lastUsed: now}
+	return client, nil
+}
+
+// withIdleConnTimeout overrides the transport's idle connection
+// timeout. Must run before installHeaderTransport wraps the transport,
+// which option ordering in NewClient guarantees.
+func withIdleConnTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		if t := c.transport(); t != nil {
+			t.IdleConnTimeout = d
+		}
+	}
+}
+
+// clientFingerprint hashes the password and the per-source client
+// settings, letting the cache notice a changed password without
+// holding on to it.
+func (se *SyncEngine) clientFingerprint(password string, source *db.Source) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%g\x00%s", password, source.MaxRequestsPerSecond, se.userAgent)
+	names := make([]string, 0, len(source.CustomHeaders))
+	for name := range source.CustomHeaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(h, "\x00%s\x00%s", name, source.CustomHeaders[name])
+	}
+	fmt.Fprintf(h, "\x00%s\x00%s", source.CABundle, se.sourceProxyURL(source))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cachedClient returns source's Basic Auth Client for (url, username),
+// reusing the one from its previous sync when the password and source
+// settings are unchanged.
+func (se *SyncEngine) cachedClient(url, username, password string, source *db.Source) (*Client, error) {
+	key := clientCacheKey{sourceID: source.ID, url: url, username: username, fingerprint: se.clientFingerprint(password, source)}
+	return se.clients.get(key, func() (*Client, error) {
+		opts := append([]ClientOption{withIdleConnTimeout(cachedClientIdleConnTimeout)}, se.ClientOptions(source)...)
+		return NewClient(url, username, password, opts...)
+	})
+}
@@ -0,0 +1,179 @@
+package caldav
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/macjediwizard/calbridgesync/internal/crypto"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func TestClientCache(t *testing.T) {
+	// Every request is rejected, so SyncSource stops at the connection
+	// test — after both clients have been built, which is all we need.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	encryptor, err := crypto.NewEncryptor(make([]byte, 32))
+	if err != nil {
+		t.Fatalf("NewEncryptor: %v", err)
+	}
+	encrypt := func(s string) string {
+		enc, err := encryptor.Encrypt(s)
+		if err != nil {
+			t.Fatalf("Encrypt: %v", err)
+		}
+		return enc
+	}
+	user, err := database.GetOrCreateUser("pool@example.com", "Pool")
+	if err != nil {
+		t.Fatalf("GetOrCreateUser: %v", err)
+	}
+	source := &db.Source{
+		UserID:           user.ID,
+		Name:             "Pooled",
+		SourceType:       db.SourceTypeCustom,
+		SourceURL:        srv.URL + "/source/",
+		SourceUsername:   "user",
+		SourcePassword:   encrypt("source-pass"),
+		DestURL:          srv.URL + "/dest/",
+		DestUsername:     "dest",
+		DestPassword:     encrypt("dest-pass"),
+		SyncInterval:     60,
+		SyncDirection:    db.SyncDirectionOneWay,
+		ConflictStrategy: db.ConflictSourceWins,
+		Enabled:          true,
+	}
+	if err := database.CreateSource(source); err != nil {
+		t.Fatalf("CreateSource: %v", err)
+	}
+
+	se := NewSyncEngine(database, encryptor)
+	// Every sync here is rejected; keep the auth circuit from pausing
+	// the source partway through.
+	se.SetAuthFailureThreshold(0)
+	sourceKey := clientCacheKey{sourceID: source.ID, url: source.SourceURL, username: source.SourceUsername}
+	destKey := clientCacheKey{sourceID: source.ID, url: source.DestURL, username: source.DestUsername}
+	// cached returns the client cached for key's account, whatever
+	// settings it was built from.
+	cached := func(key clientCacheKey) *Client {
+		se.clients.mu.Lock()
+		defer se.clients.mu.Unlock()
+		for k, e := range se.clients.entries {
+			if k.sameAccount(key) {
+				return e.client
+			}
+		}
+		return nil
+	}
+
+	t.Run("consecutive syncs reuse clients", func(t *testing.T) {
+		se.SyncSource(context.Background(), source)
+		firstSource, firstDest := cached(sourceKey), cached(destKey)
+		if firstSource == nil || firstDest == nil {
+			t.Fatal("expected source and destination clients to be cached after the first sync")
+		}
+		if firstSource.httpClient.Transport.(*http.Transport).IdleConnTimeout != cachedClientIdleConnTimeout {
+			t.Error("expected cached clients to keep idle connections open longer")
+		}
+
+		se.SyncSource(context.Background(), source)
+		if cached(sourceKey) != firstSource {
+			t.Error("second sync built a new source client instead of reusing the cached one")
+		}
+		if cached(destKey) != firstDest {
+			t.Error("second sync built a new destination client instead of reusing the cached one")
+		}
+	})
+
+	t.Run("password change invalidates", func(t *testing.T) {
+		before := cached(destKey)
+		changed := *source
+		changed.DestPassword = encrypt("rotated-pass")
+		se.SyncSource(context.Background(), &changed)
+		if after := cached(destKey); after == nil || after == before {
+			t.Error("expected a new destination client after the password changed")
+		}
+		if len(se.clients.entries) != 2 {
+			t.Errorf("expected the stale entry to be replaced, have %d entries", len(se.clients.entries))
+		}
+	})
+
+	t.Run("settings change invalidates", func(t *testing.T) {
+		before := cached(sourceKey)
+		changed := *source
+		changed.CustomHeaders = map[string]string{"X-Proxy-Tenant": "acme"}
+		se.SyncSource(context.Background(), &changed)
+		if after := cached(sourceKey); after == nil || after == before {
+			t.Error("expected a new source client after custom headers changed")
+		}
+	})
+
+	t.Run("sources on one account keep their own clients", func(t *testing.T) {
+		other := *source
+		other.ID = ""
+		other.Name = "Paced"
+		other.MaxRequestsPerSecond = 5
+		if err := database.CreateSource(&other); err != nil {
+			t.Fatalf("CreateSource: %v", err)
+		}
+		twin := other
+		twin.ID = ""
+		twin.Name = "Twin"
+		if err := database.CreateSource(&twin); err != nil {
+			t.Fatalf("CreateSource: %v", err)
+		}
+		otherKey := clientCacheKey{sourceID: other.ID, url: other.DestURL, username: other.DestUsername}
+		twinKey := clientCacheKey{sourceID: twin.ID, url: twin.DestURL, username: twin.DestUsername}
+
+		se.SyncSource(context.Background(), source)
+		se.SyncSource(context.Background(), &other)
+		se.SyncSource(context.Background(), &twin)
+		first, firstOther, firstTwin := cached(destKey), cached(otherKey), cached(twinKey)
+		if first == nil || firstOther == nil || firstTwin == nil {
+			t.Fatal("expected every source's destination client to be cached")
+		}
+		if firstOther == firstTwin {
+			t.Error("sources with identical settings should not share a client")
+		}
+
+		// Alternating syncs must not evict each other.
+		se.SyncSource(context.Background(), source)
+		se.SyncSource(context.Background(), &other)
+		if cached(destKey) != first || cached(otherKey) != firstOther {
+			t.Error("sources with different settings on one account evicted each other's clients")
+		}
+	})
+
+	t.Run("idle entries expire", func(t *testing.T) {
+		cc := newClientCache(time.Minute)
+		now := time.Now()
+		cc.now = func() time.Time { return now }
+		create := func() (*Client, error) { return NewClient(srv.URL+"/cal/", "user", "pass") }
+		key := clientCacheKey{sourceID: "src", url: srv.URL + "/cal/", username: "user", fingerprint: "fp"}
+
+		first, err := cc.get(key, create)
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		now = now.Add(30 * time.Second)
+		if again, _ := cc.get(key, create); again != first {
+			t.Error("expected reuse within the TTL")
+		}
+		now = now.Add(2 * time.Minute)
+		if later, _ := cc.get(key, create); later == first {
+			t.Error("expected a fresh client once the entry sat idle past the TTL")
+		}
+	})
+}
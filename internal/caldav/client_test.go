@@ -2,17 +2,21 @@ package caldav
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/emersion/go-ical"
 	"github.com/emersion/go-webdav/caldav"
+	"golang.org/x/oauth2"
 )
 
 func TestEventDedupeKey(t *testing.T) {
@@ -345,6 +349,78 @@ func TestNewClient(t *testing.T) {
 	})
 }
 
+func TestNewClient_BasicAuthCredentials(t *testing.T) {
+	var mu sync.Mutex
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		gotAuth = r.Header.Get("Authorization")
+		mu.Unlock()
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name, username, password string
+		wantUser, wantPass       string
+	}{
+		{"colon in password", "alice", "pa:ss:word", "alice", "pa:ss:word"},
+		{"inner spaces kept", "alice", "correct horse battery", "alice", "correct horse battery"},
+		{"surrounding whitespace trimmed", " alice@example.com ", "\tsecret \n", "alice@example.com", "secret"},
+		{"non-ASCII", "jürgen", "pässwörd-日本語", "jürgen", "pässwörd-日本語"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(srv.URL+"/", tt.username, tt.password)
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			_ = client.TestConnection(context.Background())
+
+			mu.Lock()
+			encoded, ok := strings.CutPrefix(gotAuth, "Basic ")
+			mu.Unlock()
+			if !ok {
+				t.Fatalf("expected a Basic Authorization header, got %q", gotAuth)
+			}
+			decoded, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				t.Fatalf("Authorization header is not valid Base64: %v", err)
+			}
+			user, pass, _ := strings.Cut(string(decoded), ":")
+			if user != tt.wantUser || pass != tt.wantPass {
+				t.Errorf("header decodes to %q / %q, want %q / %q", user, pass, tt.wantUser, tt.wantPass)
+			}
+		})
+	}
+}
+
+func TestNormalizeCredentials(t *testing.T) {
+	t.Run("iCloud app password typed with spaces", func(t *testing.T) {
+		for _, pw := range []string{"abcd efgh ijkl mnop", "abcdefghijklmnop", " abcd-efgh-ijkl-mnop "} {
+			_, got, err := normalizeCredentials("https://caldav.icloud.com/", "me@icloud.com", pw)
+			if err != nil || got != "abcd-efgh-ijkl-mnop" {
+				t.Errorf("normalizeCredentials(%q) = %q, %v", pw, got, err)
+			}
+		}
+	})
+
+	t.Run("other servers keep spaces", func(t *testing.T) {
+		_, got, _ := normalizeCredentials("https://dav.example.com/", "me", "abcd efgh ijkl mnop")
+		if got != "abcd efgh ijkl mnop" {
+			t.Errorf("expected the password unchanged, got %q", got)
+		}
+	})
+
+	t.Run("unsendable credentials are refused", func(t *testing.T) {
+		for _, c := range [][2]string{{"ali:ce", "pass"}, {"alice", "pa\r\nss"}, {"al\x00ice", "pass"}} {
+			if _, _, err := normalizeCredentials("https://dav.example.com/", c[0], c[1]); !errors.Is(err, ErrAuthFailed) {
+				t.Errorf("normalizeCredentials(%q, %q): expected ErrAuthFailed, got %v", c[0], c[1], err)
+			}
+		}
+	})
+}
+
 func TestClientGetCalendarPath(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -421,6 +497,36 @@ func TestClientBuildURL(t *testing.T) {
 			path:     "/calendars/event.ics",
 			expected: "https://caldav.example.com/calendars/event.ics",
 		},
+		{
+			name:     "nextcloud href under base path is not double-prefixed",
+			baseURL:  "https://cloud.example.com/remote.php/dav/calendars/alice/personal/",
+			path:     "/remote.php/dav/calendars/alice/personal/abc.ics",
+			expected: "https://cloud.example.com/remote.php/dav/calendars/alice/personal/abc.ics",
+		},
+		{
+			name:     "nextcloud subdirectory install href already includes prefix",
+			baseURL:  "https://example.com/nextcloud/remote.php/dav/calendars/alice/personal/",
+			path:     "/nextcloud/remote.php/dav/calendars/alice/work/abc.ics",
+			expected: "https://example.com/nextcloud/remote.php/dav/calendars/alice/work/abc.ics",
+		},
+		{
+			name:     "proxy prefix unknown to server is re-inserted",
+			baseURL:  "https://example.com/cal-proxy/remote.php/dav/calendars/alice/personal/",
+			path:     "/remote.php/dav/calendars/alice/personal/abc.ics",
+			expected: "https://example.com/cal-proxy/remote.php/dav/calendars/alice/personal/abc.ics",
+		},
+		{
+			name:     "multi-segment proxy prefix with sibling calendar href",
+			baseURL:  "https://example.com/apps/dav-gw/remote.php/dav/calendars/alice/personal",
+			path:     "/remote.php/dav/calendars/alice/work/",
+			expected: "https://example.com/apps/dav-gw/remote.php/dav/calendars/alice/work/",
+		},
+		{
+			name:     "unrelated domain-root href is left at the root",
+			baseURL:  "https://example.com/cal-proxy/remote.php/dav/calendars/alice/personal/",
+			path:     "/principals/alice/",
+			expected: "https://example.com/principals/alice/",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1029,6 +1135,83 @@ func TestConstants(t *testing.T) {
 	})
 }
 
+func TestNewClient_Transport(t *testing.T) {
+	t.Run("pools connections per host and asks for HTTP/2", func(t *testing.T) {
+		client, err := NewClient("https://dav.example.com/", "user", "pass", WithUserAgent("test"))
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		tr := client.transport()
+		if tr == nil {
+			t.Fatal("expected an *http.Transport beneath the header wrapper")
+		}
+		if tr.MaxIdleConnsPerHost != maxIdleConns || tr.MaxIdleConns != maxIdleConns {
+			t.Errorf("idle conns = %d total / %d per host, want %d for both", tr.MaxIdleConns, tr.MaxIdleConnsPerHost, maxIdleConns)
+		}
+		if !tr.ForceAttemptHTTP2 || tr.TLSNextProto != nil {
+			t.Errorf("expected HTTP/2 to be attempted, got ForceAttemptHTTP2=%v TLSNextProto=%v", tr.ForceAttemptHTTP2, tr.TLSNextProto)
+		}
+	})
+
+	t.Run("WithHTTP2(false) disables h2", func(t *testing.T) {
+		client, err := NewClient("https://dav.example.com/", "user", "pass", WithHTTP2(false))
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		tr := client.transport()
+		if tr.ForceAttemptHTTP2 || tr.TLSNextProto == nil || len(tr.TLSNextProto) != 0 {
+			t.Errorf("expected h2 off, got ForceAttemptHTTP2=%v TLSNextProto=%v", tr.ForceAttemptHTTP2, tr.TLSNextProto)
+		}
+	})
+
+	t.Run("OAuth clients share the transport settings", func(t *testing.T) {
+		client, err := NewOAuthClient(context.Background(), "https://apidata.googleusercontent.com/caldav/v2/",
+			&oauth2.Config{}, &oauth2.Token{RefreshToken: "refresh"}, WithHTTP2(false))
+		if err != nil {
+			t.Fatalf("NewOAuthClient: %v", err)
+		}
+		tr := client.transport()
+		if tr == nil || tr.MaxIdleConnsPerHost != maxIdleConns || tr.ForceAttemptHTTP2 {
+			t.Errorf("unexpected OAuth transport: %+v", tr)
+		}
+	})
+}
+
+func TestNewClient_NegotiatesHTTP2(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Proto)
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+
+	for _, tc := range []struct {
+		http2 bool
+		want  string
+	}{
+		{true, "HTTP/2.0"},
+		{false, "HTTP/1.1"},
+	} {
+		client, err := NewClient(srv.URL+"/", "user", "pass", WithHTTP2(tc.http2))
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		client.transport().TLSClientConfig.RootCAs = roots
+
+		resp, err := client.httpClient.Get(srv.URL + "/")
+		if err != nil {
+			t.Fatalf("GET: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != tc.want {
+			t.Errorf("WithHTTP2(%v): server saw %s, want %s", tc.http2, body, tc.want)
+		}
+	}
+}
+
 func TestEncodeCalendar(t *testing.T) {
 	t.Run("round trip encodes parsed calendar", func(t *testing.T) {
 		// Use parseICalendar to get a valid calendar, then encode it back
@@ -2099,7 +2282,7 @@ func TestPutEventSequenceRetry_NoExistingEvent(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewClient: %v", err)
 	}
-	body := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\nUID:x\r\nSEQUENCE:0\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260101T120000Z\r\nDTEND:20260101T130000Z\r\nSUMMARY:t\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	body := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VEVENT\r\nUID:x\r\nSEQUENCE:0\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260101T120000Z\r\nDTEND:20260101T130000Z\r\nSUMMARY:t\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
 	err = client.PutEvent(context.Background(), "/cal", &Event{
 		UID:  "x",
 		Path: "/cal/x.ics",
@@ -0,0 +1,98 @@
+package caldav
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// clockSkewWarnThreshold is how far apart the source and destination
+// clocks may drift before a sync warns that latest_wins decisions are
+// being corrected for it.
+const clockSkewWarnThreshold = 2 * time.Minute
+
+// serverClock estimates how far a server's clock is from ours, from
+// the Date header of its responses. Date has one-second resolution,
+// so the estimate is only good to about a second.
+type serverClock struct {
+	mu     sync.Mutex
+	offset time.Duration
+	known  bool
+}
+
+// observe records the offset implied by resp, assuming the server
+// stamped Date halfway through the round trip that began at sent.
+func (c *serverClock) observe(resp *http.Response, sent time.Time) {
+	if c == nil || resp == nil {
+		return
+	}
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return
+	}
+	received := time.Now()
+	midpoint := sent.Add(received.Sub(sent) / 2)
+	c.mu.Lock()
+	c.offset = date.Sub(midpoint)
+	c.known = true
+	c.mu.Unlock()
+}
+
+func (c *serverClock) get() (time.Duration, bool) {
+	if c == nil {
+		return 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.offset, c.known
+}
+
+// ClockOffset returns how far the server's clock is ahead of ours (a
+// negative value: behind), as of its last response with a Date
+// header. ok is false before any such response.
+func (c *Client) ClockOffset() (offset time.Duration, ok bool) {
+	return c.clock.get()
+}
+
+// estimateClockSkew returns how far the source server's clock is ahead
+// of the destination's, rounded to the second. ok is false unless
+// both servers have sent a Date header.
+func estimateClockSkew(sourceClient, destClient *Client) (time.Duration, bool) {
+	sourceOffset, ok := sourceClient.ClockOffset()
+	if !ok {
+		return 0, false
+	}
+	destOffset, ok := destClient.ClockOffset()
+	if !ok {
+		return 0, false
+	}
+	return (sourceOffset - destOffset).Round(time.Second), true
+}
+
+// recordClockSkew estimates the skew between source's two servers
+// after their connection tests, stores it on the source when it
+// changed, and warns when it is large enough to matter. An estimate
+// that can't be made leaves the stored one in place.
+func (se *SyncEngine) recordClockSkew(source *db.Source, sourceClient, destClient *Client, result *SyncResult) {
+	skew, ok := estimateClockSkew(sourceClient, destClient)
+	if !ok {
+		return
+	}
+	seconds := int(skew / time.Second)
+	if seconds != source.ClockSkewSeconds {
+		if err := se.retryDB(func() error {
+			return se.db.UpdateSourceClockSkew(source.ID, seconds)
+		}); err != nil {
+			log.Printf("Failed to store clock skew for source %s: %v", source.ID, err)
+		}
+		source.ClockSkewSeconds = seconds
+	}
+	if skew > clockSkewWarnThreshold || skew < -clockSkewWarnThreshold {
+		result.Warnings = append(result.Warnings, fmt.Sprintf(
+			"Source and destination clocks differ by %v; latest_wins comparisons are corrected for it", skew))
+	}
+}
@@ -0,0 +1,77 @@
+package caldav
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// skewedServer answers the principal PROPFIND with a Date header
+// offset from the real time by skew.
+func skewedServer(t *testing.T, skew time.Duration) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(skew).UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><d:multistatus xmlns:d="DAV:">` +
+			`<d:response><d:href>/dav/</d:href><d:propstat><d:prop>` +
+			`<d:current-user-principal><d:href>/dav/principals/alice/</d:href></d:current-user-principal>` +
+			`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response></d:multistatus>`))
+	}))
+}
+
+func TestClockSkewCorrectsLatestWins(t *testing.T) {
+	sourceSrv := skewedServer(t, 5*time.Minute)
+	defer sourceSrv.Close()
+	destSrv := skewedServer(t, -time.Minute)
+	defer destSrv.Close()
+
+	sourceClient, err := NewClient(sourceSrv.URL+"/dav/", "user", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	destClient, err := NewClient(destSrv.URL+"/dav/", "user", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if _, ok := estimateClockSkew(sourceClient, destClient); ok {
+		t.Fatal("expected no estimate before either server answered")
+	}
+	for _, c := range []*Client{sourceClient, destClient} {
+		if err := c.TestConnection(context.Background()); err != nil {
+			t.Fatalf("TestConnection: %v", err)
+		}
+	}
+
+	skew, ok := estimateClockSkew(sourceClient, destClient)
+	if !ok {
+		t.Fatal("expected a skew estimate after both connection tests")
+	}
+	// Date has one-second resolution, so allow for rounding.
+	if diff := skew - 6*time.Minute; diff < -2*time.Second || diff > 2*time.Second {
+		t.Fatalf("expected the source about 6m ahead, got %v", skew)
+	}
+
+	// The source stamped its edit 12:04 on its own clock, i.e. about
+	// 11:58 on the destination's; the destination edit at 12:00 is the
+	// later one once the skew is taken out.
+	sourceData := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VEVENT\r\nUID:e1\r\n" +
+		"DTSTAMP:20260301T120400Z\r\nLAST-MODIFIED:20260301T120400Z\r\nDTSTART:20260310T090000Z\r\n" +
+		"SUMMARY:Source edit\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	destData := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VEVENT\r\nUID:e1\r\n" +
+		"DTSTAMP:20260301T120000Z\r\nLAST-MODIFIED:20260301T120000Z\r\nDTSTART:20260310T090000Z\r\n" +
+		"SUMMARY:Dest edit\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	if destIsNewer(sourceData, destData, 0) {
+		t.Error("expected the source to win when the skew is ignored")
+	}
+	if !destIsNewer(sourceData, destData, skew) {
+		t.Error("expected the destination to win once the skew is applied")
+	}
+	if destIsNewer(sourceData, "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n", skew) {
+		t.Error("expected a version without timestamps to lose to the source")
+	}
+}
@@ -0,0 +1,71 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"strings"
+	"time"
+)
+
+// SetConnectionTestRetry makes SyncSource try its source and
+// destination connection tests up to attempts times when they fail
+// transiently, sleeping backoff before the first retry and doubling it
+// after. attempts <= 1 (the default) tests once. Wired from
+// SYNC_CONNECT_ATTEMPTS / SYNC_CONNECT_BACKOFF_MS; call before the
+// scheduler starts.
+func (se *SyncEngine) SetConnectionTestRetry(attempts int, backoff time.Duration) {
+	se.connectAttempts = attempts
+	se.connectBackoff = backoff
+}
+
+// testConnection runs test, retrying transient failures as configured
+// by SetConnectionTestRetry. A canceled ctx stops the retries and the
+// last error is returned.
+func (se *SyncEngine) testConnection(ctx context.Context, name string, test func(context.Context) error) error {
+	backoff := se.connectBackoff
+	for attempt := 1; ; attempt++ {
+		err := test(ctx)
+		if err == nil || attempt >= se.connectAttempts || !isTransientConnectionError(err) {
+			return err
+		}
+		log.Printf("%s connection test failed (attempt %d of %d), retrying in %v: %v",
+			name, attempt, se.connectAttempts, backoff, err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// isTransientConnectionError reports whether a connection test failed
+// in a way a second try moments later might not: network and DNS
+// errors, timeouts, TLS handshakes cut short, and 429/5xx responses.
+// Rejected credentials, other 4xx responses and a canceled context are
+// permanent.
+func isTransientConnectionError(err error) bool {
+	if err == nil || isAuthFailure(err) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	msg := err.Error()
+	for _, marker := range []string{
+		"no such host", "connection refused", "connection reset", "broken pipe",
+		"i/o timeout", "TLS handshake", "EOF",
+		"429 Too Many Requests", "500 Internal Server Error", "502 Bad Gateway",
+		"503 Service Unavailable", "504 Gateway Timeout",
+	} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
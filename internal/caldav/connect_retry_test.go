@@ -0,0 +1,149 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyHandler answers the first failures requests with status, then
+// hands every request to next.
+type flakyHandler struct {
+	next     http.Handler
+	status   int
+	failures int32
+	hits     atomic.Int32
+}
+
+func (h *flakyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.hits.Add(1) <= h.failures {
+		w.WriteHeader(h.status)
+		return
+	}
+	h.next.ServeHTTP(w, r)
+}
+
+func TestSyncSource_RetriesTransientConnectionTest(t *testing.T) {
+	for _, side := range []string{"source", "destination"} {
+		t.Run(side, func(t *testing.T) {
+			srcMock, destMock := &nextcloudMock{}, &nextcloudMock{}
+			var src, dest http.Handler = srcMock, destMock
+			flaky := &flakyHandler{status: http.StatusServiceUnavailable, failures: 1}
+			if side == "source" {
+				flaky.next, src = srcMock, flaky
+			} else {
+				flaky.next, dest = destMock, flaky
+			}
+			srcSrv := httptest.NewServer(src)
+			defer srcSrv.Close()
+			destSrv := httptest.NewServer(dest)
+			defer destSrv.Close()
+
+			f := newSelfLoopFixture(t)
+			f.engine.SetConnectionTestRetry(3, time.Millisecond)
+			source := f.createSource(t, "Flaky", srcSrv.URL+"/remote.php/dav/",
+				destSrv.URL+"/remote.php/dav/calendars/alice/personal/")
+
+			result := f.engine.SyncSource(context.Background(), source)
+
+			if strings.Contains(result.Message, "connection test failed") {
+				t.Fatalf("expected the retry to get past the connection test, got %q (errors %v)", result.Message, result.Errors)
+			}
+			if !srcMock.requested("PROPFIND /remote.php/dav/calendars/alice/") {
+				t.Error("expected the sync to go on to discover source calendars")
+			}
+		})
+	}
+}
+
+func TestSyncSource_ConnectionTestRetryLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		attempts int
+		wantHits int32
+	}{
+		{"rejected credentials are not retried", http.StatusUnauthorized, 3, 1},
+		{"persistent outage stops after the configured attempts", http.StatusServiceUnavailable, 2, 2},
+		{"a single attempt disables retrying", http.StatusServiceUnavailable, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flaky := &flakyHandler{next: &nextcloudMock{}, status: tt.status, failures: 100}
+			srv := httptest.NewServer(flaky)
+			defer srv.Close()
+			destSrv := httptest.NewServer(&nextcloudMock{})
+			defer destSrv.Close()
+
+			f := newSelfLoopFixture(t)
+			f.engine.SetConnectionTestRetry(tt.attempts, time.Millisecond)
+			source := f.createSource(t, "Down", srv.URL+"/remote.php/dav/",
+				destSrv.URL+"/remote.php/dav/calendars/alice/personal/")
+
+			result := f.engine.SyncSource(context.Background(), source)
+
+			if result.Message != "Source connection test failed" {
+				t.Errorf("expected the source connection test to fail, got %q", result.Message)
+			}
+			if got := flaky.hits.Load(); got != tt.wantHits {
+				t.Errorf("expected %d connection attempts, got %d", tt.wantHits, got)
+			}
+		})
+	}
+}
+
+func TestTestConnection_HonorsCanceledContext(t *testing.T) {
+	se := NewSyncEngine(nil, nil)
+	se.SetConnectionTestRetry(3, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	done := make(chan error, 1)
+	go func() {
+		done <- se.testConnection(ctx, "Source", func(context.Context) error {
+			calls++
+			cancel()
+			return errors.New("dial tcp: i/o timeout")
+		})
+	}()
+	select {
+	case err := <-done:
+		if err == nil || calls != 1 {
+			t.Errorf("expected the first error after one call, got %v after %d calls", err, calls)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("testConnection kept waiting after the context was canceled")
+	}
+}
+
+func TestIsTransientConnectionError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"dns", fmt.Errorf("%w: %w", ErrConnectionFailed, &net.DNSError{Err: "no such host", Name: "dav.example.com"}), true},
+		{"refused", errors.New("dial tcp 10.0.0.1:443: connect: connection refused"), true},
+		{"tls", errors.New("net/http: TLS handshake timeout"), true},
+		{"eof", errors.New("Propfind \"https://dav.example.com/\": EOF"), true},
+		{"503", errors.New("503 Service Unavailable"), true},
+		{"429", errors.New("429 Too Many Requests"), true},
+		{"401", fmt.Errorf("%w: 401 Unauthorized", ErrConnectionFailed), false},
+		{"404 on a port containing 500", errors.New("Propfind \"http://dav.example.com:8500/\": 404 Not Found"), false},
+		{"canceled", fmt.Errorf("request: %w", context.Canceled), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransientConnectionError(tt.err); got != tt.want {
+				t.Errorf("isTransientConnectionError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
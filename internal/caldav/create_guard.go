@@ -0,0 +1,74 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// PutEventIfNoneMatch is PutEvent for an event the caller believes the
+// destination doesn't have: the PUT carries If-None-Match: * so a
+// server that already holds a resource at the event's path refuses it
+// instead of overwriting it. It returns a wrapped ErrEventExists in
+// that case, and otherwise behaves like PutEvent.
+func (c *Client) PutEventIfNoneMatch(ctx context.Context, calendarPath string, event *Event) error {
+	return c.putEvent(ctx, calendarPath, event, true)
+}
+
+// putNewEvent PUTs data to path only if nothing is there yet.
+func (c *Client) putNewEvent(ctx context.Context, path, data string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.buildURL(path), strings.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+	req.Header.Set("If-None-Match", "*")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: failed to put event: %w", ErrConnectionFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		return fmt.Errorf("%w: %s", ErrEventExists, path)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: failed to put event: %w", ErrConnectionFailed, putStatusError(resp))
+	}
+	return nil
+}
+
+// maxPutErrorBody bounds how much of a refused PUT's response goes
+// into its error.
+const maxPutErrorBody = 1024
+
+// putStatusError describes a failed PUT the way go-webdav does ("400
+// Bad Request: <body>"), so classifyPutRejection can tell why the
+// server refused it.
+func putStatusError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxPutErrorBody))
+	status := fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	if text := strings.TrimSpace(string(body)); text != "" {
+		return fmt.Errorf("%s: %s", status, text)
+	}
+	return errors.New(status)
+}
+
+// createEvent writes an event the destination listing didn't have.
+// If another client created it in the meantime, the server refuses
+// the create and the event is written over that copy like any update;
+// updated reports that it was.
+func createEvent(ctx context.Context, destClient *Client, calendarPath string, event *Event) (updated bool, err error) {
+	err = destClient.PutEventIfNoneMatch(ctx, calendarPath, event)
+	if !errors.Is(err, ErrEventExists) {
+		return false, err
+	}
+	log.Printf("Event %s was created on the destination concurrently; updating it instead", event.UID)
+	return true, destClient.PutEvent(ctx, calendarPath, event)
+}
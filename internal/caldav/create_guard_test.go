@@ -0,0 +1,189 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emersion/go-ical"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func TestPutEventIfNoneMatch(t *testing.T) {
+	store := newCalendarStore()
+	existing := mergeICS("UID:taken@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:Theirs\r\n")
+	store.set("/dest/taken@example.com.ics", existing)
+	srv := httptest.NewServer(store)
+	defer srv.Close()
+	client, err := NewClient(srv.URL+"/dest/", "dest", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	taken := &Event{UID: "taken@example.com", Data: mergeICS("UID:taken@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:Ours\r\n")}
+	if err := client.PutEventIfNoneMatch(context.Background(), "/dest/", taken); !errors.Is(err, ErrEventExists) {
+		t.Fatalf("expected ErrEventExists for an existing resource, got %v", err)
+	}
+	if store.data["/dest/taken@example.com.ics"] != existing {
+		t.Error("expected the existing resource left untouched")
+	}
+
+	fresh := &Event{UID: "fresh@example.com", Data: mergeICS("UID:fresh@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260106T100000Z\r\nSUMMARY:New\r\n")}
+	if err := client.PutEventIfNoneMatch(context.Background(), "/dest/", fresh); err != nil {
+		t.Fatalf("PutEventIfNoneMatch: %v", err)
+	}
+	if !strings.Contains(store.data["/dest/fresh@example.com.ics"], "SUMMARY:New") {
+		t.Errorf("expected the new event written, got %v", store.data)
+	}
+}
+
+// racingStore is a calendarStore where another client creates race
+// just before the first conditional PUT to its path arrives, after
+// the destination was listed.
+type racingStore struct {
+	*calendarStore
+	path, race string
+}
+
+func (s *racingStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPut && r.URL.Path == s.path && r.Header.Get("If-None-Match") == "*" && s.race != "" {
+		s.mu.Lock()
+		s.set(s.path, s.race)
+		s.race = ""
+		s.mu.Unlock()
+	}
+	s.calendarStore.ServeHTTP(w, r)
+}
+
+// newCreateFixture returns an engine and a one-way source syncing an
+// empty source calendar to dest, with clients for both sides.
+func newCreateFixture(t *testing.T, dest http.Handler) (*SyncEngine, *db.Source, *Client, *Client) {
+	t.Helper()
+	destSrv := httptest.NewServer(dest)
+	t.Cleanup(destSrv.Close)
+	srcSrv := httptest.NewServer(newCalendarStore())
+	t.Cleanup(srcSrv.Close)
+
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	user, err := database.GetOrCreateUser("create@example.com", "Create")
+	if err != nil {
+		t.Fatalf("GetOrCreateUser: %v", err)
+	}
+	source := &db.Source{
+		UserID:         user.ID,
+		Name:           "Creates",
+		SourceType:     db.SourceTypeCustom,
+		SourceURL:      srcSrv.URL + "/cal/",
+		SourceUsername: "user",
+		SourcePassword: "encrypted",
+		DestURL:        destSrv.URL + "/dest/",
+		DestUsername:   "dest",
+		DestPassword:   "encrypted",
+		SyncInterval:   3600,
+		SyncDirection:  db.SyncDirectionOneWay,
+		Enabled:        true,
+	}
+	if err := database.CreateSource(source); err != nil {
+		t.Fatalf("CreateSource: %v", err)
+	}
+	sourceClient, err := NewClient(srcSrv.URL+"/cal/", "user", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	destClient, err := NewClient(destSrv.URL+"/dest/", "dest", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	return NewSyncEngine(database, nil), source, sourceClient, destClient
+}
+
+func TestSyncEventsToDestination_CreateRaceBecomesUpdate(t *testing.T) {
+	destStore := &racingStore{
+		calendarStore: newCalendarStore(),
+		path:          "/dest/standup@example.com.ics",
+		race:          mergeICS("UID:standup@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:Created elsewhere\r\n"),
+	}
+	se, source, sourceClient, destClient := newCreateFixture(t, destStore)
+
+	events := []Event{{
+		Path:      "/cal/standup.ics",
+		ETag:      "src-1",
+		UID:       "standup@example.com",
+		Summary:   "Standup",
+		StartTime: "20260105T100000Z",
+		Data:      mergeICS("UID:standup@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:Standup\r\n"),
+	}}
+	result := se.syncEventsToDestination(context.Background(), source, sourceClient, destClient, events,
+		Calendar{Path: "/cal/", Name: "Cal"}, 1, db.SyncDirectionOneWay)
+
+	if result.Created != 0 || result.Updated != 1 {
+		t.Errorf("expected the raced create counted as one update, got created %d updated %d", result.Created, result.Updated)
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("expected no errors, got %v", result.Errors)
+	}
+	for _, w := range result.Warnings {
+		if strings.Contains(w, "Failed to create") {
+			t.Errorf("expected the race not reported as a failure, got warning %q", w)
+		}
+	}
+	if got := propValue(mergedMaster(t, destStore.data[destStore.path]), ical.PropSummary); got != "Standup" {
+		t.Errorf("destination SUMMARY = %q, want the source's version", got)
+	}
+	synced, err := se.db.GetSyncedEventsForSource(source.ID)
+	if err != nil {
+		t.Fatalf("GetSyncedEventsForSource: %v", err)
+	}
+	if len(synced) != 1 || synced[0].EventUID != "standup@example.com" {
+		t.Errorf("expected the event tracked as synced, got %+v", synced)
+	}
+}
+
+func TestSyncEventsToDestination_CreateRejectionClassified(t *testing.T) {
+	store := newCalendarStore()
+	dest := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			store.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Unknown TZID: Mars/Olympus_Mons"))
+	})
+	se, source, sourceClient, destClient := newCreateFixture(t, dest)
+
+	events := []Event{{
+		Path:      "/cal/mars.ics",
+		ETag:      "src-1",
+		UID:       "mars@example.com",
+		Summary:   "Landing",
+		StartTime: "20260105T100000Z",
+		Data: mergeICS("UID:mars@example.com\r\nDTSTAMP:20260101T000000Z\r\n" +
+			"DTSTART;TZID=Mars/Olympus_Mons:20260105T100000\r\nSUMMARY:Landing\r\n"),
+	}}
+	result := se.syncEventsToDestination(context.Background(), source, sourceClient, destClient, events,
+		Calendar{Path: "/cal/", Name: "Cal"}, 1, db.SyncDirectionOneWay)
+
+	if result.PutRejections["unknown timezone"] != 1 {
+		t.Errorf("expected the create counted as an unknown-timezone rejection, got %v (warnings %v)",
+			result.PutRejections, result.Warnings)
+	}
+	found := false
+	for _, w := range result.Warnings {
+		if strings.Contains(w, "unknown timezone") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected an unknown-timezone warning, got %v", result.Warnings)
+	}
+}
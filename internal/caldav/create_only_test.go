@@ -0,0 +1,99 @@
+package caldav
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func TestSyncEventsToDestination_CreateOnly(t *testing.T) {
+	vevent := func(uid, summary string) string {
+		return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VEVENT\r\n" +
+			"UID:" + uid + "\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\n" +
+			"SUMMARY:" + summary + "\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	}
+
+	destStore := newCalendarStore()
+	destSrv := httptest.NewServer(destStore)
+	defer destSrv.Close()
+	// The user annotated a holiday the feed created, and kept an
+	// earlier one the feed has since dropped.
+	annotated := vevent("new-year@example.com", "New Year (office closed)")
+	destStore.set("/dest/new-year@example.com.ics", annotated)
+	dropped := vevent("dropped@example.com", "Dropped holiday")
+	destStore.set("/dest/dropped@example.com.ics", dropped)
+
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	user, err := database.GetOrCreateUser("holidays@example.com", "Holidays")
+	if err != nil {
+		t.Fatalf("GetOrCreateUser: %v", err)
+	}
+	source := &db.Source{
+		UserID:           user.ID,
+		Name:             "Holidays",
+		SourceType:       db.SourceTypeCustom,
+		SourceURL:        "https://source.example.com/cal/",
+		SourceUsername:   "user",
+		SourcePassword:   "encrypted",
+		DestURL:          destSrv.URL + "/dest/",
+		DestUsername:     "dest",
+		DestPassword:     "encrypted",
+		SyncInterval:     3600,
+		SyncDirection:    db.SyncDirectionOneWayCreateOnly,
+		ConflictStrategy: db.ConflictSourceWins,
+		Enabled:          true,
+	}
+	if err := database.CreateSource(source); err != nil {
+		t.Fatalf("CreateSource: %v", err)
+	}
+	// Everything but the brand-new event was synced before; "deleted"
+	// has since been removed from the destination by the user.
+	for _, uid := range []string{"new-year@example.com", "dropped@example.com", "deleted@example.com"} {
+		if err := database.UpsertSyncedEvent(&db.SyncedEvent{
+			SourceID: source.ID, CalendarHref: "/cal/", EventUID: uid, SourceETag: `"old"`,
+		}); err != nil {
+			t.Fatalf("UpsertSyncedEvent: %v", err)
+		}
+	}
+	destClient, err := NewClient(destSrv.URL+"/dest/", "dest", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	sourceEvents := []Event{
+		{Path: "/cal/new-year.ics", ETag: `"changed"`, UID: "new-year@example.com", Data: vevent("new-year@example.com", "New Year's Day")},
+		{Path: "/cal/deleted.ics", ETag: `"old"`, UID: "deleted@example.com", Data: vevent("deleted@example.com", "Deleted holiday")},
+		{Path: "/cal/fresh.ics", ETag: `"new"`, UID: "fresh@example.com", Data: vevent("fresh@example.com", "Fresh holiday")},
+	}
+
+	se := NewSyncEngine(database, nil)
+	result := se.syncEventsToDestination(context.Background(), source, nil, destClient, sourceEvents,
+		Calendar{Path: "/cal/", Name: "Holidays"}, 1, db.SyncDirectionOneWayCreateOnly)
+
+	if result.Created != 1 || result.Updated != 0 || result.Deleted != 0 {
+		t.Errorf("expected 1 created and nothing updated or deleted, got %d/%d/%d (errors %v, warnings %v)",
+			result.Created, result.Updated, result.Deleted, result.Errors, result.Warnings)
+	}
+	if destStore.puts != 1 {
+		t.Errorf("expected a single PUT, for the new event, got %d", destStore.puts)
+	}
+	if destStore.data["/dest/new-year@example.com.ics"] != annotated {
+		t.Error("expected the divergent destination copy to be left untouched")
+	}
+	if destStore.data["/dest/dropped@example.com.ics"] != dropped {
+		t.Error("expected the event the source dropped to stay on the destination")
+	}
+	if _, ok := destStore.data["/dest/deleted@example.com.ics"]; ok {
+		t.Error("expected the event deleted on the destination not to be created again")
+	}
+	if _, ok := destStore.data["/dest/fresh@example.com.ics"]; !ok {
+		t.Errorf("expected the new event to be created, destination holds %v", destStore.etags)
+	}
+}
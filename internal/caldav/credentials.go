@@ -0,0 +1,58 @@
+package caldav
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// iCloudAppPassword matches an Apple app-specific password typed with
+// spaces, or nothing, where Apple shows hyphens ("abcd efgh ijkl mnop").
+var iCloudAppPassword = regexp.MustCompile(`^([a-z]{4})[ -]?([a-z]{4})[ -]?([a-z]{4})[ -]?([a-z]{4})$`)
+
+// isICloudURL reports whether a CalDAV base URL points at iCloud.
+func isICloudURL(baseURL string) bool {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return host == "icloud.com" || strings.HasSuffix(host, ".icloud.com")
+}
+
+// normalizeCredentials cleans up Basic Auth credentials as users paste
+// them. Surrounding whitespace is trimmed from both, and an iCloud
+// app-specific password entered with spaces instead of hyphens is put
+// back in Apple's hyphenated form. Anything else, including ':' and
+// non-ASCII characters in the password, is passed through unchanged:
+// the Authorization header Base64-encodes the raw UTF-8 bytes.
+//
+// Credentials that cannot be sent are refused: a ':' in the username
+// would be read back as the start of the password (RFC 7617 §2), and
+// control characters are never part of a real credential.
+func normalizeCredentials(baseURL, username, password string) (string, string, error) {
+	username = strings.TrimSpace(username)
+	password = strings.TrimSpace(password)
+
+	if strings.Contains(username, ":") {
+		return "", "", fmt.Errorf("%w: username must not contain ':'", ErrAuthFailed)
+	}
+	if strings.IndexFunc(username+password, isControl) >= 0 {
+		return "", "", fmt.Errorf("%w: credentials contain control characters", ErrAuthFailed)
+	}
+
+	if isICloudURL(baseURL) {
+		if m := iCloudAppPassword.FindStringSubmatch(password); m != nil {
+			password = strings.Join(m[1:], "-")
+		} else if strings.ContainsAny(password, " \t") {
+			log.Printf("iCloud password for %s contains whitespace; iCloud expects an app-specific password (xxxx-xxxx-xxxx-xxxx)", username)
+		}
+	}
+	return username, password, nil
+}
+
+func isControl(r rune) bool {
+	return r < 0x20 || r == 0x7f
+}
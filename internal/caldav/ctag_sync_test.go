@@ -0,0 +1,641 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// collectionStateMock fronts a calendarStore with the collection-level
+// parts of the protocol: a getctag/sync-token PROPFIND and the
+// sync-collection REPORT. Any other REPORT is a calendar-query, i.e. a
+// full enumeration.
+type collectionStateMock struct {
+	store *calendarStore
+
+	mu        sync.Mutex
+	ctag      string
+	syncToken string
+	// lastModified is reported as the collection's getlastmodified
+	// when set.
+	lastModified string
+	// deltaFails makes sync-collection answer 403, like a server that
+	// has expired the client's token.
+	deltaFails bool
+	// tokenInvalid makes sync-collection fail the RFC 6578
+	// DAV:valid-sync-token precondition with 409.
+	tokenInvalid bool
+	requests     []string
+	lastToken    string
+}
+
+func (m *collectionStateMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch {
+	case r.Method == "PROPFIND" && strings.Contains(string(body), "getctag"):
+		m.requests = append(m.requests, "collection-state")
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusMultiStatus)
+		lastModified := ""
+		if m.lastModified != "" {
+			lastModified = "<d:getlastmodified>" + html.EscapeString(m.lastModified) + "</d:getlastmodified>"
+		}
+		fmt.Fprintf(w, `<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/">`+
+			`<d:response><d:href>%s</d:href><d:propstat><d:prop><cs:getctag>%s</cs:getctag><d:sync-token>%s</d:sync-token>%s</d:prop>`+
+			`<d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response></d:multistatus>`,
+			r.URL.Path, html.EscapeString(m.ctag), html.EscapeString(m.syncToken), lastModified)
+	case r.Method == "REPORT" && strings.Contains(string(body), "sync-collection"):
+		m.requests = append(m.requests, "sync-collection")
+		if start := strings.Index(string(body), "<D:sync-token>"); start != -1 {
+			rest := string(body)[start+len("<D:sync-token>"):]
+			m.lastToken = rest[:strings.Index(rest, "<")]
+		}
+		if m.deltaFails {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if m.tokenInvalid {
+			w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+			w.WriteHeader(http.StatusConflict)
+			_, _ = io.WriteString(w, `<?xml version="1.0"?><D:error xmlns:D="DAV:"><D:valid-sync-token/></D:error>`)
+			return
+		}
+		m.store.mu.Lock()
+		defer m.store.mu.Unlock()
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusMultiStatus)
+		var b strings.Builder
+		b.WriteString(`<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">`)
+		for path, data := range m.store.data {
+			fmt.Fprintf(&b, `<d:response><d:href>%s</d:href><d:propstat><d:prop><d:getetag>%s</d:getetag>`+
+				`<C:calendar-data>%s</C:calendar-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`,
+				path, html.EscapeString(`"`+m.store.etags[path]+`"`), html.EscapeString(data))
+		}
+		fmt.Fprintf(&b, `<d:sync-token>%s</d:sync-token></d:multistatus>`, html.EscapeString(m.syncToken))
+		_, _ = w.Write([]byte(b.String()))
+	default:
+		if r.Method == "REPORT" {
+			m.requests = append(m.requests, "calendar-query")
+		}
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+		m.store.ServeHTTP(w, r)
+	}
+}
+
+// take returns the requests seen since the last call.
+func (m *collectionStateMock) take() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	got := m.requests
+	m.requests = nil
+	return got
+}
+
+func (m *collectionStateMock) setState(ctag, syncToken string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ctag, m.syncToken = ctag, syncToken
+}
+
+func TestSyncCalendar_CTagAndSyncTokenStrategy(t *testing.T) {
+	srcStore, destStore := newCalendarStore(), newCalendarStore()
+	srcStore.set("/cal/one.ics", mergeICS("UID:one@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:One\r\n"))
+	// An unrelated event keeps the destination listing non-empty; an
+	// empty calendar-query result makes the client retry via PROPFIND,
+	// which calendarStore does not answer.
+	destStore.set("/dest/other.ics", mergeICS("UID:other@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260107T100000Z\r\nSUMMARY:Other\r\n"))
+	srcMock := &collectionStateMock{store: srcStore, ctag: "ctag-1", syncToken: "token-1"}
+	srcSrv := httptest.NewServer(srcMock)
+	defer srcSrv.Close()
+	destSrv := httptest.NewServer(destStore)
+	defer destSrv.Close()
+
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	user, err := database.GetOrCreateUser("ctag@example.com", "CTag")
+	if err != nil {
+		t.Fatalf("GetOrCreateUser: %v", err)
+	}
+	source := &db.Source{
+		UserID:           user.ID,
+		Name:             "CTag",
+		SourceType:       db.SourceTypeCustom,
+		SourceURL:        srcSrv.URL + "/cal/",
+		SourceUsername:   "user",
+		SourcePassword:   "encrypted",
+		DestURL:          destSrv.URL + "/dest/",
+		DestUsername:     "dest",
+		DestPassword:     "encrypted",
+		SyncInterval:     3600,
+		SyncDirection:    db.SyncDirectionOneWay,
+		ConflictStrategy: db.ConflictSourceWins,
+		Enabled:          true,
+	}
+	if err := database.CreateSource(source); err != nil {
+		t.Fatalf("CreateSource: %v", err)
+	}
+
+	sourceClient, err := NewClient(srcSrv.URL+"/cal/", "user", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	destClient, err := NewClient(destSrv.URL+"/dest/", "dest", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	se := NewSyncEngine(database, nil)
+	calendar := Calendar{Path: "/cal/", Name: "Cal"}
+
+	syncOnce := func(t *testing.T) []string {
+		t.Helper()
+		result := se.syncCalendar(context.Background(), source, sourceClient, destClient, calendar, 1)
+		if len(result.Errors) > 0 || len(result.Warnings) > 0 {
+			t.Fatalf("unexpected errors %v / warnings %v", result.Errors, result.Warnings)
+		}
+		return srcMock.take()
+	}
+	storedState := func(t *testing.T) *db.SyncState {
+		t.Helper()
+		state, err := database.GetSyncState(source.ID, calendar.Path)
+		if err != nil {
+			t.Fatalf("GetSyncState: %v", err)
+		}
+		return state
+	}
+	has := func(requests []string, want string) bool {
+		for _, r := range requests {
+			if r == want {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("no sync token runs a full sync and records the markers", func(t *testing.T) {
+		requests := syncOnce(t)
+		if !has(requests, "calendar-query") || has(requests, "sync-collection") {
+			t.Errorf("expected a full enumeration only, got %v", requests)
+		}
+		if destStore.puts != 1 {
+			t.Errorf("expected the event to be written to the destination, got %d PUTs", destStore.puts)
+		}
+		if state := storedState(t); state.CTag != "ctag-1" || state.SyncToken != "token-1" {
+			t.Errorf("expected ctag-1/token-1 to be recorded, got %q/%q", state.CTag, state.SyncToken)
+		}
+	})
+
+	t.Run("unchanged CTag skips the calendar", func(t *testing.T) {
+		puts := destStore.puts
+		requests := syncOnce(t)
+		if len(requests) != 1 || requests[0] != "collection-state" {
+			t.Errorf("expected only the CTag check, got %v", requests)
+		}
+		if destStore.puts != puts {
+			t.Errorf("expected no writes, got %d", destStore.puts-puts)
+		}
+	})
+
+	t.Run("changed CTag with a token fetches the delta", func(t *testing.T) {
+		srcStore.mu.Lock()
+		srcStore.set("/cal/two.ics", mergeICS("UID:two@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260106T100000Z\r\nSUMMARY:Two\r\n"))
+		srcStore.mu.Unlock()
+		srcMock.setState("ctag-2", "token-2")
+
+		requests := syncOnce(t)
+		if !has(requests, "sync-collection") || has(requests, "calendar-query") {
+			t.Errorf("expected a delta fetch only, got %v", requests)
+		}
+		if srcMock.lastToken != "token-1" {
+			t.Errorf("expected the delta to start from token-1, got %q", srcMock.lastToken)
+		}
+		if _, ok := destStore.data["/dest/two@example.com.ics"]; !ok {
+			t.Errorf("expected the new event on the destination, have %d events", len(destStore.data))
+		}
+		if state := storedState(t); state.CTag != "ctag-2" || state.SyncToken != "token-2" {
+			t.Errorf("expected ctag-2/token-2 to be recorded, got %q/%q", state.CTag, state.SyncToken)
+		}
+	})
+
+	t.Run("failed delta falls back to a full sync", func(t *testing.T) {
+		srcMock.setState("ctag-3", "token-3")
+		srcMock.mu.Lock()
+		srcMock.deltaFails = true
+		srcMock.mu.Unlock()
+		defer func() {
+			srcMock.mu.Lock()
+			srcMock.deltaFails = false
+			srcMock.mu.Unlock()
+		}()
+
+		requests := syncOnce(t)
+		if !has(requests, "sync-collection") || !has(requests, "calendar-query") {
+			t.Errorf("expected a delta attempt then a full enumeration, got %v", requests)
+		}
+		if state := storedState(t); state.CTag != "ctag-3" || state.SyncToken != "token-3" {
+			t.Errorf("expected ctag-3/token-3 to be recorded, got %q/%q", state.CTag, state.SyncToken)
+		}
+	})
+
+	t.Run("two-way calendars always sync in full", func(t *testing.T) {
+		source.SyncDirection = db.SyncDirectionTwoWay
+		defer func() { source.SyncDirection = db.SyncDirectionOneWay }()
+
+		requests := syncOnce(t)
+		if !has(requests, "calendar-query") || has(requests, "sync-collection") {
+			t.Errorf("expected a full enumeration despite the unchanged CTag, got %v", requests)
+		}
+	})
+}
+
+func TestSyncCalendar_LastModifiedStrategy(t *testing.T) {
+	srcStore, destStore := newCalendarStore(), newCalendarStore()
+	srcStore.set("/cal/one.ics", mergeICS("UID:one@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:One\r\n"))
+	destStore.set("/dest/other.ics", mergeICS("UID:other@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260107T100000Z\r\nSUMMARY:Other\r\n"))
+	// No CTag and no sync-token: only the collection's date changes.
+	srcMock := &collectionStateMock{store: srcStore, lastModified: "Mon, 05 Jan 2026 10:00:00 GMT"}
+	srcSrv := httptest.NewServer(srcMock)
+	defer srcSrv.Close()
+	destSrv := httptest.NewServer(destStore)
+	defer destSrv.Close()
+
+	f := newSelfLoopFixture(t)
+	source := f.createSource(t, "Modified", srcSrv.URL+"/cal/", destSrv.URL+"/dest/")
+	source.SyncDirection = db.SyncDirectionOneWay
+	sourceClient, err := NewClient(srcSrv.URL+"/cal/", "alice", "source-pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	destClient, err := NewClient(destSrv.URL+"/dest/", "alice", "dest-pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	calendar := Calendar{Path: "/cal/", Name: "Cal"}
+	syncOnce := func(t *testing.T) []string {
+		t.Helper()
+		result := f.engine.syncCalendar(context.Background(), source, sourceClient, destClient, calendar, 1)
+		if len(result.Errors) > 0 || len(result.Warnings) > 0 {
+			t.Fatalf("unexpected errors %v / warnings %v", result.Errors, result.Warnings)
+		}
+		return srcMock.take()
+	}
+
+	requests := syncOnce(t)
+	if len(requests) < 2 || requests[1] != "calendar-query" {
+		t.Fatalf("expected a full sync first, got %v", requests)
+	}
+	state, err := f.database.GetSyncState(source.ID, calendar.Path)
+	if err != nil {
+		t.Fatalf("GetSyncState: %v", err)
+	}
+	if state.LastModified != "Mon, 05 Jan 2026 10:00:00 GMT" {
+		t.Errorf("expected the last-modified date recorded, got %q", state.LastModified)
+	}
+
+	t.Run("unchanged last-modified skips the calendar", func(t *testing.T) {
+		puts := destStore.puts
+		if requests := syncOnce(t); len(requests) != 1 || requests[0] != "collection-state" {
+			t.Errorf("expected only the collection check, got %v", requests)
+		}
+		if destStore.puts != puts {
+			t.Errorf("expected no writes, got %d", destStore.puts-puts)
+		}
+	})
+
+	t.Run("changed last-modified syncs", func(t *testing.T) {
+		srcStore.mu.Lock()
+		srcStore.set("/cal/two.ics", mergeICS("UID:two@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260106T100000Z\r\nSUMMARY:Two\r\n"))
+		srcStore.mu.Unlock()
+		srcMock.mu.Lock()
+		srcMock.lastModified = "Tue, 06 Jan 2026 09:30:00 GMT"
+		srcMock.mu.Unlock()
+
+		if requests := syncOnce(t); len(requests) < 2 || requests[1] != "calendar-query" {
+			t.Errorf("expected a full sync, got %v", requests)
+		}
+		destStore.mu.Lock()
+		_, ok := destStore.data["/dest/two@example.com.ics"]
+		destStore.mu.Unlock()
+		if !ok {
+			t.Error("expected the new event on the destination")
+		}
+	})
+
+	t.Run("a CTag takes precedence", func(t *testing.T) {
+		srcMock.setState("ctag-1", "")
+		syncOnce(t)
+		// The date stays put while the CTag moves: a server that
+		// doesn't touch the collection's date on member changes.
+		srcMock.setState("ctag-2", "")
+		if requests := syncOnce(t); len(requests) < 2 || requests[1] != "calendar-query" {
+			t.Errorf("expected the changed CTag to force a sync, got %v", requests)
+		}
+	})
+}
+
+func TestSyncCalendar_InvalidSyncToken(t *testing.T) {
+	srcStore, destStore := newCalendarStore(), newCalendarStore()
+	srcStore.set("/cal/one.ics", mergeICS("UID:one@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:One\r\n"))
+	destStore.set("/dest/other.ics", mergeICS("UID:other@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260107T100000Z\r\nSUMMARY:Other\r\n"))
+	srcMock := &collectionStateMock{store: srcStore, ctag: "ctag-2", syncToken: "token-2", tokenInvalid: true}
+	srcSrv := httptest.NewServer(srcMock)
+	defer srcSrv.Close()
+	var failPuts atomic.Bool
+	destSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && failPuts.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		destStore.ServeHTTP(w, r)
+	}))
+	defer destSrv.Close()
+
+	f := newSelfLoopFixture(t)
+	source := f.createSource(t, "Token", srcSrv.URL+"/cal/", destSrv.URL+"/dest/")
+	source.SyncDirection = db.SyncDirectionOneWay
+	sourceClient, err := NewClient(srcSrv.URL+"/cal/", "alice", "source-pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	destClient, err := NewClient(destSrv.URL+"/dest/", "alice", "dest-pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	calendar := Calendar{Path: "/cal/", Name: "Cal"}
+	storeState := func(t *testing.T, ctag, token string) {
+		t.Helper()
+		if err := f.database.UpsertSyncState(&db.SyncState{SourceID: source.ID, CalendarHref: calendar.Path, CTag: ctag, SyncToken: token}); err != nil {
+			t.Fatalf("UpsertSyncState: %v", err)
+		}
+	}
+	storedState := func(t *testing.T) *db.SyncState {
+		t.Helper()
+		state, err := f.database.GetSyncState(source.ID, calendar.Path)
+		if err != nil {
+			t.Fatalf("GetSyncState: %v", err)
+		}
+		return state
+	}
+
+	t.Run("rejected token is cleared even when the full sync fails", func(t *testing.T) {
+		storeState(t, "ctag-1", "token-1")
+		failPuts.Store(true)
+		defer failPuts.Store(false)
+
+		f.engine.syncCalendar(context.Background(), source, sourceClient, destClient, calendar, 1)
+		requests := srcMock.take()
+		if len(requests) < 3 || requests[1] != "sync-collection" || requests[2] != "calendar-query" {
+			t.Errorf("expected a delta attempt then a full enumeration, got %v", requests)
+		}
+		if state := storedState(t); state.SyncToken != "" || state.CTag != "" {
+			t.Errorf("expected the stale markers to be cleared, got %q/%q", state.CTag, state.SyncToken)
+		}
+	})
+
+	t.Run("a clean full sync records fresh markers", func(t *testing.T) {
+		storeState(t, "ctag-1", "token-1")
+
+		result := f.engine.syncCalendar(context.Background(), source, sourceClient, destClient, calendar, 1)
+		if len(result.Errors) > 0 || len(result.Warnings) > 0 {
+			t.Fatalf("unexpected errors %v / warnings %v", result.Errors, result.Warnings)
+		}
+		if state := storedState(t); state.CTag != "ctag-2" || state.SyncToken != "token-2" {
+			t.Errorf("expected ctag-2/token-2 after the full sync, got %q/%q", state.CTag, state.SyncToken)
+		}
+	})
+
+	t.Run("dry runs leave the stored token alone", func(t *testing.T) {
+		storeState(t, "ctag-1", "token-1")
+
+		f.engine.syncCalendar(WithDryRun(context.Background()), source, sourceClient, destClient, calendar, 1)
+		if state := storedState(t); state.SyncToken != "token-1" {
+			t.Errorf("expected the dry run not to touch the token, got %q", state.SyncToken)
+		}
+	})
+}
+
+func TestIsInvalidSyncTokenError(t *testing.T) {
+	for body, want := range map[string]bool{
+		`<?xml version="1.0"?><D:error xmlns:D="DAV:"><D:valid-sync-token/></D:error>`:  true,
+		`<error xmlns="DAV:"><valid-sync-token/></error>`:                               true,
+		`<d:error xmlns:d="DAV:"><d:number-of-matches-within-limits/></d:error>`:        false,
+		`<d:error xmlns:d="DAV:" xmlns:x="urn:example"><x:valid-sync-token/></d:error>`: false,
+		`Sync token expired`: false,
+	} {
+		if got := isInvalidSyncTokenError([]byte(body)); got != want {
+			t.Errorf("isInvalidSyncTokenError(%q) = %v, want %v", body, got, want)
+		}
+	}
+}
+
+func TestParseCollectionState(t *testing.T) {
+	body := `<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/">` +
+		`<d:response><d:href>/cal/</d:href>` +
+		`<d:propstat><d:prop><cs:getctag>"abc"</cs:getctag><d:getlastmodified>Mon, 05 Jan 2026 10:00:00 GMT</d:getlastmodified></d:prop>` +
+		`<d:status>HTTP/1.1 200 OK</d:status></d:propstat>` +
+		`<d:propstat><d:prop><d:sync-token/></d:prop><d:status>HTTP/1.1 404 Not Found</d:status></d:propstat>` +
+		`</d:response></d:multistatus>`
+	state, err := parseCollectionState([]byte(body))
+	if err != nil {
+		t.Fatalf("parseCollectionState: %v", err)
+	}
+	if state.CTag != `"abc"` || state.SyncToken != "" {
+		t.Errorf("got CTag %q, SyncToken %q; want \"abc\" and none", state.CTag, state.SyncToken)
+	}
+	if state.LastModified != "Mon, 05 Jan 2026 10:00:00 GMT" {
+		t.Errorf("got LastModified %q", state.LastModified)
+	}
+}
+
+func TestSyncCalendar_DeltaDropsFilteredOutEvents(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(*db.Source)
+		before    string
+		after     string
+	}{
+		{
+			name:      "category removed",
+			configure: func(s *db.Source) { s.IncludeCategories = []string{"Work"} },
+			before:    "CATEGORIES:Work\r\n",
+			after:     "CATEGORIES:Home\r\n",
+		},
+		{
+			name: "now an invitation",
+			configure: func(s *db.Source) {
+				s.SkipInvitedEvents = true
+				s.AccountEmail = "me@example.com"
+			},
+			before: "ORGANIZER:mailto:me@example.com\r\n",
+			after:  "ORGANIZER:mailto:boss@example.com\r\nATTENDEE:mailto:me@example.com\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := func(extra string) string {
+				return mergeICS("UID:one@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:One\r\n" + extra)
+			}
+			srcStore, destStore := newCalendarStore(), newCalendarStore()
+			srcStore.set("/cal/one.ics", event(tt.before))
+			destStore.set("/dest/other.ics", mergeICS("UID:other@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260107T100000Z\r\nSUMMARY:Other\r\n"))
+			srcMock := &collectionStateMock{store: srcStore, ctag: "ctag-1", syncToken: "token-1"}
+			srcSrv := httptest.NewServer(srcMock)
+			defer srcSrv.Close()
+			destSrv := httptest.NewServer(destStore)
+			defer destSrv.Close()
+
+			database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+			if err != nil {
+				t.Fatalf("db.New: %v", err)
+			}
+			defer database.Close()
+			user, err := database.GetOrCreateUser("filter@example.com", "Filter")
+			if err != nil {
+				t.Fatalf("GetOrCreateUser: %v", err)
+			}
+			source := &db.Source{
+				UserID:           user.ID,
+				Name:             "Filtered",
+				SourceType:       db.SourceTypeCustom,
+				SourceURL:        srcSrv.URL + "/cal/",
+				SourceUsername:   "user",
+				SourcePassword:   "encrypted",
+				DestURL:          destSrv.URL + "/dest/",
+				DestUsername:     "dest",
+				DestPassword:     "encrypted",
+				SyncInterval:     3600,
+				SyncDirection:    db.SyncDirectionOneWay,
+				ConflictStrategy: db.ConflictSourceWins,
+				Enabled:          true,
+			}
+			tt.configure(source)
+			if err := database.CreateSource(source); err != nil {
+				t.Fatalf("CreateSource: %v", err)
+			}
+			sourceClient, err := NewClient(srcSrv.URL+"/cal/", "user", "pass")
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			destClient, err := NewClient(destSrv.URL+"/dest/", "dest", "pass")
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			se := NewSyncEngine(database, nil)
+			calendar := Calendar{Path: "/cal/", Name: "Cal"}
+
+			se.syncCalendar(context.Background(), source, sourceClient, destClient, calendar, 1)
+			if _, ok := destStore.data["/dest/one@example.com.ics"]; !ok {
+				t.Fatalf("expected the matching event on the destination, have %d events", len(destStore.data))
+			}
+
+			srcStore.mu.Lock()
+			srcStore.set("/cal/one.ics", event(tt.after))
+			srcStore.mu.Unlock()
+			srcMock.setState("ctag-2", "token-2")
+			se.syncCalendar(context.Background(), source, sourceClient, destClient, calendar, 1)
+
+			if requests := srcMock.take(); !strings.Contains(strings.Join(requests, ","), "sync-collection") {
+				t.Fatalf("expected the second sync to fetch the delta, got %v", requests)
+			}
+			if _, ok := destStore.data["/dest/one@example.com.ics"]; ok {
+				t.Error("expected the filtered-out event's copy removed from the destination")
+			}
+			if _, ok := destStore.data["/dest/other.ics"]; !ok {
+				t.Error("expected events the source never wrote left alone")
+			}
+			synced, err := database.GetSyncedEvents(source.ID, calendar.Path)
+			if err != nil {
+				t.Fatalf("GetSyncedEvents: %v", err)
+			}
+			if len(synced) != 0 {
+				t.Errorf("expected the event untracked, got %d synced events", len(synced))
+			}
+		})
+	}
+}
+
+func TestSyncSource_AdditionalDestination(t *testing.T) {
+	for _, marker := range []string{"CTag", "last modified"} {
+		t.Run(marker, func(t *testing.T) {
+			testAdditionalDestination(t, marker == "CTag")
+		})
+	}
+}
+
+// testAdditionalDestination syncs a source with one additional
+// destination twice, the source changing in between, and checks both
+// destinations end up with every event. The source reports its changes
+// through a CTag or, without one, its last-modified date.
+func testAdditionalDestination(t *testing.T, ctag bool) {
+	src := &discoveryStore{store: newCalendarStore()}
+	if ctag {
+		src.ctag = "ctag-1"
+	} else {
+		src.lastModified = "Mon, 05 Jan 2026 10:00:00 GMT"
+	}
+	primary := &discoveryStore{store: newCalendarStore()}
+	extra := &discoveryStore{store: newCalendarStore()}
+	srcSrv := httptest.NewServer(src)
+	defer srcSrv.Close()
+	primarySrv := httptest.NewServer(primary)
+	defer primarySrv.Close()
+	extraSrv := httptest.NewServer(extra)
+	defer extraSrv.Close()
+
+	src.store.set("/dav/calendars/cal/one.ics", mergeICS("UID:one@example.com\r\nDTSTAMP:20260101T000000Z\r\n"+
+		"DTSTART:20260105T100000Z\r\nSUMMARY:One\r\n"))
+
+	f := newSelfLoopFixture(t)
+	source := f.createSource(t, "Fan-out", srcSrv.URL+"/dav/", primarySrv.URL+"/dav/calendars/cal/")
+	source.SyncDirection = db.SyncDirectionOneWay
+	if err := f.database.CreateDestination(&db.Destination{
+		SourceID:     source.ID,
+		Name:         "Extra",
+		DestURL:      extraSrv.URL + "/dav/calendars/cal/",
+		DestUsername: "alice",
+		DestPassword: f.encrypt("extra-pass"),
+		Enabled:      true,
+	}); err != nil {
+		t.Fatalf("CreateDestination: %v", err)
+	}
+
+	for cycle := 1; cycle <= 2; cycle++ {
+		if cycle == 2 {
+			// The source changes between cycles and says so.
+			src.store.set("/dav/calendars/cal/two.ics", mergeICS("UID:two@example.com\r\nDTSTAMP:20260101T000000Z\r\n"+
+				"DTSTART:20260106T100000Z\r\nSUMMARY:Two\r\n"))
+			if ctag {
+				src.ctag = "ctag-2"
+			} else {
+				src.lastModified = "Tue, 06 Jan 2026 10:00:00 GMT"
+			}
+		}
+		result := f.engine.SyncSource(context.Background(), source)
+		if len(result.Errors) > 0 || len(result.Warnings) > 0 {
+			t.Fatalf("cycle %d: sync failed: %v %v", cycle, result.Errors, result.Warnings)
+		}
+	}
+
+	for name, dest := range map[string]*discoveryStore{"primary": primary, "additional": extra} {
+		for _, file := range []string{"one.ics", "two.ics"} {
+			if _, ok := dest.store.data["/dav/calendars/cal/"+file]; !ok {
+				t.Errorf("%s destination is missing %s", name, file)
+			}
+		}
+	}
+}
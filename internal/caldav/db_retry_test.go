@@ -0,0 +1,103 @@
+package caldav
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errDBLocked = errors.New("database is locked (5) (SQLITE_BUSY)")
+
+// TestRetryDBOperation_HonorsCustomRetryCount verifies the helper makes
+// exactly maxRetries attempts on a persistently locked database rather
+// than the old hardcoded 5.
+func TestRetryDBOperation_HonorsCustomRetryCount(t *testing.T) {
+	for _, maxRetries := range []int{1, 3, 8} {
+		attempts := 0
+		err := retryDBOperation(func() error {
+			attempts++
+			return errDBLocked
+		}, maxRetries, time.Microsecond)
+		if !errors.Is(err, errDBLocked) {
+			t.Errorf("maxRetries=%d: expected lock error, got %v", maxRetries, err)
+		}
+		if attempts != maxRetries {
+			t.Errorf("maxRetries=%d: expected %d attempts, got %d", maxRetries, maxRetries, attempts)
+		}
+	}
+}
+
+// TestRetryDBOperation_SucceedsAfterTransientLock verifies a lock that
+// clears mid-way returns nil without using the remaining attempts.
+func TestRetryDBOperation_SucceedsAfterTransientLock(t *testing.T) {
+	attempts := 0
+	err := retryDBOperation(func() error {
+		attempts++
+		if attempts < 3 {
+			return errDBLocked
+		}
+		return nil
+	}, 10, time.Microsecond)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+// TestRetryDBOperation_NonRetryableErrorReturnsImmediately verifies
+// non-lock errors are not retried.
+func TestRetryDBOperation_NonRetryableErrorReturnsImmediately(t *testing.T) {
+	wantErr := errors.New("constraint failed")
+	attempts := 0
+	err := retryDBOperation(func() error {
+		attempts++
+		return wantErr
+	}, 5, time.Microsecond)
+	if !errors.Is(err, wantErr) {
+		t.Errorf("expected %v, got %v", wantErr, err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", attempts)
+	}
+}
+
+// TestRetryDBOperation_UsesBaseBackoff verifies the sleep between
+// attempts scales with the configured base backoff.
+func TestRetryDBOperation_UsesBaseBackoff(t *testing.T) {
+	start := time.Now()
+	_ = retryDBOperation(func() error { return errDBLocked }, 3, 20*time.Millisecond)
+	// Two sleeps before the final attempt: 20ms + 40ms.
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("expected at least 60ms of backoff, got %v", elapsed)
+	}
+}
+
+// TestSyncEngine_RetryDBUsesConfiguredSettings verifies SetDBLockRetry
+// is honored by the engine and that zero values fall back to defaults.
+func TestSyncEngine_RetryDBUsesConfiguredSettings(t *testing.T) {
+	se := &SyncEngine{}
+	se.SetDBLockRetry(2, time.Microsecond)
+	attempts := 0
+	_ = se.retryDB(func() error {
+		attempts++
+		return errDBLocked
+	})
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts with custom settings, got %d", attempts)
+	}
+
+	se.SetDBLockRetry(0, 0)
+	attempts = 0
+	_ = se.retryDB(func() error {
+		attempts++
+		if attempts < defaultDBLockMaxRetries {
+			return errDBLocked
+		}
+		return nil
+	})
+	if attempts != defaultDBLockMaxRetries {
+		t.Errorf("expected default %d attempts, got %d", defaultDBLockMaxRetries, attempts)
+	}
+}
@@ -0,0 +1,68 @@
+package caldav
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// SetDeadLetterThreshold dead-letters an event once its destination
+// write has failed on after syncs in a row: it is left out of syncs
+// until it is retried through the API. 0 never dead-letters. Wired
+// from SYNC_DEAD_LETTER_AFTER; call before the scheduler starts.
+func (se *SyncEngine) SetDeadLetterThreshold(after int) {
+	se.deadLetterAfter = after
+}
+
+// loadFailedEvents returns the events of calendarHref whose writes
+// failed on earlier syncs, by UID. A lookup error is logged and treats
+// every event as healthy, so a broken table can't stall syncs.
+func (se *SyncEngine) loadFailedEvents(source *db.Source, calendarHref string) map[string]*db.FailedEvent {
+	failed, err := se.db.GetFailedEvents(source.ID, calendarHref)
+	if err != nil {
+		log.Printf("Failed to get failed events for source %s: %v", source.ID, err)
+		return map[string]*db.FailedEvent{}
+	}
+	return failed
+}
+
+// isDeadLettered reports whether uid is dead-lettered in failed, and
+// if so counts it as skipped in result.
+func isDeadLettered(failed map[string]*db.FailedEvent, uid string, result *SyncResult) bool {
+	f := failed[uid]
+	if f == nil || !f.DeadLettered {
+		return false
+	}
+	result.Skipped++
+	return true
+}
+
+// recordEventFailure counts a failed destination write of uid and
+// warns when it is the one that dead-letters the event.
+func (se *SyncEngine) recordEventFailure(source *db.Source, calendarHref, uid string, writeErr error,
+	failed map[string]*db.FailedEvent, result *SyncResult) {
+	f, err := se.db.RecordEventFailure(source.ID, calendarHref, uid, writeErr.Error(), se.deadLetterAfter)
+	if err != nil {
+		log.Printf("Failed to record failure of event %s: %v", uid, err)
+		return
+	}
+	failed[uid] = f
+	if f.DeadLettered {
+		msg := fmt.Sprintf("Event %s failed to sync %d times in a row and was dead-lettered; retry it from the failed events list", uid, f.FailureCount)
+		log.Printf("WARNING: %s", msg)
+		result.Warnings = append(result.Warnings, msg)
+	}
+}
+
+// clearEventFailure resets the failure count of uid after it was
+// written, when it had one.
+func (se *SyncEngine) clearEventFailure(source *db.Source, calendarHref, uid string, failed map[string]*db.FailedEvent) {
+	if failed[uid] == nil {
+		return
+	}
+	delete(failed, uid)
+	if err := se.db.ClearEventFailure(source.ID, calendarHref, uid); err != nil {
+		log.Printf("Failed to clear failure of event %s: %v", uid, err)
+	}
+}
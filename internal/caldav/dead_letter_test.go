@@ -0,0 +1,136 @@
+package caldav
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// rejectingStore is a calendarStore whose PUTs to one path are
+// forbidden while reject is set.
+type rejectingStore struct {
+	*calendarStore
+	path string
+
+	mu       sync.Mutex
+	reject   bool
+	attempts int
+}
+
+func (s *rejectingStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPut && r.URL.Path == s.path {
+		s.mu.Lock()
+		s.attempts++
+		reject := s.reject
+		s.mu.Unlock()
+		if reject {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+	}
+	s.calendarStore.ServeHTTP(w, r)
+}
+
+func TestSyncEventsToDestination_DeadLetter(t *testing.T) {
+	destStore := &rejectingStore{calendarStore: newCalendarStore(), path: "/dest/broken@example.com.ics", reject: true}
+	destSrv := httptest.NewServer(destStore)
+	defer destSrv.Close()
+
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	user, err := database.GetOrCreateUser("deadletter@example.com", "Dead Letter")
+	if err != nil {
+		t.Fatalf("GetOrCreateUser: %v", err)
+	}
+	source := &db.Source{
+		UserID:           user.ID,
+		Name:             "Work",
+		SourceType:       db.SourceTypeCustom,
+		SourceURL:        "https://source.example.com/cal/",
+		SourceUsername:   "user",
+		SourcePassword:   "encrypted",
+		DestURL:          destSrv.URL + "/dest/",
+		DestUsername:     "dest",
+		DestPassword:     "encrypted",
+		SyncInterval:     3600,
+		SyncDirection:    db.SyncDirectionOneWay,
+		ConflictStrategy: db.ConflictSourceWins,
+		Enabled:          true,
+	}
+	if err := database.CreateSource(source); err != nil {
+		t.Fatalf("CreateSource: %v", err)
+	}
+	destClient, err := NewClient(destSrv.URL+"/dest/", "dest", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	se := NewSyncEngine(database, nil)
+	se.SetDeadLetterThreshold(2)
+	runSync := func() *SyncResult {
+		t.Helper()
+		sourceEvents := []Event{
+			{Path: "/cal/broken.ics", ETag: `"1"`, UID: "broken@example.com", Data: statusICS("broken@example.com", "")},
+			{Path: "/cal/fine.ics", ETag: `"1"`, UID: "fine@example.com", Data: statusICS("fine@example.com", "")},
+		}
+		return se.syncEventsToDestination(context.Background(), source, nil, destClient, sourceEvents,
+			Calendar{Path: "/cal/", Name: "Work"}, 1, db.SyncDirectionOneWay)
+	}
+	deadLettered := func(result *SyncResult) bool {
+		for _, w := range result.Warnings {
+			if strings.Contains(w, "dead-lettered") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if result := runSync(); deadLettered(result) {
+		t.Errorf("expected no dead letter after one failure, got %v", result.Warnings)
+	}
+	if result := runSync(); !deadLettered(result) {
+		t.Errorf("expected the second failure to dead-letter the event, got %v", result.Warnings)
+	}
+	dead, err := database.GetDeadLetteredEvents(source.ID)
+	if err != nil {
+		t.Fatalf("GetDeadLetteredEvents: %v", err)
+	}
+	if len(dead) != 1 || dead[0].EventUID != "broken@example.com" || dead[0].FailureCount != 2 {
+		t.Fatalf("expected broken@example.com dead-lettered after 2 failures, got %+v", dead)
+	}
+
+	result := runSync()
+	if destStore.attempts != 2 {
+		t.Errorf("expected the dead-lettered event not to be written again, got %d attempts", destStore.attempts)
+	}
+	if result.Skipped != 1 || len(result.Warnings) != 0 {
+		t.Errorf("expected the event skipped quietly, got %d skipped, warnings %v", result.Skipped, result.Warnings)
+	}
+
+	// Retried once the destination accepts it: written, and forgotten.
+	destStore.mu.Lock()
+	destStore.reject = false
+	destStore.mu.Unlock()
+	if _, err := database.RetryDeadLetteredEvents(source.ID, "", ""); err != nil {
+		t.Fatalf("RetryDeadLetteredEvents: %v", err)
+	}
+	if result := runSync(); result.Created != 1 {
+		t.Errorf("expected the retried event created, got %d (warnings %v)", result.Created, result.Warnings)
+	}
+	failed, err := database.GetFailedEvents(source.ID, "/cal/")
+	if err != nil {
+		t.Fatalf("GetFailedEvents: %v", err)
+	}
+	if len(failed) != 0 {
+		t.Errorf("expected no failures tracked after the event synced, got %+v", failed)
+	}
+}
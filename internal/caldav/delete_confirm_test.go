@@ -0,0 +1,215 @@
+package caldav
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func TestConfirmDeletions(t *testing.T) {
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	user, err := database.GetOrCreateUser("confirm@example.com", "Confirm")
+	if err != nil {
+		t.Fatalf("GetOrCreateUser: %v", err)
+	}
+	source := &db.Source{
+		UserID:           user.ID,
+		Name:             "Flapping",
+		SourceType:       db.SourceTypeCustom,
+		SourceURL:        "https://source.example.com/cal/",
+		SourceUsername:   "user",
+		SourcePassword:   "encrypted",
+		DestURL:          "https://dest.example.com/cal/",
+		DestUsername:     "dest",
+		DestPassword:     "encrypted",
+		SyncInterval:     3600,
+		SyncDirection:    db.SyncDirectionOneWay,
+		ConflictStrategy: db.ConflictSourceWins,
+		Enabled:          true,
+	}
+	if err := database.CreateSource(source); err != nil {
+		t.Fatalf("CreateSource: %v", err)
+	}
+
+	t.Run("disabled passes keys through", func(t *testing.T) {
+		se := NewSyncEngine(database, nil)
+		confirmed, pending, err := se.confirmDeletions(source.ID, "/off/", source.DestURL, db.DeletionTargetDest, []string{"x"})
+		if err != nil || len(confirmed) != 1 || len(pending) != 0 {
+			t.Errorf("got confirmed=%v pending=%v err=%v, want [x] [] nil", confirmed, pending, err)
+		}
+	})
+
+	t.Run("deletes only after N consecutive misses", func(t *testing.T) {
+		se := NewSyncEngine(database, nil)
+		se.SetDeleteConfirmations(3)
+
+		for cycle := 1; cycle <= 2; cycle++ {
+			confirmed, pending, err := se.confirmDeletions(source.ID, "/cal/", source.DestURL, db.DeletionTargetDest, []string{"gone"})
+			if err != nil {
+				t.Fatalf("cycle %d: %v", cycle, err)
+			}
+			if len(confirmed) != 0 || len(pending) != 1 {
+				t.Fatalf("cycle %d: confirmed=%v pending=%v, want deletion deferred", cycle, confirmed, pending)
+			}
+		}
+		confirmed, _, err := se.confirmDeletions(source.ID, "/cal/", source.DestURL, db.DeletionTargetDest, []string{"gone"})
+		if err != nil {
+			t.Fatalf("cycle 3: %v", err)
+		}
+		if len(confirmed) != 1 || confirmed[0] != "gone" {
+			t.Errorf("cycle 3: confirmed=%v, want [gone]", confirmed)
+		}
+	})
+
+	t.Run("destinations keep their own counts", func(t *testing.T) {
+		se := NewSyncEngine(database, nil)
+		se.SetDeleteConfirmations(2)
+
+		// The primary and an additional destination see different
+		// orphans in the same calendar each cycle.
+		const extraURL = "https://extra.example.com/cal/"
+		for cycle := 1; cycle <= 2; cycle++ {
+			primary, _, err := se.confirmDeletions(source.ID, "/multi/", source.DestURL, db.DeletionTargetDest, []string{"primary-orphan"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			extra, _, err := se.confirmDeletions(source.ID, "/multi/", extraURL, db.DeletionTargetDest, []string{"extra-orphan"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cycle == 1 && (len(primary) != 0 || len(extra) != 0) {
+				t.Errorf("cycle 1: confirmed %v and %v, want both deferred", primary, extra)
+			}
+			if cycle == 2 && (len(primary) != 1 || len(extra) != 1) {
+				t.Errorf("cycle 2: confirmed %v and %v, want one deletion on each destination", primary, extra)
+			}
+		}
+	})
+
+	t.Run("reappearing event resets the count", func(t *testing.T) {
+		se := NewSyncEngine(database, nil)
+		se.SetDeleteConfirmations(2)
+
+		if _, _, err := se.confirmDeletions(source.ID, "/flap/", source.DestURL, db.DeletionTargetDest, []string{"flap"}); err != nil {
+			t.Fatal(err)
+		}
+		// Event is back this cycle: nothing planned for deletion.
+		if _, _, err := se.confirmDeletions(source.ID, "/flap/", source.DestURL, db.DeletionTargetDest, nil); err != nil {
+			t.Fatal(err)
+		}
+		confirmed, pending, err := se.confirmDeletions(source.ID, "/flap/", source.DestURL, db.DeletionTargetDest, []string{"flap"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(confirmed) != 0 || len(pending) != 1 {
+			t.Errorf("confirmed=%v pending=%v, want flap still pending after reset", confirmed, pending)
+		}
+	})
+}
+
+func TestSyncCalendar_DeleteConfirmations(t *testing.T) {
+	for _, withCTag := range []bool{false, true} {
+		name := "without CTag"
+		if withCTag {
+			name = "with CTag"
+		}
+		t.Run(name, func(t *testing.T) {
+			srcStore, destStore := newCalendarStore(), newCalendarStore()
+			for _, uid := range []string{"a", "b", "c"} {
+				srcStore.set("/cal/"+uid+".ics", mergeICS("UID:"+uid+"@example.com\r\nDTSTAMP:20260101T000000Z\r\n"+
+					"DTSTART:20260105T100000Z\r\nSUMMARY:"+uid+"\r\n"))
+			}
+			srcMock := &collectionStateMock{store: srcStore}
+			if withCTag {
+				srcMock.setState("ctag-1", "")
+			}
+			var deletes atomic.Int32
+			srcSrv := httptest.NewServer(srcMock)
+			defer srcSrv.Close()
+			destSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodDelete {
+					deletes.Add(1)
+				}
+				destStore.ServeHTTP(w, r)
+			}))
+			defer destSrv.Close()
+
+			database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+			if err != nil {
+				t.Fatalf("db.New: %v", err)
+			}
+			defer database.Close()
+			user, err := database.GetOrCreateUser("confirm@example.com", "Confirm")
+			if err != nil {
+				t.Fatalf("GetOrCreateUser: %v", err)
+			}
+			source := &db.Source{
+				UserID:           user.ID,
+				Name:             "Confirmed",
+				SourceType:       db.SourceTypeCustom,
+				SourceURL:        srcSrv.URL + "/cal/",
+				SourceUsername:   "user",
+				SourcePassword:   "encrypted",
+				DestURL:          destSrv.URL + "/dest/",
+				DestUsername:     "dest",
+				DestPassword:     "encrypted",
+				SyncInterval:     3600,
+				SyncDirection:    db.SyncDirectionOneWay,
+				ConflictStrategy: db.ConflictSourceWins,
+				Enabled:          true,
+			}
+			if err := database.CreateSource(source); err != nil {
+				t.Fatalf("CreateSource: %v", err)
+			}
+			sourceClient, err := NewClient(srcSrv.URL+"/cal/", "user", "pass")
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			destClient, err := NewClient(destSrv.URL+"/dest/", "dest", "pass")
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			se := NewSyncEngine(database, nil)
+			se.SetDeleteConfirmations(3)
+			calendar := Calendar{Path: "/cal/", Name: "Cal"}
+
+			if result := se.syncCalendar(context.Background(), source, sourceClient, destClient, calendar, 1); len(result.Errors) > 0 {
+				t.Fatalf("initial sync failed: %v", result.Errors)
+			}
+
+			// c goes away on the source, which moves its CTag once;
+			// nothing changes on the source after that.
+			srcStore.mu.Lock()
+			delete(srcStore.data, "/cal/c.ics")
+			srcStore.mu.Unlock()
+			if withCTag {
+				srcMock.setState("ctag-2", "")
+			}
+			for cycle := 1; cycle <= 4; cycle++ {
+				result := se.syncCalendar(context.Background(), source, sourceClient, destClient, calendar, 1)
+				if len(result.Errors) > 0 {
+					t.Fatalf("cycle %d: sync failed: %v", cycle, result.Errors)
+				}
+				want := int32(0)
+				if cycle >= 3 {
+					want = 1
+				}
+				if got := deletes.Load(); got != want {
+					t.Fatalf("after %d cycle(s) with c missing: %d DELETE(s) sent, want %d", cycle, got, want)
+				}
+			}
+			if _, ok := destStore.data["/dest/c.ics"]; ok {
+				t.Error("expected c to be deleted from the destination")
+			}
+		})
+	}
+}
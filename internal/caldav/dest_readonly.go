@@ -0,0 +1,82 @@
+package caldav
+
+import (
+	"fmt"
+	"log"
+	"sync/atomic"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// readOnlyDestSource returns the source a sync should run with. A
+// source with DestReadOnly set is always synced one way, whatever its
+// configured direction or its calendars' own directions, so nothing
+// is ever written back through it.
+func readOnlyDestSource(source *db.Source) *db.Source {
+	if !source.DestReadOnly {
+		return source
+	}
+	oneWay := *source
+	changed := false
+	if writesBack(source.SyncDirection) {
+		log.Printf("Source %s has a read-only destination: syncing one way instead of %s", source.Name, source.SyncDirection)
+		oneWay.SyncDirection = db.SyncDirectionOneWay
+		changed = true
+	}
+	// The selection is copied before it is changed: the caller's
+	// source keeps its configured directions.
+	var calendars []db.CalendarConfig
+	for i, cal := range source.SelectedCalendars {
+		if !writesBack(cal.SyncDirection) {
+			continue
+		}
+		if calendars == nil {
+			calendars = append([]db.CalendarConfig(nil), source.SelectedCalendars...)
+		}
+		log.Printf("Source %s has a read-only destination: syncing calendar %s one way instead of %s", source.Name, cal.Path, cal.SyncDirection)
+		calendars[i].SyncDirection = db.SyncDirectionOneWay
+	}
+	if calendars != nil {
+		oneWay.SelectedCalendars = calendars
+		changed = true
+	}
+	if !changed {
+		return source
+	}
+	return &oneWay
+}
+
+// writesBack reports whether direction syncs destination changes back
+// to the source. Empty means a calendar follows its source.
+func writesBack(direction db.SyncDirection) bool {
+	return direction != "" && direction != db.SyncDirectionOneWay && direction != db.SyncDirectionOneWayCreateOnly
+}
+
+// destGuard hands out the destination clients of one sync. For a
+// source with DestReadOnly set they refuse every PUT and DELETE, and
+// the guard counts the refused writes so they can be reported once.
+type destGuard struct {
+	readOnly bool
+	blocked  atomic.Int64
+}
+
+func newDestGuard(source *db.Source) *destGuard {
+	return &destGuard{readOnly: source.DestReadOnly}
+}
+
+// client returns c, or a read-only view of it when the destination is
+// read-only.
+func (g *destGuard) client(c *Client) *Client {
+	if !g.readOnly || c == nil {
+		return c
+	}
+	return c.readOnlyView(&g.blocked)
+}
+
+// report adds a single warning to result when writes were refused.
+func (g *destGuard) report(result *SyncResult) {
+	if n := g.blocked.Load(); n > 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf(
+			"Destination is read-only: %d write(s) were not applied", n))
+	}
+}
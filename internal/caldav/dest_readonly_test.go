@@ -0,0 +1,174 @@
+package caldav
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// discoveryStore serves a calendarStore as the single calendar
+// /dav/calendars/cal/ behind the usual principal → home set discovery,
+// counting every PUT and DELETE it receives. The calendar reports ctag
+// and lastModified as its getctag and getlastmodified when they are
+// set.
+type discoveryStore struct {
+	store        *calendarStore
+	mutations    atomic.Int32
+	ctag         string
+	lastModified string
+}
+
+func (d *discoveryStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPut || r.Method == http.MethodDelete {
+		d.mutations.Add(1)
+	}
+	if r.Method != "PROPFIND" {
+		d.store.ServeHTTP(w, r)
+		return
+	}
+	multistatus := func(body string) {
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/">` + body + `</d:multistatus>`))
+	}
+	var state string
+	if d.ctag != "" {
+		state += "<cs:getctag>" + d.ctag + "</cs:getctag>"
+	}
+	if d.lastModified != "" {
+		state += "<d:getlastmodified>" + d.lastModified + "</d:getlastmodified>"
+	}
+	calendar := `<d:response><d:href>/dav/calendars/cal/</d:href><d:propstat><d:prop>` +
+		`<d:resourcetype><d:collection/><cal:calendar/></d:resourcetype><d:displayname>Cal</d:displayname>` + state +
+		`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`
+	switch strings.TrimSuffix(r.URL.Path, "/") {
+	case "/dav":
+		multistatus(`<d:response><d:href>/dav/</d:href><d:propstat><d:prop>` +
+			`<d:current-user-principal><d:href>/dav/principals/alice/</d:href></d:current-user-principal>` +
+			`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`)
+	case "/dav/principals/alice":
+		multistatus(`<d:response><d:href>/dav/principals/alice/</d:href><d:propstat><d:prop>` +
+			`<cal:calendar-home-set><d:href>/dav/calendars/</d:href></cal:calendar-home-set>` +
+			`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`)
+	case "/dav/calendars":
+		multistatus(`<d:response><d:href>/dav/calendars/</d:href><d:propstat><d:prop>` +
+			`<d:resourcetype><d:collection/></d:resourcetype>` +
+			`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>` + calendar)
+	case "/dav/calendars/cal":
+		// The destination URL points here, so it answers principal
+		// discovery too.
+		multistatus(strings.Replace(calendar, "<d:displayname>",
+			`<d:current-user-principal><d:href>/dav/principals/alice/</d:href></d:current-user-principal><d:displayname>`, 1))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestSyncSource_DestReadOnly(t *testing.T) {
+	for _, readOnly := range []bool{true, false} {
+		name := "read-only destination is never written"
+		if !readOnly {
+			name = "writable destination is written"
+		}
+		t.Run(name, func(t *testing.T) {
+			src := &discoveryStore{store: newCalendarStore()}
+			dest := &discoveryStore{store: newCalendarStore()}
+			srcSrv := httptest.NewServer(src)
+			defer srcSrv.Close()
+			destSrv := httptest.NewServer(dest)
+			defer destSrv.Close()
+
+			// A new source event to create, a destination event the
+			// last sync wrote and the source has since dropped, and a
+			// destination-only event a two-way sync would copy back.
+			src.store.set("/dav/calendars/cal/new.ics", mergeICS("UID:new@example.com\r\nDTSTAMP:20260101T000000Z\r\n"+
+				"DTSTART:20260105T100000Z\r\nSUMMARY:New\r\n"))
+			dest.store.set("/dav/calendars/cal/gone.ics", mergeICS("UID:gone@example.com\r\nDTSTAMP:20260101T000000Z\r\n"+
+				"DTSTART:20260106T100000Z\r\nSUMMARY:Gone\r\n"))
+			dest.store.set("/dav/calendars/cal/local.ics", mergeICS("UID:local@example.com\r\nDTSTAMP:20260101T000000Z\r\n"+
+				"DTSTART:20260107T100000Z\r\nSUMMARY:Local\r\n"))
+
+			f := newSelfLoopFixture(t)
+			source := f.createSource(t, "Guarded", srcSrv.URL+"/dav/", destSrv.URL+"/dav/calendars/cal/")
+			source.DestReadOnly = readOnly
+			if err := f.database.UpsertSyncedEvent(&db.SyncedEvent{
+				SourceID:     source.ID,
+				CalendarHref: "/dav/calendars/cal/",
+				EventUID:     "gone@example.com",
+				SourceETag:   "old",
+			}); err != nil {
+				t.Fatalf("UpsertSyncedEvent: %v", err)
+			}
+
+			result := f.engine.SyncSource(context.Background(), source)
+			if len(result.Errors) > 0 {
+				t.Fatalf("sync failed: %v", result.Errors)
+			}
+
+			if readOnly {
+				if n := dest.mutations.Load(); n != 0 {
+					t.Errorf("expected no writes to the destination, got %d", n)
+				}
+				if n := src.mutations.Load(); n != 0 {
+					t.Errorf("expected the sync to run one way, got %d writes to the source", n)
+				}
+				found := false
+				for _, w := range result.Warnings {
+					if strings.HasPrefix(w, "Destination is read-only: 1 write(s)") {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("expected one read-only warning for the refused create, got %v", result.Warnings)
+				}
+				if result.Created != 0 || result.Deleted != 0 {
+					t.Errorf("expected nothing created or deleted, got %d created, %d deleted", result.Created, result.Deleted)
+				}
+				if source.SyncDirection != db.SyncDirectionTwoWay {
+					t.Errorf("the configured direction should be left alone, got %s", source.SyncDirection)
+				}
+			} else if dest.mutations.Load() == 0 {
+				t.Error("expected the writable destination to receive writes")
+			}
+		})
+	}
+}
+
+func TestSyncSource_DestReadOnlyCalendarOverride(t *testing.T) {
+	src := &discoveryStore{store: newCalendarStore()}
+	dest := &discoveryStore{store: newCalendarStore()}
+	srcSrv := httptest.NewServer(src)
+	defer srcSrv.Close()
+	destSrv := httptest.NewServer(dest)
+	defer destSrv.Close()
+
+	// A destination-only event the calendar's two-way override would
+	// copy back to the source.
+	dest.store.set("/dav/calendars/cal/local.ics", mergeICS("UID:local@example.com\r\nDTSTAMP:20260101T000000Z\r\n"+
+		"DTSTART:20260107T100000Z\r\nSUMMARY:Local\r\n"))
+
+	f := newSelfLoopFixture(t)
+	source := f.createSource(t, "Guarded", srcSrv.URL+"/dav/", destSrv.URL+"/dav/calendars/cal/")
+	source.DestReadOnly = true
+	source.SyncDirection = db.SyncDirectionOneWay
+	source.SelectedCalendars = []db.CalendarConfig{{Path: "/dav/calendars/cal/", SyncDirection: db.SyncDirectionTwoWay}}
+
+	result := f.engine.SyncSource(context.Background(), source)
+	if len(result.Errors) > 0 {
+		t.Fatalf("sync failed: %v", result.Errors)
+	}
+	if n := src.mutations.Load(); n != 0 {
+		t.Errorf("expected the calendar to sync one way, got %d writes to the source", n)
+	}
+	if n := dest.mutations.Load(); n != 0 {
+		t.Errorf("expected no writes to the destination, got %d", n)
+	}
+	if got := source.SelectedCalendars[0].SyncDirection; got != db.SyncDirectionTwoWay {
+		t.Errorf("the calendar's configured direction should be left alone, got %s", got)
+	}
+}
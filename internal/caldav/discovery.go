@@ -0,0 +1,135 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/emersion/go-webdav"
+	"github.com/emersion/go-webdav/caldav"
+)
+
+// wellKnownCalDAVPath is the RFC 6764 §5 bootstrap location. Nextcloud
+// and ownCloud redirect it to their DAV root (/remote.php/dav/).
+const wellKnownCalDAVPath = "/.well-known/caldav"
+
+// isDiscoveryFallbackError reports whether a current-user-principal
+// PROPFIND failed in a way that suggests the base URL is the wrong
+// place to ask rather than that the server is unreachable or the
+// credentials are bad. Nextcloud answers 405 Method Not Allowed or
+// 501 Not Implemented when the URL points at a calendar object or a
+// non-DAV path, and a bare calendar collection often just lacks the
+// property (go-webdav reports "404 Not Found: missing property").
+// 401/403 are deliberately excluded: retrying elsewhere with the same
+// credentials can't help.
+func isDiscoveryFallbackError(err error) bool {
+	if err == nil {
+		return false
+	}
+	errStr := err.Error()
+	for _, marker := range []string{
+		"404", "Not Found",
+		"405", "Method Not Allowed",
+		"501", "Not Implemented",
+		"missing property",
+		"PROPFIND with Depth: 0 returned",
+	} {
+		if strings.Contains(errStr, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// findCurrentUserPrincipal looks up the principal at the base URL and,
+// when that fails with a discovery error, retries against the server's
+// well-known CalDAV location and then its root. Users commonly paste a
+// calendar URL copied from the Nextcloud UI rather than the DAV root;
+// this lets TestConnection and FindCalendars succeed for them anyway.
+func (c *Client) findCurrentUserPrincipal(ctx context.Context) (string, error) {
+	principal, err := c.caldavClient.FindCurrentUserPrincipal(ctx)
+	if err == nil {
+		return principal, nil
+	}
+	if !isDiscoveryFallbackError(err) {
+		return "", err
+	}
+
+	log.Printf("Principal discovery failed at %s (%v), trying well-known fallback", c.baseURL, err)
+	for _, root := range c.discoveryRoots(ctx) {
+		rootClient, clientErr := caldav.NewClient(
+			webdav.HTTPClientWithBasicAuth(c.httpClient, c.username, c.password),
+			root,
+		)
+		if clientErr != nil {
+			continue
+		}
+		if p, rootErr := rootClient.FindCurrentUserPrincipal(ctx); rootErr == nil {
+			log.Printf("Principal discovered via %s", root)
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("principal discovery not supported at this URL and well-known fallback failed: %w", err)
+}
+
+// discoveryRoots returns the URLs to retry principal discovery at, in
+// order: the target of the /.well-known/caldav redirect (if the server
+// has one) and the server root.
+func (c *Client) discoveryRoots(ctx context.Context) []string {
+	origin, _ := splitBaseURL(c.baseURL)
+	var roots []string
+	if target := c.resolveWellKnown(ctx, origin); target != "" {
+		roots = append(roots, target)
+	}
+	return append(roots, origin+"/")
+}
+
+// resolveWellKnown follows the /.well-known/caldav redirect by hand and
+// returns the absolute target URL, or "" if there is none or it leaves
+// the base URL's scheme and host. The redirect can't be left to
+// net/http: it rewrites a PROPFIND into a GET on 301 and 302, which is
+// what Nextcloud sends.
+func (c *Client) resolveWellKnown(ctx context.Context, origin string) string {
+	req, err := http.NewRequestWithContext(ctx, "PROPFIND", origin+wellKnownCalDAVPath, nil)
+	if err != nil {
+		return ""
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Depth", "0")
+
+	noFollow := *c.httpClient
+	noFollow.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	resp, err := noFollow.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	switch resp.StatusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		// Location is resolved against the request URL. Discovery
+		// retries with the user's credentials at the target, so a
+		// redirect off the base URL's origin is ignored rather than
+		// handed the password.
+		loc, err := resp.Location()
+		if err != nil {
+			return ""
+		}
+		if loc.Scheme != req.URL.Scheme || loc.Host != req.URL.Host {
+			log.Printf("Ignoring %s redirect to another origin: %s://%s", wellKnownCalDAVPath, loc.Scheme, loc.Host)
+			return ""
+		}
+		return loc.String()
+	case http.StatusMultiStatus:
+		// Served in place rather than redirected.
+		return origin + wellKnownCalDAVPath
+	}
+	return ""
+}
@@ -0,0 +1,204 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestIsDiscoveryFallbackError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"method not allowed", errors.New("405 Method Not Allowed"), true},
+		{"not implemented", errors.New("501 Not Implemented"), true},
+		{"missing property", errors.New("webdav: failed to decode prop: 404 Not Found: missing property"), true},
+		{"multiple responses", errors.New("PROPFIND with Depth: 0 returned 3 responses"), true},
+		{"unauthorized", errors.New("401 Unauthorized"), false},
+		{"forbidden", errors.New("403 Forbidden"), false},
+		{"network", errors.New("dial tcp: connection refused"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDiscoveryFallbackError(tt.err); got != tt.want {
+				t.Errorf("isDiscoveryFallbackError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// nextcloudMock emulates the parts of a Nextcloud DAV server that
+// principal discovery touches: a calendar URL that refuses the
+// principal PROPFIND with 405, a /.well-known/caldav 301 to the DAV
+// root, and the principal → home set → calendars chain below it.
+type nextcloudMock struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (m *nextcloudMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	m.requests = append(m.requests, r.Method+" "+r.URL.Path)
+	m.mu.Unlock()
+
+	if r.Method != "PROPFIND" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	multistatus := func(body string) {
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">` + body + `</d:multistatus>`))
+	}
+
+	// go-webdav drops the trailing slash when resolving its endpoint,
+	// so match collection paths without it.
+	switch strings.TrimSuffix(r.URL.Path, "/") {
+	case "/.well-known/caldav":
+		http.Redirect(w, r, "/remote.php/dav/", http.StatusMovedPermanently)
+	case "/remote.php/dav":
+		multistatus(`<d:response><d:href>/remote.php/dav/</d:href><d:propstat><d:prop>` +
+			`<d:current-user-principal><d:href>/remote.php/dav/principals/users/alice/</d:href></d:current-user-principal>` +
+			`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`)
+	case "/remote.php/dav/principals/users/alice":
+		multistatus(`<d:response><d:href>/remote.php/dav/principals/users/alice/</d:href><d:propstat><d:prop>` +
+			`<cal:calendar-home-set><d:href>/remote.php/dav/calendars/alice/</d:href></cal:calendar-home-set>` +
+			`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`)
+	case "/remote.php/dav/calendars/alice":
+		multistatus(`<d:response><d:href>/remote.php/dav/calendars/alice/</d:href><d:propstat><d:prop>` +
+			`<d:resourcetype><d:collection/></d:resourcetype>` +
+			`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>` +
+			`<d:response><d:href>/remote.php/dav/calendars/alice/personal/</d:href><d:propstat><d:prop>` +
+			`<d:resourcetype><d:collection/><cal:calendar/></d:resourcetype><d:displayname>Personal</d:displayname>` +
+			`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`)
+	default:
+		// The calendar URL the user pasted: Nextcloud rejects the
+		// principal PROPFIND here.
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (m *nextcloudMock) requested(entry string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.requests {
+		if r == entry {
+			return true
+		}
+	}
+	return false
+}
+
+func TestFindCalendars_WellKnownFallback(t *testing.T) {
+	mock := &nextcloudMock{}
+	srv := httptest.NewServer(mock)
+	defer srv.Close()
+
+	client, err := NewClient(srv.URL+"/remote.php/dav/calendars/alice/personal/event.ics", "alice", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	cals, err := client.FindCalendars(context.Background())
+	if err != nil {
+		t.Fatalf("FindCalendars should recover via well-known fallback, got: %v", err)
+	}
+	if len(cals) != 1 || cals[0].Path != "/remote.php/dav/calendars/alice/personal/" {
+		t.Errorf("unexpected calendars: %+v", cals)
+	}
+	if !mock.requested("PROPFIND /.well-known/caldav") {
+		t.Error("expected a PROPFIND to /.well-known/caldav")
+	}
+
+	if err := client.TestConnection(context.Background()); err != nil {
+		t.Errorf("TestConnection should recover via well-known fallback, got: %v", err)
+	}
+}
+
+func TestFindCalendars_NoFallbackOnAuthFailure(t *testing.T) {
+	var wellKnownHit bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == wellKnownCalDAVPath {
+			wellKnownHit = true
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(srv.URL+"/remote.php/dav/", "alice", "wrong")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	_, err = client.FindCalendars(context.Background())
+	if !errors.Is(err, ErrConnectionFailed) {
+		t.Errorf("expected ErrConnectionFailed, got %v", err)
+	}
+	if wellKnownHit {
+		t.Error("401 must not trigger the well-known fallback")
+	}
+}
+
+func TestFindCalendars_FallbackExhausted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotImplemented)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(srv.URL+"/some/calendar/", "alice", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	_, err = client.FindCalendars(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "well-known fallback failed") {
+		t.Errorf("expected fallback-exhausted error, got %v", err)
+	}
+}
+
+func TestFindCalendars_WellKnownCrossOriginRedirectIgnored(t *testing.T) {
+	var mu sync.Mutex
+	var leaked []string
+	elsewhere := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		leaked = append(leaked, r.Header.Get("Authorization"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer elsewhere.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == wellKnownCalDAVPath {
+			http.Redirect(w, r, elsewhere.URL+"/remote.php/dav/", http.StatusMovedPermanently)
+			return
+		}
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(srv.URL+"/remote.php/dav/calendars/alice/personal/", "alice", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if roots := client.discoveryRoots(context.Background()); len(roots) != 1 || roots[0] != srv.URL+"/" {
+		t.Errorf("expected only the server root to be retried, got %v", roots)
+	}
+	if _, err := client.FindCalendars(context.Background()); err == nil {
+		t.Error("expected discovery to fail")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, auth := range leaked {
+		if auth != "" {
+			t.Errorf("cross-origin redirect target received Authorization %q", auth)
+		}
+	}
+}
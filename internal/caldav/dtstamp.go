@@ -0,0 +1,48 @@
+package caldav
+
+import (
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+)
+
+// dtstampComponents are the components RFC 5545 requires a DTSTAMP on,
+// and that the encoder therefore refuses without one.
+var dtstampComponents = map[string]bool{
+	ical.CompEvent:   true,
+	ical.CompToDo:    true,
+	ical.CompJournal: true,
+}
+
+// fillMissingDTStamps gives every VEVENT, VTODO and VJOURNAL in cal
+// that lacks a DTSTAMP one, so it can be encoded instead of dropped.
+// The stamp is the entry's LAST-MODIFIED when it has a valid one, which
+// keeps it stable across fetches, and now otherwise. It reports
+// whether anything was filled in.
+func fillMissingDTStamps(cal *ical.Calendar, now time.Time) bool {
+	filled := false
+	for _, child := range cal.Children {
+		if !dtstampComponents[child.Name] || child.Props.Get(ical.PropDateTimeStamp) != nil {
+			continue
+		}
+		stamp := now
+		if lastModified, err := child.Props.DateTime(ical.PropLastModified, time.UTC); err == nil && !lastModified.IsZero() {
+			stamp = lastModified
+		}
+		child.Props.SetDateTime(ical.PropDateTimeStamp, stamp.UTC())
+		filled = true
+	}
+	return filled
+}
+
+// fillObjectDTStamps applies fillMissingDTStamps to fetched calendar
+// objects.
+func fillObjectDTStamps(objects []caldav.CalendarObject) {
+	now := time.Now()
+	for _, obj := range objects {
+		if obj.Data != nil {
+			fillMissingDTStamps(obj.Data, now)
+		}
+	}
+}
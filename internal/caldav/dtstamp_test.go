@@ -0,0 +1,117 @@
+package caldav
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+// noDTStampEvent lacks the DTSTAMP RFC 5545 requires, which the
+// encoder refuses until one is filled in.
+const noDTStampEvent = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Test//Test//EN\r\nBEGIN:VEVENT\r\n" +
+	"UID:no-dtstamp@example.com\r\nDTSTART:20260115T140000Z\r\nSUMMARY:No stamp\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+func TestFillMissingDTStamps(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	t.Run("injects now and then encodes", func(t *testing.T) {
+		cal, err := parseICalendar(noDTStampEvent)
+		if err != nil {
+			t.Fatalf("parseICalendar: %v", err)
+		}
+		if !fillMissingDTStamps(cal, now) {
+			t.Fatal("expected a DTSTAMP to be filled in")
+		}
+		data, err := encodeCalendar(cal)
+		if err != nil {
+			t.Fatalf("encodeCalendar after fill: %v", err)
+		}
+		if !strings.Contains(data, "DTSTAMP:20260301T123000Z") {
+			t.Errorf("expected DTSTAMP set to now, got:\n%s", data)
+		}
+	})
+
+	t.Run("prefers LAST-MODIFIED", func(t *testing.T) {
+		data := strings.Replace(noDTStampEvent, "SUMMARY:", "LAST-MODIFIED:20260110T080000Z\r\nSUMMARY:", 1)
+		cal, err := parseICalendar(data)
+		if err != nil {
+			t.Fatalf("parseICalendar: %v", err)
+		}
+		fillMissingDTStamps(cal, now)
+		if got := cal.Children[0].Props.Get(ical.PropDateTimeStamp).Value; got != "20260110T080000Z" {
+			t.Errorf("expected DTSTAMP from LAST-MODIFIED, got %s", got)
+		}
+	})
+
+	t.Run("leaves an existing DTSTAMP alone", func(t *testing.T) {
+		data := strings.Replace(noDTStampEvent, "SUMMARY:", "DTSTAMP:20250101T000000Z\r\nSUMMARY:", 1)
+		cal, err := parseICalendar(data)
+		if err != nil {
+			t.Fatalf("parseICalendar: %v", err)
+		}
+		if fillMissingDTStamps(cal, now) {
+			t.Error("expected nothing to be filled in")
+		}
+		if got := cal.Children[0].Props.Get(ical.PropDateTimeStamp).Value; got != "20250101T000000Z" {
+			t.Errorf("expected the original DTSTAMP, got %s", got)
+		}
+	})
+
+	t.Run("skips components that don't take one", func(t *testing.T) {
+		data := strings.Replace(noDTStampEvent, "BEGIN:VEVENT",
+			"BEGIN:VTIMEZONE\r\nTZID:UTC\r\nBEGIN:STANDARD\r\nDTSTART:19700101T000000\r\n"+
+				"TZOFFSETFROM:+0000\r\nTZOFFSETTO:+0000\r\nEND:STANDARD\r\nEND:VTIMEZONE\r\nBEGIN:VEVENT", 1)
+		cal, err := parseICalendar(data)
+		if err != nil {
+			t.Fatalf("parseICalendar: %v", err)
+		}
+		fillMissingDTStamps(cal, now)
+		for _, comp := range cal.Children {
+			if comp.Name == ical.CompTimezone && comp.Props.Get(ical.PropDateTimeStamp) != nil {
+				t.Error("VTIMEZONE should not get a DTSTAMP")
+			}
+		}
+	})
+}
+
+func TestDTStamplessEventSyncs(t *testing.T) {
+	t.Run("fetch", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/calendar")
+			_, _ = w.Write([]byte(noDTStampEvent))
+		}))
+		defer srv.Close()
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		event, err := client.GetEvent(context.Background(), "/cal/no-dtstamp.ics")
+		if err != nil {
+			t.Fatalf("GetEvent: %v", err)
+		}
+		if event.UID != "no-dtstamp@example.com" || !strings.Contains(event.Data, "DTSTAMP:") {
+			t.Errorf("expected the event with a DTSTAMP filled in, got UID %q:\n%s", event.UID, event.Data)
+		}
+	})
+
+	t.Run("put", func(t *testing.T) {
+		srv, bodies := putRecorder(t)
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		if err := client.PutEvent(context.Background(), "/cal/", &Event{UID: "no-dtstamp@example.com", Data: noDTStampEvent}); err != nil {
+			t.Fatalf("PutEvent: %v", err)
+		}
+		if got := bodies(); len(got) != 1 || !strings.Contains(got[0], "DTSTAMP:") {
+			t.Errorf("expected one PUT carrying a DTSTAMP, got %q", got)
+		}
+	})
+}
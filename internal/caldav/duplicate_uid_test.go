@@ -0,0 +1,94 @@
+package caldav
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// TestSyncEventsToDestination_WarnsOnDuplicateSourceUID feeds two
+// source resources whose VEVENTs share a UID and checks the sync
+// reports both paths, while a master plus its detached override (same
+// UID, different RECURRENCE-ID) is not flagged.
+func TestSyncEventsToDestination_WarnsOnDuplicateSourceUID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut:
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == "REPORT" || (r.Method == "PROPFIND" && r.Header.Get("Depth") == "1"):
+			w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+			w.WriteHeader(http.StatusMultiStatus)
+			_, _ = w.Write([]byte(`<?xml version="1.0"?><d:multistatus xmlns:d="DAV:"></d:multistatus>`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	user, err := database.GetOrCreateUser("dupes@example.com", "Dupes")
+	if err != nil {
+		t.Fatalf("GetOrCreateUser: %v", err)
+	}
+	source := &db.Source{
+		UserID:           user.ID,
+		Name:             "Dupes",
+		SourceType:       db.SourceTypeCustom,
+		SourceURL:        "https://source.example.com/cal/",
+		SourceUsername:   "user",
+		SourcePassword:   "encrypted",
+		DestURL:          srv.URL + "/dest/",
+		DestUsername:     "dest",
+		DestPassword:     "encrypted",
+		SyncInterval:     3600,
+		SyncDirection:    db.SyncDirectionOneWay,
+		ConflictStrategy: db.ConflictSourceWins,
+		Enabled:          true,
+	}
+	if err := database.CreateSource(source); err != nil {
+		t.Fatalf("CreateSource: %v", err)
+	}
+	destClient, err := NewClient(srv.URL+"/dest/", "dest", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	vevent := func(uid, summary string) string {
+		return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VEVENT\r\n" +
+			"UID:" + uid + "\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\n" +
+			"SUMMARY:" + summary + "\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	}
+	sourceEvents := []Event{
+		{Path: "/cal/standup.ics", ETag: `"a"`, UID: "shared@example.com", Data: vevent("shared@example.com", "Standup")},
+		{Path: "/cal/standup-copy.ics", ETag: `"b"`, UID: "shared@example.com", Data: vevent("shared@example.com", "Standup copy")},
+		{Path: "/cal/weekly.ics", ETag: `"c"`, UID: "weekly@example.com", Data: recurringMasterICS},
+		{Path: "/cal/weekly-override.ics", ETag: `"d"`, UID: "weekly@example.com", RecurrenceID: "20260112T100000Z", Data: recurringOverrideICS},
+	}
+
+	se := NewSyncEngine(database, nil)
+	result := se.syncEventsToDestination(context.Background(), source, nil, destClient, sourceEvents, Calendar{Path: "/cal/", Name: "Cal"}, 1, db.SyncDirectionOneWay)
+
+	var dupWarnings []string
+	for _, w := range result.Warnings {
+		if strings.HasPrefix(w, "Duplicate UID") {
+			dupWarnings = append(dupWarnings, w)
+		}
+	}
+	if len(dupWarnings) != 1 {
+		t.Fatalf("expected exactly one duplicate UID warning, got %v", result.Warnings)
+	}
+	for _, want := range []string{"shared@example.com", "/cal/standup.ics", "/cal/standup-copy.ics"} {
+		if !strings.Contains(dupWarnings[0], want) {
+			t.Errorf("duplicate warning %q missing %q", dupWarnings[0], want)
+		}
+	}
+}
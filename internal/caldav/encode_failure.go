@@ -0,0 +1,81 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// EncodeFailurePolicy decides what PutEvent does with an event that
+// parses but that the iCalendar encoder then refuses, such as a VEVENT
+// with both DTEND and DURATION. The CalDAV library encodes before every
+// PUT, so without a policy such an event could only fail the write.
+type EncodeFailurePolicy string
+
+const (
+	// EncodeFailureSkip leaves the event unwritten and records it as
+	// malformed. It is the default.
+	EncodeFailureSkip EncodeFailurePolicy = "skip"
+	// EncodeFailureRaw writes the event's original data unchanged,
+	// for destinations that are more lenient than the encoder.
+	EncodeFailureRaw EncodeFailurePolicy = "raw"
+)
+
+// WithEncodeFailurePolicy sets how PutEvent handles events that can't
+// be re-encoded; see EncodeFailurePolicy. The zero value skips them.
+func WithEncodeFailurePolicy(policy EncodeFailurePolicy) ClientOption {
+	return func(c *Client) {
+		c.encodeFailurePolicy = policy
+	}
+}
+
+// putRawEvent PUTs data to path byte for byte, bypassing the encoder.
+// Only used under EncodeFailureRaw.
+func (c *Client) putRawEvent(ctx context.Context, path, data string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.buildURL(path), strings.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: failed to put event: %w", ErrConnectionFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: failed to put event: %w", ErrConnectionFailed, putStatusError(resp))
+	}
+	return nil
+}
+
+// SetEncodeFailurePolicy sets how events the encoder refuses are
+// written: skipped and recorded as malformed (the default) or PUT as
+// the original data. Wired from SYNC_ENCODE_FAILURE; call before the
+// scheduler starts.
+func (se *SyncEngine) SetEncodeFailurePolicy(policy EncodeFailurePolicy) {
+	se.encodeFailurePolicy = policy
+}
+
+// recordUnencodable records an event PutEvent skipped because it
+// couldn't be re-encoded as a malformed event at sourcePath, with a
+// warning. Other skips are left alone. sourcePath must be the event's
+// path on the source: deleting a malformed event can delete it there.
+func (se *SyncEngine) recordUnencodable(source *db.Source, sourcePath, data string, err error, result *SyncResult) {
+	if !errors.Is(err, ErrMalformedContent) {
+		return
+	}
+	msg := fmt.Sprintf("Event %s could not be encoded and was not synced: %v", icsUID(data), err)
+	log.Printf("WARNING: %s", msg)
+	result.Warnings = append(result.Warnings, msg)
+	if dbErr := se.db.SaveMalformedEvent(source.ID, sourcePath, err.Error()); dbErr != nil {
+		log.Printf("Failed to save malformed event %s: %v", sourcePath, dbErr)
+	}
+}
@@ -0,0 +1,136 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// unencodableEvent parses but fails to re-encode: the encoder allows
+// only one of DTEND and DURATION.
+const unencodableEvent = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Test//Test//EN\r\nBEGIN:VEVENT\r\n" +
+	"UID:unencodable@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260115T140000Z\r\n" +
+	"DTEND:20260115T150000Z\r\nDURATION:PT1H\r\nSUMMARY:Both ends\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+// putRecorder is a CalDAV server that accepts every PUT and keeps the
+// bodies it received.
+func putRecorder(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), bodies...)
+	}
+}
+
+func TestPutEventEncodeFailure(t *testing.T) {
+	t.Run("skip policy refuses the event", func(t *testing.T) {
+		srv, bodies := putRecorder(t)
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass", WithEncodeFailurePolicy(EncodeFailureSkip))
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		err = client.PutEvent(context.Background(), "/cal/", &Event{UID: "unencodable@example.com", Data: unencodableEvent})
+		if !errors.Is(err, ErrEventSkipped) || !errors.Is(err, ErrMalformedContent) {
+			t.Fatalf("expected a skip wrapping ErrMalformedContent, got %v", err)
+		}
+		if got := bodies(); len(got) != 0 {
+			t.Errorf("expected nothing written, got %d PUTs", len(got))
+		}
+	})
+
+	t.Run("zero policy skips", func(t *testing.T) {
+		srv, bodies := putRecorder(t)
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		err = client.PutEvent(context.Background(), "/cal/", &Event{UID: "unencodable@example.com", Data: unencodableEvent})
+		if !errors.Is(err, ErrEventSkipped) {
+			t.Fatalf("expected a skip, got %v", err)
+		}
+		if got := bodies(); len(got) != 0 {
+			t.Errorf("expected nothing written, got %d PUTs", len(got))
+		}
+	})
+
+	t.Run("raw policy puts the original data", func(t *testing.T) {
+		srv, bodies := putRecorder(t)
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass", WithEncodeFailurePolicy(EncodeFailureRaw))
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		if err := client.PutEvent(context.Background(), "/cal/", &Event{UID: "unencodable@example.com", Data: unencodableEvent}); err != nil {
+			t.Fatalf("PutEvent: %v", err)
+		}
+		got := bodies()
+		if len(got) != 1 || got[0] != unencodableEvent {
+			t.Errorf("expected the original data PUT unchanged, got %q", got)
+		}
+	})
+
+	t.Run("encodable events are unaffected", func(t *testing.T) {
+		srv, bodies := putRecorder(t)
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass", WithEncodeFailurePolicy(EncodeFailureRaw))
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		data := strings.Replace(unencodableEvent, "DURATION:PT1H\r\n", "", 1)
+		if err := client.PutEvent(context.Background(), "/cal/", &Event{UID: "unencodable@example.com", Data: data}); err != nil {
+			t.Fatalf("PutEvent: %v", err)
+		}
+		if got := bodies(); len(got) != 1 || strings.Contains(got[0], "DURATION") {
+			t.Errorf("expected one encoded PUT, got %q", got)
+		}
+	})
+}
+
+func TestRecordUnencodable(t *testing.T) {
+	f := newSelfLoopFixture(t)
+	source := f.createSource(t, "Unencodable", "https://source.example.com/dav/", "https://dest.example.com/dav/")
+	srv, _ := putRecorder(t)
+	client, err := NewClient(srv.URL+"/cal/", "user", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	putErr := client.PutEvent(context.Background(), "/cal/", &Event{UID: "unencodable@example.com", Data: unencodableEvent})
+
+	result := &SyncResult{}
+	f.engine.recordUnencodable(source, "/dav/calendars/work/unencodable.ics", unencodableEvent, putErr, result)
+	// Skips for other reasons aren't malformed events.
+	f.engine.recordUnencodable(source, "/dav/calendars/work/empty.ics", "", ErrEventSkipped, result)
+
+	malformed, err := f.database.GetMalformedEvents(f.userID)
+	if err != nil {
+		t.Fatalf("GetMalformedEvents: %v", err)
+	}
+	if len(malformed) != 1 || malformed[0].EventPath != "/dav/calendars/work/unencodable.ics" {
+		t.Fatalf("expected the unencodable event recorded under its source path, got %+v", malformed)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "unencodable@example.com") {
+		t.Errorf("expected one warning naming the event, got %v", result.Warnings)
+	}
+}
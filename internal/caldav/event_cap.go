@@ -0,0 +1,161 @@
+package caldav
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/emersion/go-webdav/caldav"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// MaxInitialSyncEventCap bounds a source's InitialSyncEventCap.
+const MaxInitialSyncEventCap = 100000
+
+// initialSyncKey marks the context of a calendar's first sync.
+// eventCapKey holds the cap GetEvents applies; only source fetches
+// set it, through sourceFetchContext.
+type initialSyncKey struct{}
+
+type eventCapKey struct{}
+
+// withInitialSync marks ctx as a calendar's first sync, so
+// sourceFetchContext applies the source's InitialSyncEventCap.
+func withInitialSync(ctx context.Context) context.Context {
+	return context.WithValue(ctx, initialSyncKey{}, true)
+}
+
+func isInitialSync(ctx context.Context) bool {
+	initial, _ := ctx.Value(initialSyncKey{}).(bool)
+	return initial
+}
+
+// withEventCap makes GetEvents return at most n events, the ones that
+// start last. The calendar-query asks the server for no more than n.
+func withEventCap(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, eventCapKey{}, n)
+}
+
+func eventCap(ctx context.Context) int {
+	n, _ := ctx.Value(eventCapKey{}).(int)
+	return n
+}
+
+// errLimitNotAccepted is returned by queryCalendarLimited when the
+// server answers a capped calendar-query with anything but a
+// multistatus, as servers without the limit extension may.
+var errLimitNotAccepted = errors.New("server did not accept the calendar-query limit")
+
+// queryCalendar runs query against calendarPath. Under a cap from
+// withEventCap it adds a limit element (nresults, as RFC 6352 defines
+// it for CardDAV and several CalDAV servers accept in calendar-query),
+// and falls back to the plain query when the server refuses it.
+func (c *Client) queryCalendar(ctx context.Context, calendarPath string, query *caldav.CalendarQuery) ([]caldav.CalendarObject, error) {
+	n := eventCap(ctx)
+	if n <= 0 {
+		return c.caldavClient.QueryCalendar(ctx, calendarPath, query)
+	}
+	objects, err := c.queryCalendarLimited(ctx, calendarPath, n)
+	if errors.Is(err, errLimitNotAccepted) {
+		log.Printf("Capped calendar-query of %s refused (%v); listing the calendar in full", calendarPath, err)
+		return c.caldavClient.QueryCalendar(ctx, calendarPath, query)
+	}
+	return objects, err
+}
+
+// limitedQueryRequest is a calendar-query for every object's ETag and
+// data, capped at %d results.
+const limitedQueryRequest = `<?xml version="1.0" encoding="utf-8" ?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop>
+    <D:getetag/>
+    <C:calendar-data/>
+  </D:prop>
+  <C:filter>
+    <C:comp-filter name="VCALENDAR"/>
+  </C:filter>
+  <C:limit>
+    <C:nresults>%d</C:nresults>
+  </C:limit>
+</C:calendar-query>`
+
+// queryCalendarLimited runs a calendar-query capped at n results. A
+// server that truncates marks the collection with a 507 response,
+// which is skipped like any response without calendar data.
+func (c *Client) queryCalendarLimited(ctx context.Context, calendarPath string, n int) ([]caldav.CalendarObject, error) {
+	req, err := http.NewRequestWithContext(ctx, "REPORT", c.buildURL(calendarPath), strings.NewReader(fmt.Sprintf(limitedQueryRequest, n)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "1")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("%w: status %d", errLimitNotAccepted, resp.StatusCode)
+	}
+	body, err := c.readBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	var ms multistatus
+	if err := xml.Unmarshal(body, &ms); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	objects := make([]caldav.CalendarObject, 0, len(ms.Responses))
+	for _, r := range ms.Responses {
+		if r.PropStat == nil || !strings.Contains(r.PropStat.Status, "200") || r.PropStat.Prop.CalendarData == "" {
+			continue
+		}
+		cal, err := parseICalendar(r.PropStat.Prop.CalendarData)
+		if err != nil {
+			log.Printf("Skipping unparseable calendar object %s: %v", r.Href, err)
+			continue
+		}
+		path := r.Href
+		if u, err := url.Parse(r.Href); err == nil {
+			path = u.Path
+		}
+		// ETags are unquoted, as go-webdav's QueryCalendar returns them.
+		etag := strings.TrimSpace(r.PropStat.Prop.GetETag)
+		if unquoted, err := strconv.Unquote(etag); err == nil {
+			etag = unquoted
+		}
+		objects = append(objects, caldav.CalendarObject{Path: path, ETag: etag, Data: cal})
+	}
+	return objects, nil
+}
+
+// latestEvents returns the n events that start last; events without
+// a DTSTART go last. Servers that ignore the limit, and the PROPFIND
+// fallback, list everything, so the cap is applied here as well.
+func latestEvents(events []Event, n int) []Event {
+	if n <= 0 || len(events) <= n {
+		return events
+	}
+	sorted := append([]Event(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].StartTime > sorted[j].StartTime })
+	return sorted[:n]
+}
+
+// initialSyncCapped reports whether a calendar's sync should cap its
+// source listing: it is the calendar's first sync, so nothing is
+// tracked for it yet and a short listing can't be read as deletions,
+// and the calendar syncs one way, so destination events missing from
+// the listing aren't pushed back to the source.
+func initialSyncCapped(source *db.Source, syncState *db.SyncState, direction db.SyncDirection) bool {
+	return source.InitialSyncEventCap > 0 && syncState == nil && direction != db.SyncDirectionTwoWay
+}
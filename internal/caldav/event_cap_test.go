@@ -0,0 +1,180 @@
+package caldav
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// capStore serves three events starting on the 1st, 2nd and 3rd of
+// January, recording the REPORT bodies it receives. honorLimit makes
+// it answer a limited query with the two that start last, as a server
+// with the limit extension would; rejectLimit makes it refuse one.
+type capStore struct {
+	*calendarStore
+	honorLimit  bool
+	rejectLimit bool
+
+	mu      sync.Mutex
+	reports []string
+}
+
+func newCapStore() *capStore {
+	s := &capStore{calendarStore: newCalendarStore()}
+	for _, day := range []string{"01", "02", "03"} {
+		s.set("/cal/"+day+".ics", mergeICS("UID:"+day+"@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:202601"+day+"T100000Z\r\nSUMMARY:Day "+day+"\r\n"))
+	}
+	return s
+}
+
+func (s *capStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == "REPORT" {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		s.reports = append(s.reports, string(body))
+		s.mu.Unlock()
+		limited := strings.Contains(string(body), "nresults")
+		if limited && s.rejectLimit {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if limited && s.honorLimit {
+			s.calendarStore.mu.Lock()
+			defer s.calendarStore.mu.Unlock()
+			w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+			w.WriteHeader(http.StatusMultiStatus)
+			var b strings.Builder
+			b.WriteString(`<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">`)
+			for _, path := range []string{"/cal/03.ics", "/cal/02.ics"} {
+				b.WriteString(`<d:response><d:href>` + path + `</d:href><d:propstat><d:prop><d:getetag>&quot;` + s.etags[path] + `&quot;</d:getetag>` +
+					`<C:calendar-data>` + strings.ReplaceAll(s.data[path], "\r", "&#13;") + `</C:calendar-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`)
+			}
+			b.WriteString(`<d:response><d:href>/cal/</d:href><d:status>HTTP/1.1 507 Insufficient Storage</d:status></d:response></d:multistatus>`)
+			_, _ = w.Write([]byte(b.String()))
+			return
+		}
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+	}
+	s.calendarStore.ServeHTTP(w, r)
+}
+
+func (s *capStore) limitedReports() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, body := range s.reports {
+		if strings.Contains(body, "<C:limit>") && strings.Contains(body, "<C:nresults>2</C:nresults>") {
+			n++
+		}
+	}
+	return n
+}
+
+func capEvents(t *testing.T, ctx context.Context, store *capStore) []Event {
+	t.Helper()
+	srv := httptest.NewServer(store)
+	t.Cleanup(srv.Close)
+	client, err := NewClient(srv.URL+"/cal/", "alice", "secret")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	events, err := client.GetEvents(ctx, "/cal/", nil)
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	return events
+}
+
+func TestGetEvents_EventCap(t *testing.T) {
+	capped := withEventCap(context.Background(), 2)
+	latest := []string{"02@example.com", "03@example.com"}
+
+	t.Run("the query carries the limit", func(t *testing.T) {
+		store := newCapStore()
+		store.honorLimit = true
+		events := capEvents(t, capped, store)
+		if got := eventUIDs(events); !reflect.DeepEqual(got, latest) {
+			t.Errorf("expected the two latest events, got %v", got)
+		}
+		if store.limitedReports() != 1 {
+			t.Errorf("expected one calendar-query with a limit of 2, got %q", store.reports)
+		}
+		for _, e := range events {
+			if e.Path != "/cal/"+strings.TrimSuffix(e.UID, "@example.com")+".ics" || e.ETag != store.etags[e.Path] {
+				t.Errorf("expected the path and unquoted ETag of %s, got %q %q", e.UID, e.Path, e.ETag)
+			}
+		}
+	})
+
+	t.Run("a server ignoring the limit is capped here", func(t *testing.T) {
+		store := newCapStore()
+		if got := eventUIDs(capEvents(t, capped, store)); !reflect.DeepEqual(got, latest) {
+			t.Errorf("expected the two latest events, got %v", got)
+		}
+	})
+
+	t.Run("a server refusing the limit gets the plain query", func(t *testing.T) {
+		store := newCapStore()
+		store.rejectLimit = true
+		if got := eventUIDs(capEvents(t, capped, store)); !reflect.DeepEqual(got, latest) {
+			t.Errorf("expected the two latest events, got %v", got)
+		}
+		if len(store.reports) != 2 {
+			t.Errorf("expected the limited query then the plain one, got %d REPORTs", len(store.reports))
+		}
+	})
+
+	t.Run("no cap, no limit", func(t *testing.T) {
+		store := newCapStore()
+		store.honorLimit = true
+		if got := capEvents(t, context.Background(), store); len(got) != 3 {
+			t.Errorf("expected every event, got %v", eventUIDs(got))
+		}
+		if store.limitedReports() != 0 {
+			t.Errorf("expected no limit without a cap, got %q", store.reports)
+		}
+	})
+}
+
+func TestSourceFetchContext_InitialSyncCap(t *testing.T) {
+	source := &db.Source{InitialSyncEventCap: 50}
+	if n := eventCap(sourceFetchContext(context.Background(), source)); n != 0 {
+		t.Errorf("expected no cap outside a first sync, got %d", n)
+	}
+	if n := eventCap(sourceFetchContext(withInitialSync(context.Background()), source)); n != 50 {
+		t.Errorf("expected the source's cap on a first sync, got %d", n)
+	}
+	if n := eventCap(sourceFetchContext(withInitialSync(context.Background()), &db.Source{})); n != 0 {
+		t.Errorf("expected no cap for a source without one, got %d", n)
+	}
+}
+
+func TestInitialSyncCapped(t *testing.T) {
+	source := &db.Source{InitialSyncEventCap: 50}
+	state := &db.SyncState{}
+	tests := []struct {
+		name      string
+		source    *db.Source
+		state     *db.SyncState
+		direction db.SyncDirection
+		want      bool
+	}{
+		{"first one-way sync", source, nil, db.SyncDirectionOneWay, true},
+		{"first create-only sync", source, nil, db.SyncDirectionOneWayCreateOnly, true},
+		{"later sync", source, state, db.SyncDirectionOneWay, false},
+		{"two-way", source, nil, db.SyncDirectionTwoWay, false},
+		{"no cap", &db.Source{}, nil, db.SyncDirectionOneWay, false},
+	}
+	for _, tt := range tests {
+		if got := initialSyncCapped(tt.source, tt.state, tt.direction); got != tt.want {
+			t.Errorf("%s: initialSyncCapped = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
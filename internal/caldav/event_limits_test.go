@@ -0,0 +1,195 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func TestGetEvents_TooManyEvents(t *testing.T) {
+	srv, _ := nestedCalDAVServer(t, map[string][]string{
+		"/cal/": {"/cal/a.ics", "/cal/b.ics", "/cal/c.ics"},
+	})
+	defer srv.Close()
+
+	t.Run("over the cap fails", func(t *testing.T) {
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass", WithEventLimits(2, 0))
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		events, err := client.GetEvents(context.Background(), "/cal/", NewMalformedEventCollector())
+		if !errors.Is(err, ErrTooManyEvents) {
+			t.Fatalf("expected ErrTooManyEvents, got %v (%d events)", err, len(events))
+		}
+	})
+
+	t.Run("at the cap succeeds", func(t *testing.T) {
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass", WithEventLimits(3, 0))
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		events, err := client.GetEvents(context.Background(), "/cal/", nil)
+		if err != nil {
+			t.Fatalf("GetEvents: %v", err)
+		}
+		if len(events) != 3 {
+			t.Errorf("expected 3 events, got %d", len(events))
+		}
+	})
+}
+
+func TestGetEvents_OversizedEventSkipped(t *testing.T) {
+	const limit = 4096
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PROPFIND":
+			var b strings.Builder
+			b.WriteString(`<?xml version="1.0" encoding="utf-8"?><D:multistatus xmlns:D="DAV:">`)
+			b.WriteString(collectionResponse("/cal/"))
+			for _, href := range []string{"/cal/small.ics", "/cal/huge.ics"} {
+				fmt.Fprintf(&b, `<D:response><D:href>%s</D:href><D:propstat><D:prop>`+
+					`<D:getcontenttype>text/calendar</D:getcontenttype><D:resourcetype/>`+
+					`</D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat></D:response>`, href)
+			}
+			b.WriteString(`</D:multistatus>`)
+			w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+			w.WriteHeader(http.StatusMultiStatus)
+			_, _ = w.Write([]byte(b.String()))
+		case "REPORT":
+			w.WriteHeader(http.StatusNotImplemented)
+		case http.MethodGet:
+			uid := strings.TrimSuffix(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], ".ics")
+			description := "short"
+			if uid == "huge" {
+				// Folded so each content line stays valid.
+				description = strings.Repeat("x", 70) + strings.Repeat("\r\n "+strings.Repeat("x", 70), 2*limit/70)
+			}
+			w.Header().Set("Content-Type", "text/calendar")
+			fmt.Fprintf(w, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VEVENT\r\n"+
+				"UID:%s\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:%s\r\n"+
+				"DESCRIPTION:%s\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n", uid, uid, description)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(srv.URL+"/cal/", "user", "pass", WithEventLimits(0, limit))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	collector := NewMalformedEventCollector()
+	events, err := client.GetEvents(context.Background(), "/cal/", collector)
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	if got := eventUIDs(events); strings.Join(got, ",") != "small" {
+		t.Errorf("expected only the small event, got %v", got)
+	}
+	malformed := collector.GetEvents()
+	if len(malformed) != 1 || malformed[0].Path != "/cal/huge.ics" {
+		t.Fatalf("expected huge.ics recorded as malformed, got %+v", malformed)
+	}
+	if !strings.Contains(malformed[0].ErrorMessage, ErrEventTooLarge.Error()) {
+		t.Errorf("expected size error in malformed record, got %q", malformed[0].ErrorMessage)
+	}
+
+	_, err = client.GetEvent(context.Background(), "/cal/huge.ics")
+	if !errors.Is(err, ErrEventTooLarge) || !errors.Is(err, ErrMalformedContent) {
+		t.Errorf("GetEvent: expected ErrEventTooLarge wrapped as malformed content, got %v", err)
+	}
+}
+
+func TestSyncCalendar_TooManyEventsAborts(t *testing.T) {
+	srcSrv, _ := nestedCalDAVServer(t, map[string][]string{
+		"/cal/": {"/cal/a.ics", "/cal/b.ics", "/cal/c.ics"},
+	})
+	defer srcSrv.Close()
+	var destWrites atomic.Int32
+	destSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut || r.Method == http.MethodDelete {
+			destWrites.Add(1)
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer destSrv.Close()
+
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	se := NewSyncEngine(database, nil)
+	se.SetEventLimits(2, 0)
+
+	source := &db.Source{ID: "src-1", DestURL: destSrv.URL + "/dest/", SyncDirection: db.SyncDirectionOneWay}
+	sourceClient, err := NewClient(srcSrv.URL+"/cal/", "user", "pass", se.ClientOptions(source)...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	destClient, err := NewClient(source.DestURL, "user", "pass", se.ClientOptions(source)...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	result := se.syncCalendar(context.Background(), source, sourceClient, destClient, Calendar{Path: "/cal/"}, 1)
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "too many events") {
+		t.Fatalf("expected a single too-many-events error, got %v", result.Errors)
+	}
+	if n := destWrites.Load(); n != 0 {
+		t.Errorf("expected no destination writes, got %d", n)
+	}
+}
+
+func TestSyncCalendar_DestinationOverCapSkipped(t *testing.T) {
+	srcSrv, _ := nestedCalDAVServer(t, map[string][]string{
+		"/cal/": {"/cal/a.ics"},
+	})
+	defer srcSrv.Close()
+	destStore := newCalendarStore()
+	for _, uid := range []string{"x", "y", "z"} {
+		destStore.set("/dest/"+uid+".ics", mergeICS("UID:"+uid+"\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\n"))
+	}
+	destSrv := httptest.NewServer(destStore)
+	defer destSrv.Close()
+
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	se := NewSyncEngine(database, nil)
+	se.SetEventLimits(2, 0)
+
+	source := &db.Source{ID: "src-1", DestURL: destSrv.URL + "/dest/", SyncDirection: db.SyncDirectionOneWay}
+	sourceClient, err := NewClient(srcSrv.URL+"/cal/", "user", "pass", se.ClientOptions(source)...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	destClient, err := NewClient(source.DestURL, "user", "pass", se.ClientOptions(source)...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	for cycle := 1; cycle <= 2; cycle++ {
+		result := se.syncCalendar(context.Background(), source, sourceClient, destClient, Calendar{Path: "/cal/"}, 1)
+		if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "too many events") {
+			t.Fatalf("cycle %d: expected a too-many-events error, got %v", cycle, result.Errors)
+		}
+		if result.Created != 0 || result.Updated != 0 {
+			t.Errorf("cycle %d: expected nothing written, got created %d updated %d", cycle, result.Created, result.Updated)
+		}
+	}
+	if destStore.puts != 0 {
+		t.Errorf("expected no PUTs to the over-cap destination, got %d", destStore.puts)
+	}
+}
@@ -0,0 +1,79 @@
+package caldav
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientHeaders(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	t.Run("user agent and custom headers are sent", func(t *testing.T) {
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass",
+			WithUserAgent("calbridgesync/test"),
+			WithCustomHeaders(map[string]string{"x-proxy-tenant": "acme"}),
+		)
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		if err := client.DeleteEvent(context.Background(), "/cal/event.ics"); err != nil {
+			t.Fatalf("DeleteEvent: %v", err)
+		}
+		if ua := got.Get("User-Agent"); ua != "calbridgesync/test" {
+			t.Errorf("User-Agent = %q, want calbridgesync/test", ua)
+		}
+		if v := got.Get("X-Proxy-Tenant"); v != "acme" {
+			t.Errorf("X-Proxy-Tenant = %q, want acme", v)
+		}
+	})
+
+	t.Run("custom header overrides the default user agent", func(t *testing.T) {
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass",
+			WithUserAgent("calbridgesync/test"),
+			WithCustomHeaders(map[string]string{"User-Agent": "Picky/1.0"}),
+		)
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		if err := client.DeleteEvent(context.Background(), "/cal/event.ics"); err != nil {
+			t.Fatalf("DeleteEvent: %v", err)
+		}
+		if ua := got.Get("User-Agent"); ua != "Picky/1.0" {
+			t.Errorf("User-Agent = %q, want Picky/1.0", ua)
+		}
+	})
+
+	t.Run("authorization cannot be clobbered", func(t *testing.T) {
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass",
+			WithCustomHeaders(map[string]string{"authorization": "Bearer stolen"}),
+		)
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		if err := client.DeleteEvent(context.Background(), "/cal/event.ics"); err != nil {
+			t.Fatalf("DeleteEvent: %v", err)
+		}
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth("user", "pass")
+		if auth := got.Get("Authorization"); auth != req.Header.Get("Authorization") {
+			t.Errorf("Authorization = %q, want the client's basic credentials", auth)
+		}
+	})
+
+	t.Run("no options leaves the transport unwrapped", func(t *testing.T) {
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		if _, ok := client.httpClient.Transport.(*headerTransport); ok {
+			t.Error("expected no header transport without a user agent or custom headers")
+		}
+	})
+}
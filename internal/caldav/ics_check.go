@@ -0,0 +1,96 @@
+package caldav
+
+import (
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+// MaxICSCheckBytes bounds the iCalendar data CheckICS is given.
+const MaxICSCheckBytes = 10 * 1024 * 1024
+
+// ICSComponentCheck reports on one VEVENT, VTODO or VJOURNAL of a
+// checked payload.
+type ICSComponentCheck struct {
+	Component    string   `json:"component"`
+	UID          string   `json:"uid,omitempty"`
+	RecurrenceID string   `json:"recurrence_id,omitempty"`
+	Summary      string   `json:"summary,omitempty"`
+	Issues       []string `json:"issues"`
+}
+
+// ICSCheckResult reports whether iCalendar data parses and encodes the
+// way a sync would read and write it, and what is wrong with each of
+// its entries.
+type ICSCheckResult struct {
+	Parsed      bool   `json:"parsed"`
+	ParseError  string `json:"parse_error,omitempty"`
+	Encodes     bool   `json:"encodes"`
+	EncodeError string `json:"encode_error,omitempty"`
+	// EncodesAfterRepair is whether the data encodes once a sync has
+	// filled in missing DTSTAMPs, as it does for every fetched event.
+	EncodesAfterRepair bool                `json:"encodes_after_repair"`
+	Components         []ICSComponentCheck `json:"components"`
+}
+
+// CheckICS runs data through parseICalendar and encodeCalendar, as a
+// sync does, and reports the outcome. Nothing is written anywhere.
+func CheckICS(data string) *ICSCheckResult {
+	result := &ICSCheckResult{Components: []ICSComponentCheck{}}
+	cal, err := parseICalendar(data)
+	if err != nil {
+		result.ParseError = err.Error()
+		return result
+	}
+	result.Parsed = true
+
+	for _, comp := range cal.Children {
+		if !dtstampComponents[comp.Name] {
+			continue
+		}
+		result.Components = append(result.Components, checkICSComponent(comp))
+	}
+
+	if _, err := encodeCalendar(cal); err != nil {
+		result.EncodeError = err.Error()
+	} else {
+		result.Encodes = true
+	}
+	result.EncodesAfterRepair = result.Encodes
+	if !result.Encodes && fillMissingDTStamps(cal, time.Now()) {
+		_, err := encodeCalendar(cal)
+		result.EncodesAfterRepair = err == nil
+	}
+	return result
+}
+
+// checkICSComponent lists what a sync would trip over in comp.
+func checkICSComponent(comp *ical.Component) ICSComponentCheck {
+	check := ICSComponentCheck{Component: comp.Name, Issues: []string{}}
+	if prop := comp.Props.Get(ical.PropUID); prop != nil {
+		check.UID = prop.Value
+	}
+	if prop := comp.Props.Get(ical.PropRecurrenceID); prop != nil {
+		check.RecurrenceID = normalizeStartTime(prop)
+	}
+	if summary, err := comp.Props.Text(ical.PropSummary); err == nil {
+		check.Summary = summary
+	}
+
+	switch n := len(comp.Props.Values(ical.PropUID)); {
+	case n == 0 || check.UID == "":
+		check.Issues = append(check.Issues, "missing UID; the sync skips it unless the source generates missing UIDs")
+	case n > 1:
+		check.Issues = append(check.Issues, "more than one UID")
+	}
+	switch n := len(comp.Props.Values(ical.PropDateTimeStamp)); {
+	case n == 0:
+		check.Issues = append(check.Issues, "missing DTSTAMP; the sync fills one in")
+	case n > 1:
+		check.Issues = append(check.Issues, "more than one DTSTAMP")
+	}
+	if comp.Name == ical.CompEvent && comp.Props.Get(ical.PropDateTimeEnd) != nil && comp.Props.Get(ical.PropDuration) != nil {
+		check.Issues = append(check.Issues, "has both DTEND and DURATION")
+	}
+	return check
+}
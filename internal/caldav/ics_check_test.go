@@ -0,0 +1,63 @@
+package caldav
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckICS(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		result := CheckICS(recurringMasterICS)
+		if !result.Parsed || !result.Encodes || !result.EncodesAfterRepair {
+			t.Fatalf("expected a clean result, got %+v", result)
+		}
+		if len(result.Components) != 1 {
+			t.Fatalf("expected one component, got %+v", result.Components)
+		}
+		c := result.Components[0]
+		if c.Component != "VEVENT" || c.UID != "weekly@example.com" || c.Summary != "Weekly sync" || len(c.Issues) != 0 {
+			t.Errorf("unexpected component %+v", c)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		result := CheckICS("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\nthis line has no colon\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n")
+		if result.Parsed || result.ParseError == "" {
+			t.Fatalf("expected a parse error, got %+v", result)
+		}
+		if result.Encodes || len(result.Components) != 0 {
+			t.Errorf("expected nothing past the parse to be reported, got %+v", result)
+		}
+	})
+
+	t.Run("missing DTSTAMP", func(t *testing.T) {
+		data := strings.Replace(recurringOverrideICS, "DTSTAMP:20260101T000000Z\r\n", "", 1)
+		result := CheckICS(data)
+		if !result.Parsed {
+			t.Fatalf("expected the payload to parse, got %+v", result)
+		}
+		if result.Encodes || !strings.Contains(result.EncodeError, "DTSTAMP") {
+			t.Errorf("expected the encoder to refuse it for DTSTAMP, got %+v", result)
+		}
+		if !result.EncodesAfterRepair {
+			t.Error("expected it to encode once the sync fills in DTSTAMP")
+		}
+		c := result.Components[0]
+		if c.RecurrenceID != "20260112T100000Z" {
+			t.Errorf("expected the override's RECURRENCE-ID, got %q", c.RecurrenceID)
+		}
+		if len(c.Issues) != 1 || !strings.Contains(c.Issues[0], "missing DTSTAMP") {
+			t.Errorf("expected a missing DTSTAMP issue, got %v", c.Issues)
+		}
+	})
+
+	t.Run("missing UID", func(t *testing.T) {
+		result := CheckICS(strings.Replace(recurringMasterICS, "UID:weekly@example.com\r\n", "", 1))
+		if result.Encodes || result.EncodesAfterRepair {
+			t.Errorf("expected a UID-less event not to encode, got %+v", result)
+		}
+		if issues := result.Components[0].Issues; len(issues) != 1 || !strings.Contains(issues[0], "missing UID") {
+			t.Errorf("expected a missing UID issue, got %v", issues)
+		}
+	})
+}
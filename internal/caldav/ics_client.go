@@ -353,17 +353,30 @@ func (c *ICSClient) FetchEvents(ctx context.Context, collector *MalformedEventCo
 		return nil, fmt.Errorf("failed to parse ICS feed: %w", err)
 	}
 
-	// Group VEVENTs by UID so recurring events (master + exceptions) stay together
+	// Group VEVENTs by UID so recurring events (master + exceptions) stay
+	// together. VJOURNALs are grouped the same way; a UID with no VEVENT
+	// becomes a journal entry.
 	type uidGroup struct {
 		summary   string
 		startTime string
 		vevents   []*ical.Component
+		journal   bool
 	}
 	groups := make(map[string]*uidGroup)
 	var groupOrder []string
+	entryCount := 0
 
-	for _, vevent := range cal.Events() {
+	for _, vevent := range cal.Children {
+		if vevent.Name != ical.CompEvent && vevent.Name != ical.CompJournal {
+			continue
+		}
+		entryCount++
 		uid, _ := vevent.Props.Text(ical.PropUID)
+		if uid == "" && generatesMissingUIDs(ctx) {
+			// Feed entries are independent events, so each gets its own.
+			uid = syntheticUID(vevent)
+			vevent.Props.SetText(ical.PropUID, uid)
+		}
 		if uid == "" {
 			if collector != nil {
 				collector.Add("", "event missing UID")
@@ -373,12 +386,15 @@ func (c *ICSClient) FetchEvents(ctx context.Context, collector *MalformedEventCo
 
 		g, exists := groups[uid]
 		if !exists {
-			g = &uidGroup{}
+			g = &uidGroup{journal: true}
 			groups[uid] = g
 			groupOrder = append(groupOrder, uid)
 		}
+		if vevent.Name == ical.CompEvent {
+			g.journal = false
+		}
 
-		g.vevents = append(g.vevents, vevent.Component)
+		g.vevents = append(g.vevents, vevent)
 
 		// Use the master event (no RECURRENCE-ID) for summary and start time
 		if vevent.Props.Get("RECURRENCE-ID") == nil {
@@ -402,6 +418,7 @@ func (c *ICSClient) FetchEvents(ctx context.Context, collector *MalformedEventCo
 			singleCal.Children = append(singleCal.Children, vevent)
 		}
 
+		fillMissingDTStamps(singleCal, time.Now())
 		data, encErr := encodeCalendar(singleCal)
 		if encErr != nil {
 			if collector != nil {
@@ -416,9 +433,10 @@ func (c *ICSClient) FetchEvents(ctx context.Context, collector *MalformedEventCo
 			Summary:   g.summary,
 			StartTime: g.startTime,
 			Data:      data,
+			Journal:   g.journal,
 		})
 	}
 
-	log.Printf("ICS feed: parsed %d events (%d UIDs grouped from %d VEVENTs)", len(events), len(groups), len(cal.Events()))
+	log.Printf("ICS feed: parsed %d events (%d UIDs grouped from %d VEVENTs/VJOURNALs)", len(events), len(groups), entryCount)
 	return events, nil
 }
@@ -0,0 +1,62 @@
+package caldav
+
+import (
+	"strings"
+
+	"github.com/emersion/go-ical"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// AccountEmail returns the address source's account organizes events
+// as: its AccountEmail, or else its source username when that is an
+// email address, as it is for most providers. "" when neither is
+// known.
+func AccountEmail(source *db.Source) string {
+	if source.AccountEmail != "" {
+		return source.AccountEmail
+	}
+	if strings.Contains(source.SourceUsername, "@") {
+		return source.SourceUsername
+	}
+	return ""
+}
+
+// isInvitedEvent reports whether data is an event someone other than
+// email organizes, that is, one the account was invited to. Events
+// without an ORGANIZER are the account's own; journals and data that
+// can't be parsed are never invitations.
+func isInvitedEvent(data, email string) bool {
+	if data == "" || email == "" {
+		return false
+	}
+	cal, err := parseICalendar(data)
+	if err != nil {
+		return false
+	}
+	entries, journal := calendarEntries(cal)
+	if journal || len(entries) == 0 {
+		return false
+	}
+	prop := entries[0].Props.Get(ical.PropOrganizer)
+	if prop == nil || strings.TrimSpace(prop.Value) == "" {
+		return false
+	}
+	return normalizeCalAddress(prop.Value) != normalizeCalAddress(email)
+}
+
+// dropInvited removes the events the source's account was invited to
+// when the source skips them, and returns how many it removed. Without
+// an account email nothing is removed.
+func dropInvited(events []Event, source *db.Source) ([]Event, int) {
+	email := AccountEmail(source)
+	if !source.SkipInvitedEvents || email == "" {
+		return events, 0
+	}
+	kept := make([]Event, 0, len(events))
+	for _, e := range events {
+		if !isInvitedEvent(e.Data, email) {
+			kept = append(kept, e)
+		}
+	}
+	return kept, len(events) - len(kept)
+}
@@ -0,0 +1,97 @@
+package caldav
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func organizedICS(uid, organizer string) string {
+	ics := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VEVENT\r\n" +
+		"UID:" + uid + "\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:" + uid + "\r\n"
+	if organizer != "" {
+		ics += "ORGANIZER;CN=Someone:" + organizer + "\r\n"
+	}
+	return ics + "END:VEVENT\r\nEND:VCALENDAR\r\n"
+}
+
+func TestIsInvitedEvent(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want bool
+	}{
+		{"organized", organizedICS("a@example.com", "mailto:alice@work.example"), false},
+		{"case and scheme differ", organizedICS("a@example.com", "MAILTO:Alice@Work.Example"), false},
+		{"invited", organizedICS("a@example.com", "mailto:bob@work.example"), true},
+		{"no organizer", organizedICS("a@example.com", ""), false},
+		{"journal", journalICS, false},
+		{"unparseable", "not a calendar", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isInvitedEvent(tt.data, "alice@work.example"); got != tt.want {
+				t.Errorf("isInvitedEvent = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccountEmail(t *testing.T) {
+	if got := AccountEmail(&db.Source{SourceUsername: "alice@work.example", AccountEmail: "a.smith@work.example"}); got != "a.smith@work.example" {
+		t.Errorf("expected the account email to win, got %q", got)
+	}
+	if got := AccountEmail(&db.Source{SourceUsername: "alice@work.example"}); got != "alice@work.example" {
+		t.Errorf("expected an email username as fallback, got %q", got)
+	}
+	if got := AccountEmail(&db.Source{SourceUsername: "alice"}); got != "" {
+		t.Errorf("expected no address for a plain username, got %q", got)
+	}
+}
+
+func TestSyncEventsToDestination_SkipInvitedEvents(t *testing.T) {
+	f := newSelfLoopFixture(t)
+	destStore := newCalendarStore()
+	destSrv := httptest.NewServer(destStore)
+	defer destSrv.Close()
+
+	source := f.createSource(t, "Work", "https://source.example.com/cal/", destSrv.URL+"/dest/")
+	source.SyncDirection = db.SyncDirectionOneWay
+	source.SkipInvitedEvents = true
+	source.AccountEmail = "alice@work.example"
+	if err := f.database.UpdateSource(source); err != nil {
+		t.Fatalf("UpdateSource: %v", err)
+	}
+	destClient, err := NewClient(destSrv.URL+"/dest/", "dest", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	sourceEvents := []Event{
+		{Path: "/cal/planning.ics", ETag: `"1"`, UID: "planning@example.com", Data: organizedICS("planning@example.com", "mailto:alice@work.example")},
+		{Path: "/cal/allhands.ics", ETag: `"1"`, UID: "allhands@example.com", Data: organizedICS("allhands@example.com", "mailto:ceo@work.example")},
+		{Path: "/cal/focus.ics", ETag: `"1"`, UID: "focus@example.com", Data: organizedICS("focus@example.com", "")},
+	}
+	result := f.engine.syncEventsToDestination(context.Background(), source, nil, destClient, sourceEvents,
+		Calendar{Path: "/cal/", Name: "Work"}, 1, db.SyncDirectionOneWay)
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+
+	destStore.mu.Lock()
+	defer destStore.mu.Unlock()
+	if _, ok := destStore.data["/dest/planning@example.com.ics"]; !ok {
+		t.Error("expected the event alice organizes to be synced")
+	}
+	if _, ok := destStore.data["/dest/focus@example.com.ics"]; !ok {
+		t.Error("expected the event without an organizer to be synced")
+	}
+	if _, ok := destStore.data["/dest/allhands@example.com.ics"]; ok {
+		t.Error("expected the event alice was invited to to be skipped")
+	}
+	if result.Created != 2 {
+		t.Errorf("expected 2 created, got %d", result.Created)
+	}
+}
@@ -0,0 +1,45 @@
+package caldav
+
+import (
+	"fmt"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// isJournal reports whether data holds VJOURNAL entries and no VEVENT.
+// Unparseable data is not a journal.
+func isJournal(data string) bool {
+	if data == "" {
+		return false
+	}
+	cal, err := parseICalendar(data)
+	if err != nil {
+		return false
+	}
+	_, journal := calendarEntries(cal)
+	return journal
+}
+
+// dropJournals removes journal entries from events unless the source
+// syncs them, and returns how many it removed.
+func dropJournals(events []Event, source *db.Source) ([]Event, int) {
+	if source.SyncJournals {
+		return events, 0
+	}
+	kept := make([]Event, 0, len(events))
+	for _, e := range events {
+		if !e.Journal {
+			kept = append(kept, e)
+		}
+	}
+	return kept, len(events) - len(kept)
+}
+
+// journalNote is appended to a sync message when journal entries were
+// skipped, so users can tell why they didn't arrive.
+func journalNote(skipped int) string {
+	if skipped == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d VJOURNAL entries skipped; enable journal sync on the source to copy them)", skipped)
+}
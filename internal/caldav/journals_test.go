@@ -0,0 +1,122 @@
+package caldav
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+const journalICS = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+	"BEGIN:VJOURNAL\r\nUID:notes@example.com\r\nDTSTAMP:20260101T000000Z\r\n" +
+	"DTSTART;VALUE=DATE:20260105\r\nSUMMARY:Meeting notes\r\nEND:VJOURNAL\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestIsJournal(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want bool
+	}{
+		{"journal only", journalICS, true},
+		{"event", mergeICS("UID:a@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\n"), false},
+		{"event next to a journal", strings.Replace(journalICS, "END:VCALENDAR",
+			"BEGIN:VEVENT\r\nUID:b@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nEND:VEVENT\r\nEND:VCALENDAR", 1), false},
+		{"empty", "", false},
+		{"unparseable", "not a calendar", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isJournal(tt.data); got != tt.want {
+				t.Errorf("isJournal = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncEventsToDestination_Journals(t *testing.T) {
+	for _, syncJournals := range []bool{false, true} {
+		name := "skipped by default"
+		if syncJournals {
+			name = "copied when enabled"
+		}
+		t.Run(name, func(t *testing.T) {
+			srcStore, destStore := newCalendarStore(), newCalendarStore()
+			srcSrv := httptest.NewServer(srcStore)
+			defer srcSrv.Close()
+			destSrv := httptest.NewServer(destStore)
+			defer destSrv.Close()
+			srcStore.set("/cal/standup.ics", mergeICS("UID:standup@example.com\r\nDTSTAMP:20260101T000000Z\r\n"+
+				"DTSTART:20260105T100000Z\r\nSUMMARY:Standup\r\n"))
+			srcStore.set("/cal/notes.ics", journalICS)
+
+			f := newSelfLoopFixture(t)
+			source := f.createSource(t, "Journals", srcSrv.URL+"/cal/", destSrv.URL+"/dest/")
+			source.SyncDirection = db.SyncDirectionOneWay
+			source.SyncJournals = syncJournals
+
+			sourceClient, err := NewClient(srcSrv.URL+"/cal/", "alice", "pass")
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			destClient, err := NewClient(destSrv.URL+"/dest/", "alice", "pass")
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			events, err := sourceClient.GetEvents(context.Background(), "/cal/", nil)
+			if err != nil {
+				t.Fatalf("GetEvents: %v", err)
+			}
+			journals := 0
+			for _, e := range events {
+				if e.Journal {
+					journals++
+					if e.UID != "notes@example.com" {
+						t.Errorf("journal UID = %q, want notes@example.com", e.UID)
+					}
+				}
+			}
+			if len(events) != 2 || journals != 1 {
+				t.Fatalf("expected an event and a journal from the source, got %+v", events)
+			}
+
+			result := f.engine.syncEventsToDestination(context.Background(), source, sourceClient, destClient, events,
+				Calendar{Path: "/cal/", Name: "Cal"}, 1, db.SyncDirectionOneWay)
+
+			destStore.mu.Lock()
+			defer destStore.mu.Unlock()
+			copied := false
+			for _, data := range destStore.data {
+				if strings.Contains(data, "BEGIN:VJOURNAL") {
+					copied = true
+				}
+			}
+			if syncJournals {
+				if !copied || result.JournalsSkipped != 0 {
+					t.Errorf("expected the journal to be copied, got skipped=%d dest=%v", result.JournalsSkipped, destStore.data)
+				}
+				if result.Created != 2 {
+					t.Errorf("expected 2 created, got %d (warnings %v)", result.Created, result.Warnings)
+				}
+			} else {
+				if copied || result.JournalsSkipped != 1 {
+					t.Errorf("expected the journal to be skipped and counted, got skipped=%d dest=%v", result.JournalsSkipped, destStore.data)
+				}
+				if result.Created != 1 {
+					t.Errorf("expected only the event created, got %d (warnings %v)", result.Created, result.Warnings)
+				}
+			}
+		})
+	}
+}
+
+func TestJournalNote(t *testing.T) {
+	if got := journalNote(0); got != "" {
+		t.Errorf("journalNote(0) = %q, want empty", got)
+	}
+	if got := journalNote(3); !strings.Contains(got, "3 VJOURNAL entries skipped") {
+		t.Errorf("journalNote(3) = %q", got)
+	}
+}
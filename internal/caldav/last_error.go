@@ -0,0 +1,72 @@
+package caldav
+
+import (
+	"regexp"
+	"strings"
+)
+
+// maxLastErrorDetail bounds a source's LastErrorDetail; it is a
+// one-glance reason, the sync log keeps the full error.
+const maxLastErrorDetail = 300
+
+// errorDetailURL matches URLs, which are left out of LastErrorDetail.
+var errorDetailURL = regexp.MustCompile(`https?://\S+`)
+
+// errorDetailCategories maps text in a sync error to the reason shown
+// as a source's LastErrorDetail. The first match wins.
+var errorDetailCategories = []struct {
+	markers []string
+	reason  string
+}{
+	{[]string{"sync loop detected"}, "source and destination are the same calendar"},
+	{[]string{"invalid_grant", "refresh token"}, "Google authorization expired or was revoked; reconnect the source"},
+	{[]string{"decrypt"}, "stored credentials could not be decrypted"},
+	{[]string{"authentication failed", "401", "unauthorized"}, "authentication failed"},
+	{[]string{"403", "forbidden"}, "permission denied"},
+	{[]string{"too many events"}, "calendar has more events than the configured limit"},
+	{[]string{"response too large"}, "server response exceeded the size limit"},
+	{[]string{"certificate", "x509", "tls:"}, "TLS certificate problem"},
+	{[]string{"no such host", "connection refused", "connection reset", "network is unreachable"}, "server unreachable"},
+	{[]string{"timeout", "deadline exceeded"}, "timed out"},
+	{[]string{"context canceled"}, "sync was canceled"},
+	{[]string{"404", "not found"}, "not found"},
+}
+
+// lastErrorDetail summarizes a failed sync for the source's
+// LastErrorDetail: what failed (the result message, or the first
+// error's leading clause when the message is only a count) and why,
+// categorized where the error is recognized. Unrecognized errors are
+// included with URLs removed, truncated.
+func lastErrorDetail(result *SyncResult) string {
+	if len(result.Errors) == 0 {
+		return truncateDetail(errorDetailURL.ReplaceAllString(result.Message, "<url>"))
+	}
+	first := result.Errors[0]
+
+	what := result.Message
+	if what == "" || strings.Contains(what, "failed with") {
+		what, _, _ = strings.Cut(first, ":")
+	}
+
+	lower := strings.ToLower(first)
+	for _, c := range errorDetailCategories {
+		for _, m := range c.markers {
+			if strings.Contains(lower, m) {
+				return truncateDetail(errorDetailURL.ReplaceAllString(what, "<url>") + ": " + c.reason)
+			}
+		}
+	}
+	why := errorDetailURL.ReplaceAllString(first, "<url>")
+	if first == what || strings.HasPrefix(first, what+":") {
+		return truncateDetail(why)
+	}
+	return truncateDetail(errorDetailURL.ReplaceAllString(what, "<url>") + ": " + why)
+}
+
+// truncateDetail cuts s to maxLastErrorDetail bytes.
+func truncateDetail(s string) string {
+	if len(s) <= maxLastErrorDetail {
+		return s
+	}
+	return strings.ToValidUTF8(s[:maxLastErrorDetail], "") + "..."
+}
@@ -0,0 +1,71 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// destIsNewer decides a latest_wins conflict: it reports whether the
+// destination version was modified after the source version. skew is
+// how far the source clock is ahead of the destination's; the source
+// timestamp is moved onto the destination's clock before comparing.
+// A version without LAST-MODIFIED or DTSTAMP, or that doesn't parse,
+// loses to the source, as under source_wins.
+func destIsNewer(sourceData, destData string, skew time.Duration) bool {
+	sourceTime := masterModifiedAt(sourceData)
+	destTime := masterModifiedAt(destData)
+	if sourceTime.IsZero() || destTime.IsZero() {
+		return false
+	}
+	return destTime.After(sourceTime.Add(-skew))
+}
+
+// masterModifiedAt is modifiedAt for the master VEVENT of data, or the
+// zero time if there is none.
+func masterModifiedAt(data string) time.Time {
+	cal, err := parseICalendar(data)
+	if err != nil {
+		return time.Time{}
+	}
+	for _, comp := range cal.Children {
+		if comp.Name == ical.CompEvent && comp.Props.Get(ical.PropRecurrenceID) == nil {
+			return modifiedAt(comp)
+		}
+	}
+	return time.Time{}
+}
+
+// writeDestToSource resolves a latest_wins conflict the destination
+// won: its version is written back to the source and the ETags to
+// track are returned. ok is false when the write failed and nothing
+// should be recorded; a read-only source simply keeps its version.
+func (se *SyncEngine) writeDestToSource(ctx context.Context, source *db.Source, sourceClient *Client,
+	sourceCalendarPath string, sourceEvent, destEvent Event, result *SyncResult) (entry syncETagEntry, ok bool) {
+	toSource := destEvent
+	toSource.Path = sourceEvent.Path
+	if err := sourceClient.PutEvent(ctx, sourceCalendarPath, &toSource); err != nil {
+		switch {
+		case errors.Is(err, ErrEventSkipped):
+			result.Skipped++
+		case isForbiddenError(err):
+			// Read-only source calendar: nothing to report.
+		default:
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to update event on source: %v", err))
+		}
+		return syncETagEntry{}, false
+	}
+	result.Updated++
+	verifyWrite(ctx, source, sourceClient, sourceCalendarPath, &toSource, result)
+	result.Warnings = append(result.Warnings, fmt.Sprintf(
+		"CONFLICT:{\"uid\":%q,\"winner\":\"dest\",\"summary\":%q,\"strategy\":%q}",
+		destEvent.UID, destEvent.Summary, source.ConflictStrategy))
+	return syncETagEntry{
+		sourceETag: currentETag(ctx, sourceClient, toSource.Path, sourceEvent.ETag),
+		destETag:   destEvent.ETag,
+	}, true
+}
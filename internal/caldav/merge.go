@@ -0,0 +1,380 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// mergeScheduleProps decide when and how often an event happens. They
+// are taken together from the newer version so a moved DTSTART never
+// ends up paired with the other side's DTEND or RRULE.
+var mergeScheduleProps = []string{
+	ical.PropDateTimeStart, ical.PropDateTimeEnd, ical.PropDuration,
+	ical.PropRecurrenceRule, ical.PropRecurrenceDates, ical.PropExceptionDates,
+	ical.PropStatus, ical.PropTransparency, ical.PropClass, ical.PropPriority,
+}
+
+// mergeEvents combines two versions of the same event for the merge
+// conflict strategy. VEVENTs are paired by RECURRENCE-ID; a VEVENT
+// present in only one version (an override the other side lacks) is
+// kept, and so is every VTIMEZONE either side defines. For each pair:
+//
+//   - The newer version is the one with the later LAST-MODIFIED,
+//     falling back to DTSTAMP; a tie goes to a.
+//   - Schedule properties (DTSTART, DTEND, DURATION, RRULE, RDATE,
+//     EXDATE, STATUS, TRANSP, CLASS, PRIORITY) come from the newer
+//     version as a block.
+//   - ATTENDEEs are the union of both lists, matched by address; an
+//     attendee on both sides takes the newer version's parameters, so
+//     a PARTSTAT reply isn't lost.
+//   - CATEGORIES are the union of both, compared case-insensitively.
+//   - VALARMs come from the newer version if it has any, otherwise
+//     from the other one.
+//   - SEQUENCE is the higher of the two; LAST-MODIFIED and DTSTAMP
+//     come from the newer version.
+//   - Every other property (SUMMARY, DESCRIPTION, LOCATION, URL,
+//     ORGANIZER, X- properties, ...) prefers a non-empty value over an
+//     empty or missing one; when both are set the newer version wins.
+//
+// The result is a's calendar with b merged in.
+func mergeEvents(a, b string) (string, error) {
+	calA, err := parseICalendar(a)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrMalformedContent, err)
+	}
+	calB, err := parseICalendar(b)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrMalformedContent, err)
+	}
+
+	eventsB := make(map[string]*ical.Component)
+	var orderB []string
+	tzidsA := make(map[string]bool)
+	for _, comp := range calA.Children {
+		if comp.Name == ical.CompTimezone {
+			tzidsA[componentTZID(comp)] = true
+		}
+	}
+	var timezones, others []*ical.Component
+	for _, comp := range calA.Children {
+		if comp.Name == ical.CompTimezone {
+			timezones = append(timezones, comp)
+		} else {
+			others = append(others, comp)
+		}
+	}
+	for _, comp := range calB.Children {
+		switch comp.Name {
+		case ical.CompEvent:
+			key := recurrenceKey(comp)
+			if _, dup := eventsB[key]; !dup {
+				orderB = append(orderB, key)
+			}
+			eventsB[key] = comp
+		case ical.CompTimezone:
+			if tzid := componentTZID(comp); !tzidsA[tzid] {
+				tzidsA[tzid] = true
+				timezones = append(timezones, comp)
+			}
+		}
+	}
+
+	matched := false
+	for _, comp := range others {
+		if comp.Name != ical.CompEvent {
+			continue
+		}
+		key := recurrenceKey(comp)
+		if other, ok := eventsB[key]; ok {
+			mergeEventComponents(comp, other)
+			delete(eventsB, key)
+			matched = true
+		}
+	}
+	if !matched {
+		return "", errors.New("no VEVENT in common")
+	}
+	for _, key := range orderB {
+		if comp, ok := eventsB[key]; ok {
+			others = append(others, comp)
+		}
+	}
+
+	calA.Children = append(timezones, others...)
+	return encodeCalendar(calA)
+}
+
+// mergeEventComponents merges y into x following mergeEvents' rules.
+func mergeEventComponents(x, y *ical.Component) {
+	yNewer := modifiedAt(y).After(modifiedAt(x))
+	newer := x
+	if yNewer {
+		newer = y
+	}
+
+	handled := map[string]bool{
+		ical.PropUID:           true,
+		ical.PropRecurrenceID:  true,
+		ical.PropAttendee:      true,
+		ical.PropCategories:    true,
+		ical.PropSequence:      true,
+		ical.PropLastModified:  true,
+		ical.PropDateTimeStamp: true,
+	}
+
+	for _, name := range mergeScheduleProps {
+		handled[name] = true
+		if yNewer {
+			copyProp(x, y, name)
+		}
+	}
+	for _, name := range []string{ical.PropLastModified, ical.PropDateTimeStamp} {
+		if newer.Props.Get(name) != nil {
+			copyProp(x, newer, name)
+		}
+	}
+
+	if attendees := mergeAttendees(x.Props[ical.PropAttendee], y.Props[ical.PropAttendee], yNewer); len(attendees) > 0 {
+		x.Props[ical.PropAttendee] = attendees
+	}
+	mergeCategories(x, y)
+	mergeSequence(x, y)
+	mergeAlarms(x, y, yNewer)
+
+	for name, ys := range y.Props {
+		if handled[name] || !hasValue(ys) {
+			continue
+		}
+		if !hasValue(x.Props[name]) || yNewer {
+			x.Props[name] = ys
+		}
+	}
+}
+
+// modifiedAt is when a VEVENT version was last changed: LAST-MODIFIED,
+// else DTSTAMP, else the zero time.
+func modifiedAt(comp *ical.Component) time.Time {
+	for _, name := range []string{ical.PropLastModified, ical.PropDateTimeStamp} {
+		if t, err := comp.Props.DateTime(name, time.UTC); err == nil && !t.IsZero() {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// recurrenceKey identifies a VEVENT within its resource: "" for the
+// master, the normalized RECURRENCE-ID for an override.
+func recurrenceKey(comp *ical.Component) string {
+	return normalizeStartTime(comp.Props.Get(ical.PropRecurrenceID))
+}
+
+func componentTZID(comp *ical.Component) string {
+	if p := comp.Props.Get(ical.PropTimezoneID); p != nil {
+		return p.Value
+	}
+	return ""
+}
+
+// copyProp replaces x's name property with src's, removing it from x
+// if src doesn't have it.
+func copyProp(x, src *ical.Component, name string) {
+	if values, ok := src.Props[name]; ok {
+		x.Props[name] = values
+	} else {
+		delete(x.Props, name)
+	}
+}
+
+func hasValue(props []ical.Prop) bool {
+	for _, p := range props {
+		if strings.TrimSpace(p.Value) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// attendeeAddress normalizes an ATTENDEE value for matching.
+func attendeeAddress(value string) string {
+	addr := strings.ToLower(strings.TrimSpace(value))
+	return strings.TrimPrefix(addr, "mailto:")
+}
+
+// mergeAttendees returns xs followed by the attendees only ys has. An
+// attendee on both sides keeps its position in xs and takes ys'
+// parameters when yNewer.
+func mergeAttendees(xs, ys []ical.Prop, yNewer bool) []ical.Prop {
+	merged := make([]ical.Prop, 0, len(xs)+len(ys))
+	index := make(map[string]int, len(xs)+len(ys))
+	add := func(props []ical.Prop, override bool) {
+		for _, p := range props {
+			addr := attendeeAddress(p.Value)
+			if i, ok := index[addr]; ok {
+				if override {
+					merged[i] = p
+				}
+				continue
+			}
+			index[addr] = len(merged)
+			merged = append(merged, p)
+		}
+	}
+	add(xs, false)
+	add(ys, yNewer)
+	return merged
+}
+
+// mergeCategories sets x's CATEGORIES to the union of both sides.
+func mergeCategories(x, y *ical.Component) {
+	var values []string
+	seen := make(map[string]bool)
+	for _, comp := range []*ical.Component{x, y} {
+		for _, prop := range comp.Props.Values(ical.PropCategories) {
+			list, err := prop.TextList()
+			if err != nil {
+				continue
+			}
+			for _, v := range list {
+				key := strings.ToLower(strings.TrimSpace(v))
+				if key == "" || seen[key] {
+					continue
+				}
+				seen[key] = true
+				values = append(values, v)
+			}
+		}
+	}
+	if len(values) == 0 {
+		return
+	}
+	prop := ical.NewProp(ical.PropCategories)
+	prop.SetTextList(values)
+	x.Props.Set(prop)
+}
+
+// mergeSequence sets x's SEQUENCE to the higher of the two.
+func mergeSequence(x, y *ical.Component) {
+	seq := -1
+	for _, comp := range []*ical.Component{x, y} {
+		if p := comp.Props.Get(ical.PropSequence); p != nil {
+			if n, err := p.Int(); err == nil && n > seq {
+				seq = n
+			}
+		}
+	}
+	if seq >= 0 {
+		setRawProp(x, ical.PropSequence, strconv.Itoa(seq))
+	}
+}
+
+// mergeAlarms gives x the newer side's VALARMs, or y's when x has none.
+func mergeAlarms(x, y *ical.Component, yNewer bool) {
+	var xAlarms, yAlarms []*ical.Component
+	var rest []*ical.Component
+	for _, child := range x.Children {
+		if child.Name == ical.CompAlarm {
+			xAlarms = append(xAlarms, child)
+		} else {
+			rest = append(rest, child)
+		}
+	}
+	for _, child := range y.Children {
+		if child.Name == ical.CompAlarm {
+			yAlarms = append(yAlarms, child)
+		}
+	}
+	if len(yAlarms) == 0 || (len(xAlarms) > 0 && !yNewer) {
+		return
+	}
+	x.Children = append(rest, yAlarms...)
+}
+
+// isMergeConflict reports whether both sides of an event changed since
+// the last sync, which is when the merge strategy merges instead of
+// copying source over destination. Without recorded ETags for both
+// sides there is nothing to compare against and it is not a conflict.
+func isMergeConflict(sourceETag, destETag string, prev *db.SyncedEvent) bool {
+	if prev == nil || prev.SourceETag == "" || prev.DestETag == "" {
+		return false
+	}
+	return prev.SourceETag != sourceETag && prev.DestETag != destETag
+}
+
+// writeMergedEvent resolves a two-way conflict under the merge
+// strategy: it merges both versions, writes the result to the
+// destination and back to the source, and returns the ETags to track.
+// If the versions can't be merged the source version is written to the
+// destination instead, as with source_wins. ok is false when the
+// destination write failed and nothing should be recorded.
+func (se *SyncEngine) writeMergedEvent(ctx context.Context, source *db.Source, sourceClient, destClient *Client,
+	sourceCalendarPath, destCalendarPath string, sourceEvent, destEvent Event, result *SyncResult) (entry syncETagEntry, ok bool) {
+	merged, err := mergeEvents(sourceEvent.Data, destEvent.Data)
+	writeBack := err == nil
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Event %s: could not merge, keeping source version: %v",
+			sourceEvent.UID, err))
+		merged = sourceEvent.Data
+	}
+
+	toDest := sourceEvent
+	toDest.Data = merged
+	toDest.Path = destEvent.Path
+	if err := destClient.PutEvent(ctx, destCalendarPath, &toDest); err != nil {
+		if errors.Is(err, ErrEventSkipped) {
+			result.Skipped++
+		} else {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to update event on dest: %v", err))
+		}
+		return syncETagEntry{}, false
+	}
+	result.Updated++
+	verifyWrite(ctx, source, destClient, destCalendarPath, &toDest, result)
+
+	sourceETag := sourceEvent.ETag
+	if writeBack {
+		toSource := sourceEvent
+		toSource.Data = merged
+		if err := sourceClient.PutEvent(ctx, sourceCalendarPath, &toSource); err != nil {
+			// A read-only source keeps its version; the destination
+			// still gets the merge.
+			if !isForbiddenError(err) && !errors.Is(err, ErrEventSkipped) {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to write merged event to source: %v", err))
+			}
+		} else {
+			verifyWrite(ctx, source, sourceClient, sourceCalendarPath, &toSource, result)
+			sourceETag = currentETag(ctx, sourceClient, toSource.Path, sourceETag)
+		}
+		result.Warnings = append(result.Warnings, fmt.Sprintf(
+			"CONFLICT:{\"uid\":%q,\"winner\":\"merge\",\"summary\":%q,\"strategy\":%q}",
+			sourceEvent.UID, sourceEvent.Summary, source.ConflictStrategy))
+	}
+
+	// Both resources were just rewritten. Without their new ETags the
+	// next cycle would see both sides changed again and merge forever.
+	return syncETagEntry{
+		sourceETag: sourceETag,
+		destETag:   currentETag(ctx, destClient, toDest.Path, destEvent.ETag),
+	}, true
+}
+
+// currentETag reads path's ETag back from the server, returning
+// fallback if that fails or in dry-run mode (nothing was written).
+func currentETag(ctx context.Context, client *Client, path, fallback string) string {
+	if path == "" || IsDryRun(ctx) {
+		return fallback
+	}
+	event, err := client.GetEvent(ctx, path)
+	if err != nil || event.ETag == "" {
+		log.Printf("Could not refresh ETag for %s: %v", path, err)
+		return fallback
+	}
+	return event.ETag
+}
@@ -0,0 +1,438 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/emersion/go-ical"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// mergeICS wraps VEVENT bodies (without BEGIN/END lines) in a calendar.
+func mergeICS(events ...string) string {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n")
+	for _, ev := range events {
+		b.WriteString("BEGIN:VEVENT\r\n")
+		b.WriteString(ev)
+		b.WriteString("END:VEVENT\r\n")
+	}
+	b.WriteString("END:VCALENDAR\r\n")
+	return b.String()
+}
+
+// mergedMaster parses a mergeEvents result and returns its master VEVENT.
+func mergedMaster(t *testing.T, data string) *ical.Component {
+	t.Helper()
+	cal, err := parseICalendar(data)
+	if err != nil {
+		t.Fatalf("merged output does not parse: %v\n%s", err, data)
+	}
+	for _, comp := range cal.Children {
+		if comp.Name == ical.CompEvent && comp.Props.Get(ical.PropRecurrenceID) == nil {
+			return comp
+		}
+	}
+	t.Fatalf("no master VEVENT in merged output:\n%s", data)
+	return nil
+}
+
+func TestMergeEvents_FillsEmptyFields(t *testing.T) {
+	a := mergeICS("UID:m1\r\nDTSTAMP:20260101T000000Z\r\nLAST-MODIFIED:20260102T000000Z\r\n" +
+		"DTSTART:20260105T100000Z\r\nSUMMARY:Standup\r\nDESCRIPTION:\r\n")
+	b := mergeICS("UID:m1\r\nDTSTAMP:20260101T000000Z\r\nLAST-MODIFIED:20260101T000000Z\r\n" +
+		"DTSTART:20260105T100000Z\r\nSUMMARY:Old standup\r\nDESCRIPTION:Agenda in the doc\r\nLOCATION:Room 4\r\n")
+
+	merged, err := mergeEvents(a, b)
+	if err != nil {
+		t.Fatalf("mergeEvents: %v", err)
+	}
+	ev := mergedMaster(t, merged)
+	if got := propValue(ev, ical.PropSummary); got != "Standup" {
+		t.Errorf("SUMMARY = %q, want the newer side's %q", got, "Standup")
+	}
+	if got := propValue(ev, ical.PropDescription); got != "Agenda in the doc" {
+		t.Errorf("DESCRIPTION = %q, want it filled from the older side", got)
+	}
+	if got := propValue(ev, ical.PropLocation); got != "Room 4" {
+		t.Errorf("LOCATION = %q, want it kept from the only side that has it", got)
+	}
+}
+
+func TestMergeEvents_NewerScheduleWins(t *testing.T) {
+	a := mergeICS("UID:m2\r\nDTSTAMP:20260101T000000Z\r\nLAST-MODIFIED:20260101T000000Z\r\n" +
+		"DTSTART:20260105T100000Z\r\nDTEND:20260105T110000Z\r\nRRULE:FREQ=WEEKLY\r\nSUMMARY:Review\r\nLOCATION:Room 1\r\n")
+	b := mergeICS("UID:m2\r\nDTSTAMP:20260101T000000Z\r\nLAST-MODIFIED:20260103T000000Z\r\n" +
+		"DTSTART:20260106T140000Z\r\nDURATION:PT30M\r\nSUMMARY:Review\r\nLOCATION:Room 2\r\n")
+
+	merged, err := mergeEvents(a, b)
+	if err != nil {
+		t.Fatalf("mergeEvents: %v", err)
+	}
+	ev := mergedMaster(t, merged)
+	if got := propValue(ev, ical.PropDateTimeStart); got != "20260106T140000Z" {
+		t.Errorf("DTSTART = %q, want the newer side's", got)
+	}
+	// The schedule moves as a block: a's DTEND and RRULE don't survive
+	// next to b's DTSTART and DURATION.
+	for _, name := range []string{ical.PropDateTimeEnd, ical.PropRecurrenceRule} {
+		if got := propValue(ev, name); got != "" {
+			t.Errorf("%s = %q, want it dropped with the older schedule", name, got)
+		}
+	}
+	if got := propValue(ev, ical.PropDuration); got != "PT30M" {
+		t.Errorf("DURATION = %q, want PT30M", got)
+	}
+	if got := propValue(ev, ical.PropLocation); got != "Room 2" {
+		t.Errorf("LOCATION = %q, want the newer side's when both are set", got)
+	}
+	if got := propValue(ev, ical.PropLastModified); got != "20260103T000000Z" {
+		t.Errorf("LAST-MODIFIED = %q, want the newer side's", got)
+	}
+}
+
+func TestMergeEvents_TieGoesToFirst(t *testing.T) {
+	a := mergeICS("UID:m3\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:From A\r\n")
+	b := mergeICS("UID:m3\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T120000Z\r\nSUMMARY:From B\r\n")
+
+	merged, err := mergeEvents(a, b)
+	if err != nil {
+		t.Fatalf("mergeEvents: %v", err)
+	}
+	ev := mergedMaster(t, merged)
+	if got := propValue(ev, ical.PropSummary); got != "From A" {
+		t.Errorf("SUMMARY = %q, want a's on a tie", got)
+	}
+	if got := propValue(ev, ical.PropDateTimeStart); got != "20260105T100000Z" {
+		t.Errorf("DTSTART = %q, want a's on a tie", got)
+	}
+}
+
+func TestMergeEvents_AttendeesCategoriesSequence(t *testing.T) {
+	a := mergeICS("UID:m4\r\nDTSTAMP:20260101T000000Z\r\nLAST-MODIFIED:20260101T000000Z\r\n" +
+		"DTSTART:20260105T100000Z\r\nSEQUENCE:4\r\nCATEGORIES:Work,Planning\r\n" +
+		"ATTENDEE;PARTSTAT=NEEDS-ACTION:mailto:alice@example.com\r\n" +
+		"ATTENDEE;PARTSTAT=ACCEPTED:mailto:bob@example.com\r\n")
+	b := mergeICS("UID:m4\r\nDTSTAMP:20260101T000000Z\r\nLAST-MODIFIED:20260102T000000Z\r\n" +
+		"DTSTART:20260105T100000Z\r\nSEQUENCE:2\r\nCATEGORIES:work,Travel\r\n" +
+		"ATTENDEE;PARTSTAT=ACCEPTED:MAILTO:Alice@Example.com\r\n" +
+		"ATTENDEE;PARTSTAT=TENTATIVE:mailto:carol@example.com\r\n")
+
+	merged, err := mergeEvents(a, b)
+	if err != nil {
+		t.Fatalf("mergeEvents: %v", err)
+	}
+	ev := mergedMaster(t, merged)
+
+	partstat := make(map[string]string)
+	for _, p := range ev.Props.Values(ical.PropAttendee) {
+		partstat[attendeeAddress(p.Value)] = p.Params.Get(ical.ParamParticipationStatus)
+	}
+	want := map[string]string{
+		"alice@example.com": "ACCEPTED", // newer side's reply
+		"bob@example.com":   "ACCEPTED",
+		"carol@example.com": "TENTATIVE",
+	}
+	if len(partstat) != len(want) || len(ev.Props.Values(ical.PropAttendee)) != len(want) {
+		t.Fatalf("attendees = %v, want %v", partstat, want)
+	}
+	for addr, ps := range want {
+		if partstat[addr] != ps {
+			t.Errorf("attendee %s PARTSTAT = %q, want %q", addr, partstat[addr], ps)
+		}
+	}
+
+	categories, err := ev.Props.Get(ical.PropCategories).TextList()
+	if err != nil {
+		t.Fatalf("CATEGORIES: %v", err)
+	}
+	if got := strings.Join(categories, ","); got != "Work,Planning,Travel" {
+		t.Errorf("CATEGORIES = %q, want the case-insensitive union", got)
+	}
+	if got := propValue(ev, ical.PropSequence); got != "4" {
+		t.Errorf("SEQUENCE = %q, want the higher of the two", got)
+	}
+}
+
+func TestMergeEvents_Alarms(t *testing.T) {
+	alarm := "BEGIN:VALARM\r\nACTION:DISPLAY\r\nTRIGGER:-PT%dM\r\nDESCRIPTION:Reminder\r\nEND:VALARM\r\n"
+	tests := []struct {
+		name        string
+		aAlarm      string
+		bAlarm      string
+		bModified   string
+		wantTrigger string
+	}{
+		{"older side only", fmt.Sprintf(alarm, 10), "", "20260102T000000Z", "-PT10M"},
+		{"newer side replaces", fmt.Sprintf(alarm, 10), fmt.Sprintf(alarm, 30), "20260102T000000Z", "-PT30M"},
+		{"older side kept when newer has none", "", fmt.Sprintf(alarm, 30), "20251231T000000Z", "-PT30M"},
+		{"newer side's kept over older", fmt.Sprintf(alarm, 10), fmt.Sprintf(alarm, 30), "20251231T000000Z", "-PT10M"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := mergeICS("UID:m5\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\n" + tt.aAlarm)
+			b := mergeICS("UID:m5\r\nDTSTAMP:20260101T000000Z\r\nLAST-MODIFIED:" + tt.bModified +
+				"\r\nDTSTART:20260105T100000Z\r\n" + tt.bAlarm)
+			merged, err := mergeEvents(a, b)
+			if err != nil {
+				t.Fatalf("mergeEvents: %v", err)
+			}
+			var triggers []string
+			for _, child := range mergedMaster(t, merged).Children {
+				if child.Name == ical.CompAlarm {
+					triggers = append(triggers, propValue(child, ical.PropTrigger))
+				}
+			}
+			if len(triggers) != 1 || triggers[0] != tt.wantTrigger {
+				t.Errorf("alarm triggers = %v, want [%s]", triggers, tt.wantTrigger)
+			}
+		})
+	}
+}
+
+func TestMergeEvents_KeepsOverridesAndTimezones(t *testing.T) {
+	vtimezone := "BEGIN:VTIMEZONE\r\nTZID:Europe/Berlin\r\nBEGIN:STANDARD\r\nDTSTART:19701025T030000\r\n" +
+		"TZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\nEND:STANDARD\r\nEND:VTIMEZONE\r\n"
+	a := mergeICS("UID:m6\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nRRULE:FREQ=DAILY\r\nSUMMARY:Daily\r\n")
+	b := strings.Replace(mergeICS(
+		"UID:m6\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nRRULE:FREQ=DAILY\r\nSUMMARY:Daily\r\n",
+		"UID:m6\r\nDTSTAMP:20260101T000000Z\r\nRECURRENCE-ID:20260107T100000Z\r\n"+
+			"DTSTART;TZID=Europe/Berlin:20260107T150000\r\nSUMMARY:Daily (moved)\r\n",
+	), "PRODID:-//test//EN\r\n", "PRODID:-//test//EN\r\n"+vtimezone, 1)
+
+	merged, err := mergeEvents(a, b)
+	if err != nil {
+		t.Fatalf("mergeEvents: %v", err)
+	}
+	cal, err := parseICalendar(merged)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	var events, timezones int
+	var override *ical.Component
+	for _, comp := range cal.Children {
+		switch comp.Name {
+		case ical.CompEvent:
+			events++
+			if comp.Props.Get(ical.PropRecurrenceID) != nil {
+				override = comp
+			}
+		case ical.CompTimezone:
+			timezones++
+		}
+	}
+	if events != 2 || override == nil {
+		t.Fatalf("expected the master plus b's override, got %d VEVENTs:\n%s", events, merged)
+	}
+	if got := propValue(override, ical.PropSummary); got != "Daily (moved)" {
+		t.Errorf("override SUMMARY = %q", got)
+	}
+	if timezones != 1 {
+		t.Errorf("expected b's VTIMEZONE to be carried over, got %d", timezones)
+	}
+}
+
+func TestMergeEvents_Errors(t *testing.T) {
+	valid := mergeICS("UID:m7\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\n")
+	if _, err := mergeEvents(valid, "not a calendar"); !errors.Is(err, ErrMalformedContent) {
+		t.Errorf("expected ErrMalformedContent for an unparsable side, got %v", err)
+	}
+	override := mergeICS("UID:m7\r\nDTSTAMP:20260101T000000Z\r\nRECURRENCE-ID:20260106T100000Z\r\nDTSTART:20260106T100000Z\r\n")
+	if _, err := mergeEvents(valid, override); err == nil {
+		t.Error("expected an error when the versions share no VEVENT")
+	}
+}
+
+// calendarStore is a minimal in-memory CalDAV collection: REPORT lists
+// every resource with its data, GET, PUT and DELETE read, write and
+// remove them, and each PUT assigns a new ETag; a PUT with
+// If-None-Match: * fails with 412 on an existing resource. ETags are
+// kept unquoted, the way the client reports them.
+type calendarStore struct {
+	mu      sync.Mutex
+	data    map[string]string
+	etags   map[string]string
+	version int
+	puts    int
+}
+
+func newCalendarStore() *calendarStore {
+	return &calendarStore{data: make(map[string]string), etags: make(map[string]string)}
+}
+
+func (s *calendarStore) set(path, data string) string {
+	s.version++
+	s.data[path] = data
+	s.etags[path] = fmt.Sprintf("v%d", s.version)
+	return s.etags[path]
+}
+
+func (s *calendarStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.Method {
+	case "REPORT":
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusMultiStatus)
+		var b strings.Builder
+		b.WriteString(`<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">`)
+		for path, data := range s.data {
+			fmt.Fprintf(&b, `<d:response><d:href>%s</d:href><d:propstat><d:prop><d:getetag>%s</d:getetag>`+
+				`<C:calendar-data>%s</C:calendar-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`,
+				path, html.EscapeString(`"`+s.etags[path]+`"`), html.EscapeString(data))
+		}
+		b.WriteString(`</d:multistatus>`)
+		_, _ = w.Write([]byte(b.String()))
+	case http.MethodGet:
+		data, ok := s.data[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar")
+		w.Header().Set("ETag", `"`+s.etags[r.URL.Path]+`"`)
+		_, _ = w.Write([]byte(data))
+	case http.MethodPut:
+		if _, exists := s.data[r.URL.Path]; exists && r.Header.Get("If-None-Match") == "*" {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		s.puts++
+		w.Header().Set("ETag", `"`+s.set(r.URL.Path, string(body))+`"`)
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		if _, ok := s.data[r.URL.Path]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(s.data, r.URL.Path)
+		delete(s.etags, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusForbidden)
+	}
+}
+
+func TestSyncEventsToDestination_MergeConflict(t *testing.T) {
+	srcStore, destStore := newCalendarStore(), newCalendarStore()
+	srcSrv := httptest.NewServer(srcStore)
+	defer srcSrv.Close()
+	destSrv := httptest.NewServer(destStore)
+	defer destSrv.Close()
+
+	// Since the last sync the source renamed the event and the
+	// destination added a description.
+	srcETag := srcStore.set("/cal/standup.ics", mergeICS("UID:standup@example.com\r\nDTSTAMP:20260101T000000Z\r\n"+
+		"LAST-MODIFIED:20260103T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:Daily standup\r\n"))
+	destStore.set("/dest/standup.ics", mergeICS("UID:standup@example.com\r\nDTSTAMP:20260101T000000Z\r\n"+
+		"LAST-MODIFIED:20260102T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:Standup\r\nDESCRIPTION:Dial-in in the invite\r\n"))
+
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	user, err := database.GetOrCreateUser("merge@example.com", "Merge")
+	if err != nil {
+		t.Fatalf("GetOrCreateUser: %v", err)
+	}
+	source := &db.Source{
+		UserID:           user.ID,
+		Name:             "Merged",
+		SourceType:       db.SourceTypeCustom,
+		SourceURL:        srcSrv.URL + "/cal/",
+		SourceUsername:   "user",
+		SourcePassword:   "encrypted",
+		DestURL:          destSrv.URL + "/dest/",
+		DestUsername:     "dest",
+		DestPassword:     "encrypted",
+		SyncInterval:     3600,
+		SyncDirection:    db.SyncDirectionTwoWay,
+		ConflictStrategy: db.ConflictMerge,
+		Enabled:          true,
+	}
+	if err := database.CreateSource(source); err != nil {
+		t.Fatalf("CreateSource: %v", err)
+	}
+	if err := database.UpsertSyncedEvent(&db.SyncedEvent{
+		SourceID:     source.ID,
+		CalendarHref: "/cal/",
+		EventUID:     "standup@example.com",
+		SourceETag:   "old-src",
+		DestETag:     "old-dest",
+	}); err != nil {
+		t.Fatalf("UpsertSyncedEvent: %v", err)
+	}
+
+	sourceClient, err := NewClient(srcSrv.URL+"/cal/", "user", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	destClient, err := NewClient(destSrv.URL+"/dest/", "dest", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	se := NewSyncEngine(database, nil)
+	runSync := func(etag string) *SyncResult {
+		srcStore.mu.Lock()
+		events := []Event{{
+			Path: "/cal/standup.ics",
+			ETag: etag,
+			UID:  "standup@example.com",
+			Data: srcStore.data["/cal/standup.ics"],
+		}}
+		srcStore.mu.Unlock()
+		return se.syncEventsToDestination(context.Background(), source, sourceClient, destClient, events,
+			Calendar{Path: "/cal/", Name: "Cal"}, 1, db.SyncDirectionTwoWay)
+	}
+
+	result := runSync(srcETag)
+	if result.Updated != 1 {
+		t.Fatalf("expected one merged update, got %d (warnings %v)", result.Updated, result.Warnings)
+	}
+	conflicts := 0
+	for _, w := range result.Warnings {
+		if strings.HasPrefix(w, "CONFLICT:") && strings.Contains(w, `"winner":"merge"`) {
+			conflicts++
+		}
+	}
+	if conflicts != 1 {
+		t.Errorf("expected one merge CONFLICT warning, got %v", result.Warnings)
+	}
+
+	for name, store := range map[string]*calendarStore{"source": srcStore, "dest": destStore} {
+		var data string
+		for _, d := range store.data {
+			data = d
+		}
+		ev := mergedMaster(t, data)
+		if got := propValue(ev, ical.PropSummary); got != "Daily standup" {
+			t.Errorf("%s SUMMARY = %q, want the newer source title", name, got)
+		}
+		if got := propValue(ev, ical.PropDescription); got != "Dial-in in the invite" {
+			t.Errorf("%s DESCRIPTION = %q, want the destination's description", name, got)
+		}
+	}
+
+	// The ETags recorded for the next cycle are the ones the merge
+	// writes produced, so an untouched event is left alone next time.
+	srcStore.mu.Lock()
+	newSrcETag := srcStore.etags["/cal/standup.ics"]
+	srcStore.mu.Unlock()
+	srcPuts, destPuts := srcStore.puts, destStore.puts
+	result = runSync(newSrcETag)
+	if result.Updated != 0 || srcStore.puts != srcPuts || destStore.puts != destPuts {
+		t.Errorf("expected a quiet second sync, got %d updates and %d/%d new PUTs (warnings %v)",
+			result.Updated, srcStore.puts-srcPuts, destStore.puts-destPuts, result.Warnings)
+	}
+}
@@ -0,0 +1,150 @@
+package caldav
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// generatedUIDPrefix marks UIDs synthesized for events that arrived
+// without one, so the engine can tell them apart after the fetch.
+const generatedUIDPrefix = "calbridgesync-gen-"
+
+// missingUIDContextKey, when set on the context passed to GetEvents,
+// GetEvent or FetchEvents, makes them synthesize UIDs for UID-less
+// entries instead of dropping them. Only source fetches set it:
+// destination events are never rewritten.
+type missingUIDContextKeyType struct{}
+
+var missingUIDContextKey = missingUIDContextKeyType{}
+
+// withMissingUIDGeneration returns a context under which fetches give
+// UID-less events a generated UID.
+func withMissingUIDGeneration(ctx context.Context) context.Context {
+	return context.WithValue(ctx, missingUIDContextKey, true)
+}
+
+// generatesMissingUIDs reports whether ctx asks for generated UIDs.
+func generatesMissingUIDs(ctx context.Context) bool {
+	v, _ := ctx.Value(missingUIDContextKey).(bool)
+	return v
+}
+
+// sourceFetchContext returns the context to fetch source's events
+// under: with UID generation when the source has GenerateMissingUIDs
+// set, and capped at InitialSyncEventCap on a calendar's first sync.
+func sourceFetchContext(ctx context.Context, source *db.Source) context.Context {
+	if source.GenerateMissingUIDs {
+		ctx = withMissingUIDGeneration(ctx)
+	}
+	if source.InitialSyncEventCap > 0 && isInitialSync(ctx) {
+		ctx = withEventCap(ctx, source.InitialSyncEventCap)
+	}
+	return ctx
+}
+
+// syntheticUID derives a UID from an entry's SUMMARY, DTSTART and
+// LOCATION. The same content always yields the same UID, so an event
+// without one matches itself across sync cycles instead of being
+// re-created; editing any of the three fields makes it a new event.
+func syntheticUID(comp *ical.Component) string {
+	h := sha256.New()
+	for _, name := range []string{ical.PropSummary, ical.PropDateTimeStart, ical.PropLocation} {
+		value := ""
+		if prop := comp.Props.Get(name); prop != nil {
+			value = prop.Value
+		}
+		fmt.Fprintf(h, "%s\x00", value)
+	}
+	return generatedUIDPrefix + hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// isGeneratedUID reports whether uid was produced by syntheticUID.
+func isGeneratedUID(uid string) bool {
+	return strings.HasPrefix(uid, generatedUIDPrefix)
+}
+
+// fillMissingUIDs gives every VEVENT and VJOURNAL in cal that lacks a
+// UID a synthesized one. A calendar object is a single event, so all
+// of its UID-less entries share the UID derived from the master (the
+// entry without RECURRENCE-ID), keeping overrides with their series.
+// It reports whether anything was filled in.
+func fillMissingUIDs(cal *ical.Calendar) bool {
+	var missing []*ical.Component
+	for _, child := range cal.Children {
+		if child.Name != ical.CompEvent && child.Name != ical.CompJournal {
+			continue
+		}
+		if uid, _ := child.Props.Text(ical.PropUID); uid == "" {
+			missing = append(missing, child)
+		}
+	}
+	if len(missing) == 0 {
+		return false
+	}
+
+	master := missing[0]
+	for _, comp := range missing {
+		if comp.Props.Get(ical.PropRecurrenceID) == nil {
+			master = comp
+			break
+		}
+	}
+	uid := syntheticUID(master)
+	for _, comp := range missing {
+		comp.Props.SetText(ical.PropUID, uid)
+	}
+	return true
+}
+
+// fillObjectUIDs applies fillMissingUIDs to fetched calendar objects
+// when ctx asks for generated UIDs.
+func fillObjectUIDs(ctx context.Context, objects []caldav.CalendarObject) {
+	if !generatesMissingUIDs(ctx) {
+		return
+	}
+	for _, obj := range objects {
+		if obj.Data != nil {
+			fillMissingUIDs(obj.Data)
+		}
+	}
+}
+
+// fillDataUID is fillMissingUIDs for raw iCalendar data, as the
+// WebDAV-Sync path receives it. Data that already has a UID, or that
+// does not parse, is returned unchanged.
+func fillDataUID(data string) (string, bool) {
+	if data == "" || icsUID(data) != "" {
+		return data, false
+	}
+	cal, err := parseICalendar(data)
+	if err != nil || !fillMissingUIDs(cal) {
+		return data, false
+	}
+	filled, err := encodeCalendar(cal)
+	if err != nil {
+		return data, false
+	}
+	return filled, true
+}
+
+// generatedUIDWarning summarizes the events in events that were given
+// a generated UID, or returns "" if there are none.
+func generatedUIDWarning(events []Event, calendarPath string) string {
+	n := 0
+	for _, e := range events {
+		if isGeneratedUID(e.UID) {
+			n++
+		}
+	}
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d event(s) in %s have no UID; synced under generated UIDs derived from summary, start and location", n, calendarPath)
+}
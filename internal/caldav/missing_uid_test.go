@@ -0,0 +1,136 @@
+package caldav
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emersion/go-ical"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+const uidlessEvent = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//legacy//EN\r\nBEGIN:VEVENT\r\n" +
+	"DTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:Legacy review\r\n" +
+	"LOCATION:Room 4\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+// uidlessServer serves one calendar holding a single UID-less event.
+// REPORT is refused so the client lists the calendar and GETs it.
+func uidlessServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PROPFIND":
+			var b strings.Builder
+			b.WriteString(`<?xml version="1.0" encoding="utf-8"?><D:multistatus xmlns:D="DAV:">`)
+			b.WriteString(collectionResponse("/cal/"))
+			b.WriteString(`<D:response><D:href>/cal/legacy.ics</D:href><D:propstat><D:prop>` +
+				`<D:getcontenttype>text/calendar</D:getcontenttype><D:resourcetype/>` +
+				`</D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat></D:response>`)
+			b.WriteString(`</D:multistatus>`)
+			w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+			w.WriteHeader(http.StatusMultiStatus)
+			_, _ = w.Write([]byte(b.String()))
+		case "REPORT":
+			w.WriteHeader(http.StatusNotImplemented)
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "text/calendar")
+			_, _ = w.Write([]byte(uidlessEvent))
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+}
+
+func TestGetEvents_GenerateMissingUIDs(t *testing.T) {
+	srv := uidlessServer(t)
+	defer srv.Close()
+	client, err := NewClient(srv.URL+"/cal/", "user", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	events, err := client.GetEvents(context.Background(), "/cal/", nil)
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("expected the UID-less event to be skipped by default, got %v", eventUIDs(events))
+	}
+
+	ctx := sourceFetchContext(context.Background(), &db.Source{GenerateMissingUIDs: true})
+	var uids []string
+	for cycle := 0; cycle < 2; cycle++ {
+		events, err := client.GetEvents(ctx, "/cal/", nil)
+		if err != nil {
+			t.Fatalf("GetEvents: %v", err)
+		}
+		if len(events) != 1 || !isGeneratedUID(events[0].UID) {
+			t.Fatalf("expected one event with a generated UID, got %v", eventUIDs(events))
+		}
+		if icsUID(events[0].Data) != events[0].UID {
+			t.Errorf("expected the generated UID in the event data, got %q", icsUID(events[0].Data))
+		}
+		uids = append(uids, events[0].UID)
+	}
+	if uids[0] != uids[1] {
+		t.Errorf("expected the same UID on every sync, got %v", uids)
+	}
+
+	if msg := generatedUIDWarning([]Event{{UID: "real"}}, "/cal/"); msg != "" {
+		t.Errorf("expected no warning for events with real UIDs, got %q", msg)
+	}
+	events, _ = client.GetEvents(ctx, "/cal/", nil)
+	if msg := generatedUIDWarning(events, "/cal/"); !strings.Contains(msg, "1 event(s) in /cal/") {
+		t.Errorf("expected a warning for the generated UID, got %q", msg)
+	}
+}
+
+func TestFillMissingUIDs(t *testing.T) {
+	t.Run("overrides share the master's UID", func(t *testing.T) {
+		data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//legacy//EN\r\n" +
+			"BEGIN:VEVENT\r\nDTSTAMP:20260101T000000Z\r\nRECURRENCE-ID:20260112T100000Z\r\n" +
+			"DTSTART:20260112T110000Z\r\nSUMMARY:Moved\r\nEND:VEVENT\r\n" +
+			"BEGIN:VEVENT\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\n" +
+			"RRULE:FREQ=WEEKLY\r\nSUMMARY:Weekly\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+		cal, err := parseICalendar(data)
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		if !fillMissingUIDs(cal) {
+			t.Fatal("expected UIDs to be filled in")
+		}
+		override, _ := cal.Children[0].Props.Text(ical.PropUID)
+		master, _ := cal.Children[1].Props.Text(ical.PropUID)
+		if override != master || master != syntheticUID(cal.Children[1]) {
+			t.Errorf("expected both entries under the master's UID, got %q and %q", override, master)
+		}
+	})
+
+	t.Run("content changes the UID", func(t *testing.T) {
+		a, _ := parseICalendar(uidlessEvent)
+		b, _ := parseICalendar(strings.Replace(uidlessEvent, "Room 4", "Room 5", 1))
+		fillMissingUIDs(a)
+		fillMissingUIDs(b)
+		uidA, _ := a.Children[0].Props.Text(ical.PropUID)
+		uidB, _ := b.Children[0].Props.Text(ical.PropUID)
+		if uidA == uidB {
+			t.Error("expected a different location to give a different UID")
+		}
+	})
+
+	t.Run("raw data", func(t *testing.T) {
+		filled, ok := fillDataUID(uidlessEvent)
+		if !ok || !isGeneratedUID(icsUID(filled)) {
+			t.Fatalf("expected a generated UID, got %q", icsUID(filled))
+		}
+		again, _ := fillDataUID(uidlessEvent)
+		if icsUID(again) != icsUID(filled) {
+			t.Errorf("expected a stable UID, got %q and %q", icsUID(filled), icsUID(again))
+		}
+		if got, ok := fillDataUID(filled); ok || got != filled {
+			t.Error("expected data with a UID to pass through unchanged")
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package caldav
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// ErrMkcalendarUnsupported is returned by MakeCalendar when the server
+// refuses to create calendars at all, as opposed to failing this one.
+var ErrMkcalendarUnsupported = errors.New("server does not support MKCALENDAR")
+
+// mkcalendarRejectionCodes are the statuses a server answers MKCALENDAR
+// with when it does not support it or doesn't let this user use it.
+var mkcalendarRejectionCodes = map[int]bool{
+	http.StatusForbidden:        true,
+	http.StatusMethodNotAllowed: true,
+	http.StatusNotImplemented:   true,
+}
+
+// defaultDestCalendarSlug names the collection MakeCalendar creates
+// when the source calendar's path has no usable last segment.
+const defaultDestCalendarSlug = "calbridgesync"
+
+// calendarHomeSet returns the path of the user's calendar home, where
+// their calendars live.
+func (c *Client) calendarHomeSet(ctx context.Context) (string, error) {
+	principal, err := c.findCurrentUserPrincipal(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to find principal: %w", ErrConnectionFailed, err)
+	}
+	homeSet, err := c.caldavClient.FindCalendarHomeSet(ctx, principal)
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to find home set: %w", ErrConnectionFailed, err)
+	}
+	return homeSet, nil
+}
+
+// MakeCalendar creates a calendar named name under the user's calendar
+// home, at <home>/<slug>/, and returns its path. color, a CSS hex
+// color, is set as the Apple calendar-color when not empty.
+func (c *Client) MakeCalendar(ctx context.Context, slug, name, color string) (string, error) {
+	homeSet, err := c.calendarHomeSet(ctx)
+	if err != nil {
+		return "", err
+	}
+	calendarPath := strings.TrimSuffix(homeSet, "/") + "/" + slug + "/"
+
+	var body bytes.Buffer
+	body.WriteString(`<?xml version="1.0" encoding="utf-8" ?>
+<C:mkcalendar xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav" xmlns:A="http://apple.com/ns/ical/">
+  <D:set>
+    <D:prop>
+      <D:displayname>`)
+	_ = xml.EscapeText(&body, []byte(name))
+	body.WriteString("</D:displayname>\n")
+	if color != "" {
+		body.WriteString("      <A:calendar-color>")
+		_ = xml.EscapeText(&body, []byte(color))
+		body.WriteString("</A:calendar-color>\n")
+	}
+	body.WriteString(`    </D:prop>
+  </D:set>
+</C:mkcalendar>`)
+
+	req, err := http.NewRequestWithContext(ctx, "MKCALENDAR", c.buildURL(calendarPath), &body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to create calendar: %w", ErrConnectionFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if mkcalendarRejectionCodes[resp.StatusCode] {
+		return "", fmt.Errorf("%w (status %d)", ErrMkcalendarUnsupported, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("%w: failed to create calendar: unexpected status %d", ErrInvalidResponse, resp.StatusCode)
+	}
+	return calendarPath, nil
+}
+
+type calendarColorMultistatus struct {
+	XMLName   xml.Name `xml:"DAV: multistatus"`
+	Responses []struct {
+		PropStats []struct {
+			Prop struct {
+				Color string `xml:"http://apple.com/ns/ical/ calendar-color"`
+			} `xml:"prop"`
+			Status string `xml:"status"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+const calendarColorRequest = `<?xml version="1.0" encoding="utf-8" ?>
+<D:propfind xmlns:D="DAV:" xmlns:A="http://apple.com/ns/ical/">
+  <D:prop>
+    <A:calendar-color/>
+  </D:prop>
+</D:propfind>`
+
+// CalendarColor reads a calendar's Apple calendar-color. It returns ""
+// when the server doesn't report one or the request fails: a color is
+// never worth failing a sync over.
+func (c *Client) CalendarColor(ctx context.Context, calendarPath string) string {
+	req, err := http.NewRequestWithContext(ctx, "PROPFIND", c.buildURL(calendarPath), strings.NewReader(calendarColorRequest))
+	if err != nil {
+		return ""
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		return ""
+	}
+	body, err := c.readBody(resp)
+	if err != nil {
+		return ""
+	}
+	var ms calendarColorMultistatus
+	if err := xml.Unmarshal(body, &ms); err != nil {
+		return ""
+	}
+	for _, r := range ms.Responses {
+		for _, ps := range r.PropStats {
+			if color := strings.TrimSpace(ps.Prop.Color); color != "" && strings.Contains(ps.Status, "200") {
+				return color
+			}
+		}
+	}
+	return ""
+}
+
+// SetCreateDestCalendar makes syncs create a calendar on destinations
+// that have none, instead of writing to the destination URL's own
+// path. Wired from SYNC_CREATE_DEST_CALENDAR; call before the
+// scheduler starts.
+func (se *SyncEngine) SetCreateDestCalendar(enabled bool) {
+	se.createDestCalendar = enabled
+}
+
+// destCalendarFallback returns the path to write calendar's events to
+// when discovery found no calendar on the destination. With
+// SetCreateDestCalendar on it creates one named and colored like
+// calendar; otherwise, in a dry run, or when the server won't create
+// it, it falls back to the destination URL's path.
+func (se *SyncEngine) destCalendarFallback(ctx context.Context, sourceClient, destClient *Client, calendar Calendar) string {
+	fallback := destClient.GetCalendarPath()
+	if !se.createDestCalendar {
+		log.Printf("No calendars found on destination, using URL path as fallback")
+		return fallback
+	}
+
+	name := calendar.Name
+	if name == "" {
+		name = "Calendar"
+	}
+	if IsDryRun(ctx) {
+		log.Printf("Dry run: would create destination calendar %q; using URL path meanwhile", name)
+		return fallback
+	}
+
+	slug := path.Base(strings.TrimSuffix(calendar.Path, "/"))
+	if slug == "" || slug == "." || slug == "/" {
+		slug = defaultDestCalendarSlug
+	}
+	color := calendar.Color
+	if color == "" && sourceClient != nil {
+		color = sourceClient.CalendarColor(ctx, calendar.Path)
+	}
+
+	created, err := destClient.MakeCalendar(ctx, slug, name, color)
+	if err != nil {
+		if errors.Is(err, ErrMkcalendarUnsupported) {
+			log.Printf("Destination can't create calendars (%v); using URL path as fallback", err)
+		} else {
+			log.Printf("Failed to create destination calendar %q, using URL path as fallback: %v", name, err)
+		}
+		return fallback
+	}
+	log.Printf("No calendars found on destination; created %q at %s", name, created)
+	return created
+}
@@ -0,0 +1,154 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// mkcalendarServer serves a DAV tree whose calendar home
+// (/dav/calendars/) holds no calendars, and answers MKCALENDAR with
+// status. PROPFINDs for a calendar-color under /dav/source/ report
+// #FF2968FF. It returns the MKCALENDAR requests it received as
+// "<path>\n<body>".
+func mkcalendarServer(t *testing.T, status int) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var made []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		multistatus := func(body string) {
+			w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+			w.WriteHeader(http.StatusMultiStatus)
+			_, _ = w.Write([]byte(`<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav" xmlns:a="http://apple.com/ns/ical/">` + body + `</d:multistatus>`))
+		}
+		path := strings.TrimSuffix(r.URL.Path, "/")
+		switch {
+		case r.Method == "MKCALENDAR":
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			made = append(made, r.URL.Path+"\n"+string(body))
+			mu.Unlock()
+			w.WriteHeader(status)
+		case r.Method != "PROPFIND":
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case strings.HasPrefix(path, "/dav/source"):
+			multistatus(`<d:response><d:href>` + r.URL.Path + `</d:href><d:propstat><d:prop>` +
+				`<a:calendar-color>#FF2968FF</a:calendar-color>` +
+				`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`)
+		case path == "/dav":
+			multistatus(`<d:response><d:href>/dav/</d:href><d:propstat><d:prop>` +
+				`<d:current-user-principal><d:href>/dav/principals/alice/</d:href></d:current-user-principal>` +
+				`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`)
+		case path == "/dav/principals/alice":
+			multistatus(`<d:response><d:href>/dav/principals/alice/</d:href><d:propstat><d:prop>` +
+				`<cal:calendar-home-set><d:href>/dav/calendars/</d:href></cal:calendar-home-set>` +
+				`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`)
+		case path == "/dav/calendars":
+			multistatus(`<d:response><d:href>/dav/calendars/</d:href><d:propstat><d:prop>` +
+				`<d:resourcetype><d:collection/></d:resourcetype>` +
+				`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), made...)
+	}
+}
+
+func TestDestCalendarFallback(t *testing.T) {
+	calendar := Calendar{Path: "/dav/source/work/", Name: "Work & Play"}
+	clients := func(t *testing.T, srv *httptest.Server) (*Client, *Client) {
+		t.Helper()
+		sourceClient, err := NewClient(srv.URL+"/dav/source/", "alice", "pass")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		destClient, err := NewClient(srv.URL+"/dav/", "alice", "pass")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		return sourceClient, destClient
+	}
+
+	t.Run("creates a calendar mirroring the source", func(t *testing.T) {
+		srv, made := mkcalendarServer(t, http.StatusCreated)
+		sourceClient, destClient := clients(t, srv)
+		se := NewSyncEngine(nil, nil)
+		se.SetCreateDestCalendar(true)
+
+		got := se.destCalendarFallback(context.Background(), sourceClient, destClient, calendar)
+
+		if got != "/dav/calendars/work/" {
+			t.Errorf("expected the new calendar's path, got %q", got)
+		}
+		requests := made()
+		if len(requests) != 1 {
+			t.Fatalf("expected one MKCALENDAR, got %d", len(requests))
+		}
+		for _, want := range []string{"/dav/calendars/work/\n", "<D:displayname>Work &amp; Play</D:displayname>", "<A:calendar-color>#FF2968FF</A:calendar-color>"} {
+			if !strings.Contains(requests[0], want) {
+				t.Errorf("expected the MKCALENDAR request to contain %q, got:\n%s", want, requests[0])
+			}
+		}
+	})
+
+	t.Run("falls back when the server rejects MKCALENDAR", func(t *testing.T) {
+		srv, made := mkcalendarServer(t, http.StatusMethodNotAllowed)
+		sourceClient, destClient := clients(t, srv)
+		se := NewSyncEngine(nil, nil)
+		se.SetCreateDestCalendar(true)
+
+		got := se.destCalendarFallback(context.Background(), sourceClient, destClient, calendar)
+
+		if got != destClient.GetCalendarPath() {
+			t.Errorf("expected the URL path fallback %q, got %q", destClient.GetCalendarPath(), got)
+		}
+		if len(made()) != 1 {
+			t.Errorf("expected MKCALENDAR to be tried once, got %d", len(made()))
+		}
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		srv, made := mkcalendarServer(t, http.StatusCreated)
+		sourceClient, destClient := clients(t, srv)
+
+		got := NewSyncEngine(nil, nil).destCalendarFallback(context.Background(), sourceClient, destClient, calendar)
+
+		if got != destClient.GetCalendarPath() || len(made()) != 0 {
+			t.Errorf("expected the URL path and no MKCALENDAR, got %q after %d", got, len(made()))
+		}
+	})
+
+	t.Run("dry run creates nothing", func(t *testing.T) {
+		srv, made := mkcalendarServer(t, http.StatusCreated)
+		sourceClient, destClient := clients(t, srv)
+		se := NewSyncEngine(nil, nil)
+		se.SetCreateDestCalendar(true)
+
+		got := se.destCalendarFallback(WithDryRun(context.Background()), sourceClient, destClient, calendar)
+
+		if got != destClient.GetCalendarPath() || len(made()) != 0 {
+			t.Errorf("expected the URL path and no MKCALENDAR, got %q after %d", got, len(made()))
+		}
+	})
+}
+
+func TestMakeCalendar_Unsupported(t *testing.T) {
+	srv, _ := mkcalendarServer(t, http.StatusForbidden)
+	client, err := NewClient(srv.URL+"/dav/", "alice", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := client.MakeCalendar(context.Background(), "work", "Work", ""); !errors.Is(err, ErrMkcalendarUnsupported) {
+		t.Errorf("expected ErrMkcalendarUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package caldav
+
+import (
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+// multiGetStatus finds the HTTP status at the start of a failed
+// go-webdav request's error ("501 Not Implemented: <body>").
+var multiGetStatus = regexp.MustCompile(`\b([45]\d\d) [A-Z]`)
+
+// multiGetRejectionCodes are the statuses a server answers a
+// calendar-multiget REPORT with when it does not support the report
+// at all, as opposed to failing this particular request.
+var multiGetRejectionCodes = map[int]bool{
+	http.StatusBadRequest:           true,
+	http.StatusForbidden:            true,
+	http.StatusMethodNotAllowed:     true,
+	http.StatusUnsupportedMediaType: true,
+	http.StatusUnprocessableEntity:  true,
+	http.StatusNotImplemented:       true,
+}
+
+// isMultiGetRejection reports whether err is the server refusing
+// calendar-multiget outright. Network errors, timeouts and other
+// statuses are not: the next batch tries multiget again.
+func isMultiGetRejection(err error) bool {
+	if err == nil {
+		return false
+	}
+	m := multiGetStatus.FindStringSubmatch(err.Error())
+	if m == nil {
+		return false
+	}
+	code, _ := strconv.Atoi(m[1])
+	return multiGetRejectionCodes[code]
+}
+
+// multiGetSupported reports whether getEventsViaList should try
+// calendar-multiget. Once the server has rejected it, the client goes
+// straight to individual GETs for as long as it lives, which with the
+// client cache spans sync cycles.
+func (c *Client) multiGetSupported() bool {
+	return c.multiGetRejected == nil || !c.multiGetRejected.Load()
+}
+
+// noteMultiGetFailure records a failed multiget batch, remembering
+// rejections so later batches don't repeat them.
+func (c *Client) noteMultiGetFailure(err error) {
+	if c.multiGetRejected == nil || !isMultiGetRejection(err) {
+		return
+	}
+	if !c.multiGetRejected.Swap(true) {
+		log.Printf("Server at %s rejected calendar-multiget (%v); using individual GETs", c.baseURL, err)
+	}
+}
@@ -0,0 +1,153 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// multiGetServer lists /cal/a.ics, b.ics and c.ics and answers
+// calendar-multiget with all three in one response, c.ics carrying
+// both DTEND and DURATION, which the encoder refuses. With reject set,
+// multiget gets a 400 instead. calendar-query returns nothing so the
+// client lists the collection.
+func multiGetServer(t *testing.T, reject bool) (srv *httptest.Server, multiGets, gets *atomic.Int32) {
+	t.Helper()
+	multiGets, gets = new(atomic.Int32), new(atomic.Int32)
+	event := func(uid string, encodable bool) string {
+		end := "DTEND:20260105T110000Z\r\n"
+		if !encodable {
+			end += "DURATION:PT1H\r\n"
+		}
+		return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VEVENT\r\nUID:" + uid + "\r\n" +
+			"DTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\n" + end +
+			"SUMMARY:" + uid + "\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	}
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		multistatus := func(body string) {
+			w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+			w.WriteHeader(http.StatusMultiStatus)
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?><D:multistatus xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">` +
+				body + `</D:multistatus>`))
+		}
+		switch r.Method {
+		case "PROPFIND":
+			var b strings.Builder
+			b.WriteString(collectionResponse("/cal/"))
+			for _, href := range []string{"/cal/a.ics", "/cal/b.ics", "/cal/c.ics"} {
+				fmt.Fprintf(&b, `<D:response><D:href>%s</D:href><D:propstat><D:prop>`+
+					`<D:getcontenttype>text/calendar</D:getcontenttype><D:resourcetype/>`+
+					`</D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat></D:response>`, href)
+			}
+			multistatus(b.String())
+		case "REPORT":
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), "calendar-multiget") {
+				multistatus("")
+				return
+			}
+			multiGets.Add(1)
+			if reject {
+				w.Header().Set("Content-Type", "text/plain")
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte("REPORT not supported"))
+				return
+			}
+			var b strings.Builder
+			for _, uid := range []string{"a", "b", "c"} {
+				fmt.Fprintf(&b, `<D:response><D:href>/cal/%s.ics</D:href><D:propstat><D:prop><D:getetag>"1"</D:getetag>`+
+					`<C:calendar-data>%s</C:calendar-data></D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat></D:response>`,
+					uid, html.EscapeString(event(uid, uid != "c")))
+			}
+			multistatus(b.String())
+		case http.MethodGet:
+			gets.Add(1)
+			uid := strings.TrimSuffix(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], ".ics")
+			w.Header().Set("Content-Type", "text/calendar")
+			_, _ = w.Write([]byte(event(uid, true)))
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	return srv, multiGets, gets
+}
+
+func TestGetEvents_MultiGetFetchesBatchInOneRequest(t *testing.T) {
+	srv, multiGets, gets := multiGetServer(t, false)
+	defer srv.Close()
+	client, err := NewClient(srv.URL+"/cal/", "user", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	collector := NewMalformedEventCollector()
+	events, err := client.GetEvents(context.Background(), "/cal/", collector)
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	uids := eventUIDs(events)
+	sort.Strings(uids)
+	if strings.Join(uids, ",") != "a,b" {
+		t.Errorf("expected events a and b, got %v", uids)
+	}
+	if multiGets.Load() != 1 || gets.Load() != 0 {
+		t.Errorf("expected one multiget and no GETs, got %d multigets and %d GETs", multiGets.Load(), gets.Load())
+	}
+	malformed := collector.GetEvents()
+	if len(malformed) != 1 || malformed[0].Path != "/cal/c.ics" {
+		t.Errorf("expected c.ics recorded as malformed, got %+v", malformed)
+	}
+}
+
+func TestGetEvents_MultiGetRejectedFallsBack(t *testing.T) {
+	srv, multiGets, gets := multiGetServer(t, true)
+	defer srv.Close()
+	client, err := NewClient(srv.URL+"/cal/", "user", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	for cycle := 1; cycle <= 2; cycle++ {
+		events, err := client.GetEvents(context.Background(), "/cal/", nil)
+		if err != nil {
+			t.Fatalf("GetEvents: %v", err)
+		}
+		if len(events) != 3 {
+			t.Errorf("cycle %d: expected all three events via GET, got %v", cycle, eventUIDs(events))
+		}
+	}
+	if multiGets.Load() != 1 {
+		t.Errorf("expected the rejected multiget not to be retried, got %d attempts", multiGets.Load())
+	}
+	if gets.Load() != 6 {
+		t.Errorf("expected 3 GETs per cycle, got %d", gets.Load())
+	}
+}
+
+func TestIsMultiGetRejection(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{errors.New("400 Bad Request: REPORT not supported"), true},
+		{errors.New("501 Not Implemented"), true},
+		{errors.New("MULTIGET failed: 405 Method Not Allowed: "), true},
+		{errors.New("503 Service Unavailable"), false},
+		{errors.New("401 Unauthorized"), false},
+		{errors.New("dial tcp: connection refused"), false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := isMultiGetRejection(tt.err); got != tt.want {
+			t.Errorf("isMultiGetRejection(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// nestedCalDAVServer serves a PROPFIND listing per collection path and
+// a VEVENT for any .ics GET. MULTIGET is refused so the client falls
+// back to individual GETs, keeping the fake small.
+func nestedCalDAVServer(t *testing.T, listings map[string][]string) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var propfinds []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PROPFIND":
+			mu.Lock()
+			propfinds = append(propfinds, r.URL.Path)
+			mu.Unlock()
+			children, ok := listings[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			var b strings.Builder
+			b.WriteString(`<?xml version="1.0" encoding="utf-8"?><D:multistatus xmlns:D="DAV:">`)
+			b.WriteString(collectionResponse(r.URL.Path))
+			for _, child := range children {
+				if strings.HasSuffix(child, "/") {
+					b.WriteString(collectionResponse(child))
+				} else {
+					fmt.Fprintf(&b, `<D:response><D:href>%s</D:href><D:propstat><D:prop>`+
+						`<D:getcontenttype>text/calendar</D:getcontenttype><D:resourcetype/>`+
+						`</D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat></D:response>`, child)
+				}
+			}
+			b.WriteString(`</D:multistatus>`)
+			w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+			w.WriteHeader(http.StatusMultiStatus)
+			_, _ = w.Write([]byte(b.String()))
+		case "REPORT":
+			w.WriteHeader(http.StatusNotImplemented)
+		case http.MethodGet:
+			uid := strings.TrimSuffix(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], ".ics")
+			w.Header().Set("Content-Type", "text/calendar")
+			fmt.Fprintf(w, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VEVENT\r\n"+
+				"UID:%s\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:%s\r\n"+
+				"END:VEVENT\r\nEND:VCALENDAR\r\n", uid, uid)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), propfinds...)
+	}
+}
+
+// collectionResponse is a multistatus entry for a collection. It claims
+// text/calendar on purpose: some servers label calendar collections
+// that way, and they must still not be mistaken for events.
+func collectionResponse(href string) string {
+	return fmt.Sprintf(`<D:response><D:href>%s</D:href><D:propstat><D:prop>`+
+		`<D:getcontenttype>text/calendar</D:getcontenttype><D:resourcetype><D:collection/></D:resourcetype>`+
+		`</D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat></D:response>`, href)
+}
+
+func eventUIDs(events []Event) []string {
+	uids := make([]string, 0, len(events))
+	for _, e := range events {
+		uids = append(uids, e.UID)
+	}
+	sort.Strings(uids)
+	return uids
+}
+
+func TestGetEventsViaList_NestedCollections(t *testing.T) {
+	t.Run("finds events one level down", func(t *testing.T) {
+		srv, _ := nestedCalDAVServer(t, map[string][]string{
+			"/cal/":       {"/cal/inner/"},
+			"/cal/inner/": {"/cal/inner/a.ics", "/cal/inner/b.ics"},
+		})
+		defer srv.Close()
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		events, err := client.getEventsViaList(context.Background(), "/cal/", NewMalformedEventCollector())
+		if err != nil {
+			t.Fatalf("getEventsViaList: %v", err)
+		}
+		if got := eventUIDs(events); strings.Join(got, ",") != "a,b" {
+			t.Errorf("expected events a and b from the sub-collection, got %v", got)
+		}
+	})
+
+	t.Run("top-level events keep sub-collections out", func(t *testing.T) {
+		srv, propfinds := nestedCalDAVServer(t, map[string][]string{
+			"/cal/":             {"/cal/top.ics", "/cal/attachments/"},
+			"/cal/attachments/": {"/cal/attachments/other.ics"},
+		})
+		defer srv.Close()
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		events, err := client.getEventsViaList(context.Background(), "/cal/", NewMalformedEventCollector())
+		if err != nil {
+			t.Fatalf("getEventsViaList: %v", err)
+		}
+		if got := eventUIDs(events); strings.Join(got, ",") != "top" {
+			t.Errorf("expected only the top-level event, got %v", got)
+		}
+		if n := len(propfinds()); n != 1 {
+			t.Errorf("expected a single PROPFIND, got %d: %v", n, propfinds())
+		}
+	})
+
+	t.Run("descent stops at the depth cap", func(t *testing.T) {
+		listings := map[string][]string{"/cal/": {"/cal/1/"}}
+		path := "/cal/1/"
+		for level := 1; level <= maxNestedCollectionDepth+1; level++ {
+			next := fmt.Sprintf("%s%d/", path, level+1)
+			listings[path] = []string{next}
+			path = next
+		}
+		listings[path] = []string{path + "deep.ics"}
+		srv, propfinds := nestedCalDAVServer(t, listings)
+		defer srv.Close()
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		events, err := client.getEventsViaList(context.Background(), "/cal/", NewMalformedEventCollector())
+		if err != nil {
+			t.Fatalf("getEventsViaList: %v", err)
+		}
+		if len(events) != 0 {
+			t.Errorf("expected the over-deep event to be ignored, got %v", eventUIDs(events))
+		}
+		if n := len(propfinds()); n != maxNestedCollectionDepth+1 {
+			t.Errorf("expected %d PROPFINDs (calendar + %d levels), got %d: %v",
+				maxNestedCollectionDepth+1, maxNestedCollectionDepth, n, propfinds())
+		}
+	})
+
+	t.Run("cycles terminate", func(t *testing.T) {
+		srv, propfinds := nestedCalDAVServer(t, map[string][]string{
+			"/cal/":      {"/cal/loop/"},
+			"/cal/loop/": {"/cal/", "/cal/loop/again/"},
+			// again lists its parent.
+			"/cal/loop/again/": {"/cal/loop/"},
+		})
+		defer srv.Close()
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		events, err := client.getEventsViaList(context.Background(), "/cal/", NewMalformedEventCollector())
+		if err != nil {
+			t.Fatalf("getEventsViaList: %v", err)
+		}
+		if len(events) != 0 {
+			t.Errorf("expected no events, got %v", eventUIDs(events))
+		}
+		if n := len(propfinds()); n != 3 {
+			t.Errorf("expected each collection listed once, got %d PROPFINDs: %v", n, propfinds())
+		}
+	})
+}
@@ -2,10 +2,9 @@ package caldav
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"net/http"
-	"time"
+	"sync/atomic"
 
 	"github.com/emersion/go-webdav/caldav"
 	"golang.org/x/oauth2"
@@ -25,7 +24,7 @@ import (
 // ctx is stored inside the returned TokenSource and used for token
 // refreshes, so it must remain valid for the lifetime of the Client.
 // Pass context.Background() for long-lived use (the sync engine).
-func NewOAuthClient(ctx context.Context, baseURL string, oauthConfig *oauth2.Config, token *oauth2.Token) (*Client, error) {
+func NewOAuthClient(ctx context.Context, baseURL string, oauthConfig *oauth2.Config, token *oauth2.Token, opts ...ClientOption) (*Client, error) {
 	if baseURL == "" {
 		return nil, fmt.Errorf("%w: base URL is required", ErrConnectionFailed)
 	}
@@ -36,18 +35,9 @@ func NewOAuthClient(ctx context.Context, baseURL string, oauthConfig *oauth2.Con
 		return nil, fmt.Errorf("%w: refresh token is required", ErrAuthFailed)
 	}
 
-	// Base transport — matches NewClient's TLS/timeouts exactly so
-	// OAuth requests and non-OAuth requests behave identically at the
-	// network layer. Any change to TLS/timeout policy here MUST be
-	// mirrored in NewClient (and vice versa).
-	baseTransport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			MinVersion: minTLSVersion,
-		},
-		MaxIdleConns:        10,
-		IdleConnTimeout:     30 * time.Second,
-		TLSHandshakeTimeout: 10 * time.Second,
-	}
+	// Base transport — shared with NewClient so OAuth requests and
+	// non-OAuth requests behave identically at the network layer.
+	baseTransport := newTransport()
 
 	// oauth2.Transport wraps baseTransport and injects the bearer
 	// token into every request. When the access token expires, the
@@ -65,19 +55,27 @@ func NewOAuthClient(ctx context.Context, baseURL string, oauthConfig *oauth2.Con
 		Transport: oauthTransport,
 	}
 
+	client := &Client{
+		baseURL:          baseURL,
+		username:         "", // OAuth clients don't carry a username/password
+		password:         "",
+		httpClient:       httpClient,
+		maxResponseBytes: defaultMaxResponseBytes,
+		multiGetRejected: new(atomic.Bool),
+		clock:            new(serverClock),
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+
 	// caldav.NewClient accepts anything that implements webdav.HTTPClient,
 	// and *http.Client satisfies that interface via its Do method. We
 	// pass the oauth-wrapped client directly — no basic-auth wrapper.
-	caldavClient, err := caldav.NewClient(httpClient, baseURL)
+	caldavClient, err := caldav.NewClient(&limitedHTTPClient{base: httpClient, max: client.maxResponseBytes, clock: client.clock}, baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("%w: failed to create CalDAV client: %w", ErrConnectionFailed, err)
 	}
-
-	return &Client{
-		baseURL:      baseURL,
-		username:     "", // OAuth clients don't carry a username/password
-		password:     "",
-		httpClient:   httpClient,
-		caldavClient: caldavClient,
-	}, nil
+	client.caldavClient = caldavClient
+	client.installHeaderTransport()
+	return client, nil
 }
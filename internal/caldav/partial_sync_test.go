@@ -0,0 +1,82 @@
+package caldav
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// twoCalendarServer serves a DAV tree with a "Work" and a "Broken"
+// calendar under /dav/calendars/. Work is empty; every REPORT against
+// Broken fails, so its full sync can't list events.
+func twoCalendarServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		multistatus := func(body string) {
+			w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+			w.WriteHeader(http.StatusMultiStatus)
+			_, _ = w.Write([]byte(`<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">` + body + `</d:multistatus>`))
+		}
+		calendar := func(href, name string) string {
+			return `<d:response><d:href>` + href + `</d:href><d:propstat><d:prop>` +
+				`<d:resourcetype><d:collection/><cal:calendar/></d:resourcetype><d:displayname>` + name + `</d:displayname>` +
+				`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`
+		}
+		path := strings.TrimSuffix(r.URL.Path, "/")
+		switch {
+		case strings.HasPrefix(path, "/dav/calendars/broken"):
+			w.WriteHeader(http.StatusInternalServerError)
+		case r.Method == "REPORT":
+			multistatus("")
+		case r.Method != "PROPFIND":
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case path == "/dav":
+			multistatus(`<d:response><d:href>/dav/</d:href><d:propstat><d:prop>` +
+				`<d:current-user-principal><d:href>/dav/principals/alice/</d:href></d:current-user-principal>` +
+				`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`)
+		case path == "/dav/principals/alice":
+			multistatus(`<d:response><d:href>/dav/principals/alice/</d:href><d:propstat><d:prop>` +
+				`<cal:calendar-home-set><d:href>/dav/calendars/</d:href></cal:calendar-home-set>` +
+				`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`)
+		case path == "/dav/calendars/work":
+			multistatus(calendar("/dav/calendars/work/", "Work"))
+		case path == "/dav/calendars":
+			multistatus(`<d:response><d:href>/dav/calendars/</d:href><d:propstat><d:prop>` +
+				`<d:resourcetype><d:collection/></d:resourcetype>` +
+				`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>` +
+				calendar("/dav/calendars/work/", "Work") + calendar("/dav/calendars/broken/", "Broken"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSyncSource_PartialCalendarFailure(t *testing.T) {
+	f := newSelfLoopFixture(t)
+	source := f.createSource(t, "Partial", twoCalendarServer(t).URL+"/dav/", twoCalendarServer(t).URL+"/dav/")
+
+	result := f.engine.SyncSource(context.Background(), source)
+
+	if result.CalendarsSynced != 1 {
+		t.Errorf("expected 1 calendar synced, got %d", result.CalendarsSynced)
+	}
+	if result.Success {
+		t.Error("expected the failed calendar to fail the sync")
+	}
+	var warning string
+	for _, w := range result.Warnings {
+		if strings.Contains(w, "failed to sync") {
+			warning = w
+		}
+	}
+	if !strings.Contains(warning, "1 of 2 calendar(s)") || !strings.Contains(warning, `"Broken" (/dav/calendars/broken/)`) {
+		t.Errorf("expected a warning naming the failed calendar, got %q (warnings %v)", warning, result.Warnings)
+	}
+	if strings.Contains(warning, "Work") {
+		t.Errorf("the calendar that synced should not be named: %q", warning)
+	}
+}
@@ -0,0 +1,75 @@
+package caldav
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/emersion/go-ical"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// preserveDestProps returns data, the source version of an event about
+// to overwrite destData on the destination, with the properties named
+// in names taken from destData instead: the annotations a user added on
+// the destination (a personal X-NOTE, their own CATEGORIES) survive
+// the update while everything else still comes from the source.
+//
+// Components are paired by RECURRENCE-ID, so an override keeps its own
+// annotations. A property the destination copy doesn't have is left as
+// the source has it. When nothing is copied, or either side can't be
+// parsed, data is returned unchanged.
+func preserveDestProps(data, destData string, names []string) (string, error) {
+	if len(names) == 0 || data == "" || destData == "" {
+		return data, nil
+	}
+	cal, err := parseICalendar(data)
+	if err != nil {
+		return data, fmt.Errorf("%w: %w", ErrMalformedContent, err)
+	}
+	destCal, err := parseICalendar(destData)
+	if err != nil {
+		return data, fmt.Errorf("destination copy: %w: %w", ErrMalformedContent, err)
+	}
+
+	destEntries, _ := calendarEntries(destCal)
+	byRecurrence := make(map[string]*ical.Component, len(destEntries))
+	for _, comp := range destEntries {
+		byRecurrence[propValue(comp, ical.PropRecurrenceID)] = comp
+	}
+
+	copied := false
+	entries, _ := calendarEntries(cal)
+	for _, comp := range entries {
+		destComp := byRecurrence[propValue(comp, ical.PropRecurrenceID)]
+		if destComp == nil {
+			continue
+		}
+		for _, name := range names {
+			name = strings.ToUpper(name)
+			if props := destComp.Props[name]; len(props) > 0 {
+				comp.Props[name] = append([]ical.Prop(nil), props...)
+				copied = true
+			}
+		}
+	}
+	if !copied {
+		return data, nil
+	}
+	merged, err := encodeCalendar(cal)
+	if err != nil {
+		return data, err
+	}
+	return merged, nil
+}
+
+// withPreservedDestProps applies the source's PreserveDestProps to
+// data before it overwrites destData. On failure the update goes ahead
+// with data as it is, and the reason is added to result's warnings.
+func withPreservedDestProps(source *db.Source, data, destData string, result *SyncResult) string {
+	merged, err := preserveDestProps(data, destData, source.PreserveDestProps)
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Event %s: destination properties not preserved: %v", icsUID(data), err))
+		return data
+	}
+	return merged
+}
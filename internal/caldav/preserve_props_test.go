@@ -0,0 +1,124 @@
+package caldav
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emersion/go-ical"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func TestPreserveDestProps(t *testing.T) {
+	source := mergeICS(
+		"UID:standup@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:Daily standup\r\n",
+		"UID:standup@example.com\r\nRECURRENCE-ID:20260106T100000Z\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260106T110000Z\r\nSUMMARY:Late standup\r\n")
+	dest := mergeICS(
+		"UID:standup@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T090000Z\r\nSUMMARY:Standup\r\nX-NOTE:bring the numbers\r\n",
+		"UID:standup@example.com\r\nRECURRENCE-ID:20260106T100000Z\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260106T100000Z\r\nSUMMARY:Standup\r\nX-NOTE:skip this one\r\n")
+
+	t.Run("preserved property survives, core fields from source", func(t *testing.T) {
+		out, err := preserveDestProps(source, dest, []string{"x-note"})
+		if err != nil {
+			t.Fatalf("preserveDestProps: %v", err)
+		}
+		cal, err := parseICalendar(out)
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		want := map[string][3]string{
+			"":                 {"Daily standup", "20260105T100000Z", "bring the numbers"},
+			"20260106T100000Z": {"Late standup", "20260106T110000Z", "skip this one"},
+		}
+		for _, event := range cal.Events() {
+			comp := event.Component
+			w := want[propValue(comp, ical.PropRecurrenceID)]
+			got := [3]string{propValue(comp, ical.PropSummary), propValue(comp, ical.PropDateTimeStart), propValue(comp, "X-NOTE")}
+			if got != w {
+				t.Errorf("RECURRENCE-ID %q: got %v, want %v", propValue(comp, ical.PropRecurrenceID), got, w)
+			}
+		}
+	})
+
+	t.Run("nothing to preserve leaves data untouched", func(t *testing.T) {
+		for _, names := range [][]string{nil, {"X-MISSING"}} {
+			if out, err := preserveDestProps(source, dest, names); err != nil || out != source {
+				t.Errorf("%v: expected source data unchanged, got err %v", names, err)
+			}
+		}
+	})
+
+	t.Run("unparseable destination copy", func(t *testing.T) {
+		out, err := preserveDestProps(source, "not a calendar", []string{"X-NOTE"})
+		if err == nil || out != source {
+			t.Errorf("expected an error and the source data, got %v", err)
+		}
+	})
+}
+
+func TestSyncEventsToDestination_PreserveDestProps(t *testing.T) {
+	destStore := newCalendarStore()
+	destSrv := httptest.NewServer(destStore)
+	defer destSrv.Close()
+	destStore.set("/dest/review@example.com.ics", mergeICS(
+		"UID:review@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:Review\r\nX-NOTE:room 4\r\n"))
+
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	user, err := database.GetOrCreateUser("preserve@example.com", "Preserve")
+	if err != nil {
+		t.Fatalf("GetOrCreateUser: %v", err)
+	}
+	source := &db.Source{
+		UserID:            user.ID,
+		Name:              "Work",
+		SourceType:        db.SourceTypeCustom,
+		SourceURL:         "https://source.example.com/cal/",
+		SourceUsername:    "user",
+		SourcePassword:    "encrypted",
+		DestURL:           destSrv.URL + "/dest/",
+		DestUsername:      "dest",
+		DestPassword:      "encrypted",
+		SyncInterval:      3600,
+		SyncDirection:     db.SyncDirectionOneWay,
+		ConflictStrategy:  db.ConflictSourceWins,
+		Enabled:           true,
+		PreserveDestProps: []string{"X-NOTE"},
+	}
+	if err := database.CreateSource(source); err != nil {
+		t.Fatalf("CreateSource: %v", err)
+	}
+	if err := database.UpsertSyncedEvent(&db.SyncedEvent{
+		SourceID: source.ID, CalendarHref: "/cal/", EventUID: "review@example.com", SourceETag: `"1"`,
+	}); err != nil {
+		t.Fatalf("UpsertSyncedEvent: %v", err)
+	}
+	destClient, err := NewClient(destSrv.URL+"/dest/", "dest", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	// The source moved the review; the destination's note must stay.
+	sourceEvents := []Event{{Path: "/cal/review.ics", ETag: `"2"`, UID: "review@example.com", Data: mergeICS(
+		"UID:review@example.com\r\nDTSTAMP:20260102T000000Z\r\nDTSTART:20260106T150000Z\r\nSUMMARY:Quarterly review\r\n")}}
+	se := NewSyncEngine(database, nil)
+	result := se.syncEventsToDestination(context.Background(), source, nil, destClient, sourceEvents,
+		Calendar{Path: "/cal/", Name: "Work"}, 1, db.SyncDirectionOneWay)
+	if result.Updated != 1 || len(result.Errors) > 0 {
+		t.Fatalf("expected one update, got %d (errors %v, warnings %v)", result.Updated, result.Errors, result.Warnings)
+	}
+
+	destStore.mu.Lock()
+	data := destStore.data["/dest/review@example.com.ics"]
+	destStore.mu.Unlock()
+	for _, want := range []string{"X-NOTE:room 4", "SUMMARY:Quarterly review", "DTSTART:20260106T150000Z"} {
+		if !strings.Contains(data, want) {
+			t.Errorf("expected %q on the destination, got:\n%s", want, data)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package caldav
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ValidateProxyURL reports whether proxyURL can be used as an HTTP(S)
+// proxy: an http or https URL with a host. Empty is valid and means
+// no proxy.
+func ValidateProxyURL(proxyURL string) error {
+	if proxyURL == "" {
+		return nil
+	}
+	// url.Parse errors quote the URL, which may hold credentials.
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return errors.New("proxy URL doesn't parse")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("proxy URL must use http or https")
+	}
+	if u.Host == "" {
+		return errors.New("proxy URL is missing a host")
+	}
+	return nil
+}
+
+// proxyFunc returns an http.Transport Proxy function that sends
+// requests through proxyURL, except to hosts noProxy matches. noProxy
+// has NO_PROXY syntax: comma-separated hosts, domain suffixes, IPs and
+// CIDRs, optionally with a port, or "*" for all; requests to
+// localhost and loopback addresses are never proxied. An empty
+// proxyURL returns nil, meaning connect directly.
+func proxyFunc(proxyURL, noProxy string) func(*http.Request) (*url.URL, error) {
+	if proxyURL == "" {
+		return nil
+	}
+	proxy := (&httpproxy.Config{HTTPProxy: proxyURL, HTTPSProxy: proxyURL, NoProxy: noProxy}).ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
+}
+
+// WithProxy sends the client's requests through proxyURL, except to
+// hosts noProxy matches (see proxyFunc). An empty proxyURL connects
+// directly, and one that doesn't validate is logged and ignored.
+func WithProxy(proxyURL, noProxy string) ClientOption {
+	return func(c *Client) {
+		if proxyURL == "" {
+			return
+		}
+		t := c.transport()
+		if t == nil {
+			return
+		}
+		if err := ValidateProxyURL(proxyURL); err != nil {
+			log.Printf("Ignoring proxy for %s: %v", c.baseURL, err)
+			return
+		}
+		t.Proxy = proxyFunc(proxyURL, noProxy)
+	}
+}
+
+// SetProxy sets the proxy the engine's CalDAV clients connect through,
+// and the NO_PROXY-style list of hosts they reach directly. A source's
+// own ProxyURL takes precedence. Wired from OUTBOUND_PROXY_URL and
+// OUTBOUND_NO_PROXY; call before the scheduler starts.
+func (se *SyncEngine) SetProxy(proxyURL, noProxy string) {
+	se.proxyURL = proxyURL
+	se.noProxy = noProxy
+}
+
+// sourceProxyURL returns the proxy source's clients use: its own, or
+// the engine's.
+func (se *SyncEngine) sourceProxyURL(source *db.Source) string {
+	if source.ProxyURL != "" {
+		return source.ProxyURL
+	}
+	return se.proxyURL
+}
@@ -0,0 +1,96 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// ErrCreateOnly is returned by PurgeDestination for a source synced
+// create-only, which never deletes from its destination.
+var ErrCreateOnly = errors.New("source is create-only and never deletes destination events")
+
+// PurgeResult reports what PurgeDestination removed.
+type PurgeResult struct {
+	Deleted int `json:"deleted"`
+	// NotFound counts tracked events already gone from the destination;
+	// their tracking is dropped all the same.
+	NotFound int      `json:"not_found"`
+	Failed   int      `json:"failed"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// PurgeDestination deletes every event the source's syncs created on
+// its destination, as recorded in synced_events, and drops their
+// tracking. Destination events the source doesn't track are left
+// alone. Deletions are backed up like a sync's, so UndoLastSync can
+// put them back.
+//
+// Only the primary destination is purged. Events that fail to delete
+// stay tracked so the purge can be retried; once nothing failed the
+// source's sync states go too, and its next sync is a first sync.
+func (se *SyncEngine) PurgeDestination(ctx context.Context, source *db.Source) (*PurgeResult, error) {
+	if source.DestReadOnly {
+		return nil, ErrDestReadOnly
+	}
+	if source.SyncDirection == db.SyncDirectionOneWayCreateOnly {
+		return nil, ErrCreateOnly
+	}
+	synced, err := se.db.GetSyncedEventsForSource(source.ID)
+	if err != nil {
+		return nil, err
+	}
+	destClient, err := se.undoDestClient(source, source.DestURL)
+	if err != nil {
+		return nil, err
+	}
+	destCalendarPath := se.primaryDestCalendarPath(ctx, source, destClient)
+	destEvents, err := destClient.GetEvents(ctx, destCalendarPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get destination events: %w", err)
+	}
+	destEventMap := make(map[string]Event, len(destEvents))
+	for _, e := range destEvents {
+		if e.UID != "" {
+			destEventMap[e.MatchKey()] = e
+		}
+	}
+
+	ctx = withDeletionBatch(ctx)
+	backupResult := &SyncResult{}
+	result := &PurgeResult{}
+	for _, s := range synced {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		destEvent, onDest := destEventMap[s.EventUID]
+		if !onDest {
+			if err := se.db.DeleteSyncedEvent(source.ID, s.CalendarHref, s.EventUID); err != nil {
+				log.Printf("Purge: failed to untrack event %s: %v", s.EventUID, err)
+			}
+			result.NotFound++
+			continue
+		}
+		se.backupDeletedEvent(ctx, source, destClient, s.CalendarHref, destCalendarPath, destEvent, backupResult)
+		if err := performDeletionAndCleanup(ctx, destClient, se.db, destEvent.Path, source.ID, s.CalendarHref, s.EventUID); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", s.EventUID, err))
+			continue
+		}
+		// A UID synced from two calendars is one destination event.
+		delete(destEventMap, s.EventUID)
+		result.Deleted++
+	}
+	result.Errors = append(result.Errors, backupResult.Warnings...)
+	if result.Failed == 0 {
+		if err := se.db.ResetSourceSyncState(source.ID); err != nil {
+			log.Printf("Purge: failed to reset sync state of source %s: %v", source.Name, err)
+		}
+	}
+	log.Printf("Purged destination of source %s: deleted %d of %d tracked events (%d already gone, %d failed)",
+		source.Name, result.Deleted, len(synced), result.NotFound, result.Failed)
+	return result, nil
+}
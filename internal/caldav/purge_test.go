@@ -0,0 +1,74 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func TestPurgeDestination(t *testing.T) {
+	srcStore, destStore := newCalendarStore(), newCalendarStore()
+	srcSrv := httptest.NewServer(srcStore)
+	defer srcSrv.Close()
+	destSrv := httptest.NewServer(destStore)
+	defer destSrv.Close()
+
+	f := newSelfLoopFixture(t)
+	source := f.createSource(t, "Purge", srcSrv.URL+"/cal/", destSrv.URL+"/dest/")
+	source.SyncDirection = db.SyncDirectionOneWay
+
+	sourceClient, err := NewClient(srcSrv.URL+"/cal/", "alice", "source-pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	destClient, err := NewClient(destSrv.URL+"/dest/", "alice", "dest-pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	events := make([]Event, 0, 2)
+	for _, uid := range []string{"a", "b"} {
+		data := mergeICS("UID:" + uid + "@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:Event " + uid + "\r\n")
+		etag := srcStore.set("/cal/"+uid+".ics", data)
+		events = append(events, Event{Path: "/cal/" + uid + ".ics", ETag: etag, UID: uid + "@example.com", Data: data})
+	}
+	destStore.set("/dest/manual.ics", mergeICS("UID:manual@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260107T100000Z\r\nSUMMARY:Manual\r\n"))
+
+	calendar := Calendar{Path: "/cal/", Name: "Cal"}
+	result := f.engine.syncEventsToDestination(context.Background(), source, sourceClient, destClient, events, calendar, 1, db.SyncDirectionOneWay)
+	if result.Created != 2 {
+		t.Fatalf("expected 2 events created, got %d (warnings %v)", result.Created, result.Warnings)
+	}
+	// Tracked, but already deleted on the destination by hand.
+	if err := f.database.UpsertSyncedEvent(&db.SyncedEvent{SourceID: source.ID, CalendarHref: "/cal/", EventUID: "gone@example.com"}); err != nil {
+		t.Fatalf("UpsertSyncedEvent: %v", err)
+	}
+
+	createOnly := *source
+	createOnly.SyncDirection = db.SyncDirectionOneWayCreateOnly
+	if _, err := f.engine.PurgeDestination(context.Background(), &createOnly); !errors.Is(err, ErrCreateOnly) {
+		t.Fatalf("expected ErrCreateOnly for a create-only source, got %v", err)
+	}
+
+	purge, err := f.engine.PurgeDestination(context.Background(), source)
+	if err != nil {
+		t.Fatalf("PurgeDestination: %v", err)
+	}
+	if purge.Deleted != 2 || purge.NotFound != 1 || purge.Failed != 0 {
+		t.Fatalf("expected 2 deleted and 1 not found, got %+v", purge)
+	}
+	for _, path := range []string{"/dest/a@example.com.ics", "/dest/b@example.com.ics"} {
+		if _, ok := destStore.data[path]; ok {
+			t.Errorf("expected %s deleted", path)
+		}
+	}
+	if _, ok := destStore.data["/dest/manual.ics"]; !ok {
+		t.Error("expected the untracked event left alone")
+	}
+	if synced, _ := f.database.GetSyncedEventsForSource(source.ID); len(synced) != 0 {
+		t.Errorf("expected no synced events left, got %d", len(synced))
+	}
+}
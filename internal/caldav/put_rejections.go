@@ -0,0 +1,135 @@
+package caldav
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// putRejectionStatus finds the HTTP status go-webdav puts at the start
+// of a failed request's error ("400 Bad Request: <body>").
+var putRejectionStatus = regexp.MustCompile(`\b(4\d\d) [A-Z]`)
+
+// putRejectionCodes are the statuses a destination answers a PUT with
+// when it refuses the event itself rather than the request: auth,
+// missing collections and throttling are left to their own handling.
+var putRejectionCodes = map[int]bool{
+	http.StatusBadRequest:            true,
+	http.StatusForbidden:             true,
+	http.StatusConflict:              true,
+	http.StatusPreconditionFailed:    true,
+	http.StatusRequestEntityTooLarge: true,
+	http.StatusUnsupportedMediaType:  true,
+	http.StatusUnprocessableEntity:   true,
+}
+
+// putRejectionMarkers maps text found in a rejected PUT's response to
+// an actionable reason. Matching is case-insensitive and the first
+// marker found wins, so the specific complaints come before the
+// generic CalDAV preconditions they are often reported alongside.
+var putRejectionMarkers = []struct {
+	marker string
+	reason string
+}{
+	{"sequences don't match", "destination holds a newer SEQUENCE for this event"},
+	{"no-uid-conflict", "UID is already used by another event on the destination"},
+	{"max-resource-size", "event exceeds the destination's size limit"},
+	{"tzid", "unknown timezone"},
+	{"timezone", "unknown timezone"},
+	{"transp", "destination rejected TRANSP property"},
+	{"rrule", "destination rejected recurrence rule"},
+	{"recurrence", "destination rejected recurrence rule"},
+	{"valarm", "destination rejected alarm"},
+	{"trigger", "destination rejected alarm"},
+	{"attendee", "destination rejected ATTENDEE property"},
+	{"organizer", "destination rejected ORGANIZER property"},
+	{"dtstart", "destination rejected event start/end"},
+	{"dtend", "destination rejected event start/end"},
+	{"supported-calendar-component", "destination does not accept this component type"},
+	{"supported-calendar-data", "destination does not accept this calendar data"},
+	{"valid-calendar-data", "destination considers the calendar data invalid"},
+	{"valid-calendar-object-resource", "destination considers the calendar data invalid"},
+}
+
+// classifyPutRejection reports whether err is a destination refusing an
+// event, and if so why in terms a user can act on. Rejections nothing
+// in putRejectionMarkers explains fall back to the HTTP status.
+func classifyPutRejection(err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+	msg := err.Error()
+	m := putRejectionStatus.FindStringSubmatch(msg)
+	if m == nil {
+		return "", false
+	}
+	code, _ := strconv.Atoi(m[1])
+	if !putRejectionCodes[code] {
+		return "", false
+	}
+	lower := strings.ToLower(msg)
+	for _, r := range putRejectionMarkers {
+		if strings.Contains(lower, r.marker) {
+			return r.reason, true
+		}
+	}
+	return fmt.Sprintf("destination rejected the event (%d %s)", code, http.StatusText(code)), true
+}
+
+// recordPutFailure adds the warning for a failed destination PUT to
+// result. Rejections are reported by reason and counted in
+// result.PutRejections; the server's own text only goes to the log.
+func recordPutFailure(result *SyncResult, prefix, uid string, err error) {
+	reason, ok := classifyPutRejection(err)
+	if !ok {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %v", prefix, err))
+		return
+	}
+	log.Printf("%s (UID %s): %v", prefix, uid, err)
+	result.Warnings = append(result.Warnings, fmt.Sprintf("%s (UID %s): %s", prefix, uid, reason))
+	if result.PutRejections == nil {
+		result.PutRejections = make(map[string]int)
+	}
+	result.PutRejections[reason]++
+}
+
+// addPutRejections folds the rejection counts of src into dst.
+func addPutRejections(dst, src *SyncResult) {
+	for reason, n := range src.PutRejections {
+		if dst.PutRejections == nil {
+			dst.PutRejections = make(map[string]int)
+		}
+		dst.PutRejections[reason] += n
+	}
+}
+
+// reportPutRejections adds a single warning to result summarizing the
+// events destinations rejected, most common reason first.
+func reportPutRejections(result *SyncResult) {
+	if len(result.PutRejections) == 0 {
+		return
+	}
+	reasons := make([]string, 0, len(result.PutRejections))
+	total := 0
+	for reason, n := range result.PutRejections {
+		reasons = append(reasons, reason)
+		total += n
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		a, b := result.PutRejections[reasons[i]], result.PutRejections[reasons[j]]
+		if a != b {
+			return a > b
+		}
+		return reasons[i] < reasons[j]
+	})
+	parts := make([]string, len(reasons))
+	for i, reason := range reasons {
+		parts[i] = fmt.Sprintf("%s (%d)", reason, result.PutRejections[reason])
+	}
+	result.Warnings = append(result.Warnings, fmt.Sprintf(
+		"Destination rejected %d event(s): %s", total, strings.Join(parts, ", ")))
+}
@@ -0,0 +1,100 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// putErr wraps a server response the way PutEvent reports it.
+func putErr(response string) error {
+	return fmt.Errorf("%w: failed to put event: %w", ErrConnectionFailed, errors.New(response))
+}
+
+func TestClassifyPutRejection(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+		ok   bool
+	}{
+		{"google unknown TZID", putErr("400 Bad Request: Invalid TZID: Customized Time Zone"), "unknown timezone", true},
+		{"missing VTIMEZONE", putErr(`400 Bad Request: <error xmlns="DAV:"><valid-calendar-data xmlns="urn:ietf:params:xml:ns:caldav">Unknown timezone US-Eastern</valid-calendar-data></error>`), "unknown timezone", true},
+		{"TRANSP value", putErr("400 Bad Request: Invalid value for property TRANSP: OPAQUE-ISH"), "destination rejected TRANSP property", true},
+		{"bad RRULE", putErr("422 Unprocessable Entity: cannot parse RRULE FREQ=FORTNIGHTLY"), "destination rejected recurrence rule", true},
+		{"SOGo sequence", putErr("403 Forbidden: <D:error>sequences don't match</D:error>"), "destination holds a newer SEQUENCE for this event", true},
+		{"UID conflict", putErr(`409 Conflict: <error xmlns="DAV:"><no-uid-conflict xmlns="urn:ietf:params:xml:ns:caldav"><href>/cal/other.ics</href></no-uid-conflict></error>`), "UID is already used by another event on the destination", true},
+		{"size limit", putErr(`413 Request Entity Too Large: <error xmlns="DAV:"><max-resource-size xmlns="urn:ietf:params:xml:ns:caldav"/></error>`), "event exceeds the destination's size limit", true},
+		{"generic calendar data", putErr(`400 Bad Request: <error xmlns="DAV:"><valid-calendar-data xmlns="urn:ietf:params:xml:ns:caldav"/></error>`), "destination considers the calendar data invalid", true},
+		{"no body", putErr("400 Bad Request"), "destination rejected the event (400 Bad Request)", true},
+		{"auth is not a rejection", putErr("401 Unauthorized"), "", false},
+		{"server error is not a rejection", putErr("500 Internal Server Error: timezone service down"), "", false},
+		{"network error", putErr("dial tcp: connection refused"), "", false},
+		{"nil", nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := classifyPutRejection(tt.err)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("classifyPutRejection() = %q, %v; want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRecordPutFailure(t *testing.T) {
+	result := &SyncResult{}
+	recordPutFailure(result, "Failed to create event on dest", "a", putErr("400 Bad Request: Invalid TZID: Foo"))
+	recordPutFailure(result, "Failed to update event on dest", "b", putErr("400 Bad Request: Invalid TZID: Bar"))
+	recordPutFailure(result, "Failed to create event on dest", "c", putErr("400 Bad Request: bad TRANSP"))
+	recordPutFailure(result, "Failed to create event on dest", "d", context.DeadlineExceeded)
+
+	if len(result.Warnings) != 4 {
+		t.Fatalf("expected one warning per failure, got %v", result.Warnings)
+	}
+	if result.Warnings[0] != "Failed to create event on dest (UID a): unknown timezone" {
+		t.Errorf("unexpected rejection warning: %q", result.Warnings[0])
+	}
+	if result.Warnings[3] != "Failed to create event on dest: context deadline exceeded" {
+		t.Errorf("expected other failures to keep the raw error, got %q", result.Warnings[3])
+	}
+
+	total := &SyncResult{}
+	addPutRejections(total, result)
+	addPutRejections(total, &SyncResult{PutRejections: map[string]int{"destination rejected TRANSP property": 2}})
+	reportPutRejections(total)
+	want := "Destination rejected 5 event(s): destination rejected TRANSP property (3), unknown timezone (2)"
+	if len(total.Warnings) != 1 || total.Warnings[0] != want {
+		t.Errorf("expected summary %q, got %v", want, total.Warnings)
+	}
+
+	quiet := &SyncResult{}
+	reportPutRejections(quiet)
+	if len(quiet.Warnings) != 0 {
+		t.Errorf("expected no summary without rejections, got %v", quiet.Warnings)
+	}
+}
+
+func TestPutEvent_RejectionIsClassified(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Invalid TZID: Customized Time Zone"))
+	}))
+	defer srv.Close()
+	client, err := NewClient(srv.URL+"/cal/", "user", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	err = client.PutEvent(context.Background(), "/cal/", &Event{UID: "tz-1", Data: tzEvent("DTSTART:20260115T090000Z")})
+	if err == nil {
+		t.Fatal("expected the PUT to fail")
+	}
+	if reason, ok := classifyPutRejection(err); !ok || reason != "unknown timezone" {
+		t.Errorf("classifyPutRejection(%v) = %q, %v; want unknown timezone", err, reason, ok)
+	}
+}
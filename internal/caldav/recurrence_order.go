@@ -0,0 +1,16 @@
+package caldav
+
+import "sort"
+
+// mastersFirst reorders events so every series master is written
+// before any detached override. Some servers answer 409 to an override
+// PUT whose master they don't have yet; with this order a first sync
+// creates the master first, and a master that failed is retried ahead
+// of its overrides on the next sync. Masters and overrides otherwise
+// keep their relative order. events is sorted in place and returned.
+func mastersFirst(events []Event) []Event {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].RecurrenceID == "" && events[j].RecurrenceID != ""
+	})
+	return events
+}
@@ -0,0 +1,113 @@
+package caldav
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func TestMastersFirst(t *testing.T) {
+	events := mastersFirst([]Event{
+		{UID: "weekly@example.com", RecurrenceID: "20260112T100000Z"},
+		{UID: "daily@example.com", RecurrenceID: "20260106T090000Z"},
+		{UID: "weekly@example.com"},
+		{UID: "once@example.com"},
+		{UID: "daily@example.com"},
+	})
+	var got []string
+	for _, e := range events {
+		got = append(got, e.MatchKey())
+	}
+	want := []string{
+		"weekly@example.com", "once@example.com", "daily@example.com",
+		"weekly@example.com_20260112T100000Z", "daily@example.com_20260106T090000Z",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+// TestSyncEventsToDestination_MasterPutBeforeOverride hands the sync a
+// detached override ahead of its master and checks the destination
+// sees the master's PUT first.
+func TestSyncEventsToDestination_MasterPutBeforeOverride(t *testing.T) {
+	var mu sync.Mutex
+	var puts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut:
+			mu.Lock()
+			puts = append(puts, r.URL.Path)
+			mu.Unlock()
+			w.Header().Set("ETag", `"1"`)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == "REPORT" || (r.Method == "PROPFIND" && r.Header.Get("Depth") == "1"):
+			w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+			w.WriteHeader(http.StatusMultiStatus)
+			_, _ = w.Write([]byte(`<?xml version="1.0"?><d:multistatus xmlns:d="DAV:"></d:multistatus>`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	user, err := database.GetOrCreateUser("order@example.com", "Order")
+	if err != nil {
+		t.Fatalf("GetOrCreateUser: %v", err)
+	}
+	source := &db.Source{
+		UserID:           user.ID,
+		Name:             "Ordered",
+		SourceType:       db.SourceTypeCustom,
+		SourceURL:        "https://source.example.com/cal/",
+		SourceUsername:   "user",
+		SourcePassword:   "encrypted",
+		DestURL:          srv.URL + "/dest/",
+		DestUsername:     "dest",
+		DestPassword:     "encrypted",
+		SyncInterval:     3600,
+		SyncDirection:    db.SyncDirectionOneWay,
+		ConflictStrategy: db.ConflictSourceWins,
+		Enabled:          true,
+	}
+	if err := database.CreateSource(source); err != nil {
+		t.Fatalf("CreateSource: %v", err)
+	}
+	destClient, err := NewClient(srv.URL+"/dest/", "dest", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	sourceEvents := []Event{
+		{Path: "/cal/weekly-override.ics", ETag: `"o"`, Data: recurringOverrideICS, UID: "weekly@example.com", RecurrenceID: "20260112T100000Z"},
+		{Path: "/cal/weekly.ics", ETag: `"m"`, Data: recurringMasterICS, UID: "weekly@example.com"},
+	}
+	se := NewSyncEngine(database, nil)
+	result := se.syncEventsToDestination(context.Background(), source, nil, destClient, sourceEvents,
+		Calendar{Path: "/cal/", Name: "Cal"}, 1, db.SyncDirectionOneWay)
+	if result.Created != 2 {
+		t.Fatalf("Created = %d, want 2 (warnings: %v, errors: %v)", result.Created, result.Warnings, result.Errors)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"/dest/weekly@example.com.ics", "/dest/weekly@example.com_20260112T100000Z.ics"}
+	if len(puts) != 2 || puts[0] != want[0] || puts[1] != want[1] {
+		t.Errorf("PUT order = %v, want %v", puts, want)
+	}
+}
@@ -0,0 +1,173 @@
+package caldav
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+const (
+	recurringMasterICS = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+		"BEGIN:VEVENT\r\nUID:weekly@example.com\r\nDTSTAMP:20260101T000000Z\r\n" +
+		"DTSTART:20260105T100000Z\r\nDTEND:20260105T110000Z\r\nRRULE:FREQ=WEEKLY\r\n" +
+		"SUMMARY:Weekly sync\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	recurringOverrideICS = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+		"BEGIN:VEVENT\r\nUID:weekly@example.com\r\nDTSTAMP:20260101T000000Z\r\n" +
+		"RECURRENCE-ID:20260112T100000Z\r\n" +
+		"DTSTART:20260112T140000Z\r\nDTEND:20260112T150000Z\r\n" +
+		"SUMMARY:Weekly sync (moved)\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+)
+
+func TestDetachedRecurrenceID(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"master only", recurringMasterICS, ""},
+		{"override only", recurringOverrideICS, "20260112T100000Z"},
+		{
+			"master with embedded override",
+			"BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+				"BEGIN:VEVENT\r\nUID:a\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nRRULE:FREQ=DAILY\r\nEND:VEVENT\r\n" +
+				"BEGIN:VEVENT\r\nUID:a\r\nDTSTAMP:20260101T000000Z\r\nRECURRENCE-ID:20260106T100000Z\r\nDTSTART:20260106T120000Z\r\nEND:VEVENT\r\n" +
+				"END:VCALENDAR\r\n",
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cal, err := parseICalendar(tt.data)
+			if err != nil {
+				t.Fatalf("parseICalendar: %v", err)
+			}
+			if got := detachedRecurrenceID(cal); got != tt.want {
+				t.Errorf("detachedRecurrenceID = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventMatchKey(t *testing.T) {
+	master := Event{UID: "weekly@example.com"}
+	override := Event{UID: "weekly@example.com", RecurrenceID: "20260112T100000Z"}
+
+	if got := master.MatchKey(); got != "weekly@example.com" {
+		t.Errorf("master MatchKey = %q, want bare UID", got)
+	}
+	if master.MatchKey() == override.MatchKey() {
+		t.Error("master and override must not share a MatchKey")
+	}
+}
+
+// TestSyncEventsToDestination_MasterAndOverrideBothPropagate syncs a
+// recurring master plus a detached override (same UID, separate source
+// resources) and checks both land on the destination and are tracked
+// separately in synced_events.
+func TestSyncEventsToDestination_MasterAndOverrideBothPropagate(t *testing.T) {
+	var mu sync.Mutex
+	puts := make(map[string]bool)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut:
+			mu.Lock()
+			puts[r.URL.Path] = true
+			mu.Unlock()
+			w.Header().Set("ETag", `"1"`)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == "REPORT" || (r.Method == "PROPFIND" && r.Header.Get("Depth") == "1"):
+			// Empty destination calendar.
+			w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+			w.WriteHeader(http.StatusMultiStatus)
+			_, _ = w.Write([]byte(`<?xml version="1.0"?><d:multistatus xmlns:d="DAV:"></d:multistatus>`))
+		default:
+			// Principal discovery: refuse so the engine uses the URL path.
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	user, err := database.GetOrCreateUser("recur@example.com", "Recur")
+	if err != nil {
+		t.Fatalf("GetOrCreateUser: %v", err)
+	}
+	source := &db.Source{
+		UserID:           user.ID,
+		Name:             "Recurring",
+		SourceType:       db.SourceTypeCustom,
+		SourceURL:        "https://source.example.com/cal/",
+		SourceUsername:   "user",
+		SourcePassword:   "encrypted",
+		DestURL:          srv.URL + "/dest/",
+		DestUsername:     "dest",
+		DestPassword:     "encrypted",
+		SyncInterval:     3600,
+		SyncDirection:    db.SyncDirectionOneWay,
+		ConflictStrategy: db.ConflictSourceWins,
+		Enabled:          true,
+	}
+	if err := database.CreateSource(source); err != nil {
+		t.Fatalf("CreateSource: %v", err)
+	}
+
+	destClient, err := NewClient(srv.URL+"/dest/", "dest", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	var sourceEvents []Event
+	for path, data := range map[string]string{
+		"/cal/weekly.ics":          recurringMasterICS,
+		"/cal/weekly-override.ics": recurringOverrideICS,
+	} {
+		cal, err := parseICalendar(data)
+		if err != nil {
+			t.Fatalf("parseICalendar: %v", err)
+		}
+		sourceEvents = append(sourceEvents, Event{
+			Path:         path,
+			ETag:         `"src"`,
+			Data:         data,
+			UID:          "weekly@example.com",
+			RecurrenceID: detachedRecurrenceID(cal),
+		})
+	}
+
+	se := NewSyncEngine(database, nil)
+	calendar := Calendar{Path: "/cal/", Name: "Cal"}
+	result := se.syncEventsToDestination(context.Background(), source, nil, destClient, sourceEvents, calendar, 1, db.SyncDirectionOneWay)
+
+	if result.Created != 2 {
+		t.Errorf("Created = %d, want 2 (warnings: %v, errors: %v)", result.Created, result.Warnings, result.Errors)
+	}
+	for _, path := range []string{"/dest/weekly@example.com.ics", "/dest/weekly@example.com_20260112T100000Z.ics"} {
+		if !puts[path] {
+			t.Errorf("expected PUT to %s, got %v", path, puts)
+		}
+	}
+
+	synced, err := database.GetSyncedEvents(source.ID, calendar.Path)
+	if err != nil {
+		t.Fatalf("GetSyncedEvents: %v", err)
+	}
+	var keys []string
+	for _, s := range synced {
+		keys = append(keys, s.EventUID)
+	}
+	sort.Strings(keys)
+	want := []string{"weekly@example.com", "weekly@example.com_20260112T100000Z"}
+	if len(keys) != len(want) || keys[0] != want[0] || keys[1] != want[1] {
+		t.Errorf("synced_events keys = %v, want %v", keys, want)
+	}
+}
@@ -0,0 +1,122 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// endlessServer answers PROPFIND with a multistatus that never ends and
+// GET with an oversized calendar object, counting the bytes it manages
+// to write before the client hangs up.
+func endlessServer(t *testing.T, written *atomic.Int64) *httptest.Server {
+	t.Helper()
+	chunk := []byte(strings.Repeat(collectionResponse("/cal/sub/"), 64))
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PROPFIND":
+			w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+			w.WriteHeader(http.StatusMultiStatus)
+			n, _ := w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?><D:multistatus xmlns:D="DAV:">`))
+			written.Add(int64(n))
+			for r.Context().Err() == nil {
+				n, err := w.Write(chunk)
+				written.Add(int64(n))
+				if err != nil {
+					return
+				}
+			}
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "text/calendar")
+			_, _ = io.WriteString(w, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VEVENT\r\n"+
+				"UID:huge\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\n"+
+				"DESCRIPTION:"+strings.Repeat("x", 70)+strings.Repeat("\r\n "+strings.Repeat("x", 70), 2048)+
+				"\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n")
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	const limit = 64 << 10
+	var written atomic.Int64
+	srv := endlessServer(t, &written)
+	defer srv.Close()
+
+	client, err := NewClient(srv.URL+"/cal/", "user", "pass", WithMaxResponseBytes(limit))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	t.Run("oversized PROPFIND listing fails the read", func(t *testing.T) {
+		_, err := client.getEventsViaList(context.Background(), "/cal/", NewMalformedEventCollector())
+		if !errors.Is(err, ErrResponseTooLarge) {
+			t.Fatalf("expected ErrResponseTooLarge, got %v", err)
+		}
+		// The server stops once the connection drops; what it got out
+		// is the cap plus whatever the socket buffers held, not the
+		// unbounded stream.
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			before := written.Load()
+			time.Sleep(50 * time.Millisecond)
+			if written.Load() == before {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("server still writing after the client gave up (%d bytes)", written.Load())
+			}
+		}
+		if n := written.Load(); n > 32<<20 {
+			t.Errorf("expected a bounded transfer, server wrote %d bytes", n)
+		}
+	})
+
+	t.Run("oversized event is reported as malformed", func(t *testing.T) {
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass", WithMaxResponseBytes(16<<10))
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		_, err = client.GetEvent(context.Background(), "/cal/huge.ics")
+		if !errors.Is(err, ErrResponseTooLarge) || !errors.Is(err, ErrMalformedContent) {
+			t.Errorf("expected ErrResponseTooLarge wrapped as malformed content, got %v", err)
+		}
+	})
+
+	t.Run("bodies up to the cap are read in full", func(t *testing.T) {
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass", WithMaxResponseBytes(1<<20))
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		if event, err := client.GetEvent(context.Background(), "/cal/huge.ics"); err != nil || event.UID != "huge" {
+			t.Errorf("expected the event under a larger cap, got %+v, %v", event, err)
+		}
+	})
+}
+
+func TestLimitedBody(t *testing.T) {
+	read := func(body string, max int64) (string, error) {
+		data, err := io.ReadAll(newLimitedBody(io.NopCloser(strings.NewReader(body)), max, "http://x/"))
+		return string(data), err
+	}
+	if data, err := read("12345", 5); err != nil || data != "12345" {
+		t.Errorf("a body of exactly the cap should read cleanly, got %q, %v", data, err)
+	}
+	if data, err := read("123456", 5); !errors.Is(err, ErrResponseTooLarge) || data != "12345" {
+		t.Errorf("expected the first 5 bytes and ErrResponseTooLarge, got %q, %v", data, err)
+	}
+
+	// Readers that retry after an error keep getting it.
+	b := newLimitedBody(io.NopCloser(strings.NewReader("123456")), 5, "http://x/")
+	_, _ = io.ReadAll(b)
+	if n, err := b.Read(make([]byte, 8)); n != 0 || !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("expected a repeated read to fail with no data, got %d, %v", n, err)
+	}
+}
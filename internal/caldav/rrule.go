@@ -0,0 +1,158 @@
+package caldav
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/emersion/go-ical"
+)
+
+// RecurrencePolicy decides what happens to an event whose RRULE is
+// invalid. Some destinations reject the whole event over a bad rule,
+// so the sync fixes or removes it before the PUT.
+type RecurrencePolicy string
+
+const (
+	// RecurrenceRepair fixes what can be fixed (a COUNT next to an
+	// UNTIL, BYDAY entries that aren't weekdays) and falls back to
+	// stripping the recurrence only when the rule is beyond repair.
+	RecurrenceRepair RecurrencePolicy = "repair"
+	// RecurrenceStrip removes any invalid recurrence, syncing the
+	// event as a single instance.
+	RecurrenceStrip RecurrencePolicy = "strip"
+)
+
+// validRRuleFreqs are the FREQ values RFC 5545 §3.3.10 allows.
+var validRRuleFreqs = map[string]bool{
+	"SECONDLY": true, "MINUTELY": true, "HOURLY": true, "DAILY": true,
+	"WEEKLY": true, "MONTHLY": true, "YEARLY": true,
+}
+
+// rruleWeekday matches one BYDAY entry: a weekday with an optional
+// signed ordinal ("MO", "-1FR", "+2TU").
+var rruleWeekday = regexp.MustCompile(`^[+-]?([1-9]|[1-4][0-9]|5[0-3])?(MO|TU|WE|TH|FR|SA|SU)$`)
+
+// checkRRule validates an RRULE value. It returns the value with
+// repairable problems fixed, a description of each problem found, and
+// whether the rule is beyond repair (missing or unknown FREQ).
+func checkRRule(value string) (repaired string, problems []string, fatal bool) {
+	parts := strings.Split(value, ";")
+	kept := make([]string, 0, len(parts))
+	hasUntil := false
+	for _, part := range parts {
+		if name, _, _ := strings.Cut(part, "="); strings.EqualFold(name, "UNTIL") {
+			hasUntil = true
+		}
+	}
+
+	freq := ""
+	for _, part := range parts {
+		name, val, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			freq = strings.ToUpper(val)
+		case "COUNT", "INTERVAL":
+			if n, err := strconv.Atoi(val); err != nil || n < 1 {
+				problems = append(problems, fmt.Sprintf("invalid %s %q", strings.ToUpper(name), val))
+				continue
+			}
+			if strings.EqualFold(name, "COUNT") && hasUntil {
+				problems = append(problems, "COUNT and UNTIL both set")
+				continue
+			}
+		case "BYDAY":
+			var days []string
+			for _, day := range strings.Split(val, ",") {
+				if rruleWeekday.MatchString(strings.ToUpper(day)) {
+					days = append(days, day)
+				} else {
+					problems = append(problems, fmt.Sprintf("invalid BYDAY %q", day))
+				}
+			}
+			if len(days) == 0 {
+				continue
+			}
+			part = name + "=" + strings.Join(days, ",")
+		}
+		kept = append(kept, part)
+	}
+
+	if freq == "" {
+		return value, append(problems, "missing FREQ"), true
+	}
+	if !validRRuleFreqs[freq] {
+		return value, append(problems, fmt.Sprintf("invalid FREQ %q", freq)), true
+	}
+	return strings.Join(kept, ";"), problems, false
+}
+
+// validateRecurrence checks every RRULE in data and applies policy to
+// invalid ones. It returns data unchanged, without re-encoding, when
+// every rule is valid; otherwise the fixed data and a warning saying
+// what was wrong and what was done. Data that doesn't parse is left to
+// the malformed-event handling elsewhere.
+func validateRecurrence(data string, policy RecurrencePolicy) (string, string) {
+	if data == "" || !strings.Contains(strings.ToUpper(data), "RRULE") {
+		return data, ""
+	}
+	cal, err := parseICalendar(data)
+	if err != nil {
+		return data, ""
+	}
+
+	var problems []string
+	strip := false
+	for _, comp := range cal.Children {
+		if comp.Name != ical.CompEvent {
+			continue
+		}
+		rules := comp.Props[ical.PropRecurrenceRule]
+		for i := range rules {
+			repaired, found, fatal := checkRRule(rules[i].Value)
+			if len(found) == 0 {
+				continue
+			}
+			problems = append(problems, found...)
+			if fatal || policy == RecurrenceStrip {
+				strip = true
+			} else {
+				rules[i].Value = repaired
+			}
+		}
+	}
+	if len(problems) == 0 {
+		return data, ""
+	}
+
+	action := "repaired the rule"
+	if strip {
+		stripRecurrence(cal)
+		action = "removed the recurrence, syncing a single instance"
+	}
+	fixed, err := encodeCalendar(cal)
+	if err != nil {
+		return data, ""
+	}
+	return fixed, fmt.Sprintf("Event %s: invalid RRULE (%s); %s", icsUID(data), strings.Join(problems, ", "), action)
+}
+
+// stripRecurrence turns a recurring event into a single instance: the
+// master loses its recurrence properties and the overrides, which no
+// longer have a series to belong to, are dropped.
+func stripRecurrence(cal *ical.Calendar) {
+	kept := cal.Children[:0]
+	for _, comp := range cal.Children {
+		if comp.Name == ical.CompEvent {
+			if comp.Props.Get(ical.PropRecurrenceID) != nil {
+				continue
+			}
+			delete(comp.Props, ical.PropRecurrenceRule)
+			delete(comp.Props, ical.PropRecurrenceDates)
+			delete(comp.Props, ical.PropExceptionDates)
+		}
+		kept = append(kept, comp)
+	}
+	cal.Children = kept
+}
@@ -0,0 +1,75 @@
+package caldav
+
+import (
+	"strings"
+	"testing"
+)
+
+// rruleEvent returns a recurring event with the given RRULE value and
+// one overridden occurrence.
+func rruleEvent(rule string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+		"BEGIN:VEVENT\r\nUID:rrule-1\r\nDTSTAMP:20260101T000000Z\r\n" +
+		"DTSTART:20260105T100000Z\r\nRRULE:" + rule + "\r\nEXDATE:20260119T100000Z\r\n" +
+		"SUMMARY:Standup\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:rrule-1\r\nDTSTAMP:20260101T000000Z\r\n" +
+		"RECURRENCE-ID:20260112T100000Z\r\nDTSTART:20260112T110000Z\r\n" +
+		"SUMMARY:Standup (moved)\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+}
+
+func TestValidateRecurrence(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     string
+		policy   RecurrencePolicy
+		wantRule string // "" means the recurrence was stripped
+		warning  string
+	}{
+		{"UNTIL and COUNT repaired", "FREQ=WEEKLY;COUNT=10;UNTIL=20260601T000000Z", RecurrenceRepair,
+			"RRULE:FREQ=WEEKLY;UNTIL=20260601T000000Z", "COUNT and UNTIL both set"},
+		{"UNTIL and COUNT stripped", "FREQ=WEEKLY;COUNT=10;UNTIL=20260601T000000Z", RecurrenceStrip,
+			"", "COUNT and UNTIL both set"},
+		{"garbage BYDAY repaired", "FREQ=WEEKLY;BYDAY=MO,XX,WE", RecurrenceRepair,
+			"RRULE:FREQ=WEEKLY;BYDAY=MO,WE", `invalid BYDAY "XX"`},
+		{"garbage BYDAY stripped", "FREQ=WEEKLY;BYDAY=MO,XX,WE", RecurrenceStrip,
+			"", `invalid BYDAY "XX"`},
+		{"all-garbage BYDAY dropped", "FREQ=WEEKLY;BYDAY=FOO", RecurrenceRepair,
+			"RRULE:FREQ=WEEKLY\r\n", `invalid BYDAY "FOO"`},
+		{"bad FREQ stripped even under repair", "FREQ=FORTNIGHTLY", RecurrenceRepair,
+			"", `invalid FREQ "FORTNIGHTLY"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warning := validateRecurrence(rruleEvent(tt.rule), tt.policy)
+			if !strings.Contains(warning, tt.warning) || !strings.Contains(warning, "rrule-1") {
+				t.Errorf("expected a warning naming the event and %q, got %q", tt.warning, warning)
+			}
+			if tt.wantRule != "" {
+				if !strings.Contains(got, tt.wantRule) {
+					t.Errorf("expected %q, got:\n%s", tt.wantRule, got)
+				}
+				if !strings.Contains(got, "RECURRENCE-ID") {
+					t.Error("expected a repaired series to keep its override")
+				}
+				return
+			}
+			for _, prop := range []string{"RRULE", "EXDATE", "RECURRENCE-ID"} {
+				if strings.Contains(got, prop) {
+					t.Errorf("expected %s removed from a stripped event, got:\n%s", prop, got)
+				}
+			}
+			if strings.Count(got, "BEGIN:VEVENT") != 1 || !strings.Contains(got, "SUMMARY:Standup\r\n") {
+				t.Errorf("expected the master as a single instance, got:\n%s", got)
+			}
+		})
+	}
+
+	t.Run("valid rule passes through untouched", func(t *testing.T) {
+		data := rruleEvent("FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR;COUNT=6")
+		for _, policy := range []RecurrencePolicy{RecurrenceRepair, RecurrenceStrip} {
+			if got, warning := validateRecurrence(data, policy); got != data || warning != "" {
+				t.Errorf("%s: expected passthrough, got warning %q", policy, warning)
+			}
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package caldav
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func TestMatchSelectedCalendars(t *testing.T) {
+	discovered := []Calendar{
+		{Path: "/cal/ABC-new/", Name: "Work"},
+		{Path: "/cal/home/", Name: "Home"},
+		{Path: "/cal/x1/", Name: "Shared"},
+		{Path: "/cal/x2/", Name: "Shared"},
+	}
+
+	t.Run("paths that still exist match as before", func(t *testing.T) {
+		matched, updated, changed, unmatched := matchSelectedCalendars(
+			[]db.CalendarConfig{{Path: "/cal/home/", Name: "Home"}}, discovered)
+		if len(matched) != 1 || matched[0].Path != "/cal/home/" || changed || len(unmatched) != 0 {
+			t.Errorf("got matched %v, changed %v, unmatched %v", matched, changed, unmatched)
+		}
+		if updated[0] != (db.CalendarConfig{Path: "/cal/home/", Name: "Home"}) {
+			t.Errorf("selection should be unchanged, got %+v", updated[0])
+		}
+	})
+
+	t.Run("a moved calendar is matched by name and its path healed", func(t *testing.T) {
+		selection := []db.CalendarConfig{{Path: "/cal/ABC-old/", Name: "Work", SyncDirection: db.SyncDirectionTwoWay}}
+		matched, updated, changed, unmatched := matchSelectedCalendars(selection, discovered)
+		if len(matched) != 1 || matched[0].Path != "/cal/ABC-new/" || !changed || len(unmatched) != 0 {
+			t.Fatalf("got matched %v, changed %v, unmatched %v", matched, changed, unmatched)
+		}
+		want := db.CalendarConfig{Path: "/cal/ABC-new/", Name: "Work", SyncDirection: db.SyncDirectionTwoWay}
+		if updated[0] != want {
+			t.Errorf("expected %+v, got %+v", want, updated[0])
+		}
+		if selection[0].Path != "/cal/ABC-old/" {
+			t.Error("the input selection was modified")
+		}
+	})
+
+	t.Run("names are learned for selections stored without one", func(t *testing.T) {
+		_, updated, changed, _ := matchSelectedCalendars([]db.CalendarConfig{{Path: "/cal/home/"}}, discovered)
+		if !changed || updated[0].Name != "Home" {
+			t.Errorf("expected the name to be filled in, got %+v (changed %v)", updated[0], changed)
+		}
+	})
+
+	t.Run("gone calendars and ambiguous names are unmatched", func(t *testing.T) {
+		selection := []db.CalendarConfig{
+			{Path: "/cal/gone/", Name: "Archive"},
+			{Path: "/cal/x0/", Name: "Shared"},
+			{Path: "/cal/nameless/"},
+		}
+		matched, _, changed, unmatched := matchSelectedCalendars(selection, discovered)
+		if len(matched) != 0 || changed || len(unmatched) != 3 {
+			t.Errorf("got matched %v, changed %v, unmatched %v", matched, changed, unmatched)
+		}
+	})
+
+	t.Run("a calendar selected by path is not claimed again by name", func(t *testing.T) {
+		selection := []db.CalendarConfig{
+			{Path: "/cal/home/", Name: "Home"},
+			{Path: "/cal/old-home/", Name: "Home"},
+		}
+		matched, _, _, unmatched := matchSelectedCalendars(selection, discovered)
+		if len(matched) != 1 || len(unmatched) != 1 || unmatched[0].Path != "/cal/old-home/" {
+			t.Errorf("got matched %v, unmatched %v", matched, unmatched)
+		}
+	})
+}
+
+func TestResolveSelectedCalendars(t *testing.T) {
+	f := newSelfLoopFixture(t)
+	source := f.createSource(t, "Selection", "http://127.0.0.1:1/cal/", "http://127.0.0.1:1/dest/")
+	source.SelectedCalendars = []db.CalendarConfig{
+		{Path: "/cal/ABC-old/", Name: "Work", SyncDirection: db.SyncDirectionOneWay},
+		{Path: "/cal/gone/", Name: "Archive"},
+	}
+	if err := f.database.UpdateSource(source); err != nil {
+		t.Fatalf("UpdateSource: %v", err)
+	}
+	discovered := []Calendar{{Path: "/cal/ABC-new/", Name: "Work"}, {Path: "/cal/home/", Name: "Home"}}
+
+	t.Run("dry runs heal in memory only", func(t *testing.T) {
+		copied := *source
+		copied.SelectedCalendars = append([]db.CalendarConfig(nil), source.SelectedCalendars...)
+		result := &SyncResult{}
+		f.engine.resolveSelectedCalendars(WithDryRun(context.Background()), &copied, discovered, result)
+
+		stored, err := f.database.GetSourceByID(source.ID)
+		if err != nil {
+			t.Fatalf("GetSourceByID: %v", err)
+		}
+		if stored.SelectedCalendars[0].Path != "/cal/ABC-old/" {
+			t.Errorf("dry run stored the healed path: %+v", stored.SelectedCalendars)
+		}
+	})
+
+	result := &SyncResult{}
+	selected := f.engine.resolveSelectedCalendars(context.Background(), source, discovered, result)
+
+	if len(selected) != 1 || selected[0].Path != "/cal/ABC-new/" {
+		t.Fatalf("expected the moved Work calendar to sync, got %v", selected)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], `"Archive"`) {
+		t.Errorf("expected one warning naming Archive, got %v", result.Warnings)
+	}
+	if got := getSyncDirectionForCalendar(source, "/cal/ABC-new/"); got != db.SyncDirectionOneWay {
+		t.Errorf("expected the per-calendar direction to follow the healed path, got %q", got)
+	}
+
+	stored, err := f.database.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("GetSourceByID: %v", err)
+	}
+	if len(stored.SelectedCalendars) != 2 || stored.SelectedCalendars[0].Path != "/cal/ABC-new/" ||
+		stored.SelectedCalendars[1].Path != "/cal/gone/" {
+		t.Errorf("expected the healed selection to be stored, got %+v", stored.SelectedCalendars)
+	}
+}
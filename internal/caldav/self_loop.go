@@ -0,0 +1,157 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// normalizeCollectionURL reduces a collection URL to the form two
+// clients pointed at the same collection agree on: lowercase scheme and
+// host, no default port, no credentials, query or fragment, and a
+// cleaned, unescaped path with a trailing slash.
+func normalizeCollectionURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(raw), "/")) + "/"
+	}
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Host)
+	if (scheme == "https" && strings.HasSuffix(host, ":443")) || (scheme == "http" && strings.HasSuffix(host, ":80")) {
+		host = host[:strings.LastIndex(host, ":")]
+	}
+	p := path.Clean("/" + u.Path)
+	if p != "/" {
+		p += "/"
+	}
+	return scheme + "://" + host + p
+}
+
+// sameEndpoint reports whether two configured URL/username pairs are
+// the same account on the same collection. The username matters: two
+// users' DAV roots share a URL but not a calendar.
+func sameEndpoint(urlA, userA, urlB, userB string) bool {
+	return normalizeCollectionURL(urlA) == normalizeCollectionURL(urlB) &&
+		strings.EqualFold(strings.TrimSpace(userA), strings.TrimSpace(userB))
+}
+
+// configuredSelfLoopReason checks the source's configuration alone, with
+// no server round trips: a destination identical to the source, or
+// another of the user's sources that syncs the same pair of endpoints
+// the other way round. Returns "" when neither applies.
+func (se *SyncEngine) configuredSelfLoopReason(source *db.Source) string {
+	if sameEndpoint(source.SourceURL, source.SourceUsername, source.DestURL, source.DestUsername) {
+		return "the destination URL and username are the same as the source's"
+	}
+	if se.db == nil {
+		return ""
+	}
+	others, err := se.db.GetSourcesByUserID(source.UserID)
+	if err != nil {
+		log.Printf("Self-loop check: failed to load sources for user %s: %v", source.UserID, err)
+		return ""
+	}
+	for _, other := range others {
+		if other.ID == source.ID || !other.Enabled || other.SourceType == db.SourceTypeICS {
+			continue
+		}
+		if sameEndpoint(other.SourceURL, other.SourceUsername, source.DestURL, source.DestUsername) &&
+			sameEndpoint(other.DestURL, other.DestUsername, source.SourceURL, source.SourceUsername) {
+			return fmt.Sprintf("source %q syncs the same calendars in the opposite direction", other.Name)
+		}
+	}
+	return ""
+}
+
+// resolveDestCalendarPath picks the destination calendar the same way
+// syncCalendar and syncEventsToDestination do: the first calendar
+// discovered under the destination's principal and home set, or the
+// URL's own path when discovery fails or finds nothing.
+func resolveDestCalendarPath(ctx context.Context, source *db.Source, destClient *Client) string {
+	var destCalendars []Calendar
+	var err error
+	if IsGoogleURL(source.DestURL) {
+		destCalendars, err = destClient.FindCalendarsGoogle(ctx)
+	} else {
+		destCalendars, err = destClient.FindCalendars(ctx)
+	}
+	if err != nil || len(destCalendars) == 0 {
+		return destClient.GetCalendarPath()
+	}
+	return destCalendars[0].Path
+}
+
+// selfLoopReason explains why syncing sourceCalendars to the
+// destination would write events back into the calendars they came
+// from, or returns "" when it wouldn't. Both sides' calendars are
+// resolved through principal and home-set discovery and compared as
+// absolute collection URLs, so a DAV root on one side and a pasted
+// calendar URL on the other are still recognized as the same
+// collection.
+func (se *SyncEngine) selfLoopReason(ctx context.Context, source *db.Source, sourceClient, destClient *Client, sourceCalendars []Calendar) string {
+	if reason := se.configuredSelfLoopReason(source); reason != "" {
+		return reason
+	}
+	destURL := normalizeCollectionURL(destClient.buildURL(resolveDestCalendarPath(ctx, source, destClient)))
+	for _, cal := range sourceCalendars {
+		if normalizeCollectionURL(sourceClient.buildURL(cal.Path)) == destURL {
+			return fmt.Sprintf("calendar %q is both the source and the destination", cal.Name)
+		}
+	}
+	return ""
+}
+
+// DetectSelfLoop reports whether the source's destination resolves to
+// one of the calendars it syncs from, or mirrors another source, so
+// that syncing would multiply events. SyncSource runs the same check
+// and refuses to sync; this entry point lets callers ask beforehand.
+// ICS feeds are read-only and never loop.
+func (se *SyncEngine) DetectSelfLoop(ctx context.Context, source *db.Source) (bool, error) {
+	if source.SourceType == db.SourceTypeICS {
+		return false, nil
+	}
+	if se.configuredSelfLoopReason(source) != "" {
+		return true, nil
+	}
+
+	var sourceClient *Client
+	var err error
+	if source.SourceType == db.SourceTypeGoogle {
+		// Google calendars are derived from the URL alone (see
+		// FindCalendarsGoogle), so no OAuth token is needed here.
+		sourceClient, err = NewClient(source.SourceURL, "", "", se.ClientOptions(source)...)
+	} else {
+		sourcePassword, decErr := se.encryptor.Decrypt(source.SourcePassword)
+		if decErr != nil {
+			return false, fmt.Errorf("failed to decrypt source credentials: %w", decErr)
+		}
+		sourceClient, err = se.cachedClient(source.SourceURL, source.SourceUsername, sourcePassword, source)
+	}
+	if err != nil {
+		return false, err
+	}
+	destPassword, err := se.encryptor.Decrypt(source.DestPassword)
+	if err != nil {
+		return false, fmt.Errorf("failed to decrypt destination credentials: %w", err)
+	}
+	destClient, err := se.cachedClient(source.DestURL, source.DestUsername, destPassword, source)
+	if err != nil {
+		return false, err
+	}
+
+	var sourceCalendars []Calendar
+	if source.SourceType == db.SourceTypeGoogle {
+		sourceCalendars, err = sourceClient.FindCalendarsGoogle(ctx)
+	} else {
+		sourceCalendars, err = sourceClient.FindCalendars(ctx)
+	}
+	if err != nil {
+		return false, err
+	}
+	return se.selfLoopReason(ctx, source, sourceClient, destClient, filterSelectedCalendars(source, sourceCalendars)) != "", nil
+}
@@ -0,0 +1,203 @@
+package caldav
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/crypto"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func TestNormalizeCollectionURL(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"https://dav.example.com/cal/work/", "https://dav.example.com/cal/work", true},
+		{"HTTPS://DAV.Example.com:443/cal/work/", "https://dav.example.com/cal/work/", true},
+		{"https://dav.example.com/cal//work/./", "https://dav.example.com/cal/work/", true},
+		{"https://dav.example.com/cal/my%20work/", "https://dav.example.com/cal/my work/", true},
+		{"https://alice@dav.example.com/cal/work/?x=1", "https://dav.example.com/cal/work/", true},
+		{"https://dav.example.com/cal/work/", "https://dav.example.com/cal/home/", false},
+		{"https://dav.example.com:8443/cal/work/", "https://dav.example.com/cal/work/", false},
+		{"https://a.example.com/cal/work/", "https://b.example.com/cal/work/", false},
+	}
+	for _, tt := range tests {
+		got := normalizeCollectionURL(tt.a) == normalizeCollectionURL(tt.b)
+		if got != tt.same {
+			t.Errorf("%q vs %q: same = %v, want %v (%q, %q)", tt.a, tt.b, got, tt.same,
+				normalizeCollectionURL(tt.a), normalizeCollectionURL(tt.b))
+		}
+	}
+}
+
+// selfLoopFixture wires a sync engine to a real database and encryptor
+// so DetectSelfLoop can decrypt credentials and load sibling sources.
+type selfLoopFixture struct {
+	database *db.DB
+	engine   *SyncEngine
+	userID   string
+	encrypt  func(string) string
+}
+
+func newSelfLoopFixture(t *testing.T) *selfLoopFixture {
+	t.Helper()
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	encryptor, err := crypto.NewEncryptor(make([]byte, 32))
+	if err != nil {
+		t.Fatalf("NewEncryptor: %v", err)
+	}
+	user, err := database.GetOrCreateUser("loop@example.com", "Loop")
+	if err != nil {
+		t.Fatalf("GetOrCreateUser: %v", err)
+	}
+	return &selfLoopFixture{
+		database: database,
+		engine:   NewSyncEngine(database, encryptor),
+		userID:   user.ID,
+		encrypt: func(s string) string {
+			enc, err := encryptor.Encrypt(s)
+			if err != nil {
+				t.Fatalf("Encrypt: %v", err)
+			}
+			return enc
+		},
+	}
+}
+
+func (f *selfLoopFixture) createSource(t *testing.T, name, sourceURL, destURL string) *db.Source {
+	t.Helper()
+	source := &db.Source{
+		UserID:           f.userID,
+		Name:             name,
+		SourceType:       db.SourceTypeCustom,
+		SourceURL:        sourceURL,
+		SourceUsername:   "alice",
+		SourcePassword:   f.encrypt("source-pass"),
+		DestURL:          destURL,
+		DestUsername:     "alice",
+		DestPassword:     f.encrypt("dest-pass"),
+		SyncInterval:     60,
+		SyncDirection:    db.SyncDirectionTwoWay,
+		ConflictStrategy: db.ConflictSourceWins,
+		Enabled:          true,
+	}
+	if err := f.database.CreateSource(source); err != nil {
+		t.Fatalf("CreateSource: %v", err)
+	}
+	return source
+}
+
+func TestDetectSelfLoop(t *testing.T) {
+	t.Run("identical URLs are flagged without contacting the server", func(t *testing.T) {
+		f := newSelfLoopFixture(t)
+		// Nothing listens here; a network check would fail.
+		source := f.createSource(t, "Same", "http://127.0.0.1:1/cal/work/", "http://127.0.0.1:1/cal/work")
+
+		loop, err := f.engine.DetectSelfLoop(context.Background(), source)
+		if err != nil {
+			t.Fatalf("DetectSelfLoop: %v", err)
+		}
+		if !loop {
+			t.Error("expected identical source and destination to be flagged")
+		}
+	})
+
+	t.Run("different URLs resolving to the same calendar are flagged", func(t *testing.T) {
+		srv := httptest.NewServer(&nextcloudMock{})
+		defer srv.Close()
+		f := newSelfLoopFixture(t)
+		// The source is the DAV root; the destination is the calendar
+		// URL copied from the UI. Both resolve to alice/personal.
+		source := f.createSource(t, "Overlap", srv.URL+"/remote.php/dav/", srv.URL+"/remote.php/dav/calendars/alice/personal/")
+
+		loop, err := f.engine.DetectSelfLoop(context.Background(), source)
+		if err != nil {
+			t.Fatalf("DetectSelfLoop: %v", err)
+		}
+		if !loop {
+			t.Error("expected overlapping source and destination to be flagged")
+		}
+	})
+
+	t.Run("distinct servers pass", func(t *testing.T) {
+		srcSrv := httptest.NewServer(&nextcloudMock{})
+		defer srcSrv.Close()
+		destSrv := httptest.NewServer(&nextcloudMock{})
+		defer destSrv.Close()
+		f := newSelfLoopFixture(t)
+		source := f.createSource(t, "Distinct", srcSrv.URL+"/remote.php/dav/", destSrv.URL+"/remote.php/dav/")
+
+		loop, err := f.engine.DetectSelfLoop(context.Background(), source)
+		if err != nil {
+			t.Fatalf("DetectSelfLoop: %v", err)
+		}
+		if loop {
+			t.Error("expected distinct source and destination to pass")
+		}
+	})
+
+	t.Run("sources mirroring each other are flagged", func(t *testing.T) {
+		f := newSelfLoopFixture(t)
+		a := f.createSource(t, "A to B", "http://127.0.0.1:1/a/", "http://127.0.0.1:1/b/")
+		f.createSource(t, "B to A", "http://127.0.0.1:1/b/", "http://127.0.0.1:1/a/")
+
+		loop, err := f.engine.DetectSelfLoop(context.Background(), a)
+		if err != nil {
+			t.Fatalf("DetectSelfLoop: %v", err)
+		}
+		if !loop {
+			t.Error("expected mirrored sources to be flagged")
+		}
+	})
+
+	t.Run("ICS feeds never loop", func(t *testing.T) {
+		f := newSelfLoopFixture(t)
+		source := f.createSource(t, "Feed", "http://127.0.0.1:1/cal/work/", "http://127.0.0.1:1/cal/work/")
+		source.SourceType = db.SourceTypeICS
+
+		loop, err := f.engine.DetectSelfLoop(context.Background(), source)
+		if err != nil || loop {
+			t.Errorf("DetectSelfLoop = %v, %v; want false, nil", loop, err)
+		}
+	})
+}
+
+func TestSyncSource_RefusesSelfLoop(t *testing.T) {
+	mock := &nextcloudMock{}
+	srv := httptest.NewServer(mock)
+	defer srv.Close()
+	f := newSelfLoopFixture(t)
+	source := f.createSource(t, "Overlap", srv.URL+"/remote.php/dav/", srv.URL+"/remote.php/dav/calendars/alice/personal/")
+
+	result := f.engine.SyncSource(context.Background(), source)
+	if result.Success {
+		t.Fatalf("expected the sync to be refused: %+v", result)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "Sync loop detected") {
+		t.Errorf("expected a sync loop error, got %q", result.Errors)
+	}
+
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	for _, r := range mock.requests {
+		if !strings.HasPrefix(r, "PROPFIND ") {
+			t.Errorf("expected only discovery requests before refusing, got %q", r)
+		}
+	}
+
+	stored, err := f.database.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("GetSourceByID: %v", err)
+	}
+	if stored.LastSyncStatus != db.SyncStatusError {
+		t.Errorf("expected status %q, got %q", db.SyncStatusError, stored.LastSyncStatus)
+	}
+}
@@ -0,0 +1,108 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/emersion/go-ical"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// SourceStampProp is the property a source's stamp is written to.
+const SourceStampProp = "X-CALBRIDGE-SOURCE"
+
+// RenderSourceStamp expands source's SourceStamp template: {source_name}
+// becomes the source's name and {source_id} its ID. It returns "" when
+// the source doesn't stamp its events.
+func RenderSourceStamp(source *db.Source) string {
+	if source.SourceStamp == "" {
+		return ""
+	}
+	return strings.NewReplacer("{source_name}", source.Name, "{source_id}", source.ID).Replace(source.SourceStamp)
+}
+
+// sourceStampTransform is a pipeline step that sets SourceStampProp to
+// stamp on every VEVENT and VJOURNAL, replacing whatever value the
+// source had there.
+func sourceStampTransform(stamp string) EventTransform {
+	return func(cal *ical.Calendar) error {
+		for _, comp := range cal.Children {
+			if comp.Name != ical.CompEvent && comp.Name != ical.CompJournal {
+				continue
+			}
+			prop := ical.NewProp(SourceStampProp)
+			prop.SetText(stamp)
+			// X- properties are TEXT by default; no VALUE parameter needed.
+			prop.Params.Del(ical.ParamValue)
+			comp.Props.Set(prop)
+		}
+		return nil
+	}
+}
+
+// hasSourceStamp reports whether data's events carry stamp.
+func hasSourceStamp(data, stamp string) bool {
+	cal, err := parseICalendar(data)
+	if err != nil {
+		return false
+	}
+	entries, _ := calendarEntries(cal)
+	for _, entry := range entries {
+		if v, err := entry.Props.Text(SourceStampProp); err == nil && v == stamp {
+			return true
+		}
+	}
+	return false
+}
+
+// StampCleanupResult reports what DeleteStampedEvents removed.
+type StampCleanupResult struct {
+	Stamp   string   `json:"stamp"`
+	Deleted int      `json:"deleted"`
+	Failed  int      `json:"failed"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// DeleteStampedEvents deletes every event on source's destination
+// calendar whose SourceStampProp is stamp, whichever source wrote it;
+// events without the stamp are left alone. Only the primary
+// destination is cleaned up. Synced-event tracking is not touched:
+// the caller decides what the sources that wrote the events forget.
+func (se *SyncEngine) DeleteStampedEvents(ctx context.Context, source *db.Source, stamp string) (*StampCleanupResult, error) {
+	if stamp == "" {
+		return nil, errors.New("stamp is empty")
+	}
+	if source.DestReadOnly {
+		return nil, ErrDestReadOnly
+	}
+	destClient, err := se.undoDestClient(source, source.DestURL)
+	if err != nil {
+		return nil, err
+	}
+	destCalendarPath := se.primaryDestCalendarPath(ctx, source, destClient)
+	destEvents, err := destClient.GetEvents(ctx, destCalendarPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get destination events: %w", err)
+	}
+
+	result := &StampCleanupResult{Stamp: stamp}
+	for _, e := range destEvents {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if !hasSourceStamp(e.Data, stamp) {
+			continue
+		}
+		if err := destClient.DeleteEvent(ctx, e.Path); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", e.MatchKey(), err))
+			continue
+		}
+		result.Deleted++
+	}
+	log.Printf("Stamp cleanup for source %s: deleted %d events stamped %q (%d failed)", source.Name, result.Deleted, stamp, result.Failed)
+	return result, nil
+}
@@ -0,0 +1,84 @@
+package caldav
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func TestRenderSourceStamp(t *testing.T) {
+	source := &db.Source{ID: "src-1", Name: "Work", SourceStamp: "calbridge/{source_name}/{source_id}"}
+	if got := RenderSourceStamp(source); got != "calbridge/Work/src-1" {
+		t.Errorf("expected the placeholders expanded, got %q", got)
+	}
+	source.SourceStamp = ""
+	if got := RenderSourceStamp(source); got != "" {
+		t.Errorf("expected no stamp, got %q", got)
+	}
+}
+
+func TestSourceStamp_WrittenAndCleanedUp(t *testing.T) {
+	srcStore, destStore := newCalendarStore(), newCalendarStore()
+	srcSrv := httptest.NewServer(srcStore)
+	defer srcSrv.Close()
+	destSrv := httptest.NewServer(destStore)
+	defer destSrv.Close()
+
+	f := newSelfLoopFixture(t)
+	source := f.createSource(t, "Work", srcSrv.URL+"/cal/", destSrv.URL+"/dest/")
+	source.SyncDirection = db.SyncDirectionOneWay
+	source.SourceStamp = "calbridge:{source_name}"
+
+	sourceClient, err := NewClient(srcSrv.URL+"/cal/", "alice", "source-pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	destClient, err := NewClient(destSrv.URL+"/dest/", "alice", "dest-pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	events := make([]Event, 0, 2)
+	for _, uid := range []string{"a", "b"} {
+		data := mergeICS("UID:" + uid + "@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:Event " + uid + "\r\n")
+		etag := srcStore.set("/cal/"+uid+".ics", data)
+		events = append(events, Event{Path: "/cal/" + uid + ".ics", ETag: etag, UID: uid + "@example.com", Data: data})
+	}
+	// Events another source, and the user, put on the same calendar.
+	destStore.set("/dest/other.ics", mergeICS("UID:other@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260106T100000Z\r\n"+
+		"SUMMARY:Other\r\nX-CALBRIDGE-SOURCE:calbridge:Personal\r\n"))
+	destStore.set("/dest/manual.ics", mergeICS("UID:manual@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260107T100000Z\r\nSUMMARY:Manual\r\n"))
+
+	calendar := Calendar{Path: "/cal/", Name: "Cal"}
+	result := f.engine.syncEventsToDestination(context.Background(), source, sourceClient, destClient, events, calendar, 1, db.SyncDirectionOneWay)
+	if result.Created != 2 {
+		t.Fatalf("expected 2 events created, got %d (warnings %v)", result.Created, result.Warnings)
+	}
+	for _, uid := range []string{"a", "b"} {
+		data := destStore.data["/dest/"+uid+"@example.com.ics"]
+		if !strings.Contains(data, "X-CALBRIDGE-SOURCE:calbridge:Work\r\n") {
+			t.Errorf("expected %s to carry the stamp, got:\n%s", uid, data)
+		}
+	}
+
+	cleanup, err := f.engine.DeleteStampedEvents(context.Background(), source, "calbridge:Work")
+	if err != nil {
+		t.Fatalf("DeleteStampedEvents: %v", err)
+	}
+	if cleanup.Deleted != 2 || cleanup.Failed != 0 {
+		t.Fatalf("expected 2 deleted, got %+v", cleanup)
+	}
+	for _, path := range []string{"/dest/a@example.com.ics", "/dest/b@example.com.ics"} {
+		if _, ok := destStore.data[path]; ok {
+			t.Errorf("expected %s deleted", path)
+		}
+	}
+	for _, path := range []string{"/dest/other.ics", "/dest/manual.ics"} {
+		if _, ok := destStore.data[path]; !ok {
+			t.Errorf("expected %s left alone", path)
+		}
+	}
+}
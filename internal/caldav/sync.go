@@ -14,6 +14,7 @@ import (
 	"github.com/macjediwizard/calbridgesync/internal/activity"
 	"github.com/macjediwizard/calbridgesync/internal/crypto"
 	"github.com/macjediwizard/calbridgesync/internal/db"
+	"github.com/macjediwizard/calbridgesync/internal/notify"
 )
 
 // isAlreadyExistsError checks if the error indicates the event already exists (412 Precondition Failed).
@@ -512,10 +513,10 @@ func planReverseCreate(
 		if event.UID == "" {
 			continue
 		}
-		if _, existsOnSource := sourceEventMap[event.UID]; existsOnSource {
+		if _, existsOnSource := sourceEventMap[event.MatchKey()]; existsOnSource {
 			continue
 		}
-		if _, wasPrevSynced := previouslySyncedMap[event.UID]; wasPrevSynced {
+		if _, wasPrevSynced := previouslySyncedMap[event.MatchKey()]; wasPrevSynced {
 			continue
 		}
 		// Content dedupe: same Summary+StartTime already exists on
@@ -566,6 +567,105 @@ func isWithinSyncSafetyThreshold(syncedAt time.Time, sourceSyncInterval time.Dur
 	return syncedAt.After(threshold)
 }
 
+// filterSelectedCalendars narrows discovered calendars to the source's
+// selected_calendars. An empty selection means every calendar syncs.
+func filterSelectedCalendars(source *db.Source, calendars []Calendar) []Calendar {
+	if len(source.SelectedCalendars) == 0 {
+		return calendars
+	}
+	filtered, _, _, _ := matchSelectedCalendars(source.SelectedCalendars, calendars)
+	return filtered
+}
+
+// matchSelectedCalendars resolves a calendar selection against the
+// calendars discovered on the server. An entry whose path is gone is
+// matched by name instead, if exactly one calendar not otherwise
+// selected carries that name: servers that rotate collection IDs
+// (iCloud) or reorganize their tree would otherwise leave the source
+// syncing nothing.
+//
+// It returns the matched calendars in discovery order, the selection
+// with healed paths and newly learned names, whether that differs from
+// the input, and the entries that matched nothing.
+func matchSelectedCalendars(selection []db.CalendarConfig, calendars []Calendar) (matched []Calendar, updated []db.CalendarConfig, changed bool, unmatched []db.CalendarConfig) {
+	byPath := make(map[string]Calendar, len(calendars))
+	byName := make(map[string][]Calendar)
+	for _, cal := range calendars {
+		byPath[cal.Path] = cal
+		if cal.Name != "" {
+			byName[cal.Name] = append(byName[cal.Name], cal)
+		}
+	}
+
+	updated = append([]db.CalendarConfig(nil), selection...)
+	taken := make(map[string]bool)
+	found := make([]bool, len(updated))
+	for i, cfg := range updated {
+		cal, ok := byPath[cfg.Path]
+		if !ok {
+			continue
+		}
+		found[i] = true
+		taken[cal.Path] = true
+		if cfg.Name == "" && cal.Name != "" {
+			updated[i].Name = cal.Name
+			changed = true
+		}
+	}
+
+	for i, cfg := range updated {
+		if found[i] {
+			continue
+		}
+		var candidates []Calendar
+		for _, cal := range byName[cfg.Name] {
+			if !taken[cal.Path] {
+				candidates = append(candidates, cal)
+			}
+		}
+		if cfg.Name == "" || len(candidates) != 1 {
+			unmatched = append(unmatched, cfg)
+			continue
+		}
+		log.Printf("Selected calendar %q moved from %s to %s", cfg.Name, cfg.Path, candidates[0].Path)
+		updated[i].Path = candidates[0].Path
+		taken[candidates[0].Path] = true
+		changed = true
+	}
+
+	for _, cal := range calendars {
+		if taken[cal.Path] {
+			matched = append(matched, cal)
+		}
+	}
+	return matched, updated, changed, unmatched
+}
+
+// resolveSelectedCalendars narrows calendars to the source's selection
+// like filterSelectedCalendars, and also stores healed paths on the
+// source (in memory, and in the database unless this is a dry run) and
+// warns about selected calendars that no longer exist.
+func (se *SyncEngine) resolveSelectedCalendars(ctx context.Context, source *db.Source, calendars []Calendar, result *SyncResult) []Calendar {
+	matched, updated, changed, unmatched := matchSelectedCalendars(source.SelectedCalendars, calendars)
+	for _, cfg := range unmatched {
+		label := cfg.Path
+		if cfg.Name != "" {
+			label = fmt.Sprintf("%q (%s)", cfg.Name, cfg.Path)
+		}
+		result.Warnings = append(result.Warnings, fmt.Sprintf(
+			"Selected calendar %s was not found on the source and was not synced - re-select it in the source settings", label))
+	}
+	if changed {
+		source.SelectedCalendars = updated
+		if !IsDryRun(ctx) {
+			if err := se.db.UpdateSourceSelectedCalendars(source.ID, updated); err != nil {
+				log.Printf("Failed to store updated calendar selection for source %s: %v", source.Name, err)
+			}
+		}
+	}
+	return matched
+}
+
 // getSyncDirectionForCalendar returns the effective sync direction for a calendar.
 // It checks per-calendar settings first, then falls back to the source default.
 func getSyncDirectionForCalendar(source *db.Source, calendarPath string) db.SyncDirection {
@@ -579,6 +679,16 @@ func getSyncDirectionForCalendar(source *db.Source, calendarPath string) db.Sync
 	return source.SyncDirection
 }
 
+// icsSyncDirection returns the direction an ICS feed syncs in. A feed
+// can't be written to, so it always syncs one way; create-only is the
+// one variant it can use.
+func icsSyncDirection(source *db.Source) db.SyncDirection {
+	if source.SyncDirection == db.SyncDirectionOneWayCreateOnly {
+		return db.SyncDirectionOneWayCreateOnly
+	}
+	return db.SyncDirectionOneWay
+}
+
 // defaultOrphanDeleteRatioThreshold is the maximum fraction of previously-synced
 // events that can be deleted in a single one-way sync cycle before safety aborts.
 // Exceeding this threshold usually indicates an auth failure, broken source URL,
@@ -685,6 +795,22 @@ func rewriteDeletePathForDestination(sourcePath, destCalendarPath string) string
 	return strings.TrimSuffix(destCalendarPath, "/") + "/" + filename
 }
 
+// loadSyncedUIDs returns the UIDs earlier syncs of calendarHref wrote
+// to source's destination. A lookup failure is logged and yields none,
+// so nothing is deleted on its account.
+func (se *SyncEngine) loadSyncedUIDs(source *db.Source, calendarHref string) map[string]bool {
+	synced, err := se.db.GetSyncedEvents(source.ID, calendarHref)
+	if err != nil {
+		log.Printf("Failed to get synced events for source %s: %v", source.ID, err)
+		return map[string]bool{}
+	}
+	uids := make(map[string]bool, len(synced))
+	for _, e := range synced {
+		uids[e.EventUID] = true
+	}
+	return uids
+}
+
 // planOrphanDeletion determines which destination events should be deleted as
 // "orphans" during a one-way + source_wins sync.
 //
@@ -837,8 +963,34 @@ type SyncResult struct {
 	// DryRun indicates this result was computed without actually
 	// writing to the CalDAV servers. Counts are what WOULD happen.
 	DryRun bool `json:"dry_run,omitempty"`
+	// AuthCircuitOpen is set when the source's auth circuit is open:
+	// either this sync was skipped because of it, or this sync's auth
+	// failure opened or kept it open. See authCircuit.
+	AuthCircuitOpen bool `json:"auth_circuit_open,omitempty"`
+	// Canceled is set when the sync was stopped with ErrSyncCanceled
+	// partway through. Counts cover the work done before it stopped.
+	Canceled bool `json:"canceled,omitempty"`
+	// TimedOut is set when the sync ran past its maximum duration and
+	// was stopped with ErrSyncTimedOut. Such a sync has failed.
+	TimedOut bool `json:"timed_out,omitempty"`
+	// JournalsSkipped counts VJOURNAL entries left out because the
+	// source doesn't have SyncJournals set.
+	JournalsSkipped int `json:"journals_skipped,omitempty"`
+	// PutRejections counts the events destinations refused to store,
+	// keyed by the reason classifyPutRejection gave.
+	PutRejections map[string]int `json:"put_rejections,omitempty"`
+	// Calendar is the path a calendar-scoped sync was limited to; see
+	// SyncCalendar. Such a sync doesn't touch the source's status.
+	Calendar string `json:"calendar,omitempty"`
+	// DeletionsPending counts the deletions held back until they are
+	// confirmed on a later cycle; see SetDeleteConfirmations.
+	DeletionsPending int `json:"deletions_pending,omitempty"`
 }
 
+// SyncLogWarningsHeader is the line in a sync log's details after
+// which each line is one raw sync warning.
+const SyncLogWarningsHeader = "Warnings:"
+
 // sanitizeLogDetails removes potentially sensitive information from sync log details.
 // This prevents leaking server internal paths, stack traces, or network info.
 func sanitizeLogDetails(details string) string {
@@ -859,18 +1011,32 @@ func sanitizeLogDetails(details string) string {
 	return details
 }
 
+// defaultDBLockMaxRetries and defaultDBLockBackoffBase are the SQLite
+// lock retry settings used when the engine hasn't been tuned via
+// SetDBLockRetry. maxDBLockBackoff caps any single sleep so a large
+// retry count can't stall finishSync for minutes.
+const (
+	defaultDBLockMaxRetries  = 5
+	defaultDBLockBackoffBase = 100 * time.Millisecond
+	maxDBLockBackoff         = 5 * time.Second
+)
+
 // retryDBOperation retries a database operation with exponential backoff.
 // This helps handle SQLite "database is locked" errors during concurrent operations.
-func retryDBOperation(operation func() error, maxRetries int) error {
+// Attempt i sleeps baseBackoff*2^i (capped at maxDBLockBackoff) before retrying.
+func retryDBOperation(operation func() error, maxRetries int, baseBackoff time.Duration) error {
 	var lastErr error
 	for i := 0; i < maxRetries; i++ {
 		if err := operation(); err != nil {
 			lastErr = err
 			// Check if it's a busy/locked error worth retrying
 			if strings.Contains(err.Error(), "SQLITE_BUSY") || strings.Contains(err.Error(), "database is locked") {
-				backoff := time.Duration(100*(1<<i)) * time.Millisecond // 100ms, 200ms, 400ms, ...
-				if backoff > 5*time.Second {
-					backoff = 5 * time.Second
+				if i == maxRetries-1 {
+					break // Out of attempts; don't sleep for nothing
+				}
+				backoff := baseBackoff * time.Duration(1<<i) // 100ms, 200ms, 400ms, ... by default
+				if backoff > maxDBLockBackoff || backoff <= 0 {
+					backoff = maxDBLockBackoff
 				}
 				time.Sleep(backoff)
 				continue
@@ -886,7 +1052,77 @@ func retryDBOperation(operation func() error, maxRetries int) error {
 type SyncEngine struct {
 	db        *db.DB
 	encryptor *crypto.Encryptor
-	tracker   *activity.Tracker
+	tracker   Tracker
+
+	// SQLite lock retry tuning. Zero values fall back to
+	// defaultDBLockMaxRetries / defaultDBLockBackoffBase.
+	dbLockMaxRetries  int
+	dbLockBackoffBase time.Duration
+
+	// notifier delivers per-source result webhooks. Nil disables them.
+	notifier *notify.Notifier
+
+	// deleteConfirmations is how many consecutive cycles a tracked
+	// event must be missing before its deletion propagates. Values
+	// <= 1 delete on first sight (the historical behavior).
+	deleteConfirmations int
+
+	// userAgent is sent on every CalDAV request the engine makes.
+	userAgent string
+
+	// maxEvents and maxEventBytes are the per-calendar event count and
+	// per-event size guards passed to every client (0 = unlimited).
+	maxEvents     int
+	maxEventBytes int
+
+	// maxResponseBytes caps each CalDAV response body (0 = client
+	// default).
+	maxResponseBytes int64
+
+	// disableHTTP2 keeps the engine's clients on HTTP/1.1.
+	disableHTTP2 bool
+
+	// proxyURL and noProxy route the engine's clients through an
+	// egress proxy; see SetProxy.
+	proxyURL string
+	noProxy  string
+
+	// recurrencePolicy decides how invalid RRULEs are handled before
+	// a PUT; see SetRecurrencePolicy.
+	recurrencePolicy RecurrencePolicy
+	// encodeFailurePolicy decides how events the encoder refuses are
+	// written; see SetEncodeFailurePolicy.
+	encodeFailurePolicy EncodeFailurePolicy
+	// createDestCalendar makes syncs create a calendar on destinations
+	// that have none; see SetCreateDestCalendar.
+	createDestCalendar bool
+	// warningFailCount and warningFailPercent escalate syncs with too
+	// many warnings to failures; see SetWarningLimits.
+	warningFailCount   int
+	warningFailPercent int
+	// vanishedCalendarPolicy decides what happens to the destination
+	// copy of a calendar gone from the source; see
+	// SetVanishedCalendarPolicy.
+	vanishedCalendarPolicy VanishedCalendarPolicy
+	// deadLetterAfter is how many failed writes in a row dead-letter
+	// an event; see SetDeadLetterThreshold.
+	deadLetterAfter int
+
+	// clients keeps Basic Auth CalDAV clients alive between sync
+	// cycles; see cachedClient.
+	clients *clientCache
+
+	// authCircuit pauses sources whose credentials keep being rejected.
+	authCircuit *authCircuit
+
+	// connectAttempts and connectBackoff bound the retries of a sync's
+	// connection tests; see SetConnectionTestRetry.
+	connectAttempts int
+	connectBackoff  time.Duration
+
+	// backupDeletions keeps a restorable copy of every event a one-way
+	// sync deletes; see SetDeletionBackups.
+	backupDeletions bool
 }
 
 // NewSyncEngine creates a new sync engine. As of #79 the engine no
@@ -895,10 +1131,207 @@ type SyncEngine struct {
 // sync code builds an oauth2.Config per request from those columns.
 func NewSyncEngine(database *db.DB, encryptor *crypto.Encryptor) *SyncEngine {
 	return &SyncEngine{
-		db:        database,
-		encryptor: encryptor,
-		tracker:   activity.NewTracker(),
+		db:          database,
+		encryptor:   encryptor,
+		tracker:     activity.NewTracker(),
+		clients:     newClientCache(clientCacheTTL),
+		authCircuit: newAuthCircuit(defaultAuthFailureThreshold),
+	}
+}
+
+// SetDBLockRetry tunes how persistently the engine retries SQLite
+// writes that fail with "database is locked". Non-positive values
+// keep the defaults (5 attempts, 100ms base backoff). Wired from
+// DB_MAX_LOCK_RETRIES / DB_LOCK_BACKOFF_BASE_MS; call before the
+// scheduler starts.
+func (se *SyncEngine) SetDBLockRetry(maxRetries int, baseBackoff time.Duration) {
+	se.dbLockMaxRetries = maxRetries
+	se.dbLockBackoffBase = baseBackoff
+}
+
+// SetNotifier enables per-source result webhooks, sent through n after
+// every finished sync. Call before the scheduler starts.
+func (se *SyncEngine) SetNotifier(n *notify.Notifier) {
+	se.notifier = n
+}
+
+// SetUserAgent sets the User-Agent for the engine's CalDAV clients.
+// Wired from CALDAV_USER_AGENT; call before the scheduler starts.
+func (se *SyncEngine) SetUserAgent(ua string) {
+	se.userAgent = ua
+}
+
+// SetEventLimits sets the per-calendar event count cap and per-event
+// size cap for the engine's CalDAV clients. Wired from
+// CALDAV_MAX_EVENTS_PER_CALENDAR / CALDAV_MAX_EVENT_BYTES; call before
+// the scheduler starts.
+func (se *SyncEngine) SetEventLimits(maxEvents, maxEventBytes int) {
+	se.maxEvents = maxEvents
+	se.maxEventBytes = maxEventBytes
+}
+
+// SetMaxResponseBytes sets the cap on any single CalDAV response body
+// read by the engine's clients. Wired from CALDAV_MAX_RESPONSE_BYTES;
+// call before the scheduler starts.
+func (se *SyncEngine) SetMaxResponseBytes(n int64) {
+	se.maxResponseBytes = n
+}
+
+// SetHTTP2 controls whether the engine's clients negotiate HTTP/2.
+// Wired from CALDAV_DISABLE_HTTP2; call before the scheduler starts.
+func (se *SyncEngine) SetHTTP2(enabled bool) {
+	se.disableHTTP2 = !enabled
+}
+
+// SetRecurrencePolicy sets how events with an invalid RRULE are
+// written: repaired where possible (the default) or stripped to a
+// single instance. Wired from SYNC_INVALID_RRULE; call before the
+// scheduler starts.
+func (se *SyncEngine) SetRecurrencePolicy(policy RecurrencePolicy) {
+	se.recurrencePolicy = policy
+}
+
+// checkRecurrence applies the engine's recurrence policy to data,
+// recording a warning when an invalid RRULE was changed.
+func (se *SyncEngine) checkRecurrence(data string, result *SyncResult) string {
+	checked, warning := validateRecurrence(data, se.recurrencePolicy)
+	if warning != "" {
+		log.Printf("WARNING: %s", warning)
+		result.Warnings = append(result.Warnings, warning)
+	}
+	return checked
+}
+
+// ClientOptions returns the options every CalDAV client built for
+// source should carry: request pacing, User-Agent, custom headers,
+// the CA bundle, the proxy, event limits, the response size cap, the
+// HTTP/2 setting and the encode failure policy.
+func (se *SyncEngine) ClientOptions(source *db.Source) []ClientOption {
+	return []ClientOption{
+		WithMaxRequestsPerSecond(source.MaxRequestsPerSecond),
+		WithUserAgent(se.userAgent),
+		WithCustomHeaders(source.CustomHeaders),
+		WithCABundle(source.CABundle),
+		WithProxy(se.sourceProxyURL(source), se.noProxy),
+		WithEventLimits(se.maxEvents, se.maxEventBytes),
+		WithMaxResponseBytes(se.maxResponseBytes),
+		WithHTTP2(!se.disableHTTP2),
+		WithEncodeFailurePolicy(se.encodeFailurePolicy),
+	}
+}
+
+// SetDeleteConfirmations sets how many consecutive sync cycles an
+// event must be observed missing on one side before it is deleted from
+// the other. Wired from SYNC_DELETE_CONFIRMATIONS; call before the
+// scheduler starts.
+func (se *SyncEngine) SetDeleteConfirmations(n int) {
+	se.deleteConfirmations = n
+}
+
+// confirmDeletions records this cycle's planned deletions against the
+// destination at destURL and returns the keys that have now been
+// missing for deleteConfirmations cycles in a row (confirmed) and those
+// still waiting (pending). Keys not
+// planned this cycle have their counters reset, so a flapping server
+// that drops an event for one cycle never triggers its deletion.
+//
+// With confirmations disabled the keys pass straight through without a
+// DB round trip. On a DB error nothing is confirmed: a skipped delete
+// is retried next cycle, a wrong one can't be undone.
+func (se *SyncEngine) confirmDeletions(sourceID, calendarHref, destURL string, target db.DeletionTarget, keys []string) (confirmed, pending []string, err error) {
+	if se.deleteConfirmations <= 1 {
+		return keys, nil, nil
+	}
+	var counts map[string]int
+	if err := se.retryDB(func() error {
+		var recErr error
+		counts, recErr = se.db.RecordDeletionCandidates(sourceID, calendarHref, destURL, target, keys)
+		return recErr
+	}); err != nil {
+		return nil, keys, err
+	}
+	for _, key := range keys {
+		if counts[key] >= se.deleteConfirmations {
+			confirmed = append(confirmed, key)
+		} else {
+			pending = append(pending, key)
+		}
+	}
+	return confirmed, pending, nil
+}
+
+// applyDeleteConfirmations is confirmDeletions for callers that only
+// need the confirmed keys. Pending deletions are logged and a DB
+// failure is surfaced as a warning on result.
+func (se *SyncEngine) applyDeleteConfirmations(sourceID, calendarHref, destURL string, target db.DeletionTarget, keys []string, result *SyncResult) []string {
+	confirmed, _ := se.splitDeleteConfirmations(sourceID, calendarHref, destURL, target, keys, result)
+	return confirmed
+}
+
+// splitDeleteConfirmations wraps confirmDeletions with the logging and
+// warning handling shared by every deletion pass.
+func (se *SyncEngine) splitDeleteConfirmations(sourceID, calendarHref, destURL string, target db.DeletionTarget, keys []string, result *SyncResult) (confirmed, pending []string) {
+	confirmed, pending, err := se.confirmDeletions(sourceID, calendarHref, destURL, target, keys)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to record deletion confirmations (target: %s), deferring %d deletion(s): %v", target, len(keys), err)
+		log.Printf("%s", msg)
+		result.Warnings = append(result.Warnings, msg)
+	}
+	if len(pending) > 0 {
+		log.Printf("Deferring %d deletion(s) from %s until missing for %d consecutive syncs", len(pending), target, se.deleteConfirmations)
+	}
+	result.DeletionsPending += len(pending)
+	return confirmed, pending
+}
+
+// verifyWrite re-reads an event after a successful PUT when the source
+// has VerifyWrites set, turning a silently dropped write into a
+// warning. Skipped in dry-run, where nothing was written.
+func verifyWrite(ctx context.Context, source *db.Source, client *Client, calendarPath string, event *Event, result *SyncResult) {
+	if !source.VerifyWrites || IsDryRun(ctx) {
+		return
+	}
+	if err := client.VerifyEvent(ctx, calendarPath, event); err != nil {
+		msg := fmt.Sprintf("Write verification failed for event %s: %v", event.UID, err)
+		log.Printf("%s", msg)
+		result.Warnings = append(result.Warnings, msg)
+	}
+}
+
+// syncCanceled reports whether ctx is done and, the first time it is,
+// records the cancellation on result as a critical error. Loops call it
+// once per event so a shutdown or timeout stops the pass within one
+// request instead of after the whole calendar. Work already done stays
+// counted in result and is still recorded in synced_events.
+func syncCanceled(ctx context.Context, result *SyncResult) bool {
+	err := ctx.Err()
+	if err == nil {
+		return false
+	}
+	msg := fmt.Sprintf("sync canceled: %v", context.Cause(ctx))
+	for _, e := range result.Errors {
+		if e == msg {
+			return true
+		}
+	}
+	log.Printf("Sync canceled mid-pass after %d created, %d updated, %d deleted: %v",
+		result.Created, result.Updated, result.Deleted, err)
+	result.Errors = append(result.Errors, msg)
+	return true
+}
+
+// retryDB runs operation through retryDBOperation using the engine's
+// configured lock retry settings.
+func (se *SyncEngine) retryDB(operation func() error) error {
+	maxRetries := se.dbLockMaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultDBLockMaxRetries
+	}
+	baseBackoff := se.dbLockBackoffBase
+	if baseBackoff <= 0 {
+		baseBackoff = defaultDBLockBackoffBase
 	}
+	return retryDBOperation(operation, maxRetries, baseBackoff)
 }
 
 // googleScopes are the OAuth scopes every Google CalDAV sync needs.
@@ -941,11 +1374,6 @@ func (se *SyncEngine) buildPerSourceGoogleOAuthConfig(source *db.Source, redirec
 	}, nil
 }
 
-// GetActivityTracker returns the activity tracker for external use.
-func (se *SyncEngine) GetActivityTracker() *activity.Tracker {
-	return se.tracker
-}
-
 // SyncSource performs synchronization for a single source.
 func (se *SyncEngine) SyncSource(ctx context.Context, source *db.Source) *SyncResult {
 	start := time.Now()
@@ -953,19 +1381,37 @@ func (se *SyncEngine) SyncSource(ctx context.Context, source *db.Source) *SyncRe
 		Errors:   make([]string, 0),
 		Warnings: make([]string, 0),
 		DryRun:   IsDryRun(ctx),
+		Calendar: scopedCalendarPath(ctx),
+	}
+	source = readOnlyDestSource(source)
+
+	// A source whose credentials keep being rejected is only retried
+	// when its next probe is due. Dry runs are never held back.
+	if !result.DryRun {
+		if skipped := se.skipForAuthCircuit(source, result); skipped != nil {
+			skipped.Duration = time.Since(start)
+			return skipped
+		}
 	}
 
 	// Skip status update in dry-run mode — we don't want to
 	// change the source's last_sync_status/last_sync_at. (#150)
-	if !result.DryRun {
+	// Calendar-scoped syncs leave it alone too.
+	if !result.DryRun && result.Calendar == "" {
 		// Update status to running (with retry for concurrent access)
-		if err := retryDBOperation(func() error {
+		if err := se.retryDB(func() error {
 			return se.db.UpdateSourceSyncStatus(source.ID, db.SyncStatusRunning, "Sync in progress")
-		}, 5); err != nil {
+		}); err != nil {
 			log.Printf("Failed to update sync status after retries: %v", err)
 		}
 	}
 
+	// Deletions made by this run are backed up as one batch, which is
+	// what UndoLastSync restores.
+	if !result.DryRun {
+		ctx = withDeletionBatch(ctx)
+	}
+
 	// Branch for ICS sources (read-only feed, different sync path)
 	if source.SourceType == db.SourceTypeICS {
 		return se.syncICSSource(ctx, source)
@@ -984,7 +1430,7 @@ func (se *SyncEngine) SyncSource(ctx context.Context, source *db.Source) *SyncRe
 			result.Message = "Failed to decrypt source credentials"
 			result.Errors = append(result.Errors, decErr.Error())
 			result.Duration = time.Since(start)
-			se.finishSync(source.ID, result)
+			se.finishSync(ctx, source, result)
 			return result
 		}
 		sourcePassword = decPassword
@@ -995,7 +1441,7 @@ func (se *SyncEngine) SyncSource(ctx context.Context, source *db.Source) *SyncRe
 		result.Message = "Failed to decrypt destination credentials"
 		result.Errors = append(result.Errors, err.Error())
 		result.Duration = time.Since(start)
-		se.finishSync(source.ID, result)
+		se.finishSync(ctx, source, result)
 		return result
 	}
 
@@ -1018,7 +1464,7 @@ func (se *SyncEngine) SyncSource(ctx context.Context, source *db.Source) *SyncRe
 			result.Message = "Google source is missing its OAuth refresh token — reconnect via the web UI"
 			result.Errors = append(result.Errors, result.Message)
 			result.Duration = time.Since(start)
-			se.finishSync(source.ID, result)
+			se.finishSync(ctx, source, result)
 			return result
 		}
 		perSourceOAuthConfig, cfgErr := se.buildPerSourceGoogleOAuthConfig(source, "")
@@ -1026,7 +1472,7 @@ func (se *SyncEngine) SyncSource(ctx context.Context, source *db.Source) *SyncRe
 			result.Message = cfgErr.Error()
 			result.Errors = append(result.Errors, cfgErr.Error())
 			result.Duration = time.Since(start)
-			se.finishSync(source.ID, result)
+			se.finishSync(ctx, source, result)
 			return result
 		}
 		refreshToken, decErr := se.encryptor.Decrypt(source.OAuthRefreshToken)
@@ -1034,71 +1480,66 @@ func (se *SyncEngine) SyncSource(ctx context.Context, source *db.Source) *SyncRe
 			result.Message = "Failed to decrypt Google OAuth refresh token"
 			result.Errors = append(result.Errors, decErr.Error())
 			result.Duration = time.Since(start)
-			se.finishSync(source.ID, result)
+			se.finishSync(ctx, source, result)
 			return result
 		}
 		token := &oauth2.Token{RefreshToken: refreshToken}
-		sourceClient, err = NewOAuthClient(ctx, source.SourceURL, perSourceOAuthConfig, token)
+		sourceClient, err = NewOAuthClient(ctx, source.SourceURL, perSourceOAuthConfig, token, se.ClientOptions(source)...)
 	} else {
-		sourceClient, err = NewClient(source.SourceURL, source.SourceUsername, sourcePassword)
+		sourceClient, err = se.cachedClient(source.SourceURL, source.SourceUsername, sourcePassword, source)
 	}
 	if err != nil {
 		result.Message = "Failed to connect to source"
 		result.Errors = append(result.Errors, err.Error())
 		result.Duration = time.Since(start)
-		se.finishSync(source.ID, result)
+		se.finishSync(ctx, source, result)
 		return result
 	}
 
 	// Create destination client
-	destClient, err := NewClient(source.DestURL, source.DestUsername, destPassword)
+	destClient, err := se.cachedClient(source.DestURL, source.DestUsername, destPassword, source)
 	if err != nil {
 		result.Message = "Failed to connect to destination"
 		result.Errors = append(result.Errors, err.Error())
 		result.Duration = time.Since(start)
-		se.finishSync(source.ID, result)
+		se.finishSync(ctx, source, result)
 		return result
 	}
+	destGuard := newDestGuard(source)
+	destClient = destGuard.client(destClient)
 
 	// Test connections — Google CalDAV doesn't support the standard
 	// FindCurrentUserPrincipal PROPFIND, so we use a different test. (#160)
 	if source.SourceType == db.SourceTypeGoogle {
-		if err := sourceClient.TestConnectionGoogle(ctx); err != nil {
-			result.Message = "Source connection test failed"
-			result.Errors = append(result.Errors, err.Error())
-			result.Duration = time.Since(start)
-			se.finishSync(source.ID, result)
-			return result
-		}
+		err = se.testConnection(ctx, "Source", sourceClient.TestConnectionGoogle)
 	} else {
-		if err := sourceClient.TestConnection(ctx); err != nil {
-			result.Message = "Source connection test failed"
-			result.Errors = append(result.Errors, err.Error())
-			result.Duration = time.Since(start)
-			se.finishSync(source.ID, result)
-			return result
-		}
+		err = se.testConnection(ctx, "Source", sourceClient.TestConnection)
+	}
+	if err != nil {
+		se.recordConnectionTest(source, result, err)
+		result.Message = "Source connection test failed"
+		result.Errors = append(result.Errors, err.Error())
+		result.Duration = time.Since(start)
+		se.finishSync(ctx, source, result)
+		return result
 	}
 
 	// Destination connection test — Google destinations need the same
 	// non-standard path as Google sources. (#165)
 	if IsGoogleURL(source.DestURL) {
-		if err := destClient.TestConnectionGoogle(ctx); err != nil {
-			result.Message = "Destination connection test failed"
-			result.Errors = append(result.Errors, err.Error())
-			result.Duration = time.Since(start)
-			se.finishSync(source.ID, result)
-			return result
-		}
+		err = se.testConnection(ctx, "Destination", destClient.TestConnectionGoogle)
 	} else {
-		if err := destClient.TestConnection(ctx); err != nil {
-			result.Message = "Destination connection test failed"
-			result.Errors = append(result.Errors, err.Error())
-			result.Duration = time.Since(start)
-			se.finishSync(source.ID, result)
-			return result
-		}
+		err = se.testConnection(ctx, "Destination", destClient.TestConnection)
+	}
+	se.recordConnectionTest(source, result, err)
+	if err != nil {
+		result.Message = "Destination connection test failed"
+		result.Errors = append(result.Errors, err.Error())
+		result.Duration = time.Since(start)
+		se.finishSync(ctx, source, result)
+		return result
 	}
+	se.recordClockSkew(source, sourceClient, destClient, result)
 
 	// Find calendars on source — Google needs a different discovery path. (#160)
 	var sourceCalendars []Calendar
@@ -1111,7 +1552,7 @@ func (se *SyncEngine) SyncSource(ctx context.Context, source *db.Source) *SyncRe
 		result.Message = "Failed to find source calendars"
 		result.Errors = append(result.Errors, err.Error())
 		result.Duration = time.Since(start)
-		se.finishSync(source.ID, result)
+		se.finishSync(ctx, source, result)
 		return result
 	}
 
@@ -1121,46 +1562,87 @@ func (se *SyncEngine) SyncSource(ctx context.Context, source *db.Source) *SyncRe
 		log.Printf("  [%d] Name: %q, Path: %s", i+1, cal.Name, cal.Path)
 	}
 
+	discoveredCalendars := sourceCalendars
+
 	// Filter calendars based on selected_calendars setting
 	if len(source.SelectedCalendars) > 0 {
-		selectedSet := make(map[string]bool)
-		for _, calConfig := range source.SelectedCalendars {
-			selectedSet[calConfig.Path] = true
-		}
+		filteredCalendars := se.resolveSelectedCalendars(ctx, source, sourceCalendars, result)
+		log.Printf("Filtered to %d selected calendars (from %d discovered)", len(filteredCalendars), len(sourceCalendars))
+		sourceCalendars = filteredCalendars
+	}
 
-		var filteredCalendars []Calendar
-		for _, cal := range sourceCalendars {
-			if selectedSet[cal.Path] {
-				filteredCalendars = append(filteredCalendars, cal)
-			}
+	// A calendar-scoped sync runs only the calendar it names, which has
+	// to be one of those left after selection.
+	if scope := calendarScopeFrom(ctx); scope != nil {
+		sourceCalendars = scope.narrow(sourceCalendars)
+		if len(sourceCalendars) == 0 {
+			result.Message = "Calendar not found on source"
+			result.Errors = append(result.Errors, fmt.Sprintf("Calendar %s is not one of this source's calendars", scope.path))
+			result.Duration = time.Since(start)
+			return result
 		}
+	}
 
-		log.Printf("Filtered to %d selected calendars (from %d discovered)", len(filteredCalendars), len(sourceCalendars))
-		sourceCalendars = filteredCalendars
+	// Refuse to sync a calendar into itself (or into a source that
+	// syncs straight back): every cycle would copy events again.
+	if reason := se.selfLoopReason(ctx, source, sourceClient, destClient, sourceCalendars); reason != "" {
+		log.Printf("Refusing to sync source %s: %s", source.Name, reason)
+		result.Message = "Source and destination are the same calendar - sync refused"
+		result.Errors = append(result.Errors, "Sync loop detected: "+reason)
+		result.Duration = time.Since(start)
+		se.finishSync(ctx, source, result)
+		return result
 	}
 
 	// Start activity tracking
-	se.tracker.StartSync(source.ID, source.Name, len(sourceCalendars))
+	se.progress().StartSync(source.ID, source.Name, len(sourceCalendars))
 
-	// Sync each calendar
+	// Sync each calendar. A calendar whose pass ends in errors, or that
+	// a cancellation never reaches, doesn't count as synced.
+	var failedCalendars []string
 	for i, cal := range sourceCalendars {
+		if syncCanceled(ctx, result) {
+			break
+		}
 		// Update activity tracker with current calendar
-		se.tracker.UpdateCalendar(source.ID, cal.Name, i+1)
+		se.progress().UpdateCalendar(source.ID, cal.Name, i+1)
 
 		calResult := se.syncCalendar(ctx, source, sourceClient, destClient, cal, i+1)
+		if len(calResult.Errors) > 0 {
+			label := cal.Path
+			if cal.Name != "" {
+				label = fmt.Sprintf("%q (%s)", cal.Name, cal.Path)
+			}
+			failedCalendars = append(failedCalendars, label)
+		} else if ctx.Err() == nil {
+			result.CalendarsSynced++
+		}
+		result.JournalsSkipped += calResult.JournalsSkipped
+		addPutRejections(result, calResult)
 		result.Created += calResult.Created
 		result.Updated += calResult.Updated
 		result.Deleted += calResult.Deleted
 		result.Skipped += calResult.Skipped
+		result.DeletionsPending += calResult.DeletionsPending
 		result.EventsProcessed += calResult.EventsProcessed
 		result.Errors = append(result.Errors, calResult.Errors...)
 		result.Warnings = append(result.Warnings, calResult.Warnings...)
 
 		// Update progress in activity tracker
-		se.tracker.UpdateProgress(source.ID, result.Created, result.Updated, result.Deleted, result.Skipped, result.EventsProcessed)
+		se.progress().UpdateProgress(source.ID, result.Created, result.Updated, result.Deleted, result.Skipped, result.EventsProcessed)
+	}
+
+	if len(failedCalendars) > 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%d of %d calendar(s) failed to sync: %s",
+			len(failedCalendars), len(sourceCalendars), strings.Join(failedCalendars, ", ")))
 	}
 
-	result.CalendarsSynced = len(sourceCalendars)
+	// Calendars that synced before but are gone from the source get
+	// the vanished-calendar policy. A calendar-scoped sync only looks
+	// at one calendar, so it leaves the others alone.
+	if calendarScopeFrom(ctx) == nil {
+		se.handleVanishedCalendars(ctx, source, destClient, discoveredCalendars, result)
+	}
 
 	// Multi-destination sync (#156): after syncing to the primary
 	// destination, check for additional destinations and sync to
@@ -1176,27 +1658,33 @@ func (se *SyncEngine) SyncSource(ctx context.Context, source *db.Source) *SyncRe
 		if !dest.Enabled {
 			continue
 		}
+		if syncCanceled(ctx, result) {
+			break
+		}
 		log.Printf("Syncing to additional destination: %s (%s)", dest.Name, dest.DestURL)
 		extraDestPassword, decErr := se.encryptor.Decrypt(dest.DestPassword)
 		if decErr != nil {
 			result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to decrypt credentials for additional dest %q: %v", dest.Name, decErr))
 			continue
 		}
-		extraDestClient, connErr := NewClient(dest.DestURL, dest.DestUsername, extraDestPassword)
+		extraDestClient, connErr := se.cachedClient(dest.DestURL, dest.DestUsername, extraDestPassword, source)
 		if connErr != nil {
 			result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to connect to additional dest %q: %v", dest.Name, connErr))
 			continue
 		}
+		extraDestClient = destGuard.client(extraDestClient)
 		if testErr := extraDestClient.TestConnection(ctx); testErr != nil {
 			result.Warnings = append(result.Warnings, fmt.Sprintf("Connection test failed for additional dest %q: %v", dest.Name, testErr))
 			continue
 		}
 		for i, cal := range sourceCalendars {
-			calResult := se.syncCalendar(ctx, source, sourceClient, extraDestClient, cal, i+1)
+			calResult := se.syncCalendar(withAdditionalDest(ctx), source, sourceClient, extraDestClient, cal, i+1)
+			addPutRejections(result, calResult)
 			result.Created += calResult.Created
 			result.Updated += calResult.Updated
 			result.Deleted += calResult.Deleted
 			result.Skipped += calResult.Skipped
+			result.DeletionsPending += calResult.DeletionsPending
 			result.EventsProcessed += calResult.EventsProcessed
 			result.Warnings = append(result.Warnings, calResult.Warnings...)
 			// Errors from additional dests are downgraded to warnings
@@ -1209,24 +1697,45 @@ func (se *SyncEngine) SyncSource(ctx context.Context, source *db.Source) *SyncRe
 		log.Printf("Completed sync to additional destination: %s", dest.Name)
 	}
 
+	destGuard.report(result)
+	reportPutRejections(result)
+
+	markCanceled(ctx, result)
+
 	// Success if no critical errors (warnings are OK)
 	result.Success = len(result.Errors) == 0
 	if result.Success && len(result.Warnings) == 0 {
 		result.Message = fmt.Sprintf("Synced %d calendar(s): %d created, %d updated, %d deleted, %d skipped",
-			len(sourceCalendars), result.Created, result.Updated, result.Deleted, result.Skipped)
+			result.CalendarsSynced, result.Created, result.Updated, result.Deleted, result.Skipped) + journalNote(result.JournalsSkipped)
 	} else if result.Success && len(result.Warnings) > 0 {
 		result.Message = fmt.Sprintf("Synced %d calendar(s) with %d warnings: %d created, %d updated, %d deleted, %d skipped",
-			len(sourceCalendars), len(result.Warnings), result.Created, result.Updated, result.Deleted, result.Skipped)
+			result.CalendarsSynced, len(result.Warnings), result.Created, result.Updated, result.Deleted, result.Skipped) + journalNote(result.JournalsSkipped)
+	} else if ctx.Err() != nil {
+		result.Message = fmt.Sprintf("Sync canceled: %d created, %d updated, %d deleted before stopping",
+			result.Created, result.Updated, result.Deleted)
 	} else {
 		result.Message = fmt.Sprintf("Sync failed with %d errors", len(result.Errors))
 	}
 
 	result.Duration = time.Since(start)
-	se.finishSync(source.ID, result)
+	se.finishSync(ctx, source, result)
 
 	return result
 }
 
+// additionalDestKey marks the context of a calendar pass to one of the
+// source's additional destinations (#156).
+type additionalDestKey struct{}
+
+func withAdditionalDest(ctx context.Context) context.Context {
+	return context.WithValue(ctx, additionalDestKey{}, true)
+}
+
+func isAdditionalDest(ctx context.Context) bool {
+	additional, _ := ctx.Value(additionalDestKey{}).(bool)
+	return additional
+}
+
 func (se *SyncEngine) syncCalendar(ctx context.Context, source *db.Source, sourceClient, destClient *Client, calendar Calendar, calendarIndex int) *SyncResult {
 	result := &SyncResult{
 		Errors:   make([]string, 0),
@@ -1240,9 +1749,49 @@ func (se *SyncEngine) syncCalendar(ctx context.Context, source *db.Source, sourc
 		return result
 	}
 
-	var syncToken string
+	var syncToken, prevCTag, prevLastModified string
 	if syncState != nil {
 		syncToken = syncState.SyncToken
+		prevCTag = syncState.CTag
+		prevLastModified = syncState.LastModified
+	}
+
+	// Read the calendar's CTag, sync-token and last-modified date up
+	// front (one PROPFIND). A server that reports none of them gets a
+	// full sync every cycle.
+	collState, err := sourceClient.GetCollectionState(ctx, calendar.Path)
+	if err != nil {
+		log.Printf("Failed to read CTag/sync-token for calendar %q, doing a full sync: %v", calendar.Name, err)
+		collState = &CollectionState{}
+	}
+
+	// Both shortcuts below only look at the source. A two-way calendar
+	// must also pick up destination changes, so it always syncs in full.
+	direction := getSyncDirectionForCalendar(source, calendar.Path)
+	oneWay := direction != db.SyncDirectionTwoWay
+
+	// The sync state is the primary destination's: by the time an
+	// additional destination is synced, the primary's pass has already
+	// recorded this cycle's CTag and token. An additional destination
+	// therefore takes none of the shortcuts and records no state; it
+	// gets a full sync every cycle.
+	primary := !isAdditionalDest(ctx)
+	// A calendar-scoped sync was asked to check this calendar; an
+	// unchanged marker doesn't excuse it.
+	skippable := primary && oneWay && !forcesCheck(ctx)
+
+	// Unchanged CTag: nothing in the source calendar changed since the
+	// last clean sync recorded it.
+	if skippable && collState.CTag != "" && collState.CTag == prevCTag {
+		log.Printf("Calendar %q unchanged (CTag %s), skipping", calendar.Name, collState.CTag)
+		return result
+	}
+	// Without a CTag, an unchanged collection last-modified date means
+	// the same. A CTag, when reported, always decides: not every server
+	// moves the collection's date when an event in it changes.
+	if skippable && collState.CTag == "" && collState.LastModified != "" && collState.LastModified == prevLastModified {
+		log.Printf("Calendar %q unchanged (last modified %s), skipping", calendar.Name, collState.LastModified)
+		return result
 	}
 
 	// Discover destination calendar path using the same logic as fullSync
@@ -1261,8 +1810,7 @@ func (se *SyncEngine) syncCalendar(ctx context.Context, source *db.Source, sourc
 		log.Printf("Failed to discover destination calendars, falling back to URL path: %v", discoverErr)
 		destCalendarPath = destClient.GetCalendarPath()
 	} else if len(destCalendars) == 0 {
-		log.Printf("No calendars found on destination, using URL path as fallback")
-		destCalendarPath = destClient.GetCalendarPath()
+		destCalendarPath = se.destCalendarFallback(ctx, sourceClient, destClient, calendar)
 	} else {
 		destCalendarPath = destCalendars[0].Path
 		if len(destCalendars) > 1 {
@@ -1270,17 +1818,86 @@ func (se *SyncEngine) syncCalendar(ctx context.Context, source *db.Source, sourc
 		}
 	}
 
-	// Try WebDAV-Sync if supported
-	if sourceClient.SupportsWebDAVSync(ctx, calendar.Path) {
+	// CTag changed and we hold a sync-token from an earlier sync: fetch
+	// just the delta. Holding a token means the server supports
+	// WebDAV-Sync, so there is no need to probe for it. Create-only
+	// calendars skip this: the delta applies updates and deletions, and
+	// only a full sync sees which UIDs the destination already has.
+	if primary && oneWay && direction != db.SyncDirectionOneWayCreateOnly && syncToken != "" {
 		syncResult, err := sourceClient.SyncCollection(ctx, calendar.Path, syncToken)
 		if err == nil {
 			// Process changes
+			pipeline, pipelineWarnings := buildTransformPipeline(source)
+			result.Warnings = append(result.Warnings, pipelineWarnings...)
+			canceled := false
+			var cancelledPaths, filteredPaths []string
+			failedEvents := se.loadFailedEvents(source, calendar.Path)
+			syncedUIDs := se.loadSyncedUIDs(source, calendar.Path)
+			// An event the source's filters now leave out was changed
+			// so it no longer matches; a copy an earlier sync wrote is
+			// removed below like a deleted event's.
+			dropFiltered := func(data string) {
+				uid := namespacedUID(source.UIDPrefix, icsUID(data))
+				if syncedUIDs[uid] {
+					filteredPaths = append(filteredPaths, putPath(destCalendarPath, &Event{UID: uid}))
+				}
+			}
 			for _, item := range syncResult.Changed {
+				if canceled = syncCanceled(ctx, result); canceled {
+					break
+				}
+				if item.Data != "" && len(source.IncludeCategories) > 0 && !eventHasCategory(item.Data, source.IncludeCategories) {
+					dropFiltered(item.Data)
+					continue
+				}
+				if source.SkipInvitedEvents && isInvitedEvent(item.Data, AccountEmail(source)) {
+					dropFiltered(item.Data)
+					continue
+				}
+				if !source.SyncJournals && isJournal(item.Data) {
+					result.JournalsSkipped++
+					continue
+				}
+				if source.SkipCancelled && isCancelled(item.Data) {
+					// Handled like a deletion: the copy, if an earlier
+					// sync wrote one, is removed below.
+					cancelledPaths = append(cancelledPaths, item.Path)
+					continue
+				}
 				if item.Data != "" {
 					event := &Event{
 						Path: item.Path,
 						ETag: item.ETag,
-						Data: item.Data,
+						Data: rewriteAttendees(item.Data, source.AttendeeRewrite),
+					}
+					if source.GenerateMissingUIDs {
+						if filled, ok := fillDataUID(event.Data); ok {
+							event.Data = filled
+							msg := fmt.Sprintf("Event %s has no UID; synced under generated UID %s", item.Path, icsUID(filled))
+							log.Printf("WARNING: %s", msg)
+							result.Warnings = append(result.Warnings, msg)
+						}
+					}
+					event.Data = se.checkRecurrence(event.Data, result)
+					event.Data = transformEvent(event.Data, pipeline, result)
+					if source.UIDPrefix != "" {
+						event.UID = icsUID(event.Data)
+						*event = applyUIDNamespace(*event, source.UIDPrefix)
+					}
+					if isDeadLettered(failedEvents, icsUID(event.Data), result) {
+						continue
+					}
+					if len(source.PreserveDestProps) > 0 {
+						// The delta doesn't carry the destination copy;
+						// read it from where PutEvent will write. No copy
+						// there means this is a create.
+						target := *event
+						if target.UID == "" {
+							target.UID = icsUID(target.Data)
+						}
+						if destData, err := destClient.fetchRawEvent(ctx, putPath(destCalendarPath, &target)); err == nil {
+							event.Data = withPreservedDestProps(source, event.Data, destData, result)
+						}
 					}
 					if err := destClient.PutEvent(ctx, destCalendarPath, event); err != nil {
 						if errors.Is(err, ErrEventSkipped) {
@@ -1288,11 +1905,17 @@ func (se *SyncEngine) syncCalendar(ctx context.Context, source *db.Source, sourc
 							// missing UID). Count it as skipped rather than
 							// falsely incrementing Updated.
 							result.Skipped++
+							se.recordUnencodable(source, item.Path, event.Data, err, result)
 						} else {
-							result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to sync event: %v", err))
+							recordPutFailure(result, "Failed to sync event", icsUID(event.Data), err)
+							if uid := icsUID(event.Data); uid != "" {
+								se.recordEventFailure(source, calendar.Path, uid, err, failedEvents, result)
+							}
 						}
 					} else {
 						result.Updated++
+						se.clearEventFailure(source, calendar.Path, event.UID, failedEvents)
+						verifyWrite(ctx, source, destClient, destCalendarPath, event, result)
 						// Track in synced_events so PR #22's ownership filter
 						// and two-way deletion logic can see these writes.
 						// PutEvent populates event.UID in-place when it
@@ -1334,12 +1957,31 @@ func (se *SyncEngine) syncCalendar(ctx context.Context, source *db.Source, sourc
 			// destination, so we rewrite each path through
 			// rewriteDeletePathForDestination. See that helper's doc comment
 			// for the full rationale.
-			for _, sourcePath := range syncResult.Deleted {
-				destEventPath := rewriteDeletePathForDestination(sourcePath, destCalendarPath)
+			deleted := append(append(syncResult.Deleted, cancelledPaths...), filteredPaths...)
+			if source.DestReadOnly {
+				// Nothing is ever deleted from a read-only destination.
+				deleted = nil
+			}
+			for _, sourcePath := range deleted {
+				if canceled = syncCanceled(ctx, result); canceled {
+					break
+				}
+				destEventPath := namespacedEventPath(rewriteDeletePathForDestination(sourcePath, destCalendarPath), source.UIDPrefix)
 				if destEventPath == "" {
 					log.Printf("Skipping delete for unrewriteable source path: %q", sourcePath)
 					continue
 				}
+				// The delta only names the deleted resource, so fetch
+				// the destination copy to back it up.
+				if se.backsUpDeletions(ctx, source) {
+					if existing, err := destClient.GetEvent(ctx, destEventPath); err == nil {
+						existing.Path = destEventPath
+						if existing.UID == "" {
+							existing.UID = extractUIDFromEventPath(destEventPath)
+						}
+						se.backupDeletedEvent(ctx, source, destClient, calendar.Path, destCalendarPath, *existing, result)
+					}
+				}
 				if err := destClient.DeleteEvent(ctx, destEventPath); err != nil {
 					// Don't count as error if event doesn't exist on destination
 					log.Printf("Failed to delete event (source: %s, dest: %s): %v", sourcePath, destEventPath, err)
@@ -1368,24 +2010,88 @@ func (se *SyncEngine) syncCalendar(ctx context.Context, source *db.Source, sourc
 				}
 			}
 
-			// Update sync state
-			newState := &db.SyncState{
-				SourceID:     source.ID,
-				CalendarHref: calendar.Path,
-				SyncToken:    syncResult.SyncToken,
+			// Keep the old token on cancellation so the next cycle
+			// re-fetches the changes this pass didn't get to.
+			if canceled {
+				return result
 			}
-			if err := se.db.UpsertSyncState(newState); err != nil {
-				log.Printf("Failed to update sync state: %v", err)
+
+			// Update sync state. The CTag and last-modified date are
+			// only recorded after a clean pass so a failed write isn't
+			// skipped next cycle.
+			if !IsDryRun(ctx) {
+				newState := &db.SyncState{
+					SourceID:     source.ID,
+					CalendarHref: calendar.Path,
+					SyncToken:    syncResult.SyncToken,
+				}
+				if len(result.Warnings) == 0 && result.DeletionsPending == 0 {
+					newState.CTag = collState.CTag
+					newState.LastModified = collState.LastModified
+				}
+				if err := se.db.UpsertSyncState(newState); err != nil {
+					log.Printf("Failed to update sync state: %v", err)
+				}
 			}
 
 			return result
 		}
 		// Fall through to full sync if WebDAV-Sync fails
 		log.Printf("WebDAV-Sync failed, falling back to full sync: %v", err)
+		if errors.Is(err, ErrInvalidSyncToken) && !IsDryRun(ctx) {
+			// Drop the rejected token so a failed full sync below
+			// can't leave it in place to be rejected again. The CTag
+			// goes too: it vouches for changes the delta never fetched.
+			cleared := &db.SyncState{SourceID: source.ID, CalendarHref: calendar.Path}
+			if err := se.db.UpsertSyncState(cleared); err != nil {
+				log.Printf("Failed to clear invalid sync token: %v", err)
+			}
+		}
 	}
 
-	// Full sync fallback
-	return se.fullSync(ctx, source, sourceClient, destClient, calendar, calendarIndex)
+	// No token yet (or the delta failed): enumerate the whole calendar.
+	// A first sync may cap how many source events it fetches.
+	fullCtx := ctx
+	capped := initialSyncCapped(source, syncState, direction)
+	if capped {
+		fullCtx = withInitialSync(ctx)
+	}
+	fullResult := se.fullSync(fullCtx, source, sourceClient, destClient, calendar, calendarIndex)
+	if primary && capped && len(fullResult.Errors) == 0 && ctx.Err() == nil && !IsDryRun(ctx) {
+		// Leave a sync state behind even when the pass below records
+		// none, so the next cycle isn't a first sync and lists the
+		// calendar uncapped.
+		if err := se.db.UpsertSyncState(&db.SyncState{SourceID: source.ID, CalendarHref: calendar.Path}); err != nil {
+			log.Printf("Failed to update sync state: %v", err)
+		}
+	}
+
+	// Record the markers read before the full sync, so anything that
+	// changed while it ran is picked up next cycle. A pass with errors
+	// or warnings records nothing, so the next cycle retries in full.
+	clean := len(fullResult.Errors) == 0 && len(fullResult.Warnings) == 0 && ctx.Err() == nil
+	if primary && clean && fullResult.DeletionsPending > 0 && !IsDryRun(ctx) {
+		// Deferred deletions are only confirmed by later full syncs
+		// observing them again. Clear the markers so the next cycle
+		// runs one even if the source doesn't change meanwhile.
+		if err := se.db.UpsertSyncState(&db.SyncState{SourceID: source.ID, CalendarHref: calendar.Path}); err != nil {
+			log.Printf("Failed to update sync state: %v", err)
+		}
+		clean = false
+	}
+	if primary && clean && !IsDryRun(ctx) && (collState.CTag != "" || collState.SyncToken != "" || collState.LastModified != "") {
+		newState := &db.SyncState{
+			SourceID:     source.ID,
+			CalendarHref: calendar.Path,
+			SyncToken:    collState.SyncToken,
+			CTag:         collState.CTag,
+			LastModified: collState.LastModified,
+		}
+		if err := se.db.UpsertSyncState(newState); err != nil {
+			log.Printf("Failed to update sync state: %v", err)
+		}
+	}
+	return fullResult
 }
 
 // filterEventsByDate filters events to only include those with start time after cutoff date.
@@ -1450,11 +2156,21 @@ func (se *SyncEngine) fullSync(ctx context.Context, source *db.Source, sourceCli
 
 	// Get the effective sync direction for this calendar (may be per-calendar or source default)
 	syncDirection := getSyncDirectionForCalendar(source, calendar.Path)
+	if source.UIDPrefix != "" && syncDirection == db.SyncDirectionTwoWay {
+		// Namespaced UIDs exist only on the destination; pushing them
+		// back would duplicate every event on the source. The API
+		// refuses this combination, but per-calendar overrides and
+		// older rows can still produce it.
+		msg := fmt.Sprintf("Calendar %q: UID prefix is set, syncing one-way instead of two-way", calendar.Name)
+		log.Printf("%s", msg)
+		result.Warnings = append(result.Warnings, msg)
+		syncDirection = db.SyncDirectionOneWay
+	}
 	log.Printf("Calendar %q sync direction: %s (source default: %s)", calendar.Name, syncDirection, source.SyncDirection)
 
 	// Helper to update status message during loading phases
 	updateStatus := func(status string) {
-		se.tracker.UpdateCalendar(source.ID, fmt.Sprintf("%s (%s)", calendar.Name, status), calendarIndex)
+		se.progress().UpdateCalendar(source.ID, fmt.Sprintf("%s (%s)", calendar.Name, status), calendarIndex)
 	}
 
 	// Create collector for malformed events from source
@@ -1467,11 +2183,21 @@ func (se *SyncEngine) fullSync(ctx context.Context, source *db.Source, sourceCli
 
 	// Get all events from source
 	updateStatus("fetching source events")
-	sourceEvents, err := sourceClient.GetEvents(ctx, calendar.Path, malformedCollector)
+	fetchCtx := sourceFetchContext(ctx, source)
+	sourceEvents, err := sourceClient.GetEvents(fetchCtx, calendar.Path, malformedCollector)
 	if err != nil {
 		result.Errors = append(result.Errors, fmt.Sprintf("Failed to get source events: %v", err))
 		return result
 	}
+	if n := eventCap(fetchCtx); n > 0 && len(sourceEvents) >= n {
+		msg := fmt.Sprintf("Calendar %q: first sync capped at the %d most recent events; the rest sync next cycle", calendar.Name, n)
+		log.Printf("%s", msg)
+		result.Warnings = append(result.Warnings, msg)
+	}
+	if msg := generatedUIDWarning(sourceEvents, calendar.Path); msg != "" {
+		log.Printf("WARNING: %s", msg)
+		result.Warnings = append(result.Warnings, msg)
+	}
 	updateStatus(fmt.Sprintf("loaded %d source events", len(sourceEvents)))
 
 	// Filter events by date if sync_days_past is configured
@@ -1486,6 +2212,16 @@ func (se *SyncEngine) fullSync(ctx context.Context, source *db.Source, sourceCli
 		}
 	}
 
+	// Keep only events tagged with one of the source's categories
+	if len(source.IncludeCategories) > 0 {
+		originalCount := len(sourceEvents)
+		sourceEvents = filterEventsByCategory(sourceEvents, source.IncludeCategories)
+		if filteredOut := originalCount - len(sourceEvents); filteredOut > 0 {
+			log.Printf("Filtered out %d events not in categories %v", filteredOut, source.IncludeCategories)
+			updateStatus(fmt.Sprintf("filtered to %d events (-%d by category)", len(sourceEvents), filteredOut))
+		}
+	}
+
 	// Store any malformed events found
 	for _, mf := range malformedCollector.GetEvents() {
 		if err := se.db.SaveMalformedEvent(source.ID, mf.Path, mf.ErrorMessage); err != nil {
@@ -1525,8 +2261,11 @@ func (se *SyncEngine) fullSync(ctx context.Context, source *db.Source, sourceCli
 		}
 	}
 
-	// Delegate to shared sync logic
-	return se.syncEventsToDestination(ctx, source, sourceClient, destClient, sourceEvents, calendar, calendarIndex, syncDirection)
+	// Delegate to shared sync logic, carrying over the warnings
+	// gathered above (direction downgrade, zombie masters).
+	destResult := se.syncEventsToDestination(ctx, source, sourceClient, destClient, sourceEvents, calendar, calendarIndex, syncDirection)
+	destResult.Warnings = append(result.Warnings, destResult.Warnings...)
+	return destResult
 }
 
 // syncEventsToDestination handles the comparison, creation, update, and deletion of events
@@ -1538,25 +2277,63 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 		Warnings: make([]string, 0),
 	}
 
+	// Journals are left alone on both sides unless the source opts in.
+	sourceEvents, result.JournalsSkipped = dropJournals(sourceEvents, source)
+
+	// Events the account was only invited to are left off when the
+	// source skips them. This reads ORGANIZER before attendee rewriting
+	// changes it.
+	sourceEvents, invited := dropInvited(sourceEvents, source)
+	if invited > 0 {
+		log.Printf("Skipping %d events %s was invited to", invited, AccountEmail(source))
+	}
+
 	// Sanitize VALARM blocks before comparison/PUT. Always strip malformed
 	// alarms (missing the RFC-required TRIGGER) so RFC-strict destinations
 	// like SOGo don't 501 the whole calendar object. When the user has
 	// flipped "Ignore alarms" for this source, strip every VALARM.
+	// Invalid RRULEs are repaired or stripped for the same reason.
+	// Attendee rewriting and the transform pipeline run here too so the
+	// comparison below sees the same bytes we'd PUT and doesn't
+	// re-upload every cycle. Text rules can change the summary, so the
+	// dedupe key is read again from what will be written. UID
+	// namespacing goes last: everything after this loop — destination
+	// matching, synced_events, PutEvent paths — uses the prefixed UID.
+	pipeline, pipelineWarnings := buildTransformPipeline(source)
+	result.Warnings = append(result.Warnings, pipelineWarnings...)
 	for i := range sourceEvents {
 		if sourceEvents[i].Data == "" {
 			continue
 		}
 		sourceEvents[i].Data = sanitizeAlarms(sourceEvents[i].Data, source.StripAlarms)
+		sourceEvents[i].Data = se.checkRecurrence(sourceEvents[i].Data, result)
+		sourceEvents[i].Data = rewriteAttendees(sourceEvents[i].Data, source.AttendeeRewrite)
+		sourceEvents[i].Data = transformEvent(sourceEvents[i].Data, pipeline, result)
+		if len(source.TextRules) > 0 {
+			sourceEvents[i].Summary = eventSummary(sourceEvents[i].Data)
+		}
+		sourceEvents[i] = applyUIDNamespace(sourceEvents[i], source.UIDPrefix)
+	}
+
+	// Cancelled events are left out when the source skips them; any
+	// copies an earlier sync wrote are deleted once the destination has
+	// been read.
+	sourceEvents, cancelledEvents := dropCancelled(sourceEvents, source)
+	if len(cancelledEvents) > 0 {
+		log.Printf("Skipping %d cancelled events", len(cancelledEvents))
 	}
 
+	// Overrides are PUT after their series master.
+	sourceEvents = mastersFirst(sourceEvents)
+
 	// Helper to update activity tracker with current progress
 	updateProgress := func() {
-		se.tracker.UpdateProgress(source.ID, result.Created, result.Updated, result.Deleted, result.Skipped, result.EventsProcessed)
+		se.progress().UpdateProgress(source.ID, result.Created, result.Updated, result.Deleted, result.Skipped, result.EventsProcessed)
 	}
 
 	// Helper to update status message during loading phases
 	updateStatus := func(status string) {
-		se.tracker.UpdateCalendar(source.ID, fmt.Sprintf("%s (%s)", calendar.Name, status), calendarIndex)
+		se.progress().UpdateCalendar(source.ID, fmt.Sprintf("%s (%s)", calendar.Name, status), calendarIndex)
 	}
 
 	// Discover destination calendar path - try calendar discovery first, then fall back to URL path.
@@ -1574,8 +2351,7 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 		log.Printf("Failed to discover destination calendars, falling back to URL path: %v", destDiscoverErr)
 		destCalendarPath = destClient.GetCalendarPath()
 	} else if len(destCalendars) == 0 {
-		log.Printf("No calendars found on destination, using URL path as fallback")
-		destCalendarPath = destClient.GetCalendarPath()
+		destCalendarPath = se.destCalendarFallback(ctx, sourceClient, destClient, calendar)
 	} else {
 		log.Printf("Found %d calendar(s) on destination:", len(destCalendars))
 		for i, cal := range destCalendars {
@@ -1591,6 +2367,13 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 	// Get all events from destination (no collector needed - we only track source issues)
 	updateStatus("fetching destination events")
 	destEvents, err := destClient.GetEvents(ctx, destCalendarPath, nil)
+	if errors.Is(err, ErrTooManyEvents) {
+		// Over the cap the destination can't be listed at all, not
+		// just this cycle. Treated as empty, every source event would
+		// be PUT again on every sync; skip the calendar instead.
+		result.Errors = append(result.Errors, fmt.Sprintf("Failed to get destination events: %v", err))
+		return result
+	}
 	if err != nil {
 		// Previously this failure only logged and then proceeded with
 		// an empty destEvents slice. That silently masked a real
@@ -1611,6 +2394,7 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 		result.Warnings = append(result.Warnings, msg)
 		destEvents = []Event{}
 	}
+	destEvents, _ = dropJournals(destEvents, source)
 	log.Printf("Fetched %d events from destination calendar", len(destEvents))
 
 	// Filter destination events by date if sync_days_past is configured
@@ -1639,18 +2423,36 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 		previouslySyncedMap[syncedEvt.EventUID] = syncedEvt
 	}
 
-	// Create maps for comparison by UID
+	// Events that keep failing to write are counted here; the
+	// dead-lettered ones are left out until retried.
+	failedEvents := se.loadFailedEvents(source, calendar.Path)
+
+	// Create maps for comparison by MatchKey (UID, plus RECURRENCE-ID
+	// for detached overrides that share their master's UID)
+	//
+	// Two source resources sharing a key means the source calendar is
+	// itself broken: the map keeps only the later one, and on the
+	// destination the later PUT overwrites the earlier. We don't try to
+	// repair that, but we do tell the user which resources collide.
 	sourceEventMap := make(map[string]Event)
 	for _, e := range sourceEvents {
-		if e.UID != "" {
-			sourceEventMap[e.UID] = e
+		if e.UID == "" {
+			continue
 		}
+		key := e.MatchKey()
+		if prev, dup := sourceEventMap[key]; dup {
+			msg := fmt.Sprintf("Duplicate UID %q in source calendar %s: %s and %s (the latter wins on the destination)",
+				key, calendar.Path, prev.Path, e.Path)
+			log.Printf("WARNING: %s", msg)
+			result.Warnings = append(result.Warnings, msg)
+		}
+		sourceEventMap[key] = e
 	}
 
 	destEventMap := make(map[string]Event)
 	for _, e := range destEvents {
 		if e.UID != "" {
-			destEventMap[e.UID] = e
+			destEventMap[e.MatchKey()] = e
 		}
 	}
 
@@ -1664,6 +2466,11 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 		}
 	}
 
+	if !source.DestReadOnly && syncDirection != db.SyncDirectionOneWayCreateOnly {
+		se.deleteCancelledFromDest(ctx, source, destClient, calendar.Path, destCalendarPath,
+			cancelledEvents, destEventMap, previouslySyncedMap, result)
+	}
+
 	skippedDupes := 0
 
 	// Track UIDs that exist in current sync (for updating synced_events
@@ -1702,7 +2509,13 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 	//     deferred to a follow-up. The shouldSkipTwoWayDeletion
 	//     guard is still consulted to short-circuit when the dest
 	//     query failed entirely.
-	if syncDirection == db.SyncDirectionTwoWay && sourceClient != nil {
+	//
+	// A canceled context skips everything past this point except the
+	// synced_events upsert at the end, which records the writes that
+	// did land so the next cycle doesn't redo them.
+	canceled := syncCanceled(ctx, result)
+	pendingSourceDelete := make(map[string]bool)
+	if !canceled && syncDirection == db.SyncDirectionTwoWay && sourceClient != nil {
 		// Step 1: dest-deletion via planTwoWayDeletion. The helper's
 		// three guards subsume the previous shouldSkipTwoWayDeletion
 		// check for this direction and add empty-source + ratio
@@ -1717,10 +2530,14 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 			log.Printf("WARNING: %s", deletionWarning)
 			result.Warnings = append(result.Warnings, deletionWarning)
 		}
+		toDeleteFromDest = se.applyDeleteConfirmations(source.ID, calendar.Path, destClient.baseURL, db.DeletionTargetDest, toDeleteFromDest, result)
 		// Track which UIDs the dest-deletion pass already handled so
 		// the source-deletion pass below skips them.
 		handledByDestDelete := make(map[string]bool, len(toDeleteFromDest))
 		for _, uid := range toDeleteFromDest {
+			if canceled = syncCanceled(ctx, result); canceled {
+				break
+			}
 			destEvent := destEventMap[uid]
 			log.Printf("Event %s deleted from source, deleting from destination", uid)
 			// performDeletionAndCleanup enforces the success-only
@@ -1767,10 +2584,21 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 			log.Printf("WARNING: %s", sourceDelWarning)
 			result.Warnings = append(result.Warnings, sourceDelWarning)
 		}
+		// Unconfirmed source deletions are held back from the forward
+		// pass too; re-creating them on the destination would make the
+		// event "present" again and reset its confirmation count.
+		var pendingSource []string
+		toDeleteFromSource, pendingSource = se.splitDeleteConfirmations(source.ID, calendar.Path, destClient.baseURL, db.DeletionTargetSource, toDeleteFromSource, result)
+		for _, uid := range pendingSource {
+			pendingSourceDelete[uid] = true
+		}
 		// Track UIDs handled by either deletion pass so the cleanup
 		// loop below skips them when reaping orphan synced_events.
 		handledBySourceDelete := make(map[string]bool, len(toDeleteFromSource))
 		for _, uid := range toDeleteFromSource {
+			if canceled = syncCanceled(ctx, result); canceled {
+				break
+			}
 			if handledByDestDelete[uid] {
 				continue
 			}
@@ -1821,8 +2649,13 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 		// Cleanup pass: walk previouslySyncedMap one more time for
 		// the "deleted from both" case. Skip UIDs already handled by
 		// either deletion pass above to avoid double-deletes from
-		// synced_events.
+		// synced_events. Skipped on cancellation: the maps above are
+		// only partially reconciled, so "missing from both" can't be
+		// trusted.
 		for uid, syncedEvent := range previouslySyncedMap {
+			if canceled {
+				break
+			}
 			if handledByDestDelete[uid] || handledBySourceDelete[uid] {
 				continue
 			}
@@ -1842,13 +2675,62 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 		}
 	}
 
+	// Under the merge strategy an event edited on both sides is merged
+	// instead of overwritten. That needs the source writable, so it only
+	// applies to two-way sync.
+	mergeConflicts := source.ConflictStrategy == db.ConflictMerge &&
+		syncDirection == db.SyncDirectionTwoWay && sourceClient != nil
+
+	// Create-only sync writes source events the destination doesn't
+	// have and leaves every event it does have alone, however the two
+	// copies differ. An event it created before and that has since
+	// gone from the destination was deleted there on purpose, so it
+	// isn't created again either.
+	createOnly := syncDirection == db.SyncDirectionOneWayCreateOnly
+
+	// Under latest_wins an event edited on both sides goes to the side
+	// that changed it last, with the source's timestamps corrected for
+	// clock skew between the servers. The source winning is the
+	// ordinary update below; only a newer destination is handled here.
+	latestWins := source.ConflictStrategy == db.ConflictLatestWins &&
+		syncDirection == db.SyncDirectionTwoWay && sourceClient != nil
+	clockSkew := time.Duration(source.ClockSkewSeconds) * time.Second
+
 	// Sync source events to destination
 	for _, sourceEvent := range sourceEvents {
+		if canceled = syncCanceled(ctx, result); canceled {
+			break
+		}
 		if sourceEvent.UID == "" {
 			continue
 		}
+		if pendingSourceDelete[sourceEvent.MatchKey()] {
+			continue
+		}
+		if isDeadLettered(failedEvents, sourceEvent.MatchKey(), result) {
+			// Left for manual review. Its destination copy, if any,
+			// stays as it is rather than looking destination-only.
+			result.EventsProcessed++
+			updateProgress()
+			delete(destEventMap, sourceEvent.MatchKey())
+			continue
+		}
+
+		destEvent, existsByUID := destEventMap[sourceEvent.MatchKey()]
 
-		destEvent, existsByUID := destEventMap[sourceEvent.UID]
+		if createOnly {
+			if prior, synced := previouslySyncedMap[sourceEvent.MatchKey()]; existsByUID || synced {
+				entry := syncETagEntry{sourceETag: sourceEvent.ETag, destETag: destEvent.ETag}
+				if !existsByUID {
+					entry.destETag = prior.DestETag
+				}
+				currentUIDs[sourceEvent.MatchKey()] = entry
+				result.EventsProcessed++
+				updateProgress()
+				delete(destEventMap, sourceEvent.MatchKey())
+				continue
+			}
+		}
 
 		if !existsByUID {
 			// Check for duplicate by content
@@ -1863,19 +2745,30 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 				continue
 			}
 
-			// Create new event on destination
-			if err := destClient.PutEvent(ctx, destCalendarPath, &sourceEvent); err != nil {
+			// Create new event on destination. Another client may
+			// have created the same UID since the destination was
+			// listed; createEvent then updates that copy instead.
+			updated, err := createEvent(ctx, destClient, destCalendarPath, &sourceEvent)
+			if err != nil {
 				if errors.Is(err, ErrEventSkipped) {
 					// PutEvent refused (empty data, missing UID). Count
 					// it as skipped. Do NOT mark the event as "ours" in
 					// destDedupeMap or currentUIDs since nothing was
 					// actually written to the destination.
 					result.Skipped++
+					se.recordUnencodable(source, sourceEvent.Path, sourceEvent.Data, err, result)
 				} else {
-					result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to create event on dest: %v", err))
+					recordPutFailure(result, "Failed to create event on dest", sourceEvent.UID, err)
+					se.recordEventFailure(source, calendar.Path, sourceEvent.MatchKey(), err, failedEvents, result)
 				}
 			} else {
-				result.Created++
+				if updated {
+					result.Updated++
+				} else {
+					result.Created++
+				}
+				se.clearEventFailure(source, calendar.Path, sourceEvent.MatchKey(), failedEvents)
+				verifyWrite(ctx, source, destClient, destCalendarPath, &sourceEvent, result)
 				if dedupeKey != "|" {
 					destDedupeMap[dedupeKey] = true
 				}
@@ -1884,18 +2777,38 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 				// yet — PutEvent does not return one on create; the
 				// next cycle will read it from PROPFIND and populate
 				// the dest side at that point. (#79)
-				currentUIDs[sourceEvent.UID] = syncETagEntry{sourceETag: sourceEvent.ETag}
+				currentUIDs[sourceEvent.MatchKey()] = syncETagEntry{sourceETag: sourceEvent.ETag}
 			}
 			result.EventsProcessed++
 			updateProgress()
-		} else if shouldUpdateDestFromSource(sourceEvent.ETag, previouslySyncedMap[sourceEvent.UID]) {
+		} else if mergeConflicts && isMergeConflict(sourceEvent.ETag, destEvent.ETag, previouslySyncedMap[sourceEvent.MatchKey()]) {
+			// Both sides changed since the last sync and the source is
+			// configured to merge rather than pick a winner.
+			if entry, ok := se.writeMergedEvent(ctx, source, sourceClient, destClient, calendar.Path, destCalendarPath,
+				sourceEvent, destEvent, result); ok {
+				currentUIDs[sourceEvent.MatchKey()] = entry
+			}
+			result.EventsProcessed++
+			updateProgress()
+		} else if latestWins && isMergeConflict(sourceEvent.ETag, destEvent.ETag, previouslySyncedMap[sourceEvent.MatchKey()]) &&
+			destIsNewer(sourceEvent.Data, destEvent.Data, clockSkew) {
+			if entry, ok := se.writeDestToSource(ctx, source, sourceClient, calendar.Path, sourceEvent, destEvent, result); ok {
+				currentUIDs[sourceEvent.MatchKey()] = entry
+			}
+			result.EventsProcessed++
+			updateProgress()
+		} else if shouldUpdateDestFromSource(sourceEvent.ETag, previouslySyncedMap[sourceEvent.MatchKey()]) {
 			// Source ETag has changed since the last recorded sync
 			// (or this is a first-time update with tracked ETags).
 			// Only then do we actually PUT. Comparing sourceEvent.ETag
 			// against destEvent.ETag directly is WRONG — they come
 			// from different servers and will never match, which was
 			// the cause of the infinite re-PUT loop fixed in #79.
+			sourcePath := sourceEvent.Path
 			sourceEvent.Path = destEvent.Path
+			if len(source.PreserveDestProps) > 0 {
+				sourceEvent.Data = withPreservedDestProps(source, sourceEvent.Data, destEvent.Data, result)
+			}
 			if err := destClient.PutEvent(ctx, destCalendarPath, &sourceEvent); err != nil {
 				if errors.Is(err, ErrEventSkipped) {
 					// PutEvent refused. Don't add to currentUIDs —
@@ -1903,11 +2816,15 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 					// this event, not an updated one, so we should
 					// not track it as freshly synced.
 					result.Skipped++
+					se.recordUnencodable(source, sourcePath, sourceEvent.Data, err, result)
 				} else {
-					result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to update event on dest: %v", err))
+					recordPutFailure(result, "Failed to update event on dest", sourceEvent.UID, err)
+					se.recordEventFailure(source, calendar.Path, sourceEvent.MatchKey(), err, failedEvents, result)
 				}
 			} else {
 				result.Updated++
+				se.clearEventFailure(source, calendar.Path, sourceEvent.MatchKey(), failedEvents)
+				verifyWrite(ctx, source, destClient, destCalendarPath, &sourceEvent, result)
 				// Log conflict resolution for the UI (#136, refined in #169).
 				//
 				// A routine source→dest update is NOT a conflict — it's
@@ -1928,7 +2845,7 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 				// warnings list in false-positive "conflicts" that were
 				// in fact just routine propagation.
 				if syncDirection == db.SyncDirectionTwoWay &&
-					isRealConflictSourceWins(previouslySyncedMap[sourceEvent.UID], destEvent.ETag) {
+					isRealConflictSourceWins(previouslySyncedMap[sourceEvent.MatchKey()], destEvent.ETag) {
 					result.Warnings = append(result.Warnings, fmt.Sprintf(
 						"CONFLICT:{\"uid\":%q,\"winner\":\"source\",\"summary\":%q,\"strategy\":%q}",
 						sourceEvent.UID, sourceEvent.Summary, source.ConflictStrategy))
@@ -1943,9 +2860,16 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 				// either see this stale value (correctly triggering
 				// an update back to source on the first cycle where
 				// it runs) or the refreshed value. (#79)
-				currentUIDs[sourceEvent.UID] = syncETagEntry{
+				destETag := destEvent.ETag
+				if mergeConflicts {
+					// The merge check needs the dest ETag we just
+					// produced; the stale one would make the next
+					// source edit look like a conflict.
+					destETag = currentETag(ctx, destClient, sourceEvent.Path, destETag)
+				}
+				currentUIDs[sourceEvent.MatchKey()] = syncETagEntry{
 					sourceETag: sourceEvent.ETag,
-					destETag:   destEvent.ETag,
+					destETag:   destETag,
 				}
 			}
 			result.EventsProcessed++
@@ -1955,14 +2879,14 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 			// still track it so the synced_events upsert at end of
 			// pass keeps it alive. Record both ETags from this cycle
 			// so the next cycle has fresh reference points. (#79)
-			currentUIDs[sourceEvent.UID] = syncETagEntry{
+			currentUIDs[sourceEvent.MatchKey()] = syncETagEntry{
 				sourceETag: sourceEvent.ETag,
 				destETag:   destEvent.ETag,
 			}
 			result.EventsProcessed++
 			updateProgress()
 		}
-		delete(destEventMap, sourceEvent.UID)
+		delete(destEventMap, sourceEvent.MatchKey())
 	}
 
 	if skippedDupes > 0 {
@@ -1988,7 +2912,7 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 	//     both sides with a different ETag, when the user has
 	//     explicitly opted into dest_wins conflict resolution.
 	//     Unchanged from pre-#72 behavior.
-	if syncDirection == db.SyncDirectionTwoWay && sourceClient != nil {
+	if !canceled && syncDirection == db.SyncDirectionTwoWay && sourceClient != nil {
 		// Case 1: reverse create pass, delegated to planReverseCreate
 		// so the ownership/empty-source/cap safety rules are all
 		// enforced in one testable place. The helper also filters out
@@ -2019,7 +2943,7 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 		// different UID, so we cannot store a source ETag against
 		// this UID. (#79)
 		for i := range contentDupes {
-			currentUIDs[contentDupes[i].UID] = syncETagEntry{
+			currentUIDs[contentDupes[i].MatchKey()] = syncETagEntry{
 				destETag: contentDupes[i].ETag,
 			}
 		}
@@ -2031,6 +2955,9 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 		skippedAlreadyExists := 0
 		skippedForbidden := 0
 		for i := range toUpload {
+			if canceled = syncCanceled(ctx, result); canceled {
+				break
+			}
 			destEvent := toUpload[i]
 			// Clear the Path so PutEvent generates a source-side
 			// path for the upload (source and dest namespaces are
@@ -2057,7 +2984,7 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 					// side exists but we do not know its current ETag
 					// on the source server. (#79)
 					skippedAlreadyExists++
-					currentUIDs[destEvent.UID] = syncETagEntry{
+					currentUIDs[destEvent.MatchKey()] = syncETagEntry{
 						destETag: destEvent.ETag,
 					}
 				case isForbiddenError(err):
@@ -2070,6 +2997,7 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 				}
 			} else {
 				result.Created++
+				verifyWrite(ctx, source, sourceClient, calendar.Path, &destEvent, result)
 				// Track the newly-uploaded event so the sync_events
 				// upsert at the end of this calendar's pass records
 				// it. Without this, the next cycle would see the
@@ -2078,7 +3006,7 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 				// from before the upload; the source ETag we just
 				// wrote is not returned by PutEvent and will be
 				// populated from a read on the next cycle. (#79)
-				currentUIDs[destEvent.UID] = syncETagEntry{
+				currentUIDs[destEvent.MatchKey()] = syncETagEntry{
 					destETag: destEvent.ETag,
 				}
 			}
@@ -2096,17 +3024,20 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 		// against the last-known dest ETag in previouslySyncedMap via
 		// shouldUpdateSourceFromDest — the symmetric twin of the
 		// forward helper.
-		if source.ConflictStrategy == db.ConflictDestWins {
+		if !canceled && source.ConflictStrategy == db.ConflictDestWins {
 			for _, destEvent := range destEvents {
+				if canceled = syncCanceled(ctx, result); canceled {
+					break
+				}
 				if destEvent.UID == "" {
 					continue
 				}
-				sourceEvent, exists := sourceEventMap[destEvent.UID]
+				sourceEvent, exists := sourceEventMap[destEvent.MatchKey()]
 				if !exists {
 					// Case 1 already handled this.
 					continue
 				}
-				if !shouldUpdateSourceFromDest(destEvent.ETag, previouslySyncedMap[destEvent.UID]) {
+				if !shouldUpdateSourceFromDest(destEvent.ETag, previouslySyncedMap[destEvent.MatchKey()]) {
 					continue
 				}
 				destEvent.Path = sourceEvent.Path
@@ -2123,6 +3054,7 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 					}
 				} else {
 					result.Updated++
+					verifyWrite(ctx, source, sourceClient, calendar.Path, &destEvent, result)
 					// Log conflict resolution for the UI (#136, refined in #169).
 					// Symmetric to the forward path: only log a real
 					// conflict when BOTH sides moved since our last
@@ -2132,7 +3064,7 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 					// that source also moved since its previously
 					// tracked ETag — otherwise this is a routine
 					// dest→source update, not a conflict.
-					if isRealConflictDestWins(previouslySyncedMap[destEvent.UID], sourceEvent.ETag) {
+					if isRealConflictDestWins(previouslySyncedMap[destEvent.MatchKey()], sourceEvent.ETag) {
 						result.Warnings = append(result.Warnings, fmt.Sprintf(
 							"CONFLICT:{\"uid\":%q,\"winner\":\"dest\",\"summary\":%q,\"strategy\":%q}",
 							destEvent.UID, destEvent.Summary, source.ConflictStrategy))
@@ -2142,7 +3074,7 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 					// dest-side change. We don't know the new source
 					// ETag PutEvent just created — next read cycle
 					// will populate it. (#79)
-					currentUIDs[destEvent.UID] = syncETagEntry{
+					currentUIDs[destEvent.MatchKey()] = syncETagEntry{
 						destETag: destEvent.ETag,
 					}
 				}
@@ -2164,7 +3096,7 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 	// destination event whenever the source returned 0 events (auth failure,
 	// broken URL, filter wipeout) or whenever multiple sources shared a
 	// destination (each source would delete the others' events on every cycle).
-	if syncDirection == db.SyncDirectionOneWay && source.ConflictStrategy == db.ConflictSourceWins {
+	if !canceled && !source.DestReadOnly && syncDirection == db.SyncDirectionOneWay && source.ConflictStrategy == db.ConflictSourceWins {
 		toDelete, warning := planOrphanDeletion(
 			destEventMap,
 			len(sourceEvents),
@@ -2175,7 +3107,22 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 			log.Printf("WARNING: %s", warning)
 			result.Warnings = append(result.Warnings, warning)
 		}
+		keys := make([]string, 0, len(toDelete))
+		for _, event := range toDelete {
+			keys = append(keys, event.MatchKey())
+		}
+		confirmed := make(map[string]bool)
+		for _, key := range se.applyDeleteConfirmations(source.ID, calendar.Path, destClient.baseURL, db.DeletionTargetDest, keys, result) {
+			confirmed[key] = true
+		}
 		for _, event := range toDelete {
+			if canceled = syncCanceled(ctx, result); canceled {
+				break
+			}
+			if !confirmed[event.MatchKey()] {
+				continue
+			}
+			se.backupDeletedEvent(ctx, source, destClient, calendar.Path, destCalendarPath, event, result)
 			if err := destClient.DeleteEvent(ctx, event.Path); err != nil {
 				result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to delete orphan event: %v", err))
 			} else {
@@ -2189,9 +3136,11 @@ func (se *SyncEngine) syncEventsToDestination(ctx context.Context, source *db.So
 	// directly into result (DuplicatesRemoved count + any Warnings for
 	// failed deletes) so delete failures are visible to callers instead
 	// of being log-only swallowed.
-	se.cleanupDuplicates(ctx, destClient, destCalendarPath, sourceEventMap, result)
-	if result.DuplicatesRemoved > 0 {
-		log.Printf("Removed %d duplicate events from destination", result.DuplicatesRemoved)
+	if !canceled && !source.DestReadOnly && !createOnly {
+		se.cleanupDuplicates(ctx, destClient, destCalendarPath, sourceEventMap, result)
+		if result.DuplicatesRemoved > 0 {
+			log.Printf("Removed %d duplicate events from destination", result.DuplicatesRemoved)
+		}
 	}
 
 	// Update synced_events table with current state. Each entry's
@@ -2293,7 +3242,7 @@ func (se *SyncEngine) cleanupDuplicates(ctx context.Context, destClient *Client,
 		// 2. Otherwise keep the first one (arbitrary but consistent)
 		keepIndex := 0
 		for i, event := range group.events {
-			if _, existsInSource := sourceEventMap[event.UID]; existsInSource {
+			if _, existsInSource := sourceEventMap[event.MatchKey()]; existsInSource {
 				keepIndex = i
 				break
 			}
@@ -2339,7 +3288,7 @@ func (se *SyncEngine) syncICSSource(ctx context.Context, source *db.Source) *Syn
 			result.Message = "Failed to decrypt source credentials"
 			result.Errors = append(result.Errors, err.Error())
 			result.Duration = time.Since(start)
-			se.finishSync(source.ID, result)
+			se.finishSync(ctx, source, result)
 			return result
 		}
 	}
@@ -2349,7 +3298,7 @@ func (se *SyncEngine) syncICSSource(ctx context.Context, source *db.Source) *Syn
 		result.Message = "Failed to decrypt destination credentials"
 		result.Errors = append(result.Errors, err.Error())
 		result.Duration = time.Since(start)
-		se.finishSync(source.ID, result)
+		se.finishSync(ctx, source, result)
 		return result
 	}
 
@@ -2359,26 +3308,28 @@ func (se *SyncEngine) syncICSSource(ctx context.Context, source *db.Source) *Syn
 		result.Message = "Failed to create ICS client"
 		result.Errors = append(result.Errors, err.Error())
 		result.Duration = time.Since(start)
-		se.finishSync(source.ID, result)
+		se.finishSync(ctx, source, result)
 		return result
 	}
 
 	// Create CalDAV client for destination
-	destClient, err := NewClient(source.DestURL, source.DestUsername, destPassword)
+	destClient, err := se.cachedClient(source.DestURL, source.DestUsername, destPassword, source)
 	if err != nil {
 		result.Message = "Failed to connect to destination"
 		result.Errors = append(result.Errors, err.Error())
 		result.Duration = time.Since(start)
-		se.finishSync(source.ID, result)
+		se.finishSync(ctx, source, result)
 		return result
 	}
+	destGuard := newDestGuard(source)
+	destClient = destGuard.client(destClient)
 
 	// Test connections
 	if err := icsClient.TestConnection(ctx); err != nil {
 		result.Message = "ICS feed connection test failed"
 		result.Errors = append(result.Errors, err.Error())
 		result.Duration = time.Since(start)
-		se.finishSync(source.ID, result)
+		se.finishSync(ctx, source, result)
 		return result
 	}
 
@@ -2386,7 +3337,7 @@ func (se *SyncEngine) syncICSSource(ctx context.Context, source *db.Source) *Syn
 		result.Message = "Destination connection test failed"
 		result.Errors = append(result.Errors, err.Error())
 		result.Duration = time.Since(start)
-		se.finishSync(source.ID, result)
+		se.finishSync(ctx, source, result)
 		return result
 	}
 
@@ -2396,14 +3347,18 @@ func (se *SyncEngine) syncICSSource(ctx context.Context, source *db.Source) *Syn
 		log.Printf("Failed to clear old malformed events: %v", err)
 	}
 
-	sourceEvents, err := icsClient.FetchEvents(ctx, malformedCollector)
+	sourceEvents, err := icsClient.FetchEvents(sourceFetchContext(ctx, source), malformedCollector)
 	if err != nil {
 		result.Message = "Failed to fetch ICS feed"
 		result.Errors = append(result.Errors, err.Error())
 		result.Duration = time.Since(start)
-		se.finishSync(source.ID, result)
+		se.finishSync(ctx, source, result)
 		return result
 	}
+	if msg := generatedUIDWarning(sourceEvents, "the ICS feed"); msg != "" {
+		log.Printf("WARNING: %s", msg)
+		result.Warnings = append(result.Warnings, msg)
+	}
 
 	// Capture content hash for adaptive polling (#146)
 	result.ContentHash = icsClient.LastFetchHash()
@@ -2413,6 +3368,7 @@ func (se *SyncEngine) syncICSSource(ctx context.Context, source *db.Source) *Syn
 		cutoffDate := time.Now().AddDate(0, 0, -source.SyncDaysPast)
 		sourceEvents = filterEventsByDate(sourceEvents, cutoffDate)
 	}
+	sourceEvents = filterEventsByCategory(sourceEvents, source.IncludeCategories)
 
 	// Store malformed events
 	for _, mf := range malformedCollector.GetEvents() {
@@ -2428,11 +3384,11 @@ func (se *SyncEngine) syncICSSource(ctx context.Context, source *db.Source) *Syn
 	}
 
 	// Start activity tracking (single calendar for ICS)
-	se.tracker.StartSync(source.ID, source.Name, 1)
-	se.tracker.UpdateCalendar(source.ID, calendar.Name, 1)
+	se.progress().StartSync(source.ID, source.Name, 1)
+	se.progress().UpdateCalendar(source.ID, calendar.Name, 1)
 
 	// Use shared sync logic — ICS is always one-way, sourceClient is nil (no write-back)
-	syncResult := se.syncEventsToDestination(ctx, source, nil, destClient, sourceEvents, calendar, 1, db.SyncDirectionOneWay)
+	syncResult := se.syncEventsToDestination(ctx, source, nil, destClient, sourceEvents, calendar, 1, icsSyncDirection(source))
 
 	result.Created = syncResult.Created
 	result.Updated = syncResult.Updated
@@ -2440,6 +3396,8 @@ func (se *SyncEngine) syncICSSource(ctx context.Context, source *db.Source) *Syn
 	result.Skipped = syncResult.Skipped
 	result.EventsProcessed = syncResult.EventsProcessed
 	result.DuplicatesRemoved = syncResult.DuplicatesRemoved
+	result.JournalsSkipped = syncResult.JournalsSkipped
+	addPutRejections(result, syncResult)
 	result.Errors = append(result.Errors, syncResult.Errors...)
 	result.Warnings = append(result.Warnings, syncResult.Warnings...)
 	result.CalendarsSynced = 1
@@ -2461,16 +3419,18 @@ func (se *SyncEngine) syncICSSource(ctx context.Context, source *db.Source) *Syn
 			result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to decrypt credentials for additional dest %q: %v", dest.Name, decErr))
 			continue
 		}
-		extraDestClient, connErr := NewClient(dest.DestURL, dest.DestUsername, extraDestPassword)
+		extraDestClient, connErr := se.cachedClient(dest.DestURL, dest.DestUsername, extraDestPassword, source)
 		if connErr != nil {
 			result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to connect to additional dest %q: %v", dest.Name, connErr))
 			continue
 		}
+		extraDestClient = destGuard.client(extraDestClient)
 		if testErr := extraDestClient.TestConnection(ctx); testErr != nil {
 			result.Warnings = append(result.Warnings, fmt.Sprintf("Connection test failed for additional dest %q: %v", dest.Name, testErr))
 			continue
 		}
-		extraResult := se.syncEventsToDestination(ctx, source, nil, extraDestClient, sourceEvents, calendar, 1, db.SyncDirectionOneWay)
+		extraResult := se.syncEventsToDestination(ctx, source, nil, extraDestClient, sourceEvents, calendar, 1, icsSyncDirection(source))
+		addPutRejections(result, extraResult)
 		result.Created += extraResult.Created
 		result.Updated += extraResult.Updated
 		result.Deleted += extraResult.Deleted
@@ -2483,19 +3443,27 @@ func (se *SyncEngine) syncICSSource(ctx context.Context, source *db.Source) *Syn
 		log.Printf("Completed ICS sync to additional destination: %s", dest.Name)
 	}
 
+	destGuard.report(result)
+	reportPutRejections(result)
+
+	markCanceled(ctx, result)
+
 	result.Success = len(result.Errors) == 0
 	if result.Success && len(result.Warnings) == 0 {
 		result.Message = fmt.Sprintf("ICS sync: %d created, %d updated, %d deleted, %d skipped",
-			result.Created, result.Updated, result.Deleted, result.Skipped)
+			result.Created, result.Updated, result.Deleted, result.Skipped) + journalNote(result.JournalsSkipped)
 	} else if result.Success && len(result.Warnings) > 0 {
 		result.Message = fmt.Sprintf("ICS sync with %d warnings: %d created, %d updated, %d deleted, %d skipped",
-			len(result.Warnings), result.Created, result.Updated, result.Deleted, result.Skipped)
+			len(result.Warnings), result.Created, result.Updated, result.Deleted, result.Skipped) + journalNote(result.JournalsSkipped)
+	} else if ctx.Err() != nil {
+		result.Message = fmt.Sprintf("ICS sync canceled: %d created, %d updated, %d deleted before stopping",
+			result.Created, result.Updated, result.Deleted)
 	} else {
 		result.Message = fmt.Sprintf("ICS sync failed with %d errors", len(result.Errors))
 	}
 
 	result.Duration = time.Since(start)
-	se.finishSync(source.ID, result)
+	se.finishSync(ctx, source, result)
 	return result
 }
 
@@ -2515,16 +3483,28 @@ func (se *SyncEngine) TestICSConnection(ctx context.Context, url, username, pass
 // having to parse the full warning text.
 const finishSyncPersistenceWarningPrefix = "sync persistence failure: "
 
-func (se *SyncEngine) finishSync(sourceID string, result *SyncResult) {
+func (se *SyncEngine) finishSync(ctx context.Context, source *db.Source, result *SyncResult) {
+	sourceID := source.ID
+	markTimedOut(ctx, result)
+
 	// In dry-run mode, don't write status or sync log to DB —
-	// the sync didn't actually happen. (#150)
-	if result.DryRun {
+	// the sync didn't actually happen. (#150) A calendar-scoped sync
+	// isn't a sync of the whole source, so it isn't recorded either.
+	// Both still started activity tracking, which has to end here.
+	if result.DryRun || result.Calendar != "" {
+		se.progress().FinishSync(sourceID, result.Success, result.Message, result.Errors)
 		return
 	}
 
-	// Determine status: error > partial > success
+	se.escalateWarnings(result)
+
+	// Determine status: auth_error > canceled > error > partial > success
 	var status db.SyncStatus
-	if !result.Success {
+	if result.AuthCircuitOpen {
+		status = db.SyncStatusAuthError
+	} else if result.Canceled {
+		status = db.SyncStatusCanceled
+	} else if !result.Success {
 		status = db.SyncStatusError
 	} else if len(result.Warnings) > 0 {
 		status = db.SyncStatusPartial
@@ -2538,15 +3518,26 @@ func (se *SyncEngine) finishSync(sourceID string, result *SyncResult) {
 	// gets recorded in the sync log details below, and surfaces on
 	// the dashboard instead of being silently swallowed as a log
 	// line nobody reads.
-	if err := retryDBOperation(func() error {
+	if err := se.retryDB(func() error {
 		return se.db.UpdateSourceSyncStatus(sourceID, status, result.Message)
-	}, 5); err != nil {
+	}); err != nil {
 		msg := fmt.Sprintf("%sfailed to update sync status after retries: %v",
 			finishSyncPersistenceWarningPrefix, err)
 		log.Printf("%s", msg)
 		result.Warnings = append(result.Warnings, msg)
 	}
 
+	// A failed sync leaves a short reason on the source for the
+	// dashboard; the status update above clears it on success.
+	if status == db.SyncStatusError || status == db.SyncStatusAuthError {
+		detail := lastErrorDetail(result)
+		if err := se.retryDB(func() error {
+			return se.db.UpdateSourceLastErrorDetail(sourceID, detail)
+		}); err != nil {
+			log.Printf("Failed to record last error detail for source %s: %v", sourceID, err)
+		}
+	}
+
 	// Create sync log with detailed stats
 	syncLog := &db.SyncLog{
 		SourceID:        sourceID,
@@ -2564,12 +3555,18 @@ func (se *SyncEngine) finishSync(sourceID string, result *SyncResult) {
 	// Include both errors and warnings in details (sanitized to remove sensitive info).
 	// If the UpdateSourceSyncStatus call above failed, its warning was
 	// just appended to result.Warnings and will be captured here.
+	// Warnings go one per line after a "Warnings:" header so the API
+	// can summarize them without the raw text reaching the browser.
 	var details []string
 	if len(result.Errors) > 0 {
 		details = append(details, fmt.Sprintf("Errors: %v", result.Errors))
 	}
 	if len(result.Warnings) > 0 {
-		details = append(details, fmt.Sprintf("Warnings: %v", result.Warnings))
+		details = append(details, SyncLogWarningsHeader)
+		details = append(details, result.Warnings...)
+		for _, w := range result.Warnings {
+			log.Printf("Sync warning for source %s: %s", source.Name, w)
+		}
 	}
 	if len(details) > 0 {
 		syncLog.Details = sanitizeLogDetails(strings.Join(details, "\n"))
@@ -2580,22 +3577,56 @@ func (se *SyncEngine) finishSync(sourceID string, result *SyncResult) {
 	// so we can only append to result.Warnings and log inline. Callers
 	// that inspect the returned SyncResult will still see the warning,
 	// even though it won't appear in the sync_logs table for this run.
-	if err := retryDBOperation(func() error {
+	if err := se.retryDB(func() error {
 		return se.db.CreateSyncLog(syncLog)
-	}, 5); err != nil {
+	}); err != nil {
 		msg := fmt.Sprintf("%sfailed to create sync log after retries: %v",
 			finishSyncPersistenceWarningPrefix, err)
 		log.Printf("%s", msg)
 		result.Warnings = append(result.Warnings, msg)
 	}
 
+	// Persist when this source is next due so a restarted process
+	// picks up where the schedule left off. Not surfaced as a warning:
+	// a stale next_sync_at only affects timing after a restart, and
+	// turning an otherwise clean sync "partial" over it would be noise.
+	if source.SyncInterval > 0 {
+		nextSyncAt := time.Now().Add(time.Duration(source.SyncInterval) * time.Second)
+		if err := se.retryDB(func() error {
+			return se.db.UpdateSourceNextSyncAt(sourceID, nextSyncAt)
+		}); err != nil {
+			log.Printf("Failed to persist next sync time for source %s: %v", sourceID, err)
+		}
+	}
+
 	// Finish activity tracking
-	se.tracker.FinishSync(sourceID, result.Success, result.Message, result.Errors)
+	se.progress().FinishSync(sourceID, result.Success, result.Message, result.Errors)
+
+	if se.notifier != nil && source.ResultWebhookURL != "" {
+		se.notifier.SendSyncResultWebhook(source.ResultWebhookURL, notify.SyncResultPayload{
+			SourceID:          sourceID,
+			SourceName:        source.Name,
+			Status:            string(status),
+			Success:           result.Success,
+			Message:           result.Message,
+			Created:           result.Created,
+			Updated:           result.Updated,
+			Deleted:           result.Deleted,
+			Skipped:           result.Skipped,
+			DuplicatesRemoved: result.DuplicatesRemoved,
+			CalendarsSynced:   result.CalendarsSynced,
+			EventsProcessed:   result.EventsProcessed,
+			DurationMs:        result.Duration.Milliseconds(),
+			Errors:            append([]string(nil), result.Errors...),
+			Warnings:          append([]string(nil), result.Warnings...),
+			Timestamp:         time.Now().UTC().Format(time.RFC3339),
+		})
+	}
 }
 
 // TestConnection tests connection to a CalDAV endpoint.
 func (se *SyncEngine) TestConnection(ctx context.Context, url, username, password string) error {
-	client, err := NewClient(url, username, password)
+	client, err := NewClient(url, username, password, WithUserAgent(se.userAgent))
 	if err != nil {
 		return err
 	}
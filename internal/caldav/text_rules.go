@@ -0,0 +1,160 @@
+package caldav
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/emersion/go-ical"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// Limits on a source's text rules. Go's regexp engine runs in time
+// linear in its input, so there is no catastrophic backtracking to
+// guard against; what is bounded is the size of a pattern's compiled
+// program and of the text it runs over, which together bound the work
+// per event, and how far replacements can grow the text.
+const (
+	// MaxTextRules bounds how many rules a source can have.
+	MaxTextRules = 20
+	// maxTextRulePattern bounds a rule's find text, in bytes.
+	maxTextRulePattern = 500
+	// maxTextRuleProgram bounds a regex's compiled program, in
+	// instructions; repetition counts like (a{1,100}){1,100} blow up
+	// here rather than at match time.
+	maxTextRuleProgram = 5000
+	// maxTextRuleInput is the longest summary or description rules are
+	// applied to, and the longest a replacement may make one.
+	maxTextRuleInput = 64 * 1024
+)
+
+// textRuleProps maps a db.TextRule field onto the property it rewrites.
+var textRuleProps = map[string]string{
+	db.TextRuleFieldSummary:     ical.PropSummary,
+	db.TextRuleFieldDescription: ical.PropDescription,
+}
+
+// textRule is a compiled db.TextRule.
+type textRule struct {
+	prop    string
+	literal string
+	re      *regexp.Regexp
+	replace string
+}
+
+func (r textRule) apply(text string) string {
+	if r.re != nil {
+		return r.re.ReplaceAllString(text, r.replace)
+	}
+	return strings.ReplaceAll(text, r.literal, r.replace)
+}
+
+// ValidateTextRules reports the first of rules that can't be used: an
+// unknown field, an empty or oversized find text, or a regex that
+// doesn't compile or is too complex.
+func ValidateTextRules(rules []db.TextRule) error {
+	_, err := compileTextRules(rules)
+	return err
+}
+
+func compileTextRules(rules []db.TextRule) ([]textRule, error) {
+	if len(rules) > MaxTextRules {
+		return nil, fmt.Errorf("too many text rules (max %d)", MaxTextRules)
+	}
+	compiled := make([]textRule, 0, len(rules))
+	for i, r := range rules {
+		prop, ok := textRuleProps[strings.ToLower(r.Field)]
+		if !ok {
+			return nil, fmt.Errorf("text rule %d: field must be %s or %s, got %q",
+				i+1, db.TextRuleFieldSummary, db.TextRuleFieldDescription, r.Field)
+		}
+		if r.Find == "" {
+			return nil, fmt.Errorf("text rule %d: find is empty", i+1)
+		}
+		if len(r.Find) > maxTextRulePattern || len(r.Replace) > maxTextRulePattern {
+			return nil, fmt.Errorf("text rule %d: find and replace are limited to %d bytes", i+1, maxTextRulePattern)
+		}
+		rule := textRule{prop: prop, literal: r.Find, replace: r.Replace}
+		if r.Regex {
+			re, err := compileBoundedRegexp(r.Find)
+			if err != nil {
+				return nil, fmt.Errorf("text rule %d: %w", i+1, err)
+			}
+			rule.re = re
+		}
+		compiled = append(compiled, rule)
+	}
+	return compiled, nil
+}
+
+// compileBoundedRegexp compiles pattern, refusing ones whose program
+// is larger than maxTextRuleProgram.
+func compileBoundedRegexp(pattern string) (*regexp.Regexp, error) {
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %w", err)
+	}
+	prog, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %w", err)
+	}
+	if len(prog.Inst) > maxTextRuleProgram {
+		return nil, errors.New("regex is too complex")
+	}
+	return regexp.Compile(pattern)
+}
+
+// textRulesTransform is a pipeline step that runs rules, in order,
+// over the SUMMARY and DESCRIPTION of every VEVENT and VJOURNAL.
+// Components without the property are left alone. A text past
+// maxTextRuleInput, or one a replacement would grow past it, fails the
+// transform so the event is written as the source has it.
+func textRulesTransform(rules []textRule) EventTransform {
+	return func(cal *ical.Calendar) error {
+		for _, comp := range cal.Children {
+			if comp.Name != ical.CompEvent && comp.Name != ical.CompJournal {
+				continue
+			}
+			for _, r := range rules {
+				prop := comp.Props.Get(r.prop)
+				if prop == nil {
+					continue
+				}
+				text, err := prop.Text()
+				if err != nil {
+					return err
+				}
+				if len(text) > maxTextRuleInput {
+					return fmt.Errorf("%s is longer than %d bytes; text rules not applied", r.prop, maxTextRuleInput)
+				}
+				rewritten := r.apply(text)
+				if len(rewritten) > maxTextRuleInput {
+					return fmt.Errorf("text rules would make %s longer than %d bytes; not applied", r.prop, maxTextRuleInput)
+				}
+				if rewritten != text {
+					prop.SetText(rewritten)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// eventSummary returns the SUMMARY the sync matches data's event by,
+// read the way the clients read it: the last VEVENT's (or VJOURNAL's).
+func eventSummary(data string) string {
+	cal, err := parseICalendar(data)
+	if err != nil {
+		return ""
+	}
+	entries, _ := calendarEntries(cal)
+	summary := ""
+	for _, entry := range entries {
+		if s, err := entry.Props.Text(ical.PropSummary); err == nil {
+			summary = s
+		}
+	}
+	return summary
+}
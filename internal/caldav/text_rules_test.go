@@ -0,0 +1,107 @@
+package caldav
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+const boilerplateEvent = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VEVENT\r\n" +
+	"UID:standup@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\n" +
+	"SUMMARY:Standup\r\n" +
+	"DESCRIPTION:Agenda: blockers\\n\\n-- Join Zoom Meeting https://zoom.us/j/123 Meeting ID: 123 --\r\n" +
+	"END:VEVENT\r\nEND:VCALENDAR\r\n"
+
+func rewriteText(t *testing.T, data string, rules ...db.TextRule) string {
+	t.Helper()
+	compiled, err := compileTextRules(rules)
+	if err != nil {
+		t.Fatalf("compileTextRules: %v", err)
+	}
+	out, err := applyTransforms(data, []EventTransform{textRulesTransform(compiled)})
+	if err != nil {
+		t.Fatalf("applyTransforms: %v", err)
+	}
+	return out
+}
+
+func TestTextRules_PrefixSummary(t *testing.T) {
+	out := rewriteText(t, boilerplateEvent,
+		db.TextRule{Field: db.TextRuleFieldSummary, Find: "^", Replace: "[Team] ", Regex: true})
+	if got := eventSummary(out); got != "[Team] Standup" {
+		t.Errorf("expected prefixed summary, got %q", got)
+	}
+	if !strings.Contains(out, "Agenda: blockers") {
+		t.Errorf("expected the description untouched, got:\n%s", out)
+	}
+
+	// Written again on the next sync, the prefix isn't doubled.
+	if again := rewriteText(t, out,
+		db.TextRule{Field: db.TextRuleFieldSummary, Find: `^(\[Team\] )?`, Replace: "[Team] ", Regex: true}); eventSummary(again) != "[Team] Standup" {
+		t.Errorf("expected an anchored optional prefix to be idempotent, got %q", eventSummary(again))
+	}
+}
+
+func TestTextRules_RegexStripsDescription(t *testing.T) {
+	out := rewriteText(t, boilerplateEvent,
+		db.TextRule{Field: db.TextRuleFieldDescription, Find: `\s*-- Join Zoom Meeting.*--`, Regex: true},
+		db.TextRule{Field: db.TextRuleFieldDescription, Find: "Agenda: ", Replace: "Topics: "})
+	if strings.Contains(out, "zoom.us") {
+		t.Errorf("expected the boilerplate stripped, got:\n%s", out)
+	}
+	if !strings.Contains(out, "DESCRIPTION:Topics: blockers\r\n") {
+		t.Errorf("expected the rules applied in order, got:\n%s", out)
+	}
+	if eventSummary(out) != "Standup" {
+		t.Errorf("expected the summary untouched, got %q", eventSummary(out))
+	}
+}
+
+func TestTextRules_NoMatchKeepsFormatting(t *testing.T) {
+	out := rewriteText(t, boilerplateEvent,
+		db.TextRule{Field: db.TextRuleFieldSummary, Find: "Retro", Replace: "Retrospective"})
+	if out != boilerplateEvent {
+		t.Errorf("expected the event returned byte for byte, got:\n%s", out)
+	}
+}
+
+func TestValidateTextRules(t *testing.T) {
+	tooMany := make([]db.TextRule, MaxTextRules+1)
+	for i := range tooMany {
+		tooMany[i] = db.TextRule{Field: db.TextRuleFieldSummary, Find: "x"}
+	}
+	tests := []struct {
+		name    string
+		rules   []db.TextRule
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"literal", []db.TextRule{{Field: "summary", Find: "Mtg", Replace: "Meeting"}}, false},
+		{"field is case-insensitive", []db.TextRule{{Field: "Description", Find: "x"}}, false},
+		{"unknown field", []db.TextRule{{Field: "location", Find: "x"}}, true},
+		{"empty find", []db.TextRule{{Field: "summary", Replace: "x"}}, true},
+		{"invalid regex", []db.TextRule{{Field: "summary", Find: "(", Regex: true}}, true},
+		{"regex too complex", []db.TextRule{{Field: "summary", Find: "(a{1,100}){1,100}", Regex: true}}, true},
+		{"find too long", []db.TextRule{{Field: "summary", Find: strings.Repeat("a", maxTextRulePattern+1)}}, true},
+		{"too many rules", tooMany, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateTextRules(tt.rules); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTextRules = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTextRules_OversizedTextLeftAlone(t *testing.T) {
+	data := strings.Replace(boilerplateEvent, "Agenda: blockers", strings.Repeat("a", maxTextRuleInput+1), 1)
+	compiled, err := compileTextRules([]db.TextRule{{Field: db.TextRuleFieldDescription, Find: "a", Replace: "b"}})
+	if err != nil {
+		t.Fatalf("compileTextRules: %v", err)
+	}
+	if _, err := applyTransforms(data, []EventTransform{textRulesTransform(compiled)}); err == nil {
+		t.Error("expected a description past the limit to fail the transform")
+	}
+}
@@ -0,0 +1,88 @@
+package caldav
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithMaxRequestsPerSecond(t *testing.T) {
+	var mu sync.Mutex
+	var hits []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits = append(hits, time.Now())
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	t.Run("unset leaves client unthrottled", func(t *testing.T) {
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass", WithMaxRequestsPerSecond(0))
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		if client.limiter != nil {
+			t.Error("expected no limiter for rps 0")
+		}
+	})
+
+	t.Run("requests stay under the cap", func(t *testing.T) {
+		const rps = 20.0
+		const requests = 6
+		mu.Lock()
+		hits = nil
+		mu.Unlock()
+
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass", WithMaxRequestsPerSecond(rps))
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		start := time.Now()
+		for i := 0; i < requests; i++ {
+			if err := client.DeleteEvent(context.Background(), "/cal/event.ics"); err != nil {
+				t.Fatalf("DeleteEvent: %v", err)
+			}
+		}
+		elapsed := time.Since(start)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(hits) != requests {
+			t.Fatalf("server saw %d requests, want %d", len(hits), requests)
+		}
+		// Burst of 1: the first request is free, each later one waits
+		// 1/rps. Allow a little scheduler slack below the ideal.
+		minElapsed := time.Duration(float64(requests-1)/rps*float64(time.Second)) - 20*time.Millisecond
+		if elapsed < minElapsed {
+			t.Errorf("%d requests took %v, want at least %v at %.0f req/s", requests, elapsed, minElapsed, rps)
+		}
+		for i := 1; i < len(hits); i++ {
+			if gap := hits[i].Sub(hits[i-1]); gap < time.Duration(float64(time.Second)/rps)-10*time.Millisecond {
+				t.Errorf("gap between request %d and %d was %v, under the 1/%.0fs spacing", i-1, i, gap, rps)
+			}
+		}
+	})
+
+	t.Run("wait honors context cancellation", func(t *testing.T) {
+		client, err := NewClient(srv.URL+"/cal/", "user", "pass", WithMaxRequestsPerSecond(0.1))
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		if err := client.DeleteEvent(context.Background(), "/cal/event.ics"); err != nil {
+			t.Fatalf("first DeleteEvent: %v", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		if err := client.DeleteEvent(ctx, "/cal/event.ics"); err == nil {
+			t.Error("expected an error when the context ends before a slot frees up")
+		}
+		if time.Since(start) > time.Second {
+			t.Error("throttled call should return promptly on context cancellation")
+		}
+	})
+}
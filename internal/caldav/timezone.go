@@ -0,0 +1,272 @@
+package caldav
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+const (
+	icalDateTimeLayout    = "20060102T150405"
+	icalUTCDateTimeLayout = "20060102T150405Z"
+)
+
+// LoadTargetTimezone resolves a source's TargetTimezone setting. Only
+// IANA names are accepted; "Local" is refused since it depends on the
+// server's own zone.
+func LoadTargetTimezone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("invalid timezone %q", name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// convertTimezone rewrites every VEVENT's DTSTART, DTEND and
+// RECURRENCE-ID into tzName (as local time with a TZID parameter) and
+// embeds a matching VTIMEZONE. UTC and zoned values keep their instant;
+// floating values are taken to already be in tzName. RRULE UNTIL is
+// rewritten to UTC, which RFC 5545 requires once DTSTART carries a
+// TZID. All-day (DATE) values are left alone, so an all-day event comes
+// back unchanged.
+//
+// Unlike sanitizeAlarms this round-trips through go-ical; it only does
+// so when something needs converting. An empty tzName is a no-op.
+func convertTimezone(data, tzName string) (string, error) {
+	if data == "" || tzName == "" {
+		return data, nil
+	}
+	loc, err := LoadTargetTimezone(tzName)
+	if err != nil {
+		return "", err
+	}
+	cal, err := parseICalendar(data)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrMalformedContent, err)
+	}
+	converted, err := convertCalendarTimezone(cal, loc)
+	if err != nil || !converted {
+		return data, err
+	}
+	return encodeCalendar(cal)
+}
+
+// timezoneTransform is convertTimezone as a pipeline step.
+func timezoneTransform(loc *time.Location) EventTransform {
+	return func(cal *ical.Calendar) error {
+		if _, err := convertCalendarTimezone(cal, loc); err != nil {
+			return fmt.Errorf("not converted to %s: %w", loc, err)
+		}
+		return nil
+	}
+}
+
+// convertCalendarTimezone does the work of convertTimezone on a parsed
+// calendar, reporting whether anything was converted.
+func convertCalendarTimezone(cal *ical.Calendar, loc *time.Location) (bool, error) {
+	var earliest time.Time
+	converted := false
+	for _, comp := range cal.Children {
+		if comp.Name != ical.CompEvent {
+			continue
+		}
+		var startLoc *time.Location
+		for _, name := range []string{ical.PropDateTimeStart, ical.PropDateTimeEnd, ical.PropRecurrenceID} {
+			prop := comp.Props.Get(name)
+			if prop == nil {
+				continue
+			}
+			t, from, ok, err := convertDateTimeProp(prop, loc)
+			if err != nil {
+				return false, fmt.Errorf("%s: %w", name, err)
+			}
+			if !ok {
+				continue
+			}
+			converted = true
+			if earliest.IsZero() || t.Before(earliest) {
+				earliest = t
+			}
+			if name == ical.PropDateTimeStart {
+				startLoc = from
+			}
+		}
+		if startLoc != nil {
+			for i := range comp.Props[ical.PropRecurrenceRule] {
+				rule := &comp.Props[ical.PropRecurrenceRule][i]
+				rule.Value = untilToUTC(rule.Value, startLoc)
+			}
+		}
+	}
+	if !converted {
+		return false, nil
+	}
+
+	tzid := loc.String()
+	children := make([]*ical.Component, 0, len(cal.Children)+1)
+	children = append(children, buildVTimezone(loc, earliest.In(loc).Year()))
+	for _, comp := range cal.Children {
+		if comp.Name == ical.CompTimezone && comp.Props.Get(ical.PropTimezoneID) != nil &&
+			comp.Props.Get(ical.PropTimezoneID).Value == tzid {
+			continue
+		}
+		children = append(children, comp)
+	}
+	cal.Children = children
+	return true, nil
+}
+
+// convertDateTimeProp moves a DATE-TIME property into loc. It returns
+// the instant, the location the value was originally expressed in, and
+// false for DATE values, which are left as they are.
+func convertDateTimeProp(prop *ical.Prop, loc *time.Location) (time.Time, *time.Location, bool, error) {
+	value := prop.Value
+	if strings.EqualFold(prop.Params.Get(ical.ParamValue), string(ical.ValueDate)) || !strings.Contains(value, "T") {
+		return time.Time{}, nil, false, nil
+	}
+
+	var t time.Time
+	var from *time.Location
+	var err error
+	switch tzid := prop.Params.Get(ical.ParamTimezoneID); {
+	case strings.HasSuffix(value, "Z"):
+		from = time.UTC
+		t, err = time.Parse(icalUTCDateTimeLayout, value)
+	case tzid != "":
+		from = resolveTZID(tzid)
+		if from == nil {
+			return time.Time{}, nil, false, fmt.Errorf("unknown TZID %q", tzid)
+		}
+		t, err = time.ParseInLocation(icalDateTimeLayout, value, from)
+	default:
+		from = loc
+		t, err = time.ParseInLocation(icalDateTimeLayout, value, loc)
+	}
+	if err != nil {
+		return time.Time{}, nil, false, fmt.Errorf("invalid date-time %q: %w", value, err)
+	}
+
+	prop.Value = t.In(loc).Format(icalDateTimeLayout)
+	prop.Params.Set(ical.ParamTimezoneID, loc.String())
+	return t, from, true, nil
+}
+
+// untilToUTC rewrites a local-time UNTIL in an RRULE value to UTC,
+// reading it in from (the zone DTSTART was written in). UTC and DATE
+// UNTIL values are returned unchanged.
+func untilToUTC(rule string, from *time.Location) string {
+	parts := strings.Split(rule, ";")
+	for i, part := range parts {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || !strings.EqualFold(key, "UNTIL") || strings.HasSuffix(value, "Z") || !strings.Contains(value, "T") {
+			continue
+		}
+		t, err := time.ParseInLocation(icalDateTimeLayout, value, from)
+		if err != nil {
+			continue
+		}
+		parts[i] = key + "=" + t.UTC().Format(icalUTCDateTimeLayout)
+	}
+	return strings.Join(parts, ";")
+}
+
+// buildVTimezone describes loc as a VTIMEZONE. The offset transitions
+// loc has in year become yearly-recurring DAYLIGHT/STANDARD
+// observances; a zone without transitions gets one fixed STANDARD
+// observance.
+func buildVTimezone(loc *time.Location, year int) *ical.Component {
+	tz := ical.NewComponent(ical.CompTimezone)
+	tz.Props.SetText(ical.PropTimezoneID, loc.String())
+
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(1, 0, 0)
+	for t := start; t.Before(end); {
+		next := t.Add(24 * time.Hour)
+		_, before := t.Zone()
+		if _, after := next.Zone(); after != before {
+			tz.Children = append(tz.Children, observance(findTransition(t, next)))
+		}
+		t = next
+	}
+
+	if len(tz.Children) == 0 {
+		name, offset := start.Zone()
+		std := ical.NewComponent(ical.CompTimezoneStandard)
+		setRawProp(std, ical.PropDateTimeStart, "19700101T000000")
+		setRawProp(std, ical.PropTimezoneOffsetFrom, formatUTCOffset(offset))
+		setRawProp(std, ical.PropTimezoneOffsetTo, formatUTCOffset(offset))
+		std.Props.SetText(ical.PropTimezoneName, name)
+		tz.Children = append(tz.Children, std)
+	}
+	return tz
+}
+
+// findTransition returns the first instant in (lo, hi] whose UTC offset
+// differs from lo's.
+func findTransition(lo, hi time.Time) time.Time {
+	_, offset := lo.Zone()
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		if _, o := mid.Zone(); o == offset {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi.Truncate(time.Second)
+}
+
+// observance builds the STANDARD or DAYLIGHT component for a
+// transition, recurring yearly on the same weekday of the month
+// (e.g. second Sunday of March, or last Sunday of October).
+func observance(transition time.Time) *ical.Component {
+	_, fromOffset := transition.Add(-time.Second).Zone()
+	name, toOffset := transition.Zone()
+
+	kind := ical.CompTimezoneStandard
+	if transition.IsDST() {
+		kind = ical.CompTimezoneDaylight
+	}
+	// DTSTART is the wall-clock time the change happens at, read in
+	// the offset in force before it.
+	local := transition.In(time.FixedZone("", fromOffset))
+	week := strconv.Itoa((local.Day()-1)/7 + 1)
+	if local.AddDate(0, 0, 7).Month() != local.Month() {
+		week = "-1"
+	}
+	weekday := strings.ToUpper(local.Weekday().String()[:2])
+
+	comp := ical.NewComponent(kind)
+	setRawProp(comp, ical.PropDateTimeStart, local.Format(icalDateTimeLayout))
+	setRawProp(comp, ical.PropTimezoneOffsetFrom, formatUTCOffset(fromOffset))
+	setRawProp(comp, ical.PropTimezoneOffsetTo, formatUTCOffset(toOffset))
+	comp.Props.SetText(ical.PropTimezoneName, name)
+	setRawProp(comp, ical.PropRecurrenceRule,
+		fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%s%s", int(local.Month()), week, weekday))
+	return comp
+}
+
+// setRawProp sets a property to an already-formatted value with its
+// default value type (Props.SetText would tag it VALUE=TEXT).
+func setRawProp(comp *ical.Component, name, value string) {
+	prop := ical.NewProp(name)
+	prop.Value = value
+	comp.Props.Set(prop)
+}
+
+// formatUTCOffset renders seconds east of UTC as an iCalendar
+// UTC-OFFSET ("-0500", "+0530").
+func formatUTCOffset(seconds int) string {
+	sign := '+'
+	if seconds < 0 {
+		sign = '-'
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
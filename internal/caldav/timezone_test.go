@@ -0,0 +1,190 @@
+package caldav
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+func tzEvent(lines ...string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VEVENT\r\n" +
+		"UID:tz-1\r\nDTSTAMP:20260101T000000Z\r\nSUMMARY:Standup\r\n" +
+		strings.Join(lines, "\r\n") + "\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+}
+
+func TestConvertTimezone_UTCToNewYork(t *testing.T) {
+	data := tzEvent("DTSTART:20260715T140000Z", "DTEND:20260715T150000Z")
+
+	got, err := convertTimezone(data, "America/New_York")
+	if err != nil {
+		t.Fatalf("convertTimezone: %v", err)
+	}
+
+	for _, want := range []string{
+		"DTSTART;TZID=America/New_York:20260715T100000\r\n",
+		"DTEND;TZID=America/New_York:20260715T110000\r\n",
+		"BEGIN:VTIMEZONE\r\n",
+		"TZID:America/New_York\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+
+	cal, err := parseICalendar(got)
+	if err != nil {
+		t.Fatalf("converted event does not parse: %v", err)
+	}
+	var tz, event *ical.Component
+	for _, child := range cal.Children {
+		switch child.Name {
+		case ical.CompTimezone:
+			tz = child
+		case ical.CompEvent:
+			event = child
+		}
+	}
+	if tz == nil || event == nil {
+		t.Fatalf("expected a VTIMEZONE and a VEVENT, got %d components", len(cal.Children))
+	}
+	if cal.Children[0] != tz {
+		t.Error("expected VTIMEZONE before the VEVENT")
+	}
+
+	start, err := event.Props.DateTime(ical.PropDateTimeStart, nil)
+	if err != nil {
+		t.Fatalf("DTSTART: %v", err)
+	}
+	if want := time.Date(2026, 7, 15, 14, 0, 0, 0, time.UTC); !start.Equal(want) {
+		t.Errorf("expected DTSTART to keep its instant %s, got %s", want, start.UTC())
+	}
+
+	observances := map[string]*ical.Component{}
+	for _, child := range tz.Children {
+		observances[child.Name] = child
+	}
+	for _, tt := range []struct {
+		kind, start, from, to, name, rrule string
+	}{
+		{ical.CompTimezoneDaylight, "20260308T020000", "-0500", "-0400", "EDT", "FREQ=YEARLY;BYMONTH=3;BYDAY=2SU"},
+		{ical.CompTimezoneStandard, "20261101T020000", "-0400", "-0500", "EST", "FREQ=YEARLY;BYMONTH=11;BYDAY=1SU"},
+	} {
+		obs := observances[tt.kind]
+		if obs == nil {
+			t.Errorf("missing %s observance", tt.kind)
+			continue
+		}
+		for prop, want := range map[string]string{
+			ical.PropDateTimeStart:      tt.start,
+			ical.PropTimezoneOffsetFrom: tt.from,
+			ical.PropTimezoneOffsetTo:   tt.to,
+			ical.PropTimezoneName:       tt.name,
+			ical.PropRecurrenceRule:     tt.rrule,
+		} {
+			if p := obs.Props.Get(prop); p == nil || p.Value != want {
+				t.Errorf("%s %s: expected %q, got %v", tt.kind, prop, want, p)
+			}
+		}
+	}
+}
+
+func TestConvertTimezone_AllDayUnchanged(t *testing.T) {
+	data := tzEvent("DTSTART;VALUE=DATE:20260715", "DTEND;VALUE=DATE:20260716")
+
+	got, err := convertTimezone(data, "America/New_York")
+	if err != nil {
+		t.Fatalf("convertTimezone: %v", err)
+	}
+	if got != data {
+		t.Errorf("expected all-day event untouched, got:\n%s", got)
+	}
+}
+
+func TestConvertTimezone_ZonedAndFloating(t *testing.T) {
+	t.Run("zoned value keeps its instant", func(t *testing.T) {
+		got, err := convertTimezone(tzEvent("DTSTART;TZID=Europe/Berlin:20260115T090000"), "America/New_York")
+		if err != nil {
+			t.Fatalf("convertTimezone: %v", err)
+		}
+		if !strings.Contains(got, "DTSTART;TZID=America/New_York:20260115T030000\r\n") {
+			t.Errorf("expected 09:00 Berlin to become 03:00 New York, got:\n%s", got)
+		}
+	})
+
+	t.Run("floating value is read as target time", func(t *testing.T) {
+		got, err := convertTimezone(tzEvent("DTSTART:20260115T090000"), "America/New_York")
+		if err != nil {
+			t.Fatalf("convertTimezone: %v", err)
+		}
+		if !strings.Contains(got, "DTSTART;TZID=America/New_York:20260115T090000\r\n") {
+			t.Errorf("expected floating 09:00 pinned to New York, got:\n%s", got)
+		}
+	})
+
+	t.Run("unknown TZID fails", func(t *testing.T) {
+		if _, err := convertTimezone(tzEvent("DTSTART;TZID=Mars Standard Time:20260115T090000"), "America/New_York"); err == nil {
+			t.Error("expected an error for an unresolvable TZID")
+		}
+	})
+}
+
+func TestConvertTimezone_RRuleUntil(t *testing.T) {
+	data := tzEvent(
+		"DTSTART;TZID=Europe/Berlin:20260105T090000",
+		"RRULE:FREQ=WEEKLY;UNTIL=20260330T090000;BYDAY=MO",
+	)
+
+	got, err := convertTimezone(data, "America/New_York")
+	if err != nil {
+		t.Fatalf("convertTimezone: %v", err)
+	}
+	// 09:00 Berlin on 30 March is CEST (+02:00).
+	if !strings.Contains(got, "RRULE:FREQ=WEEKLY;UNTIL=20260330T070000Z;BYDAY=MO\r\n") {
+		t.Errorf("expected UNTIL converted to UTC, got:\n%s", got)
+	}
+}
+
+func TestConvertTimezone_ReplacesExistingVTimezone(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+		"BEGIN:VTIMEZONE\r\nTZID:America/New_York\r\nBEGIN:STANDARD\r\nDTSTART:19700101T000000\r\n" +
+		"TZOFFSETFROM:-0500\r\nTZOFFSETTO:-0500\r\nEND:STANDARD\r\nEND:VTIMEZONE\r\n" +
+		"BEGIN:VEVENT\r\nUID:tz-2\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260715T140000Z\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	got, err := convertTimezone(data, "America/New_York")
+	if err != nil {
+		t.Fatalf("convertTimezone: %v", err)
+	}
+	if n := strings.Count(got, "BEGIN:VTIMEZONE"); n != 1 {
+		t.Errorf("expected exactly one VTIMEZONE, got %d:\n%s", n, got)
+	}
+	if !strings.Contains(got, "BEGIN:DAYLIGHT") {
+		t.Errorf("expected the generated VTIMEZONE to replace the stale one, got:\n%s", got)
+	}
+}
+
+func TestBuildVTimezone_FixedOffset(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	tz := buildVTimezone(loc, 2026)
+	if len(tz.Children) != 1 || tz.Children[0].Name != ical.CompTimezoneStandard {
+		t.Fatalf("expected a single STANDARD observance, got %d", len(tz.Children))
+	}
+	std := tz.Children[0]
+	if from, to := std.Props.Get(ical.PropTimezoneOffsetFrom).Value, std.Props.Get(ical.PropTimezoneOffsetTo).Value; from != "+0900" || to != "+0900" {
+		t.Errorf("expected +0900/+0900, got %s/%s", from, to)
+	}
+}
+
+func TestConvertTimezone_NoTarget(t *testing.T) {
+	data := tzEvent("DTSTART:20260715T140000Z")
+	if got, err := convertTimezone(data, ""); err != nil || got != data {
+		t.Errorf("expected no-op without a target, got %q, %v", got, err)
+	}
+	if _, err := convertTimezone(data, "Not/AZone"); err == nil {
+		t.Error("expected an error for an invalid target timezone")
+	}
+}
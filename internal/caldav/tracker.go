@@ -0,0 +1,55 @@
+package caldav
+
+import "github.com/macjediwizard/calbridgesync/internal/activity"
+
+// Tracker receives the progress of the engine's syncs.
+// *activity.Tracker implements it and is what NewSyncEngine installs;
+// NopTracker discards everything, for engines nobody watches.
+type Tracker interface {
+	StartSync(sourceID, sourceName string, totalCalendars int)
+	UpdateCalendar(sourceID, calendarName string, calendarIndex int)
+	UpdateProgress(sourceID string, created, updated, deleted, skipped, processed int)
+	FinishSync(sourceID string, success bool, message string, errors []string)
+}
+
+// NopTracker is a Tracker that records nothing.
+type NopTracker struct{}
+
+// StartSync implements Tracker.
+func (NopTracker) StartSync(string, string, int) {}
+
+// UpdateCalendar implements Tracker.
+func (NopTracker) UpdateCalendar(string, string, int) {}
+
+// UpdateProgress implements Tracker.
+func (NopTracker) UpdateProgress(string, int, int, int, int, int) {}
+
+// FinishSync implements Tracker.
+func (NopTracker) FinishSync(string, bool, string, []string) {}
+
+// SetTracker sets where the engine reports sync progress. A nil
+// tracker, typed or not, installs NopTracker. The activity API only
+// sees progress reported to an *activity.Tracker; see
+// GetActivityTracker. Call before the scheduler starts.
+func (se *SyncEngine) SetTracker(t Tracker) {
+	if at, ok := t.(*activity.Tracker); t == nil || (ok && at == nil) {
+		t = NopTracker{}
+	}
+	se.tracker = t
+}
+
+// progress returns the engine's tracker, NopTracker for an engine
+// built without NewSyncEngine.
+func (se *SyncEngine) progress() Tracker {
+	if se.tracker == nil {
+		return NopTracker{}
+	}
+	return se.tracker
+}
+
+// GetActivityTracker returns the activity tracker for external use, or
+// nil when the engine reports to some other Tracker.
+func (se *SyncEngine) GetActivityTracker() *activity.Tracker {
+	at, _ := se.tracker.(*activity.Tracker)
+	return at
+}
@@ -0,0 +1,96 @@
+package caldav
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/activity"
+)
+
+// recordingTracker records which Tracker calls a sync made.
+type recordingTracker struct {
+	NopTracker
+	mu       sync.Mutex
+	started  []string
+	finished []bool
+}
+
+func (r *recordingTracker) StartSync(sourceID, _ string, _ int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.started = append(r.started, sourceID)
+}
+
+func (r *recordingTracker) FinishSync(_ string, success bool, _ string, _ []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finished = append(r.finished, success)
+}
+
+func TestSyncSource_NopTracker(t *testing.T) {
+	f := newSelfLoopFixture(t)
+	f.engine.SetTracker(NopTracker{})
+	source := f.createSource(t, "Quiet", twoCalendarServer(t).URL+"/dav/", twoCalendarServer(t).URL+"/dav/")
+
+	result := f.engine.SyncSource(context.Background(), source)
+	if result.CalendarsSynced != 1 {
+		t.Errorf("expected the sync to run without a tracker, got %+v", result)
+	}
+	if f.engine.GetActivityTracker() != nil {
+		t.Error("expected no activity tracker behind a NopTracker")
+	}
+}
+
+func TestSyncSource_ZeroTracker(t *testing.T) {
+	f := newSelfLoopFixture(t)
+	// As an engine assembled without NewSyncEngine has it.
+	f.engine.tracker = nil
+	source := f.createSource(t, "Bare", twoCalendarServer(t).URL+"/dav/", twoCalendarServer(t).URL+"/dav/")
+
+	if result := f.engine.SyncSource(context.Background(), source); result.CalendarsSynced != 1 {
+		t.Errorf("expected the sync to run without a tracker, got %+v", result)
+	}
+}
+
+func TestSyncSource_ReportsToTracker(t *testing.T) {
+	f := newSelfLoopFixture(t)
+	rec := &recordingTracker{}
+	f.engine.SetTracker(rec)
+	source := f.createSource(t, "Watched", twoCalendarServer(t).URL+"/dav/", twoCalendarServer(t).URL+"/dav/")
+
+	f.engine.SyncSource(context.Background(), source)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.started) != 1 || rec.started[0] != source.ID {
+		t.Errorf("expected one StartSync for the source, got %v", rec.started)
+	}
+	if len(rec.finished) != 1 || rec.finished[0] {
+		t.Errorf("expected one failed FinishSync, got %v", rec.finished)
+	}
+}
+
+func TestSetTracker(t *testing.T) {
+	se := &SyncEngine{}
+	if _, ok := se.progress().(NopTracker); !ok {
+		t.Errorf("expected a zero engine to report to NopTracker, got %T", se.progress())
+	}
+
+	at := activity.NewTracker()
+	se.SetTracker(at)
+	if se.GetActivityTracker() != at {
+		t.Error("expected the activity tracker to be returned")
+	}
+
+	var typedNil *activity.Tracker
+	for _, tr := range []Tracker{nil, typedNil} {
+		se.SetTracker(tr)
+		if _, ok := se.progress().(NopTracker); !ok {
+			t.Errorf("SetTracker(%#v): expected NopTracker, got %T", tr, se.progress())
+		}
+		if se.GetActivityTracker() != nil {
+			t.Errorf("SetTracker(%#v): expected no activity tracker", tr)
+		}
+	}
+}
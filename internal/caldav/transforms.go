@@ -0,0 +1,99 @@
+package caldav
+
+import (
+	"fmt"
+
+	"github.com/emersion/go-ical"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// EventTransform rewrites one parsed calendar object in place before
+// it is written to the destination. Returning an error leaves the event
+// to be written untransformed.
+type EventTransform func(cal *ical.Calendar) error
+
+// buildTransformPipeline returns the ordered transforms source's
+// settings ask for. It is built once per calendar sync, so settings
+// that fail to resolve are reported once here rather than per event;
+// the pipeline is returned without them.
+//
+// The line-level rewrites (sanitizeAlarms, rewriteAttendees,
+// applyUIDNamespace) are not pipeline steps: they keep the source's
+// exact formatting, which a parse/encode round trip would not.
+func buildTransformPipeline(source *db.Source) ([]EventTransform, []string) {
+	var pipeline []EventTransform
+	var warnings []string
+
+	if len(source.TextRules) > 0 {
+		rules, err := compileTextRules(source.TextRules)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Text rules ignored: %v", err))
+		} else {
+			pipeline = append(pipeline, textRulesTransform(rules))
+		}
+	}
+
+	if stamp := RenderSourceStamp(source); stamp != "" {
+		pipeline = append(pipeline, sourceStampTransform(stamp))
+	}
+
+	if source.TargetTimezone != "" {
+		loc, err := LoadTargetTimezone(source.TargetTimezone)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Target timezone ignored: %v", err))
+		} else {
+			pipeline = append(pipeline, timezoneTransform(loc))
+		}
+	}
+
+	if !source.SyncAttachments {
+		pipeline = append(pipeline, stripLargeAttachments(maxInlineAttachmentBytes))
+	}
+
+	// Last, so it also trims the VTIMEZONE a conversion above added.
+	if source.MinimizeTimezones {
+		pipeline = append(pipeline, minimizeTimezones)
+	}
+
+	return pipeline, warnings
+}
+
+// applyTransforms runs pipeline over data in order. An empty pipeline
+// returns data untouched without parsing it; otherwise data is parsed
+// once, passed through every transform and encoded once. When the
+// transforms change nothing the original data is returned, so events
+// they don't apply to keep the source's exact formatting.
+func applyTransforms(data string, pipeline []EventTransform) (string, error) {
+	if data == "" || len(pipeline) == 0 {
+		return data, nil
+	}
+	cal, err := parseICalendar(data)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrMalformedContent, err)
+	}
+	before, err := encodeCalendar(cal)
+	if err != nil {
+		return "", err
+	}
+	for _, transform := range pipeline {
+		if err := transform(cal); err != nil {
+			return "", err
+		}
+	}
+	after, err := encodeCalendar(cal)
+	if err != nil || after == before {
+		return data, err
+	}
+	return after, nil
+}
+
+// transformEvent applies pipeline to data. On failure the event is
+// written as-is and a warning is recorded.
+func transformEvent(data string, pipeline []EventTransform, result *SyncResult) string {
+	transformed, err := applyTransforms(data, pipeline)
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Event %s: %v", icsUID(data), err))
+		return data
+	}
+	return transformed
+}
@@ -0,0 +1,104 @@
+package caldav
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/emersion/go-ical"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// summaryTransform appends suffix to every VEVENT's SUMMARY.
+func summaryTransform(suffix string) EventTransform {
+	return func(cal *ical.Calendar) error {
+		for _, comp := range cal.Children {
+			if comp.Name != ical.CompEvent {
+				continue
+			}
+			summary, _ := comp.Props.Text(ical.PropSummary)
+			comp.Props.SetText(ical.PropSummary, summary+suffix)
+		}
+		return nil
+	}
+}
+
+func TestApplyTransforms_RunsInOrder(t *testing.T) {
+	data := tzEvent("DTSTART:20260115T090000Z")
+	got, err := applyTransforms(data, []EventTransform{summaryTransform(" A"), summaryTransform(" B")})
+	if err != nil {
+		t.Fatalf("applyTransforms: %v", err)
+	}
+	if !strings.Contains(got, "SUMMARY:Standup A B") {
+		t.Errorf("expected both transforms applied in order, got:\n%s", got)
+	}
+}
+
+func TestApplyTransforms_EmptyPipelinePassthrough(t *testing.T) {
+	// Lowercase property names would not survive a parse/encode round
+	// trip, so an unchanged result proves nothing was re-encoded.
+	data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\nuid:abc\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	for _, pipeline := range [][]EventTransform{nil, {}} {
+		got, err := applyTransforms(data, pipeline)
+		if err != nil || got != data {
+			t.Errorf("expected passthrough, got %q, %v", got, err)
+		}
+	}
+}
+
+func TestApplyTransforms_UnchangedKeepsOriginal(t *testing.T) {
+	// LF line endings and a folded line, neither of which survives
+	// re-encoding.
+	data := "BEGIN:VCALENDAR\nVERSION:2.0\nPRODID:-//test//EN\nBEGIN:VEVENT\nUID:abc\n" +
+		"DTSTAMP:20260101T000000Z\nSUMMARY:Folded\n  summary\nEND:VEVENT\nEND:VCALENDAR\n"
+	noop := func(*ical.Calendar) error { return nil }
+	got, err := applyTransforms(data, []EventTransform{noop})
+	if err != nil || got != data {
+		t.Errorf("expected the original bytes back, got %q, %v", got, err)
+	}
+}
+
+func TestApplyTransforms_StopsOnError(t *testing.T) {
+	boom := errors.New("boom")
+	called := false
+	pipeline := []EventTransform{
+		func(*ical.Calendar) error { return boom },
+		func(*ical.Calendar) error { called = true; return nil },
+	}
+	if _, err := applyTransforms(tzEvent("DTSTART:20260115T090000Z"), pipeline); !errors.Is(err, boom) {
+		t.Errorf("expected boom, got %v", err)
+	}
+	if called {
+		t.Error("expected the pipeline to stop at the failing transform")
+	}
+
+	result := &SyncResult{}
+	data := tzEvent("DTSTART:20260115T090000Z")
+	if got := transformEvent(data, pipeline, result); got != data {
+		t.Error("expected a failed transform to leave the event as-is")
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "tz-1") {
+		t.Errorf("expected one warning naming the event, got %v", result.Warnings)
+	}
+}
+
+func TestBuildTransformPipeline(t *testing.T) {
+	pipeline, warnings := buildTransformPipeline(&db.Source{SyncAttachments: true})
+	if len(pipeline) != 0 || len(warnings) != 0 {
+		t.Errorf("expected an empty pipeline, got %d transforms, warnings %v", len(pipeline), warnings)
+	}
+
+	pipeline, _ = buildTransformPipeline(&db.Source{TargetTimezone: "America/New_York", SyncAttachments: true})
+	got, err := applyTransforms(tzEvent("DTSTART:20260115T140000Z"), pipeline)
+	if err != nil {
+		t.Fatalf("applyTransforms: %v", err)
+	}
+	if !strings.Contains(got, "DTSTART;TZID=America/New_York:20260115T090000") {
+		t.Errorf("expected timezone conversion, got:\n%s", got)
+	}
+
+	pipeline, warnings = buildTransformPipeline(&db.Source{TargetTimezone: "Not/AZone", SyncAttachments: true})
+	if len(pipeline) != 0 || len(warnings) != 1 {
+		t.Errorf("expected the bad timezone to be reported once and dropped, got %d transforms, warnings %v", len(pipeline), warnings)
+	}
+}
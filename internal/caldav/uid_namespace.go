@@ -0,0 +1,136 @@
+package caldav
+
+import "strings"
+
+// namespacedUID returns uid qualified with the source's UID prefix
+// ("prefix-uid"). Idempotent: a UID that already carries the prefix is
+// returned as-is, so events that round-trip through the destination
+// aren't double-prefixed. An empty prefix disables namespacing.
+func namespacedUID(prefix, uid string) string {
+	if prefix == "" || uid == "" || strings.HasPrefix(uid, prefix+"-") {
+		return uid
+	}
+	return prefix + "-" + uid
+}
+
+// applyUIDNamespace rewrites a source event into the source's UID
+// namespace before it is compared against or written to the
+// destination. Two sources feeding one destination calendar can carry
+// identical UIDs (auto-generated "1@example", copied invites) and
+// would otherwise overwrite each other's events.
+//
+// The rewrite covers Event.UID and every UID line in the calendar data
+// whose value matches, so recurrence overrides (same UID, different
+// RECURRENCE-ID) stay grouped with their master. Because the sync
+// engine works in the namespaced space from here on, destination
+// read-backs, synced_events rows and PutEvent's UID-derived paths all
+// agree without any further translation.
+func applyUIDNamespace(e Event, prefix string) Event {
+	if prefix == "" || e.UID == "" {
+		return e
+	}
+	newUID := namespacedUID(prefix, e.UID)
+	if newUID == e.UID {
+		return e
+	}
+	e.Data = rewriteUIDLines(e.Data, e.UID, newUID)
+	e.UID = newUID
+	return e
+}
+
+// rewriteUIDLines replaces the value of UID properties equal to oldUID
+// with newUID, unfolding continuation lines so long UIDs (Google and
+// Outlook emit 60+ character UIDs) still match. Other lines are left
+// byte-for-byte intact.
+func rewriteUIDLines(data, oldUID, newUID string) string {
+	if data == "" || !strings.Contains(data, "UID") {
+		return data
+	}
+
+	lineEnd := "\n"
+	if strings.Contains(data, "\r\n") {
+		lineEnd = "\r\n"
+	}
+	lines := strings.Split(data, lineEnd)
+
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if len(line) < 4 || !strings.EqualFold(line[:3], "UID") || (line[3] != ':' && line[3] != ';') {
+			out = append(out, line)
+			continue
+		}
+
+		j := i + 1
+		unfolded := line
+		for j < len(lines) && len(lines[j]) > 0 && (lines[j][0] == ' ' || lines[j][0] == '\t') {
+			unfolded += lines[j][1:]
+			j++
+		}
+
+		sep := valueSeparatorIndex(unfolded)
+		if sep < 0 || unfolded[sep+1:] != oldUID {
+			out = append(out, lines[i:j]...)
+		} else {
+			out = append(out, foldICalLine(unfolded[:sep+1]+newUID, lineEnd))
+		}
+		i = j - 1
+	}
+
+	return strings.Join(out, lineEnd)
+}
+
+// maxUIDPrefixLength bounds UIDPrefix so namespaced UIDs stay well
+// within server resource-name limits.
+const maxUIDPrefixLength = 32
+
+// ValidUIDPrefix reports whether prefix is safe to splice into UIDs
+// and the UID-derived resource names PutEvent builds: ASCII letters,
+// digits, '.', '_' and '-', at most maxUIDPrefixLength characters.
+// Empty means "no namespacing" and is always valid.
+func ValidUIDPrefix(prefix string) bool {
+	if len(prefix) > maxUIDPrefixLength {
+		return false
+	}
+	for _, r := range prefix {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '.' || r == '_' || r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// icsUID returns the first UID property value in data, or "" if none.
+// Used on the WebDAV-Sync path, where changed items arrive as raw data
+// without a parsed Event.UID.
+func icsUID(data string) string {
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if len(line) < 4 || !strings.EqualFold(line[:3], "UID") || (line[3] != ':' && line[3] != ';') {
+			continue
+		}
+		for j := i + 1; j < len(lines) && len(lines[j]) > 0 && (lines[j][0] == ' ' || lines[j][0] == '\t'); j++ {
+			line += lines[j][1:]
+		}
+		if sep := valueSeparatorIndex(line); sep >= 0 {
+			return line[sep+1:]
+		}
+		return ""
+	}
+	return ""
+}
+
+// namespacedEventPath rewrites the "{UID}.ics" filename of an event
+// path into the prefix's namespace so WebDAV-Sync deletes target the
+// object PutEvent actually wrote. Paths without a UID filename are
+// returned unchanged.
+func namespacedEventPath(eventPath, prefix string) string {
+	uid := extractUIDFromEventPath(eventPath)
+	if prefix == "" || uid == "" {
+		return eventPath
+	}
+	return strings.TrimSuffix(eventPath, uid+".ics") + namespacedUID(prefix, uid) + ".ics"
+}
@@ -0,0 +1,167 @@
+package caldav
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestNamespacedUID(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		uid    string
+		want   string
+	}{
+		{"empty prefix", "", "abc@example.com", "abc@example.com"},
+		{"empty uid", "work", "", ""},
+		{"prefixed", "work", "abc@example.com", "work-abc@example.com"},
+		{"idempotent", "work", "work-abc@example.com", "work-abc@example.com"},
+		{"other prefix not stripped", "home", "work-abc", "home-work-abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := namespacedUID(tt.prefix, tt.uid); got != tt.want {
+				t.Errorf("namespacedUID(%q, %q) = %q, want %q", tt.prefix, tt.uid, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyUIDNamespace(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\nUID:abc\r\nSUMMARY:master\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:abc\r\nRECURRENCE-ID:20260105T100000Z\r\nSUMMARY:override\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:unrelated\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	got := applyUIDNamespace(Event{UID: "abc", Data: data}, "work")
+
+	if got.UID != "work-abc" {
+		t.Errorf("UID = %q, want %q", got.UID, "work-abc")
+	}
+	if n := strings.Count(got.Data, "UID:work-abc\r\n"); n != 2 {
+		t.Errorf("expected master and override rewritten, got %d rewritten UID lines:\n%s", n, got.Data)
+	}
+	if !strings.Contains(got.Data, "UID:unrelated\r\n") {
+		t.Errorf("unrelated UID should be untouched:\n%s", got.Data)
+	}
+	if !strings.Contains(got.Data, "RECURRENCE-ID:20260105T100000Z\r\n") {
+		t.Errorf("RECURRENCE-ID should be untouched:\n%s", got.Data)
+	}
+
+	again := applyUIDNamespace(got, "work")
+	if again.Data != got.Data || again.UID != got.UID {
+		t.Error("applying the same prefix twice should be a no-op")
+	}
+
+	if unchanged := applyUIDNamespace(Event{UID: "abc", Data: data}, ""); unchanged.Data != data {
+		t.Error("empty prefix should leave data untouched")
+	}
+}
+
+func TestRewriteUIDLines_FoldedUID(t *testing.T) {
+	longUID := strings.Repeat("x", 70) + "@google.com"
+	data := "BEGIN:VEVENT\nUID:" + longUID[:74] + "\n " + longUID[74:] + "\nEND:VEVENT\n"
+
+	got := rewriteUIDLines(data, longUID, "work-"+longUID)
+
+	if icsUID(got) != "work-"+longUID {
+		t.Errorf("folded UID not rewritten, got UID %q from:\n%s", icsUID(got), got)
+	}
+	for _, line := range strings.Split(got, "\n") {
+		if len(line) > icalFoldWidth {
+			t.Errorf("line exceeds fold width (%d): %q", len(line), line)
+		}
+	}
+}
+
+func TestNamespacedEventPath(t *testing.T) {
+	tests := []struct {
+		path   string
+		prefix string
+		want   string
+	}{
+		{"/cal/abc.ics", "work", "/cal/work-abc.ics"},
+		{"/cal/abc.ics", "", "/cal/abc.ics"},
+		{"/cal/work-abc.ics", "work", "/cal/work-abc.ics"},
+		{"/cal/", "work", "/cal/"},
+		{"", "work", ""},
+	}
+	for _, tt := range tests {
+		if got := namespacedEventPath(tt.path, tt.prefix); got != tt.want {
+			t.Errorf("namespacedEventPath(%q, %q) = %q, want %q", tt.path, tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestValidUIDPrefix(t *testing.T) {
+	valid := []string{"", "work", "src-1", "a.b_c", strings.Repeat("a", maxUIDPrefixLength)}
+	for _, p := range valid {
+		if !ValidUIDPrefix(p) {
+			t.Errorf("ValidUIDPrefix(%q) = false, want true", p)
+		}
+	}
+	invalid := []string{"has space", "slash/", "new\nline", "ümlaut", "a?b", strings.Repeat("a", maxUIDPrefixLength+1)}
+	for _, p := range invalid {
+		if ValidUIDPrefix(p) {
+			t.Errorf("ValidUIDPrefix(%q) = true, want false", p)
+		}
+	}
+}
+
+// TestUIDNamespace_SourcesDoNotClobber writes the same original UID from
+// two sources with different prefixes into one destination calendar and
+// checks both objects survive under distinct paths and UIDs.
+func TestUIDNamespace_SourcesDoNotClobber(t *testing.T) {
+	var mu sync.Mutex
+	stored := make(map[string]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		stored[r.URL.Path] = string(body)
+		mu.Unlock()
+		w.Header().Set("ETag", `"1"`)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(srv.URL+"/cal", "user", "pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	const sharedUID = "1@example.com"
+	data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VEVENT\r\nUID:" + sharedUID +
+		"\r\nDTSTAMP:20260115T000000Z\r\nDTSTART:20260115T120000Z\r\nDTEND:20260115T130000Z\r\nSUMMARY:meeting\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	for _, prefix := range []string{"work", "home"} {
+		event := applyUIDNamespace(Event{UID: sharedUID, Path: "/source/" + sharedUID + ".ics", Data: data}, prefix)
+		if err := client.PutEvent(context.Background(), "/cal", &event); err != nil {
+			t.Fatalf("PutEvent(%s): %v", prefix, err)
+		}
+	}
+
+	if len(stored) != 2 {
+		t.Fatalf("expected 2 distinct destination objects, got %d: %v", len(stored), stored)
+	}
+	for _, prefix := range []string{"work", "home"} {
+		path := "/cal/" + prefix + "-" + sharedUID + ".ics"
+		body, ok := stored[path]
+		if !ok {
+			t.Errorf("missing object at %s", path)
+			continue
+		}
+		if got := icsUID(body); got != prefix+"-"+sharedUID {
+			t.Errorf("object at %s has UID %q, want %q", path, got, prefix+"-"+sharedUID)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+const (
+	// deletionBackupTTL is how long a deleted event stays restorable.
+	deletionBackupTTL = 7 * 24 * time.Hour
+
+	// maxDeletionBackupEvents and maxDeletionBackupBytes bound what one
+	// sync run may back up. Deletions past either limit still happen,
+	// they just can't be undone.
+	maxDeletionBackupEvents = 1000
+	maxDeletionBackupBytes  = 10 << 20
+)
+
+// ErrNothingToUndo is returned by UndoLastSync when a source has no
+// unexpired deletion backups.
+var ErrNothingToUndo = errors.New("no deleted events to restore")
+
+// deletionBatch collects the backups written by one sync run and
+// enforces the per-run size bound. Shared across the calendars and
+// destinations of the run through the context.
+type deletionBatch struct {
+	id string
+
+	mu     sync.Mutex
+	events int
+	bytes  int
+	capped bool
+}
+
+type deletionBatchContextKeyType struct{}
+
+var deletionBatchContextKey = deletionBatchContextKeyType{}
+
+// withDeletionBatch returns a context whose one-way deletions are
+// backed up under a new batch ID.
+func withDeletionBatch(ctx context.Context) context.Context {
+	return context.WithValue(ctx, deletionBatchContextKey, &deletionBatch{id: uuid.New().String()})
+}
+
+func deletionBatchFrom(ctx context.Context) *deletionBatch {
+	b, _ := ctx.Value(deletionBatchContextKey).(*deletionBatch)
+	return b
+}
+
+// reserve claims room for one more backup of size bytes. It reports
+// false once either bound is reached; first is true only on the call
+// that hit it, so the caller warns once per run.
+func (b *deletionBatch) reserve(size int) (ok, first bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.events >= maxDeletionBackupEvents || b.bytes+size > maxDeletionBackupBytes {
+		first = !b.capped
+		b.capped = true
+		return false, first
+	}
+	b.events++
+	b.bytes += size
+	return true, false
+}
+
+// SetDeletionBackups makes every source back up the destination events
+// its one-way syncs delete, so UndoLastSync can put them back. Sources
+// with VerifyWrites set are backed up either way. Wired from
+// BACKUP_ENABLED; call before the scheduler starts.
+func (se *SyncEngine) SetDeletionBackups(enabled bool) {
+	se.backupDeletions = enabled
+}
+
+// backsUpDeletions reports whether deletions made under ctx for source
+// are to be backed up.
+func (se *SyncEngine) backsUpDeletions(ctx context.Context, source *db.Source) bool {
+	return (se.backupDeletions || source.VerifyWrites) && se.db != nil &&
+		!IsDryRun(ctx) && deletionBatchFrom(ctx) != nil
+}
+
+// backupDeletedEvent stores event, which is about to be deleted from
+// destClient, in the current run's batch. A failed or skipped backup
+// is a warning; the deletion goes ahead regardless.
+func (se *SyncEngine) backupDeletedEvent(ctx context.Context, source *db.Source, destClient *Client, calendarHref, destCalendarPath string, event Event, result *SyncResult) {
+	if !se.backsUpDeletions(ctx, source) || event.Data == "" {
+		return
+	}
+	batch := deletionBatchFrom(ctx)
+	if ok, first := batch.reserve(len(event.Data)); !ok {
+		if first {
+			result.Warnings = append(result.Warnings, fmt.Sprintf(
+				"Deletion backup limit reached (%d events or %d MiB) - further deletions in this sync cannot be undone",
+				maxDeletionBackupEvents, maxDeletionBackupBytes>>20))
+		}
+		return
+	}
+	backup := &db.DeletedEventBackup{
+		SourceID:         source.ID,
+		BatchID:          batch.id,
+		CalendarHref:     calendarHref,
+		DestURL:          destClient.baseURL,
+		DestCalendarPath: destCalendarPath,
+		EventPath:        event.Path,
+		EventKey:         event.MatchKey(),
+		Data:             event.Data,
+		ExpiresAt:        time.Now().Add(deletionBackupTTL),
+	}
+	if err := se.db.CreateDeletedEventBackup(backup); err != nil {
+		msg := fmt.Sprintf("Failed to back up event %s before deleting it: %v", event.MatchKey(), err)
+		log.Printf("%s", msg)
+		result.Warnings = append(result.Warnings, msg)
+	}
+}
+
+// UndoResult reports what UndoLastSync put back.
+type UndoResult struct {
+	// DeletedAt is when the restored batch was written.
+	DeletedAt time.Time `json:"deleted_at"`
+	Restored  int       `json:"restored"`
+	Failed    int       `json:"failed"`
+	Errors    []string  `json:"errors,omitempty"`
+}
+
+// UndoLastSync re-creates the destination events deleted by the most
+// recent sync of source that deleted anything and had backups enabled.
+// Restored events stop being tracked as synced, so the next one-way
+// sync does not delete them again; they are left for the user to sort
+// out. Backups that restore are dropped, failed ones are kept so the
+// undo can be retried. Returns ErrNothingToUndo when there is no batch.
+func (se *SyncEngine) UndoLastSync(ctx context.Context, source *db.Source) (*UndoResult, error) {
+	backups, err := se.db.GetLatestDeletedEventBackups(source.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(backups) == 0 {
+		return nil, ErrNothingToUndo
+	}
+
+	result := &UndoResult{DeletedAt: backups[0].CreatedAt}
+	clients := make(map[string]*Client)
+	for _, b := range backups {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		client, ok := clients[b.DestURL]
+		if !ok {
+			client, err = se.undoDestClient(source, b.DestURL)
+			if err != nil {
+				log.Printf("Undo: no client for destination %s: %v", b.DestURL, err)
+			}
+			clients[b.DestURL] = client
+		}
+		if client == nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: destination %s is unavailable", b.EventKey, b.DestURL))
+			continue
+		}
+
+		event := &Event{Path: b.EventPath, UID: b.EventKey, Data: b.Data}
+		if err := client.PutEvent(ctx, b.DestCalendarPath, event); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", b.EventKey, err))
+			continue
+		}
+		result.Restored++
+		if err := se.db.DeleteSyncedEvent(source.ID, b.CalendarHref, b.EventKey); err != nil {
+			log.Printf("Undo: failed to untrack restored event %s: %v", b.EventKey, err)
+		}
+		if err := se.db.DeleteDeletedEventBackup(b.ID); err != nil {
+			log.Printf("Undo: failed to drop restored backup %s: %v", b.ID, err)
+		}
+	}
+	log.Printf("Undo for source %s: restored %d of %d deleted events", source.Name, result.Restored, len(backups))
+	return result, nil
+}
+
+// undoDestClient returns a client for destURL, which must be the
+// source's own destination or one of its additional destinations.
+func (se *SyncEngine) undoDestClient(source *db.Source, destURL string) (*Client, error) {
+	username, encPassword := source.DestUsername, source.DestPassword
+	if normalizeCollectionURL(destURL) != normalizeCollectionURL(source.DestURL) {
+		dests, err := se.db.GetDestinationsBySourceID(source.ID)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, dest := range dests {
+			if normalizeCollectionURL(destURL) == normalizeCollectionURL(dest.DestURL) {
+				username, encPassword, found = dest.DestUsername, dest.DestPassword, true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.New("destination is no longer configured")
+		}
+	}
+	password, err := se.encryptor.Decrypt(encPassword)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt destination credentials: %w", err)
+	}
+	client, err := se.cachedClient(destURL, username, password, source)
+	if err != nil {
+		return nil, err
+	}
+	return newDestGuard(source).client(client), nil
+}
@@ -0,0 +1,132 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func TestUndoLastSync(t *testing.T) {
+	srcStore, destStore := newCalendarStore(), newCalendarStore()
+	srcSrv := httptest.NewServer(srcStore)
+	defer srcSrv.Close()
+	destSrv := httptest.NewServer(destStore)
+	defer destSrv.Close()
+
+	f := newSelfLoopFixture(t)
+	source := f.createSource(t, "Undo", srcSrv.URL+"/cal/", destSrv.URL+"/dest/")
+	source.SyncDirection = db.SyncDirectionOneWay
+	f.engine.SetDeletionBackups(true)
+
+	sourceClient, err := NewClient(srcSrv.URL+"/cal/", "alice", "source-pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	destClient, err := NewClient(destSrv.URL+"/dest/", "alice", "dest-pass")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	events := make([]Event, 0, 3)
+	for _, uid := range []string{"a", "b", "c"} {
+		data := mergeICS("UID:" + uid + "@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:Event " + uid + "\r\n")
+		etag := srcStore.set("/cal/"+uid+".ics", data)
+		events = append(events, Event{Path: "/cal/" + uid + ".ics", ETag: etag, UID: uid + "@example.com", Data: data})
+	}
+	calendar := Calendar{Path: "/cal/", Name: "Cal"}
+	runSync := func(events []Event) *SyncResult {
+		ctx := withDeletionBatch(context.Background())
+		return f.engine.syncEventsToDestination(ctx, source, sourceClient, destClient, events, calendar, 1, db.SyncDirectionOneWay)
+	}
+
+	if result := runSync(events); result.Created != 3 {
+		t.Fatalf("expected 3 events created, got %d (warnings %v)", result.Created, result.Warnings)
+	}
+	if _, err := f.engine.UndoLastSync(context.Background(), source); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("expected ErrNothingToUndo before anything was deleted, got %v", err)
+	}
+
+	// The source drops "c": the one-way pass deletes it from the
+	// destination, backing it up first.
+	if result := runSync(events[:2]); result.Deleted != 1 {
+		t.Fatalf("expected 1 deletion, got %d (warnings %v)", result.Deleted, result.Warnings)
+	}
+	if _, ok := destStore.data["/dest/c@example.com.ics"]; ok {
+		t.Fatal("expected c to be deleted from the destination")
+	}
+	backups, err := f.database.GetLatestDeletedEventBackups(source.ID)
+	if err != nil {
+		t.Fatalf("GetLatestDeletedEventBackups: %v", err)
+	}
+	if len(backups) != 1 || backups[0].EventKey != "c@example.com" || !strings.Contains(backups[0].Data, "SUMMARY:Event c") {
+		t.Fatalf("expected c to be backed up, got %+v", backups)
+	}
+
+	result, err := f.engine.UndoLastSync(context.Background(), source)
+	if err != nil {
+		t.Fatalf("UndoLastSync: %v", err)
+	}
+	if result.Restored != 1 || result.Failed != 0 {
+		t.Fatalf("expected 1 restored, got %+v", result)
+	}
+	if data, ok := destStore.data["/dest/c@example.com.ics"]; !ok || !strings.Contains(data, "SUMMARY:Event c") {
+		t.Error("expected c to be back on the destination")
+	}
+
+	// The restored event is no longer tracked, so the next sync leaves
+	// it alone, and the batch is used up.
+	if result := runSync(events[:2]); result.Deleted != 0 {
+		t.Errorf("expected the restored event to survive the next sync, got %d deletions", result.Deleted)
+	}
+	if _, err := f.engine.UndoLastSync(context.Background(), source); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("expected nothing left to undo, got %v", err)
+	}
+}
+
+func TestDeletionBackupsDisabled(t *testing.T) {
+	f := newSelfLoopFixture(t)
+	source := f.createSource(t, "Off", "http://127.0.0.1:1/cal/", "http://127.0.0.1:1/dest/")
+	ctx := withDeletionBatch(context.Background())
+
+	if f.engine.backsUpDeletions(ctx, source) {
+		t.Error("expected no backups without SetDeletionBackups or VerifyWrites")
+	}
+	source.VerifyWrites = true
+	if !f.engine.backsUpDeletions(ctx, source) {
+		t.Error("expected VerifyWrites to enable backups")
+	}
+	if f.engine.backsUpDeletions(WithDryRun(ctx), source) {
+		t.Error("expected dry runs never to back up")
+	}
+	if f.engine.backsUpDeletions(context.Background(), source) {
+		t.Error("expected no backups outside a sync run")
+	}
+}
+
+func TestDeletionBatchReserve(t *testing.T) {
+	b := &deletionBatch{id: "batch"}
+	if ok, _ := b.reserve(maxDeletionBackupBytes); !ok {
+		t.Fatal("expected a backup up to the byte limit to fit")
+	}
+	ok, first := b.reserve(1)
+	if ok || !first {
+		t.Errorf("expected the first backup over the limit to be refused and reported, got %v, %v", ok, first)
+	}
+	if ok, first := b.reserve(1); ok || first {
+		t.Errorf("expected later backups to be refused quietly, got %v, %v", ok, first)
+	}
+
+	b = &deletionBatch{id: "batch"}
+	for i := 0; i < maxDeletionBackupEvents; i++ {
+		if ok, _ := b.reserve(1); !ok {
+			t.Fatalf("backup %d refused below the event limit", i)
+		}
+	}
+	if ok, _ := b.reserve(1); ok {
+		t.Error("expected the event limit to be enforced")
+	}
+}
@@ -0,0 +1,202 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// VanishedCalendarPolicy decides what a sync does when a calendar that
+// synced before is no longer among the calendars the source reports,
+// because it was deleted or unshared there.
+type VanishedCalendarPolicy string
+
+const (
+	// VanishedCalendarKeep leaves the events synced from the calendar
+	// on the destination. It is the default.
+	VanishedCalendarKeep VanishedCalendarPolicy = "keep"
+	// VanishedCalendarDelete deletes the events synced from the
+	// calendar from the destination, as if each had been deleted on
+	// the source.
+	VanishedCalendarDelete VanishedCalendarPolicy = "delete"
+	// VanishedCalendarAlert leaves the events in place and tells the
+	// user, once.
+	VanishedCalendarAlert VanishedCalendarPolicy = "alert"
+)
+
+// SetVanishedCalendarPolicy sets what happens to the destination copy
+// of a calendar gone from the source; see VanishedCalendarPolicy.
+// Wired from SYNC_VANISHED_CALENDAR; call before the scheduler starts.
+func (se *SyncEngine) SetVanishedCalendarPolicy(policy VanishedCalendarPolicy) {
+	se.vanishedCalendarPolicy = policy
+}
+
+// vanishedCalendars returns the hrefs in known, the calendars with a
+// sync state, that discovery no longer returned.
+func vanishedCalendars(known []string, discovered []Calendar) []string {
+	present := make(map[string]bool, len(discovered))
+	for _, cal := range discovered {
+		present[cal.Path] = true
+	}
+	var vanished []string
+	for _, href := range known {
+		if !present[href] {
+			vanished = append(vanished, href)
+		}
+	}
+	return vanished
+}
+
+// handleVanishedCalendars applies the vanished-calendar policy to the
+// calendars of source that have synced before but are missing from
+// discovered, every calendar the source reported (before selection:
+// a calendar the user deselected hasn't vanished).
+//
+// An empty discovery is ignored. A server that lists no calendars at
+// all is far more often having a bad moment than emptied out, and
+// the policy must not delete a whole destination over it.
+func (se *SyncEngine) handleVanishedCalendars(ctx context.Context, source *db.Source, destClient *Client, discovered []Calendar, result *SyncResult) {
+	if len(discovered) == 0 {
+		return
+	}
+	known, err := se.db.GetSyncStateCalendars(source.ID)
+	if err != nil {
+		log.Printf("Failed to load sync states for source %s: %v", source.Name, err)
+		return
+	}
+	for _, href := range vanishedCalendars(known, discovered) {
+		if syncCanceled(ctx, result) {
+			return
+		}
+		switch se.vanishedCalendarPolicy {
+		case VanishedCalendarDelete:
+			se.deleteVanishedCalendar(ctx, source, destClient, href, discovered, result)
+		case VanishedCalendarAlert:
+			msg := fmt.Sprintf("Calendar %s is no longer on the source; the events synced from it were left on the destination", href)
+			log.Printf("WARNING: source %s: %s", source.Name, msg)
+			result.Warnings = append(result.Warnings, msg)
+			if IsDryRun(ctx) {
+				continue
+			}
+			se.sendVanishedCalendarAlert(source, href)
+			se.forgetCalendar(source, href)
+		default:
+			log.Printf("Calendar %s is no longer on source %s; keeping its destination copy", href, source.Name)
+		}
+	}
+}
+
+// deleteVanishedCalendar deletes the destination copies of the events
+// synced from the vanished calendar href, then forgets the calendar.
+// Events another of the source's calendars tracks stay: a calendar
+// the server moved to a new path looks vanished at its old one, and
+// the sync that just ran tracks its events under the new path.
+//
+// Only the primary destination is cleaned up. If any deletion fails,
+// the calendar is kept on record so the next sync tries again.
+func (se *SyncEngine) deleteVanishedCalendar(ctx context.Context, source *db.Source, destClient *Client, href string, discovered []Calendar, result *SyncResult) {
+	if source.DestReadOnly {
+		log.Printf("Calendar %s is no longer on source %s; destination is read-only, keeping its copy", href, source.Name)
+		return
+	}
+	synced, err := se.db.GetSyncedEvents(source.ID, href)
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to load events synced from vanished calendar %s: %v", href, err))
+		return
+	}
+	trackedElsewhere := make(map[string]bool)
+	for _, cal := range discovered {
+		others, err := se.db.GetSyncedEvents(source.ID, cal.Path)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to load synced events for calendar %s: %v", cal.Path, err))
+			return
+		}
+		for _, o := range others {
+			trackedElsewhere[o.EventUID] = true
+		}
+	}
+
+	destCalendarPath := se.primaryDestCalendarPath(ctx, source, destClient)
+	destEvents, err := destClient.GetEvents(ctx, destCalendarPath, nil)
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to get destination events to remove vanished calendar %s: %v", href, err))
+		return
+	}
+	destEventMap := make(map[string]Event, len(destEvents))
+	for _, e := range destEvents {
+		if e.UID != "" {
+			destEventMap[e.MatchKey()] = e
+		}
+	}
+
+	log.Printf("Calendar %s is no longer on source %s; deleting the events synced from it", href, source.Name)
+	failed := 0
+	for _, s := range synced {
+		if syncCanceled(ctx, result) {
+			return
+		}
+		destEvent, onDest := destEventMap[s.EventUID]
+		if !onDest || trackedElsewhere[s.EventUID] {
+			continue
+		}
+		if IsDryRun(ctx) {
+			result.Deleted++
+			continue
+		}
+		se.backupDeletedEvent(ctx, source, destClient, href, destCalendarPath, destEvent, result)
+		if err := performDeletionAndCleanup(ctx, destClient, se.db, destEvent.Path, source.ID, href, s.EventUID); err != nil {
+			failed++
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to delete event %s of vanished calendar %s from dest: %v", s.EventUID, href, err))
+			continue
+		}
+		result.Deleted++
+	}
+	if failed == 0 && !IsDryRun(ctx) {
+		se.forgetCalendar(source, href)
+	}
+}
+
+// forgetCalendar drops the sync state and synced-event tracking of a
+// vanished calendar, so it is handled only once. Should the calendar
+// come back, its next sync is a first sync.
+func (se *SyncEngine) forgetCalendar(source *db.Source, href string) {
+	if err := se.db.DeleteSyncedEventsForCalendar(source.ID, href); err != nil {
+		log.Printf("Failed to delete synced events of vanished calendar %s: %v", href, err)
+	}
+	if err := se.db.DeleteSyncState(source.ID, href); err != nil {
+		log.Printf("Failed to delete sync state of vanished calendar %s: %v", href, err)
+	}
+}
+
+// primaryDestCalendarPath finds the destination calendar a source's
+// events go to the way syncEventsToDestination does, without creating
+// one when the destination has none.
+func (se *SyncEngine) primaryDestCalendarPath(ctx context.Context, source *db.Source, destClient *Client) string {
+	var destCalendars []Calendar
+	var err error
+	if IsGoogleURL(source.DestURL) {
+		destCalendars, err = destClient.FindCalendarsGoogle(ctx)
+	} else {
+		destCalendars, err = destClient.FindCalendars(ctx)
+	}
+	if err != nil || len(destCalendars) == 0 {
+		return destClient.GetCalendarPath()
+	}
+	return destCalendars[0].Path
+}
+
+// sendVanishedCalendarAlert tells a source's owner that one of its
+// calendars is gone from the source.
+func (se *SyncEngine) sendVanishedCalendarAlert(source *db.Source, href string) {
+	if se.notifier == nil || !se.notifier.IsEnabled() {
+		return
+	}
+	userEmail, prefs := se.alertRecipient(source)
+	se.notifier.SendSyncFailureAlertWithPrefs(context.Background(), source.ID, source.Name, userEmail,
+		fmt.Sprintf("A calendar of source '%s' is no longer on the source", source.Name),
+		fmt.Sprintf("Calendar %s was deleted or unshared on the source. "+
+			"The events synced from it are still on the destination; delete them there if they are no longer wanted.", href),
+		prefs)
+}
@@ -0,0 +1,160 @@
+package caldav
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func TestVanishedCalendars(t *testing.T) {
+	known := []string{"/cal/gone/", "/cal/home/", "/cal/work/"}
+	discovered := []Calendar{{Path: "/cal/work/"}, {Path: "/cal/new/"}, {Path: "/cal/home/"}}
+	if got := vanishedCalendars(known, discovered); !slices.Equal(got, []string{"/cal/gone/"}) {
+		t.Errorf("vanishedCalendars = %v, want [/cal/gone/]", got)
+	}
+	if got := vanishedCalendars(nil, discovered); len(got) != 0 {
+		t.Errorf("expected nothing vanished without sync states, got %v", got)
+	}
+}
+
+func TestHandleVanishedCalendars(t *testing.T) {
+	// setup returns a source whose /cal/gone/ and /cal/work/ calendars
+	// have both synced. b@example.com is tracked under both, the way a
+	// calendar the server moved looks.
+	setup := func(t *testing.T) (*db.DB, *db.Source, *calendarStore, *Client) {
+		t.Helper()
+		destStore := newCalendarStore()
+		destSrv := httptest.NewServer(destStore)
+		t.Cleanup(destSrv.Close)
+		for _, uid := range []string{"a", "b", "w"} {
+			destStore.set("/dest/"+uid+"@example.com.ics", statusICS(uid+"@example.com", ""))
+		}
+
+		database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+		if err != nil {
+			t.Fatalf("db.New: %v", err)
+		}
+		t.Cleanup(func() { database.Close() })
+		user, err := database.GetOrCreateUser("vanished@example.com", "Vanished")
+		if err != nil {
+			t.Fatalf("GetOrCreateUser: %v", err)
+		}
+		source := &db.Source{
+			UserID:           user.ID,
+			Name:             "Work",
+			SourceType:       db.SourceTypeCustom,
+			SourceURL:        "https://source.example.com/cal/",
+			SourceUsername:   "user",
+			SourcePassword:   "encrypted",
+			DestURL:          destSrv.URL + "/dest/",
+			DestUsername:     "dest",
+			DestPassword:     "encrypted",
+			SyncInterval:     3600,
+			SyncDirection:    db.SyncDirectionOneWay,
+			ConflictStrategy: db.ConflictSourceWins,
+			Enabled:          true,
+		}
+		if err := database.CreateSource(source); err != nil {
+			t.Fatalf("CreateSource: %v", err)
+		}
+		tracked := map[string][]string{
+			"/cal/gone/": {"a@example.com", "b@example.com"},
+			"/cal/work/": {"b@example.com", "w@example.com"},
+		}
+		for href, uids := range tracked {
+			if err := database.UpsertSyncState(&db.SyncState{SourceID: source.ID, CalendarHref: href, CTag: "1"}); err != nil {
+				t.Fatalf("UpsertSyncState: %v", err)
+			}
+			for _, uid := range uids {
+				if err := database.UpsertSyncedEvent(&db.SyncedEvent{
+					SourceID: source.ID, CalendarHref: href, EventUID: uid, SourceETag: `"1"`,
+				}); err != nil {
+					t.Fatalf("UpsertSyncedEvent: %v", err)
+				}
+			}
+		}
+		destClient, err := NewClient(destSrv.URL+"/dest/", "dest", "pass")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		return database, source, destStore, destClient
+	}
+
+	run := func(t *testing.T, policy VanishedCalendarPolicy, discovered []Calendar) (*SyncResult, []string, map[string]bool) {
+		t.Helper()
+		database, source, destStore, destClient := setup(t)
+		se := NewSyncEngine(database, nil)
+		se.SetVanishedCalendarPolicy(policy)
+		result := &SyncResult{}
+		se.handleVanishedCalendars(context.Background(), source, destClient, discovered, result)
+
+		states, err := database.GetSyncStateCalendars(source.ID)
+		if err != nil {
+			t.Fatalf("GetSyncStateCalendars: %v", err)
+		}
+		destStore.mu.Lock()
+		defer destStore.mu.Unlock()
+		onDest := make(map[string]bool)
+		for path := range destStore.data {
+			onDest[path] = true
+		}
+		return result, states, onDest
+	}
+
+	discovered := []Calendar{{Path: "/cal/work/", Name: "Work"}}
+	all := []string{"/dest/a@example.com.ics", "/dest/b@example.com.ics", "/dest/w@example.com.ics"}
+
+	t.Run("keep leaves everything", func(t *testing.T) {
+		result, states, onDest := run(t, VanishedCalendarKeep, discovered)
+		for _, path := range all {
+			if !onDest[path] {
+				t.Errorf("expected %s kept on the destination", path)
+			}
+		}
+		if len(states) != 2 || len(result.Warnings) != 0 || result.Deleted != 0 {
+			t.Errorf("expected no changes, got states %v, warnings %v, %d deleted", states, result.Warnings, result.Deleted)
+		}
+	})
+
+	t.Run("delete removes the calendar's events", func(t *testing.T) {
+		result, states, onDest := run(t, VanishedCalendarDelete, discovered)
+		if onDest["/dest/a@example.com.ics"] {
+			t.Error("expected the vanished calendar's event deleted from the destination")
+		}
+		if !onDest["/dest/b@example.com.ics"] || !onDest["/dest/w@example.com.ics"] {
+			t.Errorf("expected events tracked by the remaining calendar kept, destination holds %v", onDest)
+		}
+		if result.Deleted != 1 || len(result.Warnings) != 0 {
+			t.Errorf("expected 1 deleted and no warnings, got %d, %v", result.Deleted, result.Warnings)
+		}
+		if !slices.Equal(states, []string{"/cal/work/"}) {
+			t.Errorf("expected the vanished calendar forgotten, states %v", states)
+		}
+	})
+
+	t.Run("alert warns once and keeps the events", func(t *testing.T) {
+		result, states, onDest := run(t, VanishedCalendarAlert, discovered)
+		for _, path := range all {
+			if !onDest[path] {
+				t.Errorf("expected %s kept on the destination", path)
+			}
+		}
+		if len(result.Warnings) != 1 || result.Deleted != 0 {
+			t.Errorf("expected one warning and nothing deleted, got %v, %d deleted", result.Warnings, result.Deleted)
+		}
+		if !slices.Equal(states, []string{"/cal/work/"}) {
+			t.Errorf("expected the vanished calendar forgotten so it alerts once, states %v", states)
+		}
+	})
+
+	t.Run("empty discovery is ignored", func(t *testing.T) {
+		result, states, onDest := run(t, VanishedCalendarDelete, nil)
+		if len(onDest) != len(all) || len(states) != 2 || result.Deleted != 0 {
+			t.Errorf("expected no changes, got destination %v, states %v, %d deleted", onDest, states, result.Deleted)
+		}
+	})
+}
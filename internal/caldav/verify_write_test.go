@@ -0,0 +1,166 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// TestSyncEventsToDestination_VerifyWrites runs a one-way sync against
+// a destination that accepts every PUT, varying what a GET of the
+// written resource returns.
+func TestSyncEventsToDestination_VerifyWrites(t *testing.T) {
+	const eventICS = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VEVENT\r\n" +
+		"UID:%s\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\n" +
+		"SUMMARY:Board meeting\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	tests := []struct {
+		name         string
+		verify       bool
+		readBack     func(w http.ResponseWriter)
+		wantWarning  string
+		wantReadBack bool
+	}{
+		{
+			name:   "matching read-back",
+			verify: true,
+			readBack: func(w http.ResponseWriter) {
+				w.Header().Set("Content-Type", "text/calendar")
+				_, _ = w.Write([]byte(fmt.Sprintf(eventICS, "board@example.com")))
+			},
+			wantReadBack: true,
+		},
+		{
+			name:   "different UID on read-back",
+			verify: true,
+			readBack: func(w http.ResponseWriter) {
+				w.Header().Set("Content-Type", "text/calendar")
+				_, _ = w.Write([]byte(fmt.Sprintf(eventICS, "someone-else@example.com")))
+			},
+			wantWarning:  `returned UID "someone-else@example.com"`,
+			wantReadBack: true,
+		},
+		{
+			name:   "empty calendar on read-back",
+			verify: true,
+			readBack: func(w http.ResponseWriter) {
+				w.Header().Set("Content-Type", "text/calendar")
+				_, _ = w.Write([]byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nEND:VCALENDAR\r\n"))
+			},
+			wantWarning:  "Write verification failed",
+			wantReadBack: true,
+		},
+		{
+			name:   "dropped write",
+			verify: true,
+			readBack: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			wantWarning:  "read-back of /dest/board@example.com.ics failed",
+			wantReadBack: true,
+		},
+		{
+			name:   "disabled",
+			verify: false,
+			readBack: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			gets := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPut:
+					w.WriteHeader(http.StatusCreated)
+				case r.Method == http.MethodGet:
+					mu.Lock()
+					gets++
+					mu.Unlock()
+					tt.readBack(w)
+				case r.Method == "REPORT" || (r.Method == "PROPFIND" && r.Header.Get("Depth") == "1"):
+					w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+					w.WriteHeader(http.StatusMultiStatus)
+					_, _ = w.Write([]byte(`<?xml version="1.0"?><d:multistatus xmlns:d="DAV:"></d:multistatus>`))
+				default:
+					w.WriteHeader(http.StatusForbidden)
+				}
+			}))
+			defer srv.Close()
+
+			database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+			if err != nil {
+				t.Fatalf("db.New: %v", err)
+			}
+			defer database.Close()
+			user, err := database.GetOrCreateUser("verify@example.com", "Verify")
+			if err != nil {
+				t.Fatalf("GetOrCreateUser: %v", err)
+			}
+			source := &db.Source{
+				UserID:           user.ID,
+				Name:             "Verified",
+				SourceType:       db.SourceTypeCustom,
+				SourceURL:        "https://source.example.com/cal/",
+				SourceUsername:   "user",
+				SourcePassword:   "encrypted",
+				DestURL:          srv.URL + "/dest/",
+				DestUsername:     "dest",
+				DestPassword:     "encrypted",
+				SyncInterval:     3600,
+				SyncDirection:    db.SyncDirectionOneWay,
+				ConflictStrategy: db.ConflictSourceWins,
+				Enabled:          true,
+				VerifyWrites:     tt.verify,
+			}
+			if err := database.CreateSource(source); err != nil {
+				t.Fatalf("CreateSource: %v", err)
+			}
+			destClient, err := NewClient(srv.URL+"/dest/", "dest", "pass")
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+
+			sourceEvents := []Event{{
+				Path: "/cal/board.ics",
+				ETag: `"1"`,
+				UID:  "board@example.com",
+				Data: fmt.Sprintf(eventICS, "board@example.com"),
+			}}
+			se := NewSyncEngine(database, nil)
+			result := se.syncEventsToDestination(context.Background(), source, nil, destClient, sourceEvents, Calendar{Path: "/cal/", Name: "Cal"}, 1, db.SyncDirectionOneWay)
+
+			if result.Created != 1 {
+				t.Fatalf("expected the event to count as created, got %d (warnings %v)", result.Created, result.Warnings)
+			}
+			var verifyWarnings []string
+			for _, w := range result.Warnings {
+				if strings.HasPrefix(w, "Write verification failed") {
+					verifyWarnings = append(verifyWarnings, w)
+				}
+			}
+			if tt.wantWarning == "" {
+				if len(verifyWarnings) != 0 {
+					t.Errorf("expected no verification warning, got %v", verifyWarnings)
+				}
+			} else if len(verifyWarnings) != 1 || !strings.Contains(verifyWarnings[0], tt.wantWarning) {
+				t.Errorf("expected one verification warning containing %q, got %v", tt.wantWarning, result.Warnings)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if (gets > 0) != tt.wantReadBack {
+				t.Errorf("read-back GETs = %d, want read-back %v", gets, tt.wantReadBack)
+			}
+		})
+	}
+}
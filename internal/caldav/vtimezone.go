@@ -0,0 +1,149 @@
+package caldav
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+// minimizeTimezones is a pipeline step that trims the VTIMEZONE
+// components some servers bloat every event with:
+//
+//   - a TZID defined more than once keeps its first definition;
+//   - a VTIMEZONE no property refers to is dropped;
+//   - a VTIMEZONE for an IANA zone is replaced with the compact one
+//     buildVTimezone derives from tzdata, so every event in that zone
+//     carries the same short definition. Only when the zone's rules
+//     are the same from the event's earliest date through this year:
+//     otherwise one yearly rule can't describe it, and the source's
+//     definition is kept.
+//
+// Every TZID the calendar refers to keeps a definition, since CalDAV
+// servers require one (RFC 4791 §4.1); the times themselves are never
+// touched.
+func minimizeTimezones(cal *ical.Calendar) error {
+	earliest := referencedTZIDs(cal.Component)
+	thisYear := time.Now().Year()
+
+	defined := make(map[string]bool)
+	children := make([]*ical.Component, 0, len(cal.Children))
+	for _, comp := range cal.Children {
+		if comp.Name != ical.CompTimezone {
+			children = append(children, comp)
+			continue
+		}
+		prop := comp.Props.Get(ical.PropTimezoneID)
+		if prop == nil {
+			children = append(children, comp)
+			continue
+		}
+		tzid := prop.Value
+		year, used := earliest[tzid]
+		if !used || defined[tzid] {
+			continue
+		}
+		defined[tzid] = true
+		if canonical := canonicalVTimezone(tzid, year, thisYear); canonical != nil {
+			comp = canonical
+		}
+		children = append(children, comp)
+	}
+	cal.Children = children
+	return nil
+}
+
+// canonicalVTimezone returns buildVTimezone's definition of the IANA
+// zone tzid when its rules in year, the earliest one an event uses it
+// in, are still the rules in thisYear. It returns nil for names tzdata
+// doesn't know and for zones whose rules changed in between.
+func canonicalVTimezone(tzid string, year, thisYear int) *ical.Component {
+	loc, err := LoadTargetTimezone(tzid)
+	if err != nil {
+		return nil
+	}
+	if year <= 0 || year > thisYear {
+		year = thisYear
+	}
+	tz := buildVTimezone(loc, year)
+	if year != thisYear && !sameTimezoneRules(tz, buildVTimezone(loc, thisYear)) {
+		return nil
+	}
+	return tz
+}
+
+// sameTimezoneRules reports whether two buildVTimezone definitions of
+// a zone describe the same offsets changing at the same wall-clock
+// times on the same yearly rules, whatever year each starts in.
+func sameTimezoneRules(a, b *ical.Component) bool {
+	if len(a.Children) != len(b.Children) {
+		return false
+	}
+	for i := range a.Children {
+		x, y := a.Children[i], b.Children[i]
+		if x.Name != y.Name {
+			return false
+		}
+		for _, name := range []string{ical.PropTimezoneOffsetFrom, ical.PropTimezoneOffsetTo, ical.PropRecurrenceRule} {
+			if propValue(x, name) != propValue(y, name) {
+				return false
+			}
+		}
+		// DTSTART is YYYYMMDDTHHMMSS; the time of day must match, the
+		// date moves with the year.
+		if start, other := propValue(x, ical.PropDateTimeStart), propValue(y, ical.PropDateTimeStart); len(start) < 9 || len(other) < 9 || start[8:] != other[8:] {
+			return false
+		}
+	}
+	return true
+}
+
+func propValue(comp *ical.Component, name string) string {
+	if prop := comp.Props.Get(name); prop != nil {
+		return prop.Value
+	}
+	return ""
+}
+
+// referencedTZIDs maps every TZID a property outside the VTIMEZONEs
+// refers to onto the earliest year it is used in (0 when no value
+// could be read).
+func referencedTZIDs(comp *ical.Component) map[string]int {
+	refs := make(map[string]int)
+	var walk func(c *ical.Component)
+	walk = func(c *ical.Component) {
+		if c.Name == ical.CompTimezone {
+			return
+		}
+		for _, props := range c.Props {
+			for _, prop := range props {
+				tzid := prop.Params.Get(ical.ParamTimezoneID)
+				if tzid == "" {
+					continue
+				}
+				year, seen := refs[tzid]
+				// Multi-valued properties (EXDATE, RDATE) list their
+				// dates separated by commas.
+				for _, value := range strings.Split(prop.Value, ",") {
+					if len(value) < 4 {
+						continue
+					}
+					y, err := strconv.Atoi(value[:4])
+					if err != nil {
+						continue
+					}
+					if !seen || year == 0 || y < year {
+						year, seen = y, true
+					}
+				}
+				refs[tzid] = year
+			}
+		}
+		for _, child := range c.Children {
+			walk(child)
+		}
+	}
+	walk(comp)
+	return refs
+}
@@ -0,0 +1,137 @@
+package caldav
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+// bloatedNewYork is a VTIMEZONE the way some servers write it: every
+// historical rule change spelled out, plus extras nobody needs.
+const bloatedNewYork = "BEGIN:VTIMEZONE\r\nTZID:America/New_York\r\nX-LIC-LOCATION:America/New_York\r\n" +
+	"TZURL:http://tzurl.org/zoneinfo/America/New_York\r\n" +
+	"BEGIN:DAYLIGHT\r\nTZNAME:EDT\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0400\r\nDTSTART:19180331T020000\r\nRDATE:19180331T020000\r\nRDATE:19190330T020000\r\nRDATE:19200328T020000\r\nEND:DAYLIGHT\r\n" +
+	"BEGIN:STANDARD\r\nTZNAME:EST\r\nTZOFFSETFROM:-0400\r\nTZOFFSETTO:-0500\r\nDTSTART:19181027T020000\r\nRDATE:19181027T020000\r\nRDATE:19191026T020000\r\nRDATE:19201031T020000\r\nEND:STANDARD\r\n" +
+	"BEGIN:DAYLIGHT\r\nTZNAME:EDT\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0400\r\nDTSTART:19670430T020000\r\nRRULE:FREQ=YEARLY;UNTIL=19730429T070000Z;BYMONTH=4;BYDAY=-1SU\r\nEND:DAYLIGHT\r\n" +
+	"BEGIN:STANDARD\r\nTZNAME:EST\r\nTZOFFSETFROM:-0400\r\nTZOFFSETTO:-0500\r\nDTSTART:19671029T020000\r\nRRULE:FREQ=YEARLY;UNTIL=20061029T060000Z;BYMONTH=10;BYDAY=-1SU\r\nEND:STANDARD\r\n" +
+	"BEGIN:DAYLIGHT\r\nTZNAME:EDT\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0400\r\nDTSTART:19870405T020000\r\nRRULE:FREQ=YEARLY;UNTIL=20060402T070000Z;BYMONTH=4;BYDAY=1SU\r\nEND:DAYLIGHT\r\n" +
+	"BEGIN:DAYLIGHT\r\nTZNAME:EDT\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0400\r\nDTSTART:20070311T020000\r\nRRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU\r\nEND:DAYLIGHT\r\n" +
+	"BEGIN:STANDARD\r\nTZNAME:EST\r\nTZOFFSETFROM:-0400\r\nTZOFFSETTO:-0500\r\nDTSTART:20071104T020000\r\nRRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU\r\nEND:STANDARD\r\n" +
+	"END:VTIMEZONE\r\n"
+
+const unusedParis = "BEGIN:VTIMEZONE\r\nTZID:Europe/Paris\r\nBEGIN:STANDARD\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\n" +
+	"DTSTART:19961027T030000\r\nRRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU\r\nEND:STANDARD\r\nEND:VTIMEZONE\r\n"
+
+func zonedEvent(uid, start string, timezones ...string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" + strings.Join(timezones, "") +
+		"BEGIN:VEVENT\r\nUID:" + uid + "\r\nDTSTAMP:20260101T000000Z\r\n" +
+		"DTSTART;TZID=America/New_York:" + start + "\r\nDTEND;TZID=America/New_York:" + start[:9] + "110000\r\n" +
+		"SUMMARY:Standup\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+}
+
+func minimize(t *testing.T, data string) string {
+	t.Helper()
+	out, err := applyTransforms(data, []EventTransform{minimizeTimezones})
+	if err != nil {
+		t.Fatalf("applyTransforms: %v", err)
+	}
+	return out
+}
+
+func timezoneIDs(t *testing.T, data string) []string {
+	t.Helper()
+	cal, err := parseICalendar(data)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	var ids []string
+	for _, comp := range cal.Children {
+		if comp.Name == ical.CompTimezone {
+			ids = append(ids, comp.Props.Get(ical.PropTimezoneID).Value)
+		}
+	}
+	return ids
+}
+
+func TestMinimizeTimezones_SharedZoneShrinks(t *testing.T) {
+	before, after := 0, 0
+	for i := 0; i < 20; i++ {
+		data := zonedEvent(fmt.Sprintf("standup-%d@example.com", i), fmt.Sprintf("202603%02dT100000", i+1),
+			bloatedNewYork, bloatedNewYork, unusedParis)
+		out := minimize(t, data)
+		before += len(data)
+		after += len(out)
+
+		if ids := timezoneIDs(t, out); len(ids) != 1 || ids[0] != "America/New_York" {
+			t.Fatalf("expected one America/New_York definition, got %v", ids)
+		}
+		if !strings.Contains(out, fmt.Sprintf("DTSTART;TZID=America/New_York:202603%02dT100000", i+1)) {
+			t.Errorf("expected DTSTART untouched, got:\n%s", out)
+		}
+	}
+	if after*3 > before {
+		t.Errorf("expected the payload to shrink to under a third, got %d bytes from %d", after, before)
+	}
+	t.Logf("20 events: %d bytes before, %d after", before, after)
+}
+
+func TestMinimizeTimezones_SameInstant(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	for _, start := range []string{"20260115T100000", "20260715T100000"} {
+		out := minimize(t, zonedEvent("a@example.com", start, bloatedNewYork))
+		cal, err := parseICalendar(out)
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		var offsets []string
+		for _, comp := range cal.Children {
+			if comp.Name != ical.CompTimezone {
+				continue
+			}
+			for _, obs := range comp.Children {
+				offsets = append(offsets, obs.Name+" "+obs.Props.Get(ical.PropTimezoneOffsetTo).Value)
+			}
+		}
+		want, _ := time.ParseInLocation(icalDateTimeLayout, start, loc)
+		_, offset := want.Zone()
+		wantOffset := formatUTCOffset(offset)
+		found := false
+		for _, o := range offsets {
+			if strings.HasSuffix(o, wantOffset) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%s: no observance with offset %s in %v", start, wantOffset, offsets)
+		}
+	}
+}
+
+func TestMinimizeTimezones_KeepsWhatItCannotReplace(t *testing.T) {
+	t.Run("rules changed since the event", func(t *testing.T) {
+		// US DST rules changed in 2007, so one yearly rule can't
+		// describe a 2005 series; the source's definition stays.
+		out := minimize(t, zonedEvent("old@example.com", "20050615T100000", bloatedNewYork, bloatedNewYork))
+		if !strings.Contains(out, "RDATE:19190330T020000") {
+			t.Error("expected the source's definition to be kept")
+		}
+		if ids := timezoneIDs(t, out); len(ids) != 1 {
+			t.Errorf("expected the duplicate still dropped, got %v", ids)
+		}
+	})
+
+	t.Run("non-IANA TZID", func(t *testing.T) {
+		custom := strings.ReplaceAll(bloatedNewYork, "America/New_York", "Eastern Standard Time")
+		data := strings.ReplaceAll(zonedEvent("win@example.com", "20260315T100000", custom),
+			"TZID=America/New_York", "TZID=Eastern Standard Time")
+		if out := minimize(t, data); out != data {
+			t.Errorf("expected a lone non-IANA definition to be left alone, got:\n%s", out)
+		}
+	})
+}
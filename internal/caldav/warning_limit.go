@@ -0,0 +1,45 @@
+package caldav
+
+import (
+	"fmt"
+	"log"
+)
+
+// warningPercentFloor is how many warnings a sync needs before the
+// percentage limit applies, so one warning on a tiny calendar doesn't
+// fail it.
+const warningPercentFloor = 10
+
+// SetWarningLimits makes a successful sync with more than count
+// warnings, or warnings for more than percent of the events it
+// processed, count as failed so alerting fires. 0 turns either check
+// off. Wired from SYNC_WARNING_FAIL_COUNT / SYNC_WARNING_FAIL_PERCENT;
+// call before the scheduler starts.
+func (se *SyncEngine) SetWarningLimits(count, percent int) {
+	se.warningFailCount = count
+	se.warningFailPercent = percent
+}
+
+// warningsExceedLimit reports whether warnings warnings over processed
+// events is past the engine's limits.
+func (se *SyncEngine) warningsExceedLimit(warnings, processed int) bool {
+	if se.warningFailCount > 0 && warnings > se.warningFailCount {
+		return true
+	}
+	return se.warningFailPercent > 0 && processed > 0 && warnings >= warningPercentFloor &&
+		warnings*100 > se.warningFailPercent*processed
+}
+
+// escalateWarnings turns a successful sync whose warnings are past
+// the engine's limits into a failed one.
+func (se *SyncEngine) escalateWarnings(result *SyncResult) {
+	if !result.Success || !se.warningsExceedLimit(len(result.Warnings), result.EventsProcessed) {
+		return
+	}
+	msg := fmt.Sprintf("Too many warnings: %d for %d events processed", len(result.Warnings), result.EventsProcessed)
+	log.Printf("%s; marking the sync failed", msg)
+	result.Success = false
+	result.Errors = append(result.Errors, msg)
+	result.Message = fmt.Sprintf("Sync failed with too many warnings (%d for %d events processed)",
+		len(result.Warnings), result.EventsProcessed)
+}
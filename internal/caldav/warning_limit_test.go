@@ -0,0 +1,107 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func TestWarningsExceedLimit(t *testing.T) {
+	tests := []struct {
+		name                string
+		count, percent      int
+		warnings, processed int
+		want                bool
+	}{
+		{"limits off", 0, 0, 10000, 10, false},
+		{"at the count", 500, 0, 500, 100000, false},
+		{"over the count", 500, 0, 501, 100000, true},
+		{"at the percent", 0, 50, 50, 100, false},
+		{"over the percent", 0, 50, 51, 100, true},
+		{"under the floor", 0, 50, warningPercentFloor - 1, 1, false},
+		{"nothing processed", 0, 50, 20, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			se := &SyncEngine{}
+			se.SetWarningLimits(tt.count, tt.percent)
+			if got := se.warningsExceedLimit(tt.warnings, tt.processed); got != tt.want {
+				t.Errorf("warningsExceedLimit(%d, %d) = %v, want %v", tt.warnings, tt.processed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFinishSync_WarningLimit(t *testing.T) {
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	user, err := database.GetOrCreateUser("warnings@example.com", "Warnings")
+	if err != nil {
+		t.Fatalf("GetOrCreateUser: %v", err)
+	}
+	source := &db.Source{
+		UserID:           user.ID,
+		Name:             "Warnings",
+		SourceType:       db.SourceTypeCustom,
+		SourceURL:        "https://source.example.com/dav/",
+		DestURL:          "https://dest.example.com/dav/",
+		SyncInterval:     60,
+		SyncDirection:    db.SyncDirectionOneWay,
+		ConflictStrategy: db.ConflictSourceWins,
+		Enabled:          true,
+	}
+	if err := database.CreateSource(source); err != nil {
+		t.Fatalf("CreateSource: %v", err)
+	}
+	se := NewSyncEngine(database, nil)
+	se.SetWarningLimits(500, 50)
+
+	warnings := func(n int) []string {
+		w := make([]string, n)
+		for i := range w {
+			w[i] = fmt.Sprintf("Failed to create event on dest (UID=e%d): 500", i)
+		}
+		return w
+	}
+
+	tests := []struct {
+		name      string
+		warnings  int
+		want      db.SyncStatus
+		wantError bool
+	}{
+		{"just under", 50, db.SyncStatusPartial, false},
+		{"just over", 51, db.SyncStatusError, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &SyncResult{
+				Success:         true,
+				Message:         "Synced 1 calendar(s) with warnings",
+				EventsProcessed: 100,
+				Warnings:        warnings(tt.warnings),
+			}
+			se.finishSync(context.Background(), source, result)
+
+			if result.Success == tt.wantError {
+				t.Errorf("Success = %v, want %v", result.Success, !tt.wantError)
+			}
+			got, err := database.GetSourceByID(source.ID)
+			if err != nil {
+				t.Fatalf("GetSourceByID: %v", err)
+			}
+			if got.LastSyncStatus != tt.want {
+				t.Errorf("status = %q, want %q", got.LastSyncStatus, tt.want)
+			}
+			if tt.wantError && got.LastErrorDetail != "Too many warnings: 51 for 100 events processed" {
+				t.Errorf("LastErrorDetail = %q", got.LastErrorDetail)
+			}
+		})
+	}
+}
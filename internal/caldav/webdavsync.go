@@ -46,12 +46,101 @@ type prop struct {
 	CalendarData string `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
 }
 
+// CollectionState holds a calendar collection's change markers: the
+// CalendarServer getctag, which changes whenever anything in the
+// collection does, the RFC 6578 sync-token, and the collection's
+// DAV:getlastmodified, which some servers without a CTag keep up to
+// date the same way. Each is empty when the server doesn't report it.
+type CollectionState struct {
+	CTag         string
+	SyncToken    string
+	LastModified string
+}
+
+type collectionStateMultistatus struct {
+	XMLName   xml.Name `xml:"DAV: multistatus"`
+	Responses []struct {
+		PropStats []struct {
+			Prop struct {
+				CTag         string `xml:"http://calendarserver.org/ns/ getctag"`
+				SyncToken    string `xml:"DAV: sync-token"`
+				LastModified string `xml:"DAV: getlastmodified"`
+			} `xml:"prop"`
+			Status string `xml:"status"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+const collectionStateRequest = `<?xml version="1.0" encoding="utf-8" ?>
+<D:propfind xmlns:D="DAV:" xmlns:CS="http://calendarserver.org/ns/">
+  <D:prop>
+    <CS:getctag/>
+    <D:sync-token/>
+    <D:getlastmodified/>
+  </D:prop>
+</D:propfind>`
+
+// GetCollectionState reads a calendar's CTag, sync-token and
+// last-modified date with a single Depth: 0 PROPFIND.
+func (c *Client) GetCollectionState(ctx context.Context, calendarPath string) (*CollectionState, error) {
+	req, err := http.NewRequestWithContext(ctx, "PROPFIND", c.buildURL(calendarPath), strings.NewReader(collectionStateRequest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConnectionFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("%w: unexpected status %d", ErrInvalidResponse, resp.StatusCode)
+	}
+	body, err := c.readBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return parseCollectionState(body)
+}
+
+func parseCollectionState(body []byte) (*CollectionState, error) {
+	var ms collectionStateMultistatus
+	if err := xml.Unmarshal(body, &ms); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	// Unsupported properties come back in a separate 404 propstat.
+	state := &CollectionState{}
+	for _, resp := range ms.Responses {
+		for _, ps := range resp.PropStats {
+			if !strings.Contains(ps.Status, "200") {
+				continue
+			}
+			if ctag := strings.TrimSpace(ps.Prop.CTag); ctag != "" {
+				state.CTag = ctag
+			}
+			if token := strings.TrimSpace(ps.Prop.SyncToken); token != "" {
+				state.SyncToken = token
+			}
+			if modified := strings.TrimSpace(ps.Prop.LastModified); modified != "" {
+				state.LastModified = modified
+			}
+		}
+	}
+	return state, nil
+}
+
 // SyncCollection performs a WebDAV-Sync (RFC 6578) operation.
 func (c *Client) SyncCollection(ctx context.Context, calendarPath, syncToken string) (*SyncResponse, error) {
 	// Build the sync-collection REPORT request
 	reqBody := buildSyncCollectionRequest(syncToken)
 
-	req, err := http.NewRequestWithContext(ctx, "REPORT", c.baseURL+calendarPath, strings.NewReader(reqBody))
+	req, err := http.NewRequestWithContext(ctx, "REPORT", c.buildURL(calendarPath), strings.NewReader(reqBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -67,26 +156,26 @@ func (c *Client) SyncCollection(ctx context.Context, calendarPath, syncToken str
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusMultiStatus {
-		// WebDAV-Sync not supported or invalid token
+		// The error body is only used to spot an invalid token and
+		// for the wrapped error message; 4 KB is plenty for both.
+		body, readErr := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if readErr == nil && isInvalidSyncTokenError(body) {
+			return nil, fmt.Errorf("%w: status %d", ErrInvalidSyncToken, resp.StatusCode)
+		}
+		// WebDAV-Sync not supported
 		if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotImplemented {
 			return nil, fmt.Errorf("WebDAV-Sync not supported")
 		}
-		// Size-capped so an error response (which might be
-		// arbitrarily large under a misconfigured or malicious
-		// server) cannot force unbounded allocation. Error body
-		// is only used for the wrapped error message so a 200 MB
-		// cap is already absurdly generous for diagnostics. (#119)
-		body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxCalDAVResponseSize))
 		if readErr != nil {
 			return nil, fmt.Errorf("%w: unexpected status %d", ErrInvalidResponse, resp.StatusCode)
 		}
 		return nil, fmt.Errorf("%w: unexpected status %d: %s", ErrInvalidResponse, resp.StatusCode, string(body))
 	}
 
-	// Success path: cap the multistatus response the same way so
-	// a malicious server can't OOM the daemon via a runaway
-	// WebDAV-Sync response. (#119)
-	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCalDAVResponseSize))
+	// Success path: cap the multistatus response so a malicious
+	// server can't OOM the daemon via a runaway WebDAV-Sync
+	// response. (#119)
+	body, err := c.readBody(resp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
@@ -94,10 +183,28 @@ func (c *Client) SyncCollection(ctx context.Context, calendarPath, syncToken str
 	return parseSyncResponse(body)
 }
 
+// davError is the DAV:error body a server sends with a failed
+// precondition (RFC 4918 section 16).
+type davError struct {
+	XMLName        xml.Name  `xml:"DAV: error"`
+	ValidSyncToken *struct{} `xml:"DAV: valid-sync-token"`
+}
+
+// isInvalidSyncTokenError reports whether body is a DAV:error naming
+// the RFC 6578 DAV:valid-sync-token precondition, i.e. the server no
+// longer accepts the token. Servers pair it with 403, 409 or 507.
+func isInvalidSyncTokenError(body []byte) bool {
+	var e davError
+	if err := xml.Unmarshal(body, &e); err != nil {
+		return false
+	}
+	return e.ValidSyncToken != nil
+}
+
 // SupportsWebDAVSync checks if the calendar supports WebDAV-Sync.
 func (c *Client) SupportsWebDAVSync(ctx context.Context, calendarPath string) bool {
 	// Try an OPTIONS request to check for sync-collection support
-	req, err := http.NewRequestWithContext(ctx, http.MethodOptions, c.baseURL+calendarPath, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodOptions, c.buildURL(calendarPath), nil)
 	if err != nil {
 		return false
 	}
@@ -5,12 +5,14 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
 
 	"github.com/joho/godotenv"
 	"github.com/macjediwizard/calbridgesync/internal/validator"
+	"github.com/macjediwizard/calbridgesync/internal/version"
 )
 
 var (
@@ -33,6 +35,7 @@ const (
 type Config struct {
 	Server       ServerConfig
 	OIDC         OIDCConfig
+	Auth         AuthConfig
 	Security     SecurityConfig
 	Database     DatabaseConfig
 	CalDAV       CalDAVConfig
@@ -40,12 +43,22 @@ type Config struct {
 	Sync         SyncConfig
 	Alerts       AlertConfig
 	GoogleOAuth  GoogleOAuthConfig
-	// LogRetentionDays controls how many days of sync logs the
-	// scheduler's daily cleanup routine keeps. Configurable via
-	// SYNC_LOG_RETENTION_DAYS env var. Default 30.
-	LogRetentionDays int
 	// Backup settings for automated DB snapshots.
 	Backup BackupConfig
+	// Proxy is the egress proxy for CalDAV and webhook requests.
+	Proxy ProxyConfig
+}
+
+// ProxyConfig routes outbound CalDAV and webhook requests through an
+// HTTP(S) proxy.
+type ProxyConfig struct {
+	// URL is the proxy (OUTBOUND_PROXY_URL); empty connects directly.
+	// A source's own proxy URL takes precedence for its CalDAV
+	// clients.
+	URL string
+	// NoProxy lists the hosts reached directly, in NO_PROXY syntax
+	// (OUTBOUND_NO_PROXY, defaulting to NO_PROXY).
+	NoProxy string
 }
 
 // BackupConfig holds automated backup settings. (#148)
@@ -88,6 +101,9 @@ type AlertConfig struct {
 	// Webhook settings
 	WebhookEnabled bool
 	WebhookURL     string
+	// WebhookSecret keys the HMAC-SHA256 signature sent with every
+	// webhook; empty sends them unsigned.
+	WebhookSecret string
 
 	// Email settings
 	EmailEnabled bool
@@ -98,6 +114,8 @@ type AlertConfig struct {
 	SMTPFrom     string
 	SMTPTo       []string
 	SMTPTLS      bool
+	// EmailHTML adds an HTML part to alert emails (default: true).
+	EmailHTML bool
 
 	// Cooldown period in minutes (default: 60)
 	CooldownMinutes int
@@ -124,23 +142,144 @@ type OIDCConfig struct {
 	RedirectURL  string
 }
 
+// AuthConfig restricts which signed-in users may create sources. Both
+// lists empty means every user may. Users outside the lists keep full
+// control of the sources they already have.
+type AuthConfig struct {
+	// AllowedEmails are matched case-insensitively against the OIDC
+	// email; AllowedDomains against the part after its "@".
+	AllowedEmails  []string
+	AllowedDomains []string
+	// AdminEmails may perform instance-wide operations such as
+	// reloading the alert settings. Empty means nobody may.
+	AdminEmails []string
+	// AdminClaim names an OIDC ID-token claim that makes a user an
+	// admin when it is AdminClaimValue or, for a list claim such as
+	// "groups", contains it. Dots reach into nested claims. Empty
+	// turns claim-based admins off.
+	AdminClaim      string
+	AdminClaimValue string
+}
+
+// IsAdmin reports whether the user signed in as email is an instance
+// administrator by AUTH_ADMIN_EMAILS. Claim-based admins are decided
+// at login; see AdminClaim.
+func (a AuthConfig) IsAdmin(email string) bool {
+	email = strings.ToLower(strings.TrimSpace(email))
+	for _, admin := range a.AdminEmails {
+		if email == admin {
+			return true
+		}
+	}
+	return false
+}
+
+// CanCreateSources reports whether the user signed in as email may add
+// sources and probe CalDAV servers.
+func (a AuthConfig) CanCreateSources(email string) bool {
+	if len(a.AllowedEmails) == 0 && len(a.AllowedDomains) == 0 {
+		return true
+	}
+	email = strings.ToLower(strings.TrimSpace(email))
+	for _, allowed := range a.AllowedEmails {
+		if email == allowed {
+			return true
+		}
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := email[at+1:]
+	for _, allowed := range a.AllowedDomains {
+		if domain == allowed {
+			return true
+		}
+	}
+	return false
+}
+
 // SecurityConfig holds security-related configuration.
 type SecurityConfig struct {
 	EncryptionKey        []byte
 	SessionSecret        string
 	SessionMaxAgeSecs    int // Session timeout in seconds (default: 86400 = 24 hours)
 	OAuthStateMaxAgeSecs int // OAuth state timeout in seconds (default: 300 = 5 minutes)
+	// SessionRefreshWindowSecs: a session used within this long of
+	// expiring is re-issued with a fresh SessionMaxAgeSecs (default:
+	// 3600 = 1 hour; 0 = sessions hard-expire).
+	SessionRefreshWindowSecs int
+	// SessionAbsoluteMaxAgeSecs caps a session's life from sign-in,
+	// however active (default: 604800 = 7 days).
+	SessionAbsoluteMaxAgeSecs int
 }
 
 // DatabaseConfig holds database configuration.
 type DatabaseConfig struct {
 	Path string
+
+	// SQLite lock retry tuning for sync status / sync log writes.
+	// Wired from DB_MAX_LOCK_RETRIES and DB_LOCK_BACKOFF_BASE_MS.
+	// Zero values fall back to the sync engine defaults (5 attempts,
+	// 100ms base backoff).
+	MaxLockRetries    int
+	LockBackoffBaseMs int
 }
 
 // CalDAVConfig holds CalDAV-related configuration.
 type CalDAVConfig struct {
 	DefaultDestURL     string
 	RequestTimeoutSecs int // HTTP request timeout in seconds (default: 300 = 5 minutes)
+	// UserAgent is sent on every CalDAV request (default:
+	// "calbridgesync/<version>"). Some servers and proxies filter on it.
+	UserAgent string
+	// MaxEventsPerCalendar aborts a calendar's sync when it lists more
+	// events than this. MaxEventBytes skips (as malformed) any single
+	// event whose iCalendar body is larger. 0 disables either guard.
+	MaxEventsPerCalendar int
+	MaxEventBytes        int
+	// MaxResponseBytes caps any single CalDAV response body; a larger
+	// one fails the read instead of being buffered.
+	MaxResponseBytes int64
+	// DisableHTTP2 keeps CalDAV clients on HTTP/1.1 for servers or
+	// proxies that misbehave on h2. Set CALDAV_DISABLE_HTTP2=true.
+	DisableHTTP2 bool
+	// AllowedDestHosts restricts the hosts sources may sync to
+	// (CALDAV_ALLOWED_DEST_HOSTS). Empty allows any; see
+	// DestHostAllowed.
+	AllowedDestHosts []string
+	// DiscoverCacheSecs is how long calendar discovery results are
+	// reused for the same user, URL and credentials. 0 disables the
+	// cache.
+	DiscoverCacheSecs int
+}
+
+// DestHostAllowed reports whether destURL's host is on the
+// AllowedDestHosts list. Hosts compare case-insensitively without the
+// port; an entry "*.example.com" matches any subdomain of example.com.
+// An empty list allows every host, and a URL without a host none.
+func (c CalDAVConfig) DestHostAllowed(destURL string) bool {
+	if len(c.AllowedDestHosts) == 0 {
+		return true
+	}
+	parsed, err := url.Parse(destURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+	if host == "" {
+		return false
+	}
+	for _, allowed := range c.AllowedDestHosts {
+		if suffix, ok := strings.CutPrefix(allowed, "*"); ok {
+			if strings.HasSuffix(host, suffix) {
+				return true
+			}
+		} else if host == allowed {
+			return true
+		}
+	}
+	return false
 }
 
 // RateLimitConfig holds rate limiting configuration.
@@ -153,6 +292,84 @@ type RateLimitConfig struct {
 type SyncConfig struct {
 	MinInterval int
 	MaxInterval int
+
+	// DeleteConfirmations is how many consecutive cycles an event must
+	// be missing on one side before the deletion propagates to the
+	// other. 1 (the default) deletes on first sight.
+	DeleteConfirmations int
+
+	// AuthFailureThreshold is how many consecutive syncs of a source
+	// must be rejected with an auth failure before its syncs are paused
+	// (see caldav.SyncEngine.SetAuthFailureThreshold). 0 disables it.
+	AuthFailureThreshold int
+
+	// ConnectAttempts is how many times a sync tries its source and
+	// destination connection tests before giving up on a transient
+	// failure, waiting ConnectBackoffMs (doubling each retry) between
+	// tries. Configurable via SYNC_CONNECT_ATTEMPTS (default 3, 1
+	// disables retrying) and SYNC_CONNECT_BACKOFF_MS (default 500).
+	ConnectAttempts  int
+	ConnectBackoffMs int
+
+	// MaxConcurrent caps how many syncs run at once across all
+	// sources. Configurable via SYNC_MAX_CONCURRENT. Default 4; 0
+	// removes the cap.
+	MaxConcurrent int
+
+	// MaxDurationSecs is how long one sync may run before the
+	// scheduler cancels it as timed out, freeing its slot; sources can
+	// set their own. Configurable via SYNC_MAX_DURATION_SECS. Default
+	// 7200 (2 hours), minimum 60.
+	MaxDurationSecs int
+
+	// LogRetentionDays controls how many days of sync logs and
+	// malformed-event records the scheduler's daily cleanup keeps.
+	// Configurable via SYNC_LOG_RETENTION_DAYS. Default 30; 0 keeps
+	// them forever.
+	LogRetentionDays int
+
+	// HealthLogMinutes is how often the scheduler logs its health
+	// snapshot. Configurable via SYNC_HEALTH_LOG_MINUTES. Default 5.
+	HealthLogMinutes int
+
+	// RecurrencePolicy is how events with an invalid RRULE are
+	// written: "repair" (default) fixes what it can, "strip" syncs
+	// them as single instances. Configurable via SYNC_INVALID_RRULE.
+	RecurrencePolicy string
+
+	// EncodeFailurePolicy is what happens to events the iCalendar
+	// encoder refuses (e.g. both DTEND and DURATION): "skip" (default)
+	// records them as malformed, "raw" writes the original data.
+	// Configurable via SYNC_ENCODE_FAILURE.
+	EncodeFailurePolicy string
+
+	// CreateDestCalendar makes a sync create a calendar on a
+	// destination that has none (MKCALENDAR, named and colored like
+	// the source calendar) instead of writing to the destination URL
+	// itself. Configurable via SYNC_CREATE_DEST_CALENDAR.
+	CreateDestCalendar bool
+
+	// WarningFailCount and WarningFailPercent turn a sync with too
+	// many warnings from partial into a failure, so alerts fire: more
+	// than WarningFailCount warnings, or warnings for more than
+	// WarningFailPercent of the events processed. Configurable via
+	// SYNC_WARNING_FAIL_COUNT (default 500) and
+	// SYNC_WARNING_FAIL_PERCENT (default 50); 0 turns either off.
+	WarningFailCount   int
+	WarningFailPercent int
+
+	// VanishedCalendarPolicy is what a sync does when a calendar that
+	// synced before is gone from the source: "keep" (default) leaves
+	// the destination copy, "delete" deletes the events synced from
+	// it, "alert" notifies the user. Configurable via
+	// SYNC_VANISHED_CALENDAR.
+	VanishedCalendarPolicy string
+
+	// DeadLetterAfter is how many syncs in a row an event can fail to
+	// be written before it is dead-lettered: left out of syncs until
+	// it is retried from the API. Configurable via
+	// SYNC_DEAD_LETTER_AFTER (default 5); 0 never dead-letters.
+	DeadLetterAfter int
 }
 
 // Load loads configuration from environment variables.
@@ -178,6 +395,16 @@ func Load() (*Config, error) {
 	cfg.OIDC.ClientSecret = getEnvRequired("OIDC_CLIENT_SECRET")
 	cfg.OIDC.RedirectURL = getEnvRequired("OIDC_REDIRECT_URL")
 
+	// Source creation allow-list (empty = everyone)
+	cfg.Auth.AllowedEmails = getEnvList("AUTH_ALLOWED_EMAILS")
+	cfg.Auth.AllowedDomains = getEnvList("AUTH_ALLOWED_DOMAINS")
+	cfg.Auth.AdminEmails = getEnvList("AUTH_ADMIN_EMAILS")
+	cfg.Auth.AdminClaim = strings.TrimSpace(os.Getenv("AUTH_ADMIN_CLAIM"))
+	cfg.Auth.AdminClaimValue = getEnv("AUTH_ADMIN_CLAIM_VALUE", "calbridge-admin")
+	for i, domain := range cfg.Auth.AllowedDomains {
+		cfg.Auth.AllowedDomains[i] = strings.TrimPrefix(domain, "@")
+	}
+
 	// Security configuration
 	encKeyHex := getEnvRequired("ENCRYPTION_KEY")
 	if encKeyHex != "" {
@@ -210,9 +437,48 @@ func Load() (*Config, error) {
 	}
 	cfg.Security.OAuthStateMaxAgeSecs = oauthStateMaxAge
 
+	// Sliding session expiration, within an absolute cap
+	sessionRefreshWindow, err := getEnvInt("SESSION_REFRESH_WINDOW_SECS", 3600)
+	if err != nil {
+		return nil, fmt.Errorf("%w: SESSION_REFRESH_WINDOW_SECS: %w", ErrInvalidConfig, err)
+	}
+	if sessionRefreshWindow < 0 {
+		return nil, fmt.Errorf("%w: SESSION_REFRESH_WINDOW_SECS must not be negative", ErrInvalidConfig)
+	}
+	cfg.Security.SessionRefreshWindowSecs = sessionRefreshWindow
+
+	sessionAbsoluteMaxAge, err := getEnvInt("SESSION_ABSOLUTE_MAX_AGE_SECS", 604800)
+	if err != nil {
+		return nil, fmt.Errorf("%w: SESSION_ABSOLUTE_MAX_AGE_SECS: %w", ErrInvalidConfig, err)
+	}
+	if sessionAbsoluteMaxAge < 1 {
+		return nil, fmt.Errorf("%w: SESSION_ABSOLUTE_MAX_AGE_SECS must be at least 1", ErrInvalidConfig)
+	}
+	cfg.Security.SessionAbsoluteMaxAgeSecs = sessionAbsoluteMaxAge
+
 	// Database configuration
 	cfg.Database.Path = getEnv("DATABASE_PATH", "./data/calbridgesync.db")
 
+	maxLockRetries, err := getEnvInt("DB_MAX_LOCK_RETRIES", 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: DB_MAX_LOCK_RETRIES: %w", ErrInvalidConfig, err)
+	}
+	if maxLockRetries < 0 || maxLockRetries > 20 {
+		return nil, fmt.Errorf("%w: DB_MAX_LOCK_RETRIES must be between 0 and 20, got %d",
+			ErrInvalidConfig, maxLockRetries)
+	}
+	cfg.Database.MaxLockRetries = maxLockRetries
+
+	lockBackoffBaseMs, err := getEnvInt("DB_LOCK_BACKOFF_BASE_MS", 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: DB_LOCK_BACKOFF_BASE_MS: %w", ErrInvalidConfig, err)
+	}
+	if lockBackoffBaseMs < 0 || lockBackoffBaseMs > 5000 {
+		return nil, fmt.Errorf("%w: DB_LOCK_BACKOFF_BASE_MS must be between 0 and 5000, got %d",
+			ErrInvalidConfig, lockBackoffBaseMs)
+	}
+	cfg.Database.LockBackoffBaseMs = lockBackoffBaseMs
+
 	// CalDAV configuration
 	cfg.CalDAV.DefaultDestURL = getEnvRequired("DEFAULT_DEST_URL")
 	caldavTimeout, err := getEnvInt("CALDAV_REQUEST_TIMEOUT", 300)
@@ -220,6 +486,57 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("%w: CALDAV_REQUEST_TIMEOUT: %w", ErrInvalidConfig, err)
 	}
 	cfg.CalDAV.RequestTimeoutSecs = caldavTimeout
+	cfg.CalDAV.UserAgent = getEnv("CALDAV_USER_AGENT", "calbridgesync/"+version.Version)
+	if strings.ContainsAny(cfg.CalDAV.UserAgent, "\r\n") {
+		return nil, fmt.Errorf("%w: CALDAV_USER_AGENT must not contain line breaks", ErrInvalidConfig)
+	}
+	maxEvents, err := getEnvInt("CALDAV_MAX_EVENTS_PER_CALENDAR", 100000)
+	if err != nil {
+		return nil, fmt.Errorf("%w: CALDAV_MAX_EVENTS_PER_CALENDAR: %w", ErrInvalidConfig, err)
+	}
+	if maxEvents < 0 {
+		return nil, fmt.Errorf("%w: CALDAV_MAX_EVENTS_PER_CALENDAR must not be negative, got %d",
+			ErrInvalidConfig, maxEvents)
+	}
+	cfg.CalDAV.MaxEventsPerCalendar = maxEvents
+	maxEventBytes, err := getEnvInt("CALDAV_MAX_EVENT_BYTES", 5*1024*1024)
+	if err != nil {
+		return nil, fmt.Errorf("%w: CALDAV_MAX_EVENT_BYTES: %w", ErrInvalidConfig, err)
+	}
+	if maxEventBytes < 0 {
+		return nil, fmt.Errorf("%w: CALDAV_MAX_EVENT_BYTES must not be negative, got %d",
+			ErrInvalidConfig, maxEventBytes)
+	}
+	cfg.CalDAV.MaxEventBytes = maxEventBytes
+	maxResponseBytes, err := getEnvInt("CALDAV_MAX_RESPONSE_BYTES", 64*1024*1024)
+	if err != nil {
+		return nil, fmt.Errorf("%w: CALDAV_MAX_RESPONSE_BYTES: %w", ErrInvalidConfig, err)
+	}
+	if maxResponseBytes <= 0 {
+		return nil, fmt.Errorf("%w: CALDAV_MAX_RESPONSE_BYTES must be positive, got %d",
+			ErrInvalidConfig, maxResponseBytes)
+	}
+	cfg.CalDAV.MaxResponseBytes = int64(maxResponseBytes)
+	cfg.CalDAV.DisableHTTP2 = getEnv("CALDAV_DISABLE_HTTP2", "") == "true"
+	cfg.CalDAV.AllowedDestHosts = getEnvList("CALDAV_ALLOWED_DEST_HOSTS")
+	for _, host := range cfg.CalDAV.AllowedDestHosts {
+		if strings.ContainsAny(host, "/:") || (strings.Contains(host, "*") && !strings.HasPrefix(host, "*.")) {
+			return nil, fmt.Errorf("%w: CALDAV_ALLOWED_DEST_HOSTS entries must be host names or *.domain, got %q",
+				ErrInvalidConfig, host)
+		}
+	}
+	discoverCacheSecs, err := getEnvInt("CALDAV_DISCOVER_CACHE_SECS", 60)
+	if err != nil {
+		return nil, fmt.Errorf("%w: CALDAV_DISCOVER_CACHE_SECS: %w", ErrInvalidConfig, err)
+	}
+	if discoverCacheSecs < 0 {
+		return nil, fmt.Errorf("%w: CALDAV_DISCOVER_CACHE_SECS must not be negative, got %d",
+			ErrInvalidConfig, discoverCacheSecs)
+	}
+	cfg.CalDAV.DiscoverCacheSecs = discoverCacheSecs
+	if cfg.CalDAV.DefaultDestURL != "" && !cfg.CalDAV.DestHostAllowed(cfg.CalDAV.DefaultDestURL) {
+		return nil, fmt.Errorf("%w: DEFAULT_DEST_URL host is not in CALDAV_ALLOWED_DEST_HOSTS", ErrInvalidConfig)
+	}
 
 	// Rate limiting configuration
 	rps, err := getEnvFloat("RATE_LIMIT_RPS", 10.0)
@@ -247,58 +564,71 @@ func Load() (*Config, error) {
 	}
 	cfg.Sync.MaxInterval = maxInterval
 
-	// Alert configuration (all optional)
-	cfg.Alerts.WebhookEnabled = getEnv("ALERT_WEBHOOK_ENABLED", "") == "true"
-	cfg.Alerts.WebhookURL = getEnv("ALERT_WEBHOOK_URL", "")
+	deleteConfirmations, err := getEnvInt("SYNC_DELETE_CONFIRMATIONS", 1)
+	if err != nil {
+		return nil, fmt.Errorf("%w: SYNC_DELETE_CONFIRMATIONS: %w", ErrInvalidConfig, err)
+	}
+	if deleteConfirmations < 1 || deleteConfirmations > 20 {
+		return nil, fmt.Errorf("%w: SYNC_DELETE_CONFIRMATIONS must be between 1 and 20, got %d",
+			ErrInvalidConfig, deleteConfirmations)
+	}
+	cfg.Sync.DeleteConfirmations = deleteConfirmations
 
-	cfg.Alerts.EmailEnabled = getEnv("ALERT_EMAIL_ENABLED", "") == "true"
-	cfg.Alerts.SMTPHost = getEnv("ALERT_SMTP_HOST", "")
-	smtpPort, err := getEnvInt("ALERT_SMTP_PORT", 587)
+	authFailureThreshold, err := getEnvInt("SYNC_AUTH_FAILURE_THRESHOLD", 3)
 	if err != nil {
-		return nil, fmt.Errorf("%w: ALERT_SMTP_PORT: %w", ErrInvalidConfig, err)
+		return nil, fmt.Errorf("%w: SYNC_AUTH_FAILURE_THRESHOLD: %w", ErrInvalidConfig, err)
 	}
-	cfg.Alerts.SMTPPort = smtpPort
-	cfg.Alerts.SMTPUsername = getEnv("ALERT_SMTP_USERNAME", "")
-	cfg.Alerts.SMTPPassword = getEnv("ALERT_SMTP_PASSWORD", "")
-	cfg.Alerts.SMTPFrom = getEnv("ALERT_SMTP_FROM", "")
-	smtpTo := getEnv("ALERT_SMTP_TO", "")
-	if smtpTo != "" {
-		cfg.Alerts.SMTPTo = strings.Split(smtpTo, ",")
-		for i, addr := range cfg.Alerts.SMTPTo {
-			cfg.Alerts.SMTPTo[i] = strings.TrimSpace(addr)
-		}
+	if authFailureThreshold < 0 || authFailureThreshold > 100 {
+		return nil, fmt.Errorf("%w: SYNC_AUTH_FAILURE_THRESHOLD must be between 0 and 100, got %d",
+			ErrInvalidConfig, authFailureThreshold)
 	}
-	cfg.Alerts.SMTPTLS = getEnv("ALERT_SMTP_TLS", "") == "true"
+	cfg.Sync.AuthFailureThreshold = authFailureThreshold
 
-	cooldownMinutes, err := getEnvInt("ALERT_COOLDOWN_MINUTES", 60)
+	maxConcurrent, err := getEnvInt("SYNC_MAX_CONCURRENT", 4)
 	if err != nil {
-		return nil, fmt.Errorf("%w: ALERT_COOLDOWN_MINUTES: %w", ErrInvalidConfig, err)
+		return nil, fmt.Errorf("%w: SYNC_MAX_CONCURRENT: %w", ErrInvalidConfig, err)
+	}
+	if maxConcurrent < 0 {
+		return nil, fmt.Errorf("%w: SYNC_MAX_CONCURRENT must not be negative, got %d",
+			ErrInvalidConfig, maxConcurrent)
 	}
-	cfg.Alerts.CooldownMinutes = cooldownMinutes
+	cfg.Sync.MaxConcurrent = maxConcurrent
 
-	// Retry tuning (Issue #64). Optional — unset means "use notify
-	// package defaults" (3 attempts, 500ms initial backoff). Bounded
-	// to prevent pathological values: zero or negative attempts would
-	// silence all alerts; excessive backoff would delay them.
-	maxAttempts, err := getEnvInt("ALERT_MAX_SEND_ATTEMPTS", 0)
+	maxDuration, err := getEnvInt("SYNC_MAX_DURATION_SECS", 7200)
 	if err != nil {
-		return nil, fmt.Errorf("%w: ALERT_MAX_SEND_ATTEMPTS: %w", ErrInvalidConfig, err)
+		return nil, fmt.Errorf("%w: SYNC_MAX_DURATION_SECS: %w", ErrInvalidConfig, err)
 	}
-	if maxAttempts < 0 || maxAttempts > 10 {
-		return nil, fmt.Errorf("%w: ALERT_MAX_SEND_ATTEMPTS must be between 0 and 10, got %d",
-			ErrInvalidConfig, maxAttempts)
+	if maxDuration < 60 {
+		return nil, fmt.Errorf("%w: SYNC_MAX_DURATION_SECS must be at least 60, got %d",
+			ErrInvalidConfig, maxDuration)
 	}
-	cfg.Alerts.MaxSendAttempts = maxAttempts
+	cfg.Sync.MaxDurationSecs = maxDuration
 
-	initialBackoffMS, err := getEnvInt("ALERT_INITIAL_BACKOFF_MS", 0)
+	connectAttempts, err := getEnvInt("SYNC_CONNECT_ATTEMPTS", 3)
 	if err != nil {
-		return nil, fmt.Errorf("%w: ALERT_INITIAL_BACKOFF_MS: %w", ErrInvalidConfig, err)
+		return nil, fmt.Errorf("%w: SYNC_CONNECT_ATTEMPTS: %w", ErrInvalidConfig, err)
 	}
-	if initialBackoffMS < 0 || initialBackoffMS > 10000 {
-		return nil, fmt.Errorf("%w: ALERT_INITIAL_BACKOFF_MS must be between 0 and 10000, got %d",
-			ErrInvalidConfig, initialBackoffMS)
+	if connectAttempts < 1 || connectAttempts > 5 {
+		return nil, fmt.Errorf("%w: SYNC_CONNECT_ATTEMPTS must be between 1 and 5, got %d",
+			ErrInvalidConfig, connectAttempts)
+	}
+	cfg.Sync.ConnectAttempts = connectAttempts
+
+	connectBackoffMs, err := getEnvInt("SYNC_CONNECT_BACKOFF_MS", 500)
+	if err != nil {
+		return nil, fmt.Errorf("%w: SYNC_CONNECT_BACKOFF_MS: %w", ErrInvalidConfig, err)
+	}
+	if connectBackoffMs < 0 || connectBackoffMs > 10000 {
+		return nil, fmt.Errorf("%w: SYNC_CONNECT_BACKOFF_MS must be between 0 and 10000, got %d",
+			ErrInvalidConfig, connectBackoffMs)
+	}
+	cfg.Sync.ConnectBackoffMs = connectBackoffMs
+
+	alerts, err := loadAlertConfig()
+	if err != nil {
+		return nil, err
 	}
-	cfg.Alerts.InitialBackoffMS = initialBackoffMS
+	cfg.Alerts = alerts
 
 	// Google OAuth2 configuration. As of #79 the per-source client_id
 	// and client_secret live in the sources table, not in env vars.
@@ -313,18 +643,81 @@ func Load() (*Config, error) {
 		cfg.GoogleOAuth.RedirectURL = strings.TrimRight(cfg.Server.BaseURL, "/") + "/auth/oauth/google/callback"
 	}
 
-	// Sync log retention (default 30 days, range 1-365)
+	// Sync log retention (default 30 days, range 0-365, 0 = keep forever)
 	logRetention, err := getEnvInt("SYNC_LOG_RETENTION_DAYS", 30)
 	if err != nil {
 		return nil, fmt.Errorf("%w: SYNC_LOG_RETENTION_DAYS: %w", ErrInvalidConfig, err)
 	}
-	if logRetention < 1 {
-		logRetention = 1
+	if logRetention < 0 {
+		return nil, fmt.Errorf("%w: SYNC_LOG_RETENTION_DAYS must not be negative, got %d",
+			ErrInvalidConfig, logRetention)
 	}
 	if logRetention > 365 {
 		logRetention = 365
 	}
-	cfg.LogRetentionDays = logRetention
+	cfg.Sync.LogRetentionDays = logRetention
+
+	healthLogMinutes, err := getEnvInt("SYNC_HEALTH_LOG_MINUTES", 5)
+	if err != nil {
+		return nil, fmt.Errorf("%w: SYNC_HEALTH_LOG_MINUTES: %w", ErrInvalidConfig, err)
+	}
+	if healthLogMinutes < 1 || healthLogMinutes > 1440 {
+		return nil, fmt.Errorf("%w: SYNC_HEALTH_LOG_MINUTES must be between 1 and 1440, got %d",
+			ErrInvalidConfig, healthLogMinutes)
+	}
+	cfg.Sync.HealthLogMinutes = healthLogMinutes
+
+	recurrencePolicy := strings.ToLower(getEnv("SYNC_INVALID_RRULE", "repair"))
+	if recurrencePolicy != "repair" && recurrencePolicy != "strip" {
+		return nil, fmt.Errorf("%w: SYNC_INVALID_RRULE must be repair or strip, got %q",
+			ErrInvalidConfig, recurrencePolicy)
+	}
+	cfg.Sync.RecurrencePolicy = recurrencePolicy
+
+	encodeFailurePolicy := strings.ToLower(getEnv("SYNC_ENCODE_FAILURE", "skip"))
+	if encodeFailurePolicy != "skip" && encodeFailurePolicy != "raw" {
+		return nil, fmt.Errorf("%w: SYNC_ENCODE_FAILURE must be skip or raw, got %q",
+			ErrInvalidConfig, encodeFailurePolicy)
+	}
+	cfg.Sync.EncodeFailurePolicy = encodeFailurePolicy
+	cfg.Sync.CreateDestCalendar = getEnv("SYNC_CREATE_DEST_CALENDAR", "") == "true"
+
+	warningFailCount, err := getEnvInt("SYNC_WARNING_FAIL_COUNT", 500)
+	if err != nil {
+		return nil, fmt.Errorf("%w: SYNC_WARNING_FAIL_COUNT: %w", ErrInvalidConfig, err)
+	}
+	if warningFailCount < 0 {
+		return nil, fmt.Errorf("%w: SYNC_WARNING_FAIL_COUNT must not be negative, got %d",
+			ErrInvalidConfig, warningFailCount)
+	}
+	cfg.Sync.WarningFailCount = warningFailCount
+
+	warningFailPercent, err := getEnvInt("SYNC_WARNING_FAIL_PERCENT", 50)
+	if err != nil {
+		return nil, fmt.Errorf("%w: SYNC_WARNING_FAIL_PERCENT: %w", ErrInvalidConfig, err)
+	}
+	if warningFailPercent < 0 || warningFailPercent > 100 {
+		return nil, fmt.Errorf("%w: SYNC_WARNING_FAIL_PERCENT must be between 0 and 100, got %d",
+			ErrInvalidConfig, warningFailPercent)
+	}
+	cfg.Sync.WarningFailPercent = warningFailPercent
+
+	vanishedCalendarPolicy := strings.ToLower(getEnv("SYNC_VANISHED_CALENDAR", "keep"))
+	if vanishedCalendarPolicy != "keep" && vanishedCalendarPolicy != "delete" && vanishedCalendarPolicy != "alert" {
+		return nil, fmt.Errorf("%w: SYNC_VANISHED_CALENDAR must be keep, delete or alert, got %q",
+			ErrInvalidConfig, vanishedCalendarPolicy)
+	}
+	cfg.Sync.VanishedCalendarPolicy = vanishedCalendarPolicy
+
+	deadLetterAfter, err := getEnvInt("SYNC_DEAD_LETTER_AFTER", 5)
+	if err != nil {
+		return nil, fmt.Errorf("%w: SYNC_DEAD_LETTER_AFTER: %w", ErrInvalidConfig, err)
+	}
+	if deadLetterAfter < 0 {
+		return nil, fmt.Errorf("%w: SYNC_DEAD_LETTER_AFTER must not be negative, got %d",
+			ErrInvalidConfig, deadLetterAfter)
+	}
+	cfg.Sync.DeadLetterAfter = deadLetterAfter
 
 	// Backup configuration
 	cfg.Backup.Enabled = strings.ToLower(getEnv("BACKUP_ENABLED", "true")) != "false"
@@ -341,6 +734,16 @@ func Load() (*Config, error) {
 	}
 	cfg.Backup.RetentionCount = backupRetention
 
+	// Egress proxy
+	cfg.Proxy.URL = getEnv("OUTBOUND_PROXY_URL", "")
+	if cfg.Proxy.URL != "" {
+		proxyURL, err := url.Parse(cfg.Proxy.URL)
+		if err != nil || (proxyURL.Scheme != "http" && proxyURL.Scheme != "https") || proxyURL.Host == "" {
+			return nil, fmt.Errorf("%w: OUTBOUND_PROXY_URL must be an http or https URL with a host", ErrInvalidConfig)
+		}
+	}
+	cfg.Proxy.NoProxy = getEnv("OUTBOUND_NO_PROXY", os.Getenv("NO_PROXY"))
+
 	// Check for missing required configuration
 	missing := cfg.getMissingRequired()
 	if len(missing) > 0 {
@@ -466,6 +869,95 @@ func getEnvRequired(key string) string {
 	return os.Getenv(key)
 }
 
+// loadAlertConfig reads the ALERT_* variables. Every setting is
+// optional; alerting is off unless a channel is enabled.
+func loadAlertConfig() (AlertConfig, error) {
+	var alerts AlertConfig
+	alerts.WebhookEnabled = getEnv("ALERT_WEBHOOK_ENABLED", "") == "true"
+	alerts.WebhookURL = getEnv("ALERT_WEBHOOK_URL", "")
+	alerts.WebhookSecret = getEnv("ALERT_WEBHOOK_SECRET", "")
+
+	alerts.EmailEnabled = getEnv("ALERT_EMAIL_ENABLED", "") == "true"
+	alerts.SMTPHost = getEnv("ALERT_SMTP_HOST", "")
+	smtpPort, err := getEnvInt("ALERT_SMTP_PORT", 587)
+	if err != nil {
+		return alerts, fmt.Errorf("%w: ALERT_SMTP_PORT: %w", ErrInvalidConfig, err)
+	}
+	alerts.SMTPPort = smtpPort
+	alerts.SMTPUsername = getEnv("ALERT_SMTP_USERNAME", "")
+	alerts.SMTPPassword = getEnv("ALERT_SMTP_PASSWORD", "")
+	alerts.SMTPFrom = getEnv("ALERT_SMTP_FROM", "")
+	smtpTo := getEnv("ALERT_SMTP_TO", "")
+	if smtpTo != "" {
+		alerts.SMTPTo = strings.Split(smtpTo, ",")
+		for i, addr := range alerts.SMTPTo {
+			alerts.SMTPTo[i] = strings.TrimSpace(addr)
+		}
+	}
+	alerts.SMTPTLS = getEnv("ALERT_SMTP_TLS", "") == "true"
+	alerts.EmailHTML = strings.ToLower(getEnv("ALERT_EMAIL_HTML", "true")) != "false"
+
+	cooldownMinutes, err := getEnvInt("ALERT_COOLDOWN_MINUTES", 60)
+	if err != nil {
+		return alerts, fmt.Errorf("%w: ALERT_COOLDOWN_MINUTES: %w", ErrInvalidConfig, err)
+	}
+	alerts.CooldownMinutes = cooldownMinutes
+
+	// Retry tuning (Issue #64). Optional — unset means "use notify
+	// package defaults" (3 attempts, 500ms initial backoff). Bounded
+	// to prevent pathological values: zero or negative attempts would
+	// silence all alerts; excessive backoff would delay them.
+	maxAttempts, err := getEnvInt("ALERT_MAX_SEND_ATTEMPTS", 0)
+	if err != nil {
+		return alerts, fmt.Errorf("%w: ALERT_MAX_SEND_ATTEMPTS: %w", ErrInvalidConfig, err)
+	}
+	if maxAttempts < 0 || maxAttempts > 10 {
+		return alerts, fmt.Errorf("%w: ALERT_MAX_SEND_ATTEMPTS must be between 0 and 10, got %d",
+			ErrInvalidConfig, maxAttempts)
+	}
+	alerts.MaxSendAttempts = maxAttempts
+
+	initialBackoffMS, err := getEnvInt("ALERT_INITIAL_BACKOFF_MS", 0)
+	if err != nil {
+		return alerts, fmt.Errorf("%w: ALERT_INITIAL_BACKOFF_MS: %w", ErrInvalidConfig, err)
+	}
+	if initialBackoffMS < 0 || initialBackoffMS > 10000 {
+		return alerts, fmt.Errorf("%w: ALERT_INITIAL_BACKOFF_MS must be between 0 and 10000, got %d",
+			ErrInvalidConfig, initialBackoffMS)
+	}
+	alerts.InitialBackoffMS = initialBackoffMS
+	return alerts, nil
+}
+
+// ReloadAlertConfig re-reads the alert settings so they can be applied
+// without a restart. ALERT_* values in the .env file replace the ones
+// in the process environment, since Load's godotenv.Load never
+// overrides variables that are already set; other keys are left alone.
+func ReloadAlertConfig() (AlertConfig, error) {
+	if values, err := godotenv.Read(); err == nil {
+		for key, value := range values {
+			if strings.HasPrefix(key, "ALERT_") {
+				if err := os.Setenv(key, value); err != nil {
+					return AlertConfig{}, fmt.Errorf("set %s: %w", key, err)
+				}
+			}
+		}
+	}
+	return loadAlertConfig()
+}
+
+// getEnvList splits a comma-separated variable into lower-cased,
+// trimmed, non-empty entries. Unset yields nil.
+func getEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 // getEnvInt returns the integer value of an environment variable or a default.
 func getEnvInt(key string, defaultValue int) (int, error) {
 	value := os.Getenv(key)
@@ -3,6 +3,8 @@ package config
 import (
 	"errors"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -316,11 +318,14 @@ func TestLoad(t *testing.T) {
 	configEnvVars := []string{
 		"PORT", "BASE_URL", "ENVIRONMENT",
 		"OIDC_ISSUER", "OIDC_CLIENT_ID", "OIDC_CLIENT_SECRET", "OIDC_REDIRECT_URL",
-		"ENCRYPTION_KEY", "SESSION_SECRET", "SESSION_MAX_AGE_SECS", "OAUTH_STATE_MAX_AGE_SECS",
+		"ENCRYPTION_KEY", "SESSION_SECRET", "SESSION_MAX_AGE_SECS", "OAUTH_STATE_MAX_AGE_SECS", "SESSION_REFRESH_WINDOW_SECS", "SESSION_ABSOLUTE_MAX_AGE_SECS",
 		"DATABASE_PATH",
-		"DEFAULT_DEST_URL",
+		"DEFAULT_DEST_URL", "CALDAV_USER_AGENT", "CALDAV_MAX_EVENTS_PER_CALENDAR", "CALDAV_MAX_EVENT_BYTES", "CALDAV_MAX_RESPONSE_BYTES", "CALDAV_DISABLE_HTTP2", "CALDAV_ALLOWED_DEST_HOSTS", "CALDAV_DISCOVER_CACHE_SECS",
 		"RATE_LIMIT_RPS", "RATE_LIMIT_BURST",
-		"MIN_SYNC_INTERVAL", "MAX_SYNC_INTERVAL",
+		"MIN_SYNC_INTERVAL", "MAX_SYNC_INTERVAL", "SYNC_DELETE_CONFIRMATIONS", "SYNC_AUTH_FAILURE_THRESHOLD", "SYNC_CONNECT_ATTEMPTS", "SYNC_CONNECT_BACKOFF_MS", "SYNC_MAX_CONCURRENT", "SYNC_MAX_DURATION_SECS", "SYNC_LOG_RETENTION_DAYS", "SYNC_HEALTH_LOG_MINUTES", "SYNC_INVALID_RRULE", "SYNC_ENCODE_FAILURE", "SYNC_CREATE_DEST_CALENDAR", "SYNC_WARNING_FAIL_COUNT", "SYNC_WARNING_FAIL_PERCENT", "SYNC_VANISHED_CALENDAR", "SYNC_DEAD_LETTER_AFTER",
+		"DB_MAX_LOCK_RETRIES", "DB_LOCK_BACKOFF_BASE_MS",
+		"AUTH_ALLOWED_EMAILS", "AUTH_ALLOWED_DOMAINS", "AUTH_ADMIN_EMAILS", "AUTH_ADMIN_CLAIM", "AUTH_ADMIN_CLAIM_VALUE",
+		"OUTBOUND_PROXY_URL", "OUTBOUND_NO_PROXY", "NO_PROXY",
 	}
 
 	cleanup := func() func() {
@@ -398,12 +403,75 @@ func TestLoad(t *testing.T) {
 		if cfg.Sync.MaxInterval != 3600 {
 			t.Errorf("expected default MaxInterval 3600, got %d", cfg.Sync.MaxInterval)
 		}
+		if cfg.Sync.DeleteConfirmations != 1 {
+			t.Errorf("expected default DeleteConfirmations 1, got %d", cfg.Sync.DeleteConfirmations)
+		}
+		if cfg.Sync.AuthFailureThreshold != 3 {
+			t.Errorf("expected default AuthFailureThreshold 3, got %d", cfg.Sync.AuthFailureThreshold)
+		}
+		if cfg.Sync.ConnectAttempts != 3 || cfg.Sync.ConnectBackoffMs != 500 {
+			t.Errorf("expected default connect retry 3 attempts / 500ms, got %d / %d",
+				cfg.Sync.ConnectAttempts, cfg.Sync.ConnectBackoffMs)
+		}
+		if cfg.Sync.MaxConcurrent != 4 {
+			t.Errorf("expected default MaxConcurrent 4, got %d", cfg.Sync.MaxConcurrent)
+		}
+		if cfg.Sync.MaxDurationSecs != 7200 {
+			t.Errorf("expected default MaxDurationSecs 7200, got %d", cfg.Sync.MaxDurationSecs)
+		}
+		if cfg.Sync.LogRetentionDays != 30 {
+			t.Errorf("expected default LogRetentionDays 30, got %d", cfg.Sync.LogRetentionDays)
+		}
+		if cfg.Sync.HealthLogMinutes != 5 {
+			t.Errorf("expected default HealthLogMinutes 5, got %d", cfg.Sync.HealthLogMinutes)
+		}
+		if cfg.Sync.RecurrencePolicy != "repair" {
+			t.Errorf("expected default RecurrencePolicy repair, got %q", cfg.Sync.RecurrencePolicy)
+		}
+		if cfg.Sync.EncodeFailurePolicy != "skip" {
+			t.Errorf("expected default EncodeFailurePolicy skip, got %q", cfg.Sync.EncodeFailurePolicy)
+		}
+		if cfg.Sync.CreateDestCalendar {
+			t.Error("expected CreateDestCalendar off by default")
+		}
+		if cfg.Sync.WarningFailCount != 500 || cfg.Sync.WarningFailPercent != 50 {
+			t.Errorf("expected default warning limits 500 / 50%%, got %d / %d%%",
+				cfg.Sync.WarningFailCount, cfg.Sync.WarningFailPercent)
+		}
+		if cfg.Sync.VanishedCalendarPolicy != "keep" {
+			t.Errorf("expected default VanishedCalendarPolicy keep, got %q", cfg.Sync.VanishedCalendarPolicy)
+		}
+		if cfg.Sync.DeadLetterAfter != 5 {
+			t.Errorf("expected default DeadLetterAfter 5, got %d", cfg.Sync.DeadLetterAfter)
+		}
+		if cfg.CalDAV.MaxEventsPerCalendar != 100000 {
+			t.Errorf("expected default MaxEventsPerCalendar 100000, got %d", cfg.CalDAV.MaxEventsPerCalendar)
+		}
+		if cfg.CalDAV.MaxEventBytes != 5*1024*1024 {
+			t.Errorf("expected default MaxEventBytes 5 MiB, got %d", cfg.CalDAV.MaxEventBytes)
+		}
+		if cfg.CalDAV.MaxResponseBytes != 64*1024*1024 {
+			t.Errorf("expected default MaxResponseBytes 64 MiB, got %d", cfg.CalDAV.MaxResponseBytes)
+		}
+		if cfg.CalDAV.DisableHTTP2 {
+			t.Error("expected HTTP/2 to be enabled by default")
+		}
+		if cfg.CalDAV.DiscoverCacheSecs != 60 {
+			t.Errorf("expected default DiscoverCacheSecs 60, got %d", cfg.CalDAV.DiscoverCacheSecs)
+		}
+		if cfg.CalDAV.UserAgent != "calbridgesync/dev" {
+			t.Errorf("expected default UserAgent 'calbridgesync/dev', got %q", cfg.CalDAV.UserAgent)
+		}
 		if cfg.Security.SessionMaxAgeSecs != 86400 {
 			t.Errorf("expected default SessionMaxAgeSecs 86400, got %d", cfg.Security.SessionMaxAgeSecs)
 		}
 		if cfg.Security.OAuthStateMaxAgeSecs != 300 {
 			t.Errorf("expected default OAuthStateMaxAgeSecs 300, got %d", cfg.Security.OAuthStateMaxAgeSecs)
 		}
+		if cfg.Security.SessionRefreshWindowSecs != 3600 || cfg.Security.SessionAbsoluteMaxAgeSecs != 604800 {
+			t.Errorf("expected default session refresh 3600/604800, got %d/%d",
+				cfg.Security.SessionRefreshWindowSecs, cfg.Security.SessionAbsoluteMaxAgeSecs)
+		}
 	})
 
 	t.Run("loads config with custom values", func(t *testing.T) {
@@ -419,8 +487,26 @@ func TestLoad(t *testing.T) {
 		os.Setenv("RATE_LIMIT_BURST", "10")
 		os.Setenv("MIN_SYNC_INTERVAL", "60")
 		os.Setenv("MAX_SYNC_INTERVAL", "7200")
+		os.Setenv("SYNC_DELETE_CONFIRMATIONS", "3")
+		os.Setenv("SYNC_INVALID_RRULE", "Strip")
+		os.Setenv("SYNC_ENCODE_FAILURE", "RAW")
+		os.Setenv("SYNC_CREATE_DEST_CALENDAR", "true")
+		os.Setenv("SYNC_WARNING_FAIL_COUNT", "0")
+		os.Setenv("SYNC_WARNING_FAIL_PERCENT", "20")
+		os.Setenv("SYNC_VANISHED_CALENDAR", "Delete")
+		os.Setenv("SYNC_DEAD_LETTER_AFTER", "0")
+		os.Setenv("CALDAV_USER_AGENT", "CorpCalendar/2.1")
+		os.Setenv("CALDAV_MAX_EVENTS_PER_CALENDAR", "0")
+		os.Setenv("CALDAV_MAX_EVENT_BYTES", "1048576")
+		os.Setenv("CALDAV_MAX_RESPONSE_BYTES", "8388608")
+		os.Setenv("CALDAV_DISABLE_HTTP2", "true")
+		os.Setenv("CALDAV_DISCOVER_CACHE_SECS", "0")
 		os.Setenv("SESSION_MAX_AGE_SECS", "3600")
 		os.Setenv("OAUTH_STATE_MAX_AGE_SECS", "600")
+		os.Setenv("SESSION_REFRESH_WINDOW_SECS", "0")
+		os.Setenv("SESSION_ABSOLUTE_MAX_AGE_SECS", "86400")
+		os.Setenv("DB_MAX_LOCK_RETRIES", "10")
+		os.Setenv("DB_LOCK_BACKOFF_BASE_MS", "250")
 
 		cfg, err := Load()
 		if err != nil {
@@ -448,12 +534,431 @@ func TestLoad(t *testing.T) {
 		if cfg.Sync.MaxInterval != 7200 {
 			t.Errorf("expected MaxInterval 7200, got %d", cfg.Sync.MaxInterval)
 		}
+		if cfg.Sync.DeleteConfirmations != 3 {
+			t.Errorf("expected DeleteConfirmations 3, got %d", cfg.Sync.DeleteConfirmations)
+		}
+		if cfg.Sync.RecurrencePolicy != "strip" {
+			t.Errorf("expected RecurrencePolicy strip, got %q", cfg.Sync.RecurrencePolicy)
+		}
+		if cfg.Sync.EncodeFailurePolicy != "raw" {
+			t.Errorf("expected EncodeFailurePolicy raw, got %q", cfg.Sync.EncodeFailurePolicy)
+		}
+		if !cfg.Sync.CreateDestCalendar {
+			t.Error("expected CreateDestCalendar on")
+		}
+		if cfg.Sync.WarningFailCount != 0 || cfg.Sync.WarningFailPercent != 20 {
+			t.Errorf("expected warning limits 0 / 20%%, got %d / %d%%",
+				cfg.Sync.WarningFailCount, cfg.Sync.WarningFailPercent)
+		}
+		if cfg.Sync.VanishedCalendarPolicy != "delete" {
+			t.Errorf("expected VanishedCalendarPolicy delete, got %q", cfg.Sync.VanishedCalendarPolicy)
+		}
+		if cfg.Sync.DeadLetterAfter != 0 {
+			t.Errorf("expected DeadLetterAfter 0, got %d", cfg.Sync.DeadLetterAfter)
+		}
+		if cfg.CalDAV.MaxEventsPerCalendar != 0 {
+			t.Errorf("expected MaxEventsPerCalendar 0, got %d", cfg.CalDAV.MaxEventsPerCalendar)
+		}
+		if cfg.CalDAV.MaxEventBytes != 1048576 {
+			t.Errorf("expected MaxEventBytes 1048576, got %d", cfg.CalDAV.MaxEventBytes)
+		}
+		if cfg.CalDAV.MaxResponseBytes != 8388608 {
+			t.Errorf("expected MaxResponseBytes 8388608, got %d", cfg.CalDAV.MaxResponseBytes)
+		}
+		if !cfg.CalDAV.DisableHTTP2 {
+			t.Error("expected CALDAV_DISABLE_HTTP2=true to disable HTTP/2")
+		}
+		if cfg.CalDAV.DiscoverCacheSecs != 0 {
+			t.Errorf("expected DiscoverCacheSecs 0, got %d", cfg.CalDAV.DiscoverCacheSecs)
+		}
+		if cfg.CalDAV.UserAgent != "CorpCalendar/2.1" {
+			t.Errorf("expected UserAgent 'CorpCalendar/2.1', got %q", cfg.CalDAV.UserAgent)
+		}
 		if cfg.Security.SessionMaxAgeSecs != 3600 {
 			t.Errorf("expected SessionMaxAgeSecs 3600, got %d", cfg.Security.SessionMaxAgeSecs)
 		}
 		if cfg.Security.OAuthStateMaxAgeSecs != 600 {
 			t.Errorf("expected OAuthStateMaxAgeSecs 600, got %d", cfg.Security.OAuthStateMaxAgeSecs)
 		}
+		if cfg.Security.SessionRefreshWindowSecs != 0 || cfg.Security.SessionAbsoluteMaxAgeSecs != 86400 {
+			t.Errorf("expected session refresh 0/86400, got %d/%d",
+				cfg.Security.SessionRefreshWindowSecs, cfg.Security.SessionAbsoluteMaxAgeSecs)
+		}
+		if cfg.Database.MaxLockRetries != 10 {
+			t.Errorf("expected MaxLockRetries 10, got %d", cfg.Database.MaxLockRetries)
+		}
+		if cfg.Database.LockBackoffBaseMs != 250 {
+			t.Errorf("expected LockBackoffBaseMs 250, got %d", cfg.Database.LockBackoffBaseMs)
+		}
+	})
+
+	t.Run("returns error for out-of-range DB lock retry settings", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+
+		for _, tc := range []struct{ key, val string }{
+			{"DB_MAX_LOCK_RETRIES", "21"},
+			{"DB_MAX_LOCK_RETRIES", "-1"},
+			{"DB_LOCK_BACKOFF_BASE_MS", "5001"},
+			{"DB_LOCK_BACKOFF_BASE_MS", "abc"},
+		} {
+			clearAllEnvVars()
+			setRequiredEnvVars()
+			os.Setenv(tc.key, tc.val)
+
+			_, err := Load()
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("%s=%s: expected ErrInvalidConfig, got %v", tc.key, tc.val, err)
+			}
+		}
+	})
+
+	t.Run("SYNC_LOG_RETENTION_DAYS accepts 0 and caps at 365", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+
+		for _, tc := range []struct {
+			val  string
+			want int
+		}{{"0", 0}, {"90", 90}, {"1000", 365}} {
+			clearAllEnvVars()
+			setRequiredEnvVars()
+			os.Setenv("SYNC_LOG_RETENTION_DAYS", tc.val)
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("SYNC_LOG_RETENTION_DAYS=%s: unexpected error: %v", tc.val, err)
+			}
+			if cfg.Sync.LogRetentionDays != tc.want {
+				t.Errorf("SYNC_LOG_RETENTION_DAYS=%s: expected %d, got %d", tc.val, tc.want, cfg.Sync.LogRetentionDays)
+			}
+		}
+
+		os.Setenv("SYNC_LOG_RETENTION_DAYS", "-1")
+		if _, err := Load(); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("SYNC_LOG_RETENTION_DAYS=-1: expected ErrInvalidConfig, got %v", err)
+		}
+	})
+
+	t.Run("returns error for negative event limits", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+
+		for _, key := range []string{"CALDAV_MAX_EVENTS_PER_CALENDAR", "CALDAV_MAX_EVENT_BYTES"} {
+			clearAllEnvVars()
+			setRequiredEnvVars()
+			os.Setenv(key, "-1")
+			if _, err := Load(); !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("%s=-1: expected ErrInvalidConfig, got %v", key, err)
+			}
+		}
+	})
+
+	t.Run("returns error for non-positive CALDAV_MAX_RESPONSE_BYTES", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+
+		for _, val := range []string{"0", "-1", "abc"} {
+			clearAllEnvVars()
+			setRequiredEnvVars()
+			os.Setenv("CALDAV_MAX_RESPONSE_BYTES", val)
+			if _, err := Load(); !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("CALDAV_MAX_RESPONSE_BYTES=%s: expected ErrInvalidConfig, got %v", val, err)
+			}
+		}
+	})
+
+	t.Run("returns error for invalid CALDAV_DISCOVER_CACHE_SECS", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+
+		for _, val := range []string{"-1", "abc"} {
+			clearAllEnvVars()
+			setRequiredEnvVars()
+			os.Setenv("CALDAV_DISCOVER_CACHE_SECS", val)
+			if _, err := Load(); !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("CALDAV_DISCOVER_CACHE_SECS=%s: expected ErrInvalidConfig, got %v", val, err)
+			}
+		}
+	})
+
+	t.Run("returns error for out-of-range SYNC_DELETE_CONFIRMATIONS", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+
+		for _, val := range []string{"0", "21", "abc"} {
+			clearAllEnvVars()
+			setRequiredEnvVars()
+			os.Setenv("SYNC_DELETE_CONFIRMATIONS", val)
+
+			_, err := Load()
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("SYNC_DELETE_CONFIRMATIONS=%s: expected ErrInvalidConfig, got %v", val, err)
+			}
+		}
+	})
+
+	t.Run("returns error for out-of-range SYNC_AUTH_FAILURE_THRESHOLD", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+
+		for _, val := range []string{"-1", "101", "abc"} {
+			clearAllEnvVars()
+			setRequiredEnvVars()
+			os.Setenv("SYNC_AUTH_FAILURE_THRESHOLD", val)
+
+			_, err := Load()
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("SYNC_AUTH_FAILURE_THRESHOLD=%s: expected ErrInvalidConfig, got %v", val, err)
+			}
+		}
+	})
+
+	t.Run("returns error for out-of-range connect retry settings", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+
+		for key, vals := range map[string][]string{
+			"SYNC_CONNECT_ATTEMPTS":   {"0", "6", "abc"},
+			"SYNC_CONNECT_BACKOFF_MS": {"-1", "10001", "abc"},
+		} {
+			for _, val := range vals {
+				clearAllEnvVars()
+				setRequiredEnvVars()
+				os.Setenv(key, val)
+
+				_, err := Load()
+				if !errors.Is(err, ErrInvalidConfig) {
+					t.Errorf("%s=%s: expected ErrInvalidConfig, got %v", key, val, err)
+				}
+			}
+		}
+	})
+
+	t.Run("SYNC_MAX_DURATION_SECS overrides the default", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+		clearAllEnvVars()
+		setRequiredEnvVars()
+		os.Setenv("SYNC_MAX_DURATION_SECS", "900")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Sync.MaxDurationSecs != 900 {
+			t.Errorf("expected MaxDurationSecs 900, got %d", cfg.Sync.MaxDurationSecs)
+		}
+	})
+
+	t.Run("returns error for invalid SYNC_MAX_DURATION_SECS", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+
+		for _, val := range []string{"0", "59", "-1", "abc"} {
+			clearAllEnvVars()
+			setRequiredEnvVars()
+			os.Setenv("SYNC_MAX_DURATION_SECS", val)
+			if _, err := Load(); !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("SYNC_MAX_DURATION_SECS=%s: expected ErrInvalidConfig, got %v", val, err)
+			}
+		}
+	})
+
+	t.Run("OUTBOUND_PROXY_URL sets the egress proxy", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+		clearAllEnvVars()
+		setRequiredEnvVars()
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Proxy.URL != "" || cfg.Proxy.NoProxy != "" {
+			t.Errorf("expected no proxy by default, got %+v", cfg.Proxy)
+		}
+
+		os.Setenv("OUTBOUND_PROXY_URL", "http://proxy.corp.example:3128")
+		os.Setenv("NO_PROXY", "localhost,.corp.example")
+		if cfg, err = Load(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Proxy.URL != "http://proxy.corp.example:3128" || cfg.Proxy.NoProxy != "localhost,.corp.example" {
+			t.Errorf("expected the proxy with NO_PROXY's exclusions, got %+v", cfg.Proxy)
+		}
+
+		os.Setenv("OUTBOUND_NO_PROXY", "caldav.internal")
+		if cfg, err = Load(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Proxy.NoProxy != "caldav.internal" {
+			t.Errorf("expected OUTBOUND_NO_PROXY to take precedence, got %q", cfg.Proxy.NoProxy)
+		}
+	})
+
+	t.Run("returns error for invalid OUTBOUND_PROXY_URL", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+
+		for _, val := range []string{"proxy.corp.example:3128", "socks5://proxy:1080", "http://", "://bad"} {
+			clearAllEnvVars()
+			setRequiredEnvVars()
+			os.Setenv("OUTBOUND_PROXY_URL", val)
+			if _, err := Load(); !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("OUTBOUND_PROXY_URL=%s: expected ErrInvalidConfig, got %v", val, err)
+			}
+		}
+	})
+
+	t.Run("returns error for invalid SYNC_MAX_CONCURRENT", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+
+		for _, val := range []string{"-1", "abc"} {
+			clearAllEnvVars()
+			setRequiredEnvVars()
+			os.Setenv("SYNC_MAX_CONCURRENT", val)
+
+			_, err := Load()
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("SYNC_MAX_CONCURRENT=%s: expected ErrInvalidConfig, got %v", val, err)
+			}
+		}
+	})
+
+	t.Run("returns error for out-of-range SYNC_HEALTH_LOG_MINUTES", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+
+		for _, val := range []string{"0", "1441", "abc"} {
+			clearAllEnvVars()
+			setRequiredEnvVars()
+			os.Setenv("SYNC_HEALTH_LOG_MINUTES", val)
+
+			_, err := Load()
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("SYNC_HEALTH_LOG_MINUTES=%s: expected ErrInvalidConfig, got %v", val, err)
+			}
+		}
+	})
+
+	t.Run("returns error for out-of-range warning limits", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+
+		for _, tc := range []struct{ key, val string }{
+			{"SYNC_WARNING_FAIL_COUNT", "-1"},
+			{"SYNC_WARNING_FAIL_COUNT", "abc"},
+			{"SYNC_WARNING_FAIL_PERCENT", "101"},
+			{"SYNC_WARNING_FAIL_PERCENT", "-5"},
+		} {
+			clearAllEnvVars()
+			setRequiredEnvVars()
+			os.Setenv(tc.key, tc.val)
+
+			_, err := Load()
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("%s=%s: expected ErrInvalidConfig, got %v", tc.key, tc.val, err)
+			}
+		}
+	})
+
+	t.Run("returns error for unknown SYNC_INVALID_RRULE", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+		clearAllEnvVars()
+		setRequiredEnvVars()
+		os.Setenv("SYNC_INVALID_RRULE", "ignore")
+
+		_, err := Load()
+		if !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("expected ErrInvalidConfig, got %v", err)
+		}
+	})
+
+	t.Run("parses CALDAV_ALLOWED_DEST_HOSTS", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+		clearAllEnvVars()
+		setRequiredEnvVars()
+		os.Setenv("CALDAV_ALLOWED_DEST_HOSTS", " CalDAV.example.com, *.corp.example ")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if got := strings.Join(cfg.CalDAV.AllowedDestHosts, ","); got != "caldav.example.com,*.corp.example" {
+			t.Errorf("expected the trimmed, lower-cased hosts, got %q", got)
+		}
+	})
+
+	t.Run("returns error for an invalid CALDAV_ALLOWED_DEST_HOSTS entry", func(t *testing.T) {
+		for _, val := range []string{"https://caldav.example.com", "caldav.example.com:443", "cal*.example.com"} {
+			restore := cleanup()
+			clearAllEnvVars()
+			setRequiredEnvVars()
+			os.Setenv("CALDAV_ALLOWED_DEST_HOSTS", "caldav.example.com,"+val)
+
+			_, err := Load()
+			restore()
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("CALDAV_ALLOWED_DEST_HOSTS=%s: expected ErrInvalidConfig, got %v", val, err)
+			}
+		}
+	})
+
+	t.Run("returns error when DEFAULT_DEST_URL is outside CALDAV_ALLOWED_DEST_HOSTS", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+		clearAllEnvVars()
+		setRequiredEnvVars()
+		os.Setenv("CALDAV_ALLOWED_DEST_HOSTS", "other.example.com")
+
+		_, err := Load()
+		if !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("expected ErrInvalidConfig, got %v", err)
+		}
+	})
+
+	t.Run("returns error for unknown SYNC_ENCODE_FAILURE", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+		clearAllEnvVars()
+		setRequiredEnvVars()
+		os.Setenv("SYNC_ENCODE_FAILURE", "empty")
+
+		_, err := Load()
+		if !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("expected ErrInvalidConfig, got %v", err)
+		}
+	})
+
+	t.Run("returns error for unknown SYNC_VANISHED_CALENDAR", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+		clearAllEnvVars()
+		setRequiredEnvVars()
+		os.Setenv("SYNC_VANISHED_CALENDAR", "archive")
+
+		_, err := Load()
+		if !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("expected ErrInvalidConfig, got %v", err)
+		}
+	})
+
+	t.Run("returns error for invalid SYNC_DEAD_LETTER_AFTER", func(t *testing.T) {
+		for _, value := range []string{"-1", "often"} {
+			restore := cleanup()
+			clearAllEnvVars()
+			setRequiredEnvVars()
+			os.Setenv("SYNC_DEAD_LETTER_AFTER", value)
+
+			_, err := Load()
+			restore()
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("%q: expected ErrInvalidConfig, got %v", value, err)
+			}
+		}
 	})
 
 	t.Run("returns error for missing required fields", func(t *testing.T) {
@@ -631,6 +1136,24 @@ func TestLoad(t *testing.T) {
 		}
 	})
 
+	t.Run("returns error for invalid session refresh settings", func(t *testing.T) {
+		for key, value := range map[string]string{
+			"SESSION_REFRESH_WINDOW_SECS":   "-1",
+			"SESSION_ABSOLUTE_MAX_AGE_SECS": "0",
+		} {
+			restore := cleanup()
+			clearAllEnvVars()
+			setRequiredEnvVars()
+			os.Setenv(key, value)
+
+			_, err := Load()
+			restore()
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("%s=%s: expected ErrInvalidConfig, got %v", key, value, err)
+			}
+		}
+	})
+
 	t.Run("environment is case-insensitive", func(t *testing.T) {
 		restore := cleanup()
 		defer restore()
@@ -647,6 +1170,159 @@ func TestLoad(t *testing.T) {
 			t.Errorf("expected Environment 'development', got %q", cfg.Server.Environment)
 		}
 	})
+
+	t.Run("parses the source creation allow-list", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+		clearAllEnvVars()
+		setRequiredEnvVars()
+		os.Setenv("AUTH_ALLOWED_EMAILS", " Admin@Example.com, ,ops@example.org")
+		os.Setenv("AUTH_ALLOWED_DOMAINS", "@Corp.example,team.example")
+		os.Setenv("AUTH_ADMIN_EMAILS", "Admin@Example.com")
+		os.Setenv("AUTH_ADMIN_CLAIM", "groups")
+		os.Setenv("AUTH_ADMIN_CLAIM_VALUE", "CalBridge-Admins")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(cfg.Auth.AdminEmails, []string{"admin@example.com"}) {
+			t.Errorf("AdminEmails = %v, want [admin@example.com]", cfg.Auth.AdminEmails)
+		}
+		if cfg.Auth.AdminClaim != "groups" || cfg.Auth.AdminClaimValue != "CalBridge-Admins" {
+			t.Errorf("expected the admin claim kept as given, got %q = %q", cfg.Auth.AdminClaim, cfg.Auth.AdminClaimValue)
+		}
+
+		wantEmails := []string{"admin@example.com", "ops@example.org"}
+		wantDomains := []string{"corp.example", "team.example"}
+		if !reflect.DeepEqual(cfg.Auth.AllowedEmails, wantEmails) {
+			t.Errorf("AllowedEmails = %v, want %v", cfg.Auth.AllowedEmails, wantEmails)
+		}
+		if !reflect.DeepEqual(cfg.Auth.AllowedDomains, wantDomains) {
+			t.Errorf("AllowedDomains = %v, want %v", cfg.Auth.AllowedDomains, wantDomains)
+		}
+	})
+
+	t.Run("allow-list defaults to empty", func(t *testing.T) {
+		restore := cleanup()
+		defer restore()
+		clearAllEnvVars()
+		setRequiredEnvVars()
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Auth.AllowedEmails != nil || cfg.Auth.AllowedDomains != nil {
+			t.Errorf("expected no allow-list, got %+v", cfg.Auth)
+		}
+		if cfg.Auth.AdminClaim != "" || cfg.Auth.AdminClaimValue != "calbridge-admin" {
+			t.Errorf("expected claim-based admins off with the default value, got %+v", cfg.Auth)
+		}
+	})
+}
+
+func TestAuthConfig_CanCreateSources(t *testing.T) {
+	restricted := AuthConfig{
+		AllowedEmails:  []string{"admin@example.com"},
+		AllowedDomains: []string{"corp.example"},
+	}
+	tests := []struct {
+		name  string
+		cfg   AuthConfig
+		email string
+		want  bool
+	}{
+		{"empty lists allow everyone", AuthConfig{}, "anyone@elsewhere.example", true},
+		{"listed email", restricted, "admin@example.com", true},
+		{"listed email in another case", restricted, "Admin@Example.COM", true},
+		{"listed domain", restricted, "someone@corp.example", true},
+		{"subdomain is not the domain", restricted, "someone@sub.corp.example", false},
+		{"unlisted email", restricted, "user@example.com", false},
+		{"no email", restricted, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.CanCreateSources(tt.email); got != tt.want {
+				t.Errorf("CanCreateSources(%q) = %v, want %v", tt.email, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthConfig_IsAdmin(t *testing.T) {
+	admins := AuthConfig{AdminEmails: []string{"admin@example.com"}}
+	if !admins.IsAdmin(" Admin@Example.com") {
+		t.Error("expected a listed email to be an admin")
+	}
+	if admins.IsAdmin("user@example.com") {
+		t.Error("expected an unlisted email not to be an admin")
+	}
+	if (AuthConfig{}).IsAdmin("anyone@example.com") {
+		t.Error("expected nobody to be an admin when the list is empty")
+	}
+}
+
+func TestCalDAVConfig_DestHostAllowed(t *testing.T) {
+	restricted := CalDAVConfig{AllowedDestHosts: []string{"caldav.example.com", "*.corp.example"}}
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://caldav.example.com/dav/", true},
+		{"https://CalDAV.Example.com:8443/dav/", true},
+		{"https://caldav.example.com./dav/", true},
+		{"https://cal.corp.example/", true},
+		{"https://a.b.corp.example/", true},
+		{"https://corp.example/", false},
+		{"https://evilcorp.example/", false},
+		{"https://caldav.example.com.attacker.test/", false},
+		{"https://attacker.test/caldav.example.com/", false},
+		{"/relative/path", false},
+		{"://bad", false},
+	}
+	for _, tt := range tests {
+		if got := restricted.DestHostAllowed(tt.url); got != tt.want {
+			t.Errorf("DestHostAllowed(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+	if !(CalDAVConfig{}).DestHostAllowed("https://anything.test/") {
+		t.Error("expected an empty list to allow any host")
+	}
+}
+
+func TestReloadAlertConfig(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	// t.Setenv restores each key the reload overwrites.
+	t.Setenv("ALERT_WEBHOOK_ENABLED", "false")
+	t.Setenv("ALERT_WEBHOOK_URL", "")
+	t.Setenv("ALERT_WEBHOOK_SECRET", "")
+	t.Setenv("ALERT_SMTP_PORT", "587")
+	t.Setenv("ALERT_COOLDOWN_MINUTES", "")
+	t.Setenv("ALERT_MAX_SEND_ATTEMPTS", "")
+
+	env := "ALERT_WEBHOOK_ENABLED=true\nALERT_WEBHOOK_URL=https://hooks.example.com/x\nALERT_WEBHOOK_SECRET=s3cret\nALERT_COOLDOWN_MINUTES=15\n"
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte(env), 0o600); err != nil {
+		t.Fatalf("write .env: %v", err)
+	}
+	alerts, err := ReloadAlertConfig()
+	if err != nil {
+		t.Fatalf("ReloadAlertConfig: %v", err)
+	}
+	if !alerts.WebhookEnabled || alerts.WebhookURL != "https://hooks.example.com/x" || alerts.WebhookSecret != "s3cret" {
+		t.Errorf("expected the .env webhook settings to replace the environment, got %+v", alerts)
+	}
+	if alerts.CooldownMinutes != 15 || alerts.SMTPPort != 587 {
+		t.Errorf("expected cooldown 15 and port 587, got %d and %d", alerts.CooldownMinutes, alerts.SMTPPort)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("ALERT_MAX_SEND_ATTEMPTS=99\n"), 0o600); err != nil {
+		t.Fatalf("write .env: %v", err)
+	}
+	if _, err := ReloadAlertConfig(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for an out-of-range value, got %v", err)
+	}
 }
 
 func TestConfigStructs(t *testing.T) {
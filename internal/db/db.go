@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -23,6 +24,34 @@ type DB struct {
 	conn *sql.DB
 }
 
+// connectionPragmas configure SQLite for concurrent syncs. WAL lets
+// readers proceed alongside the single writer, busy_timeout makes a
+// blocked writer wait 5s before returning "database is locked" (the
+// sync engine's retry wrapper handles anything longer), and
+// synchronous=NORMAL is durable enough under WAL without an fsync
+// per commit.
+var connectionPragmas = []string{
+	"journal_mode(WAL)",
+	"busy_timeout(5000)",
+	"foreign_keys(ON)",
+	"secure_delete(ON)",
+	"synchronous(NORMAL)",
+}
+
+// buildDSN appends connectionPragmas to dbPath as modernc.org/sqlite
+// _pragma query parameters.
+func buildDSN(dbPath string) string {
+	params := url.Values{}
+	for _, pragma := range connectionPragmas {
+		params.Add("_pragma", pragma)
+	}
+	sep := "?"
+	if strings.Contains(dbPath, "?") {
+		sep = "&"
+	}
+	return dbPath + sep + params.Encode()
+}
+
 // New creates a new database connection and initializes the schema.
 func New(dbPath string) (*DB, error) {
 	// Ensure the directory exists
@@ -31,8 +60,11 @@ func New(dbPath string) (*DB, error) {
 		return nil, fmt.Errorf("%w: failed to create directory: %w", ErrDatabaseInit, err)
 	}
 
-	// Open the database
-	conn, err := sql.Open("sqlite", dbPath)
+	// Open the database. Pragmas ride on the DSN so the driver applies
+	// them to every pooled connection — a one-off conn.Exec only
+	// configures whichever connection happened to run it, leaving the
+	// rest of the pool with no busy_timeout and foreign keys off.
+	conn, err := sql.Open("sqlite", buildDSN(dbPath))
 	if err != nil {
 		return nil, fmt.Errorf("%w: failed to open database: %w", ErrDatabaseInit, err)
 	}
@@ -45,20 +77,11 @@ func New(dbPath string) (*DB, error) {
 	conn.SetConnMaxLifetime(0) // Connections are reused forever
 	conn.SetConnMaxIdleTime(0) // Idle connections are kept forever
 
-	// Configure SQLite for optimal performance and security
-	pragmas := []string{
-		"PRAGMA journal_mode=WAL",
-		"PRAGMA busy_timeout=30000", // 30 seconds to handle concurrent sync operations
-		"PRAGMA foreign_keys=ON",
-		"PRAGMA secure_delete=ON",
-		"PRAGMA synchronous=NORMAL",
-	}
-
-	for _, pragma := range pragmas {
-		if _, err := conn.Exec(pragma); err != nil {
-			conn.Close()
-			return nil, fmt.Errorf("%w: failed to set pragma: %w", ErrDatabaseInit, err)
-		}
+	// Force a connection now so a bad path or pragma fails at startup
+	// rather than on the first query.
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("%w: failed to connect: %w", ErrDatabaseInit, err)
 	}
 
 	db := &DB{conn: conn}
@@ -294,6 +317,197 @@ func (db *DB) migrate() error {
 		// stripped regardless of this flag — they cause RFC-strict
 		// servers like SOGo to 501 the whole calendar object.
 		`ALTER TABLE sources ADD COLUMN strip_alarms INTEGER NOT NULL DEFAULT 0`,
+
+		// Persisted next sync time. Written when a sync finishes so a
+		// restarted process can tell which sources are already overdue
+		// instead of resetting every timer to now + interval. NULL
+		// means "never scheduled" and is treated as due. The composite
+		// index backs GetSourcesDueForSync, which Scheduler.Start uses to
+		// run overdue sources first.
+		`ALTER TABLE sources ADD COLUMN next_sync_at DATETIME`,
+		`CREATE INDEX IF NOT EXISTS idx_sources_enabled_next_sync_at ON sources(enabled, next_sync_at)`,
+
+		// Attendee rewrite map (JSON object of from→to calendar
+		// addresses). Applied to ORGANIZER/ATTENDEE before PUT so
+		// cross-account mirrors show the destination owner as the
+		// organizer/attendee. NULL means no rewriting.
+		`ALTER TABLE sources ADD COLUMN attendee_rewrite TEXT`,
+
+		// UID namespace prefix. When set, every event this source
+		// writes carries UID "prefix-originalUID" so several sources
+		// can share a destination calendar without colliding.
+		`ALTER TABLE sources ADD COLUMN uid_prefix TEXT NOT NULL DEFAULT ''`,
+
+		// Per-source result webhook: receives the full SyncResult after
+		// every run, independent of the alert webhooks.
+		`ALTER TABLE sources ADD COLUMN result_webhook_url TEXT NOT NULL DEFAULT ''`,
+
+		// Per-source CalDAV request pacing (requests/second, 0 = off)
+		// for servers that rate-limit aggressively (iCloud, Google).
+		`ALTER TABLE sources ADD COLUMN max_requests_per_second REAL NOT NULL DEFAULT 0`,
+
+		// Deletion confirmation counters. A tracked event missing from
+		// one side is only deleted from the other after it has been
+		// missing for SYNC_DELETE_CONFIRMATIONS consecutive cycles;
+		// target is which side the pending delete would hit ("dest" or
+		// "source"). A source with additional destinations syncs each
+		// calendar once per destination, so dest_url keeps their counts
+		// apart. Rows are dropped as soon as the event reappears.
+		`CREATE TABLE IF NOT EXISTS deletion_candidates (
+			source_id TEXT NOT NULL,
+			calendar_href TEXT NOT NULL,
+			dest_url TEXT NOT NULL,
+			event_uid TEXT NOT NULL,
+			target TEXT NOT NULL,
+			missing_count INTEGER NOT NULL DEFAULT 0,
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (source_id, calendar_href, dest_url, event_uid, target),
+			FOREIGN KEY (source_id) REFERENCES sources(id) ON DELETE CASCADE
+		)`,
+
+		// Per-user API tokens for scripts and CI. Only the SHA-256 of
+		// the token is stored; the plaintext is shown once at creation.
+		`CREATE TABLE IF NOT EXISTS api_tokens (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			token_hash TEXT NOT NULL UNIQUE,
+			name TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			last_used_at DATETIME,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_api_tokens_user_id ON api_tokens(user_id)`,
+
+		// Read-only share links for a source's status page. The signed
+		// token in the URL carries the row ID and expiry; deleting the
+		// row revokes the link before it expires.
+		`CREATE TABLE IF NOT EXISTS share_links (
+			id TEXT PRIMARY KEY,
+			source_id TEXT NOT NULL,
+			expires_at DATETIME NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (source_id) REFERENCES sources(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_share_links_source_id ON share_links(source_id)`,
+
+		// Extra HTTP headers sent on every CalDAV request for this
+		// source (corporate proxies, picky servers). JSON object,
+		// NULL when unset.
+		`ALTER TABLE sources ADD COLUMN custom_headers TEXT`,
+
+		// Read every PUT back and warn if the server didn't keep it.
+		`ALTER TABLE sources ADD COLUMN verify_writes INTEGER NOT NULL DEFAULT 0`,
+
+		// Category filter: JSON array of CATEGORIES values. When set,
+		// only events tagged with one of them are synced. NULL = all.
+		`ALTER TABLE sources ADD COLUMN include_categories TEXT`,
+
+		// IANA zone events are rewritten into before PUT; '' keeps
+		// each event's own timezones.
+		`ALTER TABLE sources ADD COLUMN target_timezone TEXT NOT NULL DEFAULT ''`,
+
+		// When the source last synced without failing; unlike
+		// last_sync_at it survives failed attempts.
+		`ALTER TABLE sources ADD COLUMN last_success_at DATETIME`,
+
+		// Raw copies of destination events a one-way sync deleted, so
+		// the last sync's deletions can be undone. batch_id groups the
+		// rows written by one sync run; rows expire after a few days.
+		`CREATE TABLE IF NOT EXISTS deleted_event_backups (
+			id TEXT PRIMARY KEY,
+			source_id TEXT NOT NULL,
+			batch_id TEXT NOT NULL,
+			calendar_href TEXT NOT NULL,
+			dest_url TEXT NOT NULL,
+			dest_calendar_path TEXT NOT NULL,
+			event_path TEXT NOT NULL,
+			event_key TEXT NOT NULL,
+			data TEXT NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			expires_at DATETIME NOT NULL,
+			FOREIGN KEY (source_id) REFERENCES sources(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_deleted_event_backups_source ON deleted_event_backups(source_id, created_at)`,
+
+		// Opt-in escalation of partial syncs to failure alerts.
+		`ALTER TABLE user_alert_preferences ADD COLUMN alert_on_partial INTEGER NOT NULL DEFAULT 0`,
+
+		// Whether VJOURNAL entries are synced along with events.
+		`ALTER TABLE sources ADD COLUMN sync_journals INTEGER NOT NULL DEFAULT 0`,
+
+		// Read-only destination guard: never write to the destination.
+		`ALTER TABLE sources ADD COLUMN dest_read_only INTEGER NOT NULL DEFAULT 0`,
+
+		// Whether large inline attachments are synced; on by default so
+		// existing sources keep every attachment.
+		`ALTER TABLE sources ADD COLUMN sync_attachments INTEGER NOT NULL DEFAULT 1`,
+
+		// Short reason for the last failed sync, for the dashboard.
+		`ALTER TABLE sources ADD COLUMN last_error_detail TEXT NOT NULL DEFAULT ''`,
+
+		// Whether UID-less source events get a generated UID.
+		`ALTER TABLE sources ADD COLUMN generate_missing_uids INTEGER NOT NULL DEFAULT 0`,
+
+		// Whether events marked STATUS:CANCELLED are left off the destination.
+		`ALTER TABLE sources ADD COLUMN skip_cancelled INTEGER NOT NULL DEFAULT 0`,
+
+		// Whether redundant VTIMEZONE definitions are trimmed from events.
+		`ALTER TABLE sources ADD COLUMN minimize_timezones INTEGER NOT NULL DEFAULT 0`,
+
+		// Dashboard grouping labels: JSON array of tags. NULL = untagged.
+		`ALTER TABLE sources ADD COLUMN tags TEXT`,
+
+		// Destination properties kept on update: JSON array of names.
+		`ALTER TABLE sources ADD COLUMN preserve_dest_props TEXT`,
+
+		// Per-source alert channel overrides; NULL follows the user's
+		// alert preferences.
+		`ALTER TABLE sources ADD COLUMN alert_email_enabled INTEGER`,
+		`ALTER TABLE sources ADD COLUMN alert_webhook_enabled INTEGER`,
+
+		// Estimated source-minus-destination clock offset, for
+		// latest_wins.
+		`ALTER TABLE sources ADD COLUMN clock_skew_seconds INTEGER NOT NULL DEFAULT 0`,
+
+		// Cause of each malformed event; rows saved before this are
+		// re-categorized the next time their source syncs.
+		`ALTER TABLE malformed_events ADD COLUMN category TEXT NOT NULL DEFAULT 'other'`,
+
+		// Events whose destination write keeps failing. failure_count
+		// counts consecutive failed cycles; once it reaches
+		// SYNC_DEAD_LETTER_AFTER the event is dead-lettered and left
+		// out of syncs until a user retries it. A successful write
+		// drops the row.
+		`CREATE TABLE IF NOT EXISTS failed_events (
+			source_id TEXT NOT NULL,
+			calendar_href TEXT NOT NULL,
+			event_uid TEXT NOT NULL,
+			failure_count INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			dead_lettered INTEGER NOT NULL DEFAULT 0,
+			first_failed_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (source_id, calendar_href, event_uid),
+			FOREIGN KEY (source_id) REFERENCES sources(id) ON DELETE CASCADE
+		)`,
+
+		// Summary/description find-replace rules: JSON array of
+		// {field, find, replace, regex}. NULL = none.
+		`ALTER TABLE sources ADD COLUMN text_rules TEXT`,
+
+		// Collection DAV:getlastmodified, the change marker for
+		// servers without a CTag.
+		`ALTER TABLE sync_states ADD COLUMN last_modified TEXT`,
+
+		// Template for the X-CALBRIDGE-SOURCE stamp written to synced
+		// events; empty = no stamp.
+		`ALTER TABLE sources ADD COLUMN source_stamp TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE sources ADD COLUMN ca_bundle TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE sources ADD COLUMN skip_invited_events INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE sources ADD COLUMN account_email TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE sources ADD COLUMN max_sync_duration_secs INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE sources ADD COLUMN proxy_url TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE sources ADD COLUMN initial_sync_event_cap INTEGER NOT NULL DEFAULT 0`,
 	}
 
 	for _, migration := range migrations {
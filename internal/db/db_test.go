@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+// TestNew_EnablesWALMode verifies that New switches the database into
+// WAL journaling so sync status/log writes from concurrent syncs don't
+// serialize behind readers and trip "database is locked".
+func TestNew_EnablesWALMode(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	var mode string
+	if err := db.conn.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatalf("failed to query journal_mode: %v", err)
+	}
+	if !strings.EqualFold(mode, "wal") {
+		t.Errorf("expected journal_mode wal, got %q", mode)
+	}
+}
+
+// TestNew_SetsBusyTimeout verifies that a busy_timeout is configured so
+// SQLite waits for a competing writer instead of failing immediately.
+func TestNew_SetsBusyTimeout(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	var timeout int
+	if err := db.conn.QueryRow("PRAGMA busy_timeout").Scan(&timeout); err != nil {
+		t.Fatalf("failed to query busy_timeout: %v", err)
+	}
+	if timeout <= 0 {
+		t.Errorf("expected positive busy_timeout, got %d", timeout)
+	}
+}
+
+// TestNew_PragmasApplyToEveryPooledConnection verifies busy_timeout,
+// foreign_keys and synchronous are set on each connection the pool
+// hands out, not just the first one opened.
+func TestNew_PragmasApplyToEveryPooledConnection(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	var conns []*sql.Conn
+	defer func() {
+		for _, c := range conns {
+			c.Close()
+		}
+	}()
+
+	for i := 0; i < 3; i++ {
+		c, err := db.conn.Conn(ctx)
+		if err != nil {
+			t.Fatalf("failed to get connection %d: %v", i, err)
+		}
+		conns = append(conns, c)
+
+		var busyTimeout, foreignKeys, synchronous int
+		if err := c.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&busyTimeout); err != nil {
+			t.Fatalf("conn %d: failed to query busy_timeout: %v", i, err)
+		}
+		if err := c.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&foreignKeys); err != nil {
+			t.Fatalf("conn %d: failed to query foreign_keys: %v", i, err)
+		}
+		if err := c.QueryRowContext(ctx, "PRAGMA synchronous").Scan(&synchronous); err != nil {
+			t.Fatalf("conn %d: failed to query synchronous: %v", i, err)
+		}
+
+		if busyTimeout != 5000 {
+			t.Errorf("conn %d: expected busy_timeout 5000, got %d", i, busyTimeout)
+		}
+		if foreignKeys != 1 {
+			t.Errorf("conn %d: expected foreign_keys on, got %d", i, foreignKeys)
+		}
+		if synchronous != 1 { // 1 = NORMAL
+			t.Errorf("conn %d: expected synchronous NORMAL (1), got %d", i, synchronous)
+		}
+	}
+}
+
+func TestBuildDSN(t *testing.T) {
+	dsn := buildDSN("/data/calbridgesync.db")
+	if !strings.HasPrefix(dsn, "/data/calbridgesync.db?") {
+		t.Errorf("expected path followed by query string, got %q", dsn)
+	}
+	if !strings.Contains(dsn, "_pragma=busy_timeout%285000%29") {
+		t.Errorf("expected busy_timeout pragma in DSN, got %q", dsn)
+	}
+
+	dsn = buildDSN("file:test.db?cache=shared")
+	if !strings.HasPrefix(dsn, "file:test.db?cache=shared&_pragma=") {
+		t.Errorf("expected pragmas appended to existing query, got %q", dsn)
+	}
+}
@@ -13,6 +13,12 @@ const (
 	SyncStatusSuccess SyncStatus = "success"
 	SyncStatusPartial SyncStatus = "partial" // Sync completed with some non-critical warnings
 	SyncStatusError   SyncStatus = "error"   // Sync failed due to critical error
+	// SyncStatusAuthError marks a source whose credentials were rejected
+	// on several consecutive syncs; syncs are paused until they work.
+	SyncStatusAuthError SyncStatus = "auth_error"
+	// SyncStatusCanceled marks a sync stopped on request before it
+	// finished; the work it did before stopping is kept.
+	SyncStatusCanceled SyncStatus = "canceled"
 )
 
 // ConflictStrategy represents how to handle sync conflicts.
@@ -22,6 +28,9 @@ const (
 	ConflictSourceWins ConflictStrategy = "source_wins"
 	ConflictDestWins   ConflictStrategy = "dest_wins"
 	ConflictLatestWins ConflictStrategy = "latest_wins"
+	// ConflictMerge merges the two versions field by field when both
+	// sides changed and writes the result to both.
+	ConflictMerge ConflictStrategy = "merge"
 )
 
 // SyncDirection represents the direction of synchronization.
@@ -30,6 +39,9 @@ type SyncDirection string
 const (
 	SyncDirectionOneWay SyncDirection = "one_way" // Source -> Destination only
 	SyncDirectionTwoWay SyncDirection = "two_way" // Bidirectional sync
+	// SyncDirectionOneWayCreateOnly copies source events whose UID the
+	// destination doesn't have and never updates or deletes anything.
+	SyncDirectionOneWayCreateOnly SyncDirection = "one_way_create_only"
 )
 
 // SourceType represents the type of calendar source.
@@ -68,6 +80,7 @@ var ValidConflictStrategies = map[ConflictStrategy]bool{
 	ConflictSourceWins: true,
 	ConflictDestWins:   true,
 	ConflictLatestWins: true,
+	ConflictMerge:      true,
 }
 
 // IsValid returns true if the conflict strategy is a known valid value.
@@ -77,8 +90,9 @@ func (cs ConflictStrategy) IsValid() bool {
 
 // ValidSyncDirections contains all valid sync direction values.
 var ValidSyncDirections = map[SyncDirection]bool{
-	SyncDirectionOneWay: true,
-	SyncDirectionTwoWay: true,
+	SyncDirectionOneWay:           true,
+	SyncDirectionTwoWay:           true,
+	SyncDirectionOneWayCreateOnly: true,
 }
 
 // IsValid returns true if the sync direction is a known valid value.
@@ -92,33 +106,52 @@ type SourcePreset struct {
 	Type        SourceType
 	BaseURL     string
 	Description string
+	// URLHosts, when set, are the hosts a source URL of this type must
+	// use. An entry starting with "." matches any subdomain.
+	URLHosts []string
+	// AppSpecificPassword means the provider's CalDAV endpoint only
+	// accepts an app-specific password, never the account password.
+	AppSpecificPassword bool
+	// AuthHint tells the user which credentials the provider expects.
+	AuthHint string
 }
 
 // SourcePresets maps source types to their preset configurations.
 var SourcePresets = map[SourceType]SourcePreset{
+	// iCloud redirects each account to its own pNN-caldav.icloud.com.
 	SourceTypeICloud: {
-		Name:        "iCloud",
-		Type:        SourceTypeICloud,
-		BaseURL:     "https://caldav.icloud.com/",
-		Description: "Apple iCloud Calendar",
+		Name:                "iCloud",
+		Type:                SourceTypeICloud,
+		BaseURL:             "https://caldav.icloud.com/",
+		Description:         "Apple iCloud Calendar",
+		URLHosts:            []string{"caldav.icloud.com", ".icloud.com"},
+		AppSpecificPassword: true,
+		AuthHint: "Use your Apple ID email and an app-specific password (xxxx-xxxx-xxxx-xxxx) " +
+			"generated at appleid.apple.com",
 	},
 	SourceTypeGoogle: {
 		Name:        "Google Calendar",
 		Type:        SourceTypeGoogle,
 		BaseURL:     "https://apidata.googleusercontent.com/caldav/v2/",
 		Description: "Google Calendar (requires OAuth)",
+		URLHosts:    []string{"apidata.googleusercontent.com", "www.google.com"},
+		AuthHint:    "Connect with Google OAuth, or use an app password if 2-Step Verification is on",
 	},
 	SourceTypeFastmail: {
 		Name:        "Fastmail",
 		Type:        SourceTypeFastmail,
 		BaseURL:     "https://caldav.fastmail.com/dav/",
 		Description: "Fastmail Calendar",
+		URLHosts:    []string{".fastmail.com"},
+		AuthHint:    "Use your Fastmail address and an app password created under Settings > Privacy & Security",
 	},
 	SourceTypeNextcloud: {
 		Name:        "Nextcloud",
 		Type:        SourceTypeNextcloud,
 		BaseURL:     "",
 		Description: "Nextcloud Calendar (self-hosted)",
+		AuthHint: "Use your Nextcloud username and an app password from Settings > Security; " +
+			"the URL is https://<your-server>/remote.php/dav/",
 	},
 	SourceTypeCustom: {
 		Name:        "Custom CalDAV",
@@ -164,6 +197,7 @@ type UserAlertPreferences struct {
 	WebhookEnabled  *bool     `json:"webhook_enabled"`  // nil = use global default
 	WebhookURL      string    `json:"webhook_url"`      // empty = no personal webhook
 	CooldownMinutes *int      `json:"cooldown_minutes"` // nil = use global default
+	AlertOnPartial  bool      `json:"alert_on_partial"` // alert on syncs that finish with warnings
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 }
@@ -219,15 +253,132 @@ type Source struct {
 	// destination calendar. Malformed VALARMs (missing TRIGGER) are
 	// always stripped regardless of this flag.
 	StripAlarms bool `json:"strip_alarms"`
+	// NextSyncAt is when the scheduler next intends to sync this
+	// source, persisted so schedules survive a restart. Nil until the
+	// first sync finishes.
+	NextSyncAt *time.Time `json:"next_sync_at,omitempty"`
+	// LastSuccessAt is when a sync of this source last finished with
+	// status success or partial. Nil if it never has.
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+	// LastErrorDetail says briefly why the last failed sync failed.
+	// Cleared by the next sync that finishes with success or partial.
+	LastErrorDetail string `json:"last_error_detail,omitempty"`
+	// AttendeeRewrite maps source-account calendar addresses to their
+	// destination-account equivalents (e.g. "alice@work.example" →
+	// "alice@home.example"). ORGANIZER and ATTENDEE values matching a
+	// key are rewritten before each PUT; unmapped addresses are left
+	// alone. Keys match case-insensitively with or without "mailto:".
+	AttendeeRewrite map[string]string `json:"attendee_rewrite,omitempty"`
+	// UIDPrefix namespaces this source's events on the destination:
+	// each UID is written as "UIDPrefix-originalUID" and synced_events
+	// tracks the namespaced form. Empty disables namespacing. Only
+	// honored for one-way sync.
+	UIDPrefix string `json:"uid_prefix,omitempty"`
+	// ResultWebhookURL, when set, receives a JSON summary of every
+	// sync run (success included). Empty disables it.
+	ResultWebhookURL string `json:"result_webhook_url,omitempty"`
+	// MaxRequestsPerSecond caps per-event CalDAV requests (GET, PUT,
+	// DELETE) against both this source's servers. 0 disables pacing.
+	MaxRequestsPerSecond float64 `json:"max_requests_per_second,omitempty"`
+	// CustomHeaders are extra HTTP headers added to every CalDAV
+	// request against this source's servers. Authorization is never
+	// overridden by these.
+	CustomHeaders map[string]string `json:"custom_headers,omitempty"`
+	// VerifyWrites re-reads each event after a successful PUT and adds
+	// a sync warning when the read-back is missing or carries another
+	// UID. Costs one extra GET per write.
+	VerifyWrites bool `json:"verify_writes"`
+	// IncludeCategories, when non-empty, limits the sync to events
+	// whose CATEGORIES contain at least one of these values (matched
+	// case-insensitively). Uncategorized events are then skipped.
+	IncludeCategories []string `json:"include_categories,omitempty"`
+	// TargetTimezone, when set to an IANA name, rewrites each event's
+	// timed DTSTART/DTEND into that zone (with a VTIMEZONE) before
+	// writing it to the destination. All-day events are untouched.
+	TargetTimezone string `json:"target_timezone,omitempty"`
+	// SyncJournals copies VJOURNAL entries along with events. When
+	// off, journals are left out of the sync and counted as skipped.
+	SyncJournals bool `json:"sync_journals"`
+	// DestReadOnly guards a destination the user can only read: the
+	// sync never PUTs or DELETEs there (refused writes are reported as
+	// one warning) and runs one way regardless of SyncDirection.
+	DestReadOnly bool `json:"dest_read_only"`
+	// SyncAttachments copies inline (BASE64) ATTACH properties of any
+	// size. When off, inline attachments too large to be worth the
+	// destination's storage are dropped; URI attachments are always kept.
+	SyncAttachments bool `json:"sync_attachments"`
+	// GenerateMissingUIDs syncs source events that have no UID under
+	// one derived from their summary, start and location, instead of
+	// skipping them. Meant for legacy exports that omit UIDs.
+	GenerateMissingUIDs bool `json:"generate_missing_uids"`
+	// SkipCancelled leaves events marked STATUS:CANCELLED off the
+	// destination, and deletes copies synced before they were
+	// cancelled, instead of mirroring them.
+	SkipCancelled bool `json:"skip_cancelled"`
+	// SkipInvitedEvents syncs only the events the source account
+	// organizes, leaving off the ones it was invited to: those whose
+	// ORGANIZER isn't AccountEmail.
+	SkipInvitedEvents bool `json:"skip_invited_events"`
+	// AccountEmail is the source account's calendar address, for
+	// SkipInvitedEvents. Empty falls back to the source username when
+	// that is an email address.
+	AccountEmail string `json:"account_email,omitempty"`
+	// MaxSyncDurationSecs is how long one sync of this source may run
+	// before the scheduler cancels it as timed out. 0 uses the server
+	// default (SYNC_MAX_DURATION_SECS).
+	MaxSyncDurationSecs int `json:"max_sync_duration_secs"`
+	// ProxyURL is the HTTP(S) proxy this source's CalDAV clients
+	// connect through. Empty uses the server's (OUTBOUND_PROXY_URL).
+	ProxyURL string `json:"proxy_url,omitempty"`
+	// InitialSyncEventCap bounds how many events a calendar's first
+	// sync fetches from the source, keeping the most recent. 0 fetches
+	// them all. Later syncs always list the whole calendar.
+	InitialSyncEventCap int `json:"initial_sync_event_cap"`
+	// MinimizeTimezones trims the VTIMEZONE definitions written with
+	// each event: duplicates and unused ones are dropped, and IANA
+	// zones get one compact canonical definition.
+	MinimizeTimezones bool `json:"minimize_timezones"`
+	// Tags group sources on the dashboard (by client, team, ...). They
+	// don't affect syncing.
+	Tags []string `json:"tags,omitempty"`
+	// PreserveDestProps names iCalendar properties (e.g. X-NOTE) that
+	// an update from the source doesn't overwrite: the destination
+	// copy's values are kept.
+	PreserveDestProps []string `json:"preserve_dest_props,omitempty"`
+	// TextRules rewrite event summaries and descriptions before they
+	// are written, in order.
+	TextRules []TextRule `json:"text_rules,omitempty"`
+	// SourceStamp, when set, is written to every synced event as
+	// X-CALBRIDGE-SOURCE, so the events one source wrote to a shared
+	// destination can be found and cleaned up. {source_name} and
+	// {source_id} are replaced with the source's name and ID.
+	SourceStamp string `json:"source_stamp,omitempty"`
+	// CABundle is a PEM bundle of CA certificates trusted, on top of
+	// the system store, for this source's servers, for CalDAV servers
+	// behind an internal CA. Certificates are public, so it is stored
+	// as is.
+	CABundle string `json:"ca_bundle,omitempty"`
+	// AlertEmailEnabled and AlertWebhookEnabled turn a channel on or
+	// off for this source's alerts only. nil follows the user's alert
+	// preferences, which in turn fall back to the global settings.
+	AlertEmailEnabled   *bool `json:"alert_email_enabled"`
+	AlertWebhookEnabled *bool `json:"alert_webhook_enabled"`
+	// ClockSkewSeconds is how far the source server's clock was ahead
+	// of the destination's (negative: behind) when last measured from
+	// their Date headers. latest_wins corrects timestamps by it.
+	ClockSkewSeconds int `json:"clock_skew_seconds"`
 }
 
 // SyncState represents the synchronization state for a calendar.
 type SyncState struct {
-	ID           string    `json:"id"`
-	SourceID     string    `json:"source_id"`
-	CalendarHref string    `json:"calendar_href"`
-	SyncToken    string    `json:"sync_token"`
-	CTag         string    `json:"ctag"`
+	ID           string `json:"id"`
+	SourceID     string `json:"source_id"`
+	CalendarHref string `json:"calendar_href"`
+	SyncToken    string `json:"sync_token"`
+	CTag         string `json:"ctag"`
+	// LastModified is the collection's DAV:getlastmodified, as the
+	// server formats it, for servers that report no CTag.
+	LastModified string    `json:"last_modified"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
@@ -251,7 +402,11 @@ type SyncLog struct {
 // CalendarConfig holds per-calendar configuration including sync direction.
 // This allows different calendars within a source to have different sync directions.
 type CalendarConfig struct {
-	Path          string        `json:"path"`
+	Path string `json:"path"`
+	// Name is the calendar's display name when it was selected. If the
+	// server later moves the calendar to a new path, the selection is
+	// re-matched by name and Path updated.
+	Name          string        `json:"name,omitempty"`
 	SyncDirection SyncDirection `json:"sync_direction,omitempty"` // empty = use source default
 }
 
@@ -275,6 +430,46 @@ type SyncedEvent struct {
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
+// DeletionTarget names the side a pending deletion would remove an
+// event from.
+type DeletionTarget string
+
+const (
+	DeletionTargetDest   DeletionTarget = "dest"   // missing on source, delete from destination
+	DeletionTargetSource DeletionTarget = "source" // missing on destination, delete from source
+)
+
+// Fields a TextRule can rewrite.
+const (
+	TextRuleFieldSummary     = "summary"
+	TextRuleFieldDescription = "description"
+)
+
+// TextRule is one find/replace step applied to an event's SUMMARY or
+// DESCRIPTION. Find is literal text unless Regex is set, in which case
+// Replace may refer to capture groups ($1, ${name}); the regex ^
+// prefixes.
+type TextRule struct {
+	Field   string `json:"field"`
+	Find    string `json:"find"`
+	Replace string `json:"replace"`
+	Regex   bool   `json:"regex,omitempty"`
+}
+
+// FailedEvent tracks an event whose destination write failed on
+// consecutive syncs. Once DeadLettered, syncs leave it alone until a
+// user retries it.
+type FailedEvent struct {
+	SourceID      string    `json:"source_id"`
+	CalendarHref  string    `json:"calendar_href"`
+	EventUID      string    `json:"event_uid"`
+	FailureCount  int       `json:"failure_count"`
+	LastError     string    `json:"last_error"`
+	DeadLettered  bool      `json:"dead_lettered"`
+	FirstFailedAt time.Time `json:"first_failed_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
 // MalformedEvent tracks corrupted calendar events that cannot be synced.
 type MalformedEvent struct {
 	ID           string    `json:"id"`
@@ -283,8 +478,27 @@ type MalformedEvent struct {
 	EventPath    string    `json:"event_path"`
 	ErrorMessage string    `json:"error_message"`
 	DiscoveredAt time.Time `json:"discovered_at"`
+	// Category is the cause derived from ErrorMessage when the record
+	// was saved, one of the MalformedCategory values.
+	Category string `json:"category"`
 }
 
+// Malformed event categories, derived from the parser or fetch error
+// by classifyMalformedError so events can be grouped by cause.
+const (
+	MalformedCategoryEmpty           = "empty"
+	MalformedCategoryMissingDTStamp  = "missing_dtstamp"
+	MalformedCategoryMissingUID      = "missing_uid"
+	MalformedCategoryMissingProperty = "missing_property"
+	MalformedCategoryMissingColon    = "missing_colon"
+	MalformedCategoryInvalidEncoding = "invalid_encoding"
+	MalformedCategoryInvalidValue    = "invalid_value"
+	MalformedCategoryBadStructure    = "bad_structure"
+	MalformedCategoryTooLarge        = "too_large"
+	MalformedCategoryFetchFailed     = "fetch_failed"
+	MalformedCategoryOther           = "other"
+)
+
 // Destination is an additional sync destination for a source.
 // The primary destination lives on the Source row (dest_url etc.);
 // entries in this table are ADDITIONAL destinations that the sync
@@ -301,6 +515,49 @@ type Destination struct {
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
+// APIToken is a long-lived bearer credential a user issues for
+// programmatic access to /api. TokenHash is never serialized; the
+// plaintext token exists only in the create response.
+type APIToken struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	TokenHash  string     `json:"-"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// ShareLink grants read-only access to one source's status page until
+// ExpiresAt. The URL token is signed separately; this row exists so a
+// link can be revoked early.
+type ShareLink struct {
+	ID        string    `json:"id"`
+	SourceID  string    `json:"source_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DeletedEventBackup is the raw iCalendar data of a destination event
+// that a one-way sync deleted, kept until ExpiresAt so the deletion can
+// be undone. Rows written by the same sync run share a BatchID.
+type DeletedEventBackup struct {
+	ID       string `json:"id"`
+	SourceID string `json:"source_id"`
+	BatchID  string `json:"batch_id"`
+	// CalendarHref is the source calendar the event was synced from;
+	// with EventKey it identifies the synced_events row.
+	CalendarHref string `json:"calendar_href"`
+	// DestURL is the destination the event was deleted from: the
+	// source's own DestURL or one of its additional destinations.
+	DestURL          string    `json:"dest_url"`
+	DestCalendarPath string    `json:"dest_calendar_path"`
+	EventPath        string    `json:"event_path"`
+	EventKey         string    `json:"event_key"`
+	Data             string    `json:"-"`
+	CreatedAt        time.Time `json:"created_at"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
 // AuditLog records a user action for accountability. (#152)
 type AuditLog struct {
 	ID           string    `json:"id"`
@@ -323,6 +580,32 @@ type SourceStats struct {
 	HealthLabel      string        `json:"health_label"`
 }
 
+// SyncOutcomeCounts is how many of a source's sync runs in a window
+// were logged, and how many of those failed.
+type SyncOutcomeCounts struct {
+	Total  int
+	Failed int
+}
+
+// SyncOverview aggregates a user's sources and their recent sync runs
+// for the dashboard. Paused sources are the disabled ones; failing
+// ones are those whose last sync ended in error or auth_error. Events
+// synced counts creates, updates and deletes. SuccessRate is the
+// percentage of the week's runs that did not fail (partial runs
+// completed), or 0 with no runs.
+type SyncOverview struct {
+	TotalSources   int     `json:"total_sources"`
+	ActiveSources  int     `json:"active_sources"`
+	PausedSources  int     `json:"paused_sources"`
+	FailingSources int     `json:"failing_sources"`
+	SyncsToday     int     `json:"syncs_today"`
+	EventsToday    int     `json:"events_synced_today"`
+	EventsWeek     int     `json:"events_synced_7d"`
+	SyncsWeek      int     `json:"syncs_7d"`
+	SuccessRate    float64 `json:"success_rate"`
+	StaleSources   int     `json:"stale_sources"`
+}
+
 // MiniSyncLog is a compact sync log entry for sparklines. (#136)
 type MiniSyncLog struct {
 	Status     SyncStatus `json:"status"`
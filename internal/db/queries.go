@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -121,22 +122,48 @@ func (db *DB) CreateSource(source *Source) error {
 		googleClientSecret = &t
 	}
 
+	// Encode attendee_rewrite as JSON (NULL when empty)
+	attendeeRewriteJSON, err := encodeStringMap(source.AttendeeRewrite, "attendee rewrite")
+	if err != nil {
+		return err
+	}
+	customHeadersJSON, err := encodeStringMap(source.CustomHeaders, "custom headers")
+	if err != nil {
+		return err
+	}
+	includeCategoriesJSON, err := encodeStringList(source.IncludeCategories, "include categories")
+	if err != nil {
+		return err
+	}
+	tagsJSON, err := encodeStringList(source.Tags, "tags")
+	if err != nil {
+		return err
+	}
+	preserveDestPropsJSON, err := encodeStringList(source.PreserveDestProps, "preserved destination properties")
+	if err != nil {
+		return err
+	}
+	textRulesJSON, err := encodeTextRules(source.TextRules)
+	if err != nil {
+		return err
+	}
+
 	query := `INSERT INTO sources (
 		id, user_id, name, source_type, source_url, source_username, source_password,
 		dest_url, dest_username, dest_password, sync_interval, sync_days_past, sync_direction, conflict_strategy,
 		selected_calendars, enabled, last_sync_status, oauth_refresh_token,
-		google_client_id, google_client_secret, strip_alarms, created_at, updated_at
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		google_client_id, google_client_secret, strip_alarms, attendee_rewrite, uid_prefix, result_webhook_url, max_requests_per_second, custom_headers, verify_writes, include_categories, target_timezone, sync_journals, dest_read_only, sync_attachments, generate_missing_uids, skip_cancelled, minimize_timezones, tags, preserve_dest_props, text_rules, source_stamp, ca_bundle, skip_invited_events, account_email, max_sync_duration_secs, proxy_url, initial_sync_event_cap, alert_email_enabled, alert_webhook_enabled, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	_, err := db.conn.Exec(query,
+	_, err = db.conn.Exec(query,
 		source.ID, source.UserID, source.Name, source.SourceType,
 		source.SourceURL, source.SourceUsername, source.SourcePassword,
 		source.DestURL, source.DestUsername, source.DestPassword,
 		source.SyncInterval, source.SyncDaysPast, source.SyncDirection, source.ConflictStrategy,
 		selectedCalendarsJSON, source.Enabled,
 		source.LastSyncStatus, oauthRefreshToken,
-		googleClientID, googleClientSecret, source.StripAlarms,
-		source.CreatedAt, source.UpdatedAt,
+		googleClientID, googleClientSecret, source.StripAlarms, attendeeRewriteJSON, source.UIDPrefix, source.ResultWebhookURL,
+		source.MaxRequestsPerSecond, customHeadersJSON, source.VerifyWrites, includeCategoriesJSON, source.TargetTimezone, source.SyncJournals, source.DestReadOnly, source.SyncAttachments, source.GenerateMissingUIDs, source.SkipCancelled, source.MinimizeTimezones, tagsJSON, preserveDestPropsJSON, textRulesJSON, source.SourceStamp, source.CABundle, source.SkipInvitedEvents, source.AccountEmail, source.MaxSyncDurationSecs, source.ProxyURL, source.InitialSyncEventCap, source.AlertEmailEnabled, source.AlertWebhookEnabled, source.CreatedAt, source.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create source: %w", err)
@@ -149,11 +176,15 @@ func (db *DB) CreateSource(source *Source) error {
 // kept in one place so every query + scan function stays in lockstep.
 // (#70) added oauth_refresh_token at the end. (#79) appended
 // google_client_id and google_client_secret so per-source Google
-// credentials follow the same scan-positional contract.
+// credentials follow the same scan-positional contract. next_sync_at
+// is read-only here; it is written via UpdateSourceNextSyncAt, and
+// last_success_at likewise via UpdateSourceSyncStatus.
 const sourceSelectColumns = `id, user_id, name, source_type, source_url, source_username, source_password,
 	dest_url, dest_username, dest_password, sync_interval, sync_days_past, sync_direction, conflict_strategy,
 	selected_calendars, enabled, last_sync_at, last_sync_status, last_sync_message, created_at, updated_at,
-	oauth_refresh_token, google_client_id, google_client_secret, strip_alarms`
+	oauth_refresh_token, google_client_id, google_client_secret, strip_alarms, next_sync_at, attendee_rewrite, uid_prefix, result_webhook_url, max_requests_per_second,
+	custom_headers, verify_writes, include_categories, target_timezone, last_success_at, sync_journals, dest_read_only, sync_attachments, last_error_detail, generate_missing_uids,
+	alert_email_enabled, alert_webhook_enabled, clock_skew_seconds, skip_cancelled, minimize_timezones, tags, preserve_dest_props, text_rules, source_stamp, ca_bundle, skip_invited_events, account_email, max_sync_duration_secs, proxy_url, initial_sync_event_cap`
 
 // GetSourceByID returns a source by its ID.
 func (db *DB) GetSourceByID(id string) (*Source, error) {
@@ -224,6 +255,99 @@ func (db *DB) GetEnabledSources() ([]*Source, error) {
 	return sources, nil
 }
 
+// GetSourcesDueForSync returns enabled sources whose persisted
+// next_sync_at is at or before now, most overdue first. Sources that
+// have never been scheduled (NULL next_sync_at) are always due and
+// sort ahead of the rest. Backed by idx_sources_enabled_next_sync_at.
+func (db *DB) GetSourcesDueForSync(now time.Time) ([]*Source, error) {
+	query := `SELECT ` + sourceSelectColumns + ` FROM sources
+		WHERE enabled = 1 AND (next_sync_at IS NULL OR next_sync_at <= ?)
+		ORDER BY next_sync_at`
+
+	rows, err := db.conn.Query(query, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due sources: %w", err)
+	}
+	defer rows.Close()
+
+	var sources []*Source
+	for rows.Next() {
+		source, err := scanSourceFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sources: %w", err)
+	}
+
+	return sources, nil
+}
+
+// UpdateSourceNextSyncAt persists when the source is next due to sync.
+// Deliberately leaves updated_at alone — this is scheduler bookkeeping,
+// not a user edit.
+func (db *DB) UpdateSourceNextSyncAt(sourceID string, nextSyncAt time.Time) error {
+	query := `UPDATE sources SET next_sync_at = ? WHERE id = ?`
+	result, err := db.conn.Exec(query, nextSyncAt.UTC(), sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to update source next sync time: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// UpdateSourceLastErrorDetail records why the source's last sync
+// failed. UpdateSourceSyncStatus clears it again on the next success.
+// Like UpdateSourceNextSyncAt it leaves updated_at alone.
+func (db *DB) UpdateSourceLastErrorDetail(sourceID, detail string) error {
+	query := `UPDATE sources SET last_error_detail = ? WHERE id = ?`
+	result, err := db.conn.Exec(query, detail, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to update source last error detail: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// UpdateSourceClockSkew stores the measured clock offset between a
+// source's servers; see Source.ClockSkewSeconds.
+func (db *DB) UpdateSourceClockSkew(sourceID string, seconds int) error {
+	query := `UPDATE sources SET clock_skew_seconds = ? WHERE id = ?`
+	result, err := db.conn.Exec(query, seconds, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to update source clock skew: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
 // UpdateSource updates an existing source.
 func (db *DB) UpdateSource(source *Source) error {
 	source.UpdatedAt = time.Now().UTC()
@@ -269,6 +393,32 @@ func (db *DB) UpdateSource(source *Source) error {
 		googleClientSecret = &t
 	}
 
+	// Encode attendee_rewrite as JSON (NULL when empty)
+	attendeeRewriteJSON, err := encodeStringMap(source.AttendeeRewrite, "attendee rewrite")
+	if err != nil {
+		return err
+	}
+	customHeadersJSON, err := encodeStringMap(source.CustomHeaders, "custom headers")
+	if err != nil {
+		return err
+	}
+	includeCategoriesJSON, err := encodeStringList(source.IncludeCategories, "include categories")
+	if err != nil {
+		return err
+	}
+	tagsJSON, err := encodeStringList(source.Tags, "tags")
+	if err != nil {
+		return err
+	}
+	preserveDestPropsJSON, err := encodeStringList(source.PreserveDestProps, "preserved destination properties")
+	if err != nil {
+		return err
+	}
+	textRulesJSON, err := encodeTextRules(source.TextRules)
+	if err != nil {
+		return err
+	}
+
 	query := `UPDATE sources SET
 		name = ?, source_type = ?, source_url = ?, source_username = ?, source_password = ?,
 		dest_url = ?, dest_username = ?, dest_password = ?, sync_interval = ?, sync_days_past = ?,
@@ -276,8 +426,8 @@ func (db *DB) UpdateSource(source *Source) error {
 		oauth_refresh_token = COALESCE(?, oauth_refresh_token),
 		google_client_id = COALESCE(?, google_client_id),
 		google_client_secret = COALESCE(?, google_client_secret),
-		strip_alarms = ?,
-		updated_at = ?
+		strip_alarms = ?, attendee_rewrite = ?, uid_prefix = ?, result_webhook_url = ?,
+		max_requests_per_second = ?, custom_headers = ?, verify_writes = ?, include_categories = ?, target_timezone = ?, sync_journals = ?, dest_read_only = ?, sync_attachments = ?, generate_missing_uids = ?, skip_cancelled = ?, minimize_timezones = ?, tags = ?, preserve_dest_props = ?, text_rules = ?, source_stamp = ?, ca_bundle = ?, skip_invited_events = ?, account_email = ?, max_sync_duration_secs = ?, proxy_url = ?, initial_sync_event_cap = ?, alert_email_enabled = ?, alert_webhook_enabled = ?, updated_at = ?
 		WHERE id = ?`
 
 	result, err := db.conn.Exec(query,
@@ -285,8 +435,8 @@ func (db *DB) UpdateSource(source *Source) error {
 		source.DestURL, source.DestUsername, source.DestPassword, source.SyncInterval, source.SyncDaysPast,
 		source.SyncDirection, source.ConflictStrategy, selectedCalendarsJSON, source.Enabled,
 		oauthRefreshToken, googleClientID, googleClientSecret,
-		source.StripAlarms,
-		source.UpdatedAt, source.ID,
+		source.StripAlarms, attendeeRewriteJSON, source.UIDPrefix, source.ResultWebhookURL,
+		source.MaxRequestsPerSecond, customHeadersJSON, source.VerifyWrites, includeCategoriesJSON, source.TargetTimezone, source.SyncJournals, source.DestReadOnly, source.SyncAttachments, source.GenerateMissingUIDs, source.SkipCancelled, source.MinimizeTimezones, tagsJSON, preserveDestPropsJSON, textRulesJSON, source.SourceStamp, source.CABundle, source.SkipInvitedEvents, source.AccountEmail, source.MaxSyncDurationSecs, source.ProxyURL, source.InitialSyncEventCap, source.AlertEmailEnabled, source.AlertWebhookEnabled, source.UpdatedAt, source.ID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update source: %w", err)
@@ -303,12 +453,18 @@ func (db *DB) UpdateSource(source *Source) error {
 	return nil
 }
 
-// UpdateSourceSyncStatus updates the sync status of a source.
+// UpdateSourceSyncStatus updates the sync status of a source. A success
+// or partial status also moves last_success_at forward and clears
+// last_error_detail; other statuses leave both alone.
 func (db *DB) UpdateSourceSyncStatus(id string, status SyncStatus, message string) error {
 	now := time.Now().UTC()
-	query := `UPDATE sources SET last_sync_at = ?, last_sync_status = ?, last_sync_message = ?, updated_at = ? WHERE id = ?`
+	query := `UPDATE sources SET last_sync_at = ?, last_sync_status = ?, last_sync_message = ?, updated_at = ?,
+		last_success_at = CASE WHEN ? THEN ? ELSE last_success_at END,
+		last_error_detail = CASE WHEN ? THEN '' ELSE last_error_detail END
+		WHERE id = ?`
 
-	result, err := db.conn.Exec(query, now, status, message, now, id)
+	succeeded := status == SyncStatusSuccess || status == SyncStatusPartial
+	result, err := db.conn.Exec(query, now, status, message, now, succeeded, now, succeeded, id)
 	if err != nil {
 		return fmt.Errorf("failed to update source sync status: %w", err)
 	}
@@ -365,14 +521,14 @@ func (db *DB) DeleteSource(id string) error {
 
 // GetSyncState returns the sync state for a source and calendar.
 func (db *DB) GetSyncState(sourceID, calendarHref string) (*SyncState, error) {
-	query := `SELECT id, source_id, calendar_href, sync_token, ctag, updated_at
+	query := `SELECT id, source_id, calendar_href, sync_token, ctag, last_modified, updated_at
 		FROM sync_states WHERE source_id = ? AND calendar_href = ?`
 
 	row := db.conn.QueryRow(query, sourceID, calendarHref)
 
 	state := &SyncState{}
-	var syncToken, ctag sql.NullString
-	err := row.Scan(&state.ID, &state.SourceID, &state.CalendarHref, &syncToken, &ctag, &state.UpdatedAt)
+	var syncToken, ctag, lastModified sql.NullString
+	err := row.Scan(&state.ID, &state.SourceID, &state.CalendarHref, &syncToken, &ctag, &lastModified, &state.UpdatedAt)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrNotFound
 	}
@@ -382,19 +538,55 @@ func (db *DB) GetSyncState(sourceID, calendarHref string) (*SyncState, error) {
 
 	state.SyncToken = syncToken.String
 	state.CTag = ctag.String
+	state.LastModified = lastModified.String
 
 	return state, nil
 }
 
+// GetSyncStateCalendars returns the hrefs of every calendar of a source
+// that has a sync state, i.e. that has synced cleanly at least once.
+func (db *DB) GetSyncStateCalendars(sourceID string) ([]string, error) {
+	rows, err := db.conn.Query(`SELECT calendar_href FROM sync_states WHERE source_id = ? ORDER BY calendar_href`, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync state calendars: %w", err)
+	}
+	defer rows.Close()
+
+	var hrefs []string
+	for rows.Next() {
+		var href string
+		if err := rows.Scan(&href); err != nil {
+			return nil, fmt.Errorf("failed to scan sync state calendar: %w", err)
+		}
+		hrefs = append(hrefs, href)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sync state calendars: %w", err)
+	}
+
+	return hrefs, nil
+}
+
+// DeleteSyncState removes the sync state of a source's calendar.
+func (db *DB) DeleteSyncState(sourceID, calendarHref string) error {
+	_, err := db.conn.Exec(`DELETE FROM sync_states WHERE source_id = ? AND calendar_href = ?`, sourceID, calendarHref)
+	if err != nil {
+		return fmt.Errorf("failed to delete sync state: %w", err)
+	}
+
+	return nil
+}
+
 // UpsertSyncState creates or updates a sync state.
 func (db *DB) UpsertSyncState(state *SyncState) error {
 	now := time.Now().UTC()
 
 	// Try to update first
-	query := `UPDATE sync_states SET sync_token = ?, ctag = ?, updated_at = ?
+	query := `UPDATE sync_states SET sync_token = ?, ctag = ?, last_modified = ?, updated_at = ?
 		WHERE source_id = ? AND calendar_href = ?`
 
-	result, err := db.conn.Exec(query, state.SyncToken, state.CTag, now, state.SourceID, state.CalendarHref)
+	result, err := db.conn.Exec(query, state.SyncToken, state.CTag, state.LastModified, now, state.SourceID, state.CalendarHref)
 	if err != nil {
 		return fmt.Errorf("failed to update sync state: %w", err)
 	}
@@ -411,10 +603,10 @@ func (db *DB) UpsertSyncState(state *SyncState) error {
 		}
 		state.UpdatedAt = now
 
-		insertQuery := `INSERT INTO sync_states (id, source_id, calendar_href, sync_token, ctag, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?)`
+		insertQuery := `INSERT INTO sync_states (id, source_id, calendar_href, sync_token, ctag, last_modified, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`
 
-		_, err = db.conn.Exec(insertQuery, state.ID, state.SourceID, state.CalendarHref, state.SyncToken, state.CTag, state.UpdatedAt)
+		_, err = db.conn.Exec(insertQuery, state.ID, state.SourceID, state.CalendarHref, state.SyncToken, state.CTag, state.LastModified, state.UpdatedAt)
 		if err != nil {
 			return fmt.Errorf("failed to insert sync state: %w", err)
 		}
@@ -475,6 +667,27 @@ func (db *DB) GetSyncLogs(sourceID string, limit int) ([]*SyncLog, error) {
 	return logs, nil
 }
 
+// GetSyncLogByID returns a single sync log by ID.
+func (db *DB) GetSyncLogByID(id string) (*SyncLog, error) {
+	query := `SELECT id, source_id, status, message, details, duration_ms,
+		events_created, events_updated, events_deleted, events_skipped, calendars_synced, events_processed, created_at
+		FROM sync_logs WHERE id = ?`
+
+	log := &SyncLog{}
+	var durationMs int64
+	err := db.conn.QueryRow(query, id).Scan(&log.ID, &log.SourceID, &log.Status, &log.Message, &log.Details, &durationMs,
+		&log.EventsCreated, &log.EventsUpdated, &log.EventsDeleted, &log.EventsSkipped, &log.CalendarsSynced, &log.EventsProcessed, &log.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync log: %w", err)
+	}
+	log.Duration = time.Duration(durationMs) * time.Millisecond
+
+	return log, nil
+}
+
 // CleanOldSyncLogs deletes sync logs older than the given time.
 func (db *DB) CleanOldSyncLogs(olderThan time.Time) (int64, error) {
 	query := `DELETE FROM sync_logs WHERE created_at < ?`
@@ -492,6 +705,109 @@ func (db *DB) CleanOldSyncLogs(olderThan time.Time) (int64, error) {
 	return affected, nil
 }
 
+// DeleteSyncLogsForSource deletes every sync log of a source and
+// returns how many were removed.
+func (db *DB) DeleteSyncLogsForSource(sourceID string) (int64, error) {
+	result, err := db.conn.Exec(`DELETE FROM sync_logs WHERE source_id = ?`, sourceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete sync logs: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return affected, nil
+}
+
+// GetSyncOutcomeCounts tallies the sync runs logged since the given
+// time, per source ID. Failed counts error and auth_error runs; partial
+// runs completed and are not failures.
+func (db *DB) GetSyncOutcomeCounts(since time.Time) (map[string]SyncOutcomeCounts, error) {
+	query := `SELECT source_id, COUNT(*),
+		SUM(CASE WHEN status IN (?, ?) THEN 1 ELSE 0 END)
+		FROM sync_logs WHERE created_at >= ? GROUP BY source_id`
+
+	rows, err := db.conn.Query(query, SyncStatusError, SyncStatusAuthError, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sync outcomes: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]SyncOutcomeCounts)
+	for rows.Next() {
+		var sourceID string
+		var c SyncOutcomeCounts
+		if err := rows.Scan(&sourceID, &c.Total, &c.Failed); err != nil {
+			return nil, fmt.Errorf("failed to scan sync outcomes: %w", err)
+		}
+		counts[sourceID] = c
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sync outcomes: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetSyncOverview aggregates userID's sources, and their sync runs
+// logged since weekStart, in two queries. today splits out the runs
+// and events of the current day. StaleSources is left for the caller,
+// which knows which sources are stale.
+func (db *DB) GetSyncOverview(userID string, today, weekStart time.Time) (*SyncOverview, error) {
+	overview := &SyncOverview{}
+
+	row := db.conn.QueryRow(`SELECT COUNT(*),
+		COALESCE(SUM(CASE WHEN enabled = 1 THEN 1 ELSE 0 END), 0),
+		COALESCE(SUM(CASE WHEN last_sync_status IN (?, ?) THEN 1 ELSE 0 END), 0)
+		FROM sources WHERE user_id = ?`,
+		SyncStatusError, SyncStatusAuthError, userID)
+	if err := row.Scan(&overview.TotalSources, &overview.ActiveSources, &overview.FailingSources); err != nil {
+		return nil, fmt.Errorf("failed to count sources: %w", err)
+	}
+	overview.PausedSources = overview.TotalSources - overview.ActiveSources
+
+	var failed int
+	row = db.conn.QueryRow(`SELECT COUNT(*),
+		COALESCE(SUM(CASE WHEN l.status IN (?, ?) THEN 1 ELSE 0 END), 0),
+		COALESCE(SUM(CASE WHEN l.created_at >= ? THEN 1 ELSE 0 END), 0),
+		COALESCE(SUM(l.events_created + l.events_updated + l.events_deleted), 0),
+		COALESCE(SUM(CASE WHEN l.created_at >= ? THEN l.events_created + l.events_updated + l.events_deleted ELSE 0 END), 0)
+		FROM sync_logs l JOIN sources s ON s.id = l.source_id
+		WHERE s.user_id = ? AND l.created_at >= ?`,
+		SyncStatusError, SyncStatusAuthError, today.UTC(), today.UTC(), userID, weekStart.UTC())
+	if err := row.Scan(&overview.SyncsWeek, &failed, &overview.SyncsToday, &overview.EventsWeek, &overview.EventsToday); err != nil {
+		return nil, fmt.Errorf("failed to aggregate sync logs: %w", err)
+	}
+	if overview.SyncsWeek > 0 {
+		overview.SuccessRate = float64(overview.SyncsWeek-failed) / float64(overview.SyncsWeek) * 100
+	}
+
+	return overview, nil
+}
+
+// CountUserSourcesIn returns how many of ids are sources owned by
+// userID.
+func (db *DB) CountUserSourcesIn(userID string, ids []string) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	args := make([]any, 0, len(ids)+1)
+	args = append(args, userID)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	query := `SELECT COUNT(*) FROM sources WHERE user_id = ? AND id IN (?` + strings.Repeat(", ?", len(ids)-1) + `)`
+
+	var count int
+	if err := db.conn.QueryRow(query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count sources: %w", err)
+	}
+	return count, nil
+}
+
 // GetSourceStats returns per-source statistics including event count,
 // malformed count, recent sync history, success rate, and health
 // score for the dashboard. (#136)
@@ -551,7 +867,7 @@ func (db *DB) GetSourceStats(sourceID string) (*SourceStats, error) {
 	// Warning frequency: count partial/error in recent syncs
 	warningCount := 0
 	for _, s := range stats.RecentSyncs {
-		if s.Status == SyncStatusPartial || s.Status == SyncStatusError {
+		if s.Status == SyncStatusPartial || s.Status == SyncStatusError || s.Status == SyncStatusAuthError {
 			warningCount++
 		}
 	}
@@ -619,23 +935,388 @@ func (db *DB) GetDestinationsBySourceID(sourceID string) ([]*Destination, error)
 		}
 		dests = append(dests, &d)
 	}
-	return dests, nil
+	return dests, nil
+}
+
+// DeleteDestination removes a destination by ID. (#154)
+func (db *DB) DeleteDestination(id string) error {
+	result, err := db.conn.Exec(`DELETE FROM destinations WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete destination: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// CreateAPIToken stores a new API token. The caller supplies the hash;
+// ID and CreatedAt are filled in here.
+func (db *DB) CreateAPIToken(token *APIToken) error {
+	token.ID = uuid.New().String()
+	token.CreatedAt = time.Now().UTC()
+	query := `INSERT INTO api_tokens (id, user_id, token_hash, name, created_at)
+		VALUES (?, ?, ?, ?, ?)`
+	_, err := db.conn.Exec(query, token.ID, token.UserID, token.TokenHash, token.Name, token.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create API token: %w", err)
+	}
+	return nil
+}
+
+// GetAPITokensByUserID returns a user's API tokens, newest first.
+func (db *DB) GetAPITokensByUserID(userID string) ([]*APIToken, error) {
+	rows, err := db.conn.Query(
+		`SELECT id, user_id, token_hash, name, created_at, last_used_at
+		 FROM api_tokens WHERE user_id = ? ORDER BY created_at DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []*APIToken
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate API tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// GetAPITokenByHash looks up a token by the hash of its plaintext.
+func (db *DB) GetAPITokenByHash(tokenHash string) (*APIToken, error) {
+	row := db.conn.QueryRow(
+		`SELECT id, user_id, token_hash, name, created_at, last_used_at
+		 FROM api_tokens WHERE token_hash = ?`,
+		tokenHash,
+	)
+	token, err := scanAPIToken(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// scanAPIToken scans one api_tokens row from a *sql.Row or *sql.Rows.
+func scanAPIToken(row interface{ Scan(...any) error }) (*APIToken, error) {
+	var token APIToken
+	var lastUsedAt sql.NullTime
+	if err := row.Scan(&token.ID, &token.UserID, &token.TokenHash, &token.Name, &token.CreatedAt, &lastUsedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan API token: %w", err)
+	}
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	return &token, nil
+}
+
+// TouchAPIToken records that a token was just used.
+func (db *DB) TouchAPIToken(id string) error {
+	if _, err := db.conn.Exec(`UPDATE api_tokens SET last_used_at = ? WHERE id = ?`, time.Now().UTC(), id); err != nil {
+		return fmt.Errorf("failed to update API token last use: %w", err)
+	}
+	return nil
+}
+
+// DeleteAPIToken revokes a token. The user ID scopes the delete so one
+// user can't revoke another's token by guessing its ID.
+func (db *DB) DeleteAPIToken(id, userID string) error {
+	result, err := db.conn.Exec(`DELETE FROM api_tokens WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete API token: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// CreateShareLink stores a new share link. ID and CreatedAt are filled
+// in here; the caller sets SourceID and ExpiresAt.
+func (db *DB) CreateShareLink(link *ShareLink) error {
+	link.ID = uuid.New().String()
+	link.CreatedAt = time.Now().UTC()
+	link.ExpiresAt = link.ExpiresAt.UTC().Truncate(time.Second)
+	_, err := db.conn.Exec(`INSERT INTO share_links (id, source_id, expires_at, created_at) VALUES (?, ?, ?, ?)`,
+		link.ID, link.SourceID, link.ExpiresAt, link.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create share link: %w", err)
+	}
+	return nil
+}
+
+// GetShareLinkByID returns a share link, or ErrNotFound if it was
+// revoked or never existed.
+func (db *DB) GetShareLinkByID(id string) (*ShareLink, error) {
+	link := &ShareLink{}
+	err := db.conn.QueryRow(`SELECT id, source_id, expires_at, created_at FROM share_links WHERE id = ?`, id).
+		Scan(&link.ID, &link.SourceID, &link.ExpiresAt, &link.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share link: %w", err)
+	}
+	return link, nil
+}
+
+// GetShareLinksBySourceID returns a source's unexpired share links,
+// newest first.
+func (db *DB) GetShareLinksBySourceID(sourceID string) ([]*ShareLink, error) {
+	rows, err := db.conn.Query(
+		`SELECT id, source_id, expires_at, created_at FROM share_links
+		 WHERE source_id = ? AND expires_at > ? ORDER BY created_at DESC`,
+		sourceID, time.Now().UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query share links: %w", err)
+	}
+	defer rows.Close()
+
+	var links []*ShareLink
+	for rows.Next() {
+		link := &ShareLink{}
+		if err := rows.Scan(&link.ID, &link.SourceID, &link.ExpiresAt, &link.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan share link: %w", err)
+		}
+		links = append(links, link)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate share links: %w", err)
+	}
+	return links, nil
+}
+
+// DeleteShareLink revokes a share link belonging to sourceID.
+func (db *DB) DeleteShareLink(id, sourceID string) error {
+	result, err := db.conn.Exec(`DELETE FROM share_links WHERE id = ? AND source_id = ?`, id, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to delete share link: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// CreateDeletedEventBackup stores a copy of a deleted event. ID and
+// CreatedAt are filled in here; the caller sets everything else,
+// including ExpiresAt.
+func (db *DB) CreateDeletedEventBackup(b *DeletedEventBackup) error {
+	b.ID = uuid.New().String()
+	b.CreatedAt = time.Now().UTC()
+	b.ExpiresAt = b.ExpiresAt.UTC()
+	_, err := db.conn.Exec(
+		`INSERT INTO deleted_event_backups (id, source_id, batch_id, calendar_href, dest_url, dest_calendar_path,
+			event_path, event_key, data, created_at, expires_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		b.ID, b.SourceID, b.BatchID, b.CalendarHref, b.DestURL, b.DestCalendarPath,
+		b.EventPath, b.EventKey, b.Data, b.CreatedAt, b.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create deleted event backup: %w", err)
+	}
+	return nil
+}
+
+// GetLatestDeletedEventBackups returns the unexpired backups written
+// by the most recent sync run of a source that deleted anything. Empty
+// when there is nothing to restore.
+func (db *DB) GetLatestDeletedEventBackups(sourceID string) ([]*DeletedEventBackup, error) {
+	now := time.Now().UTC()
+	rows, err := db.conn.Query(
+		`SELECT id, source_id, batch_id, calendar_href, dest_url, dest_calendar_path, event_path, event_key, data, created_at, expires_at
+		 FROM deleted_event_backups
+		 WHERE source_id = ? AND expires_at > ? AND batch_id = (
+			SELECT batch_id FROM deleted_event_backups
+			WHERE source_id = ? AND expires_at > ?
+			ORDER BY created_at DESC, rowid DESC LIMIT 1
+		 )
+		 ORDER BY created_at, rowid`,
+		sourceID, now, sourceID, now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deleted event backups: %w", err)
+	}
+	defer rows.Close()
+
+	var backups []*DeletedEventBackup
+	for rows.Next() {
+		b := &DeletedEventBackup{}
+		if err := rows.Scan(&b.ID, &b.SourceID, &b.BatchID, &b.CalendarHref, &b.DestURL, &b.DestCalendarPath,
+			&b.EventPath, &b.EventKey, &b.Data, &b.CreatedAt, &b.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan deleted event backup: %w", err)
+		}
+		backups = append(backups, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate deleted event backups: %w", err)
+	}
+	return backups, nil
+}
+
+// DeleteDeletedEventBackup removes one backup, typically after it has
+// been restored.
+func (db *DB) DeleteDeletedEventBackup(id string) error {
+	if _, err := db.conn.Exec(`DELETE FROM deleted_event_backups WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete deleted event backup: %w", err)
+	}
+	return nil
+}
+
+// CleanExpiredDeletedEventBackups deletes the backups whose TTL has
+// passed as of now.
+func (db *DB) CleanExpiredDeletedEventBackups(now time.Time) (int64, error) {
+	result, err := db.conn.Exec(`DELETE FROM deleted_event_backups WHERE expires_at <= ?`, now.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to clean expired deleted event backups: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return affected, nil
+}
+
+// RecordEventFailure counts one more consecutive failed write of an
+// event and stores its error. The event is dead-lettered once its
+// count reaches deadLetterAfter; values <= 0 never dead-letter.
+func (db *DB) RecordEventFailure(sourceID, calendarHref, eventUID, lastError string, deadLetterAfter int) (*FailedEvent, error) {
+	now := time.Now().UTC()
+	f := &FailedEvent{SourceID: sourceID, CalendarHref: calendarHref, EventUID: eventUID, LastError: lastError}
+	err := db.conn.QueryRow(`INSERT INTO failed_events (source_id, calendar_href, event_uid, failure_count, last_error,
+			dead_lettered, first_failed_at, updated_at)
+		VALUES (?, ?, ?, 1, ?, ? AND 1 >= ?, ?, ?)
+		ON CONFLICT(source_id, calendar_href, event_uid)
+		DO UPDATE SET failure_count = failure_count + 1, last_error = excluded.last_error,
+			dead_lettered = ? AND failure_count + 1 >= ?, updated_at = excluded.updated_at
+		RETURNING failure_count, dead_lettered, first_failed_at, updated_at`,
+		sourceID, calendarHref, eventUID, lastError, deadLetterAfter > 0, deadLetterAfter, now, now,
+		deadLetterAfter > 0, deadLetterAfter,
+	).Scan(&f.FailureCount, &f.DeadLettered, &f.FirstFailedAt, &f.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record event failure: %w", err)
+	}
+	return f, nil
+}
+
+// GetFailedEvents returns a source's failing events for one calendar,
+// keyed by event UID.
+func (db *DB) GetFailedEvents(sourceID, calendarHref string) (map[string]*FailedEvent, error) {
+	rows, err := db.conn.Query(`SELECT source_id, calendar_href, event_uid, failure_count, last_error, dead_lettered, first_failed_at, updated_at
+		FROM failed_events WHERE source_id = ? AND calendar_href = ?`, sourceID, calendarHref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query failed events: %w", err)
+	}
+	defer rows.Close()
+
+	failed := make(map[string]*FailedEvent)
+	for rows.Next() {
+		f, err := scanFailedEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		failed[f.EventUID] = f
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate failed events: %w", err)
+	}
+	return failed, nil
+}
+
+// GetDeadLetteredEvents returns a source's dead-lettered events, most
+// recently failed first.
+func (db *DB) GetDeadLetteredEvents(sourceID string) ([]*FailedEvent, error) {
+	rows, err := db.conn.Query(`SELECT source_id, calendar_href, event_uid, failure_count, last_error, dead_lettered, first_failed_at, updated_at
+		FROM failed_events WHERE source_id = ? AND dead_lettered = 1
+		ORDER BY updated_at DESC, calendar_href, event_uid`, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dead-lettered events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*FailedEvent
+	for rows.Next() {
+		f, err := scanFailedEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate dead-lettered events: %w", err)
+	}
+	return events, nil
+}
+
+func scanFailedEvent(rows *sql.Rows) (*FailedEvent, error) {
+	f := &FailedEvent{}
+	if err := rows.Scan(&f.SourceID, &f.CalendarHref, &f.EventUID, &f.FailureCount, &f.LastError,
+		&f.DeadLettered, &f.FirstFailedAt, &f.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to scan failed event: %w", err)
+	}
+	return f, nil
 }
 
-// DeleteDestination removes a destination by ID. (#154)
-func (db *DB) DeleteDestination(id string) error {
-	result, err := db.conn.Exec(`DELETE FROM destinations WHERE id = ?`, id)
+// ClearEventFailure forgets an event's failures, after it was written
+// or so the next sync retries it. Clearing an event that isn't
+// tracked is not an error.
+func (db *DB) ClearEventFailure(sourceID, calendarHref, eventUID string) error {
+	_, err := db.conn.Exec(`DELETE FROM failed_events WHERE source_id = ? AND calendar_href = ? AND event_uid = ?`,
+		sourceID, calendarHref, eventUID)
 	if err != nil {
-		return fmt.Errorf("failed to delete destination: %w", err)
+		return fmt.Errorf("failed to clear event failure: %w", err)
+	}
+	return nil
+}
+
+// RetryDeadLetteredEvents releases dead-lettered events of a source
+// so the next sync tries them again: the one event eventUID names in
+// calendarHref, or every one when eventUID is empty. It returns how
+// many were released.
+func (db *DB) RetryDeadLetteredEvents(sourceID, calendarHref, eventUID string) (int64, error) {
+	var result sql.Result
+	var err error
+	if eventUID == "" {
+		result, err = db.conn.Exec(`DELETE FROM failed_events WHERE source_id = ? AND dead_lettered = 1`, sourceID)
+	} else {
+		result, err = db.conn.Exec(`DELETE FROM failed_events
+			WHERE source_id = ? AND calendar_href = ? AND event_uid = ? AND dead_lettered = 1`,
+			sourceID, calendarHref, eventUID)
 	}
-	affected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return 0, fmt.Errorf("failed to retry dead-lettered events: %w", err)
 	}
-	if affected == 0 {
-		return ErrNotFound
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
 	}
-	return nil
+	return affected, nil
 }
 
 // CreateAuditLog inserts an audit log entry. (#152)
@@ -713,9 +1394,37 @@ func (db *DB) GetSyncLogStats() (count int64, oldest time.Time, err error) {
 	return count, oldest, nil
 }
 
+// UpdateSourceSelectedCalendars rewrites only a source's
+// selected_calendars, leaving the rest of the row alone. Used by the
+// sync engine to record healed calendar paths mid-sync.
+func (db *DB) UpdateSourceSelectedCalendars(sourceID string, calendars []CalendarConfig) error {
+	var selectedCalendarsJSON *string
+	if len(calendars) > 0 {
+		data, err := json.Marshal(calendars)
+		if err != nil {
+			return fmt.Errorf("failed to encode selected calendars: %w", err)
+		}
+		s := string(data)
+		selectedCalendarsJSON = &s
+	}
+	result, err := db.conn.Exec(`UPDATE sources SET selected_calendars = ?, updated_at = ? WHERE id = ?`,
+		selectedCalendarsJSON, time.Now().UTC(), sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to update selected calendars: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 // parseSelectedCalendars parses selected_calendars JSON with backward compatibility.
 // Old format: ["path1", "path2"] (array of strings)
-// New format: [{"path": "path1", "sync_direction": "one_way"}] (array of CalendarConfig)
+// New format: [{"path": "path1", "name": "Work", "sync_direction": "one_way"}] (array of CalendarConfig)
 func parseSelectedCalendars(jsonStr string) []CalendarConfig {
 	if jsonStr == "" {
 		return nil
@@ -745,6 +1454,90 @@ func parseSelectedCalendars(jsonStr string) []CalendarConfig {
 	return nil
 }
 
+// encodeStringMap serializes a string map for a JSON text column
+// (attendee_rewrite, custom_headers). An empty map is stored as NULL;
+// what names the column in the error.
+func encodeStringMap(m map[string]string, what string) (*string, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", what, err)
+	}
+	s := string(data)
+	return &s, nil
+}
+
+// parseStringMap decodes a column written by encodeStringMap. Malformed
+// JSON yields nil (no rewriting, no extra headers) rather than failing
+// the whole scan.
+func parseStringMap(jsonStr string) map[string]string {
+	if jsonStr == "" {
+		return nil
+	}
+	var m map[string]string
+	if err := json.Unmarshal([]byte(jsonStr), &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+// encodeStringList serializes a string slice for a JSON text column
+// (include_categories, tags, preserve_dest_props). An empty slice is
+// stored as NULL.
+func encodeStringList(list []string, what string) (*string, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", what, err)
+	}
+	s := string(data)
+	return &s, nil
+}
+
+// parseStringList decodes a column written by encodeStringList.
+// Malformed JSON yields nil, which disables the filter it backs.
+func parseStringList(jsonStr string) []string {
+	if jsonStr == "" {
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal([]byte(jsonStr), &list); err != nil {
+		return nil
+	}
+	return list
+}
+
+// encodeTextRules serializes a source's text rules for the text_rules
+// column. No rules are stored as NULL.
+func encodeTextRules(rules []TextRule) (*string, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode text rules: %w", err)
+	}
+	s := string(data)
+	return &s, nil
+}
+
+// parseTextRules decodes a column written by encodeTextRules.
+// Malformed JSON yields nil, so no text is rewritten.
+func parseTextRules(jsonStr string) []TextRule {
+	if jsonStr == "" {
+		return nil
+	}
+	var rules []TextRule
+	if err := json.Unmarshal([]byte(jsonStr), &rules); err != nil {
+		return nil
+	}
+	return rules
+}
+
 // scanSource scans a single row into a Source struct.
 func scanSource(row *sql.Row) (*Source, error) {
 	source := &Source{}
@@ -755,6 +1548,15 @@ func scanSource(row *sql.Row) (*Source, error) {
 	var oauthRefreshToken sql.NullString
 	var googleClientID sql.NullString
 	var googleClientSecret sql.NullString
+	var nextSyncAt sql.NullTime
+	var attendeeRewriteJSON sql.NullString
+	var customHeadersJSON sql.NullString
+	var includeCategoriesJSON sql.NullString
+	var tagsJSON sql.NullString
+	var preserveDestPropsJSON sql.NullString
+	var textRulesJSON sql.NullString
+	var lastSuccessAt sql.NullTime
+	var alertEmailEnabled, alertWebhookEnabled sql.NullBool
 
 	err := row.Scan(
 		&source.ID, &source.UserID, &source.Name, &source.SourceType,
@@ -765,6 +1567,11 @@ func scanSource(row *sql.Row) (*Source, error) {
 		&lastSyncAt, &source.LastSyncStatus, &lastSyncMessage,
 		&source.CreatedAt, &source.UpdatedAt,
 		&oauthRefreshToken, &googleClientID, &googleClientSecret, &source.StripAlarms,
+		&nextSyncAt, &attendeeRewriteJSON, &source.UIDPrefix, &source.ResultWebhookURL, &source.MaxRequestsPerSecond,
+		&customHeadersJSON, &source.VerifyWrites, &includeCategoriesJSON, &source.TargetTimezone,
+		&lastSuccessAt, &source.SyncJournals, &source.DestReadOnly, &source.SyncAttachments, &source.LastErrorDetail, &source.GenerateMissingUIDs,
+		&alertEmailEnabled, &alertWebhookEnabled, &source.ClockSkewSeconds, &source.SkipCancelled, &source.MinimizeTimezones, &tagsJSON, &preserveDestPropsJSON,
+		&textRulesJSON, &source.SourceStamp, &source.CABundle, &source.SkipInvitedEvents, &source.AccountEmail, &source.MaxSyncDurationSecs, &source.ProxyURL, &source.InitialSyncEventCap,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrNotFound
@@ -790,6 +1597,36 @@ func scanSource(row *sql.Row) (*Source, error) {
 	if googleClientSecret.Valid {
 		source.GoogleClientSecret = googleClientSecret.String
 	}
+	if nextSyncAt.Valid {
+		source.NextSyncAt = &nextSyncAt.Time
+	}
+	if lastSuccessAt.Valid {
+		source.LastSuccessAt = &lastSuccessAt.Time
+	}
+	if alertEmailEnabled.Valid {
+		source.AlertEmailEnabled = &alertEmailEnabled.Bool
+	}
+	if alertWebhookEnabled.Valid {
+		source.AlertWebhookEnabled = &alertWebhookEnabled.Bool
+	}
+	if attendeeRewriteJSON.Valid {
+		source.AttendeeRewrite = parseStringMap(attendeeRewriteJSON.String)
+	}
+	if customHeadersJSON.Valid {
+		source.CustomHeaders = parseStringMap(customHeadersJSON.String)
+	}
+	if includeCategoriesJSON.Valid {
+		source.IncludeCategories = parseStringList(includeCategoriesJSON.String)
+	}
+	if tagsJSON.Valid {
+		source.Tags = parseStringList(tagsJSON.String)
+	}
+	if preserveDestPropsJSON.Valid {
+		source.PreserveDestProps = parseStringList(preserveDestPropsJSON.String)
+	}
+	if textRulesJSON.Valid {
+		source.TextRules = parseTextRules(textRulesJSON.String)
+	}
 
 	// Decode selected_calendars from JSON (backward compatible)
 	if selectedCalendarsJSON.Valid {
@@ -809,6 +1646,15 @@ func scanSourceFromRows(rows *sql.Rows) (*Source, error) {
 	var oauthRefreshToken sql.NullString
 	var googleClientID sql.NullString
 	var googleClientSecret sql.NullString
+	var nextSyncAt sql.NullTime
+	var attendeeRewriteJSON sql.NullString
+	var customHeadersJSON sql.NullString
+	var includeCategoriesJSON sql.NullString
+	var tagsJSON sql.NullString
+	var preserveDestPropsJSON sql.NullString
+	var textRulesJSON sql.NullString
+	var lastSuccessAt sql.NullTime
+	var alertEmailEnabled, alertWebhookEnabled sql.NullBool
 
 	err := rows.Scan(
 		&source.ID, &source.UserID, &source.Name, &source.SourceType,
@@ -819,6 +1665,11 @@ func scanSourceFromRows(rows *sql.Rows) (*Source, error) {
 		&lastSyncAt, &source.LastSyncStatus, &lastSyncMessage,
 		&source.CreatedAt, &source.UpdatedAt,
 		&oauthRefreshToken, &googleClientID, &googleClientSecret, &source.StripAlarms,
+		&nextSyncAt, &attendeeRewriteJSON, &source.UIDPrefix, &source.ResultWebhookURL, &source.MaxRequestsPerSecond,
+		&customHeadersJSON, &source.VerifyWrites, &includeCategoriesJSON, &source.TargetTimezone,
+		&lastSuccessAt, &source.SyncJournals, &source.DestReadOnly, &source.SyncAttachments, &source.LastErrorDetail, &source.GenerateMissingUIDs,
+		&alertEmailEnabled, &alertWebhookEnabled, &source.ClockSkewSeconds, &source.SkipCancelled, &source.MinimizeTimezones, &tagsJSON, &preserveDestPropsJSON,
+		&textRulesJSON, &source.SourceStamp, &source.CABundle, &source.SkipInvitedEvents, &source.AccountEmail, &source.MaxSyncDurationSecs, &source.ProxyURL, &source.InitialSyncEventCap,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan source: %w", err)
@@ -841,6 +1692,36 @@ func scanSourceFromRows(rows *sql.Rows) (*Source, error) {
 	if googleClientSecret.Valid {
 		source.GoogleClientSecret = googleClientSecret.String
 	}
+	if nextSyncAt.Valid {
+		source.NextSyncAt = &nextSyncAt.Time
+	}
+	if lastSuccessAt.Valid {
+		source.LastSuccessAt = &lastSuccessAt.Time
+	}
+	if alertEmailEnabled.Valid {
+		source.AlertEmailEnabled = &alertEmailEnabled.Bool
+	}
+	if alertWebhookEnabled.Valid {
+		source.AlertWebhookEnabled = &alertWebhookEnabled.Bool
+	}
+	if attendeeRewriteJSON.Valid {
+		source.AttendeeRewrite = parseStringMap(attendeeRewriteJSON.String)
+	}
+	if customHeadersJSON.Valid {
+		source.CustomHeaders = parseStringMap(customHeadersJSON.String)
+	}
+	if includeCategoriesJSON.Valid {
+		source.IncludeCategories = parseStringList(includeCategoriesJSON.String)
+	}
+	if tagsJSON.Valid {
+		source.Tags = parseStringList(tagsJSON.String)
+	}
+	if preserveDestPropsJSON.Valid {
+		source.PreserveDestProps = parseStringList(preserveDestPropsJSON.String)
+	}
+	if textRulesJSON.Valid {
+		source.TextRules = parseTextRules(textRulesJSON.String)
+	}
 
 	// Decode selected_calendars from JSON (backward compatible)
 	if selectedCalendarsJSON.Valid {
@@ -861,6 +1742,25 @@ func (db *DB) GetSyncedEvents(sourceID, calendarHref string) ([]*SyncedEvent, er
 	}
 	defer rows.Close()
 
+	return scanSyncedEvents(rows)
+}
+
+// GetSyncedEventsForSource returns the synced events of every calendar
+// of a source, ordered by calendar.
+func (db *DB) GetSyncedEventsForSource(sourceID string) ([]*SyncedEvent, error) {
+	query := `SELECT id, source_id, calendar_href, event_uid, source_etag, dest_etag, created_at, updated_at
+		FROM synced_events WHERE source_id = ? ORDER BY calendar_href, event_uid`
+
+	rows, err := db.conn.Query(query, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query synced events: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSyncedEvents(rows)
+}
+
+func scanSyncedEvents(rows *sql.Rows) ([]*SyncedEvent, error) {
 	var events []*SyncedEvent
 	for rows.Next() {
 		event := &SyncedEvent{}
@@ -946,16 +1846,159 @@ func (db *DB) DeleteSyncedEventsForCalendar(sourceID, calendarHref string) error
 	return nil
 }
 
-// SaveMalformedEvent saves or updates a malformed event record.
+// ResetSourceSyncState forgets everything a source's syncs recorded
+// about its calendars: the synced-event tracking and the sync states.
+// Its next sync starts over as a first sync.
+func (db *DB) ResetSourceSyncState(sourceID string) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM synced_events WHERE source_id = ?`, sourceID); err != nil {
+		return fmt.Errorf("failed to delete synced events: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM sync_states WHERE source_id = ?`, sourceID); err != nil {
+		return fmt.Errorf("failed to delete sync states: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// RecordDeletionCandidates records one more consecutive cycle in which
+// eventUIDs were missing and returns each UID's updated missing count.
+// Candidates tracked for this source, calendar, destination and target
+// that are not in eventUIDs have reappeared (or were deleted) and are
+// reset by removing their rows. Both steps run in one transaction so a
+// concurrent sync of the same calendar can't interleave them.
+func (db *DB) RecordDeletionCandidates(sourceID, calendarHref, destURL string, target DeletionTarget, eventUIDs []string) (map[string]int, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	missing := make(map[string]bool, len(eventUIDs))
+	for _, uid := range eventUIDs {
+		missing[uid] = true
+	}
+
+	rows, err := tx.Query(`SELECT event_uid FROM deletion_candidates
+		WHERE source_id = ? AND calendar_href = ? AND dest_url = ? AND target = ?`, sourceID, calendarHref, destURL, string(target))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deletion candidates: %w", err)
+	}
+	var stale []string
+	for rows.Next() {
+		var uid string
+		if err := rows.Scan(&uid); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan deletion candidate: %w", err)
+		}
+		if !missing[uid] {
+			stale = append(stale, uid)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate deletion candidates: %w", err)
+	}
+
+	for _, uid := range stale {
+		if _, err := tx.Exec(`DELETE FROM deletion_candidates
+			WHERE source_id = ? AND calendar_href = ? AND dest_url = ? AND event_uid = ? AND target = ?`,
+			sourceID, calendarHref, destURL, uid, string(target)); err != nil {
+			return nil, fmt.Errorf("failed to reset deletion candidate: %w", err)
+		}
+	}
+
+	now := time.Now().UTC()
+	counts := make(map[string]int, len(missing))
+	for uid := range missing {
+		var count int
+		err := tx.QueryRow(`INSERT INTO deletion_candidates (source_id, calendar_href, dest_url, event_uid, target, missing_count, updated_at)
+			VALUES (?, ?, ?, ?, ?, 1, ?)
+			ON CONFLICT(source_id, calendar_href, dest_url, event_uid, target)
+			DO UPDATE SET missing_count = missing_count + 1, updated_at = excluded.updated_at
+			RETURNING missing_count`,
+			sourceID, calendarHref, destURL, uid, string(target), now).Scan(&count)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record deletion candidate: %w", err)
+		}
+		counts[uid] = count
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit deletion candidates: %w", err)
+	}
+	return counts, nil
+}
+
+// malformedCategoryMarkers maps error text to a malformed event
+// category. Order matters: the first marker found wins, so the
+// specific causes come before the generic "failed to encode" and
+// "malformed" wrappers they appear inside.
+var malformedCategoryMarkers = []struct {
+	marker   string
+	category string
+}{
+	{"nil iCalendar data", MalformedCategoryEmpty},
+	{"empty iCalendar data", MalformedCategoryEmpty},
+	{"calendar is empty", MalformedCategoryEmpty},
+	{"event too large", MalformedCategoryTooLarge},
+	{"MULTIGET silently dropped", MalformedCategoryFetchFailed},
+	{`"DTSTAMP" property, got 0`, MalformedCategoryMissingDTStamp},
+	{`"UID" property, got 0`, MalformedCategoryMissingUID},
+	{"missing UID", MalformedCategoryMissingUID},
+	{"property, got 0", MalformedCategoryMissingProperty},
+	{"missing colon", MalformedCategoryMissingColon},
+	{"expected colon", MalformedCategoryMissingColon},
+	{"invalid UTF-8", MalformedCategoryInvalidEncoding},
+	{"malformed text", MalformedCategoryInvalidEncoding},
+	{"malformed param", MalformedCategoryInvalidEncoding},
+	{"contains a CR or LF", MalformedCategoryInvalidEncoding},
+	{"contains a double-quote", MalformedCategoryInvalidEncoding},
+	{"invalid duration", MalformedCategoryInvalidValue},
+	{"invalid boolean", MalformedCategoryInvalidValue},
+	{"cannot process", MalformedCategoryInvalidValue},
+	{"error parsing", MalformedCategoryInvalidValue},
+	{"expected type", MalformedCategoryInvalidValue},
+	{"malformed component", MalformedCategoryBadStructure},
+	{"invalid toplevel component", MalformedCategoryBadStructure},
+	{"nested", MalformedCategoryBadStructure},
+	{"only one of", MalformedCategoryBadStructure},
+	{"unexpected EOF", MalformedCategoryBadStructure},
+}
+
+// classifyMalformedError derives a MalformedCategory value from a
+// malformed event's error message. Messages nothing matches are
+// MalformedCategoryOther.
+func classifyMalformedError(msg string) string {
+	if strings.TrimSpace(msg) == "" {
+		return MalformedCategoryOther
+	}
+	for _, m := range malformedCategoryMarkers {
+		if strings.Contains(msg, m.marker) {
+			return m.category
+		}
+	}
+	return MalformedCategoryOther
+}
+
+// SaveMalformedEvent saves or updates a malformed event record,
+// categorizing it by classifyMalformedError.
 func (db *DB) SaveMalformedEvent(sourceID, eventPath, errorMessage string) error {
 	// Use INSERT OR REPLACE to handle the unique constraint
-	query := `INSERT OR REPLACE INTO malformed_events (id, source_id, event_path, error_message, discovered_at)
-		VALUES (?, ?, ?, ?, ?)`
+	query := `INSERT OR REPLACE INTO malformed_events (id, source_id, event_path, error_message, category, discovered_at)
+		VALUES (?, ?, ?, ?, ?, ?)`
 
 	id := uuid.New().String()
 	now := time.Now().UTC()
 
-	_, err := db.conn.Exec(query, id, sourceID, eventPath, errorMessage, now)
+	_, err := db.conn.Exec(query, id, sourceID, eventPath, errorMessage, classifyMalformedError(errorMessage), now)
 	if err != nil {
 		return fmt.Errorf("failed to save malformed event: %w", err)
 	}
@@ -965,7 +2008,7 @@ func (db *DB) SaveMalformedEvent(sourceID, eventPath, errorMessage string) error
 
 // GetMalformedEvents returns all malformed events for a user (via their sources).
 func (db *DB) GetMalformedEvents(userID string) ([]*MalformedEvent, error) {
-	query := `SELECT m.id, m.source_id, s.name, m.event_path, m.error_message, m.discovered_at
+	query := `SELECT m.id, m.source_id, s.name, m.event_path, m.error_message, m.category, m.discovered_at
 		FROM malformed_events m
 		JOIN sources s ON m.source_id = s.id
 		WHERE s.user_id = ?
@@ -981,7 +2024,158 @@ func (db *DB) GetMalformedEvents(userID string) ([]*MalformedEvent, error) {
 	for rows.Next() {
 		event := &MalformedEvent{}
 		err := rows.Scan(&event.ID, &event.SourceID, &event.SourceName,
-			&event.EventPath, &event.ErrorMessage, &event.DiscoveredAt)
+			&event.EventPath, &event.ErrorMessage, &event.Category, &event.DiscoveredAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan malformed event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating malformed events: %w", err)
+	}
+
+	return events, nil
+}
+
+// likeContains returns a LIKE pattern matching values that contain q
+// literally: LIKE's wildcards in q are escaped with a backslash, so
+// queries using it need ESCAPE '\'.
+func likeContains(q string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(q)
+	return "%" + escaped + "%"
+}
+
+// SearchSyncLogs returns up to limit of the user's sync logs, newest
+// first, whose message or details contain q (case-insensitive for
+// ASCII).
+func (db *DB) SearchSyncLogs(userID, q string, limit int) ([]*SyncLog, error) {
+	query := `SELECT l.id, l.source_id, l.status, l.message, l.details, l.duration_ms,
+		l.events_created, l.events_updated, l.events_deleted, l.events_skipped, l.calendars_synced, l.events_processed, l.created_at
+		FROM sync_logs l
+		JOIN sources s ON l.source_id = s.id
+		WHERE s.user_id = ? AND (l.message LIKE ? ESCAPE '\' OR l.details LIKE ? ESCAPE '\')
+		ORDER BY l.created_at DESC LIMIT ?`
+
+	pattern := likeContains(q)
+	rows, err := db.conn.Query(query, userID, pattern, pattern, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search sync logs: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []*SyncLog
+	for rows.Next() {
+		log := &SyncLog{}
+		var message, details sql.NullString
+		var durationMs int64
+		err := rows.Scan(&log.ID, &log.SourceID, &log.Status, &message, &details, &durationMs,
+			&log.EventsCreated, &log.EventsUpdated, &log.EventsDeleted, &log.EventsSkipped, &log.CalendarsSynced, &log.EventsProcessed, &log.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sync log: %w", err)
+		}
+		log.Message = message.String
+		log.Details = details.String
+		log.Duration = time.Duration(durationMs) * time.Millisecond
+		logs = append(logs, log)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sync logs: %w", err)
+	}
+
+	return logs, nil
+}
+
+// SearchMalformedEvents returns up to limit of the user's malformed
+// events, newest first, whose path or error contains q
+// (case-insensitive for ASCII).
+func (db *DB) SearchMalformedEvents(userID, q string, limit int) ([]*MalformedEvent, error) {
+	query := `SELECT m.id, m.source_id, s.name, m.event_path, m.error_message, m.category, m.discovered_at
+		FROM malformed_events m
+		JOIN sources s ON m.source_id = s.id
+		WHERE s.user_id = ? AND (m.event_path LIKE ? ESCAPE '\' OR m.error_message LIKE ? ESCAPE '\')
+		ORDER BY m.discovered_at DESC LIMIT ?`
+
+	pattern := likeContains(q)
+	rows, err := db.conn.Query(query, userID, pattern, pattern, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search malformed events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*MalformedEvent
+	for rows.Next() {
+		event := &MalformedEvent{}
+		err := rows.Scan(&event.ID, &event.SourceID, &event.SourceName,
+			&event.EventPath, &event.ErrorMessage, &event.Category, &event.DiscoveredAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan malformed event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating malformed events: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetMalformedEventCountsByCategory returns how many malformed events
+// the user's sources have in each category. Categories with none are
+// left out.
+func (db *DB) GetMalformedEventCountsByCategory(userID string) (map[string]int, error) {
+	query := `SELECT m.category, COUNT(*)
+		FROM malformed_events m
+		JOIN sources s ON m.source_id = s.id
+		WHERE s.user_id = ?
+		GROUP BY m.category`
+
+	rows, err := db.conn.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count malformed events: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var category string
+		var count int
+		if err := rows.Scan(&category, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan malformed event count: %w", err)
+		}
+		counts[category] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating malformed event counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetMalformedEventsForSourceBetween returns a source's malformed events
+// discovered in [from, to]. Sync logs carry no run ID, so this is how a
+// log is linked to the malformed events its run recorded.
+func (db *DB) GetMalformedEventsForSourceBetween(sourceID string, from, to time.Time) ([]*MalformedEvent, error) {
+	query := `SELECT m.id, m.source_id, s.name, m.event_path, m.error_message, m.category, m.discovered_at
+		FROM malformed_events m
+		JOIN sources s ON m.source_id = s.id
+		WHERE m.source_id = ? AND m.discovered_at >= ? AND m.discovered_at <= ?
+		ORDER BY m.discovered_at DESC`
+
+	rows, err := db.conn.Query(query, sourceID, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query malformed events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*MalformedEvent
+	for rows.Next() {
+		event := &MalformedEvent{}
+		err := rows.Scan(&event.ID, &event.SourceID, &event.SourceName,
+			&event.EventPath, &event.ErrorMessage, &event.Category, &event.DiscoveredAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan malformed event: %w", err)
 		}
@@ -997,14 +2191,14 @@ func (db *DB) GetMalformedEvents(userID string) ([]*MalformedEvent, error) {
 
 // GetMalformedEventByID returns a single malformed event by ID.
 func (db *DB) GetMalformedEventByID(id string) (*MalformedEvent, error) {
-	query := `SELECT m.id, m.source_id, s.name, m.event_path, m.error_message, m.discovered_at
+	query := `SELECT m.id, m.source_id, s.name, m.event_path, m.error_message, m.category, m.discovered_at
 		FROM malformed_events m
 		JOIN sources s ON m.source_id = s.id
 		WHERE m.id = ?`
 
 	event := &MalformedEvent{}
 	err := db.conn.QueryRow(query, id).Scan(&event.ID, &event.SourceID, &event.SourceName,
-		&event.EventPath, &event.ErrorMessage, &event.DiscoveredAt)
+		&event.EventPath, &event.ErrorMessage, &event.Category, &event.DiscoveredAt)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrNotFound
 	}
@@ -1018,14 +2212,14 @@ func (db *DB) GetMalformedEventByID(id string) (*MalformedEvent, error) {
 // GetMalformedEventByIDForUser returns a malformed event by ID only if it belongs to the user.
 // This prevents timing attacks by combining auth check with the query.
 func (db *DB) GetMalformedEventByIDForUser(id, userID string) (*MalformedEvent, error) {
-	query := `SELECT m.id, m.source_id, s.name, m.event_path, m.error_message, m.discovered_at
+	query := `SELECT m.id, m.source_id, s.name, m.event_path, m.error_message, m.category, m.discovered_at
 		FROM malformed_events m
 		JOIN sources s ON m.source_id = s.id
 		WHERE m.id = ? AND s.user_id = ?`
 
 	event := &MalformedEvent{}
 	err := db.conn.QueryRow(query, id, userID).Scan(&event.ID, &event.SourceID, &event.SourceName,
-		&event.EventPath, &event.ErrorMessage, &event.DiscoveredAt)
+		&event.EventPath, &event.ErrorMessage, &event.Category, &event.DiscoveredAt)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrNotFound
 	}
@@ -1069,6 +2263,26 @@ func (db *DB) ClearMalformedEventsForSource(sourceID string) error {
 	return nil
 }
 
+// CleanOldMalformedEvents deletes malformed event records last seen
+// before the given time. Events still broken at the source are
+// re-saved with a fresh discovered_at on every sync, so only stale
+// records (fixed or deleted upstream) are removed.
+func (db *DB) CleanOldMalformedEvents(olderThan time.Time) (int64, error) {
+	query := `DELETE FROM malformed_events WHERE discovered_at < ?`
+
+	result, err := db.conn.Exec(query, olderThan.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to clean old malformed events: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return affected, nil
+}
+
 // DeleteAllMalformedEventsForUser removes all malformed events for a user's sources.
 // Returns the number of events deleted.
 func (db *DB) DeleteAllMalformedEventsForUser(userID string) (int64, error) {
@@ -1097,7 +2311,7 @@ func (db *DB) DeleteAllMalformedEventsForUser(userID string) (int64, error) {
 // GetUserAlertPreferences returns alert preferences for a user.
 // Returns nil (not ErrNotFound) if preferences haven't been set yet.
 func (db *DB) GetUserAlertPreferences(userID string) (*UserAlertPreferences, error) {
-	query := `SELECT id, user_id, email_enabled, webhook_enabled, webhook_url, cooldown_minutes, created_at, updated_at
+	query := `SELECT id, user_id, email_enabled, webhook_enabled, webhook_url, cooldown_minutes, alert_on_partial, created_at, updated_at
 		FROM user_alert_preferences WHERE user_id = ?`
 
 	row := db.conn.QueryRow(query, userID)
@@ -1106,7 +2320,7 @@ func (db *DB) GetUserAlertPreferences(userID string) (*UserAlertPreferences, err
 	var emailEnabled, webhookEnabled, cooldownMinutes sql.NullInt64
 	var webhookURL sql.NullString
 
-	err := row.Scan(&prefs.ID, &prefs.UserID, &emailEnabled, &webhookEnabled, &webhookURL, &cooldownMinutes, &prefs.CreatedAt, &prefs.UpdatedAt)
+	err := row.Scan(&prefs.ID, &prefs.UserID, &emailEnabled, &webhookEnabled, &webhookURL, &cooldownMinutes, &prefs.AlertOnPartial, &prefs.CreatedAt, &prefs.UpdatedAt)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil // Return nil, nil to indicate no preferences set (use defaults)
 	}
@@ -1168,10 +2382,10 @@ func (db *DB) UpsertUserAlertPreferences(prefs *UserAlertPreferences) error {
 	}
 
 	// Try to update first
-	query := `UPDATE user_alert_preferences SET email_enabled = ?, webhook_enabled = ?, webhook_url = ?, cooldown_minutes = ?, updated_at = ?
+	query := `UPDATE user_alert_preferences SET email_enabled = ?, webhook_enabled = ?, webhook_url = ?, cooldown_minutes = ?, alert_on_partial = ?, updated_at = ?
 		WHERE user_id = ?`
 
-	result, err := db.conn.Exec(query, emailEnabled, webhookEnabled, webhookURL, cooldownMinutes, now, prefs.UserID)
+	result, err := db.conn.Exec(query, emailEnabled, webhookEnabled, webhookURL, cooldownMinutes, prefs.AlertOnPartial, now, prefs.UserID)
 	if err != nil {
 		return fmt.Errorf("failed to update user alert preferences: %w", err)
 	}
@@ -1189,10 +2403,10 @@ func (db *DB) UpsertUserAlertPreferences(prefs *UserAlertPreferences) error {
 		prefs.CreatedAt = now
 		prefs.UpdatedAt = now
 
-		insertQuery := `INSERT INTO user_alert_preferences (id, user_id, email_enabled, webhook_enabled, webhook_url, cooldown_minutes, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+		insertQuery := `INSERT INTO user_alert_preferences (id, user_id, email_enabled, webhook_enabled, webhook_url, cooldown_minutes, alert_on_partial, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-		_, err = db.conn.Exec(insertQuery, prefs.ID, prefs.UserID, emailEnabled, webhookEnabled, webhookURL, cooldownMinutes, prefs.CreatedAt, prefs.UpdatedAt)
+		_, err = db.conn.Exec(insertQuery, prefs.ID, prefs.UserID, emailEnabled, webhookEnabled, webhookURL, cooldownMinutes, prefs.AlertOnPartial, prefs.CreatedAt, prefs.UpdatedAt)
 		if err != nil {
 			return fmt.Errorf("failed to insert user alert preferences: %w", err)
 		}
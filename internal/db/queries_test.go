@@ -528,6 +528,33 @@ func TestUpdateSource(t *testing.T) {
 		}
 	})
 
+	t.Run("alert overrides round-trip including unset", func(t *testing.T) {
+		if source.AlertEmailEnabled != nil || source.AlertWebhookEnabled != nil {
+			t.Fatalf("expected no overrides on a new source, got %v / %v", source.AlertEmailEnabled, source.AlertWebhookEnabled)
+		}
+		off := false
+		source.AlertWebhookEnabled = &off
+		if err := db.UpdateSource(source); err != nil {
+			t.Fatalf("failed to update source: %v", err)
+		}
+		updated, _ := db.GetSourceByID(source.ID)
+		if updated.AlertWebhookEnabled == nil || *updated.AlertWebhookEnabled {
+			t.Errorf("expected webhook alerts off, got %v", updated.AlertWebhookEnabled)
+		}
+		if updated.AlertEmailEnabled != nil {
+			t.Errorf("expected email alerts to stay inherited, got %v", *updated.AlertEmailEnabled)
+		}
+
+		source.AlertWebhookEnabled = nil
+		if err := db.UpdateSource(source); err != nil {
+			t.Fatalf("failed to update source: %v", err)
+		}
+		updated, _ = db.GetSourceByID(source.ID)
+		if updated.AlertWebhookEnabled != nil {
+			t.Errorf("expected the override to be cleared, got %v", *updated.AlertWebhookEnabled)
+		}
+	})
+
 	t.Run("returns ErrNotFound for nonexistent source", func(t *testing.T) {
 		nonexistent := &Source{ID: "nonexistent-id"}
 		err := db.UpdateSource(nonexistent)
@@ -562,6 +589,100 @@ func TestUpdateSourceSyncStatus(t *testing.T) {
 		}
 	})
 
+	t.Run("last success persists across failures", func(t *testing.T) {
+		src := createTestSource(t, db, userID, "Last Success Test")
+		if src.LastSuccessAt != nil {
+			t.Fatal("expected LastSuccessAt to be nil for a new source")
+		}
+
+		if err := db.UpdateSourceSyncStatus(src.ID, SyncStatusError, "boom"); err != nil {
+			t.Fatalf("failed to update status: %v", err)
+		}
+		updated, _ := db.GetSourceByID(src.ID)
+		if updated.LastSuccessAt != nil {
+			t.Errorf("expected LastSuccessAt to stay nil after a failure, got %v", updated.LastSuccessAt)
+		}
+
+		if err := db.UpdateSourceSyncStatus(src.ID, SyncStatusSuccess, "ok"); err != nil {
+			t.Fatalf("failed to update status: %v", err)
+		}
+		updated, _ = db.GetSourceByID(src.ID)
+		if updated.LastSuccessAt == nil {
+			t.Fatal("expected LastSuccessAt to be set after a success")
+		}
+		lastSuccess := *updated.LastSuccessAt
+
+		time.Sleep(10 * time.Millisecond)
+		if err := db.UpdateSourceSyncStatus(src.ID, SyncStatusError, "boom again"); err != nil {
+			t.Fatalf("failed to update status: %v", err)
+		}
+		updated, _ = db.GetSourceByID(src.ID)
+		if updated.LastSuccessAt == nil || !updated.LastSuccessAt.Equal(lastSuccess) {
+			t.Errorf("expected LastSuccessAt to stay %v after a failure, got %v", lastSuccess, updated.LastSuccessAt)
+		}
+		if updated.LastSyncAt == nil || !updated.LastSyncAt.After(lastSuccess) {
+			t.Errorf("expected LastSyncAt to advance past %v, got %v", lastSuccess, updated.LastSyncAt)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		if err := db.UpdateSourceSyncStatus(src.ID, SyncStatusPartial, "some warnings"); err != nil {
+			t.Fatalf("failed to update status: %v", err)
+		}
+		updated, _ = db.GetSourceByID(src.ID)
+		if updated.LastSuccessAt == nil || !updated.LastSuccessAt.After(lastSuccess) {
+			t.Errorf("expected a partial sync to advance LastSuccessAt past %v, got %v", lastSuccess, updated.LastSuccessAt)
+		}
+	})
+
+	t.Run("last error detail cleared on success", func(t *testing.T) {
+		src := createTestSource(t, db, userID, "Last Error Detail Test")
+
+		if err := db.UpdateSourceSyncStatus(src.ID, SyncStatusError, "boom"); err != nil {
+			t.Fatalf("failed to update status: %v", err)
+		}
+		if err := db.UpdateSourceLastErrorDetail(src.ID, "Failed to connect: server unreachable"); err != nil {
+			t.Fatalf("failed to update error detail: %v", err)
+		}
+		updated, _ := db.GetSourceByID(src.ID)
+		if updated.LastErrorDetail != "Failed to connect: server unreachable" {
+			t.Errorf("expected error detail to be stored, got %q", updated.LastErrorDetail)
+		}
+
+		if err := db.UpdateSourceSyncStatus(src.ID, SyncStatusError, "boom again"); err != nil {
+			t.Fatalf("failed to update status: %v", err)
+		}
+		updated, _ = db.GetSourceByID(src.ID)
+		if updated.LastErrorDetail == "" {
+			t.Error("expected a failed sync to keep the error detail")
+		}
+
+		if err := db.UpdateSourceSyncStatus(src.ID, SyncStatusSuccess, "ok"); err != nil {
+			t.Fatalf("failed to update status: %v", err)
+		}
+		updated, _ = db.GetSourceByID(src.ID)
+		if updated.LastErrorDetail != "" {
+			t.Errorf("expected a success to clear the error detail, got %q", updated.LastErrorDetail)
+		}
+
+		if err := db.UpdateSourceLastErrorDetail("nonexistent-id", "x"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("clock skew", func(t *testing.T) {
+		src := createTestSource(t, db, userID, "Clock Skew Test")
+		if err := db.UpdateSourceClockSkew(src.ID, -42); err != nil {
+			t.Fatalf("failed to update clock skew: %v", err)
+		}
+		updated, _ := db.GetSourceByID(src.ID)
+		if updated.ClockSkewSeconds != -42 {
+			t.Errorf("expected clock skew -42, got %d", updated.ClockSkewSeconds)
+		}
+		if err := db.UpdateSourceClockSkew("nonexistent-id", 1); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
 	t.Run("returns ErrNotFound for nonexistent source", func(t *testing.T) {
 		err := db.UpdateSourceSyncStatus("nonexistent-id", SyncStatusError, "Error")
 		if !errors.Is(err, ErrNotFound) {
@@ -637,6 +758,7 @@ func TestSyncState(t *testing.T) {
 			CalendarHref: "/calendar/default/",
 			SyncToken:    "updated-token",
 			CTag:         "updated-ctag",
+			LastModified: "Mon, 05 Jan 2026 10:00:00 GMT",
 		}
 
 		err := db.UpsertSyncState(state)
@@ -648,6 +770,9 @@ func TestSyncState(t *testing.T) {
 		if retrieved.SyncToken != "updated-token" {
 			t.Errorf("expected 'updated-token', got %q", retrieved.SyncToken)
 		}
+		if retrieved.LastModified != "Mon, 05 Jan 2026 10:00:00 GMT" {
+			t.Errorf("expected the last-modified date stored, got %q", retrieved.LastModified)
+		}
 	})
 
 	t.Run("get returns ErrNotFound for unknown state", func(t *testing.T) {
@@ -656,6 +781,32 @@ func TestSyncState(t *testing.T) {
 			t.Errorf("expected ErrNotFound, got %v", err)
 		}
 	})
+
+	t.Run("lists and deletes calendars with a sync state", func(t *testing.T) {
+		if err := db.UpsertSyncState(&SyncState{SourceID: source.ID, CalendarHref: "/calendar/home/", CTag: "1"}); err != nil {
+			t.Fatalf("failed to upsert: %v", err)
+		}
+		other := createTestSource(t, db, userID, "Other Source")
+		if err := db.UpsertSyncState(&SyncState{SourceID: other.ID, CalendarHref: "/calendar/other/", CTag: "1"}); err != nil {
+			t.Fatalf("failed to upsert: %v", err)
+		}
+
+		hrefs, err := db.GetSyncStateCalendars(source.ID)
+		if err != nil {
+			t.Fatalf("GetSyncStateCalendars: %v", err)
+		}
+		if len(hrefs) != 2 || hrefs[0] != "/calendar/default/" || hrefs[1] != "/calendar/home/" {
+			t.Errorf("expected this source's two calendars, got %v", hrefs)
+		}
+
+		if err := db.DeleteSyncState(source.ID, "/calendar/home/"); err != nil {
+			t.Fatalf("DeleteSyncState: %v", err)
+		}
+		hrefs, _ = db.GetSyncStateCalendars(source.ID)
+		if len(hrefs) != 1 || hrefs[0] != "/calendar/default/" {
+			t.Errorf("expected only /calendar/default/ left, got %v", hrefs)
+		}
+	})
 }
 
 // ============================================================================
@@ -711,6 +862,34 @@ func TestSyncLog(t *testing.T) {
 		}
 	})
 
+	t.Run("gets a log by ID", func(t *testing.T) {
+		created := &SyncLog{
+			SourceID: source.ID,
+			Status:   SyncStatusPartial,
+			Message:  "Synced with warnings",
+			Details:  "Warnings:\nsomething",
+			Duration: 3 * time.Second,
+		}
+		if err := db.CreateSyncLog(created); err != nil {
+			t.Fatalf("failed to create log: %v", err)
+		}
+
+		got, err := db.GetSyncLogByID(created.ID)
+		if err != nil {
+			t.Fatalf("failed to get log: %v", err)
+		}
+		if got.SourceID != source.ID || got.Status != SyncStatusPartial || got.Details != created.Details {
+			t.Errorf("unexpected log: %+v", got)
+		}
+		if got.Duration != 3*time.Second {
+			t.Errorf("expected 3s duration, got %v", got.Duration)
+		}
+
+		if _, err := db.GetSyncLogByID("nonexistent"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
 	t.Run("get logs respects limit", func(t *testing.T) {
 		// Create multiple logs
 		for i := 0; i < 5; i++ {
@@ -740,6 +919,250 @@ func TestSyncLog(t *testing.T) {
 	})
 }
 
+func TestGetSyncOverview(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "overview@example.com")
+	otherUser := createTestUser(t, db, "other-overview@example.com")
+	healthy := createTestSource(t, db, userID, "Healthy")
+	failing := createTestSource(t, db, userID, "Failing")
+	paused := createTestSource(t, db, userID, "Paused")
+	foreign := createTestSource(t, db, otherUser, "Someone else's")
+
+	paused.Enabled = false
+	if err := db.UpdateSource(paused); err != nil {
+		t.Fatalf("failed to pause source: %v", err)
+	}
+	if err := db.UpdateSourceSyncStatus(failing.ID, SyncStatusError, "boom"); err != nil {
+		t.Fatalf("failed to update status: %v", err)
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	// addLog records a run ago before now with the given outcome.
+	addLog := func(sourceID string, status SyncStatus, created, updated, deleted int, ago time.Duration) {
+		t.Helper()
+		l := &SyncLog{SourceID: sourceID, Status: status, EventsCreated: created, EventsUpdated: updated, EventsDeleted: deleted}
+		if err := db.CreateSyncLog(l); err != nil {
+			t.Fatalf("failed to create log: %v", err)
+		}
+		if _, err := db.conn.Exec(`UPDATE sync_logs SET created_at = ? WHERE id = ?`, time.Now().UTC().Add(-ago), l.ID); err != nil {
+			t.Fatalf("failed to backdate log: %v", err)
+		}
+	}
+	sinceMidnight := time.Since(today) / 2
+	addLog(healthy.ID, SyncStatusSuccess, 2, 1, 0, sinceMidnight)
+	addLog(healthy.ID, SyncStatusPartial, 0, 3, 1, 3*24*time.Hour)
+	addLog(failing.ID, SyncStatusError, 0, 0, 0, sinceMidnight)
+	addLog(failing.ID, SyncStatusSuccess, 5, 0, 0, 10*24*time.Hour) // outside the week
+	addLog(foreign.ID, SyncStatusSuccess, 9, 9, 9, sinceMidnight)   // another user's
+
+	overview, err := db.GetSyncOverview(userID, today, today.AddDate(0, 0, -6))
+	if err != nil {
+		t.Fatalf("GetSyncOverview: %v", err)
+	}
+	want := SyncOverview{
+		TotalSources:   3,
+		ActiveSources:  2,
+		PausedSources:  1,
+		FailingSources: 1,
+		SyncsToday:     2,
+		EventsToday:    3,
+		SyncsWeek:      3,
+		EventsWeek:     7,
+	}
+	got := *overview
+	got.SuccessRate = 0
+	if got != want {
+		t.Errorf("overview = %+v, want %+v", got, want)
+	}
+	if rate := overview.SuccessRate; rate < 66.6 || rate > 66.7 {
+		t.Errorf("expected a 2-in-3 success rate, got %v", rate)
+	}
+
+	stale, err := db.CountUserSourcesIn(userID, []string{healthy.ID, foreign.ID, "missing"})
+	if err != nil {
+		t.Fatalf("CountUserSourcesIn: %v", err)
+	}
+	if stale != 1 {
+		t.Errorf("expected only the user's own source to count, got %d", stale)
+	}
+}
+
+func TestDeleteSyncLogsForSource(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "logs@example.com")
+	source := createTestSource(t, db, userID, "Noisy")
+	other := createTestSource(t, db, userID, "Quiet")
+	for i := 0; i < 3; i++ {
+		if err := db.CreateSyncLog(&SyncLog{SourceID: source.ID, Status: SyncStatusSuccess}); err != nil {
+			t.Fatalf("failed to create log: %v", err)
+		}
+	}
+	if err := db.CreateSyncLog(&SyncLog{SourceID: other.ID, Status: SyncStatusSuccess}); err != nil {
+		t.Fatalf("failed to create log: %v", err)
+	}
+
+	deleted, err := db.DeleteSyncLogsForSource(source.ID)
+	if err != nil {
+		t.Fatalf("failed to delete logs: %v", err)
+	}
+	if deleted != 3 {
+		t.Errorf("expected 3 logs deleted, got %d", deleted)
+	}
+	if logs, _ := db.GetSyncLogs(source.ID, 10); len(logs) != 0 {
+		t.Errorf("expected no logs left for the source, got %d", len(logs))
+	}
+	if logs, _ := db.GetSyncLogs(other.ID, 10); len(logs) != 1 {
+		t.Errorf("expected the other source's log to be kept, got %d", len(logs))
+	}
+
+	deleted, err = db.DeleteSyncLogsForSource(source.ID)
+	if err != nil || deleted != 0 {
+		t.Errorf("expected a second delete to remove nothing, got %d, %v", deleted, err)
+	}
+}
+
+func TestGetMalformedEventsForSourceBetween(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "window@example.com")
+	source := createTestSource(t, db, userID, "Window Test")
+	other := createTestSource(t, db, userID, "Other Source")
+
+	before := time.Now().UTC().Add(-time.Second)
+	if err := db.SaveMalformedEvent(source.ID, "/cal/bad.ics", "parse error"); err != nil {
+		t.Fatalf("SaveMalformedEvent: %v", err)
+	}
+	if err := db.SaveMalformedEvent(other.ID, "/cal/other.ics", "parse error"); err != nil {
+		t.Fatalf("SaveMalformedEvent: %v", err)
+	}
+	after := time.Now().UTC().Add(time.Second)
+
+	events, err := db.GetMalformedEventsForSourceBetween(source.ID, before, after)
+	if err != nil {
+		t.Fatalf("GetMalformedEventsForSourceBetween: %v", err)
+	}
+	if len(events) != 1 || events[0].EventPath != "/cal/bad.ics" {
+		t.Errorf("expected only this source's event in the window, got %+v", events)
+	}
+
+	events, err = db.GetMalformedEventsForSourceBetween(source.ID, before.Add(-time.Hour), before)
+	if err != nil {
+		t.Fatalf("GetMalformedEventsForSourceBetween: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("expected no events before the window, got %d", len(events))
+	}
+}
+
+func TestClassifyMalformedError(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		{"nil iCalendar data - event may be corrupted or deleted", MalformedCategoryEmpty},
+		{"empty iCalendar data - event may be corrupted or deleted", MalformedCategoryEmpty},
+		{`failed to encode event: ical: failed to encode "VEVENT": want exactly one "DTSTAMP" property, got 0`, MalformedCategoryMissingDTStamp},
+		{`failed to encode event: ical: failed to encode "VEVENT": want exactly one "UID" property, got 0`, MalformedCategoryMissingUID},
+		{`failed to encode event: ical: failed to encode "VTIMEZONE": want exactly one "TZID" property, got 0`, MalformedCategoryMissingProperty},
+		{"malformed calendar content: ical: malformed content line: missing colon", MalformedCategoryMissingColon},
+		{"malformed calendar content: ical: malformed property: expected colon", MalformedCategoryMissingColon},
+		{"malformed calendar content: ical: malformed text: antislash at end of text", MalformedCategoryInvalidEncoding},
+		{"failed to encode event: ical: failed to encode property value: contains a CR or LF", MalformedCategoryInvalidEncoding},
+		{"XML syntax error on line 3: invalid UTF-8", MalformedCategoryInvalidEncoding},
+		{"malformed calendar content: ical: invalid duration: expected 'P'", MalformedCategoryInvalidValue},
+		{`malformed calendar content: ical: malformed component: expected END property for "VEVENT", got "VCALENDAR"`, MalformedCategoryBadStructure},
+		{"malformed calendar content: event too large: /cal/big.ics is 2000000 bytes, limit is 1048576", MalformedCategoryTooLarge},
+		{"MULTIGET silently dropped this event; individual fetch returned: 404 Not Found", MalformedCategoryFetchFailed},
+		{"something nobody anticipated", MalformedCategoryOther},
+		{"", MalformedCategoryOther},
+	}
+	for _, tt := range tests {
+		if got := classifyMalformedError(tt.msg); got != tt.want {
+			t.Errorf("classifyMalformedError(%q) = %q, want %q", tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestGetMalformedEventCountsByCategory(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "counts@example.com")
+	source := createTestSource(t, db, userID, "Counted")
+	otherUser := createTestUser(t, db, "other@example.com")
+	otherSource := createTestSource(t, db, otherUser, "Not Counted")
+
+	for path, msg := range map[string]string{
+		"/cal/a.ics": "empty iCalendar data - event may be corrupted or deleted",
+		"/cal/b.ics": "nil iCalendar data - event may be corrupted or deleted",
+		"/cal/c.ics": "ical: malformed content line: missing colon",
+	} {
+		if err := db.SaveMalformedEvent(source.ID, path, msg); err != nil {
+			t.Fatalf("SaveMalformedEvent: %v", err)
+		}
+	}
+	if err := db.SaveMalformedEvent(otherSource.ID, "/cal/d.ics", "ical: malformed content line: missing colon"); err != nil {
+		t.Fatalf("SaveMalformedEvent: %v", err)
+	}
+
+	counts, err := db.GetMalformedEventCountsByCategory(userID)
+	if err != nil {
+		t.Fatalf("GetMalformedEventCountsByCategory: %v", err)
+	}
+	want := map[string]int{MalformedCategoryEmpty: 2, MalformedCategoryMissingColon: 1}
+	if len(counts) != len(want) || counts[MalformedCategoryEmpty] != 2 || counts[MalformedCategoryMissingColon] != 1 {
+		t.Errorf("counts = %v, want %v", counts, want)
+	}
+
+	events, err := db.GetMalformedEvents(userID)
+	if err != nil {
+		t.Fatalf("GetMalformedEvents: %v", err)
+	}
+	for _, e := range events {
+		if e.Category != classifyMalformedError(e.ErrorMessage) {
+			t.Errorf("event %s has category %q, want it derived from %q", e.EventPath, e.Category, e.ErrorMessage)
+		}
+	}
+}
+
+func TestCleanOldMalformedEvents(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "malformed-clean@example.com")
+	source := createTestSource(t, db, userID, "Malformed Cleanup")
+
+	for _, path := range []string{"/cal/stale.ics", "/cal/fresh.ics"} {
+		if err := db.SaveMalformedEvent(source.ID, path, "broken"); err != nil {
+			t.Fatalf("failed to save malformed event: %v", err)
+		}
+	}
+	if _, err := db.conn.Exec(`UPDATE malformed_events SET discovered_at = ? WHERE event_path = ?`,
+		time.Now().UTC().AddDate(0, 0, -40), "/cal/stale.ics"); err != nil {
+		t.Fatalf("failed to backdate malformed event: %v", err)
+	}
+
+	deleted, err := db.CleanOldMalformedEvents(time.Now().AddDate(0, 0, -30))
+	if err != nil {
+		t.Fatalf("CleanOldMalformedEvents: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected 1 stale record deleted, got %d", deleted)
+	}
+	events, err := db.GetMalformedEvents(userID)
+	if err != nil {
+		t.Fatalf("GetMalformedEvents: %v", err)
+	}
+	if len(events) != 1 || events[0].EventPath != "/cal/fresh.ics" {
+		t.Errorf("expected only the fresh record to remain, got %+v", events)
+	}
+}
+
 // ============================================================================
 // SyncedEvent Tests
 // ============================================================================
@@ -938,3 +1361,672 @@ func TestDatabaseConnection(t *testing.T) {
 		}
 	})
 }
+
+func TestGetSourcesDueForSync(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "due@example.com")
+	now := time.Now().UTC()
+
+	overdue := createTestSource(t, db, userID, "Overdue")
+	notDue := createTestSource(t, db, userID, "Not Due")
+	neverScheduled := createTestSource(t, db, userID, "Never Scheduled")
+	disabled := createTestSource(t, db, userID, "Disabled")
+
+	if err := db.UpdateSourceNextSyncAt(overdue.ID, now.Add(-10*time.Minute)); err != nil {
+		t.Fatalf("failed to set next_sync_at: %v", err)
+	}
+	if err := db.UpdateSourceNextSyncAt(notDue.ID, now.Add(10*time.Minute)); err != nil {
+		t.Fatalf("failed to set next_sync_at: %v", err)
+	}
+	if err := db.UpdateSourceNextSyncAt(disabled.ID, now.Add(-10*time.Minute)); err != nil {
+		t.Fatalf("failed to set next_sync_at: %v", err)
+	}
+	disabled.Enabled = false
+	if err := db.UpdateSource(disabled); err != nil {
+		t.Fatalf("failed to disable source: %v", err)
+	}
+
+	t.Run("returns overdue and never-scheduled enabled sources", func(t *testing.T) {
+		due, err := db.GetSourcesDueForSync(now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(due) != 2 {
+			t.Fatalf("expected 2 due sources, got %d", len(due))
+		}
+		// NULL next_sync_at sorts first, then most overdue.
+		if due[0].ID != neverScheduled.ID {
+			t.Errorf("expected never-scheduled source first, got %s", due[0].Name)
+		}
+		if due[1].ID != overdue.ID {
+			t.Errorf("expected overdue source second, got %s", due[1].Name)
+		}
+		if due[1].NextSyncAt == nil {
+			t.Error("expected NextSyncAt to be populated on overdue source")
+		}
+	})
+
+	t.Run("source becomes due once its time passes", func(t *testing.T) {
+		due, err := db.GetSourcesDueForSync(now.Add(time.Hour))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(due) != 3 {
+			t.Fatalf("expected 3 due sources, got %d", len(due))
+		}
+		for _, s := range due {
+			if s.ID == disabled.ID {
+				t.Error("disabled source should never be due")
+			}
+		}
+	})
+
+	t.Run("update for missing source returns ErrNotFound", func(t *testing.T) {
+		err := db.UpdateSourceNextSyncAt("nonexistent", now)
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+func TestSourcesNextSyncIndexExists(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	var name string
+	err := db.conn.QueryRow(
+		`SELECT name FROM sqlite_master WHERE type = 'index' AND name = 'idx_sources_enabled_next_sync_at'`,
+	).Scan(&name)
+	if err != nil {
+		t.Fatalf("expected idx_sources_enabled_next_sync_at to exist: %v", err)
+	}
+}
+
+func TestSourceAttendeeRewriteRoundTrip(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "rewrite@example.com")
+	source := createTestSource(t, db, userID, "Rewrite")
+
+	got, err := db.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get source: %v", err)
+	}
+	if got.AttendeeRewrite != nil {
+		t.Errorf("expected nil AttendeeRewrite by default, got %v", got.AttendeeRewrite)
+	}
+
+	got.AttendeeRewrite = map[string]string{"alice@work.example": "alice@home.example"}
+	if err := db.UpdateSource(got); err != nil {
+		t.Fatalf("failed to update source: %v", err)
+	}
+
+	got, err = db.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get source: %v", err)
+	}
+	if got.AttendeeRewrite["alice@work.example"] != "alice@home.example" {
+		t.Errorf("expected mapping to round-trip, got %v", got.AttendeeRewrite)
+	}
+
+	got.AttendeeRewrite = nil
+	if err := db.UpdateSource(got); err != nil {
+		t.Fatalf("failed to update source: %v", err)
+	}
+	got, err = db.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get source: %v", err)
+	}
+	if len(got.AttendeeRewrite) != 0 {
+		t.Errorf("expected mapping cleared, got %v", got.AttendeeRewrite)
+	}
+}
+
+func TestSourceCustomHeadersRoundTrip(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "headers@example.com")
+	source := createTestSource(t, db, userID, "Headers")
+
+	got, err := db.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get source: %v", err)
+	}
+	if got.CustomHeaders != nil {
+		t.Errorf("expected nil CustomHeaders by default, got %v", got.CustomHeaders)
+	}
+
+	got.CustomHeaders = map[string]string{"X-Proxy-Tenant": "acme"}
+	if err := db.UpdateSource(got); err != nil {
+		t.Fatalf("failed to update source: %v", err)
+	}
+	got, err = db.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get source: %v", err)
+	}
+	if got.CustomHeaders["X-Proxy-Tenant"] != "acme" {
+		t.Errorf("expected headers to round-trip, got %v", got.CustomHeaders)
+	}
+}
+
+func TestSourceIncludeCategoriesRoundTrip(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "categories@example.com")
+	source := createTestSource(t, db, userID, "Categories")
+
+	got, err := db.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get source: %v", err)
+	}
+	if got.IncludeCategories != nil {
+		t.Errorf("expected nil IncludeCategories by default, got %v", got.IncludeCategories)
+	}
+
+	got.IncludeCategories = []string{"Work", "Travel"}
+	if err := db.UpdateSource(got); err != nil {
+		t.Fatalf("failed to update source: %v", err)
+	}
+	got, err = db.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get source: %v", err)
+	}
+	if len(got.IncludeCategories) != 2 || got.IncludeCategories[0] != "Work" || got.IncludeCategories[1] != "Travel" {
+		t.Errorf("expected categories to round-trip, got %v", got.IncludeCategories)
+	}
+
+	got.IncludeCategories = nil
+	if err := db.UpdateSource(got); err != nil {
+		t.Fatalf("failed to update source: %v", err)
+	}
+	got, err = db.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get source: %v", err)
+	}
+	if got.IncludeCategories != nil {
+		t.Errorf("expected cleared IncludeCategories, got %v", got.IncludeCategories)
+	}
+}
+
+func TestSourceTagsRoundTrip(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "tags@example.com")
+	source := createTestSource(t, db, userID, "Tags")
+
+	got, err := db.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get source: %v", err)
+	}
+	if got.Tags != nil {
+		t.Errorf("expected untagged by default, got %v", got.Tags)
+	}
+
+	got.Tags = []string{"work", "client-a"}
+	if err := db.UpdateSource(got); err != nil {
+		t.Fatalf("failed to update source: %v", err)
+	}
+	sources, err := db.GetSourcesByUserID(userID)
+	if err != nil {
+		t.Fatalf("failed to list sources: %v", err)
+	}
+	if len(sources) != 1 || len(sources[0].Tags) != 2 || sources[0].Tags[1] != "client-a" {
+		t.Errorf("expected tags to round-trip, got %+v", sources)
+	}
+}
+
+func TestLikeContains(t *testing.T) {
+	for q, want := range map[string]string{
+		"board":  "%board%",
+		"50%":    `%50\%%`,
+		"a_b":    `%a\_b%`,
+		`C:\cal`: `%C:\\cal%`,
+	} {
+		if got := likeContains(q); got != want {
+			t.Errorf("likeContains(%q) = %q, want %q", q, got, want)
+		}
+	}
+}
+
+func TestSourcePreserveDestPropsRoundTrip(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "preserve@example.com")
+	source := createTestSource(t, db, userID, "Preserve")
+
+	got, err := db.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get source: %v", err)
+	}
+	if got.PreserveDestProps != nil {
+		t.Errorf("expected nothing preserved by default, got %v", got.PreserveDestProps)
+	}
+
+	got.PreserveDestProps = []string{"X-NOTE", "CATEGORIES"}
+	if err := db.UpdateSource(got); err != nil {
+		t.Fatalf("failed to update source: %v", err)
+	}
+	got, err = db.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get source: %v", err)
+	}
+	if len(got.PreserveDestProps) != 2 || got.PreserveDestProps[0] != "X-NOTE" {
+		t.Errorf("expected preserved properties to round-trip, got %v", got.PreserveDestProps)
+	}
+}
+
+func TestSourceTextRulesRoundTrip(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "rules@example.com")
+	source := createTestSource(t, db, userID, "Rules")
+
+	source.TextRules = []TextRule{
+		{Field: TextRuleFieldSummary, Find: "^", Replace: "[Team] ", Regex: true},
+		{Field: TextRuleFieldDescription, Find: "Join Zoom", Replace: ""},
+	}
+	if err := db.UpdateSource(source); err != nil {
+		t.Fatalf("failed to update source: %v", err)
+	}
+	got, err := db.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get source: %v", err)
+	}
+	if len(got.TextRules) != 2 || got.TextRules[0] != source.TextRules[0] || got.TextRules[1] != source.TextRules[1] {
+		t.Errorf("expected text rules to round-trip in order, got %+v", got.TextRules)
+	}
+}
+
+func TestSourceStampRoundTrip(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "stamp@example.com")
+	source := createTestSource(t, db, userID, "Stamp")
+
+	source.SourceStamp = "calbridge:{source_name}"
+	if err := db.UpdateSource(source); err != nil {
+		t.Fatalf("failed to update source: %v", err)
+	}
+	got, err := db.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get source: %v", err)
+	}
+	if got.SourceStamp != "calbridge:{source_name}" {
+		t.Errorf("expected the stamp template to round-trip, got %q", got.SourceStamp)
+	}
+}
+
+func TestCABundleRoundTrip(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "ca@example.com")
+	source := createTestSource(t, db, userID, "Internal CA")
+
+	bundle := "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+	source.CABundle = bundle
+	if err := db.UpdateSource(source); err != nil {
+		t.Fatalf("failed to update source: %v", err)
+	}
+	got, err := db.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get source: %v", err)
+	}
+	if got.CABundle != bundle {
+		t.Errorf("expected the CA bundle to round-trip, got %q", got.CABundle)
+	}
+	sources, err := db.GetSourcesByUserID(userID)
+	if err != nil {
+		t.Fatalf("failed to list sources: %v", err)
+	}
+	if len(sources) != 1 || sources[0].CABundle != bundle {
+		t.Errorf("expected the listed source to carry the CA bundle, got %+v", sources)
+	}
+}
+
+func TestResetSourceSyncState(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "reset@example.com")
+	source := createTestSource(t, db, userID, "Reset")
+	other := createTestSource(t, db, userID, "Other")
+	for _, s := range []*Source{source, other} {
+		if err := db.UpsertSyncedEvent(&SyncedEvent{SourceID: s.ID, CalendarHref: "/cal/", EventUID: "uid1"}); err != nil {
+			t.Fatalf("failed to upsert synced event: %v", err)
+		}
+		if err := db.UpsertSyncState(&SyncState{SourceID: s.ID, CalendarHref: "/cal/", CTag: "1"}); err != nil {
+			t.Fatalf("failed to upsert sync state: %v", err)
+		}
+	}
+
+	if err := db.ResetSourceSyncState(source.ID); err != nil {
+		t.Fatalf("ResetSourceSyncState: %v", err)
+	}
+	if events, _ := db.GetSyncedEvents(source.ID, "/cal/"); len(events) != 0 {
+		t.Errorf("expected the source's synced events gone, got %d", len(events))
+	}
+	if state, _ := db.GetSyncState(source.ID, "/cal/"); state != nil {
+		t.Errorf("expected the source's sync state gone, got %+v", state)
+	}
+	if events, _ := db.GetSyncedEvents(other.ID, "/cal/"); len(events) != 1 {
+		t.Errorf("expected the other source's synced events kept, got %d", len(events))
+	}
+	if state, _ := db.GetSyncState(other.ID, "/cal/"); state == nil {
+		t.Error("expected the other source's sync state kept")
+	}
+}
+
+func TestSourceUIDPrefixRoundTrip(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "prefix@example.com")
+	source := createTestSource(t, db, userID, "Prefix")
+
+	got, err := db.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get source: %v", err)
+	}
+	if got.UIDPrefix != "" {
+		t.Errorf("expected empty UIDPrefix by default, got %q", got.UIDPrefix)
+	}
+
+	got.UIDPrefix = "work"
+	if err := db.UpdateSource(got); err != nil {
+		t.Fatalf("failed to update source: %v", err)
+	}
+	got, err = db.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get source: %v", err)
+	}
+	if got.UIDPrefix != "work" {
+		t.Errorf("expected UIDPrefix %q, got %q", "work", got.UIDPrefix)
+	}
+}
+
+func TestSourceTargetTimezoneRoundTrip(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "tz@example.com")
+	source := createTestSource(t, db, userID, "Timezone")
+
+	got, err := db.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get source: %v", err)
+	}
+	if got.TargetTimezone != "" {
+		t.Errorf("expected empty TargetTimezone by default, got %q", got.TargetTimezone)
+	}
+
+	got.TargetTimezone = "America/New_York"
+	if err := db.UpdateSource(got); err != nil {
+		t.Fatalf("failed to update source: %v", err)
+	}
+	got, err = db.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get source: %v", err)
+	}
+	if got.TargetTimezone != "America/New_York" {
+		t.Errorf("expected TargetTimezone %q, got %q", "America/New_York", got.TargetTimezone)
+	}
+}
+
+func TestRecordEventFailure(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "failures@example.com")
+	source := createTestSource(t, db, userID, "Failures")
+
+	record := func(uid, msg string, after int) *FailedEvent {
+		t.Helper()
+		f, err := db.RecordEventFailure(source.ID, "/cal/", uid, msg, after)
+		if err != nil {
+			t.Fatalf("RecordEventFailure: %v", err)
+		}
+		return f
+	}
+
+	t.Run("dead-letters after K consecutive failures", func(t *testing.T) {
+		for i := 1; i <= 2; i++ {
+			if f := record("stuck", "403 Forbidden", 3); f.FailureCount != i || f.DeadLettered {
+				t.Fatalf("failure %d: got count %d, dead-lettered %v", i, f.FailureCount, f.DeadLettered)
+			}
+		}
+		f := record("stuck", "413 Payload Too Large", 3)
+		if f.FailureCount != 3 || !f.DeadLettered || f.LastError != "413 Payload Too Large" {
+			t.Errorf("expected dead-lettered on the third failure with the latest error, got %+v", f)
+		}
+
+		dead, err := db.GetDeadLetteredEvents(source.ID)
+		if err != nil {
+			t.Fatalf("GetDeadLetteredEvents: %v", err)
+		}
+		if len(dead) != 1 || dead[0].EventUID != "stuck" {
+			t.Errorf("expected stuck dead-lettered, got %+v", dead)
+		}
+	})
+
+	t.Run("success resets the count", func(t *testing.T) {
+		record("flaky", "timeout", 3)
+		record("flaky", "timeout", 3)
+		if err := db.ClearEventFailure(source.ID, "/cal/", "flaky"); err != nil {
+			t.Fatalf("ClearEventFailure: %v", err)
+		}
+		if f := record("flaky", "timeout", 3); f.FailureCount != 1 || f.DeadLettered {
+			t.Errorf("expected the count to start over, got %+v", f)
+		}
+		failed, err := db.GetFailedEvents(source.ID, "/cal/")
+		if err != nil {
+			t.Fatalf("GetFailedEvents: %v", err)
+		}
+		if len(failed) != 2 || failed["flaky"].FailureCount != 1 || !failed["stuck"].DeadLettered {
+			t.Errorf("unexpected failed events %+v", failed)
+		}
+	})
+
+	t.Run("zero threshold never dead-letters", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			if f := record("tolerated", "500", 0); f.DeadLettered {
+				t.Fatalf("expected no dead letter with threshold 0, got %+v", f)
+			}
+		}
+	})
+
+	t.Run("retry releases dead-lettered events", func(t *testing.T) {
+		n, err := db.RetryDeadLetteredEvents(source.ID, "/cal/", "flaky")
+		if err != nil || n != 0 {
+			t.Errorf("expected a live failure not to be released, got %d, %v", n, err)
+		}
+		n, err = db.RetryDeadLetteredEvents(source.ID, "", "")
+		if err != nil || n != 1 {
+			t.Errorf("expected one event released, got %d, %v", n, err)
+		}
+		if dead, _ := db.GetDeadLetteredEvents(source.ID); len(dead) != 0 {
+			t.Errorf("expected no dead letters left, got %+v", dead)
+		}
+	})
+}
+
+func TestRecordDeletionCandidates(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "candidates@example.com")
+	source := createTestSource(t, db, userID, "Candidates")
+
+	counts, err := db.RecordDeletionCandidates(source.ID, "/cal/", "https://dest.example.com/", DeletionTargetDest, []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("failed to record candidates: %v", err)
+	}
+	if counts["a"] != 1 || counts["b"] != 1 {
+		t.Errorf("expected first-cycle counts of 1, got %v", counts)
+	}
+
+	// "b" reappeared: its counter must reset rather than carry over.
+	counts, err = db.RecordDeletionCandidates(source.ID, "/cal/", "https://dest.example.com/", DeletionTargetDest, []string{"a"})
+	if err != nil {
+		t.Fatalf("failed to record candidates: %v", err)
+	}
+	if counts["a"] != 2 {
+		t.Errorf("expected count 2 for a, got %d", counts["a"])
+	}
+	counts, err = db.RecordDeletionCandidates(source.ID, "/cal/", "https://dest.example.com/", DeletionTargetDest, []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("failed to record candidates: %v", err)
+	}
+	if counts["a"] != 3 || counts["b"] != 1 {
+		t.Errorf("expected a=3 b=1, got %v", counts)
+	}
+
+	// Targets are tracked independently.
+	counts, err = db.RecordDeletionCandidates(source.ID, "/cal/", "https://dest.example.com/", DeletionTargetSource, []string{"a"})
+	if err != nil {
+		t.Fatalf("failed to record candidates: %v", err)
+	}
+	if counts["a"] != 1 {
+		t.Errorf("expected source-target count 1 for a, got %d", counts["a"])
+	}
+
+	// So are destinations: another destination's list neither resets
+	// nor bumps this one's counts.
+	if _, err := db.RecordDeletionCandidates(source.ID, "/cal/", "https://other.example.com/", DeletionTargetDest, nil); err != nil {
+		t.Fatalf("failed to record candidates: %v", err)
+	}
+	counts, err = db.RecordDeletionCandidates(source.ID, "/cal/", "https://dest.example.com/", DeletionTargetDest, []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("failed to record candidates: %v", err)
+	}
+	if counts["a"] != 4 || counts["b"] != 2 {
+		t.Errorf("expected a=4 b=2 despite the other destination, got %v", counts)
+	}
+
+	// An empty list clears every candidate for the target.
+	if _, err := db.RecordDeletionCandidates(source.ID, "/cal/", "https://dest.example.com/", DeletionTargetDest, nil); err != nil {
+		t.Fatalf("failed to clear candidates: %v", err)
+	}
+	counts, err = db.RecordDeletionCandidates(source.ID, "/cal/", "https://dest.example.com/", DeletionTargetDest, []string{"a"})
+	if err != nil {
+		t.Fatalf("failed to record candidates: %v", err)
+	}
+	if counts["a"] != 1 {
+		t.Errorf("expected count reset to 1 after clear, got %d", counts["a"])
+	}
+}
+
+func TestDeletedEventBackups(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "backups@example.com")
+	source := createTestSource(t, db, userID, "Backups")
+
+	backup := func(batch, key string, expiresIn time.Duration) {
+		t.Helper()
+		if err := db.CreateDeletedEventBackup(&DeletedEventBackup{
+			SourceID:         source.ID,
+			BatchID:          batch,
+			CalendarHref:     "/cal/",
+			DestURL:          "https://dest.example.com/",
+			DestCalendarPath: "/dest/",
+			EventPath:        "/dest/" + key + ".ics",
+			EventKey:         key,
+			Data:             "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n",
+			ExpiresAt:        time.Now().Add(expiresIn),
+		}); err != nil {
+			t.Fatalf("failed to create backup: %v", err)
+		}
+	}
+
+	latest, err := db.GetLatestDeletedEventBackups(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get backups: %v", err)
+	}
+	if len(latest) != 0 {
+		t.Fatalf("expected no backups, got %d", len(latest))
+	}
+
+	backup("first", "a", time.Hour)
+	backup("second", "b", time.Hour)
+	backup("second", "c", time.Hour)
+
+	latest, err = db.GetLatestDeletedEventBackups(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get backups: %v", err)
+	}
+	if len(latest) != 2 || latest[0].EventKey != "b" || latest[1].EventKey != "c" || latest[0].Data == "" {
+		t.Fatalf("expected the second batch (b, c), got %+v", latest)
+	}
+
+	// Once the latest batch is restored, the one before it is next.
+	for _, b := range latest {
+		if err := db.DeleteDeletedEventBackup(b.ID); err != nil {
+			t.Fatalf("failed to delete backup: %v", err)
+		}
+	}
+	latest, err = db.GetLatestDeletedEventBackups(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get backups: %v", err)
+	}
+	if len(latest) != 1 || latest[0].EventKey != "a" {
+		t.Fatalf("expected the first batch, got %+v", latest)
+	}
+
+	// Expired backups are neither returned nor kept.
+	backup("expired", "d", -time.Minute)
+	latest, err = db.GetLatestDeletedEventBackups(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get backups: %v", err)
+	}
+	if len(latest) != 1 || latest[0].EventKey != "a" {
+		t.Errorf("expected the expired batch to be skipped, got %+v", latest)
+	}
+	purged, err := db.CleanExpiredDeletedEventBackups(time.Now())
+	if err != nil {
+		t.Fatalf("failed to clean backups: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("expected 1 expired backup purged, got %d", purged)
+	}
+}
+
+func TestUpdateSourceSelectedCalendars(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	userID := createTestUser(t, db, "selection@example.com")
+	source := createTestSource(t, db, userID, "Selection")
+
+	selection := []CalendarConfig{
+		{Path: "/cal/work/", Name: "Work", SyncDirection: SyncDirectionTwoWay},
+		{Path: "/cal/home/", Name: "Home"},
+	}
+	if err := db.UpdateSourceSelectedCalendars(source.ID, selection); err != nil {
+		t.Fatalf("failed to update selection: %v", err)
+	}
+	got, err := db.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to get source: %v", err)
+	}
+	if len(got.SelectedCalendars) != 2 || got.SelectedCalendars[0] != selection[0] || got.SelectedCalendars[1] != selection[1] {
+		t.Errorf("expected %+v, got %+v", selection, got.SelectedCalendars)
+	}
+	if got.Name != source.Name || got.SourceURL != source.SourceURL {
+		t.Error("expected the rest of the source to be left alone")
+	}
+
+	if err := db.UpdateSourceSelectedCalendars("missing", selection); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an unknown source, got %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// emailLayout is the HTML alert body. Each alert type supplies its own
+// "intro" block; see emailTemplates. Every field is escaped by
+// html/template, so source names and server messages cannot inject
+// markup.
+const emailLayout = `<!DOCTYPE html>
+<html>
+<body style="margin:0;padding:24px;background:#f4f5f7;font-family:-apple-system,Segoe UI,Helvetica,Arial,sans-serif;color:#1f2933;">
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0" style="max-width:560px;margin:0 auto;background:#ffffff;border-radius:8px;border-top:4px solid {{.Color}};">
+<tr><td style="padding:24px;">
+<h2 style="margin:0 0 12px;font-size:18px;">{{.Title}}</h2>
+{{template "intro" .}}
+<table role="presentation" cellpadding="4" cellspacing="0" style="font-size:14px;margin:16px 0;">
+<tr><td style="color:#616e7c;">Source</td><td>{{.SourceName}}</td></tr>
+<tr><td style="color:#616e7c;">Status</td><td style="color:{{.Color}};font-weight:bold;">{{.Status}}</td></tr>
+<tr><td style="color:#616e7c;">Time</td><td>{{.Time}}</td></tr>
+<tr><td style="color:#616e7c;vertical-align:top;">Message</td><td>{{.Message}}</td></tr>
+{{if .Details}}<tr><td style="color:#616e7c;vertical-align:top;">Details</td><td>{{.Details}}</td></tr>{{end}}
+</table>
+{{if .Link}}<p><a href="{{.Link}}" style="display:inline-block;padding:8px 16px;background:#2563eb;color:#ffffff;text-decoration:none;border-radius:4px;">View sync logs</a></p>{{end}}
+<p style="font-size:12px;color:#9aa5b1;margin-top:24px;">Sent by CalBridgeSync</p>
+</td></tr>
+</table>
+</body>
+</html>
+`
+
+// emailIntros holds the per-type opening paragraph of the HTML body.
+var emailIntros = map[AlertType]string{
+	AlertTypeStale:    `<p><strong>{{.SourceName}}</strong> has not synced successfully within its expected window.</p>`,
+	AlertTypeRecovery: `<p><strong>{{.SourceName}}</strong> is syncing normally again.</p>`,
+	AlertTypeError:    `<p>The last sync of <strong>{{.SourceName}}</strong> failed.</p>`,
+}
+
+const defaultEmailIntro = `<p>An alert was raised for <strong>{{.SourceName}}</strong>.</p>`
+
+// emailTemplates is the layout combined with each type's intro, plus
+// a fallback under the empty type.
+var emailTemplates = func() map[AlertType]*template.Template {
+	base := template.Must(template.New("email").Parse(emailLayout))
+	templates := make(map[AlertType]*template.Template, len(emailIntros)+1)
+	for alertType, intro := range emailIntros {
+		templates[alertType] = template.Must(template.Must(base.Clone()).Parse(`{{define "intro"}}` + intro + `{{end}}`))
+	}
+	templates[""] = template.Must(template.Must(base.Clone()).Parse(`{{define "intro"}}` + defaultEmailIntro + `{{end}}`))
+	return templates
+}()
+
+// emailView is the data both email bodies render. Text fields are
+// already passed through sanitizeForEmail.
+type emailView struct {
+	Title      string
+	Status     string
+	Color      string
+	SourceName string
+	SourceID   string
+	Time       string
+	Message    string
+	Details    string
+	Link       string
+}
+
+func newEmailView(alert Alert, dashboardURL string) emailView {
+	view := emailView{
+		Title:      "CalBridgeSync alert",
+		Status:     string(alert.Type),
+		Color:      fmt.Sprintf("#%06x", alertColor(alert.Type)),
+		SourceName: sanitizeForEmail(alert.SourceName),
+		SourceID:   alert.SourceID,
+		Time:       alert.Timestamp.Format(time.RFC1123),
+		Message:    sanitizeForEmail(alert.Message),
+		Details:    sanitizeForEmail(alert.Details),
+	}
+	switch alert.Type {
+	case AlertTypeStale:
+		view.Title, view.Status = "Calendar sync is stale", "Stale"
+	case AlertTypeRecovery:
+		view.Title, view.Status = "Calendar sync recovered", "Recovered"
+	case AlertTypeError:
+		view.Title, view.Status = "Calendar sync failed", "Failed"
+	}
+	if dashboardURL != "" && alert.SourceID != "" {
+		view.Link = strings.TrimRight(dashboardURL, "/") + "/sources/" + url.PathEscape(alert.SourceID) + "/logs"
+	}
+	return view
+}
+
+// plainText renders the text/plain body, which is the whole message
+// when HTML is off and the fallback part when it is on.
+func (v emailView) plainText(alertType AlertType) string {
+	var body strings.Builder
+	body.WriteString(fmt.Sprintf("Alert Type: %s\n", alertType))
+	body.WriteString(fmt.Sprintf("Source: %s\n", v.SourceName))
+	body.WriteString(fmt.Sprintf("Source ID: %s\n", v.SourceID))
+	body.WriteString(fmt.Sprintf("Time: %s\n\n", v.Time))
+	body.WriteString(fmt.Sprintf("Message: %s\n", v.Message))
+	body.WriteString(fmt.Sprintf("Details: %s\n", v.Details))
+	if v.Link != "" {
+		body.WriteString(fmt.Sprintf("\nView sync logs: %s\n", v.Link))
+	}
+	return body.String()
+}
+
+// buildEmailMessage renders the full RFC 5322 message for alert: plain
+// text only, or multipart/alternative with an HTML part when
+// Config.EmailHTML is set.
+func buildEmailMessage(cfg *Config, alert Alert, recipients []string) ([]byte, error) {
+	view := newEmailView(alert, cfg.DashboardURL)
+	text := view.plainText(alert.Type)
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\nTo: %s\r\nSubject: [CalBridgeSync] %s\r\nMIME-Version: 1.0\r\n",
+		cfg.SMTPFrom, strings.Join(recipients, ", "), view.Message)
+
+	if !cfg.EmailHTML {
+		fmt.Fprintf(&msg, "Content-Type: text/plain; charset=UTF-8\r\n\r\n%s", text)
+		return msg.Bytes(), nil
+	}
+
+	tmpl, ok := emailTemplates[alert.Type]
+	if !ok {
+		tmpl = emailTemplates[""]
+	}
+	var html bytes.Buffer
+	if err := tmpl.Execute(&html, view); err != nil {
+		return nil, fmt.Errorf("render HTML email: %w", err)
+	}
+
+	var parts bytes.Buffer
+	mw := multipart.NewWriter(&parts)
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", mw.Boundary())
+	// Plain text first: clients show the last part they can render.
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=UTF-8", text},
+		{"text/html; charset=UTF-8", html.String()},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	msg.Write(parts.Bytes())
+	return msg.Bytes(), nil
+}
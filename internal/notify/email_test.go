@@ -0,0 +1,130 @@
+package notify
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parseEmail splits a built message into its headers and its decoded
+// parts, keyed by media type.
+func parseEmail(t *testing.T, raw []byte) (mail.Header, map[string]string) {
+	t.Helper()
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v\n%s", err, raw)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("ParseMediaType: %v", err)
+	}
+	parts := map[string]string{}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		body, _ := io.ReadAll(msg.Body)
+		parts[mediaType] = string(body)
+		return msg.Header, parts
+	}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := mr.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextRawPart: %v", err)
+		}
+		partType, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		var r io.Reader = p
+		if p.Header.Get("Content-Transfer-Encoding") == "quoted-printable" {
+			r = quotedprintable.NewReader(p)
+		}
+		body, _ := io.ReadAll(r)
+		parts[partType] = string(body)
+	}
+	return msg.Header, parts
+}
+
+func TestBuildEmailMessage(t *testing.T) {
+	alert := Alert{
+		Type:       AlertTypeError,
+		SourceID:   "src-1",
+		SourceName: `<script>alert("x")</script> & Co`,
+		Message:    "Sync failed\r\nBcc: victim@example.com",
+		Details:    `<img src=x onerror="steal()">`,
+		Timestamp:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	t.Run("HTML alerts carry a plain-text and an HTML part", func(t *testing.T) {
+		n := New(&Config{SMTPFrom: "alerts@example.com", EmailHTML: true, DashboardURL: "https://sync.example.com/"})
+		raw, err := buildEmailMessage(n.config(), alert, []string{"owner@example.com"})
+		if err != nil {
+			t.Fatalf("buildEmailMessage: %v", err)
+		}
+		header, parts := parseEmail(t, raw)
+		if !strings.HasPrefix(header.Get("Content-Type"), "multipart/alternative") {
+			t.Fatalf("expected multipart/alternative, got %q", header.Get("Content-Type"))
+		}
+		if header.Get("Bcc") != "" || strings.Contains(header.Get("Subject"), "\n") {
+			t.Errorf("header injection got through: %v", header)
+		}
+
+		text, html := parts["text/plain"], parts["text/html"]
+		if !strings.Contains(text, `Source: <script>alert("x")</script> & Co`) {
+			t.Errorf("expected the raw source name in the text part:\n%s", text)
+		}
+		if !strings.Contains(text, "https://sync.example.com/sources/src-1/logs") ||
+			!strings.Contains(html, `href="https://sync.example.com/sources/src-1/logs"`) {
+			t.Errorf("expected a link to the source's logs in both parts")
+		}
+		if !strings.Contains(html, "Calendar sync failed") || !strings.Contains(html, "Failed") {
+			t.Errorf("expected the error template in the HTML part:\n%s", html)
+		}
+		for _, raw := range []string{"<script>", "<img", `onerror="`, "ZgotmplZ"} {
+			if strings.Contains(html, raw) {
+				t.Errorf("HTML part contains unescaped %q:\n%s", raw, html)
+			}
+		}
+		if !strings.Contains(html, "&lt;script&gt;") || !strings.Contains(html, "&amp; Co") {
+			t.Errorf("expected the source name escaped in the HTML part:\n%s", html)
+		}
+	})
+
+	t.Run("each alert type has its own template", func(t *testing.T) {
+		n := New(&Config{EmailHTML: true})
+		for alertType, want := range map[AlertType]string{
+			AlertTypeStale:    "Calendar sync is stale",
+			AlertTypeRecovery: "Calendar sync recovered",
+			AlertTypeError:    "Calendar sync failed",
+			"other":           "An alert was raised",
+		} {
+			raw, err := buildEmailMessage(n.config(), Alert{Type: alertType, SourceName: "Work"}, []string{"owner@example.com"})
+			if err != nil {
+				t.Fatalf("%s: buildEmailMessage: %v", alertType, err)
+			}
+			if _, parts := parseEmail(t, raw); !strings.Contains(parts["text/html"], want) {
+				t.Errorf("%s: expected %q in the HTML part", alertType, want)
+			}
+		}
+	})
+
+	t.Run("HTML off sends plain text only", func(t *testing.T) {
+		n := New(&Config{SMTPFrom: "alerts@example.com"})
+		raw, err := buildEmailMessage(n.config(), alert, []string{"owner@example.com"})
+		if err != nil {
+			t.Fatalf("buildEmailMessage: %v", err)
+		}
+		header, parts := parseEmail(t, raw)
+		if !strings.HasPrefix(header.Get("Content-Type"), "text/plain") || len(parts) != 1 {
+			t.Fatalf("expected a single text/plain body, got %q with %d parts", header.Get("Content-Type"), len(parts))
+		}
+		if strings.Contains(parts["text/plain"], "View sync logs") {
+			t.Error("expected no link without a dashboard URL")
+		}
+	})
+}
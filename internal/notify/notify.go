@@ -47,6 +47,9 @@ type Config struct {
 	// Webhook settings
 	WebhookEnabled bool
 	WebhookURL     string
+	// WebhookSecret, when set, signs every webhook body; see
+	// SignatureHeader.
+	WebhookSecret string
 
 	// Email settings
 	EmailEnabled bool
@@ -57,6 +60,11 @@ type Config struct {
 	SMTPFrom     string
 	SMTPTo       []string // Recipients
 	SMTPTLS      bool
+	// EmailHTML sends alerts as multipart/alternative with an HTML part
+	// next to the plain text. DashboardURL, when set, is linked from
+	// both parts.
+	EmailHTML    bool
+	DashboardURL string
 
 	// Alert settings
 	CooldownPeriod time.Duration // How long to wait before re-alerting for same source
@@ -76,10 +84,36 @@ type UserPreferences struct {
 	WebhookEnabled  *bool
 	WebhookURL      string // Empty = no personal webhook
 	CooldownMinutes *int
+	AlertOnPartial  bool // Escalate partial syncs to failure alerts
+}
+
+// ForSource applies a source's channel overrides to the user's
+// preferences, giving the precedence source → user → global: a
+// non-nil override wins, otherwise the user's setting (and failing
+// that the global one) applies. p may be nil. The result is a copy;
+// p is not modified.
+func (p *UserPreferences) ForSource(emailEnabled, webhookEnabled *bool) *UserPreferences {
+	if emailEnabled == nil && webhookEnabled == nil {
+		return p
+	}
+	prefs := &UserPreferences{}
+	if p != nil {
+		*prefs = *p
+	}
+	if emailEnabled != nil {
+		prefs.EmailEnabled = emailEnabled
+	}
+	if webhookEnabled != nil {
+		prefs.WebhookEnabled = webhookEnabled
+	}
+	return prefs
 }
 
 // Notifier sends alert notifications.
 type Notifier struct {
+	// cfg is replaced wholesale by UpdateConfig and never modified in
+	// place, so a pointer read under cfgMu is a consistent snapshot.
+	cfgMu      sync.RWMutex
 	cfg        *Config
 	httpClient *http.Client
 
@@ -221,6 +255,30 @@ func New(cfg *Config) *Notifier {
 	}
 }
 
+// config returns the notifier's current configuration.
+func (n *Notifier) config() *Config {
+	n.cfgMu.RLock()
+	defer n.cfgMu.RUnlock()
+	return n.cfg
+}
+
+// UpdateConfig replaces the notifier's configuration at runtime, e.g.
+// after SMTP or webhook settings change. cfg is validated first and
+// rejected without touching the live configuration if it is invalid.
+// Cooldown, stale and in-flight state is kept, so a reload neither
+// re-sends alerts nor forgets that a source is stale. Sends already
+// under way finish with the configuration they started with.
+func (n *Notifier) UpdateConfig(cfg *Config) error {
+	if err := ValidateConfig(cfg); err != nil {
+		return err
+	}
+	updated := *cfg
+	n.cfgMu.Lock()
+	n.cfg = &updated
+	n.cfgMu.Unlock()
+	return nil
+}
+
 // ValidateConfig validates the notification configuration.
 // Returns an error if the configuration is invalid.
 func ValidateConfig(cfg *Config) error {
@@ -373,7 +431,8 @@ func sanitizeForEmail(s string) string {
 
 // IsEnabled returns true if any notification method is enabled.
 func (n *Notifier) IsEnabled() bool {
-	return n.cfg.WebhookEnabled || n.cfg.EmailEnabled
+	cfg := n.config()
+	return cfg.WebhookEnabled || cfg.EmailEnabled
 }
 
 // maxSendAttempts returns the configured retry count for this notifier,
@@ -381,8 +440,8 @@ func (n *Notifier) IsEnabled() bool {
 // zero. Issue #64 allows operators to tune this via
 // ALERT_MAX_SEND_ATTEMPTS env var.
 func (n *Notifier) maxSendAttempts() int {
-	if n.cfg.MaxSendAttempts > 0 {
-		return n.cfg.MaxSendAttempts
+	if attempts := n.config().MaxSendAttempts; attempts > 0 {
+		return attempts
 	}
 	return defaultMaxSendAttempts
 }
@@ -392,8 +451,8 @@ func (n *Notifier) maxSendAttempts() int {
 // is zero. Issue #64 allows operators to tune this via
 // ALERT_INITIAL_BACKOFF_MS env var.
 func (n *Notifier) initialBackoff() time.Duration {
-	if n.cfg.InitialBackoff > 0 {
-		return n.cfg.InitialBackoff
+	if backoff := n.config().InitialBackoff; backoff > 0 {
+		return backoff
 	}
 	return defaultInitialBackoff
 }
@@ -567,7 +626,8 @@ func buildWebhookPayload(alert Alert, webhookURL string) ([]byte, error) {
 }
 
 func (n *Notifier) sendWebhook(ctx context.Context, alert Alert) error {
-	body, err := buildWebhookPayload(alert, n.cfg.WebhookURL)
+	webhookURL := n.config().WebhookURL
+	body, err := buildWebhookPayload(alert, webhookURL)
 	if err != nil {
 		return fmt.Errorf("marshal payload: %w", err)
 	}
@@ -576,11 +636,12 @@ func (n *Notifier) sendWebhook(ctx context.Context, alert Alert) error {
 	// 5xx). Permanent errors (4xx, request construction) fall through
 	// immediately — see isTransientHTTPError for the full taxonomy.
 	return retryTransient(ctx, n.maxSendAttempts(), n.initialBackoff(), func(ctx context.Context) error {
-		req, err := http.NewRequestWithContext(ctx, "POST", n.cfg.WebhookURL, bytes.NewReader(body))
+		req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewReader(body))
 		if err != nil {
 			return fmt.Errorf("create request: %w", err)
 		}
 		req.Header.Set("Content-Type", "application/json")
+		n.signWebhook(req, body)
 
 		resp, err := n.httpClient.Do(req)
 		if err != nil {
@@ -606,33 +667,21 @@ func (n *Notifier) sendWebhook(ctx context.Context, alert Alert) error {
 }
 
 func (n *Notifier) sendEmail(ctx context.Context, alert Alert, recipients []string) error {
-	// Sanitize user-controlled inputs to prevent email header injection.
-	// This is idempotent setup and stays outside the retry.
-	sanitizedSourceName := sanitizeForEmail(alert.SourceName)
+	// Building the message sanitizes user-controlled inputs against
+	// header injection. This is idempotent setup and stays outside
+	// the retry.
+	cfg := n.config()
+	msg, err := buildEmailMessage(cfg, alert, recipients)
+	if err != nil {
+		return err
+	}
 	sanitizedMessage := sanitizeForEmail(alert.Message)
-	sanitizedDetails := sanitizeForEmail(alert.Details)
-
-	subject := fmt.Sprintf("[CalBridgeSync] %s", sanitizedMessage)
-
-	// Build email body
-	var body strings.Builder
-	body.WriteString(fmt.Sprintf("Alert Type: %s\n", alert.Type))
-	body.WriteString(fmt.Sprintf("Source: %s\n", sanitizedSourceName))
-	body.WriteString(fmt.Sprintf("Source ID: %s\n", alert.SourceID))
-	body.WriteString(fmt.Sprintf("Time: %s\n\n", alert.Timestamp.Format(time.RFC1123)))
-	body.WriteString(fmt.Sprintf("Message: %s\n", sanitizedMessage))
-	body.WriteString(fmt.Sprintf("Details: %s\n", sanitizedDetails))
-
-	// Build email message with proper MIME headers
-	to := strings.Join(recipients, ", ")
-	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s",
-		n.cfg.SMTPFrom, to, subject, body.String())
 
-	addr := fmt.Sprintf("%s:%d", n.cfg.SMTPHost, n.cfg.SMTPPort)
+	addr := fmt.Sprintf("%s:%d", cfg.SMTPHost, cfg.SMTPPort)
 
 	var auth smtp.Auth
-	if n.cfg.SMTPUsername != "" {
-		auth = smtp.PlainAuth("", n.cfg.SMTPUsername, n.cfg.SMTPPassword, n.cfg.SMTPHost)
+	if cfg.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
 	}
 
 	// Retry transient SMTP failures. SMTP errors are mostly transient
@@ -648,10 +697,10 @@ func (n *Notifier) sendEmail(ctx context.Context, alert Alert, recipients []stri
 	// attempts, not the send in progress.
 	return retryTransient(ctx, n.maxSendAttempts(), n.initialBackoff(), func(ctx context.Context) error {
 		var err error
-		if n.cfg.SMTPTLS {
-			err = n.sendEmailTLS(addr, auth, n.cfg.SMTPFrom, recipients, []byte(msg))
+		if cfg.SMTPTLS {
+			err = sendEmailTLS(cfg.SMTPHost, addr, auth, cfg.SMTPFrom, recipients, msg)
 		} else {
-			err = smtp.SendMail(addr, auth, n.cfg.SMTPFrom, recipients, []byte(msg))
+			err = smtp.SendMail(addr, auth, cfg.SMTPFrom, recipients, msg)
 		}
 		if err != nil {
 			return fmt.Errorf("send email: %w", err)
@@ -662,9 +711,9 @@ func (n *Notifier) sendEmail(ctx context.Context, alert Alert, recipients []stri
 }
 
 // sendEmailTLS sends email over TLS (for port 465).
-func (n *Notifier) sendEmailTLS(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+func sendEmailTLS(host, addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
 	tlsConfig := &tls.Config{
-		ServerName: n.cfg.SMTPHost,
+		ServerName: host,
 		MinVersion: tls.VersionTLS12, // Require TLS 1.2 or higher for security
 	}
 
@@ -674,7 +723,7 @@ func (n *Notifier) sendEmailTLS(addr string, auth smtp.Auth, from string, to []s
 	}
 	defer conn.Close()
 
-	client, err := smtp.NewClient(conn, n.cfg.SMTPHost)
+	client, err := smtp.NewClient(conn, host)
 	if err != nil {
 		return fmt.Errorf("create SMTP client: %w", err)
 	}
@@ -856,7 +905,7 @@ func (n *Notifier) getCooldownPeriod(userPrefs *UserPreferences) time.Duration {
 	if userPrefs != nil && userPrefs.CooldownMinutes != nil {
 		return time.Duration(*userPrefs.CooldownMinutes) * time.Minute
 	}
-	return n.cfg.CooldownPeriod
+	return n.config().CooldownPeriod
 }
 
 // SendSyncFailureAlertWithPrefs sends an alert when a sync fails or when a
@@ -982,17 +1031,18 @@ func IsDangerousWarning(w string) bool {
 // "alert delivered, start the cooldown" from "alert attempted but bounced,
 // please retry on the next tick".
 func (n *Notifier) sendWithPrefs(ctx context.Context, alert Alert, userPrefs *UserPreferences) bool {
+	cfg := n.config()
 	anyAttempted := false
 	anyDelivered := false
 
 	// Determine if webhook is enabled (user pref overrides global)
-	webhookEnabled := n.cfg.WebhookEnabled
+	webhookEnabled := cfg.WebhookEnabled
 	if userPrefs != nil && userPrefs.WebhookEnabled != nil {
 		webhookEnabled = *userPrefs.WebhookEnabled
 	}
 
 	// Send to global webhook if enabled
-	if webhookEnabled && n.cfg.WebhookURL != "" {
+	if webhookEnabled && cfg.WebhookURL != "" {
 		anyAttempted = true
 		if err := n.sendWebhook(ctx, alert); err != nil {
 			log.Printf("[Notify] Webhook error: %v", err)
@@ -1018,7 +1068,7 @@ func (n *Notifier) sendWithPrefs(ctx context.Context, alert Alert, userPrefs *Us
 	}
 
 	// Determine if email is enabled (user pref overrides global)
-	emailEnabled := n.cfg.EmailEnabled
+	emailEnabled := cfg.EmailEnabled
 	if userPrefs != nil && userPrefs.EmailEnabled != nil {
 		emailEnabled = *userPrefs.EmailEnabled
 	}
@@ -1053,7 +1103,7 @@ func (n *Notifier) sendWithPrefs(ctx context.Context, alert Alert, userPrefs *Us
 		}
 
 		addIfValid(alert.UserEmail)
-		for _, email := range n.cfg.SMTPTo {
+		for _, email := range cfg.SMTPTo {
 			addIfValid(email)
 		}
 
@@ -1104,6 +1154,7 @@ func (n *Notifier) sendWebhookToURL(ctx context.Context, alert Alert, webhookURL
 			return fmt.Errorf("create request: %w", err)
 		}
 		req.Header.Set("Content-Type", "application/json")
+		n.signWebhook(req, body)
 
 		resp, err := n.httpClient.Do(req)
 		if err != nil {
@@ -1156,6 +1207,7 @@ func (n *Notifier) SendTestWebhook(ctx context.Context, webhookURL string) error
 		return fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	n.signWebhook(req, body)
 
 	resp, err := n.httpClient.Do(req)
 	if err != nil {
@@ -2,8 +2,11 @@ package notify
 
 import (
 	"context"
+	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 )
@@ -916,3 +919,154 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+// TestUpdateConfigChangesSendBehavior verifies that a reloaded webhook
+// URL is used by the next send while cooldown and stale tracking from
+// before the reload are kept. UpdateConfig validates like production,
+// so the URLs are public HTTPS hostnames; the client dials the test
+// server for both and the handler tells them apart by Host.
+func TestUpdateConfigChangesSendBehavior(t *testing.T) {
+	hosts := make(chan string, 4)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hosts <- r.Host
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := New(&Config{WebhookEnabled: true, WebhookURL: "https://old.example.com/hook", CooldownPeriod: time.Hour})
+	transport := server.Client().Transport.(*http.Transport).Clone()
+	transport.TLSClientConfig.InsecureSkipVerify = true
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	}
+	n.httpClient = &http.Client{Transport: transport, Timeout: 5 * time.Second}
+
+	n.mu.Lock()
+	n.lastAlertTimes["stale-source"] = time.Now()
+	n.staleState["stale-source"] = true
+	n.mu.Unlock()
+
+	if err := n.UpdateConfig(&Config{WebhookEnabled: true, WebhookURL: "https://new.example.com/hook", CooldownPeriod: time.Hour}); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	if !n.SendSyncFailureAlertWithPrefs(context.Background(), "source1", "Test Source", "user@example.com",
+		"Sync failed", "after reload", nil) {
+		t.Fatal("alert should be queued")
+	}
+	waitForDrain(t, n)
+
+	select {
+	case got := <-hosts:
+		if got != "new.example.com" {
+			t.Errorf("expected the reloaded webhook to receive the alert, got %q", got)
+		}
+	default:
+		t.Fatal("expected a webhook delivery")
+	}
+
+	n.mu.RLock()
+	_, cooldownKept := n.lastAlertTimes["stale-source"]
+	staleKept := n.staleState["stale-source"]
+	n.mu.RUnlock()
+	if !cooldownKept || !staleKept {
+		t.Error("expected cooldown and stale state to survive the reload")
+	}
+}
+
+func TestUpdateConfigRejectsInvalidConfig(t *testing.T) {
+	original := &Config{WebhookEnabled: true, WebhookURL: "https://hooks.example.com/a", CooldownPeriod: time.Hour}
+	n := New(original)
+
+	invalid := &Config{EmailEnabled: true, SMTPPort: 587, CooldownPeriod: time.Hour}
+	if err := n.UpdateConfig(invalid); err == nil {
+		t.Fatal("expected an email config without a host to be rejected")
+	}
+	if got := n.config(); got.WebhookURL != original.WebhookURL || got.EmailEnabled {
+		t.Errorf("expected the live config to be unchanged, got %+v", got)
+	}
+
+	// The notifier keeps its own copy, so later edits by the caller
+	// don't leak into the live config.
+	valid := &Config{WebhookEnabled: true, WebhookURL: "https://hooks.example.com/b", CooldownPeriod: time.Hour}
+	if err := n.UpdateConfig(valid); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	valid.WebhookURL = "https://hooks.example.com/c"
+	if got := n.config().WebhookURL; got != "https://hooks.example.com/b" {
+		t.Errorf("expected the applied URL to stay put, got %q", got)
+	}
+}
+
+func TestUserPreferencesForSource(t *testing.T) {
+	on, off := true, false
+	tests := []struct {
+		name        string
+		user        *UserPreferences
+		email, hook *bool
+		wantEmail   *bool
+		wantHook    *bool
+	}{
+		{"no overrides keep user prefs", &UserPreferences{EmailEnabled: &on}, nil, nil, &on, nil},
+		{"no overrides and no user prefs", nil, nil, nil, nil, nil},
+		{"source overrides user", &UserPreferences{EmailEnabled: &on, WebhookEnabled: &on}, &off, nil, &off, &on},
+		{"source applies without user prefs", nil, nil, &off, nil, &off},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.user.ForSource(tt.email, tt.hook)
+			if got == nil {
+				if tt.wantEmail != nil || tt.wantHook != nil {
+					t.Fatal("expected preferences, got nil")
+				}
+				return
+			}
+			if !sameBool(got.EmailEnabled, tt.wantEmail) || !sameBool(got.WebhookEnabled, tt.wantHook) {
+				t.Errorf("got email=%v webhook=%v", got.EmailEnabled, got.WebhookEnabled)
+			}
+		})
+	}
+
+	user := &UserPreferences{WebhookEnabled: &on}
+	user.ForSource(nil, &off)
+	if !*user.WebhookEnabled {
+		t.Error("expected ForSource to leave the user's preferences untouched")
+	}
+}
+
+func sameBool(a, b *bool) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+// TestSourceAlertOverrideSkipsOneSource verifies that a source with
+// webhook alerts turned off stays silent while another source of the
+// same user still alerts through the global webhook.
+func TestSourceAlertOverrideSkipsOneSource(t *testing.T) {
+	var mu sync.Mutex
+	var alerted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload WebhookPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		mu.Lock()
+		alerted = append(alerted, payload.SourceID)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := New(&Config{WebhookEnabled: true, WebhookURL: server.URL, CooldownPeriod: time.Hour})
+	on, off := true, false
+	user := &UserPreferences{WebhookEnabled: &on}
+
+	ctx := context.Background()
+	n.SendSyncFailureAlertWithPrefs(ctx, "quiet", "Quiet Source", "user@example.com",
+		"Sync failed for Quiet Source", "", user.ForSource(nil, &off))
+	n.SendSyncFailureAlertWithPrefs(ctx, "loud", "Loud Source", "user@example.com",
+		"Sync failed for Loud Source", "", user.ForSource(nil, nil))
+	waitForDrain(t, n)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(alerted) != 1 || alerted[0] != "loud" {
+		t.Errorf("expected one alert, for the loud source, got %q", alerted)
+	}
+}
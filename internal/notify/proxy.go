@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// SetProxy sends webhooks through proxyURL, an HTTP(S) proxy, except
+// to hosts noProxy matches. noProxy has NO_PROXY syntax:
+// comma-separated hosts, domain suffixes, IPs and CIDRs, or "*" for
+// all. Direct connections keep the private-address dial check. The
+// connection to the proxy itself skips it, since egress proxies
+// usually sit on the internal network; for proxied webhooks the proxy
+// resolves the host, leaving validateWebhookURL and the proxy's own
+// policy as the guard. Wired from OUTBOUND_PROXY_URL; call before the
+// notifier sends anything.
+func (n *Notifier) SetProxy(proxyURL, noProxy string) error {
+	if proxyURL == "" {
+		return nil
+	}
+	u, err := url.Parse(proxyURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("proxy URL must be an http or https URL with a host")
+	}
+	t, ok := n.httpClient.Transport.(*http.Transport)
+	if !ok {
+		return errors.New("webhook client has no configurable transport")
+	}
+
+	proxy := (&httpproxy.Config{HTTPProxy: proxyURL, HTTPSProxy: proxyURL, NoProxy: noProxy}).ProxyFunc()
+	t.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
+
+	proxyAddr := proxyDialAddr(u)
+	dial := t.DialContext
+	t.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if addr == proxyAddr {
+			dialer := &net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}
+			return dialer.DialContext(ctx, network, addr)
+		}
+		return dial(ctx, network, addr)
+	}
+	return nil
+}
+
+// proxyDialAddr returns the address the transport dials to reach the
+// proxy at u: its host with the explicit port or the scheme's default.
+func proxyDialAddr(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestSetProxy_ProxyFunc(t *testing.T) {
+	n := New(&Config{})
+	if err := n.SetProxy("http://proxy.corp.example:3128", "localhost,.corp.example,10.0.0.0/8"); err != nil {
+		t.Fatalf("SetProxy: %v", err)
+	}
+	transport := n.httpClient.Transport.(*http.Transport)
+
+	for _, tt := range []struct {
+		target    string
+		wantProxy bool
+	}{
+		{"https://hooks.slack.com/services/x", true},
+		{"http://alerts.example.com/hook", true},
+		{"https://chat.corp.example/hook", false},
+		{"https://10.1.2.3/hook", false},
+		{"http://localhost:8080/hook", false},
+	} {
+		req, _ := http.NewRequest(http.MethodPost, tt.target, nil)
+		got, err := transport.Proxy(req)
+		if err != nil {
+			t.Fatalf("%s: Proxy: %v", tt.target, err)
+		}
+		switch {
+		case tt.wantProxy && (got == nil || got.Host != "proxy.corp.example:3128"):
+			t.Errorf("%s: expected the configured proxy, got %v", tt.target, got)
+		case !tt.wantProxy && got != nil:
+			t.Errorf("%s: expected a direct connection, got %v", tt.target, got)
+		}
+	}
+}
+
+func TestSetProxy_Invalid(t *testing.T) {
+	for _, proxyURL := range []string{"proxy.corp.example:3128", "socks5://proxy:1080", "http://"} {
+		if err := New(&Config{}).SetProxy(proxyURL, ""); err == nil {
+			t.Errorf("%s: expected an error", proxyURL)
+		}
+	}
+	n := New(&Config{})
+	if err := n.SetProxy("", "anything"); err != nil {
+		t.Errorf("expected no proxy to be accepted, got %v", err)
+	}
+	if n.httpClient.Transport.(*http.Transport).Proxy != nil {
+		t.Error("expected no Proxy function without a proxy URL")
+	}
+}
+
+// TestSetProxy_WebhookGoesThroughProxy posts an alert webhook and
+// checks it reaches the proxy addressed to the webhook's host.
+func TestSetProxy_WebhookGoesThroughProxy(t *testing.T) {
+	requested := make(chan *url.URL, 1)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested <- r.URL
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	n := New(&Config{WebhookEnabled: true, WebhookURL: "http://hooks.example.com/alerts", MaxSendAttempts: 1})
+	if err := n.SetProxy(proxy.URL, ""); err != nil {
+		t.Fatalf("SetProxy: %v", err)
+	}
+	alert := Alert{Type: AlertTypeError, SourceID: "src-1", SourceName: "Work", Message: "Sync failed", Timestamp: time.Now()}
+	if err := n.sendWebhook(context.Background(), alert); err != nil {
+		t.Fatalf("sendWebhook: %v", err)
+	}
+	if got := <-requested; got.Host != "hooks.example.com" || got.Path != "/alerts" {
+		t.Errorf("expected the proxy to be asked for hooks.example.com/alerts, got %v", got)
+	}
+}
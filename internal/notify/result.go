@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// resultWebhookTimeout bounds one result webhook delivery, retries
+// included. The send runs detached from the sync that produced it, so
+// without a deadline a hung endpoint would pin a goroutine forever.
+const resultWebhookTimeout = 2 * time.Minute
+
+// SyncResultPayload is the JSON body POSTed to a source's result
+// webhook after every sync run. Unlike alert webhooks it fires on
+// success too, so users can feed each run into their own dashboards.
+type SyncResultPayload struct {
+	SourceID          string   `json:"source_id"`
+	SourceName        string   `json:"source_name"`
+	Status            string   `json:"status"`
+	Success           bool     `json:"success"`
+	Message           string   `json:"message"`
+	Created           int      `json:"created"`
+	Updated           int      `json:"updated"`
+	Deleted           int      `json:"deleted"`
+	Skipped           int      `json:"skipped"`
+	DuplicatesRemoved int      `json:"duplicates_removed"`
+	CalendarsSynced   int      `json:"calendars_synced"`
+	EventsProcessed   int      `json:"events_processed"`
+	DurationMs        int64    `json:"duration_ms"`
+	Errors            []string `json:"errors"`
+	Warnings          []string `json:"warnings"`
+	Timestamp         string   `json:"timestamp"`
+}
+
+// SendSyncResultWebhook POSTs payload to webhookURL in the background.
+// It returns immediately; delivery failures are logged, never surfaced
+// to the sync that triggered them. Not gated on IsEnabled — the result
+// webhook is configured per source, independent of global alerting.
+func (n *Notifier) SendSyncResultWebhook(webhookURL string, payload SyncResultPayload) {
+	go func() {
+		defer recoverPanic("notify.SendSyncResultWebhook")
+		ctx, cancel := context.WithTimeout(context.Background(), resultWebhookTimeout)
+		defer cancel()
+		if err := n.sendSyncResult(ctx, webhookURL, payload); err != nil {
+			log.Printf("[Notify] Result webhook error for source %s: %v", payload.SourceID, err)
+		}
+	}()
+}
+
+// sendSyncResult validates webhookURL and delivers payload with the
+// notifier's retry policy. The URL was already validated at save time;
+// re-checking here covers rows written before validation existed.
+func (n *Notifier) sendSyncResult(ctx context.Context, webhookURL string, payload SyncResultPayload) error {
+	if err := validateWebhookURL(webhookURL); err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+
+	body, err := marshalSyncResultPayload(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	return n.postJSON(ctx, webhookURL, body)
+}
+
+// marshalSyncResultPayload encodes payload, writing nil errors and
+// warnings as [] so consumers can iterate without a null check.
+func marshalSyncResultPayload(payload SyncResultPayload) ([]byte, error) {
+	if payload.Errors == nil {
+		payload.Errors = []string{}
+	}
+	if payload.Warnings == nil {
+		payload.Warnings = []string{}
+	}
+	return json.Marshal(payload)
+}
+
+// postJSON POSTs body to webhookURL, retrying transient failures and
+// honoring Retry-After on 429. Callers are responsible for URL
+// validation.
+func (n *Notifier) postJSON(ctx context.Context, webhookURL string, body []byte) error {
+	return retryTransient(ctx, n.maxSendAttempts(), n.initialBackoff(), func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		n.signWebhook(req, body)
+
+		resp, err := n.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("send request: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 400 {
+			statusErr := fmt.Errorf("webhook returned status %d", resp.StatusCode)
+			if resp.StatusCode == http.StatusTooManyRequests {
+				return &RetryAfterError{
+					Underlying: statusErr,
+					RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+				}
+			}
+			return statusErr
+		}
+		return nil
+	}, isTransientHTTPError)
+}
@@ -0,0 +1,104 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestPostJSON_SyncResultPayloadShape checks the JSON a result webhook
+// consumer receives: every count field present, and errors/warnings as
+// arrays even when empty.
+func TestPostJSON_SyncResultPayloadShape(t *testing.T) {
+	var gotBody []byte
+	var gotContentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get("Content-Type")
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	n := New(&Config{})
+	payload := SyncResultPayload{
+		SourceID:   "src-1",
+		SourceName: "Work",
+		Status:     "partial",
+		Success:    true,
+		Message:    "Sync completed",
+		Created:    2,
+		Updated:    3,
+		Deleted:    1,
+		DurationMs: 1500,
+		Warnings:   []string{"event x skipped"},
+		Timestamp:  "2026-01-15T12:00:00Z",
+	}
+
+	// sendSyncResult would reject the loopback httptest URL, so marshal
+	// through the same path and post directly.
+	body, err := marshalSyncResultPayload(payload)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if err := n.postJSON(context.Background(), srv.URL, body); err != nil {
+		t.Fatalf("postJSON: %v", err)
+	}
+
+	if gotContentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", gotContentType)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(gotBody, &got); err != nil {
+		t.Fatalf("body is not JSON: %v\n%s", err, gotBody)
+	}
+	for _, key := range []string{
+		"source_id", "source_name", "status", "success", "message",
+		"created", "updated", "deleted", "skipped", "duplicates_removed",
+		"calendars_synced", "events_processed", "duration_ms",
+		"errors", "warnings", "timestamp",
+	} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("payload missing %q", key)
+		}
+	}
+	if got["status"] != "partial" || got["duration_ms"] != float64(1500) || got["created"] != float64(2) {
+		t.Errorf("unexpected payload values: %s", gotBody)
+	}
+	if errs, ok := got["errors"].([]any); !ok || len(errs) != 0 {
+		t.Errorf("errors = %v, want empty array", got["errors"])
+	}
+	if warns, ok := got["warnings"].([]any); !ok || len(warns) != 1 {
+		t.Errorf("warnings = %v, want one entry", got["warnings"])
+	}
+}
+
+func TestPostJSON_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	n := New(&Config{MaxSendAttempts: 1})
+	if err := n.postJSON(context.Background(), srv.URL, []byte(`{}`)); err == nil {
+		t.Error("expected error for 400 response")
+	}
+}
+
+func TestSendSyncResult_RejectsUnsafeURL(t *testing.T) {
+	n := New(&Config{})
+	for _, u := range []string{
+		"http://example.com/hook",
+		"https://localhost/hook",
+		"https://127.0.0.1/hook",
+		"https://169.254.169.254/latest/meta-data",
+		"https://10.0.0.5/hook",
+	} {
+		if err := n.sendSyncResult(context.Background(), u, SyncResultPayload{SourceID: "s"}); err == nil {
+			t.Errorf("sendSyncResult(%q) should have been rejected", u)
+		}
+	}
+}
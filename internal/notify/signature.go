@@ -0,0 +1,28 @@
+package notify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of a webhook's body, keyed
+// with Config.WebhookSecret, as "sha256=<hex>". Receivers recompute it
+// over the raw body and compare in constant time.
+const SignatureHeader = "X-CalBridge-Signature"
+
+// webhookSignature returns the SignatureHeader value for body.
+func webhookSignature(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// signWebhook sets SignatureHeader on req when a secret is configured;
+// without one, webhooks go out unsigned as before.
+func (n *Notifier) signWebhook(req *http.Request, body []byte) {
+	if secret := n.config().WebhookSecret; secret != "" {
+		req.Header.Set(SignatureHeader, webhookSignature(body, secret))
+	}
+}
@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signedRequest is what a webhook receiver saw.
+type signedRequest struct {
+	signature string
+	body      []byte
+}
+
+// signatureServer starts a TLS server recording each request and
+// returns a notifier, configured with secret, whose client dials it
+// for any host, so URLs pass validateWebhookURL as public HTTPS.
+func signatureServer(t *testing.T, secret string) (*Notifier, <-chan signedRequest) {
+	t.Helper()
+	got := make(chan signedRequest, 4)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- signedRequest{signature: r.Header.Get(SignatureHeader), body: body}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	n := New(&Config{WebhookEnabled: true, WebhookURL: "https://hooks.example.com/alerts", WebhookSecret: secret, MaxSendAttempts: 1})
+	transport := server.Client().Transport.(*http.Transport).Clone()
+	transport.TLSClientConfig.InsecureSkipVerify = true
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	}
+	n.httpClient = &http.Client{Transport: transport, Timeout: 5 * time.Second}
+	return n, got
+}
+
+// verifySignature checks a signature the way a receiver would.
+func verifySignature(t *testing.T, req signedRequest, secret string) {
+	t.Helper()
+	hexSum, ok := strings.CutPrefix(req.signature, "sha256=")
+	if !ok {
+		t.Fatalf("expected a sha256= signature, got %q", req.signature)
+	}
+	sum, err := hex.DecodeString(hexSum)
+	if err != nil {
+		t.Fatalf("signature is not hex: %v", err)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(req.body)
+	if !hmac.Equal(sum, mac.Sum(nil)) {
+		t.Errorf("signature %q does not verify against the body", req.signature)
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	const secret = "receiver-shared-secret"
+	alert := Alert{Type: AlertTypeError, SourceID: "src-1", SourceName: "Work", Message: "Sync failed", Timestamp: time.Now()}
+	sends := []struct {
+		name string
+		send func(n *Notifier) error
+	}{
+		{"sendWebhook", func(n *Notifier) error { return n.sendWebhook(context.Background(), alert) }},
+		{"sendWebhookToURL", func(n *Notifier) error {
+			return n.sendWebhookToURL(context.Background(), alert, "https://user-hooks.example.com/x")
+		}},
+		{"SendTestWebhook", func(n *Notifier) error {
+			return n.SendTestWebhook(context.Background(), "https://user-hooks.example.com/x")
+		}},
+		{"sendSyncResult", func(n *Notifier) error {
+			return n.sendSyncResult(context.Background(), "https://user-hooks.example.com/x", SyncResultPayload{SourceID: "src-1"})
+		}},
+	}
+	for _, tt := range sends {
+		t.Run(tt.name, func(t *testing.T) {
+			n, got := signatureServer(t, secret)
+			if err := tt.send(n); err != nil {
+				t.Fatalf("send: %v", err)
+			}
+			req := <-got
+			if len(req.body) == 0 {
+				t.Fatal("expected a request body")
+			}
+			verifySignature(t, req, secret)
+		})
+	}
+
+	t.Run("wrong secret does not verify", func(t *testing.T) {
+		body := []byte(`{"alert_type":"test"}`)
+		if hmac.Equal([]byte(webhookSignature(body, secret)), []byte(webhookSignature(body, "other"))) {
+			t.Error("expected signatures under different secrets to differ")
+		}
+	})
+
+	t.Run("unsigned without a secret", func(t *testing.T) {
+		n, got := signatureServer(t, "")
+		if err := n.SendTestWebhook(context.Background(), "https://user-hooks.example.com/x"); err != nil {
+			t.Fatalf("SendTestWebhook: %v", err)
+		}
+		if req := <-got; req.signature != "" {
+			t.Errorf("expected no %s header, got %q", SignatureHeader, req.signature)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/macjediwizard/calbridgesync/internal/caldav"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// ErrSyncInProgress is returned by SyncCalendar when the source is
+// already syncing.
+var ErrSyncInProgress = errors.New("a sync of this source is already running")
+
+// ErrSchedulerStopped is returned by SyncCalendar when the scheduler
+// stops before a sync slot frees up.
+var ErrSchedulerStopped = errors.New("scheduler is stopped")
+
+// SyncCalendar runs caldav.SyncEngine.SyncCalendar for one calendar of
+// source and waits for it, under the same rules as a scheduled sync: it
+// takes the source's sync lock and a sync slot, is bounded by the
+// source's maximum duration and can be stopped with CancelSync. A
+// source that is already syncing is refused with ErrSyncInProgress
+// rather than synced twice at once. The sync also stops if ctx is
+// canceled or the scheduler stops.
+func (s *Scheduler) SyncCalendar(ctx context.Context, source *db.Source, calendarPath string) (*caldav.SyncResult, error) {
+	lock := s.getSyncLock(source.ID)
+	if !lock.TryLock() {
+		return nil, ErrSyncInProgress
+	}
+	defer lock.Unlock()
+
+	if !s.acquireSyncSlot() {
+		return nil, ErrSchedulerStopped
+	}
+	defer s.releaseSyncSlot()
+	s.markInFlight(source.ID)
+	defer s.clearInFlight(source.ID)
+
+	log.Printf("Starting sync of calendar %s for source %s (%s)", calendarPath, source.Name, source.ID)
+
+	ctx, cancel := s.withSyncTimeout(ctx, source)
+	defer cancel()
+	stop := context.AfterFunc(s.ctx, cancel)
+	defer stop()
+	ctx = s.trackRun(ctx, source.ID)
+	defer s.untrackRun(source.ID)
+
+	return s.syncEngine.SyncCalendar(ctx, source, calendarPath)
+}
@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSyncCalendar_RefusedWhileSyncing(t *testing.T) {
+	database, engine, source := newHungSource(t)
+	sched := New(database, engine, nil)
+	defer sched.cancel()
+
+	lock := sched.getSyncLock(source.ID)
+	lock.Lock()
+	_, err := sched.SyncCalendar(context.Background(), source, "/dav/calendars/work/")
+	lock.Unlock()
+	if !errors.Is(err, ErrSyncInProgress) {
+		t.Errorf("expected ErrSyncInProgress while the source syncs, got %v", err)
+	}
+}
+
+func TestSyncCalendar_BoundedAndCancelable(t *testing.T) {
+	t.Run("times out", func(t *testing.T) {
+		database, engine, source := newHungSource(t)
+		sched := New(database, engine, nil)
+		defer sched.cancel()
+		sched.SetMaxSyncDuration(200 * time.Millisecond)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			result, err := sched.SyncCalendar(context.Background(), source, "/dav/calendars/work/")
+			if err != nil || !result.TimedOut {
+				t.Errorf("expected a timed-out result, got %+v, %v", result, err)
+			}
+		}()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("expected the maximum duration to stop the calendar sync")
+		}
+	})
+
+	t.Run("CancelSync stops it", func(t *testing.T) {
+		database, engine, source := newHungSource(t)
+		sched := New(database, engine, nil)
+		defer sched.cancel()
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _ = sched.SyncCalendar(context.Background(), source, "/dav/calendars/work/")
+		}()
+		deadline := time.After(5 * time.Second)
+		for !sched.CancelSync(source.ID) {
+			select {
+			case <-deadline:
+				t.Fatal("the calendar sync never became cancelable")
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("expected CancelSync to stop the calendar sync")
+		}
+	})
+}
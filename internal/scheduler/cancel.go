@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+
+	"github.com/macjediwizard/calbridgesync/internal/caldav"
+)
+
+// trackRun derives the context a sync of sourceID runs under and
+// registers its cancel function for CancelSync. Pair it with
+// untrackRun once the sync returns.
+func (s *Scheduler) trackRun(ctx context.Context, sourceID string) context.Context {
+	ctx, cancel := context.WithCancelCause(ctx)
+	s.runsMu.Lock()
+	defer s.runsMu.Unlock()
+	s.runs[sourceID] = cancel
+	return ctx
+}
+
+// untrackRun releases the context trackRun made for sourceID.
+func (s *Scheduler) untrackRun(sourceID string) {
+	s.runsMu.Lock()
+	cancel, ok := s.runs[sourceID]
+	delete(s.runs, sourceID)
+	s.runsMu.Unlock()
+	if ok {
+		cancel(nil)
+	}
+}
+
+// CancelSync stops the sync running for sourceID, if any, and reports
+// whether there was one. The sync notices at its next per-event check
+// and records a canceled result covering the work done so far.
+// Canceling an idle source does nothing.
+func (s *Scheduler) CancelSync(sourceID string) bool {
+	s.runsMu.Lock()
+	cancel, ok := s.runs[sourceID]
+	s.runsMu.Unlock()
+	if !ok {
+		return false
+	}
+	log.Printf("Canceling the running sync for source %s", sourceID)
+	cancel(caldav.ErrSyncCanceled)
+	return true
+}
@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/macjediwizard/calbridgesync/internal/caldav"
+)
+
+func TestCancelSync(t *testing.T) {
+	t.Run("stops a running sync", func(t *testing.T) {
+		sched := New(nil, nil, nil)
+		defer sched.cancel()
+
+		ctx := sched.trackRun(sched.ctx, "source-1")
+		stopped := make(chan error, 1)
+		go func() {
+			// Stands in for SyncSource, which stops at its next
+			// per-event check once ctx is done.
+			<-ctx.Done()
+			stopped <- context.Cause(ctx)
+		}()
+
+		if !sched.CancelSync("source-1") {
+			t.Fatal("expected CancelSync to find the running sync")
+		}
+		select {
+		case cause := <-stopped:
+			if !errors.Is(cause, caldav.ErrSyncCanceled) {
+				t.Errorf("expected cause ErrSyncCanceled, got %v", cause)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("the sync's context was not canceled")
+		}
+
+		sched.untrackRun("source-1")
+		if sched.CancelSync("source-1") {
+			t.Error("expected no running sync once it returned")
+		}
+	})
+
+	t.Run("idle source is a no-op", func(t *testing.T) {
+		sched := New(nil, nil, nil)
+		defer sched.cancel()
+		other := sched.trackRun(sched.ctx, "busy")
+		defer sched.untrackRun("busy")
+
+		if sched.CancelSync("idle") {
+			t.Error("expected CancelSync to report no running sync")
+		}
+		if other.Err() != nil {
+			t.Error("canceling an idle source should leave other syncs running")
+		}
+	})
+}
@@ -0,0 +1,206 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// healthErrorWindow is how far back sync logs are counted for the
+// error rates in a HealthSnapshot.
+const healthErrorWindow = 24 * time.Hour
+
+// SourceHealth is one scheduled source's entry in a HealthSnapshot.
+type SourceHealth struct {
+	ID             string        `json:"id"`
+	UserID         string        `json:"-"`
+	Name           string        `json:"name"`
+	LastSyncStatus db.SyncStatus `json:"last_sync_status"`
+	LastSyncAt     *time.Time    `json:"last_sync_at,omitempty"`
+	LastSuccessAt  *time.Time    `json:"last_success_at,omitempty"`
+	NextSyncAt     *time.Time    `json:"next_sync_at,omitempty"`
+	Stale          bool          `json:"stale"`
+	// SyncingSince is when the sync currently running for this source
+	// started; nil when none is.
+	SyncingSince *time.Time `json:"syncing_since,omitempty"`
+	// Syncs and FailedSyncs count the runs logged in the error window.
+	Syncs       int     `json:"syncs"`
+	FailedSyncs int     `json:"failed_syncs"`
+	ErrorRate   float64 `json:"error_rate"`
+}
+
+// HealthSnapshot is the scheduler's view of every enabled source at
+// one moment. The periodic health log line and GET /api/health/detailed
+// are both rendered from it.
+type HealthSnapshot struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	ActiveJobs  int       `json:"active_jobs"`
+	InFlight    int       `json:"in_flight"`
+	// MaxConcurrent is the SetMaxConcurrentSyncs cap; 0 means none.
+	MaxConcurrent int `json:"max_concurrent"`
+	// ErrorWindowHours is the period Syncs, FailedSyncs and ErrorRate
+	// cover. ErrorRate is FailedSyncs/Syncs, 0 when nothing ran.
+	ErrorWindowHours int            `json:"error_window_hours"`
+	Syncs            int            `json:"syncs"`
+	FailedSyncs      int            `json:"failed_syncs"`
+	ErrorRate        float64        `json:"error_rate"`
+	Stale            []string       `json:"stale"`
+	Sources          []SourceHealth `json:"sources"`
+}
+
+// SetHealthLogInterval sets how often the health snapshot is logged.
+// d <= 0 restores the default (healthLogInterval). Call before Start().
+func (s *Scheduler) SetHealthLogInterval(d time.Duration) {
+	if d <= 0 {
+		d = healthLogInterval
+	}
+	s.healthLogEvery = d
+}
+
+// healthLogPeriod returns the configured health log interval.
+func (s *Scheduler) healthLogPeriod() time.Duration {
+	if s.healthLogEvery <= 0 {
+		return healthLogInterval
+	}
+	return s.healthLogEvery
+}
+
+// markInFlight records that a sync for sourceID has started.
+func (s *Scheduler) markInFlight(sourceID string) {
+	s.inFlightMu.Lock()
+	defer s.inFlightMu.Unlock()
+	s.inFlight[sourceID] = time.Now()
+}
+
+// clearInFlight records that the sync for sourceID has finished.
+func (s *Scheduler) clearInFlight(sourceID string) {
+	s.inFlightMu.Lock()
+	defer s.inFlightMu.Unlock()
+	delete(s.inFlight, sourceID)
+}
+
+// HealthSnapshot reports the state of every enabled source: its last
+// status, whether it is stale or syncing right now, and how many of
+// its syncs failed in the last healthErrorWindow.
+func (s *Scheduler) HealthSnapshot() (*HealthSnapshot, error) {
+	now := time.Now()
+	sources, err := s.db.GetEnabledSources()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sources: %w", err)
+	}
+	outcomes, err := s.db.GetSyncOutcomeCounts(now.Add(-healthErrorWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sync outcomes: %w", err)
+	}
+
+	snap := &HealthSnapshot{
+		GeneratedAt:      now.UTC(),
+		ActiveJobs:       s.GetJobCount(),
+		MaxConcurrent:    cap(s.syncSlots),
+		ErrorWindowHours: int(healthErrorWindow / time.Hour),
+		Stale:            []string{},
+		Sources:          make([]SourceHealth, 0, len(sources)),
+	}
+
+	s.inFlightMu.Lock()
+	inFlight := make(map[string]time.Time, len(s.inFlight))
+	for id, started := range s.inFlight {
+		inFlight[id] = started
+	}
+	s.inFlightMu.Unlock()
+	snap.InFlight = len(inFlight)
+
+	for _, source := range sources {
+		h := SourceHealth{
+			ID:             source.ID,
+			UserID:         source.UserID,
+			Name:           source.Name,
+			LastSyncStatus: source.LastSyncStatus,
+			LastSyncAt:     source.LastSyncAt,
+			LastSuccessAt:  source.LastSuccessAt,
+			Stale:          s.IsSourceStale(source),
+		}
+		if next := s.GetNextSyncAt(source.ID); !next.IsZero() {
+			h.NextSyncAt = &next
+		} else {
+			h.NextSyncAt = source.NextSyncAt
+		}
+		if started, ok := inFlight[source.ID]; ok {
+			h.SyncingSince = &started
+		}
+		if c, ok := outcomes[source.ID]; ok {
+			h.Syncs = c.Total
+			h.FailedSyncs = c.Failed
+			h.ErrorRate = errorRate(c.Failed, c.Total)
+		}
+		snap.Sources = append(snap.Sources, h)
+	}
+	snap.tally()
+	return snap, nil
+}
+
+// tally recomputes the snapshot-wide totals and stale list from Sources.
+func (snap *HealthSnapshot) tally() {
+	snap.Syncs, snap.FailedSyncs = 0, 0
+	snap.Stale = []string{}
+	for _, h := range snap.Sources {
+		snap.Syncs += h.Syncs
+		snap.FailedSyncs += h.FailedSyncs
+		if h.Stale {
+			snap.Stale = append(snap.Stale, h.ID)
+		}
+	}
+	sort.Strings(snap.Stale)
+	snap.ErrorRate = errorRate(snap.FailedSyncs, snap.Syncs)
+}
+
+// ForUser returns a copy of the snapshot limited to one user's sources.
+// Scheduler-wide figures (jobs, in-flight syncs, the concurrency cap)
+// are kept as they are; totals and the stale list are recomputed.
+func (snap *HealthSnapshot) ForUser(userID string) *HealthSnapshot {
+	out := *snap
+	out.Sources = make([]SourceHealth, 0, len(snap.Sources))
+	for _, h := range snap.Sources {
+		if h.UserID == userID {
+			out.Sources = append(out.Sources, h)
+		}
+	}
+	out.tally()
+	return &out
+}
+
+// errorRate returns failed/total, or 0 when total is 0.
+func errorRate(failed, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(failed) / float64(total)
+}
+
+// logHealth logs a one-line summary of the current health snapshot,
+// naming any stale sources.
+func (s *Scheduler) logHealth() {
+	snap, err := s.HealthSnapshot()
+	if err != nil {
+		log.Printf("[Scheduler Health] Active jobs: %d (snapshot unavailable: %v)", s.GetJobCount(), err)
+		return
+	}
+
+	line := fmt.Sprintf("[Scheduler Health] Active jobs: %d, in flight: %d, stale: %d, failed syncs (%dh): %d/%d (%.0f%%)",
+		snap.ActiveJobs, snap.InFlight, len(snap.Stale), snap.ErrorWindowHours,
+		snap.FailedSyncs, snap.Syncs, snap.ErrorRate*100)
+	if len(snap.Stale) > 0 {
+		var names []string
+		for _, h := range snap.Sources {
+			if h.Stale {
+				names = append(names, fmt.Sprintf("%q", h.Name))
+			}
+		}
+		line += " - stale sources: " + strings.Join(names, ", ")
+	}
+	log.Print(line)
+}
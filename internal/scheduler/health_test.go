@@ -0,0 +1,185 @@
+package scheduler
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func TestHealthSnapshot(t *testing.T) {
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	defer database.Close()
+
+	newSource := func(userID, name string, enabled bool) *db.Source {
+		t.Helper()
+		source := &db.Source{
+			UserID:           userID,
+			Name:             name,
+			SourceType:       db.SourceTypeCustom,
+			SourceURL:        "https://example.com/caldav",
+			SourceUsername:   "user",
+			SourcePassword:   "encrypted",
+			DestURL:          "https://dest.example.com/caldav",
+			DestUsername:     "dest",
+			DestPassword:     "encrypted",
+			SyncInterval:     3600,
+			SyncDirection:    db.SyncDirectionOneWay,
+			ConflictStrategy: db.ConflictSourceWins,
+			Enabled:          enabled,
+		}
+		if err := database.CreateSource(source); err != nil {
+			t.Fatalf("failed to create source: %v", err)
+		}
+		return source
+	}
+	logRun := func(sourceID string, status db.SyncStatus) {
+		t.Helper()
+		if err := database.CreateSyncLog(&db.SyncLog{SourceID: sourceID, Status: status}); err != nil {
+			t.Fatalf("failed to create sync log: %v", err)
+		}
+		if err := database.UpdateSourceSyncStatus(sourceID, status, string(status)); err != nil {
+			t.Fatalf("failed to update sync status: %v", err)
+		}
+	}
+
+	alice, err := database.GetOrCreateUser("alice@example.com", "Alice")
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	bob, err := database.GetOrCreateUser("bob@example.com", "Bob")
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	healthy := newSource(alice.ID, "Healthy", true)
+	logRun(healthy.ID, db.SyncStatusSuccess)
+	logRun(healthy.ID, db.SyncStatusPartial)
+
+	failing := newSource(alice.ID, "Failing", true)
+	logRun(failing.ID, db.SyncStatusSuccess)
+	logRun(failing.ID, db.SyncStatusError)
+	// Last attempt three hours ago: past 2x the hourly interval.
+	if _, err := database.Conn().Exec(`UPDATE sources SET last_sync_at = ? WHERE id = ?`,
+		time.Now().Add(-3*time.Hour).UTC(), failing.ID); err != nil {
+		t.Fatalf("failed to backdate last_sync_at: %v", err)
+	}
+
+	other := newSource(bob.ID, "Bob's", true)
+	logRun(other.ID, db.SyncStatusAuthError)
+
+	newSource(alice.ID, "Disabled", false)
+
+	sched := New(database, nil, nil)
+	defer sched.cancel()
+	sched.SetMaxConcurrentSyncs(2)
+	addJobDirectly(sched, healthy.ID, time.Hour)
+	sched.jobs[healthy.ID].nextSyncAt = time.Now().Add(time.Hour)
+	sched.markInFlight(healthy.ID)
+
+	snap, err := sched.HealthSnapshot()
+	if err != nil {
+		t.Fatalf("HealthSnapshot: %v", err)
+	}
+
+	if snap.ActiveJobs != 1 || snap.InFlight != 1 || snap.MaxConcurrent != 2 {
+		t.Errorf("expected 1 job, 1 in flight, cap 2; got %d, %d, %d", snap.ActiveJobs, snap.InFlight, snap.MaxConcurrent)
+	}
+	if len(snap.Sources) != 3 {
+		t.Fatalf("expected the 3 enabled sources, got %d", len(snap.Sources))
+	}
+	if snap.Syncs != 5 || snap.FailedSyncs != 2 {
+		t.Errorf("expected 2 of 5 syncs failed, got %d of %d", snap.FailedSyncs, snap.Syncs)
+	}
+	if snap.ErrorRate != 0.4 {
+		t.Errorf("expected error rate 0.4, got %v", snap.ErrorRate)
+	}
+	if len(snap.Stale) != 1 || snap.Stale[0] != failing.ID {
+		t.Errorf("expected only %q to be stale, got %v", failing.ID, snap.Stale)
+	}
+
+	byID := make(map[string]SourceHealth)
+	for _, h := range snap.Sources {
+		byID[h.ID] = h
+	}
+	h := byID[healthy.ID]
+	if h.LastSyncStatus != db.SyncStatusPartial || h.SyncingSince == nil || h.NextSyncAt == nil || h.Stale {
+		t.Errorf("unexpected health for the healthy source: %+v", h)
+	}
+	if h.ErrorRate != 0 || h.Syncs != 2 {
+		t.Errorf("expected 2 syncs and no errors for the healthy source, got %d and %v", h.Syncs, h.ErrorRate)
+	}
+	f := byID[failing.ID]
+	if f.LastSyncStatus != db.SyncStatusError || f.SyncingSince != nil || f.ErrorRate != 0.5 {
+		t.Errorf("unexpected health for the failing source: %+v", f)
+	}
+	if f.LastSuccessAt == nil {
+		t.Error("expected the failing source to keep its last success")
+	}
+
+	t.Run("reflects later state changes", func(t *testing.T) {
+		sched.clearInFlight(healthy.ID)
+		logRun(failing.ID, db.SyncStatusSuccess)
+
+		snap, err := sched.HealthSnapshot()
+		if err != nil {
+			t.Fatalf("HealthSnapshot: %v", err)
+		}
+		if snap.InFlight != 0 {
+			t.Errorf("expected nothing in flight, got %d", snap.InFlight)
+		}
+		if len(snap.Stale) != 0 {
+			t.Errorf("expected no stale sources after a fresh sync, got %v", snap.Stale)
+		}
+	})
+
+	t.Run("ForUser keeps only that user's sources", func(t *testing.T) {
+		mine := snap.ForUser(alice.ID)
+		if len(mine.Sources) != 2 {
+			t.Fatalf("expected alice's 2 sources, got %d", len(mine.Sources))
+		}
+		for _, h := range mine.Sources {
+			if h.ID == other.ID {
+				t.Error("bob's source leaked into alice's snapshot")
+			}
+		}
+		if mine.Syncs != 4 || mine.FailedSyncs != 1 || mine.ErrorRate != 0.25 {
+			t.Errorf("expected 1 of 4 syncs failed, got %d of %d (%v)", mine.FailedSyncs, mine.Syncs, mine.ErrorRate)
+		}
+		if mine.ActiveJobs != snap.ActiveJobs || mine.InFlight != snap.InFlight {
+			t.Error("expected scheduler-wide figures to be kept")
+		}
+		if len(snap.Sources) != 3 {
+			t.Error("ForUser modified the original snapshot")
+		}
+	})
+}
+
+func TestHealthLogInterval(t *testing.T) {
+	sched := New(nil, nil, nil)
+	defer sched.cancel()
+
+	if got := sched.healthLogPeriod(); got != healthLogInterval {
+		t.Errorf("expected default %v, got %v", healthLogInterval, got)
+	}
+	if got := sched.expectedHeartbeatThreshold(routineHealthLog); got != watchdogHealthLogThreshold {
+		t.Errorf("expected default watchdog threshold %v, got %v", watchdogHealthLogThreshold, got)
+	}
+
+	sched.SetHealthLogInterval(30 * time.Minute)
+	if got := sched.healthLogPeriod(); got != 30*time.Minute {
+		t.Errorf("expected 30m, got %v", got)
+	}
+	if got := sched.expectedHeartbeatThreshold(routineHealthLog); got < 60*time.Minute {
+		t.Errorf("expected the watchdog threshold to grow with the interval, got %v", got)
+	}
+
+	sched.SetHealthLogInterval(0)
+	if got := sched.healthLogPeriod(); got != healthLogInterval {
+		t.Errorf("expected 0 to restore the default, got %v", got)
+	}
+}
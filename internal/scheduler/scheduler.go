@@ -16,8 +16,8 @@ import (
 const (
 	cleanupInterval         = 24 * time.Hour
 	defaultLogRetentionDays = 30
-	syncTimeout             = 120 * time.Minute // Maximum time for a single sync operation (2 hours for slow iCloud with multiple calendars)
-	healthLogInterval       = 5 * time.Minute   // Interval for scheduler health logging
+	syncTimeout             = 120 * time.Minute // Default maximum time for a single sync operation (2 hours for slow iCloud with multiple calendars)
+	healthLogInterval       = 5 * time.Minute   // Default interval for scheduler health logging
 	staleMultiplier         = 2                 // Source is stale if last sync > staleMultiplier * interval
 	startupStagger          = 30 * time.Second  // Delay between starting each source's first sync
 
@@ -109,9 +109,10 @@ type Scheduler struct {
 	skipCountsMu sync.Mutex
 	skipCounts   map[string]int
 
-	// logRetentionDays is the number of days to keep sync logs before
-	// the daily cleanup routine purges them. Configurable via
-	// SYNC_LOG_RETENTION_DAYS env var; defaults to 30. (#136)
+	// logRetentionDays is the number of days to keep sync logs and
+	// malformed-event records before the daily cleanup routine purges
+	// them. Configurable via SYNC_LOG_RETENTION_DAYS env var; defaults
+	// to 30, and 0 disables the purge. (#136)
 	logRetentionDays int
 
 	// authFailCounts tracks consecutive authentication failures per
@@ -120,6 +121,30 @@ type Scheduler struct {
 	authFailCountsMu sync.Mutex
 	authFailCounts   map[string]int
 
+	// syncSlots caps how many syncs run at once across all sources,
+	// whether scheduled or manually triggered. Nil means no cap.
+	// Configurable via SYNC_MAX_CONCURRENT.
+	syncSlots chan struct{}
+
+	// inFlight maps each source with a sync running right now to when
+	// that sync started. Reported by HealthSnapshot.
+	inFlightMu sync.Mutex
+	inFlight   map[string]time.Time
+
+	// runs holds the cancel function of each sync running right now,
+	// so CancelSync can stop it.
+	runsMu sync.Mutex
+	runs   map[string]context.CancelCauseFunc
+
+	// maxSyncDuration is how long a sync may run before it is canceled
+	// as timed out; 0 means syncTimeout. Sources can set their own.
+	// See SetMaxSyncDuration.
+	maxSyncDuration time.Duration
+
+	// healthLogEvery is how often logHealth runs; 0 means
+	// healthLogInterval. See SetHealthLogInterval.
+	healthLogEvery time.Duration
+
 	// backupMgr is the automated backup manager. Nil if backups
 	// are disabled. Called from the daily cleanup routine.
 	backupMgr interface {
@@ -129,11 +154,12 @@ type Scheduler struct {
 }
 
 // New creates a new scheduler. logRetentionDays controls how many
-// days of sync logs the daily cleanup routine keeps. Pass 0 to use
-// the default (30 days).
+// days of sync logs the daily cleanup routine keeps. Omit it (or pass
+// a negative value) to use the default (30 days); pass 0 to keep logs
+// forever.
 func New(database *db.DB, syncEngine *caldav.SyncEngine, notifier *notify.Notifier, logRetentionDays ...int) *Scheduler {
 	retention := defaultLogRetentionDays
-	if len(logRetentionDays) > 0 && logRetentionDays[0] > 0 {
+	if len(logRetentionDays) > 0 && logRetentionDays[0] >= 0 {
 		retention = logRetentionDays[0]
 	}
 	ctx, cancel := context.WithCancel(context.Background())
@@ -149,6 +175,8 @@ func New(database *db.DB, syncEngine *caldav.SyncEngine, notifier *notify.Notifi
 		heartbeats:       make(map[string]time.Time),
 		skipCounts:       make(map[string]int),
 		authFailCounts:   make(map[string]int),
+		inFlight:         make(map[string]time.Time),
+		runs:             make(map[string]context.CancelCauseFunc),
 	}
 }
 
@@ -233,7 +261,7 @@ func (s *Scheduler) sendCredentialExpiryAlert(source *db.Source, consecutiveFail
 	if user, err := s.db.GetUserByID(source.UserID); err == nil {
 		userEmail = user.Email
 	}
-	userPrefs := s.getUserAlertPrefs(source.UserID)
+	userPrefs := s.getAlertPrefs(source)
 	msg := fmt.Sprintf("Credentials may be expired for source '%s' — %d consecutive authentication failures. Re-enter credentials in the web UI.",
 		source.Name, consecutiveFailures)
 	s.notifier.SendSyncFailureAlertWithPrefs(
@@ -259,17 +287,19 @@ func (s *Scheduler) Start() error {
 		log.Printf("Reset %d interrupted sync(s) from previous run", count)
 	}
 
-	// Load all enabled sources
-	sources, err := s.db.GetEnabledSources()
+	now := time.Now()
+	sources, err := s.startupSources(now)
 	if err != nil {
 		return err
 	}
 
-	// Start jobs with staggered initial sync to avoid resource contention
+	// Seed each job from its persisted next_sync_at so a restart
+	// doesn't reset every schedule. Overdue sources still get the
+	// startup stagger to avoid resource contention.
+	delays := startupDelays(sources, now)
 	for i, source := range sources {
 		interval := time.Duration(source.SyncInterval) * time.Second
-		stagger := time.Duration(i) * startupStagger
-		s.AddJobWithDelay(source.ID, interval, stagger)
+		s.AddJobWithDelay(source.ID, interval, delays[i])
 	}
 
 	// Start cleanup goroutine
@@ -293,6 +323,54 @@ func (s *Scheduler) Start() error {
 	return nil
 }
 
+// startupSources returns the enabled sources to schedule on Start.
+// Sources already due come first, most overdue first, so they take the
+// front of the startup stagger; the rest follow in any order.
+func (s *Scheduler) startupSources(now time.Time) ([]*db.Source, error) {
+	sources, err := s.db.GetSourcesDueForSync(now)
+	if err != nil {
+		return nil, err
+	}
+	enabled, err := s.db.GetEnabledSources()
+	if err != nil {
+		return nil, err
+	}
+	due := make(map[string]bool, len(sources))
+	for _, source := range sources {
+		due[source.ID] = true
+	}
+	for _, source := range enabled {
+		if !due[source.ID] {
+			sources = append(sources, source)
+		}
+	}
+	return sources, nil
+}
+
+// startupDelays returns the initial-sync delay for each source, in
+// the same order as sources. A source whose persisted next_sync_at is
+// still in the future waits until then (capped at its interval, in
+// case the interval was shortened since). Overdue and never-scheduled
+// sources run promptly, spaced startupStagger apart so a restart after
+// long downtime doesn't fire every overdue sync at once.
+func startupDelays(sources []*db.Source, now time.Time) []time.Duration {
+	delays := make([]time.Duration, len(sources))
+	overdue := 0
+	for i, source := range sources {
+		if source.NextSyncAt != nil && source.NextSyncAt.After(now) {
+			delay := source.NextSyncAt.Sub(now)
+			if interval := time.Duration(source.SyncInterval) * time.Second; interval > 0 && delay > interval {
+				delay = interval
+			}
+			delays[i] = delay
+			continue
+		}
+		delays[i] = time.Duration(overdue) * startupStagger
+		overdue++
+	}
+	return delays
+}
+
 // heartbeat records that the named routine has made progress. Called
 // at the top of each tick iteration of long-running routines so the
 // watchdog can detect routines that have crashed or hung.
@@ -322,7 +400,7 @@ func (s *Scheduler) expectedHeartbeatThreshold(name string) time.Duration {
 	case routineCleanup:
 		return watchdogCleanupThreshold
 	case routineHealthLog:
-		return watchdogHealthLogThreshold
+		return max(watchdogHealthLogThreshold, 2*s.healthLogPeriod()+2*time.Minute)
 	case routineWatchdog:
 		return watchdogWatchdogSelfThreshold
 	}
@@ -473,7 +551,7 @@ func (s *Scheduler) Stop() {
 
 	// Cancel context to stop all jobs. This cascades into every
 	// in-flight SyncSource via the context chain in executeSync
-	// (ctx, cancel := context.WithTimeout(s.ctx, syncTimeout))
+	// (ctx, cancel := s.withSyncTimeout(s.ctx, source))
 	// so HTTP operations in flight should unblock within their
 	// current request's remaining timeout.
 	s.cancel()
@@ -614,6 +692,14 @@ func (s *Scheduler) UpdateJobInterval(sourceID string, interval time.Duration) {
 	s.jobs[sourceID] = job
 	s.mu.Unlock()
 
+	// Persist the new schedule so a restart before the next tick
+	// doesn't fall back to the old interval's next_sync_at.
+	if s.db != nil {
+		if err := s.db.UpdateSourceNextSyncAt(sourceID, job.nextSyncAt); err != nil {
+			log.Printf("Failed to persist next sync time for source %s: %v", sourceID, err)
+		}
+	}
+
 	// Start job goroutine (don't run immediately - next tick will be at interval from now)
 	s.wg.Add(1)
 	go s.runJobFromTicker(job)
@@ -621,7 +707,9 @@ func (s *Scheduler) UpdateJobInterval(sourceID string, interval time.Duration) {
 	log.Printf("Updated sync interval for source %s to %v", sourceID, interval)
 }
 
-// TriggerSync manually triggers a sync for a source.
+// TriggerSync manually triggers a sync for a source. It returns
+// immediately; the sync waits for a slot if SetMaxConcurrentSyncs caps
+// concurrency.
 func (s *Scheduler) TriggerSync(sourceID string) {
 	s.wg.Add(1)
 	go func() {
@@ -819,15 +907,45 @@ func (s *Scheduler) executeSync(sourceID string) {
 		return
 	}
 
+	// Wait for a global slot so a burst of syncs (a sync-all request,
+	// or many sources sharing an interval) can't open unbounded
+	// connections at once. The per-source lock is already held, so a
+	// queued source is skipped rather than queued twice.
+	if !s.acquireSyncSlot() {
+		return
+	}
+	defer s.releaseSyncSlot()
+	s.markInFlight(sourceID)
+	defer s.clearInFlight(sourceID)
+
 	log.Printf("Starting sync for source %s (%s)", source.Name, sourceID)
 
-	// Create a timeout context for this sync operation
-	ctx, cancel := context.WithTimeout(s.ctx, syncTimeout)
+	// Bound the sync by its maximum duration so a hung server can't
+	// hold the slot forever.
+	ctx, cancel := s.withSyncTimeout(s.ctx, source)
 	defer cancel()
+	ctx = s.trackRun(ctx, sourceID)
+	defer s.untrackRun(sourceID)
 
 	// Execute sync with timeout context
 	result := s.syncEngine.SyncSource(ctx, source)
 
+	// The engine's auth circuit already alerted the user when it opened
+	// and is pacing retries itself; counting these towards the
+	// credential-expiry alert or firing failure alerts would repeat it.
+	if result.AuthCircuitOpen {
+		log.Printf("Sync held for source %s: %s", source.Name, result.Message)
+		return
+	}
+	// A sync stopped on request isn't a failure worth alerting on.
+	if result.Canceled {
+		log.Printf("Sync canceled for source %s: %s", source.Name, result.Message)
+		return
+	}
+	if result.TimedOut {
+		log.Printf("WARNING: sync for source %s ran past its maximum duration of %v and was canceled", source.Name, s.syncDuration(source))
+	}
+
 	if result.Success {
 		log.Printf("Sync completed for source %s: %d created, %d updated, %d deleted, %d duplicates removed in %v",
 			source.Name, result.Created, result.Updated, result.Deleted, result.DuplicatesRemoved, result.Duration)
@@ -844,7 +962,7 @@ func (s *Scheduler) executeSync(sourceID string) {
 			}
 
 			// Look up user alert preferences
-			userPrefs := s.getUserAlertPrefs(source.UserID)
+			userPrefs := s.getAlertPrefs(source)
 			s.notifier.SendRecoveryAlertWithPrefs(s.ctx, sourceID, source.Name, userEmail, userPrefs)
 		}
 	} else {
@@ -913,7 +1031,9 @@ func (s *Scheduler) executeSync(sourceID string) {
 }
 
 // maybeSendFailureAlert inspects a sync result and fires a failure alert if
-// the sync failed or if any data-loss protection guard was triggered.
+// the sync failed or if any data-loss protection guard was triggered. A
+// partial sync (success with warnings) alerts too when the user has
+// turned on AlertOnPartial.
 // It respects the notifier's cooldown window — called on every sync, the
 // per-source cooldown map prevents alert storms on a persistently broken
 // source.
@@ -926,6 +1046,7 @@ func (s *Scheduler) maybeSendFailureAlert(sourceID string, source *db.Source, re
 		shouldAlert  bool
 		alertMessage string
 		alertDetails string
+		userPrefs    = s.getAlertPrefs(source)
 	)
 
 	if !result.Success {
@@ -948,6 +1069,10 @@ func (s *Scheduler) maybeSendFailureAlert(sourceID string, source *db.Source, re
 			shouldAlert = true
 			alertMessage = fmt.Sprintf("Data-loss protection triggered for source '%s'", source.Name)
 			alertDetails = strings.Join(dangerous, "\n")
+		} else if len(result.Warnings) > 0 && userPrefs != nil && userPrefs.AlertOnPartial {
+			shouldAlert = true
+			alertMessage = fmt.Sprintf("Sync partially failed for source '%s'", source.Name)
+			alertDetails = strings.Join(result.Warnings, "\n")
 		}
 	}
 
@@ -964,7 +1089,6 @@ func (s *Scheduler) maybeSendFailureAlert(sourceID string, source *db.Source, re
 			userEmail = user.Email
 		}
 	}
-	userPrefs := s.getUserAlertPrefs(source.UserID)
 
 	s.notifier.SendSyncFailureAlertWithPrefs(
 		s.ctx, sourceID, source.Name, userEmail,
@@ -972,7 +1096,9 @@ func (s *Scheduler) maybeSendFailureAlert(sourceID string, source *db.Source, re
 	)
 }
 
-// cleanupRoutine runs periodic cleanup of old sync logs.
+// cleanupRoutine runs periodic cleanup of old sync logs. The purge
+// also runs once at startup: an instance restarted more often than
+// cleanupInterval would otherwise never reach its first tick.
 func (s *Scheduler) cleanupRoutine() {
 	defer s.wg.Done()
 	defer recoverPanic("scheduler.cleanupRoutine")
@@ -981,6 +1107,8 @@ func (s *Scheduler) cleanupRoutine() {
 	defer ticker.Stop()
 
 	s.heartbeat(routineCleanup)
+	s.cleanupOldLogs()
+	s.cleanupDeletedEventBackups()
 
 	for {
 		select {
@@ -989,6 +1117,7 @@ func (s *Scheduler) cleanupRoutine() {
 		case <-ticker.C:
 			s.heartbeat(routineCleanup)
 			s.cleanupOldLogs()
+			s.cleanupDeletedEventBackups()
 			s.runAutomatedBackup()
 		}
 	}
@@ -1010,6 +1139,38 @@ func (s *Scheduler) runAutomatedBackup() {
 	}
 }
 
+// SetMaxConcurrentSyncs caps how many syncs may run at the same time;
+// further syncs wait for a slot. n <= 0 removes the cap. Call before
+// Start().
+func (s *Scheduler) SetMaxConcurrentSyncs(n int) {
+	if n <= 0 {
+		s.syncSlots = nil
+		return
+	}
+	s.syncSlots = make(chan struct{}, n)
+}
+
+// acquireSyncSlot blocks until a sync slot is free. It returns false
+// if the scheduler is stopped while waiting.
+func (s *Scheduler) acquireSyncSlot() bool {
+	if s.syncSlots == nil {
+		return true
+	}
+	select {
+	case s.syncSlots <- struct{}{}:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+// releaseSyncSlot frees a slot taken by acquireSyncSlot.
+func (s *Scheduler) releaseSyncSlot() {
+	if s.syncSlots != nil {
+		<-s.syncSlots
+	}
+}
+
 // SetBackupManager configures the automated backup manager. Called
 // from main.go after creating the backup.Manager, before Start().
 func (s *Scheduler) SetBackupManager(mgr interface {
@@ -1019,16 +1180,48 @@ func (s *Scheduler) SetBackupManager(mgr interface {
 	s.backupMgr = mgr
 }
 
-// cleanupOldLogs deletes sync logs older than retention period.
+// retentionCutoff returns the instant before which records are purged
+// for a retention of days, counted back from now. ok is false when
+// retention is disabled (days <= 0) and nothing should be deleted.
+func retentionCutoff(now time.Time, days int) (cutoff time.Time, ok bool) {
+	if days <= 0 {
+		return time.Time{}, false
+	}
+	return now.AddDate(0, 0, -days), true
+}
+
+// cleanupOldLogs deletes sync logs and malformed-event records older
+// than the retention period. A retention of 0 keeps everything.
 func (s *Scheduler) cleanupOldLogs() {
-	cutoff := time.Now().AddDate(0, 0, -s.logRetentionDays)
+	cutoff, ok := retentionCutoff(time.Now(), s.logRetentionDays)
+	if !ok {
+		return
+	}
+
 	deleted, err := s.db.CleanOldSyncLogs(cutoff)
 	if err != nil {
 		log.Printf("Failed to clean old sync logs: %v", err)
-		return
+	} else {
+		log.Printf("Cleaned %d sync logs older than %d days", deleted, s.logRetentionDays)
 	}
-	if deleted > 0 {
-		log.Printf("Cleaned %d old sync logs", deleted)
+
+	deletedMalformed, err := s.db.CleanOldMalformedEvents(cutoff)
+	if err != nil {
+		log.Printf("Failed to clean old malformed events: %v", err)
+	} else {
+		log.Printf("Cleaned %d malformed event records older than %d days", deletedMalformed, s.logRetentionDays)
+	}
+}
+
+// cleanupDeletedEventBackups drops deleted-event backups past their
+// TTL. They expire on their own schedule, independent of the log
+// retention setting.
+func (s *Scheduler) cleanupDeletedEventBackups() {
+	purged, err := s.db.CleanExpiredDeletedEventBackups(time.Now())
+	if err != nil {
+		log.Printf("Failed to clean expired deleted event backups: %v", err)
+	} else if purged > 0 {
+		log.Printf("Cleaned %d expired deleted event backups", purged)
 	}
 }
 
@@ -1037,7 +1230,7 @@ func (s *Scheduler) healthLogRoutine() {
 	defer s.wg.Done()
 	defer recoverPanic("scheduler.healthLogRoutine")
 
-	ticker := time.NewTicker(healthLogInterval)
+	ticker := time.NewTicker(s.healthLogPeriod())
 	defer ticker.Stop()
 
 	s.heartbeat(routineHealthLog)
@@ -1053,15 +1246,6 @@ func (s *Scheduler) healthLogRoutine() {
 	}
 }
 
-// logHealth logs current scheduler health status.
-func (s *Scheduler) logHealth() {
-	s.mu.RLock()
-	jobCount := len(s.jobs)
-	s.mu.RUnlock()
-
-	log.Printf("[Scheduler Health] Active jobs: %d", jobCount)
-}
-
 // staleDetectionRoutine periodically checks for stale sources and logs warnings.
 func (s *Scheduler) staleDetectionRoutine() {
 	defer s.wg.Done()
@@ -1136,7 +1320,7 @@ func (s *Scheduler) checkStaleSources() {
 				}
 
 				// Look up user alert preferences
-				userPrefs := s.getUserAlertPrefs(source.UserID)
+				userPrefs := s.getAlertPrefs(source)
 				s.notifier.SendStaleAlertWithPrefs(s.ctx, sourceID, source.Name, userEmail, timeSinceSync, staleThreshold, userPrefs)
 			}
 		}
@@ -1173,6 +1357,14 @@ func (s *Scheduler) IsSourceStale(source *db.Source) bool {
 	return now.Sub(source.CreatedAt) > staleThreshold
 }
 
+// getAlertPrefs retrieves the alert preferences for source's owner,
+// converted to notify.UserPreferences, with the source's own channel
+// overrides applied on top. Returns nil if neither the user nor the
+// source sets anything, or if there's an error.
+func (s *Scheduler) getAlertPrefs(source *db.Source) *notify.UserPreferences {
+	return s.getUserAlertPrefs(source.UserID).ForSource(source.AlertEmailEnabled, source.AlertWebhookEnabled)
+}
+
 // getUserAlertPrefs retrieves user alert preferences and converts them to notify.UserPreferences.
 // Returns nil if no preferences are set or if there's an error.
 func (s *Scheduler) getUserAlertPrefs(userID string) *notify.UserPreferences {
@@ -1190,5 +1382,6 @@ func (s *Scheduler) getUserAlertPrefs(userID string) *notify.UserPreferences {
 		WebhookEnabled:  dbPrefs.WebhookEnabled,
 		WebhookURL:      dbPrefs.WebhookURL,
 		CooldownMinutes: dbPrefs.CooldownMinutes,
+		AlertOnPartial:  dbPrefs.AlertOnPartial,
 	}
 }
@@ -1,6 +1,8 @@
 package scheduler
 
 import (
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -86,6 +88,13 @@ func TestSchedulerConstants(t *testing.T) {
 		}
 	})
 
+	t.Run("scheduler keeps zero retention as keep-forever", func(t *testing.T) {
+		sched := New(nil, nil, nil, 0)
+		if sched.logRetentionDays != 0 {
+			t.Errorf("expected logRetentionDays 0, got %d", sched.logRetentionDays)
+		}
+	})
+
 	t.Run("scheduler defaults to 30 when no retention arg", func(t *testing.T) {
 		sched := New(nil, nil, nil)
 		if sched.logRetentionDays != 30 {
@@ -531,6 +540,62 @@ func TestTriggerSync(t *testing.T) {
 	})
 }
 
+func TestSyncSlots(t *testing.T) {
+	t.Run("caps concurrent holders", func(t *testing.T) {
+		sched := New(nil, nil, nil)
+		sched.SetMaxConcurrentSyncs(2)
+
+		if !sched.acquireSyncSlot() || !sched.acquireSyncSlot() {
+			t.Fatal("expected two free slots")
+		}
+		acquired := make(chan bool)
+		go func() { acquired <- sched.acquireSyncSlot() }()
+		select {
+		case <-acquired:
+			t.Fatal("third acquire should block while both slots are held")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		sched.releaseSyncSlot()
+		select {
+		case ok := <-acquired:
+			if !ok {
+				t.Error("expected the waiting acquire to succeed")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("waiting acquire did not proceed after a release")
+		}
+	})
+
+	t.Run("stop releases waiters", func(t *testing.T) {
+		sched := New(nil, nil, nil)
+		sched.SetMaxConcurrentSyncs(1)
+		sched.acquireSyncSlot()
+
+		acquired := make(chan bool)
+		go func() { acquired <- sched.acquireSyncSlot() }()
+		sched.cancel()
+		select {
+		case ok := <-acquired:
+			if ok {
+				t.Error("expected acquire to fail once the scheduler stops")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("waiter was not released by cancellation")
+		}
+	})
+
+	t.Run("zero means unlimited", func(t *testing.T) {
+		sched := New(nil, nil, nil)
+		sched.SetMaxConcurrentSyncs(0)
+		for i := 0; i < 100; i++ {
+			if !sched.acquireSyncSlot() {
+				t.Fatal("expected no cap")
+			}
+		}
+	})
+}
+
 func TestConcurrentAccess(t *testing.T) {
 	t.Run("concurrent add and remove is safe", func(t *testing.T) {
 		sched := New(nil, nil, nil)
@@ -893,6 +958,59 @@ func TestMaybeSendFailureAlert_SuccessWithNoWarningsDoesNotFire(t *testing.T) {
 	}
 }
 
+// TestMaybeSendFailureAlert_AlertOnPartial verifies the per-user
+// escalation of partial syncs: routine warnings alert only for users
+// who turned AlertOnPartial on.
+func TestMaybeSendFailureAlert_AlertOnPartial(t *testing.T) {
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	defer database.Close()
+
+	partial := &caldav.SyncResult{
+		Success:  true,
+		Warnings: []string{"Failed to sync event: 412 Precondition Failed"},
+	}
+
+	for _, tt := range []struct {
+		name      string
+		email     string
+		prefs     *db.UserAlertPreferences
+		wantAlert bool
+	}{
+		{"preference on", "partial-on@example.com", &db.UserAlertPreferences{AlertOnPartial: true}, true},
+		{"preference off", "partial-off@example.com", &db.UserAlertPreferences{}, false},
+		{"no preferences saved", "partial-none@example.com", nil, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := database.GetOrCreateUser(tt.email, "Partial")
+			if err != nil {
+				t.Fatalf("failed to create user: %v", err)
+			}
+			if tt.prefs != nil {
+				tt.prefs.UserID = user.ID
+				if err := database.UpsertUserAlertPreferences(tt.prefs); err != nil {
+					t.Fatalf("failed to save preferences: %v", err)
+				}
+			}
+			sched, n := newTestSchedulerWithNotifier(t)
+			defer sched.cancel()
+			sched.db = database
+
+			source := &db.Source{ID: "src-" + user.ID, Name: "Partial Source", UserID: user.ID}
+			sched.maybeSendFailureAlert(source.ID, source, partial)
+
+			// The probe is blocked only if the scheduler's alert
+			// populated the failure cooldown.
+			fired := n.SendSyncFailureAlertWithPrefs(nil, source.ID, source.Name, "", "probe", "probe", nil)
+			if fired == tt.wantAlert {
+				t.Errorf("expected alert=%v for a partial sync", tt.wantAlert)
+			}
+		})
+	}
+}
+
 // TestMaybeSendFailureAlert_NilNotifierSafe verifies the nil-notifier
 // guard. A scheduler without a notifier (tests, stripped-down deploys)
 // must not panic.
@@ -906,3 +1024,196 @@ func TestMaybeSendFailureAlert_NilNotifierSafe(t *testing.T) {
 	// Must not panic.
 	sched.maybeSendFailureAlert(source.ID, source, result)
 }
+
+func TestStartupDelays(t *testing.T) {
+	now := time.Now()
+	future := now.Add(10 * time.Minute)
+	farFuture := now.Add(3 * time.Hour)
+	past := now.Add(-time.Hour)
+
+	sources := []*db.Source{
+		{ID: "overdue", SyncInterval: 3600, NextSyncAt: &past},
+		{ID: "not-due", SyncInterval: 3600, NextSyncAt: &future},
+		{ID: "never-scheduled", SyncInterval: 3600},
+		{ID: "interval-shortened", SyncInterval: 600, NextSyncAt: &farFuture},
+	}
+
+	delays := startupDelays(sources, now)
+
+	t.Run("overdue source is scheduled immediately", func(t *testing.T) {
+		if delays[0] != 0 {
+			t.Errorf("expected overdue source to start immediately, got %v", delays[0])
+		}
+	})
+
+	t.Run("not-yet-due source keeps its persisted schedule", func(t *testing.T) {
+		if delays[1] != 10*time.Minute {
+			t.Errorf("expected 10m delay, got %v", delays[1])
+		}
+	})
+
+	t.Run("further overdue sources are staggered", func(t *testing.T) {
+		if delays[2] != startupStagger {
+			t.Errorf("expected never-scheduled source at %v, got %v", startupStagger, delays[2])
+		}
+	})
+
+	t.Run("future delay is capped at the interval", func(t *testing.T) {
+		if delays[3] != 10*time.Minute {
+			t.Errorf("expected delay capped at 10m interval, got %v", delays[3])
+		}
+	})
+}
+
+func TestUpdateJobIntervalPersistsNextSyncAt(t *testing.T) {
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	defer database.Close()
+
+	user, err := database.GetOrCreateUser("sched@example.com", "Sched")
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	source := &db.Source{
+		UserID:           user.ID,
+		Name:             "Persisted",
+		SourceType:       db.SourceTypeCustom,
+		SourceURL:        "https://example.com/caldav",
+		SourceUsername:   "user",
+		SourcePassword:   "encrypted",
+		DestURL:          "https://dest.example.com/caldav",
+		DestUsername:     "dest",
+		DestPassword:     "encrypted",
+		SyncInterval:     3600,
+		SyncDirection:    db.SyncDirectionOneWay,
+		ConflictStrategy: db.ConflictSourceWins,
+		Enabled:          true,
+	}
+	if err := database.CreateSource(source); err != nil {
+		t.Fatalf("failed to create source: %v", err)
+	}
+
+	sched := New(database, nil, nil)
+	defer sched.cancel()
+	addJobDirectly(sched, source.ID, time.Hour)
+
+	before := time.Now()
+	sched.UpdateJobInterval(source.ID, 30*time.Minute)
+	sched.RemoveJob(source.ID)
+
+	got, err := database.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to reload source: %v", err)
+	}
+	if got.NextSyncAt == nil {
+		t.Fatal("expected next_sync_at to be persisted")
+	}
+	if got.NextSyncAt.Before(before.Add(29 * time.Minute)) {
+		t.Errorf("expected next_sync_at ~30m out, got %v", got.NextSyncAt.Sub(before))
+	}
+
+	// A source restored with next_sync_at in the past is overdue and
+	// must be scheduled immediately on Start.
+	if err := database.UpdateSourceNextSyncAt(source.ID, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("failed to backdate next_sync_at: %v", err)
+	}
+	now := time.Now()
+	sources, err := sched.startupSources(now)
+	if err != nil {
+		t.Fatalf("failed to load sources: %v", err)
+	}
+	if delays := startupDelays(sources, now); len(delays) != 1 || delays[0] != 0 {
+		t.Errorf("expected overdue source to start immediately, got %v", delays)
+	}
+}
+
+func TestStartupSources(t *testing.T) {
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	defer database.Close()
+
+	user, err := database.GetOrCreateUser("sched@example.com", "Sched")
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	now := time.Now()
+	nextSyncs := map[string]time.Time{
+		"not-due":          now.Add(time.Hour),
+		"slightly-overdue": now.Add(-time.Minute),
+		"most-overdue":     now.Add(-time.Hour),
+	}
+	for _, name := range []string{"not-due", "slightly-overdue", "most-overdue"} {
+		source := &db.Source{
+			UserID:           user.ID,
+			Name:             name,
+			SourceType:       db.SourceTypeCustom,
+			SourceURL:        "https://example.com/caldav",
+			SourceUsername:   "user",
+			SourcePassword:   "encrypted",
+			DestURL:          "https://dest.example.com/caldav",
+			DestUsername:     "dest",
+			DestPassword:     "encrypted",
+			SyncInterval:     3600,
+			SyncDirection:    db.SyncDirectionOneWay,
+			ConflictStrategy: db.ConflictSourceWins,
+			Enabled:          true,
+		}
+		if err := database.CreateSource(source); err != nil {
+			t.Fatalf("failed to create source: %v", err)
+		}
+		if err := database.UpdateSourceNextSyncAt(source.ID, nextSyncs[name]); err != nil {
+			t.Fatalf("failed to set next_sync_at: %v", err)
+		}
+	}
+
+	sched := New(database, nil, nil)
+	defer sched.cancel()
+	sources, err := sched.startupSources(now)
+	if err != nil {
+		t.Fatalf("startupSources: %v", err)
+	}
+	var names []string
+	for _, source := range sources {
+		names = append(names, source.Name)
+	}
+	want := []string{"most-overdue", "slightly-overdue", "not-due"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("expected overdue sources first, most overdue first: got %v, want %v", names, want)
+	}
+}
+
+func TestRetentionCutoff(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		days   int
+		want   time.Time
+		wantOK bool
+	}{
+		{30, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), true},
+		{1, time.Date(2026, 3, 30, 12, 0, 0, 0, time.UTC), true},
+		{365, time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC), true},
+		{0, time.Time{}, false},
+		{-5, time.Time{}, false},
+	} {
+		got, ok := retentionCutoff(now, tt.days)
+		if ok != tt.wantOK || !got.Equal(tt.want) {
+			t.Errorf("retentionCutoff(%d days) = %v, %v; want %v, %v", tt.days, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestCleanupOldLogsDisabledRetention(t *testing.T) {
+	// No database: a retention of 0 must return before touching it.
+	sched := New(nil, nil, nil, 0)
+	defer sched.cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("cleanup with retention 0 touched the database: %v", r)
+		}
+	}()
+	sched.cleanupOldLogs()
+}
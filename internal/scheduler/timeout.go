@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/macjediwizard/calbridgesync/internal/caldav"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// SetMaxSyncDuration sets how long a sync may run before it is
+// canceled and recorded as timed out, for sources without their own
+// MaxSyncDurationSecs. d <= 0 restores the default (syncTimeout).
+// Call before Start().
+func (s *Scheduler) SetMaxSyncDuration(d time.Duration) {
+	if d <= 0 {
+		d = syncTimeout
+	}
+	s.maxSyncDuration = d
+}
+
+// syncDuration returns how long a sync of source may run: its own
+// limit if it has one, else the scheduler's.
+func (s *Scheduler) syncDuration(source *db.Source) time.Duration {
+	if source.MaxSyncDurationSecs > 0 {
+		return time.Duration(source.MaxSyncDurationSecs) * time.Second
+	}
+	if s.maxSyncDuration <= 0 {
+		return syncTimeout
+	}
+	return s.maxSyncDuration
+}
+
+// withSyncTimeout derives the context a sync of source runs under.
+// When the sync's time is up the context is canceled with
+// caldav.ErrSyncTimedOut, so the engine stops at its next check,
+// records the sync as timed out and the sync frees its slot.
+func (s *Scheduler) withSyncTimeout(ctx context.Context, source *db.Source) (context.Context, context.CancelFunc) {
+	return context.WithTimeoutCause(ctx, s.syncDuration(source), caldav.ErrSyncTimedOut)
+}
@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/macjediwizard/calbridgesync/internal/caldav"
+	"github.com/macjediwizard/calbridgesync/internal/crypto"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func TestSyncDuration(t *testing.T) {
+	sched := New(nil, nil, nil)
+	defer sched.cancel()
+
+	if got := sched.syncDuration(&db.Source{}); got != syncTimeout {
+		t.Errorf("expected the default %v, got %v", syncTimeout, got)
+	}
+	sched.SetMaxSyncDuration(30 * time.Minute)
+	if got := sched.syncDuration(&db.Source{}); got != 30*time.Minute {
+		t.Errorf("expected the configured 30m, got %v", got)
+	}
+	if got := sched.syncDuration(&db.Source{MaxSyncDurationSecs: 600}); got != 10*time.Minute {
+		t.Errorf("expected the source's own 10m, got %v", got)
+	}
+	sched.SetMaxSyncDuration(0)
+	if got := sched.syncDuration(&db.Source{}); got != syncTimeout {
+		t.Errorf("expected 0 to restore the default, got %v", got)
+	}
+}
+
+// newHungSource creates a source whose source and destination both
+// point at a server that never answers, as a hung CalDAV server would,
+// and an engine to sync it with.
+func newHungSource(t *testing.T) (*db.DB, *caldav.SyncEngine, *db.Source) {
+	t.Helper()
+	// stop releases the handler so Close doesn't wait on it.
+	stop := make(chan struct{})
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-stop:
+		}
+	}))
+	t.Cleanup(hung.Close)
+	t.Cleanup(func() { close(stop) })
+
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	enc, err := crypto.NewEncryptor(make([]byte, 32))
+	if err != nil {
+		t.Fatalf("NewEncryptor: %v", err)
+	}
+	password, err := enc.Encrypt("secret")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	user, err := database.GetOrCreateUser("timeout@example.com", "Timeout")
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	source := &db.Source{
+		UserID:           user.ID,
+		Name:             "Hung",
+		SourceType:       db.SourceTypeCustom,
+		SourceURL:        hung.URL + "/dav/",
+		SourceUsername:   "user",
+		SourcePassword:   password,
+		DestURL:          hung.URL + "/dest/",
+		DestUsername:     "dest",
+		DestPassword:     password,
+		SyncInterval:     3600,
+		SyncDirection:    db.SyncDirectionOneWay,
+		ConflictStrategy: db.ConflictSourceWins,
+		Enabled:          true,
+	}
+	if err := database.CreateSource(source); err != nil {
+		t.Fatalf("failed to create source: %v", err)
+	}
+
+	engine := caldav.NewSyncEngine(database, enc)
+	engine.SetConnectionTestRetry(1, 0)
+	return database, engine, source
+}
+
+func TestExecuteSync_TimesOut(t *testing.T) {
+	database, engine, source := newHungSource(t)
+	sched := New(database, engine, nil)
+	defer sched.cancel()
+	sched.SetMaxSyncDuration(200 * time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		sched.executeSync(source.ID)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected the watchdog to cancel the hung sync")
+	}
+
+	got, err := database.GetSourceByID(source.ID)
+	if err != nil {
+		t.Fatalf("failed to reload source: %v", err)
+	}
+	if got.LastSyncStatus != db.SyncStatusError {
+		t.Errorf("expected status %q, got %q", db.SyncStatusError, got.LastSyncStatus)
+	}
+	if !strings.Contains(got.LastSyncMessage, "timed out") {
+		t.Errorf("expected the sync recorded as timed out, got %q", got.LastSyncMessage)
+	}
+	if sched.CancelSync(source.ID) {
+		t.Error("expected the timed-out sync to be gone")
+	}
+}
@@ -0,0 +1,134 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/macjediwizard/calbridgesync/internal/activity"
+	"github.com/macjediwizard/calbridgesync/internal/auth"
+)
+
+// The tracker has no change notifications, so the stream polls it.
+// Variables rather than constants so tests can run the loop quickly.
+var (
+	activityStreamPoll      = 500 * time.Millisecond
+	activityStreamKeepalive = 15 * time.Second
+)
+
+// APISourceActivityStream streams a source's sync progress as
+// Server-Sent Events. A "progress" event carries the tracker's snapshot
+// each time it changes; when the sync finishes a "done" event carries
+// the completed entry and the stream ends. If no sync is running the
+// stream waits for the next one. Comment lines keep idle connections
+// open through proxies.
+func (h *Handlers) APISourceActivityStream(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	sourceID := c.Param("id")
+	if _, err := h.db.GetSourceByIDForUser(sourceID, session.UserID); err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
+		return
+	}
+
+	tracker := h.syncEngine.GetActivityTracker()
+	if tracker == nil {
+		respondError(c, http.StatusServiceUnavailable, ErrCodeUnavailable, "Activity tracking is not available")
+		return
+	}
+
+	// The server's WriteTimeout is sized for ordinary requests and would
+	// cut the stream off mid-sync.
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("Activity stream: could not clear write deadline: %v", err)
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+	poll := time.NewTicker(activityStreamPoll)
+	defer poll.Stop()
+	keepalive := time.NewTicker(activityStreamKeepalive)
+	defer keepalive.Stop()
+
+	var current *activity.SyncActivity
+	var lastSent []byte
+	for {
+		if running := sourceActivity(tracker.GetActive(), sourceID); running != nil {
+			current = running
+			// Duration ticks on every read, so leave it out of the
+			// comparison or every poll would count as a change.
+			running.Duration = ""
+			data, err := json.Marshal(running)
+			if err == nil && string(data) != string(lastSent) {
+				if err := writeSSE(c, "progress", data); err != nil {
+					return
+				}
+				lastSent = data
+			}
+		} else if current != nil {
+			done := finishedActivity(tracker.GetRecent(), current)
+			data, err := json.Marshal(done)
+			if err != nil {
+				return
+			}
+			_ = writeSSE(c, "done", data)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(c.Writer, ": keepalive\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-poll.C:
+		}
+	}
+}
+
+// sourceActivity returns sourceID's entry in active, or nil.
+func sourceActivity(active []*activity.SyncActivity, sourceID string) *activity.SyncActivity {
+	for _, a := range active {
+		if a.SourceID == sourceID {
+			return a
+		}
+	}
+	return nil
+}
+
+// finishedActivity returns the completed entry for the sync last seen
+// as running. If it has already been pushed out of the recent list, the
+// last progress snapshot stands in for it.
+func finishedActivity(recent []*activity.SyncActivity, running *activity.SyncActivity) *activity.SyncActivity {
+	for _, a := range recent {
+		if a.SourceID == running.SourceID && a.StartedAt.Equal(running.StartedAt) {
+			return a
+		}
+	}
+	return running
+}
+
+// writeSSE writes one named event and flushes it to the client.
+func writeSSE(c *gin.Context, event string, data []byte) error {
+	if _, err := fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
@@ -0,0 +1,166 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/macjediwizard/calbridgesync/internal/activity"
+	"github.com/macjediwizard/calbridgesync/internal/caldav"
+)
+
+// sseFrame is one parsed Server-Sent Event.
+type sseFrame struct {
+	event string
+	data  string
+}
+
+// readSSEFrame reads the next event from r, skipping comment lines.
+func readSSEFrame(t *testing.T, r *bufio.Reader) sseFrame {
+	t.Helper()
+	var f sseFrame
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading stream: %v (partial frame %+v)", err, f)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && f.event != "":
+			return f
+		case strings.HasPrefix(line, "event: "):
+			f.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			f.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestAPISourceActivityStream(t *testing.T) {
+	oldPoll := activityStreamPoll
+	activityStreamPoll = 10 * time.Millisecond
+	defer func() { activityStreamPoll = oldPoll }()
+
+	th := setupTestHandlers(t)
+	defer th.cleanup()
+	th.handlers.syncEngine = caldav.NewSyncEngine(th.db, nil)
+	tracker := th.handlers.syncEngine.GetActivityTracker()
+
+	userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Stream Source")
+	other, _ := th.db.GetOrCreateUser("other@example.com", "Other")
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/sources/:id/activity/stream", func(c *gin.Context) {
+		if id := c.GetHeader("X-Test-User"); id != "" {
+			setAuthContext(c, id, "test@example.com")
+		}
+		th.handlers.APISourceActivityStream(c)
+	})
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	open := func(t *testing.T, userID string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/api/sources/"+source.ID+"/activity/stream", nil)
+		if userID != "" {
+			req.Header.Set("X-Test-User", userID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET stream: %v", err)
+		}
+		return resp
+	}
+
+	t.Run("streams progress until the sync finishes", func(t *testing.T) {
+		// Connect before the sync starts: the stream waits for it.
+		resp := open(t, userID)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+			t.Fatalf("expected an event stream, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+		body := bufio.NewReader(resp.Body)
+
+		tracker.StartSync(source.ID, source.Name, 2)
+		frame := readSSEFrame(t, body)
+		var got activity.SyncActivity
+		if err := json.Unmarshal([]byte(frame.data), &got); err != nil {
+			t.Fatalf("decoding %q: %v", frame.data, err)
+		}
+		if frame.event != "progress" || got.SourceID != source.ID || got.TotalCalendars != 2 {
+			t.Fatalf("expected a progress event for the source, got %s %+v", frame.event, got)
+		}
+
+		tracker.UpdateCalendar(source.ID, "Work", 1)
+		tracker.IncrementProgress(source.ID, 3, 0, 0, 0, 3)
+		for got.EventsCreated != 3 {
+			frame = readSSEFrame(t, body)
+			if frame.event != "progress" {
+				t.Fatalf("expected progress before done, got %s", frame.event)
+			}
+			if err := json.Unmarshal([]byte(frame.data), &got); err != nil {
+				t.Fatalf("decoding %q: %v", frame.data, err)
+			}
+		}
+		if got.CurrentCalendar != "Work" {
+			t.Errorf("expected the current calendar in the update, got %+v", got)
+		}
+
+		tracker.FinishSync(source.ID, true, "Synced", nil)
+		frame = readSSEFrame(t, body)
+		if err := json.Unmarshal([]byte(frame.data), &got); err != nil {
+			t.Fatalf("decoding %q: %v", frame.data, err)
+		}
+		if frame.event != "done" || got.Status != "completed" || got.Message != "Synced" {
+			t.Fatalf("expected a done event for the completed sync, got %s %+v", frame.event, got)
+		}
+		if _, err := body.ReadString('\n'); err == nil {
+			t.Error("expected the stream to close after the done event")
+		}
+	})
+
+	t.Run("returns when the client goes away", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/sources/"+source.ID+"/activity/stream", nil).WithContext(ctx)
+		c.Params = gin.Params{{Key: "id", Value: source.ID}}
+		setAuthContext(c, userID, "test@example.com")
+
+		returned := make(chan struct{})
+		go func() {
+			th.handlers.APISourceActivityStream(c)
+			close(returned)
+		}()
+		cancel()
+		select {
+		case <-returned:
+		case <-time.After(2 * time.Second):
+			t.Fatal("handler kept streaming after the request context was cancelled")
+		}
+	})
+
+	t.Run("returns 404 for another user's source", func(t *testing.T) {
+		resp := open(t, other.ID)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("returns unauthorized when not authenticated", func(t *testing.T) {
+		resp := open(t, "")
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package web
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/macjediwizard/calbridgesync/internal/auth"
+	"github.com/macjediwizard/calbridgesync/internal/config"
+	"github.com/macjediwizard/calbridgesync/internal/notify"
+)
+
+// NotifyConfig converts the ALERT_* settings into the notifier's
+// configuration. dashboardURL is the instance's BASE_URL, used for the
+// log links in alert emails.
+func NotifyConfig(alerts config.AlertConfig, dashboardURL string) *notify.Config {
+	return &notify.Config{
+		WebhookEnabled:  alerts.WebhookEnabled,
+		WebhookURL:      alerts.WebhookURL,
+		WebhookSecret:   alerts.WebhookSecret,
+		EmailEnabled:    alerts.EmailEnabled,
+		SMTPHost:        alerts.SMTPHost,
+		SMTPPort:        alerts.SMTPPort,
+		SMTPUsername:    alerts.SMTPUsername,
+		SMTPPassword:    alerts.SMTPPassword,
+		SMTPFrom:        alerts.SMTPFrom,
+		SMTPTo:          alerts.SMTPTo,
+		SMTPTLS:         alerts.SMTPTLS,
+		EmailHTML:       alerts.EmailHTML,
+		DashboardURL:    dashboardURL,
+		CooldownPeriod:  time.Duration(alerts.CooldownMinutes) * time.Minute,
+		MaxSendAttempts: alerts.MaxSendAttempts,
+		InitialBackoff:  time.Duration(alerts.InitialBackoffMS) * time.Millisecond,
+	}
+}
+
+// APIAlertReloadResponse reports which alert channels are enabled
+// after a reload.
+type APIAlertReloadResponse struct {
+	WebhookEnabled bool `json:"webhook_enabled"`
+	EmailEnabled   bool `json:"email_enabled"`
+}
+
+// APIReloadAlertConfig re-reads the ALERT_* settings and applies them
+// to the running notifier, so SMTP and webhook changes take effect
+// without a restart. An invalid configuration is rejected and the
+// notifier keeps the one it has; cooldown and stale tracking carry
+// over either way. Admins only, by auth.RequireAdmin.
+func (h *Handlers) APIReloadAlertConfig(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	alerts, err := config.ReloadAlertConfig()
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, err.Error())
+		return
+	}
+	dashboardURL := ""
+	if h.cfg != nil {
+		dashboardURL = h.cfg.Server.BaseURL
+	}
+	if err := h.notifier.UpdateConfig(NotifyConfig(alerts, dashboardURL)); err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, fmt.Sprintf("Invalid alert configuration: %v", err))
+		return
+	}
+
+	log.Printf("Alert configuration reloaded by %s (webhook: %v, email: %v)",
+		session.Email, alerts.WebhookEnabled, alerts.EmailEnabled)
+	h.audit(c, "alerts.reload", "config", "alerts",
+		fmt.Sprintf("webhook=%v email=%v", alerts.WebhookEnabled, alerts.EmailEnabled))
+	c.JSON(http.StatusOK, APIAlertReloadResponse{
+		WebhookEnabled: alerts.WebhookEnabled,
+		EmailEnabled:   alerts.EmailEnabled,
+	})
+}
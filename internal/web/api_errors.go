@@ -0,0 +1,68 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/macjediwizard/calbridgesync/internal/auth"
+)
+
+// Error codes returned in APIError.Code. They are part of the API:
+// clients branch on them, so existing codes never change meaning.
+const (
+	ErrCodeUnauthorized        = auth.ErrCodeUnauthorized
+	ErrCodeForbidden           = auth.ErrCodeForbidden
+	ErrCodeInvalidRequest      = "INVALID_REQUEST"
+	ErrCodeValidation          = "VALIDATION_ERROR"
+	ErrCodeNotFound            = "NOT_FOUND"
+	ErrCodeSourceNotFound      = "SOURCE_NOT_FOUND"
+	ErrCodeDestinationNotFound = "DESTINATION_NOT_FOUND"
+	ErrCodeConnectionFailed    = "CONNECTION_FAILED"
+	ErrCodeConflict            = "CONFLICT"
+	ErrCodeDestReadOnly        = "DEST_READ_ONLY"
+	ErrCodePayloadTooLarge     = "PAYLOAD_TOO_LARGE"
+	ErrCodeRateLimited         = "RATE_LIMITED"
+	ErrCodeUnavailable         = "SERVICE_UNAVAILABLE"
+	ErrCodeInternal            = "INTERNAL_ERROR"
+)
+
+// APIError is the body of every error response from the JSON API. It
+// is defined in auth so the auth middleware answers with the same body.
+type APIError = auth.APIError
+
+// respondError sends an APIError with the given status, code and
+// message.
+func respondError(c *gin.Context, status int, code, message string) {
+	c.JSON(status, APIError{Code: code, Message: message, Error: message})
+}
+
+// respondErrorDetails is respondError with details a client can act
+// on, such as the conflicting resource.
+func respondErrorDetails(c *gin.Context, status int, code, message string, details any) {
+	c.JSON(status, APIError{Code: code, Message: message, Details: details, Error: message})
+}
+
+// errorCodeForStatus is the code for an error that has nothing more
+// specific than its status to go on.
+func errorCodeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrCodeValidation
+	case http.StatusUnauthorized:
+		return ErrCodeUnauthorized
+	case http.StatusForbidden:
+		return ErrCodeForbidden
+	case http.StatusNotFound:
+		return ErrCodeNotFound
+	case http.StatusConflict:
+		return ErrCodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return ErrCodePayloadTooLarge
+	case http.StatusTooManyRequests:
+		return ErrCodeRateLimited
+	case http.StatusServiceUnavailable:
+		return ErrCodeUnavailable
+	default:
+		return ErrCodeInternal
+	}
+}
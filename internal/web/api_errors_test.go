@@ -0,0 +1,131 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/macjediwizard/calbridgesync/internal/auth"
+	"github.com/macjediwizard/calbridgesync/internal/config"
+)
+
+// decodeAPIError parses an error response, failing the test when the
+// body isn't an APIError with a code and a message.
+func decodeAPIError(t *testing.T, w *httptest.ResponseRecorder) APIError {
+	t.Helper()
+	var apiErr APIError
+	if err := json.Unmarshal(w.Body.Bytes(), &apiErr); err != nil {
+		t.Fatalf("failed to parse error response %q: %v", w.Body.String(), err)
+	}
+	if apiErr.Code == "" || apiErr.Message == "" {
+		t.Fatalf("expected a code and a message, got %q", w.Body.String())
+	}
+	if apiErr.Error != apiErr.Message {
+		t.Errorf("expected error to repeat the message, got %q and %q", apiErr.Error, apiErr.Message)
+	}
+	return apiErr
+}
+
+func TestAPIErrors(t *testing.T) {
+	th := setupTestHandlers(t)
+	defer th.cleanup()
+	th.handlers.cfg = &config.Config{}
+	user, _ := th.db.GetOrCreateUser("errors@example.com", "Errors")
+
+	// A server that is gone by the time it's dialed.
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		body    string
+		params  gin.Params
+		anon    bool
+		handler func(*gin.Context)
+		status  int
+		code    string
+	}{
+		{
+			name: "unauthenticated", method: http.MethodGet, path: "/api/sources", anon: true,
+			handler: th.handlers.APIListSources, status: http.StatusUnauthorized, code: ErrCodeUnauthorized,
+		},
+		{
+			// Answered by the auth package's middleware, with the
+			// same body as the handlers here.
+			name: "not an administrator", method: http.MethodPost, path: "/api/admin/alerts/reload",
+			handler: auth.RequireAdmin(), status: http.StatusForbidden, code: ErrCodeForbidden,
+		},
+		{
+			name: "unknown source", method: http.MethodGet, path: "/api/sources/missing",
+			params:  gin.Params{{Key: "id", Value: "missing"}},
+			handler: th.handlers.APIGetSource, status: http.StatusNotFound, code: ErrCodeSourceNotFound,
+		},
+		{
+			name: "malformed body", method: http.MethodPost, path: "/api/sources", body: "{not json",
+			handler: th.handlers.APICreateSource, status: http.StatusBadRequest, code: ErrCodeInvalidRequest,
+		},
+		{
+			name: "invalid field", method: http.MethodPost, path: "/api/sources",
+			body:    `{"name":"Work","source_url":"https://caldav.example.com/","source_username":"u","source_password":"p","dest_url":"https://dest.example.com/","dest_username":"u","dest_password":"p","sync_direction":"sideways"}`,
+			handler: th.handlers.APICreateSource, status: http.StatusBadRequest, code: ErrCodeValidation,
+		},
+		{
+			name: "unreachable server", method: http.MethodPost, path: "/api/calendars/discover",
+			body:    `{"url":"` + closed.URL + `/dav/","username":"u","password":"p"}`,
+			handler: th.handlers.APIDiscoverCalendars, status: http.StatusBadRequest, code: ErrCodeConnectionFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			c.Params = tt.params
+			if !tt.anon {
+				setAuthContext(c, user.ID, "errors@example.com")
+			}
+			tt.handler(c)
+
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if got := decodeAPIError(t, w); got.Code != tt.code {
+				t.Errorf("expected code %s, got %s (%q)", tt.code, got.Code, got.Message)
+			}
+		})
+	}
+}
+
+func TestAPIErrors_Details(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	respondErrorDetails(c, http.StatusConflict, ErrCodeConflict, "Disable source first", gin.H{"source_id": "src-1"})
+
+	got := decodeAPIError(t, w)
+	details, _ := got.Details.(map[string]any)
+	if got.Code != ErrCodeConflict || details["source_id"] != "src-1" {
+		t.Errorf("expected the conflict with its source, got %+v", got)
+	}
+}
+
+func TestErrorCodeForStatus(t *testing.T) {
+	for status, want := range map[int]string{
+		http.StatusBadRequest:          ErrCodeValidation,
+		http.StatusUnauthorized:        ErrCodeUnauthorized,
+		http.StatusForbidden:           ErrCodeForbidden,
+		http.StatusNotFound:            ErrCodeNotFound,
+		http.StatusConflict:            ErrCodeConflict,
+		http.StatusTooManyRequests:     ErrCodeRateLimited,
+		http.StatusServiceUnavailable:  ErrCodeUnavailable,
+		http.StatusInternalServerError: ErrCodeInternal,
+	} {
+		if got := errorCodeForStatus(status); got != want {
+			t.Errorf("errorCodeForStatus(%d) = %s, want %s", status, got, want)
+		}
+	}
+}
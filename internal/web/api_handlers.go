@@ -1,13 +1,20 @@
 package web
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/gin-gonic/gin"
 	"github.com/macjediwizard/calbridgesync/internal/activity"
@@ -15,6 +22,7 @@ import (
 	"github.com/macjediwizard/calbridgesync/internal/caldav"
 	"github.com/macjediwizard/calbridgesync/internal/db"
 	"github.com/macjediwizard/calbridgesync/internal/notify"
+	"github.com/macjediwizard/calbridgesync/internal/scheduler"
 	"github.com/macjediwizard/calbridgesync/internal/version"
 )
 
@@ -62,8 +70,336 @@ const (
 	maxURLLength      = 500
 	maxUsernameLength = 100
 	maxPasswordLength = 500
+
+	// maxAttendeeRewriteEntries bounds the per-source attendee rewrite
+	// map; a handful of aliases is the realistic use case.
+	maxAttendeeRewriteEntries = 50
+	maxCalAddressLength       = 320
+)
+
+// validateAttendeeRewrite checks an attendee rewrite map from the API.
+// Returns an error message if validation fails, empty string if valid.
+func validateAttendeeRewrite(m map[string]string) string {
+	if len(m) > maxAttendeeRewriteEntries {
+		return fmt.Sprintf("Too many attendee rewrite entries (max %d)", maxAttendeeRewriteEntries)
+	}
+	for from, to := range m {
+		if strings.TrimSpace(from) == "" || strings.TrimSpace(to) == "" {
+			return "Attendee rewrite addresses must not be empty"
+		}
+		if len(from) > maxCalAddressLength || len(to) > maxCalAddressLength {
+			return "Attendee rewrite address is too long"
+		}
+		if strings.ContainsAny(from+to, "\r\n") {
+			return "Attendee rewrite addresses must not contain line breaks"
+		}
+	}
+	return ""
+}
+
+// validateResultWebhookURL applies the alert-webhook SSRF rules to a
+// source's result webhook so a bad URL is refused at save time rather
+// than failing silently on every sync.
+// Returns an error message if validation fails, empty string if valid.
+func validateResultWebhookURL(webhookURL string) string {
+	if webhookURL == "" {
+		return ""
+	}
+	if len(webhookURL) > maxURLLength {
+		return "Result webhook URL is too long"
+	}
+	if err := notify.ValidateWebhookURL(webhookURL); err != nil {
+		return "Invalid result webhook URL: " + err.Error()
+	}
+	return ""
+}
+
+// validateUIDPrefix checks a source's UID namespace prefix against its
+// default sync direction. A prefixed source must be one-way: reverse
+// sync would push namespaced copies back onto the source.
+// Returns an error message if validation fails, empty string if valid.
+func validateUIDPrefix(prefix, syncDirection string) string {
+	if !caldav.ValidUIDPrefix(prefix) {
+		return "UID prefix may only contain letters, digits, '.', '_' and '-' (max 32 characters)"
+	}
+	if prefix != "" && db.SyncDirection(syncDirection) == db.SyncDirectionTwoWay {
+		return "UID prefix requires one-way sync"
+	}
+	return ""
+}
+
+// maxRequestsPerSecondLimit is the highest per-source request pace
+// accepted. Anything faster is effectively unthrottled.
+const maxRequestsPerSecondLimit = 100
+
+// validateMaxRequestsPerSecond checks a source's CalDAV request pace.
+// 0 disables throttling.
+// Returns an error message if validation fails, empty string if valid.
+func validateMaxRequestsPerSecond(rps float64) string {
+	if rps < 0 || rps > maxRequestsPerSecondLimit {
+		return "Max requests per second must be between 0 and 100 (0 = unlimited)"
+	}
+	return ""
+}
+
+// Bounds on a source's own maximum sync duration, in seconds.
+const (
+	minSyncDurationSecs = 60
+	maxSyncDurationSecs = 24 * 60 * 60
+)
+
+// validateMaxSyncDuration checks how long a source's syncs may run.
+// 0 uses the server default.
+// Returns an error message if validation fails, empty string if valid.
+func validateMaxSyncDuration(secs int) string {
+	if secs != 0 && (secs < minSyncDurationSecs || secs > maxSyncDurationSecs) {
+		return fmt.Sprintf("Max sync duration must be between %d and %d seconds (0 = server default)",
+			minSyncDurationSecs, maxSyncDurationSecs)
+	}
+	return ""
+}
+
+// validateInitialSyncEventCap checks how many events a calendar's first
+// sync may fetch. 0 fetches them all.
+// Returns an error message if validation fails, empty string if valid.
+func validateInitialSyncEventCap(n int) string {
+	if n < 0 || n > caldav.MaxInitialSyncEventCap {
+		return fmt.Sprintf("Initial sync event cap must be between 0 and %d (0 = no cap)", caldav.MaxInitialSyncEventCap)
+	}
+	return ""
+}
+
+const (
+	// maxCustomHeaders bounds the per-source custom header map.
+	maxCustomHeaders      = 20
+	maxCustomHeaderLength = 1024
 )
 
+// reservedCustomHeaders are headers a source may not set: credentials
+// and the framing headers the CalDAV client computes per request.
+var reservedCustomHeaders = map[string]bool{
+	"Authorization":  true,
+	"Host":           true,
+	"Content-Length": true,
+	"Content-Type":   true,
+}
+
+// validateCustomHeaders checks a source's extra CalDAV request headers.
+// Returns an error message if validation fails, empty string if valid.
+func validateCustomHeaders(m map[string]string) string {
+	if len(m) > maxCustomHeaders {
+		return fmt.Sprintf("Too many custom headers (max %d)", maxCustomHeaders)
+	}
+	for name, value := range m {
+		if !validHeaderName(name) {
+			return fmt.Sprintf("Invalid custom header name %q", name)
+		}
+		if reservedCustomHeaders[http.CanonicalHeaderKey(name)] {
+			return fmt.Sprintf("Custom headers cannot set %s", http.CanonicalHeaderKey(name))
+		}
+		if len(value) > maxCustomHeaderLength {
+			return fmt.Sprintf("Custom header %s value is too long", name)
+		}
+		if strings.ContainsAny(value, "\r\n\x00") {
+			return fmt.Sprintf("Custom header %s value must not contain line breaks", name)
+		}
+	}
+	return ""
+}
+
+// maxIncludeCategories bounds a source's category filter list.
+const maxIncludeCategories = 20
+
+// validateIncludeCategories checks a source's CATEGORIES filter. Values
+// can't contain commas since CATEGORIES splits on them.
+// Returns an error message if validation fails, empty string if valid.
+func validateIncludeCategories(cats []string) string {
+	if len(cats) > maxIncludeCategories {
+		return fmt.Sprintf("Too many categories (max %d)", maxIncludeCategories)
+	}
+	for _, cat := range cats {
+		if strings.TrimSpace(cat) == "" {
+			return "Categories must not be empty"
+		}
+		if len(cat) > maxNameLength {
+			return "Category is too long (max 100 characters)"
+		}
+		if strings.ContainsAny(cat, ",\r\n\x00") {
+			return fmt.Sprintf("Category %q must not contain commas or line breaks", cat)
+		}
+	}
+	return ""
+}
+
+// maxSourceTags bounds how many tags a source carries.
+const maxSourceTags = 20
+
+// maxTagLength bounds a single tag.
+const maxTagLength = 50
+
+// validateTags checks a source's dashboard tags. Commas are refused so
+// tags can be listed comma-separated.
+// Returns an error message if validation fails, empty string if valid.
+func validateTags(tags []string) string {
+	if len(tags) > maxSourceTags {
+		return fmt.Sprintf("Too many tags (max %d)", maxSourceTags)
+	}
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" {
+			return "Tags must not be empty"
+		}
+		if len(tag) > maxTagLength {
+			return fmt.Sprintf("Tag is too long (max %d characters)", maxTagLength)
+		}
+		if strings.ContainsAny(tag, ",\r\n\x00") {
+			return fmt.Sprintf("Tag %q must not contain commas or line breaks", tag)
+		}
+		if seen[strings.ToLower(tag)] {
+			return fmt.Sprintf("Duplicate tag %q", tag)
+		}
+		seen[strings.ToLower(tag)] = true
+	}
+	return ""
+}
+
+// maxPreserveDestProps bounds how many properties a source preserves.
+const maxPreserveDestProps = 20
+
+// icalPropertyName matches an iCalendar property name (RFC 5545 §3.1).
+var icalPropertyName = regexp.MustCompile(`^[A-Za-z0-9-]{1,64}$`)
+
+// validatePreserveDestProps checks the property names a source keeps
+// from the destination on update. UID and RECURRENCE-ID identify the
+// event and can't be preserved.
+// Returns an error message if validation fails, empty string if valid.
+func validatePreserveDestProps(names []string) string {
+	if len(names) > maxPreserveDestProps {
+		return fmt.Sprintf("Too many preserved properties (max %d)", maxPreserveDestProps)
+	}
+	for _, name := range names {
+		if !icalPropertyName.MatchString(name) {
+			return fmt.Sprintf("Invalid property name %q", name)
+		}
+		switch strings.ToUpper(name) {
+		case "UID", "RECURRENCE-ID":
+			return fmt.Sprintf("Property %s identifies the event and can't be preserved", strings.ToUpper(name))
+		}
+	}
+	return ""
+}
+
+// validateTextRules checks a source's summary/description find-replace
+// rules, including that each regex is within caldav's complexity bound.
+// Returns an error message if validation fails, empty string if valid.
+func validateTextRules(rules []db.TextRule) string {
+	if err := caldav.ValidateTextRules(rules); err != nil {
+		return fmt.Sprintf("Invalid text rules: %v", err)
+	}
+	return ""
+}
+
+// validateCABundle checks a source's PEM CA bundle parses.
+// Returns an error message if validation fails, empty string if valid.
+func validateCABundle(bundle string) string {
+	if err := caldav.ValidateCABundle(bundle); err != nil {
+		return fmt.Sprintf("Invalid CA bundle: %v", err)
+	}
+	return ""
+}
+
+// validateProxyURL checks a source's HTTP(S) proxy URL.
+// Returns an error message if validation fails, empty string if valid.
+func validateProxyURL(proxyURL string) string {
+	if err := caldav.ValidateProxyURL(proxyURL); err != nil {
+		return fmt.Sprintf("Invalid proxy URL: %v", err)
+	}
+	return ""
+}
+
+// validateInviteFilter checks a source's account email and that
+// SkipInvitedEvents has an address to compare organizers against,
+// given or taken from the source username.
+// Returns an error message if validation fails, empty string if valid.
+func validateInviteFilter(source *db.Source) string {
+	if source.AccountEmail != "" {
+		addr, err := mail.ParseAddress(source.AccountEmail)
+		if err != nil || addr.Address != source.AccountEmail || len(addr.Address) > 254 {
+			return "Account email must be a plain email address"
+		}
+	}
+	if source.SkipInvitedEvents && caldav.AccountEmail(source) == "" {
+		return "Skipping invited events needs the account email"
+	}
+	return ""
+}
+
+// maxSourceStampLength bounds a source's stamp template.
+const maxSourceStampLength = 200
+
+// validateSourceStamp checks the template a source stamps its events
+// with. Line breaks and other control characters are refused.
+// Returns an error message if validation fails, empty string if valid.
+func validateSourceStamp(stamp string) string {
+	if len(stamp) > maxSourceStampLength {
+		return fmt.Sprintf("Source stamp is too long (max %d characters)", maxSourceStampLength)
+	}
+	for _, r := range stamp {
+		if unicode.IsControl(r) {
+			return "Source stamp must not contain control characters"
+		}
+	}
+	return ""
+}
+
+// filterSourcesByTag keeps the sources carrying at least one of tags,
+// compared case-insensitively. No tags keeps every source; untagged
+// sources never match a filter.
+func filterSourcesByTag(sources []*db.Source, tags []string) []*db.Source {
+	if len(tags) == 0 {
+		return sources
+	}
+	filtered := make([]*db.Source, 0, len(sources))
+	for _, s := range sources {
+		for _, want := range tags {
+			if slices.ContainsFunc(s.Tags, func(tag string) bool { return strings.EqualFold(tag, want) }) {
+				filtered = append(filtered, s)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// validateTargetTimezone checks a source's timezone conversion target.
+// Empty disables conversion.
+// Returns an error message if validation fails, empty string if valid.
+func validateTargetTimezone(name string) string {
+	if name == "" {
+		return ""
+	}
+	if _, err := caldav.LoadTargetTimezone(name); err != nil {
+		return fmt.Sprintf("Unknown timezone %q (use an IANA name such as America/New_York)", name)
+	}
+	return ""
+}
+
+// validHeaderName reports whether name is a non-empty RFC 7230 token.
+func validHeaderName(name string) bool {
+	if name == "" || len(name) > 100 {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 // validateSourceInput validates source input fields for length and enum values.
 // Returns an error message if validation fails, empty string if valid.
 func validateSourceInput(name, sourceType, syncDirection, conflictStrategy, sourceURL, destURL, sourceUsername, destUsername string) string {
@@ -98,6 +434,51 @@ func validateSourceInput(name, sourceType, syncDirection, conflictStrategy, sour
 	return ""
 }
 
+// appSpecificPasswordPattern is the format Apple issues app-specific
+// passwords in.
+var appSpecificPasswordPattern = regexp.MustCompile(`^[a-z]{4}-[a-z]{4}-[a-z]{4}-[a-z]{4}$`)
+
+// presetSourceURL returns sourceURL, or the provider's default base URL
+// when sourceURL is blank and the source type has one.
+func presetSourceURL(sourceType, sourceURL string) string {
+	if strings.TrimSpace(sourceURL) != "" {
+		return sourceURL
+	}
+	return db.SourcePresets[db.SourceType(sourceType)].BaseURL
+}
+
+// validateProviderSource checks a source's URL and password against its
+// provider preset. An empty password is not checked, so updates that
+// keep the stored password pass.
+// Returns an error message if validation fails, empty string if valid.
+func validateProviderSource(sourceType, sourceURL, password string) string {
+	preset, ok := db.SourcePresets[db.SourceType(sourceType)]
+	if !ok {
+		return ""
+	}
+	if len(preset.URLHosts) > 0 && sourceURL != "" {
+		u, err := url.Parse(sourceURL)
+		if err != nil || !hostMatches(strings.ToLower(u.Hostname()), preset.URLHosts) {
+			return fmt.Sprintf("%s sources must use a %s CalDAV URL such as %s", preset.Name, preset.Name, preset.BaseURL)
+		}
+	}
+	if preset.AppSpecificPassword && password != "" && !appSpecificPasswordPattern.MatchString(password) {
+		return fmt.Sprintf("%s requires an app-specific password. %s", preset.Name, preset.AuthHint)
+	}
+	return ""
+}
+
+// hostMatches reports whether host is one of hosts; an entry starting
+// with "." matches any subdomain of it.
+func hostMatches(host string, hosts []string) bool {
+	for _, h := range hosts {
+		if host == h || (strings.HasPrefix(h, ".") && strings.HasSuffix(host, h)) {
+			return true
+		}
+	}
+	return false
+}
+
 // APISource represents a source in JSON format for the API.
 type APISource struct {
 	ID                string              `json:"id"`
@@ -114,8 +495,37 @@ type APISource struct {
 	SelectedCalendars []APICalendarConfig `json:"selected_calendars"`
 	Enabled           bool                `json:"enabled"`
 	StripAlarms       bool                `json:"strip_alarms"`
+	AttendeeRewrite   map[string]string   `json:"attendee_rewrite,omitempty"`
+	UIDPrefix         string              `json:"uid_prefix,omitempty"`
+	ResultWebhookURL  string              `json:"result_webhook_url,omitempty"`
+	MaxRequestsPerSec float64             `json:"max_requests_per_second,omitempty"`
+	MaxSyncDuration   int                 `json:"max_sync_duration_secs,omitempty"`
+	InitialSyncCap    int                 `json:"initial_sync_event_cap,omitempty"`
+	CustomHeaders     map[string]string   `json:"custom_headers,omitempty"`
+	VerifyWrites      bool                `json:"verify_writes"`
+	IncludeCategories []string            `json:"include_categories,omitempty"`
+	Tags              []string            `json:"tags,omitempty"`
+	PreserveDestProps []string            `json:"preserve_dest_props,omitempty"`
+	TextRules         []db.TextRule       `json:"text_rules,omitempty"`
+	SourceStamp       string              `json:"source_stamp,omitempty"`
+	CABundle          string              `json:"ca_bundle,omitempty"`
+	ProxyURL          string              `json:"proxy_url,omitempty"`
+	TargetTimezone    string              `json:"target_timezone,omitempty"`
+	SyncJournals      bool                `json:"sync_journals"`
+	DestReadOnly      bool                `json:"dest_read_only"`
+	SyncAttachments   bool                `json:"sync_attachments"`
+	GenerateUIDs      bool                `json:"generate_missing_uids"`
+	SkipCancelled     bool                `json:"skip_cancelled"`
+	SkipInvited       bool                `json:"skip_invited_events"`
+	AccountEmail      string              `json:"account_email,omitempty"`
+	MinimizeTimezones bool                `json:"minimize_timezones"`
+	AlertEmail        *bool               `json:"alert_email_enabled"`
+	AlertWebhook      *bool               `json:"alert_webhook_enabled"`
 	SyncStatus        string              `json:"sync_status"`
 	LastSyncAt        *string             `json:"last_sync_at"`
+	LastSuccessAt     *string             `json:"last_success_at"`
+	LastErrorDetail   string              `json:"last_error_detail,omitempty"`
+	ClockSkewSeconds  int                 `json:"clock_skew_seconds,omitempty"`
 	NextSyncAt        *string             `json:"next_sync_at"`
 	IsStale           bool                `json:"is_stale"`
 	CreatedAt         string              `json:"created_at"`
@@ -133,6 +543,7 @@ type APICalendar struct {
 // APICalendarConfig represents per-calendar configuration including sync direction.
 type APICalendarConfig struct {
 	Path          string `json:"path"`
+	Name          string `json:"name,omitempty"`           // display name, used to re-match a moved calendar
 	SyncDirection string `json:"sync_direction,omitempty"` // empty = use source default
 }
 
@@ -192,6 +603,9 @@ type APISyncSummary struct {
 type APIAuthStatus struct {
 	Authenticated bool     `json:"authenticated"`
 	User          *APIUser `json:"user,omitempty"`
+	// CSRFToken is sent back as X-CSRF-Token on state-changing
+	// requests; see auth.ValidateAPICSRF.
+	CSRFToken string `json:"csrf_token,omitempty"`
 }
 
 // APIUser represents a user in JSON format.
@@ -200,6 +614,8 @@ type APIUser struct {
 	Email  string `json:"email"`
 	Name   string `json:"name"`
 	Avatar string `json:"avatar,omitempty"`
+	// IsAdmin is true for instance administrators.
+	IsAdmin bool `json:"is_admin"`
 }
 
 // sourceToAPI converts a db.Source to APISource (base conversion without scheduler info).
@@ -209,6 +625,7 @@ func sourceToAPI(s *db.Source) *APISource {
 	for _, c := range s.SelectedCalendars {
 		apiCalendars = append(apiCalendars, APICalendarConfig{
 			Path:          c.Path,
+			Name:          c.Name,
 			SyncDirection: string(c.SyncDirection),
 		})
 	}
@@ -228,7 +645,35 @@ func sourceToAPI(s *db.Source) *APISource {
 		SelectedCalendars: apiCalendars,
 		Enabled:           s.Enabled,
 		StripAlarms:       s.StripAlarms,
+		AttendeeRewrite:   s.AttendeeRewrite,
+		UIDPrefix:         s.UIDPrefix,
+		ResultWebhookURL:  s.ResultWebhookURL,
+		MaxRequestsPerSec: s.MaxRequestsPerSecond,
+		MaxSyncDuration:   s.MaxSyncDurationSecs,
+		InitialSyncCap:    s.InitialSyncEventCap,
+		CustomHeaders:     s.CustomHeaders,
+		VerifyWrites:      s.VerifyWrites,
+		IncludeCategories: s.IncludeCategories,
+		Tags:              s.Tags,
+		PreserveDestProps: s.PreserveDestProps,
+		TextRules:         s.TextRules,
+		SourceStamp:       s.SourceStamp,
+		CABundle:          s.CABundle,
+		ProxyURL:          s.ProxyURL,
+		TargetTimezone:    s.TargetTimezone,
+		SyncJournals:      s.SyncJournals,
+		DestReadOnly:      s.DestReadOnly,
+		SyncAttachments:   s.SyncAttachments,
+		GenerateUIDs:      s.GenerateMissingUIDs,
+		SkipCancelled:     s.SkipCancelled,
+		SkipInvited:       s.SkipInvitedEvents,
+		AccountEmail:      s.AccountEmail,
+		MinimizeTimezones: s.MinimizeTimezones,
+		AlertEmail:        s.AlertEmailEnabled,
+		AlertWebhook:      s.AlertWebhookEnabled,
 		SyncStatus:        string(s.LastSyncStatus),
+		LastErrorDetail:   s.LastErrorDetail,
+		ClockSkewSeconds:  s.ClockSkewSeconds,
 		CreatedAt:         s.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:         s.UpdatedAt.Format(time.RFC3339),
 	}
@@ -236,6 +681,10 @@ func sourceToAPI(s *db.Source) *APISource {
 		ts := s.LastSyncAt.Format(time.RFC3339)
 		api.LastSyncAt = &ts
 	}
+	if s.LastSuccessAt != nil {
+		ts := s.LastSuccessAt.Format(time.RFC3339)
+		api.LastSuccessAt = &ts
+	}
 	// Ensure selected_calendars is never null in JSON
 	if api.SelectedCalendars == nil {
 		api.SelectedCalendars = []APICalendarConfig{}
@@ -278,7 +727,8 @@ func syncLogToAPI(l *db.SyncLog) *APISyncLog {
 		CreatedAt:       l.CreatedAt.Format(time.RFC3339),
 	}
 	if l.Details != "" {
-		api.Details = &l.Details
+		details := summarizeSyncLogDetails(l.Details)
+		api.Details = &details
 	}
 	if l.Duration > 0 {
 		dur := l.Duration.Seconds()
@@ -298,11 +748,13 @@ func (h *Handlers) APIAuthStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, APIAuthStatus{
 		Authenticated: true,
 		User: &APIUser{
-			ID:     session.UserID,
-			Email:  session.Email,
-			Name:   session.Name,
-			Avatar: session.Picture,
+			ID:      session.UserID,
+			Email:   session.Email,
+			Name:    session.Name,
+			Avatar:  session.Picture,
+			IsAdmin: session.IsAdmin,
 		},
+		CSRFToken: session.CSRFToken,
 	})
 }
 
@@ -330,7 +782,7 @@ func (h *Handlers) audit(c *gin.Context, action, resourceType, resourceID, detai
 func (h *Handlers) APIGetAuditLogs(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 	page := 1
@@ -341,7 +793,7 @@ func (h *Handlers) APIGetAuditLogs(c *gin.Context) {
 	}
 	logs, totalPages, err := h.db.GetAuditLogs(session.UserID, page, 50)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load audit logs"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load audit logs")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
@@ -355,17 +807,17 @@ func (h *Handlers) APIGetAuditLogs(c *gin.Context) {
 func (h *Handlers) APIListDestinations(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 	sourceID := c.Param("id")
 	if _, err := h.db.GetSourceByIDForUser(sourceID, session.UserID); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Source not found"})
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
 		return
 	}
 	dests, err := h.db.GetDestinationsBySourceID(sourceID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load destinations"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load destinations")
 		return
 	}
 	if dests == nil {
@@ -378,12 +830,12 @@ func (h *Handlers) APIListDestinations(c *gin.Context) {
 func (h *Handlers) APICreateDestination(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 	sourceID := c.Param("id")
 	if _, err := h.db.GetSourceByIDForUser(sourceID, session.UserID); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Source not found"})
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
 		return
 	}
 
@@ -394,17 +846,21 @@ func (h *Handlers) APICreateDestination(c *gin.Context) {
 		DestPassword string `json:"dest_password"`
 	}
 	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid request body")
 		return
 	}
 	if req.DestURL == "" || req.DestUsername == "" || req.DestPassword == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Destination URL, username, and password are required"})
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "Destination URL, username, and password are required")
+		return
+	}
+	if validationErr := h.validateDestHost(req.DestURL); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
 		return
 	}
 
 	encPassword, err := h.encryptor.Encrypt(req.DestPassword)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt password"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to encrypt password")
 		return
 	}
 
@@ -417,7 +873,7 @@ func (h *Handlers) APICreateDestination(c *gin.Context) {
 		Enabled:      true,
 	}
 	if err := h.db.CreateDestination(dest); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create destination"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to create destination")
 		return
 	}
 
@@ -429,17 +885,17 @@ func (h *Handlers) APICreateDestination(c *gin.Context) {
 func (h *Handlers) APIDeleteDestination(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 	sourceID := c.Param("id")
 	if _, err := h.db.GetSourceByIDForUser(sourceID, session.UserID); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Source not found"})
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
 		return
 	}
 	destID := c.Param("destId")
 	if err := h.db.DeleteDestination(destID); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Destination not found"})
+		respondError(c, http.StatusNotFound, ErrCodeDestinationNotFound, "Destination not found")
 		return
 	}
 	h.audit(c, "destination.delete", "destination", destID, fmt.Sprintf("source=%s", sourceID))
@@ -459,27 +915,27 @@ func (h *Handlers) APIGetVersion(c *gin.Context) {
 func (h *Handlers) APIGetLogStats(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	count, oldest, err := h.db.GetSyncLogStats()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get log stats"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to get log stats")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"total_logs":     count,
 		"oldest_log":     oldest.Format(time.RFC3339),
-		"retention_days": h.cfg.LogRetentionDays,
+		"retention_days": h.cfg.Sync.LogRetentionDays,
 	})
 }
 
 // APILogout logs out the user.
 func (h *Handlers) APILogout(c *gin.Context) {
 	if err := h.session.Clear(c.Writer, c.Request); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to logout")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
@@ -489,56 +945,64 @@ func (h *Handlers) APILogout(c *gin.Context) {
 func (h *Handlers) APIDashboardStats(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
-	sources, err := h.db.GetSourcesByUserID(session.UserID)
+	today := time.Now().Truncate(24 * time.Hour)
+	overview, err := h.db.GetSyncOverview(session.UserID, today, today)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load sources"})
+		log.Printf("APIDashboardStats: %v", err)
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load dashboard stats")
 		return
 	}
 
-	stats := APIDashboardStats{
-		TotalSources: len(sources),
-	}
+	c.JSON(http.StatusOK, APIDashboardStats{
+		TotalSources:  overview.TotalSources,
+		ActiveSources: overview.ActiveSources,
+		SyncsToday:    overview.SyncsToday,
+		FailedSyncs:   overview.FailingSources,
+	})
+}
 
-	for _, s := range sources {
-		if s.Enabled {
-			stats.ActiveSources++
-		}
-		if s.LastSyncStatus == db.SyncStatusError {
-			stats.FailedSyncs++
-		}
+// APIDashboardOverview returns aggregate stats across all the user's
+// sources: source counts by state, syncs and events for today and the
+// last 7 days, the week's success rate, and how many sources are
+// stale. Everything but the stale count comes from SQL aggregates.
+func (h *Handlers) APIDashboardOverview(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
 	}
 
-	// Count syncs today. GetSyncLogs errors are logged but not
-	// surfaced — the dashboard still renders with whatever stats we
-	// could compute. Previously this used `logs, _ :=` which hid the
-	// error entirely; operators couldn't tell the difference between
-	// "no syncs today" and "DB query failed." (#93)
 	today := time.Now().Truncate(24 * time.Hour)
-	for _, s := range sources {
-		logs, err := h.db.GetSyncLogs(s.ID, 100)
+	overview, err := h.db.GetSyncOverview(session.UserID, today, today.AddDate(0, 0, -6))
+	if err != nil {
+		log.Printf("APIDashboardOverview: %v", err)
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load dashboard overview")
+		return
+	}
+
+	// Staleness lives in the notifier, which tracks every user's
+	// sources; count only this user's. A failure leaves the count at
+	// zero rather than failing the whole overview.
+	if h.notifier != nil {
+		stale, err := h.db.CountUserSourcesIn(session.UserID, h.notifier.GetStaleSourceIDs())
 		if err != nil {
-			log.Printf("APIDashboardStats: failed to load sync logs for source %s: %v", s.ID, err)
-			continue
-		}
-		for _, l := range logs {
-			if l.CreatedAt.After(today) {
-				stats.SyncsToday++
-			}
+			log.Printf("APIDashboardOverview: %v", err)
 		}
+		overview.StaleSources = stale
 	}
 
-	c.JSON(http.StatusOK, stats)
+	c.JSON(http.StatusOK, overview)
 }
 
 // APISyncHistory returns sync history for charts.
 func (h *Handlers) APISyncHistory(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -552,7 +1016,7 @@ func (h *Handlers) APISyncHistory(c *gin.Context) {
 
 	sources, err := h.db.GetSourcesByUserID(session.UserID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load sources"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load sources")
 		return
 	}
 
@@ -617,7 +1081,7 @@ func (h *Handlers) APISyncHistory(c *gin.Context) {
 		case db.SyncStatusPartial:
 			point.Partial++
 			successCount++ // Partial counts as success for rate calculation
-		case db.SyncStatusError:
+		case db.SyncStatusError, db.SyncStatusAuthError:
 			point.Error++
 		}
 	}
@@ -648,15 +1112,17 @@ func (h *Handlers) APISyncHistory(c *gin.Context) {
 func (h *Handlers) APIListSources(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	sources, err := h.db.GetSourcesByUserID(session.UserID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load sources"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load sources")
 		return
 	}
+	// ?tag=work&tag=client-a lists the sources tagged with either.
+	sources = filterSourcesByTag(sources, c.QueryArray("tag"))
 
 	apiSources := make([]*APISource, len(sources))
 	for i, s := range sources {
@@ -670,7 +1136,7 @@ func (h *Handlers) APIListSources(c *gin.Context) {
 func (h *Handlers) APIGetSource(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -678,13 +1144,34 @@ func (h *Handlers) APIGetSource(c *gin.Context) {
 	// Use timing-safe query that combines ID and user check
 	source, err := h.db.GetSourceByIDForUser(sourceID, session.UserID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Source not found"})
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
 		return
 	}
 
 	c.JSON(http.StatusOK, h.sourceToAPIWithScheduler(source))
 }
 
+// sourceCreationForbidden is the 403 message for users outside the
+// source-creation allow-list.
+const sourceCreationForbidden = "Your account is not allowed to add sources"
+
+// canCreateSources reports whether the signed-in user may add sources
+// and probe CalDAV servers (AUTH_ALLOWED_EMAILS / AUTH_ALLOWED_DOMAINS).
+// A nil cfg, as in the test harness, allows everyone.
+func (h *Handlers) canCreateSources(session *auth.SessionData) bool {
+	return h.cfg == nil || h.cfg.Auth.CanCreateSources(session.Email)
+}
+
+// validateDestHost returns an error message when destURL's host is not
+// on CALDAV_ALLOWED_DEST_HOSTS, "" otherwise. A nil cfg, as in the test
+// harness, allows any host.
+func (h *Handlers) validateDestHost(destURL string) string {
+	if h.cfg == nil || h.cfg.CalDAV.DestHostAllowed(destURL) {
+		return ""
+	}
+	return "Destination host is not allowed on this server"
+}
+
 // APICreateSourceRequest represents the request body for creating a source.
 type APICreateSourceRequest struct {
 	Name              string              `json:"name"`
@@ -701,53 +1188,155 @@ type APICreateSourceRequest struct {
 	ConflictStrategy  string              `json:"conflict_strategy"`
 	SelectedCalendars []APICalendarConfig `json:"selected_calendars"`
 	StripAlarms       bool                `json:"strip_alarms"`
+	AttendeeRewrite   map[string]string   `json:"attendee_rewrite"`
+	UIDPrefix         string              `json:"uid_prefix"`
+	ResultWebhookURL  string              `json:"result_webhook_url"`
+	MaxRequestsPerSec float64             `json:"max_requests_per_second"`
+	MaxSyncDuration   int                 `json:"max_sync_duration_secs"`
+	InitialSyncCap    int                 `json:"initial_sync_event_cap"`
+	CustomHeaders     map[string]string   `json:"custom_headers"`
+	VerifyWrites      bool                `json:"verify_writes"`
+	IncludeCategories []string            `json:"include_categories"`
+	Tags              []string            `json:"tags"`
+	PreserveDestProps []string            `json:"preserve_dest_props"`
+	TextRules         []db.TextRule       `json:"text_rules"`
+	SourceStamp       string              `json:"source_stamp"`
+	CABundle          string              `json:"ca_bundle"`
+	ProxyURL          string              `json:"proxy_url"`
+	TargetTimezone    string              `json:"target_timezone"`
+	SyncJournals      bool                `json:"sync_journals"`
+	DestReadOnly      bool                `json:"dest_read_only"`
+	SyncAttachments   *bool               `json:"sync_attachments"` // omitted means true
+	GenerateUIDs      bool                `json:"generate_missing_uids"`
+	SkipCancelled     bool                `json:"skip_cancelled"`
+	SkipInvited       bool                `json:"skip_invited_events"`
+	AccountEmail      string              `json:"account_email"`
+	MinimizeTimezones bool                `json:"minimize_timezones"`
+	AlertEmail        *bool               `json:"alert_email_enabled"`   // omitted follows the user's preferences
+	AlertWebhook      *bool               `json:"alert_webhook_enabled"` // omitted follows the user's preferences
 }
 
 // APICreateSource creates a new source.
 func (h *Handlers) APICreateSource(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+	if !h.canCreateSources(session) {
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, sourceCreationForbidden)
 		return
 	}
 
 	var req APICreateSourceRequest
 	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	isICS := db.SourceType(req.SourceType) == db.SourceTypeICS
+	req.SourceURL = presetSourceURL(req.SourceType, req.SourceURL)
 
 	// ICS sources only require Name and SourceURL; CalDAV sources require credentials too
 	if isICS {
 		if req.Name == "" || req.SourceURL == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Name and source URL are required"})
+			respondError(c, http.StatusBadRequest, ErrCodeValidation, "Name and source URL are required")
 			return
 		}
-		// Force one-way sync for ICS (read-only feed)
-		req.SyncDirection = string(db.SyncDirectionOneWay)
+		// Force one-way sync for ICS (read-only feed); create-only
+		// is one way too.
+		if db.SyncDirection(req.SyncDirection) != db.SyncDirectionOneWayCreateOnly {
+			req.SyncDirection = string(db.SyncDirectionOneWay)
+		}
 		req.ConflictStrategy = string(db.ConflictSourceWins)
 	} else {
 		if req.Name == "" || req.SourceURL == "" || req.SourceUsername == "" || req.SourcePassword == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing required fields"})
+			respondError(c, http.StatusBadRequest, ErrCodeValidation, "Missing required fields")
 			return
 		}
 	}
 
 	// Validate input lengths and enum values
 	if validationErr := validateSourceInput(req.Name, req.SourceType, req.SyncDirection, req.ConflictStrategy, req.SourceURL, req.DestURL, req.SourceUsername, req.DestUsername); validationErr != "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr})
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateProviderSource(req.SourceType, req.SourceURL, req.SourcePassword); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateAttendeeRewrite(req.AttendeeRewrite); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateUIDPrefix(req.UIDPrefix, req.SyncDirection); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateResultWebhookURL(req.ResultWebhookURL); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateMaxRequestsPerSecond(req.MaxRequestsPerSec); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateMaxSyncDuration(req.MaxSyncDuration); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateInitialSyncEventCap(req.InitialSyncCap); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateProxyURL(req.ProxyURL); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := h.validateDestHost(req.DestURL); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateCustomHeaders(req.CustomHeaders); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateIncludeCategories(req.IncludeCategories); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateTags(req.Tags); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validatePreserveDestProps(req.PreserveDestProps); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateTextRules(req.TextRules); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateSourceStamp(req.SourceStamp); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateCABundle(req.CABundle); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateTargetTimezone(req.TargetTimezone); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
 		return
 	}
 
 	// Validate password lengths
 	if len(req.SourcePassword) > maxPasswordLength {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Source password is too long"})
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "Source password is too long")
 		return
 	}
 	if len(req.DestPassword) > maxPasswordLength {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Destination password is too long"})
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "Destination password is too long")
 		return
 	}
 
@@ -756,13 +1345,13 @@ func (h *Handlers) APICreateSource(c *gin.Context) {
 	if isICS {
 		if err := h.syncEngine.TestICSConnection(ctx, req.SourceURL, req.SourceUsername, req.SourcePassword); err != nil {
 			log.Printf("ICS feed connection test failed for %s: %v", req.SourceURL, err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to connect to ICS feed: " + categorizeConnectionError(err)})
+			respondError(c, http.StatusBadRequest, ErrCodeConnectionFailed, "Failed to connect to ICS feed: "+categorizeConnectionError(err))
 			return
 		}
 	} else {
 		if err := h.syncEngine.TestConnection(ctx, req.SourceURL, req.SourceUsername, req.SourcePassword); err != nil {
 			log.Printf("Source connection test failed for %s: %v", req.SourceURL, err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to connect to source: " + categorizeConnectionError(err)})
+			respondError(c, http.StatusBadRequest, ErrCodeConnectionFailed, "Failed to connect to source: "+categorizeConnectionError(err))
 			return
 		}
 	}
@@ -771,7 +1360,7 @@ func (h *Handlers) APICreateSource(c *gin.Context) {
 	if req.DestURL != "" && req.DestUsername != "" && req.DestPassword != "" {
 		if err := h.syncEngine.TestConnection(ctx, req.DestURL, req.DestUsername, req.DestPassword); err != nil {
 			log.Printf("Destination connection test failed for %s: %v", req.DestURL, err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to connect to destination: " + categorizeConnectionError(err)})
+			respondError(c, http.StatusBadRequest, ErrCodeConnectionFailed, "Failed to connect to destination: "+categorizeConnectionError(err))
 			return
 		}
 	}
@@ -779,13 +1368,13 @@ func (h *Handlers) APICreateSource(c *gin.Context) {
 	// Encrypt passwords (empty strings are fine for ICS sources without auth)
 	encSourcePwd, err := h.encryptor.Encrypt(req.SourcePassword)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt credentials"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to encrypt credentials")
 		return
 	}
 
 	encDestPwd, err := h.encryptor.Encrypt(req.DestPassword)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt credentials"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to encrypt credentials")
 		return
 	}
 
@@ -805,31 +1394,62 @@ func (h *Handlers) APICreateSource(c *gin.Context) {
 	for _, c := range req.SelectedCalendars {
 		dbCalendars = append(dbCalendars, db.CalendarConfig{
 			Path:          c.Path,
+			Name:          c.Name,
 			SyncDirection: db.SyncDirection(c.SyncDirection),
 		})
 	}
 
 	source := &db.Source{
-		UserID:            session.UserID,
-		Name:              req.Name,
-		SourceType:        db.SourceType(req.SourceType),
-		SourceURL:         req.SourceURL,
-		SourceUsername:    req.SourceUsername,
-		SourcePassword:    encSourcePwd,
-		DestURL:           req.DestURL,
-		DestUsername:      req.DestUsername,
-		DestPassword:      encDestPwd,
-		SyncInterval:      syncInterval,
-		SyncDaysPast:      syncDaysPast,
-		SyncDirection:     db.SyncDirection(req.SyncDirection),
-		ConflictStrategy:  db.ConflictStrategy(req.ConflictStrategy),
-		SelectedCalendars: dbCalendars,
-		Enabled:           true,
-		StripAlarms:       req.StripAlarms,
+		UserID:               session.UserID,
+		Name:                 req.Name,
+		SourceType:           db.SourceType(req.SourceType),
+		SourceURL:            req.SourceURL,
+		SourceUsername:       req.SourceUsername,
+		SourcePassword:       encSourcePwd,
+		DestURL:              req.DestURL,
+		DestUsername:         req.DestUsername,
+		DestPassword:         encDestPwd,
+		SyncInterval:         syncInterval,
+		SyncDaysPast:         syncDaysPast,
+		SyncDirection:        db.SyncDirection(req.SyncDirection),
+		ConflictStrategy:     db.ConflictStrategy(req.ConflictStrategy),
+		SelectedCalendars:    dbCalendars,
+		Enabled:              true,
+		StripAlarms:          req.StripAlarms,
+		AttendeeRewrite:      req.AttendeeRewrite,
+		UIDPrefix:            req.UIDPrefix,
+		ResultWebhookURL:     req.ResultWebhookURL,
+		MaxRequestsPerSecond: req.MaxRequestsPerSec,
+		MaxSyncDurationSecs:  req.MaxSyncDuration,
+		InitialSyncEventCap:  req.InitialSyncCap,
+		CustomHeaders:        req.CustomHeaders,
+		VerifyWrites:         req.VerifyWrites,
+		IncludeCategories:    req.IncludeCategories,
+		Tags:                 req.Tags,
+		PreserveDestProps:    req.PreserveDestProps,
+		TextRules:            req.TextRules,
+		SourceStamp:          req.SourceStamp,
+		CABundle:             req.CABundle,
+		ProxyURL:             req.ProxyURL,
+		TargetTimezone:       req.TargetTimezone,
+		SyncJournals:         req.SyncJournals,
+		DestReadOnly:         req.DestReadOnly,
+		SyncAttachments:      req.SyncAttachments == nil || *req.SyncAttachments,
+		GenerateMissingUIDs:  req.GenerateUIDs,
+		SkipCancelled:        req.SkipCancelled,
+		SkipInvitedEvents:    req.SkipInvited,
+		AccountEmail:         req.AccountEmail,
+		MinimizeTimezones:    req.MinimizeTimezones,
+		AlertEmailEnabled:    req.AlertEmail,
+		AlertWebhookEnabled:  req.AlertWebhook,
+	}
+	if validationErr := validateInviteFilter(source); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
 	}
 
 	if err := h.db.CreateSource(source); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create source"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to create source")
 		return
 	}
 
@@ -854,13 +1474,51 @@ type APIUpdateSourceRequest struct {
 	ConflictStrategy  string              `json:"conflict_strategy"`
 	SelectedCalendars []APICalendarConfig `json:"selected_calendars"`
 	StripAlarms       bool                `json:"strip_alarms"`
+	AttendeeRewrite   map[string]string   `json:"attendee_rewrite"`
+	UIDPrefix         *string             `json:"uid_prefix"`
+	ResultWebhookURL  *string             `json:"result_webhook_url"`
+	MaxRequestsPerSec *float64            `json:"max_requests_per_second"`
+	MaxSyncDuration   *int                `json:"max_sync_duration_secs"`
+	InitialSyncCap    *int                `json:"initial_sync_event_cap"`
+	CustomHeaders     map[string]string   `json:"custom_headers"`
+	VerifyWrites      *bool               `json:"verify_writes"`
+	IncludeCategories []string            `json:"include_categories"`
+	Tags              []string            `json:"tags"`
+	PreserveDestProps []string            `json:"preserve_dest_props"`
+	TextRules         []db.TextRule       `json:"text_rules"`
+	SourceStamp       *string             `json:"source_stamp"`
+	CABundle          *string             `json:"ca_bundle"`
+	ProxyURL          *string             `json:"proxy_url"`
+	TargetTimezone    *string             `json:"target_timezone"`
+	SyncJournals      *bool               `json:"sync_journals"`
+	DestReadOnly      *bool               `json:"dest_read_only"`
+	SyncAttachments   *bool               `json:"sync_attachments"`
+	GenerateUIDs      *bool               `json:"generate_missing_uids"`
+	SkipCancelled     *bool               `json:"skip_cancelled"`
+	SkipInvited       *bool               `json:"skip_invited_events"`
+	AccountEmail      *string             `json:"account_email"`
+	MinimizeTimezones *bool               `json:"minimize_timezones"`
+	AlertEmail        optionalBool        `json:"alert_email_enabled"`
+	AlertWebhook      optionalBool        `json:"alert_webhook_enabled"`
+}
+
+// optionalBool is a tri-state update field: absent leaves the setting
+// alone, null resets it to inherited, and true or false sets it.
+type optionalBool struct {
+	Set   bool
+	Value *bool
+}
+
+func (o *optionalBool) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	return json.Unmarshal(data, &o.Value)
 }
 
 // APIUpdateSource updates an existing source.
 func (h *Handlers) APIUpdateSource(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -868,29 +1526,128 @@ func (h *Handlers) APIUpdateSource(c *gin.Context) {
 	// Use timing-safe query that combines ID and user check
 	source, err := h.db.GetSourceByIDForUser(sourceID, session.UserID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Source not found"})
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
 		return
 	}
 
 	var req APIUpdateSourceRequest
 	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	// Validate input lengths and enum values
 	if validationErr := validateSourceInput(req.Name, req.SourceType, req.SyncDirection, req.ConflictStrategy, req.SourceURL, req.DestURL, req.SourceUsername, req.DestUsername); validationErr != "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr})
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateProviderSource(req.SourceType, req.SourceURL, req.SourcePassword); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateAttendeeRewrite(req.AttendeeRewrite); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	uidPrefix := source.UIDPrefix
+	if req.UIDPrefix != nil {
+		uidPrefix = *req.UIDPrefix
+	}
+	if validationErr := validateUIDPrefix(uidPrefix, req.SyncDirection); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if req.ResultWebhookURL != nil {
+		if validationErr := validateResultWebhookURL(*req.ResultWebhookURL); validationErr != "" {
+			respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+			return
+		}
+	}
+	if req.MaxRequestsPerSec != nil {
+		if validationErr := validateMaxRequestsPerSecond(*req.MaxRequestsPerSec); validationErr != "" {
+			respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+			return
+		}
+	}
+	if req.MaxSyncDuration != nil {
+		if validationErr := validateMaxSyncDuration(*req.MaxSyncDuration); validationErr != "" {
+			respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+			return
+		}
+	}
+	if req.InitialSyncCap != nil {
+		if validationErr := validateInitialSyncEventCap(*req.InitialSyncCap); validationErr != "" {
+			respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+			return
+		}
+	}
+	if req.ProxyURL != nil {
+		if validationErr := validateProxyURL(*req.ProxyURL); validationErr != "" {
+			respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+			return
+		}
+	}
+	if validationErr := validateCustomHeaders(req.CustomHeaders); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateIncludeCategories(req.IncludeCategories); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateTags(req.Tags); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validatePreserveDestProps(req.PreserveDestProps); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateTextRules(req.TextRules); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
 		return
 	}
+	if req.SourceStamp != nil {
+		if validationErr := validateSourceStamp(*req.SourceStamp); validationErr != "" {
+			respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+			return
+		}
+	}
+	if req.CABundle != nil {
+		if validationErr := validateCABundle(*req.CABundle); validationErr != "" {
+			respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+			return
+		}
+	}
+	if req.TargetTimezone != nil {
+		if validationErr := validateTargetTimezone(*req.TargetTimezone); validationErr != "" {
+			respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+			return
+		}
+	}
+	// A source already syncing to a host the list has since dropped
+	// keeps it; only a new destination is checked.
+	if req.DestURL != source.DestURL {
+		if validationErr := h.validateDestHost(req.DestURL); validationErr != "" {
+			respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+			return
+		}
+	}
 
 	// Validate password lengths if provided
 	if req.SourcePassword != "" && len(req.SourcePassword) > maxPasswordLength {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Source password is too long"})
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "Source password is too long")
 		return
 	}
 	if req.DestPassword != "" && len(req.DestPassword) > maxPasswordLength {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Destination password is too long"})
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "Destination password is too long")
+		return
+	}
+
+	// Only re-test the side whose connection details changed, so a
+	// rename doesn't depend on the servers being reachable.
+	if errMsg := h.testChangedConnections(c.Request.Context(), source, &req); errMsg != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeConnectionFailed, errMsg)
 		return
 	}
 
@@ -899,6 +1656,7 @@ func (h *Handlers) APIUpdateSource(c *gin.Context) {
 	for _, c := range req.SelectedCalendars {
 		dbCalendars = append(dbCalendars, db.CalendarConfig{
 			Path:          c.Path,
+			Name:          c.Name,
 			SyncDirection: db.SyncDirection(c.SyncDirection),
 		})
 	}
@@ -914,18 +1672,105 @@ func (h *Handlers) APIUpdateSource(c *gin.Context) {
 	source.ConflictStrategy = db.ConflictStrategy(req.ConflictStrategy)
 	source.SelectedCalendars = dbCalendars
 	source.StripAlarms = req.StripAlarms
+	// Omitted attendee_rewrite keeps the stored map (older clients
+	// don't send it); an explicit {} clears it.
+	if req.AttendeeRewrite != nil {
+		source.AttendeeRewrite = req.AttendeeRewrite
+	}
+	// Changing the prefix orphans events already written under the old
+	// one; that's the user's call, we only apply what was sent.
+	source.UIDPrefix = uidPrefix
+	if req.ResultWebhookURL != nil {
+		source.ResultWebhookURL = *req.ResultWebhookURL
+	}
+	if req.MaxRequestsPerSec != nil {
+		source.MaxRequestsPerSecond = *req.MaxRequestsPerSec
+	}
+	if req.MaxSyncDuration != nil {
+		source.MaxSyncDurationSecs = *req.MaxSyncDuration
+	}
+	if req.InitialSyncCap != nil {
+		source.InitialSyncEventCap = *req.InitialSyncCap
+	}
+	if req.ProxyURL != nil {
+		source.ProxyURL = *req.ProxyURL
+	}
+	// Same omitted-keeps / {}-clears rule as attendee_rewrite.
+	if req.CustomHeaders != nil {
+		source.CustomHeaders = req.CustomHeaders
+	}
+	if req.VerifyWrites != nil {
+		source.VerifyWrites = *req.VerifyWrites
+	}
+	// Omitted include_categories keeps the filter; [] removes it.
+	if req.IncludeCategories != nil {
+		source.IncludeCategories = req.IncludeCategories
+	}
+	// Likewise for tags.
+	if req.Tags != nil {
+		source.Tags = req.Tags
+	}
+	if req.PreserveDestProps != nil {
+		source.PreserveDestProps = req.PreserveDestProps
+	}
+	if req.TextRules != nil {
+		source.TextRules = req.TextRules
+	}
+	if req.SourceStamp != nil {
+		source.SourceStamp = *req.SourceStamp
+	}
+	if req.CABundle != nil {
+		source.CABundle = *req.CABundle
+	}
+	if req.TargetTimezone != nil {
+		source.TargetTimezone = *req.TargetTimezone
+	}
+	if req.SyncJournals != nil {
+		source.SyncJournals = *req.SyncJournals
+	}
+	if req.DestReadOnly != nil {
+		source.DestReadOnly = *req.DestReadOnly
+	}
+	if req.SyncAttachments != nil {
+		source.SyncAttachments = *req.SyncAttachments
+	}
+	if req.GenerateUIDs != nil {
+		source.GenerateMissingUIDs = *req.GenerateUIDs
+	}
+	if req.SkipCancelled != nil {
+		source.SkipCancelled = *req.SkipCancelled
+	}
+	if req.SkipInvited != nil {
+		source.SkipInvitedEvents = *req.SkipInvited
+	}
+	if req.AccountEmail != nil {
+		source.AccountEmail = *req.AccountEmail
+	}
+	if req.MinimizeTimezones != nil {
+		source.MinimizeTimezones = *req.MinimizeTimezones
+	}
+	if req.AlertEmail.Set {
+		source.AlertEmailEnabled = req.AlertEmail.Value
+	}
+	if req.AlertWebhook.Set {
+		source.AlertWebhookEnabled = req.AlertWebhook.Value
+	}
 	if req.SyncInterval > 0 {
 		source.SyncInterval = req.SyncInterval
 	}
 	if req.SyncDaysPast > 0 {
 		source.SyncDaysPast = req.SyncDaysPast
 	}
+	if validationErr := validateInviteFilter(source); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
 
 	// Update passwords if provided
 	if req.SourcePassword != "" {
 		encPassword, err := h.encryptor.Encrypt(req.SourcePassword)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt credentials"})
+			respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to encrypt credentials")
 			return
 		}
 		source.SourcePassword = encPassword
@@ -934,27 +1779,88 @@ func (h *Handlers) APIUpdateSource(c *gin.Context) {
 	if req.DestPassword != "" {
 		encPassword, err := h.encryptor.Encrypt(req.DestPassword)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt credentials"})
+			respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to encrypt credentials")
 			return
 		}
 		source.DestPassword = encPassword
 	}
 
 	if err := h.db.UpdateSource(source); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update source"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to update source")
 		return
 	}
 
+	// Edited credentials deserve an immediate retry rather than waiting
+	// out the auth circuit's backoff.
+	if h.syncEngine != nil {
+		h.syncEngine.ResetAuthCircuit(source.ID)
+	}
+
 	h.scheduler.UpdateJobInterval(source.ID, time.Duration(source.SyncInterval)*time.Second)
 
 	c.JSON(http.StatusOK, h.sourceToAPIWithScheduler(source))
 }
 
+// testChangedConnections tests the source and destination connections
+// that req changes, before any of it is applied to source. A side counts
+// as changed when its URL or username differs from what is stored or a
+// new password is given; a blank password means the stored one. It
+// returns the message to reject the update with, or "" when nothing
+// changed or every test passed.
+func (h *Handlers) testChangedConnections(ctx context.Context, source *db.Source, req *APIUpdateSourceRequest) string {
+	sourceChanged := req.SourceURL != source.SourceURL || req.SourceUsername != source.SourceUsername || req.SourcePassword != ""
+	// Google sources authenticate with their OAuth token, not a password.
+	if db.SourceType(req.SourceType) == db.SourceTypeGoogle && source.OAuthRefreshToken != "" {
+		sourceChanged = false
+	}
+	if sourceChanged {
+		password, err := h.updatedPassword(req.SourcePassword, source.SourcePassword)
+		if err != nil {
+			log.Printf("Failed to decrypt stored source password for %s: %v", source.ID, err)
+			return "Stored source password could not be read; please re-enter it"
+		}
+		if db.SourceType(req.SourceType) == db.SourceTypeICS {
+			err = h.syncEngine.TestICSConnection(ctx, req.SourceURL, req.SourceUsername, password)
+		} else {
+			err = h.syncEngine.TestConnection(ctx, req.SourceURL, req.SourceUsername, password)
+		}
+		if err != nil {
+			log.Printf("Source connection test failed for %s: %v", req.SourceURL, err)
+			return "Failed to connect to source: " + categorizeConnectionError(err)
+		}
+	}
+
+	destChanged := req.DestURL != source.DestURL || req.DestUsername != source.DestUsername || req.DestPassword != ""
+	if destChanged && req.DestURL != "" && req.DestUsername != "" {
+		password, err := h.updatedPassword(req.DestPassword, source.DestPassword)
+		if err != nil {
+			log.Printf("Failed to decrypt stored destination password for %s: %v", source.ID, err)
+			return "Stored destination password could not be read; please re-enter it"
+		}
+		if password != "" {
+			if err := h.syncEngine.TestConnection(ctx, req.DestURL, req.DestUsername, password); err != nil {
+				log.Printf("Destination connection test failed for %s: %v", req.DestURL, err)
+				return "Failed to connect to destination: " + categorizeConnectionError(err)
+			}
+		}
+	}
+	return ""
+}
+
+// updatedPassword returns the plaintext password an update will leave
+// in place: the new one if given, otherwise the decrypted stored one.
+func (h *Handlers) updatedPassword(newPassword, stored string) (string, error) {
+	if newPassword != "" || stored == "" {
+		return newPassword, nil
+	}
+	return h.encryptor.Decrypt(stored)
+}
+
 // APIDeleteSource deletes a source.
 func (h *Handlers) APIDeleteSource(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -962,14 +1868,14 @@ func (h *Handlers) APIDeleteSource(c *gin.Context) {
 	// Use timing-safe query that combines ID and user check
 	_, err := h.db.GetSourceByIDForUser(sourceID, session.UserID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Source not found"})
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
 		return
 	}
 
 	h.scheduler.RemoveJob(sourceID)
 
 	if err := h.db.DeleteSource(sourceID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete source"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to delete source")
 		return
 	}
 
@@ -981,7 +1887,7 @@ func (h *Handlers) APIDeleteSource(c *gin.Context) {
 func (h *Handlers) APIToggleSource(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -989,13 +1895,13 @@ func (h *Handlers) APIToggleSource(c *gin.Context) {
 	// Use timing-safe query that combines ID and user check
 	source, err := h.db.GetSourceByIDForUser(sourceID, session.UserID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Source not found"})
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
 		return
 	}
 
 	source.Enabled = !source.Enabled
 	if err := h.db.UpdateSource(source); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update source"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to update source")
 		return
 	}
 
@@ -1012,14 +1918,47 @@ func (h *Handlers) APIToggleSource(c *gin.Context) {
 func (h *Handlers) APITriggerSync(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	sourceID := c.Param("id")
 	source, err := h.db.GetSourceByIDForUser(sourceID, session.UserID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Source not found"})
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+
+	// A single calendar syncs synchronously and returns its result,
+	// for debugging one calendar without syncing the rest. It may be
+	// combined with dry_run. A real sync goes through the scheduler,
+	// like any other, and is refused while the source is syncing.
+	if calendarPath := c.Query("calendar"); calendarPath != "" {
+		var res *caldav.SyncResult
+		if dryRun {
+			res, err = h.syncEngine.SyncCalendar(caldav.WithDryRun(c.Request.Context()), source, calendarPath)
+		} else {
+			res, err = h.scheduler.SyncCalendar(c.Request.Context(), source, calendarPath)
+		}
+		switch {
+		case errors.Is(err, caldav.ErrUnknownCalendar):
+			respondError(c, http.StatusBadRequest, ErrCodeValidation, "Calendar is not one of this source's calendars")
+			return
+		case errors.Is(err, scheduler.ErrSyncInProgress):
+			respondError(c, http.StatusConflict, ErrCodeConflict, "A sync of this source is already running")
+			return
+		case errors.Is(err, scheduler.ErrSchedulerStopped):
+			respondError(c, http.StatusServiceUnavailable, ErrCodeUnavailable, "Syncing is shutting down")
+			return
+		}
+		result := *res
+		result.Warnings = summarizeSyncWarnings(result.Warnings)
+		if !dryRun {
+			h.audit(c, "sync.calendar", "source", sourceID, calendarPath)
+		}
+		c.JSON(http.StatusOK, result)
 		return
 	}
 
@@ -1027,19 +1966,124 @@ func (h *Handlers) APITriggerSync(c *gin.Context) {
 	// context so PutEvent/DeleteEvent are no-ops. Returns the
 	// SyncResult as JSON so the user can preview what would
 	// happen without actually changing any data. (#150)
-	if c.Query("dry_run") == "true" {
+	if dryRun {
 		ctx := caldav.WithDryRun(c.Request.Context())
-		result := h.syncEngine.SyncSource(ctx, source)
+		result := *h.syncEngine.SyncSource(ctx, source)
+		result.Warnings = summarizeSyncWarnings(result.Warnings)
 		c.JSON(http.StatusOK, result)
 		return
 	}
 
+	// A repeat of a request carrying the same Idempotency-Key (a
+	// double-click, a retrying client) gets the same answer without
+	// queueing the source again. Keys are scoped to the user and source.
+	if key := c.GetHeader("Idempotency-Key"); key != "" && len(key) <= maxIdempotencyKeyLen {
+		if !h.triggerKeys.claim(session.UserID+"|"+sourceID+"|"+key, time.Now()) {
+			c.JSON(http.StatusOK, gin.H{"message": "Sync already queued"})
+			return
+		}
+	}
+
 	h.scheduler.TriggerSync(sourceID)
 
 	h.audit(c, "sync.trigger", "source", sourceID, "")
 	c.JSON(http.StatusOK, gin.H{"message": "Sync triggered"})
 }
 
+// APICancelSync stops the sync running for a source, if one is. The
+// sync records a canceled result for the work it did before stopping.
+// Canceling a source that isn't syncing is a no-op.
+func (h *Handlers) APICancelSync(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	sourceID := c.Param("id")
+	if _, err := h.db.GetSourceByIDForUser(sourceID, session.UserID); err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
+		return
+	}
+
+	if !h.scheduler.CancelSync(sourceID) {
+		c.JSON(http.StatusOK, gin.H{"message": "No sync is running for this source"})
+		return
+	}
+
+	h.audit(c, "sync.cancel", "source", sourceID, "")
+	c.JSON(http.StatusOK, gin.H{"message": "Sync canceled"})
+}
+
+// APIUndoLastSync re-creates the destination events deleted by the
+// source's most recent sync, from the backups taken before deleting.
+func (h *Handlers) APIUndoLastSync(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	sourceID := c.Param("id")
+	source, err := h.db.GetSourceByIDForUser(sourceID, session.UserID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
+		return
+	}
+
+	result, err := h.syncEngine.UndoLastSync(c.Request.Context(), source)
+	if errors.Is(err, caldav.ErrNothingToUndo) {
+		respondError(c, http.StatusNotFound, ErrCodeNotFound, "No deleted events to restore")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, sanitizeError(err, "Failed to undo last sync"))
+		return
+	}
+
+	h.audit(c, "sync.undo", "source", sourceID, fmt.Sprintf("restored %d, failed %d", result.Restored, result.Failed))
+	c.JSON(http.StatusOK, result)
+}
+
+// APISyncAllResponse lists the sources queued by APISyncAllSources.
+type APISyncAllResponse struct {
+	Message   string   `json:"message"`
+	SourceIDs []string `json:"source_ids"`
+}
+
+// APISyncAllSources queues a sync for every enabled source the user
+// owns and returns without waiting for them. The syncs share the
+// scheduler's concurrency cap, so a user with many sources can't start
+// them all at once.
+func (h *Handlers) APISyncAllSources(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	sources, err := h.db.GetSourcesByUserID(session.UserID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load sources")
+		return
+	}
+
+	queued := make([]string, 0, len(sources))
+	for _, s := range sources {
+		if !s.Enabled {
+			continue
+		}
+		h.scheduler.TriggerSync(s.ID)
+		queued = append(queued, s.ID)
+	}
+
+	h.audit(c, "sync.trigger_all", "user", session.UserID, fmt.Sprintf("%d sources", len(queued)))
+	c.JSON(http.StatusOK, APISyncAllResponse{
+		Message:   fmt.Sprintf("Sync triggered for %d source(s)", len(queued)),
+		SourceIDs: queued,
+	})
+}
+
 // APIGetSourceLogs returns logs for a source.
 // APIGetSourceStats returns per-source statistics including event
 // count, malformed count, recent sync history, success rate, and
@@ -1047,17 +2091,17 @@ func (h *Handlers) APITriggerSync(c *gin.Context) {
 func (h *Handlers) APIGetSourceStats(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 	sourceID := c.Param("id")
 	if _, err := h.db.GetSourceByIDForUser(sourceID, session.UserID); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Source not found"})
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
 		return
 	}
 	stats, err := h.db.GetSourceStats(sourceID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get source stats"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to get source stats")
 		return
 	}
 	c.JSON(http.StatusOK, stats)
@@ -1066,7 +2110,7 @@ func (h *Handlers) APIGetSourceStats(c *gin.Context) {
 func (h *Handlers) APIGetSourceLogs(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -1074,7 +2118,7 @@ func (h *Handlers) APIGetSourceLogs(c *gin.Context) {
 	// Use timing-safe query that combines ID and user check
 	_, err := h.db.GetSourceByIDForUser(sourceID, session.UserID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Source not found"})
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
 		return
 	}
 
@@ -1090,7 +2134,7 @@ func (h *Handlers) APIGetSourceLogs(c *gin.Context) {
 
 	logs, err := h.db.GetSyncLogs(sourceID, 1000)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load logs"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load logs")
 		return
 	}
 
@@ -1122,6 +2166,114 @@ func (h *Handlers) APIGetSourceLogs(c *gin.Context) {
 	})
 }
 
+// APISyncConflict is one conflict a sync run resolved, as recorded in
+// its log's CONFLICT lines.
+type APISyncConflict struct {
+	UID      string `json:"uid"`
+	Summary  string `json:"summary"`
+	Winner   string `json:"winner"`
+	Strategy string `json:"strategy"`
+}
+
+// APISyncLogDetail is a single sync log with what its run left behind.
+type APISyncLogDetail struct {
+	Log             *APISyncLog          `json:"log"`
+	Conflicts       []APISyncConflict    `json:"conflicts"`
+	MalformedEvents []*APIMalformedEvent `json:"malformed_events"`
+}
+
+// syncLogMalformedSlack widens the window used to match malformed
+// events to a run, covering clock rounding at the run's start.
+const syncLogMalformedSlack = 5 * time.Second
+
+// syncLogConflicts extracts the conflict records from raw sync log
+// details. Lines that don't parse are skipped.
+func syncLogConflicts(details string) []APISyncConflict {
+	conflicts := []APISyncConflict{}
+	for _, line := range strings.Split(details, "\n") {
+		record, ok := strings.CutPrefix(line, conflictWarningPrefix)
+		if !ok {
+			continue
+		}
+		var conflict APISyncConflict
+		if err := json.Unmarshal([]byte(record), &conflict); err != nil {
+			continue
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts
+}
+
+// APIGetSourceLog returns one sync log of a source with the conflicts
+// its run resolved and the malformed events it recorded. Logs carry no
+// run ID, so malformed events are matched by when they were discovered
+// relative to the run.
+func (h *Handlers) APIGetSourceLog(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	sourceID := c.Param("id")
+	if _, err := h.db.GetSourceByIDForUser(sourceID, session.UserID); err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
+		return
+	}
+
+	syncLog, err := h.db.GetSyncLogByID(c.Param("log_id"))
+	if err != nil || syncLog.SourceID != sourceID {
+		respondError(c, http.StatusNotFound, ErrCodeNotFound, "Sync log not found")
+		return
+	}
+
+	started := syncLog.CreatedAt.Add(-syncLog.Duration - syncLogMalformedSlack)
+	malformed, err := h.db.GetMalformedEventsForSourceBetween(sourceID, started, syncLog.CreatedAt)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load malformed events")
+		return
+	}
+	apiMalformed := make([]*APIMalformedEvent, len(malformed))
+	for i, e := range malformed {
+		apiMalformed[i] = malformedEventToAPI(e)
+	}
+
+	c.JSON(http.StatusOK, APISyncLogDetail{
+		Log:             syncLogToAPI(syncLog),
+		Conflicts:       syncLogConflicts(syncLog.Details),
+		MalformedEvents: apiMalformed,
+	})
+}
+
+// APIDeleteSourceLogs clears a source's sync log history and reports
+// how many logs were removed.
+func (h *Handlers) APIDeleteSourceLogs(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	sourceID := c.Param("id")
+	// Use timing-safe query that combines ID and user check
+	if _, err := h.db.GetSourceByIDForUser(sourceID, session.UserID); err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
+		return
+	}
+
+	deleted, err := h.db.DeleteSyncLogsForSource(sourceID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to delete logs")
+		return
+	}
+
+	h.audit(c, "sync_logs.delete", "source", sourceID, fmt.Sprintf("deleted %d", deleted))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Sync logs deleted",
+		"deleted": deleted,
+	})
+}
+
 // APIMalformedEvent represents a malformed event in API responses.
 type APIMalformedEvent struct {
 	ID           string `json:"id"`
@@ -1129,9 +2281,16 @@ type APIMalformedEvent struct {
 	SourceName   string `json:"source_name"`
 	EventPath    string `json:"event_path"`
 	ErrorMessage string `json:"error_message"`
+	Category     string `json:"category"`
 	DiscoveredAt string `json:"discovered_at"`
 }
 
+// APIMalformedEventSummary counts a user's malformed events by cause.
+type APIMalformedEventSummary struct {
+	Total      int            `json:"total"`
+	Categories map[string]int `json:"categories"`
+}
+
 // malformedEventToAPI converts a db.MalformedEvent to API format.
 func malformedEventToAPI(e *db.MalformedEvent) *APIMalformedEvent {
 	return &APIMalformedEvent{
@@ -1140,6 +2299,7 @@ func malformedEventToAPI(e *db.MalformedEvent) *APIMalformedEvent {
 		SourceName:   e.SourceName,
 		EventPath:    e.EventPath,
 		ErrorMessage: e.ErrorMessage,
+		Category:     e.Category,
 		DiscoveredAt: e.DiscoveredAt.Format(time.RFC3339),
 	}
 }
@@ -1148,13 +2308,13 @@ func malformedEventToAPI(e *db.MalformedEvent) *APIMalformedEvent {
 func (h *Handlers) APIGetMalformedEvents(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	events, err := h.db.GetMalformedEvents(session.UserID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get malformed events"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to get malformed events")
 		return
 	}
 
@@ -1166,11 +2326,33 @@ func (h *Handlers) APIGetMalformedEvents(c *gin.Context) {
 	c.JSON(http.StatusOK, apiEvents)
 }
 
+// APIGetMalformedEventSummary returns the current user's malformed
+// event counts grouped by category.
+func (h *Handlers) APIGetMalformedEventSummary(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	counts, err := h.db.GetMalformedEventCountsByCategory(session.UserID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to get malformed event summary")
+		return
+	}
+
+	summary := APIMalformedEventSummary{Categories: counts}
+	for _, n := range counts {
+		summary.Total += n
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
 // APIDeleteMalformedEvent deletes a malformed event record and optionally the event from the source.
 func (h *Handlers) APIDeleteMalformedEvent(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -1179,21 +2361,21 @@ func (h *Handlers) APIDeleteMalformedEvent(c *gin.Context) {
 	// Use timing-safe query that combines ID and user check
 	event, err := h.db.GetMalformedEventByIDForUser(eventID, session.UserID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Malformed event not found"})
+		respondError(c, http.StatusNotFound, ErrCodeNotFound, "Malformed event not found")
 		return
 	}
 
 	// Get the source for deletion from calendar
 	source, err := h.db.GetSourceByIDForUser(event.SourceID, session.UserID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Source not found"})
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
 		return
 	}
 
 	// Try to delete the event from the source calendar
 	sourcePassword, err := h.encryptor.Decrypt(source.SourcePassword)
 	if err == nil {
-		client, err := caldav.NewClient(source.SourceURL, source.SourceUsername, sourcePassword)
+		client, err := caldav.NewClient(source.SourceURL, source.SourceUsername, sourcePassword, h.syncEngine.ClientOptions(source)...)
 		if err == nil {
 			ctx := c.Request.Context()
 			if err := client.DeleteEvent(ctx, event.EventPath); err != nil {
@@ -1207,7 +2389,7 @@ func (h *Handlers) APIDeleteMalformedEvent(c *gin.Context) {
 
 	// Delete the malformed event record
 	if err := h.db.DeleteMalformedEvent(eventID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete malformed event"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to delete malformed event")
 		return
 	}
 
@@ -1218,13 +2400,13 @@ func (h *Handlers) APIDeleteMalformedEvent(c *gin.Context) {
 func (h *Handlers) APIDeleteAllMalformedEvents(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	deleted, err := h.db.DeleteAllMalformedEventsForUser(session.UserID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete malformed events"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to delete malformed events")
 		return
 	}
 
@@ -1239,52 +2421,87 @@ type APIDiscoverCalendarsRequest struct {
 	URL      string `json:"url"`
 	Username string `json:"username"`
 	Password string `json:"password"`
+	// CABundle is the PEM CA bundle the source will trust, so a
+	// server behind an internal CA can be discovered before the
+	// source is saved.
+	CABundle string `json:"ca_bundle"`
+	// ProxyURL is the proxy the source will connect through; empty
+	// uses the server's.
+	ProxyURL string `json:"proxy_url"`
 }
 
 // APIDiscoverCalendars discovers calendars on a CalDAV server.
 func (h *Handlers) APIDiscoverCalendars(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+	if !h.canCreateSources(session) {
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, sourceCreationForbidden)
 		return
 	}
 
 	var req APIDiscoverCalendarsRequest
 	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	if req.URL == "" || req.Username == "" || req.Password == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "URL, username and password are required"})
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "URL, username and password are required")
 		return
 	}
 
 	// ICS feeds don't support calendar discovery
 	if strings.HasSuffix(strings.ToLower(req.URL), ".ics") {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Calendar discovery is not supported for ICS feeds. ICS feeds sync as a single calendar."})
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "Calendar discovery is not supported for ICS feeds. ICS feeds sync as a single calendar.")
+		return
+	}
+	if validationErr := validateCABundle(req.CABundle); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
+		return
+	}
+	if validationErr := validateProxyURL(req.ProxyURL); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
 		return
 	}
 
+	// Repeated discovery of the same account, as the source form does
+	// while it is filled in, is answered from the cache.
+	ttl := h.discoverCacheTTL()
+	if ttl > 0 {
+		if cached, ok := h.discovered.get(session.UserID, &req, time.Now()); ok {
+			c.JSON(http.StatusOK, cached)
+			return
+		}
+	}
+
 	// Create CalDAV client and discover calendars
-	client, err := caldav.NewClient(req.URL, req.Username, req.Password)
+	proxyURL := req.ProxyURL
+	if proxyURL == "" {
+		proxyURL = h.cfg.Proxy.URL
+	}
+	client, err := caldav.NewClient(req.URL, req.Username, req.Password,
+		caldav.WithUserAgent(h.cfg.CalDAV.UserAgent), caldav.WithCABundle(req.CABundle),
+		caldav.WithProxy(proxyURL, h.cfg.Proxy.NoProxy))
 	if err != nil {
 		log.Printf("CalDAV client creation failed for %s: %v", req.URL, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to connect: " + categorizeConnectionError(err)})
+		respondError(c, http.StatusBadRequest, ErrCodeConnectionFailed, "Failed to connect: "+categorizeConnectionError(err))
 		return
 	}
 
 	ctx := c.Request.Context()
 	if err := client.TestConnection(ctx); err != nil {
 		log.Printf("CalDAV connection test failed for %s: %v", req.URL, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Connection test failed: " + categorizeConnectionError(err)})
+		respondError(c, http.StatusBadRequest, ErrCodeConnectionFailed, "Connection test failed: "+categorizeConnectionError(err))
 		return
 	}
 
 	calendars, err := client.FindCalendars(ctx)
 	if err != nil {
 		log.Printf("Calendar discovery failed for %s: %v", req.URL, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to discover calendars: " + categorizeConnectionError(err)})
+		respondError(c, http.StatusBadRequest, ErrCodeConnectionFailed, "Failed to discover calendars: "+categorizeConnectionError(err))
 		return
 	}
 
@@ -1304,28 +2521,42 @@ func (h *Handlers) APIDiscoverCalendars(c *gin.Context) {
 		apiCalendars[i] = apiCal
 	}
 
+	if ttl > 0 {
+		h.discovered.put(session.UserID, &req, apiCalendars, ttl, time.Now())
+	}
 	c.JSON(http.StatusOK, apiCalendars)
 }
 
+// discoverCacheTTL is how long discovery results are cached, from
+// CALDAV_DISCOVER_CACHE_SECS. A nil cfg, as in the test harness,
+// disables the cache.
+func (h *Handlers) discoverCacheTTL() time.Duration {
+	if h.cfg == nil {
+		return 0
+	}
+	return time.Duration(h.cfg.CalDAV.DiscoverCacheSecs) * time.Second
+}
+
 // APIAlertPreferences represents user alert preferences in JSON format.
 type APIAlertPreferences struct {
 	EmailEnabled    *bool  `json:"email_enabled"`
 	WebhookEnabled  *bool  `json:"webhook_enabled"`
 	WebhookURL      string `json:"webhook_url"`
 	CooldownMinutes *int   `json:"cooldown_minutes"`
+	AlertOnPartial  bool   `json:"alert_on_partial"`
 }
 
 // APIGetAlertPreferences returns the user's alert preferences.
 func (h *Handlers) APIGetAlertPreferences(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	prefs, err := h.db.GetUserAlertPreferences(session.UserID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load preferences"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load preferences")
 		return
 	}
 
@@ -1340,6 +2571,7 @@ func (h *Handlers) APIGetAlertPreferences(c *gin.Context) {
 		WebhookEnabled:  prefs.WebhookEnabled,
 		WebhookURL:      prefs.WebhookURL,
 		CooldownMinutes: prefs.CooldownMinutes,
+		AlertOnPartial:  prefs.AlertOnPartial,
 	})
 }
 
@@ -1347,27 +2579,27 @@ func (h *Handlers) APIGetAlertPreferences(c *gin.Context) {
 func (h *Handlers) APIUpdateAlertPreferences(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	var req APIAlertPreferences
 	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	// Validate webhook URL if provided
 	if req.WebhookURL != "" {
 		if err := notify.ValidateWebhookURL(req.WebhookURL); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondError(c, http.StatusBadRequest, ErrCodeValidation, err.Error())
 			return
 		}
 	}
 
 	// Validate cooldown if provided
 	if req.CooldownMinutes != nil && *req.CooldownMinutes < 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Cooldown minutes must be non-negative"})
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "Cooldown minutes must be non-negative")
 		return
 	}
 
@@ -1394,9 +2626,7 @@ func (h *Handlers) APIUpdateAlertPreferences(c *gin.Context) {
 	// setupTestHandlers helper does exactly this. Production
 	// code always passes a real Config via NewHandlers.
 	if req.EmailEnabled != nil && *req.EmailEnabled && h.cfg != nil && h.cfg.Alerts.SMTPHost == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Cannot enable email alerts: this instance has no SMTP configured. Ask your operator to set ALERT_SMTP_HOST / ALERT_SMTP_PORT / ALERT_SMTP_FROM (and ALERT_SMTP_USERNAME / ALERT_SMTP_PASSWORD if the server requires auth).",
-		})
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "Cannot enable email alerts: this instance has no SMTP configured. Ask your operator to set ALERT_SMTP_HOST / ALERT_SMTP_PORT / ALERT_SMTP_FROM (and ALERT_SMTP_USERNAME / ALERT_SMTP_PASSWORD if the server requires auth).")
 		return
 	}
 
@@ -1406,10 +2636,11 @@ func (h *Handlers) APIUpdateAlertPreferences(c *gin.Context) {
 		WebhookEnabled:  req.WebhookEnabled,
 		WebhookURL:      req.WebhookURL,
 		CooldownMinutes: req.CooldownMinutes,
+		AlertOnPartial:  req.AlertOnPartial,
 	}
 
 	if err := h.db.UpsertUserAlertPreferences(prefs); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save preferences"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to save preferences")
 		return
 	}
 
@@ -1418,6 +2649,7 @@ func (h *Handlers) APIUpdateAlertPreferences(c *gin.Context) {
 		WebhookEnabled:  prefs.WebhookEnabled,
 		WebhookURL:      prefs.WebhookURL,
 		CooldownMinutes: prefs.CooldownMinutes,
+		AlertOnPartial:  prefs.AlertOnPartial,
 	})
 }
 
@@ -1435,13 +2667,13 @@ type APITestWebhookRequest struct {
 func (h *Handlers) APIExportCalendars(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	sources, err := h.db.GetSourcesByUserID(session.UserID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load sources"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load sources")
 		return
 	}
 
@@ -1466,7 +2698,7 @@ func (h *Handlers) APIExportCalendars(c *gin.Context) {
 		}
 
 		// Connect to destination CalDAV
-		destClient, err := caldav.NewClient(source.DestURL, source.DestUsername, destPassword)
+		destClient, err := caldav.NewClient(source.DestURL, source.DestUsername, destPassword, h.syncEngine.ClientOptions(source)...)
 		if err != nil {
 			log.Printf("Export: failed to connect to dest for source %s: %v", source.Name, err)
 			continue
@@ -1522,24 +2754,24 @@ func (h *Handlers) APIExportCalendars(c *gin.Context) {
 func (h *Handlers) APITestWebhook(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	var req APITestWebhookRequest
 	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	if req.WebhookURL == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Webhook URL is required"})
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "Webhook URL is required")
 		return
 	}
 
 	// Validate webhook URL
 	if err := notify.ValidateWebhookURL(req.WebhookURL); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, err.Error())
 		return
 	}
 
@@ -1547,7 +2779,7 @@ func (h *Handlers) APITestWebhook(c *gin.Context) {
 	ctx := c.Request.Context()
 	if err := h.notifier.SendTestWebhook(ctx, req.WebhookURL); err != nil {
 		log.Printf("Test webhook failed for %s: %v", req.WebhookURL, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to send test webhook"})
+		respondError(c, http.StatusBadRequest, ErrCodeConnectionFailed, "Failed to send test webhook")
 		return
 	}
 
@@ -1558,7 +2790,7 @@ func (h *Handlers) APITestWebhook(c *gin.Context) {
 func (h *Handlers) APIGetActivity(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -1575,7 +2807,7 @@ func (h *Handlers) APIGetActivity(c *gin.Context) {
 	// Get user's sources to filter activity
 	sources, err := h.db.GetSourcesByUserID(session.UserID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load sources"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load sources")
 		return
 	}
 
@@ -1608,3 +2840,47 @@ func (h *Handlers) APIGetActivity(c *gin.Context) {
 		"recent": userRecent,
 	})
 }
+
+// APISourceActivity returns the progress of the source's running sync,
+// or an idle snapshot when none is running. It is the polling
+// counterpart of APISourceActivityStream.
+func (h *Handlers) APISourceActivity(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	sourceID := c.Param("id")
+	if _, err := h.db.GetSourceByIDForUser(sourceID, session.UserID); err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
+		return
+	}
+
+	tracker := h.syncEngine.GetActivityTracker()
+	if tracker == nil {
+		c.JSON(http.StatusOK, activity.Snapshot{SourceID: sourceID, Status: "idle"})
+		return
+	}
+	c.JSON(http.StatusOK, tracker.Snapshot(sourceID))
+}
+
+// APIHealthDetailed returns the scheduler's health snapshot, limited to
+// the caller's sources. It is the same snapshot the scheduler logs
+// periodically.
+func (h *Handlers) APIHealthDetailed(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	snap, err := h.scheduler.HealthSnapshot()
+	if err != nil {
+		log.Printf("Failed to build health snapshot: %v", err)
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load scheduler health")
+		return
+	}
+
+	c.JSON(http.StatusOK, snap.ForUser(session.UserID))
+}
@@ -1,20 +1,28 @@
 package web
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/macjediwizard/calbridgesync/internal/activity"
 	"github.com/macjediwizard/calbridgesync/internal/auth"
+	"github.com/macjediwizard/calbridgesync/internal/caldav"
 	"github.com/macjediwizard/calbridgesync/internal/config"
+	"github.com/macjediwizard/calbridgesync/internal/crypto"
 	"github.com/macjediwizard/calbridgesync/internal/db"
+	"github.com/macjediwizard/calbridgesync/internal/notify"
 	"github.com/macjediwizard/calbridgesync/internal/scheduler"
 )
 
@@ -708,6 +716,62 @@ func TestAPIListSources(t *testing.T) {
 			t.Fatalf("expected status 401, got %d", w.Code)
 		}
 	})
+
+	t.Run("filters by tag", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		userID, acme := createTestUserAndSource(t, th.db, "test@example.com", "Acme")
+		acme.Tags = []string{"Work", "client-acme"}
+		if err := th.db.UpdateSource(acme); err != nil {
+			t.Fatalf("UpdateSource: %v", err)
+		}
+		_, team := createTestUserAndSource(t, th.db, "test@example.com", "Team")
+		team.Tags = []string{"work"}
+		if err := th.db.UpdateSource(team); err != nil {
+			t.Fatalf("UpdateSource: %v", err)
+		}
+		_, family := createTestUserAndSource(t, th.db, "test@example.com", "Family")
+		family.Tags = []string{"home"}
+		if err := th.db.UpdateSource(family); err != nil {
+			t.Fatalf("UpdateSource: %v", err)
+		}
+		createTestUserAndSource(t, th.db, "test@example.com", "Untagged")
+
+		list := func(query string) []string {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/sources"+query, nil)
+			setAuthContext(c, userID, "test@example.com")
+			th.handlers.APIListSources(c)
+			if w.Code != http.StatusOK {
+				t.Fatalf("%s: expected status 200, got %d", query, w.Code)
+			}
+			var sources []*APISource
+			if err := json.Unmarshal(w.Body.Bytes(), &sources); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			names := make([]string, len(sources))
+			for i, s := range sources {
+				names[i] = s.Name
+			}
+			return names
+		}
+
+		for _, tt := range []struct {
+			query string
+			want  []string
+		}{
+			{"", []string{"Acme", "Family", "Team", "Untagged"}},
+			{"?tag=work", []string{"Acme", "Team"}},
+			{"?tag=client-acme&tag=home", []string{"Acme", "Family"}},
+			{"?tag=nobody", []string{}},
+		} {
+			if got := list(tt.query); !slices.Equal(got, tt.want) {
+				t.Errorf("%q: got %v, want %v", tt.query, got, tt.want)
+			}
+		}
+	})
 }
 
 func TestAPIGetSource(t *testing.T) {
@@ -742,6 +806,57 @@ func TestAPIGetSource(t *testing.T) {
 		}
 	})
 
+	t.Run("last_success_at persists across failed syncs", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Test Source")
+
+		getSource := func() APISource {
+			t.Helper()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/sources/"+source.ID, nil)
+			c.Params = gin.Params{{Key: "id", Value: source.ID}}
+			setAuthContext(c, userID, "test@example.com")
+
+			th.handlers.APIGetSource(c)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var apiSource APISource
+			if err := json.Unmarshal(w.Body.Bytes(), &apiSource); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			return apiSource
+		}
+
+		if got := getSource(); got.LastSuccessAt != nil {
+			t.Errorf("expected no last_success_at before any sync, got %q", *got.LastSuccessAt)
+		}
+
+		if err := th.db.UpdateSourceSyncStatus(source.ID, db.SyncStatusSuccess, "ok"); err != nil {
+			t.Fatalf("failed to update status: %v", err)
+		}
+		succeeded := getSource()
+		if succeeded.LastSuccessAt == nil {
+			t.Fatal("expected last_success_at after a successful sync")
+		}
+
+		time.Sleep(1100 * time.Millisecond)
+		if err := th.db.UpdateSourceSyncStatus(source.ID, db.SyncStatusError, "boom"); err != nil {
+			t.Fatalf("failed to update status: %v", err)
+		}
+		failed := getSource()
+		if failed.LastSuccessAt == nil || *failed.LastSuccessAt != *succeeded.LastSuccessAt {
+			t.Errorf("expected last_success_at to stay %q after a failure, got %v", *succeeded.LastSuccessAt, failed.LastSuccessAt)
+		}
+		if failed.LastSyncAt == nil || *failed.LastSyncAt == *succeeded.LastSuccessAt {
+			t.Errorf("expected last_sync_at to move past the last success, got %v", failed.LastSyncAt)
+		}
+	})
+
 	t.Run("returns 404 for nonexistent source", func(t *testing.T) {
 		th := setupTestHandlers(t)
 		defer th.cleanup()
@@ -785,6 +900,34 @@ func TestAPIGetSource(t *testing.T) {
 	})
 }
 
+func TestAPIGetMalformedEventSummary(t *testing.T) {
+	th := setupTestHandlers(t)
+	defer th.cleanup()
+
+	userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Test Source")
+	th.db.SaveMalformedEvent(source.ID, "/event1.ics", "empty iCalendar data - event may be corrupted or deleted")
+	th.db.SaveMalformedEvent(source.ID, "/event2.ics", "ical: malformed content line: missing colon")
+	th.db.SaveMalformedEvent(source.ID, "/event3.ics", "ical: malformed content line: missing colon")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/malformed-events/summary", nil)
+	setAuthContext(c, userID, "test@example.com")
+
+	th.handlers.APIGetMalformedEventSummary(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var summary APIMalformedEventSummary
+	if err := json.Unmarshal(w.Body.Bytes(), &summary); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if summary.Total != 3 || summary.Categories["missing_colon"] != 2 || summary.Categories["empty"] != 1 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
+
 func TestAPIGetMalformedEvents(t *testing.T) {
 	t.Run("returns malformed events for user", func(t *testing.T) {
 		th := setupTestHandlers(t)
@@ -904,6 +1047,50 @@ func TestAPIDashboardStats(t *testing.T) {
 	})
 }
 
+func TestAPIDashboardOverview(t *testing.T) {
+	th := setupTestHandlers(t)
+	defer th.cleanup()
+
+	userID, source := createTestUserAndSource(t, th.db, "overview@example.com", "Source 1")
+	if err := th.db.CreateSyncLog(&db.SyncLog{SourceID: source.ID, Status: db.SyncStatusSuccess, EventsCreated: 4}); err != nil {
+		t.Fatalf("failed to create log: %v", err)
+	}
+	if err := th.db.CreateSyncLog(&db.SyncLog{SourceID: source.ID, Status: db.SyncStatusError}); err != nil {
+		t.Fatalf("failed to create log: %v", err)
+	}
+
+	// The notifier tracks staleness for every user; another user's
+	// stale source must not count.
+	n := notify.New(&notify.Config{CooldownPeriod: time.Hour})
+	n.SendStaleAlertWithPrefs(context.Background(), source.ID, "Source 1", "", 2*time.Hour, time.Hour, nil)
+	n.SendStaleAlertWithPrefs(context.Background(), "someone-elses", "Other", "", 2*time.Hour, time.Hour, nil)
+	th.handlers.notifier = n
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/dashboard/overview", nil)
+	setAuthContext(c, userID, "overview@example.com")
+
+	th.handlers.APIDashboardOverview(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var overview db.SyncOverview
+	if err := json.Unmarshal(w.Body.Bytes(), &overview); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if overview.TotalSources != 1 || overview.SyncsToday != 2 || overview.EventsToday != 4 {
+		t.Errorf("unexpected counts: %+v", overview)
+	}
+	if overview.SuccessRate != 50 {
+		t.Errorf("expected a 50%% success rate, got %v", overview.SuccessRate)
+	}
+	if overview.StaleSources != 1 {
+		t.Errorf("expected 1 stale source, got %d", overview.StaleSources)
+	}
+}
+
 func TestAPIDeleteSource(t *testing.T) {
 	t.Run("deletes source for valid owner", func(t *testing.T) {
 		th := setupTestHandlers(t)
@@ -1086,195 +1273,304 @@ func TestAPITriggerSync(t *testing.T) {
 			t.Fatalf("expected status 401, got %d", w.Code)
 		}
 	})
-}
 
-func TestAPIGetSourceLogs(t *testing.T) {
-	t.Run("returns logs for valid source", func(t *testing.T) {
+	t.Run("queues once per idempotency key", func(t *testing.T) {
 		th := setupTestHandlers(t)
 		defer th.cleanup()
 
 		userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Test Source")
+		trigger := func(key string) string {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/sources/"+source.ID+"/sync", nil)
+			if key != "" {
+				c.Request.Header.Set("Idempotency-Key", key)
+			}
+			c.Params = gin.Params{{Key: "id", Value: source.ID}}
+			setAuthContext(c, userID, "test@example.com")
+			th.handlers.APITriggerSync(c)
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var resp map[string]string
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			return resp["message"]
+		}
 
-		// Create some logs
-		th.db.CreateSyncLog(&db.SyncLog{
-			SourceID:      source.ID,
-			Status:        db.SyncStatusSuccess,
-			Message:       "Sync completed",
-			EventsCreated: 5,
-			Duration:      time.Second * 2,
-		})
+		if got := trigger("click-1"); got != "Sync triggered" {
+			t.Errorf("first request: message = %q, want Sync triggered", got)
+		}
+		if got := trigger("click-1"); got != "Sync already queued" {
+			t.Errorf("repeated key: message = %q, want Sync already queued", got)
+		}
+		if got := trigger("click-2"); got != "Sync triggered" {
+			t.Errorf("new key: message = %q, want Sync triggered", got)
+		}
+
+		logs, _, err := th.db.GetAuditLogs(userID, 1, 50)
+		if err != nil {
+			t.Fatalf("GetAuditLogs: %v", err)
+		}
+		triggered := 0
+		for _, l := range logs {
+			if l.Action == "sync.trigger" {
+				triggered++
+			}
+		}
+		if triggered != 2 {
+			t.Errorf("expected the sync queued twice (once per key), got %d", triggered)
+		}
+	})
+}
 
+func TestAPICancelSync(t *testing.T) {
+	cancelSync := func(th *testHandlers, userID, sourceID string) *httptest.ResponseRecorder {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodGet, "/api/sources/"+source.ID+"/logs", nil)
-		c.Params = gin.Params{{Key: "id", Value: source.ID}}
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/sources/"+sourceID+"/cancel", nil)
+		c.Params = gin.Params{{Key: "id", Value: sourceID}}
 		setAuthContext(c, userID, "test@example.com")
+		th.handlers.APICancelSync(c)
+		return w
+	}
 
-		th.handlers.APIGetSourceLogs(c)
+	t.Run("idle source is a no-op", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+		userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Test Source")
 
-		if w.Code != http.StatusOK {
-			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		w := cancelSync(th, userID, source.ID)
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "No sync is running") {
+			t.Fatalf("expected a no-op response, got %d: %s", w.Code, w.Body.String())
 		}
+	})
 
-		var response map[string]interface{}
-		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
-			t.Fatalf("failed to parse response: %v", err)
-		}
+	t.Run("returns 404 for another user's source", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+		_, source := createTestUserAndSource(t, th.db, "owner@example.com", "Owner Source")
+		other, _ := th.db.GetOrCreateUser("test@example.com", "Test User")
 
-		logs := response["logs"].([]interface{})
-		if len(logs) != 1 {
-			t.Errorf("expected 1 log, got %d", len(logs))
+		if w := cancelSync(th, other.ID, source.ID); w.Code != http.StatusNotFound {
+			t.Fatalf("expected status 404, got %d", w.Code)
 		}
 	})
+}
 
-	t.Run("returns paginated logs", func(t *testing.T) {
+// oneCalendarServer is a CalDAV server with a single, empty "Work"
+// calendar at /dav/calendars/work/.
+func oneCalendarServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch path := strings.TrimSuffix(r.URL.Path, "/"); {
+		case r.Method == "REPORT":
+		case r.Method != "PROPFIND":
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		case path == "/dav":
+			body = `<d:response><d:href>/dav/</d:href><d:propstat><d:prop>` +
+				`<d:current-user-principal><d:href>/dav/principals/user/</d:href></d:current-user-principal>` +
+				`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`
+		case path == "/dav/principals/user":
+			body = `<d:response><d:href>/dav/principals/user/</d:href><d:propstat><d:prop>` +
+				`<cal:calendar-home-set><d:href>/dav/calendars/</d:href></cal:calendar-home-set>` +
+				`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`
+		case path == "/dav/calendars" || path == "/dav/calendars/work":
+			body = `<d:response><d:href>/dav/calendars/work/</d:href><d:propstat><d:prop>` +
+				`<d:resourcetype><d:collection/><cal:calendar/></d:resourcetype><d:displayname>Work</d:displayname>` +
+				`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">` +
+			body + `</d:multistatus>`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAPITriggerSyncCalendar(t *testing.T) {
+	setup := func(t *testing.T) (*testHandlers, string, *db.Source) {
+		t.Helper()
 		th := setupTestHandlers(t)
-		defer th.cleanup()
+		t.Cleanup(th.cleanup)
+		enc, err := crypto.NewEncryptor([]byte("0123456789abcdef0123456789abcdef"))
+		if err != nil {
+			t.Fatalf("NewEncryptor: %v", err)
+		}
+		th.handlers.syncEngine = caldav.NewSyncEngine(th.db, enc)
+		th.handlers.scheduler = scheduler.New(th.db, th.handlers.syncEngine, nil)
 
 		userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Test Source")
-
+		source.SourceURL = oneCalendarServer(t).URL + "/dav/"
+		source.DestURL = oneCalendarServer(t).URL + "/dav/"
+		if source.SourcePassword, err = enc.Encrypt("source-secret"); err != nil {
+			t.Fatalf("Encrypt: %v", err)
+		}
+		if source.DestPassword, err = enc.Encrypt("dest-secret"); err != nil {
+			t.Fatalf("Encrypt: %v", err)
+		}
+		if err := th.db.UpdateSource(source); err != nil {
+			t.Fatalf("UpdateSource: %v", err)
+		}
+		return th, userID, source
+	}
+	syncCalendar := func(th *testHandlers, userID, sourceID, calendarPath string) *httptest.ResponseRecorder {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodGet, "/api/sources/"+source.ID+"/logs?page=2", nil)
-		c.Params = gin.Params{{Key: "id", Value: source.ID}}
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/sources/"+sourceID+"/sync?calendar="+calendarPath, nil)
+		c.Params = gin.Params{{Key: "id", Value: sourceID}}
 		setAuthContext(c, userID, "test@example.com")
+		th.handlers.APITriggerSync(c)
+		return w
+	}
 
-		th.handlers.APIGetSourceLogs(c)
+	t.Run("returns the calendar's result", func(t *testing.T) {
+		th, userID, source := setup(t)
+
+		w := syncCalendar(th, userID, source.ID, "/dav/calendars/work/")
 
 		if w.Code != http.StatusOK {
-			t.Fatalf("expected status 200, got %d", w.Code)
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
 		}
-
-		var response map[string]interface{}
-		json.Unmarshal(w.Body.Bytes(), &response)
-
-		page := int(response["page"].(float64))
-		if page != 2 {
-			t.Errorf("expected page 2, got %d", page)
+		var result caldav.SyncResult
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if !result.Success || result.CalendarsSynced != 1 || result.Calendar != "/dav/calendars/work/" {
+			t.Errorf("expected a successful sync of the one calendar, got %+v", result)
 		}
 	})
 
-	t.Run("returns 404 for nonexistent source", func(t *testing.T) {
-		th := setupTestHandlers(t)
-		defer th.cleanup()
+	t.Run("rejects an unknown calendar", func(t *testing.T) {
+		th, userID, source := setup(t)
 
-		user, _ := th.db.GetOrCreateUser("test@example.com", "Test User")
+		w := syncCalendar(th, userID, source.ID, "/dav/calendars/personal/")
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
 
+func TestAPIUndoLastSync(t *testing.T) {
+	undo := func(th *testHandlers, userID, sourceID string) *httptest.ResponseRecorder {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodGet, "/api/sources/nonexistent/logs", nil)
-		c.Params = gin.Params{{Key: "id", Value: "nonexistent"}}
-		setAuthContext(c, user.ID, "test@example.com")
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/sources/"+sourceID+"/undo-last-sync", nil)
+		c.Params = gin.Params{{Key: "id", Value: sourceID}}
+		if userID != "" {
+			setAuthContext(c, userID, "test@example.com")
+		}
+		th.handlers.APIUndoLastSync(c)
+		return w
+	}
 
-		th.handlers.APIGetSourceLogs(c)
+	t.Run("returns 404 when nothing was deleted", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+		th.handlers.syncEngine = caldav.NewSyncEngine(th.db, nil)
 
-		if w.Code != http.StatusNotFound {
-			t.Fatalf("expected status 404, got %d", w.Code)
+		userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Test Source")
+		w := undo(th, userID, source.ID)
+		if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), "No deleted events") {
+			t.Fatalf("expected 404 nothing to restore, got %d: %s", w.Code, w.Body.String())
 		}
 	})
 
-	t.Run("returns unauthorized when not authenticated", func(t *testing.T) {
+	t.Run("returns 404 for another user's source", func(t *testing.T) {
 		th := setupTestHandlers(t)
 		defer th.cleanup()
 
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodGet, "/api/sources/some-id/logs", nil)
-		c.Params = gin.Params{{Key: "id", Value: "some-id"}}
+		_, source := createTestUserAndSource(t, th.db, "owner@example.com", "Owner Source")
+		other, _ := th.db.GetOrCreateUser("other@example.com", "Other")
+		w := undo(th, other.ID, source.ID)
+		if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), "Source not found") {
+			t.Fatalf("expected 404 source not found, got %d: %s", w.Code, w.Body.String())
+		}
+	})
 
-		th.handlers.APIGetSourceLogs(c)
+	t.Run("returns unauthorized when not authenticated", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
 
-		if w.Code != http.StatusUnauthorized {
+		if w := undo(th, "", "some-id"); w.Code != http.StatusUnauthorized {
 			t.Fatalf("expected status 401, got %d", w.Code)
 		}
 	})
 }
 
-func TestAPISyncHistory(t *testing.T) {
-	t.Run("returns sync history with default 7 days", func(t *testing.T) {
+func TestAPISyncAllSources(t *testing.T) {
+	t.Run("queues only the user's enabled sources", func(t *testing.T) {
 		th := setupTestHandlers(t)
 		defer th.cleanup()
 
-		userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Test Source")
-
-		// Create some logs
-		th.db.CreateSyncLog(&db.SyncLog{
-			SourceID:      source.ID,
-			Status:        db.SyncStatusSuccess,
-			Message:       "Sync completed",
-			EventsCreated: 5,
-			Duration:      time.Second * 2,
-		})
+		userID, first := createTestUserAndSource(t, th.db, "test@example.com", "First")
+		_, second := createTestUserAndSource(t, th.db, "test@example.com", "Second")
+		_, disabled := createTestUserAndSource(t, th.db, "test@example.com", "Disabled")
+		disabled.Enabled = false
+		if err := th.db.UpdateSource(disabled); err != nil {
+			t.Fatalf("UpdateSource: %v", err)
+		}
+		_, foreign := createTestUserAndSource(t, th.db, "other@example.com", "Foreign")
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodGet, "/api/dashboard/sync-history", nil)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/sources/sync-all", nil)
 		setAuthContext(c, userID, "test@example.com")
 
-		th.handlers.APISyncHistory(c)
+		th.handlers.APISyncAllSources(c)
 
 		if w.Code != http.StatusOK {
 			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
 		}
-
-		var response APISyncHistory
-		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		var resp APISyncAllResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
 			t.Fatalf("failed to parse response: %v", err)
 		}
-
-		if len(response.History) != 7 {
-			t.Errorf("expected 7 history points, got %d", len(response.History))
+		queued := make(map[string]bool)
+		for _, id := range resp.SourceIDs {
+			queued[id] = true
 		}
-	})
-
-	t.Run("accepts custom days parameter", func(t *testing.T) {
-		th := setupTestHandlers(t)
-		defer th.cleanup()
-
-		user, _ := th.db.GetOrCreateUser("test@example.com", "Test User")
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodGet, "/api/dashboard/sync-history?days=14", nil)
-		setAuthContext(c, user.ID, "test@example.com")
-
-		th.handlers.APISyncHistory(c)
-
-		if w.Code != http.StatusOK {
-			t.Fatalf("expected status 200, got %d", w.Code)
+		if len(resp.SourceIDs) != 2 || !queued[first.ID] || !queued[second.ID] {
+			t.Errorf("expected exactly the two enabled sources queued, got %v", resp.SourceIDs)
 		}
-
-		var response APISyncHistory
-		json.Unmarshal(w.Body.Bytes(), &response)
-
-		if len(response.History) != 14 {
-			t.Errorf("expected 14 history points, got %d", len(response.History))
+		if queued[disabled.ID] {
+			t.Error("disabled source must not be queued")
+		}
+		if queued[foreign.ID] {
+			t.Error("another user's source must not be queued")
 		}
 	})
 
-	t.Run("clamps days parameter to valid range", func(t *testing.T) {
+	t.Run("returns an empty list without sources", func(t *testing.T) {
 		th := setupTestHandlers(t)
 		defer th.cleanup()
 
-		user, _ := th.db.GetOrCreateUser("test@example.com", "Test User")
+		user, _ := th.db.GetOrCreateUser("empty@example.com", "Empty")
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodGet, "/api/dashboard/sync-history?days=100", nil)
-		setAuthContext(c, user.ID, "test@example.com")
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/sources/sync-all", nil)
+		setAuthContext(c, user.ID, "empty@example.com")
 
-		th.handlers.APISyncHistory(c)
+		th.handlers.APISyncAllSources(c)
 
 		if w.Code != http.StatusOK {
 			t.Fatalf("expected status 200, got %d", w.Code)
 		}
-
-		var response APISyncHistory
-		json.Unmarshal(w.Body.Bytes(), &response)
-
-		// Should default to 7 since 100 > 30
-		if len(response.History) != 7 {
-			t.Errorf("expected 7 history points for invalid days, got %d", len(response.History))
+		var resp APISyncAllResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		if resp.SourceIDs == nil || len(resp.SourceIDs) != 0 {
+			t.Errorf("expected an empty source_ids array, got %v", resp.SourceIDs)
 		}
 	})
 
@@ -1284,9 +1580,9 @@ func TestAPISyncHistory(t *testing.T) {
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodGet, "/api/dashboard/sync-history", nil)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/sources/sync-all", nil)
 
-		th.handlers.APISyncHistory(c)
+		th.handlers.APISyncAllSources(c)
 
 		if w.Code != http.StatusUnauthorized {
 			t.Fatalf("expected status 401, got %d", w.Code)
@@ -1294,116 +1590,704 @@ func TestAPISyncHistory(t *testing.T) {
 	})
 }
 
-func TestAPIDeleteMalformedEvent(t *testing.T) {
-	t.Run("returns 404 for nonexistent event", func(t *testing.T) {
+func TestAPIDeleteSourceLogs(t *testing.T) {
+	deleteLogs := func(th *testHandlers, userID, sourceID string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/api/sources/"+sourceID+"/logs", nil)
+		c.Params = gin.Params{{Key: "id", Value: sourceID}}
+		if userID != "" {
+			setAuthContext(c, userID, "test@example.com")
+		}
+		th.handlers.APIDeleteSourceLogs(c)
+		return w
+	}
+
+	t.Run("deletes the source's logs and reports the count", func(t *testing.T) {
 		th := setupTestHandlers(t)
 		defer th.cleanup()
 
-		user, _ := th.db.GetOrCreateUser("test@example.com", "Test User")
+		userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Test Source")
+		for i := 0; i < 2; i++ {
+			th.db.CreateSyncLog(&db.SyncLog{SourceID: source.ID, Status: db.SyncStatusSuccess})
+		}
 
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodDelete, "/api/malformed-events/nonexistent", nil)
-		c.Params = gin.Params{{Key: "id", Value: "nonexistent"}}
-		setAuthContext(c, user.ID, "test@example.com")
+		w := deleteLogs(th, userID, source.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var response struct {
+			Deleted int `json:"deleted"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		if response.Deleted != 2 {
+			t.Errorf("expected 2 deleted, got %d", response.Deleted)
+		}
+		if logs, _ := th.db.GetSyncLogs(source.ID, 10); len(logs) != 0 {
+			t.Errorf("expected no logs left, got %d", len(logs))
+		}
+	})
 
-		th.handlers.APIDeleteMalformedEvent(c)
+	t.Run("returns 404 for another user's source and keeps its logs", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
 
-		if w.Code != http.StatusNotFound {
+		_, source := createTestUserAndSource(t, th.db, "owner@example.com", "Owner Source")
+		th.db.CreateSyncLog(&db.SyncLog{SourceID: source.ID, Status: db.SyncStatusSuccess})
+		other, _ := th.db.GetOrCreateUser("other@example.com", "Other")
+
+		if w := deleteLogs(th, other.ID, source.ID); w.Code != http.StatusNotFound {
 			t.Fatalf("expected status 404, got %d", w.Code)
 		}
+		if logs, _ := th.db.GetSyncLogs(source.ID, 10); len(logs) != 1 {
+			t.Errorf("expected the owner's log to survive, got %d", len(logs))
+		}
 	})
 
 	t.Run("returns unauthorized when not authenticated", func(t *testing.T) {
 		th := setupTestHandlers(t)
 		defer th.cleanup()
 
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
+		if w := deleteLogs(th, "", "some-id"); w.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status 401, got %d", w.Code)
+		}
+	})
+}
+
+func TestAPIGetSourceLogs(t *testing.T) {
+	t.Run("returns logs for valid source", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Test Source")
+
+		// Create some logs
+		th.db.CreateSyncLog(&db.SyncLog{
+			SourceID:      source.ID,
+			Status:        db.SyncStatusSuccess,
+			Message:       "Sync completed",
+			EventsCreated: 5,
+			Duration:      time.Second * 2,
+		})
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/sources/"+source.ID+"/logs", nil)
+		c.Params = gin.Params{{Key: "id", Value: source.ID}}
+		setAuthContext(c, userID, "test@example.com")
+
+		th.handlers.APIGetSourceLogs(c)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var response map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+
+		logs := response["logs"].([]interface{})
+		if len(logs) != 1 {
+			t.Errorf("expected 1 log, got %d", len(logs))
+		}
+	})
+
+	t.Run("returns paginated logs", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Test Source")
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/sources/"+source.ID+"/logs?page=2", nil)
+		c.Params = gin.Params{{Key: "id", Value: source.ID}}
+		setAuthContext(c, userID, "test@example.com")
+
+		th.handlers.APIGetSourceLogs(c)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		page := int(response["page"].(float64))
+		if page != 2 {
+			t.Errorf("expected page 2, got %d", page)
+		}
+	})
+
+	t.Run("returns 404 for nonexistent source", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		user, _ := th.db.GetOrCreateUser("test@example.com", "Test User")
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/sources/nonexistent/logs", nil)
+		c.Params = gin.Params{{Key: "id", Value: "nonexistent"}}
+		setAuthContext(c, user.ID, "test@example.com")
+
+		th.handlers.APIGetSourceLogs(c)
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("returns unauthorized when not authenticated", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/sources/some-id/logs", nil)
+		c.Params = gin.Params{{Key: "id", Value: "some-id"}}
+
+		th.handlers.APIGetSourceLogs(c)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status 401, got %d", w.Code)
+		}
+	})
+}
+
+func TestAPIGetSourceLog(t *testing.T) {
+	getLog := func(th *testHandlers, userID, email, sourceID, logID string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/sources/"+sourceID+"/logs/"+logID, nil)
+		c.Params = gin.Params{{Key: "id", Value: sourceID}, {Key: "log_id", Value: logID}}
+		setAuthContext(c, userID, email)
+		th.handlers.APIGetSourceLog(c)
+		return w
+	}
+
+	t.Run("returns the log with its conflicts and malformed events", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Test Source")
+		if err := th.db.SaveMalformedEvent(source.ID, "/cal/broken.ics", "missing colon"); err != nil {
+			t.Fatalf("SaveMalformedEvent: %v", err)
+		}
+		syncLog := &db.SyncLog{
+			SourceID: source.ID,
+			Status:   db.SyncStatusPartial,
+			Message:  "Synced with warnings",
+			Details: "Warnings:\n" +
+				`CONFLICT:{"uid":"abc@example.com","winner":"source","summary":"Standup","strategy":"source_wins"}` + "\n" +
+				"Failed to create event on dest: PUT /remote.php/dav/x.ics: 400 Bad Request",
+			Duration: 2 * time.Second,
+		}
+		if err := th.db.CreateSyncLog(syncLog); err != nil {
+			t.Fatalf("CreateSyncLog: %v", err)
+		}
+
+		w := getLog(th, userID, "test@example.com", source.ID, syncLog.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var response APISyncLogDetail
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		if response.Log == nil || response.Log.ID != syncLog.ID {
+			t.Fatalf("expected log %s, got %+v", syncLog.ID, response.Log)
+		}
+		if response.Log.Details == nil || strings.Contains(*response.Log.Details, "/remote.php") {
+			t.Errorf("expected summarized details, got %v", response.Log.Details)
+		}
+		want := APISyncConflict{UID: "abc@example.com", Summary: "Standup", Winner: "source", Strategy: "source_wins"}
+		if len(response.Conflicts) != 1 || response.Conflicts[0] != want {
+			t.Errorf("conflicts = %+v, want [%+v]", response.Conflicts, want)
+		}
+		if len(response.MalformedEvents) != 1 || response.MalformedEvents[0].EventPath != "/cal/broken.ics" {
+			t.Errorf("expected the run's malformed event, got %+v", response.MalformedEvents)
+		}
+	})
+
+	t.Run("returns 404 for nonexistent log", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Test Source")
+		if w := getLog(th, userID, "test@example.com", source.ID, "nonexistent"); w.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("returns 404 for another user's log", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		_, source := createTestUserAndSource(t, th.db, "owner@example.com", "Owner Source")
+		otherID, otherSource := createTestUserAndSource(t, th.db, "other@example.com", "Other Source")
+		syncLog := &db.SyncLog{SourceID: source.ID, Status: db.SyncStatusSuccess, Message: "ok"}
+		if err := th.db.CreateSyncLog(syncLog); err != nil {
+			t.Fatalf("CreateSyncLog: %v", err)
+		}
+
+		if w := getLog(th, otherID, "other@example.com", source.ID, syncLog.ID); w.Code != http.StatusNotFound {
+			t.Errorf("expected status 404 for a source the user doesn't own, got %d", w.Code)
+		}
+		if w := getLog(th, otherID, "other@example.com", otherSource.ID, syncLog.ID); w.Code != http.StatusNotFound {
+			t.Errorf("expected status 404 for a log of a different source, got %d", w.Code)
+		}
+	})
+}
+
+func TestAPISyncHistory(t *testing.T) {
+	t.Run("returns sync history with default 7 days", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Test Source")
+
+		// Create some logs
+		th.db.CreateSyncLog(&db.SyncLog{
+			SourceID:      source.ID,
+			Status:        db.SyncStatusSuccess,
+			Message:       "Sync completed",
+			EventsCreated: 5,
+			Duration:      time.Second * 2,
+		})
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/dashboard/sync-history", nil)
+		setAuthContext(c, userID, "test@example.com")
+
+		th.handlers.APISyncHistory(c)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var response APISyncHistory
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+
+		if len(response.History) != 7 {
+			t.Errorf("expected 7 history points, got %d", len(response.History))
+		}
+	})
+
+	t.Run("accepts custom days parameter", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		user, _ := th.db.GetOrCreateUser("test@example.com", "Test User")
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/dashboard/sync-history?days=14", nil)
+		setAuthContext(c, user.ID, "test@example.com")
+
+		th.handlers.APISyncHistory(c)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+
+		var response APISyncHistory
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		if len(response.History) != 14 {
+			t.Errorf("expected 14 history points, got %d", len(response.History))
+		}
+	})
+
+	t.Run("clamps days parameter to valid range", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		user, _ := th.db.GetOrCreateUser("test@example.com", "Test User")
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/dashboard/sync-history?days=100", nil)
+		setAuthContext(c, user.ID, "test@example.com")
+
+		th.handlers.APISyncHistory(c)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+
+		var response APISyncHistory
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		// Should default to 7 since 100 > 30
+		if len(response.History) != 7 {
+			t.Errorf("expected 7 history points for invalid days, got %d", len(response.History))
+		}
+	})
+
+	t.Run("returns unauthorized when not authenticated", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/dashboard/sync-history", nil)
+
+		th.handlers.APISyncHistory(c)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status 401, got %d", w.Code)
+		}
+	})
+}
+
+func TestAPIDeleteMalformedEvent(t *testing.T) {
+	t.Run("returns 404 for nonexistent event", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		user, _ := th.db.GetOrCreateUser("test@example.com", "Test User")
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/api/malformed-events/nonexistent", nil)
+		c.Params = gin.Params{{Key: "id", Value: "nonexistent"}}
+		setAuthContext(c, user.ID, "test@example.com")
+
+		th.handlers.APIDeleteMalformedEvent(c)
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("returns unauthorized when not authenticated", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
 		c.Request = httptest.NewRequest(http.MethodDelete, "/api/malformed-events/some-id", nil)
 		c.Params = gin.Params{{Key: "id", Value: "some-id"}}
 
-		th.handlers.APIDeleteMalformedEvent(c)
+		th.handlers.APIDeleteMalformedEvent(c)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status 401, got %d", w.Code)
+		}
+	})
+}
+
+func TestAPIUpdateSource(t *testing.T) {
+	t.Run("returns 404 for nonexistent source", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		user, _ := th.db.GetOrCreateUser("test@example.com", "Test User")
+
+		body := `{"name": "Updated"}`
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPut, "/api/sources/nonexistent", strings.NewReader(body))
+		c.Params = gin.Params{{Key: "id", Value: "nonexistent"}}
+		setAuthContext(c, user.ID, "test@example.com")
+
+		th.handlers.APIUpdateSource(c)
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("returns bad request for invalid JSON", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Test Source")
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPut, "/api/sources/"+source.ID, strings.NewReader("invalid json"))
+		c.Params = gin.Params{{Key: "id", Value: source.ID}}
+		setAuthContext(c, userID, "test@example.com")
+
+		th.handlers.APIUpdateSource(c)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("returns bad request for invalid input", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Test Source")
+
+		body := `{"name": "` + strings.Repeat("a", 200) + `"}`
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPut, "/api/sources/"+source.ID, strings.NewReader(body))
+		c.Params = gin.Params{{Key: "id", Value: source.ID}}
+		setAuthContext(c, userID, "test@example.com")
+
+		th.handlers.APIUpdateSource(c)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("returns unauthorized when not authenticated", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPut, "/api/sources/some-id", nil)
+		c.Params = gin.Params{{Key: "id", Value: "some-id"}}
+
+		th.handlers.APIUpdateSource(c)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status 401, got %d", w.Code)
+		}
+	})
+}
+
+// principalServer is a CalDAV server that answers the connection test's
+// principal lookup for user/password and rejects everything else. It
+// counts the requests it receives.
+func principalServer(t *testing.T, password string, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if u, p, ok := r.BasicAuth(); !ok || u != "user" || p != password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusMultiStatus)
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="utf-8"?>
+<d:multistatus xmlns:d="DAV:"><d:response><d:href>%s</d:href><d:propstat><d:prop>
+<d:current-user-principal><d:href>/principals/user/</d:href></d:current-user-principal>
+</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response></d:multistatus>`, r.URL.Path)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAPIUpdateSourceConnectionTests(t *testing.T) {
+	setup := func(t *testing.T) (*testHandlers, string, *db.Source) {
+		t.Helper()
+		th := setupTestHandlers(t)
+		t.Cleanup(th.cleanup)
+		th.handlers.syncEngine = caldav.NewSyncEngine(th.db, nil)
+		enc, err := crypto.NewEncryptor([]byte("0123456789abcdef0123456789abcdef"))
+		if err != nil {
+			t.Fatalf("NewEncryptor: %v", err)
+		}
+		th.handlers.encryptor = enc
+
+		userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Test Source")
+		if source.SourcePassword, err = enc.Encrypt("stored-secret"); err != nil {
+			t.Fatalf("Encrypt: %v", err)
+		}
+		if err := th.db.UpdateSource(source); err != nil {
+			t.Fatalf("UpdateSource: %v", err)
+		}
+		return th, userID, source
+	}
+	update := func(t *testing.T, th *testHandlers, userID string, source *db.Source, changes map[string]any) *httptest.ResponseRecorder {
+		t.Helper()
+		body := map[string]any{
+			"name":              source.Name,
+			"source_type":       string(source.SourceType),
+			"source_url":        source.SourceURL,
+			"source_username":   source.SourceUsername,
+			"dest_url":          source.DestURL,
+			"dest_username":     source.DestUsername,
+			"sync_direction":    string(source.SyncDirection),
+			"conflict_strategy": string(source.ConflictStrategy),
+		}
+		for k, v := range changes {
+			body[k] = v
+		}
+		data, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPut, "/api/sources/"+source.ID, strings.NewReader(string(data)))
+		c.Params = gin.Params{{Key: "id", Value: source.ID}}
+		setAuthContext(c, userID, "test@example.com")
+		th.handlers.APIUpdateSource(c)
+		return w
+	}
+
+	t.Run("name-only update skips the connection test", func(t *testing.T) {
+		// The stored URLs are unreachable, so any test would fail.
+		th, userID, source := setup(t)
+		w := update(t, th, userID, source, map[string]any{"name": "Renamed"})
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		saved, _ := th.db.GetSourceByID(source.ID)
+		if saved.Name != "Renamed" {
+			t.Errorf("expected the new name to be saved, got %q", saved.Name)
+		}
+	})
+
+	t.Run("URL change is tested with the stored password", func(t *testing.T) {
+		th, userID, source := setup(t)
+		var hits atomic.Int32
+		srv := principalServer(t, "stored-secret", &hits)
+
+		w := update(t, th, userID, source, map[string]any{"source_url": srv.URL + "/dav/"})
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if hits.Load() == 0 {
+			t.Error("expected the new source URL to be tested")
+		}
+		saved, _ := th.db.GetSourceByID(source.ID)
+		if saved.SourceURL != srv.URL+"/dav/" {
+			t.Errorf("expected the new URL to be saved, got %q", saved.SourceURL)
+		}
+	})
+
+	t.Run("failed test blocks the save", func(t *testing.T) {
+		th, userID, source := setup(t)
+		var hits atomic.Int32
+		srv := principalServer(t, "other-secret", &hits)
 
-		if w.Code != http.StatusUnauthorized {
-			t.Fatalf("expected status 401, got %d", w.Code)
+		w := update(t, th, userID, source, map[string]any{"name": "Renamed", "source_url": srv.URL + "/dav/"})
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "Failed to connect to source") {
+			t.Fatalf("expected a source connection error, got %d: %s", w.Code, w.Body.String())
+		}
+		saved, _ := th.db.GetSourceByID(source.ID)
+		if saved.SourceURL != source.SourceURL || saved.Name != source.Name {
+			t.Errorf("expected the source to be left unchanged, got %q / %q", saved.Name, saved.SourceURL)
+		}
+	})
+
+	t.Run("new password is tested instead of the stored one", func(t *testing.T) {
+		th, userID, source := setup(t)
+		var hits atomic.Int32
+		srv := principalServer(t, "new-secret", &hits)
+		source.SourceURL = srv.URL + "/dav/"
+		if err := th.db.UpdateSource(source); err != nil {
+			t.Fatalf("UpdateSource: %v", err)
+		}
+
+		w := update(t, th, userID, source, map[string]any{"source_password": "new-secret"})
+		if w.Code != http.StatusOK || hits.Load() == 0 {
+			t.Fatalf("expected a tested, successful update, got %d after %d requests: %s", w.Code, hits.Load(), w.Body.String())
 		}
 	})
 }
 
-func TestAPIUpdateSource(t *testing.T) {
-	t.Run("returns 404 for nonexistent source", func(t *testing.T) {
+func TestDestHostAllowList(t *testing.T) {
+	setup := func(t *testing.T, allowed []string) *testHandlers {
+		t.Helper()
 		th := setupTestHandlers(t)
-		defer th.cleanup()
-
+		t.Cleanup(th.cleanup)
+		th.handlers.cfg = &config.Config{
+			CalDAV: config.CalDAVConfig{AllowedDestHosts: allowed},
+			Sync:   config.SyncConfig{MinInterval: 300, MaxInterval: 3600},
+		}
+		th.handlers.syncEngine = caldav.NewSyncEngine(th.db, nil)
+		enc, err := crypto.NewEncryptor([]byte("0123456789abcdef0123456789abcdef"))
+		if err != nil {
+			t.Fatalf("NewEncryptor: %v", err)
+		}
+		th.handlers.encryptor = enc
+		return th
+	}
+	create := func(t *testing.T, th *testHandlers, destURL string) *httptest.ResponseRecorder {
+		t.Helper()
 		user, _ := th.db.GetOrCreateUser("test@example.com", "Test User")
-
-		body := `{"name": "Updated"}`
+		data, _ := json.Marshal(map[string]any{
+			"name":              "Allow-list Source",
+			"source_type":       string(db.SourceTypeCalDAV),
+			"source_url":        destURL,
+			"source_username":   "user",
+			"source_password":   "secret",
+			"dest_url":          destURL,
+			"dest_username":     "user",
+			"dest_password":     "secret",
+			"sync_direction":    string(db.SyncDirectionOneWay),
+			"conflict_strategy": string(db.ConflictSourceWins),
+		})
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPut, "/api/sources/nonexistent", strings.NewReader(body))
-		c.Params = gin.Params{{Key: "id", Value: "nonexistent"}}
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/sources", strings.NewReader(string(data)))
 		setAuthContext(c, user.ID, "test@example.com")
+		th.handlers.APICreateSource(c)
+		return w
+	}
 
-		th.handlers.APIUpdateSource(c)
+	var hits atomic.Int32
+	srv := principalServer(t, "secret", &hits)
+	destURL := srv.URL + "/dav/"
 
-		if w.Code != http.StatusNotFound {
-			t.Fatalf("expected status 404, got %d", w.Code)
+	t.Run("allowed host is accepted", func(t *testing.T) {
+		th := setup(t, []string{"127.0.0.1"})
+		if w := create(t, th, destURL); w.Code != http.StatusCreated {
+			t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
 		}
 	})
 
-	t.Run("returns bad request for invalid JSON", func(t *testing.T) {
-		th := setupTestHandlers(t)
-		defer th.cleanup()
-
-		userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Test Source")
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPut, "/api/sources/"+source.ID, strings.NewReader("invalid json"))
-		c.Params = gin.Params{{Key: "id", Value: source.ID}}
-		setAuthContext(c, userID, "test@example.com")
-
-		th.handlers.APIUpdateSource(c)
-
-		if w.Code != http.StatusBadRequest {
-			t.Fatalf("expected status 400, got %d", w.Code)
+	t.Run("disallowed host is rejected", func(t *testing.T) {
+		th := setup(t, []string{"dav.example.com"})
+		before := hits.Load()
+		w := create(t, th, destURL)
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "Destination host is not allowed") {
+			t.Fatalf("expected an allow-list error, got %d: %s", w.Code, w.Body.String())
+		}
+		if hits.Load() != before {
+			t.Error("expected the rejected destination not to be contacted")
 		}
 	})
 
-	t.Run("returns bad request for invalid input", func(t *testing.T) {
-		th := setupTestHandlers(t)
-		defer th.cleanup()
+	t.Run("empty list allows any host", func(t *testing.T) {
+		th := setup(t, nil)
+		if w := create(t, th, destURL); w.Code != http.StatusCreated {
+			t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+	})
 
+	t.Run("update to a disallowed host is rejected", func(t *testing.T) {
+		th := setup(t, []string{"dav.example.com"})
 		userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Test Source")
-
-		body := `{"name": "` + strings.Repeat("a", 200) + `"}`
+		data, _ := json.Marshal(map[string]any{
+			"name":              source.Name,
+			"source_type":       string(source.SourceType),
+			"source_url":        source.SourceURL,
+			"source_username":   source.SourceUsername,
+			"dest_url":          destURL,
+			"dest_username":     "user",
+			"dest_password":     "secret",
+			"sync_direction":    string(source.SyncDirection),
+			"conflict_strategy": string(source.ConflictStrategy),
+		})
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPut, "/api/sources/"+source.ID, strings.NewReader(body))
+		c.Request = httptest.NewRequest(http.MethodPut, "/api/sources/"+source.ID, strings.NewReader(string(data)))
 		c.Params = gin.Params{{Key: "id", Value: source.ID}}
 		setAuthContext(c, userID, "test@example.com")
-
 		th.handlers.APIUpdateSource(c)
 
-		if w.Code != http.StatusBadRequest {
-			t.Fatalf("expected status 400, got %d", w.Code)
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "Destination host is not allowed") {
+			t.Fatalf("expected an allow-list error, got %d: %s", w.Code, w.Body.String())
 		}
-	})
-
-	t.Run("returns unauthorized when not authenticated", func(t *testing.T) {
-		th := setupTestHandlers(t)
-		defer th.cleanup()
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPut, "/api/sources/some-id", nil)
-		c.Params = gin.Params{{Key: "id", Value: "some-id"}}
-
-		th.handlers.APIUpdateSource(c)
-
-		if w.Code != http.StatusUnauthorized {
-			t.Fatalf("expected status 401, got %d", w.Code)
+		saved, _ := th.db.GetSourceByID(source.ID)
+		if saved.DestURL != source.DestURL {
+			t.Errorf("expected the destination to be left unchanged, got %q", saved.DestURL)
 		}
 	})
 }
@@ -1481,6 +2365,94 @@ func TestAPICreateSource(t *testing.T) {
 	})
 }
 
+func TestPresetSourceURL(t *testing.T) {
+	testCases := []struct {
+		sourceType string
+		sourceURL  string
+		want       string
+	}{
+		{"icloud", "", "https://caldav.icloud.com/"},
+		{"icloud", "  ", "https://caldav.icloud.com/"},
+		{"fastmail", "", "https://caldav.fastmail.com/dav/"},
+		{"icloud", "https://p42-caldav.icloud.com/123/", "https://p42-caldav.icloud.com/123/"},
+		{"nextcloud", "", ""},
+		{"custom", "", ""},
+		{"", "", ""},
+	}
+	for _, tc := range testCases {
+		if got := presetSourceURL(tc.sourceType, tc.sourceURL); got != tc.want {
+			t.Errorf("presetSourceURL(%q, %q) = %q, want %q", tc.sourceType, tc.sourceURL, got, tc.want)
+		}
+	}
+}
+
+func TestValidateProviderSource(t *testing.T) {
+	testCases := []struct {
+		name       string
+		sourceType string
+		sourceURL  string
+		password   string
+		contains   string
+	}{
+		{"icloud default", "icloud", "https://caldav.icloud.com/", "abcd-efgh-ijkl-mnop", ""},
+		{"icloud partition host", "icloud", "https://p42-caldav.icloud.com/1234/calendars/", "abcd-efgh-ijkl-mnop", ""},
+		{"icloud keeps stored password", "icloud", "https://caldav.icloud.com/", "", ""},
+		{"icloud wrong host", "icloud", "https://caldav.fastmail.com/dav/", "abcd-efgh-ijkl-mnop", "iCloud CalDAV URL"},
+		{"icloud lookalike host", "icloud", "https://evilicloud.com/", "abcd-efgh-ijkl-mnop", "iCloud CalDAV URL"},
+		{"icloud account password", "icloud", "https://caldav.icloud.com/", "hunter2", "app-specific password"},
+		{"fastmail subdomain", "fastmail", "https://caldav.fastmail.com/dav/", "anything", ""},
+		{"fastmail wrong host", "fastmail", "https://caldav.icloud.com/", "anything", "Fastmail CalDAV URL"},
+		{"custom unrestricted", "custom", "https://dav.example.com/", "anything", ""},
+		{"unknown type", "", "https://dav.example.com/", "anything", ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := validateProviderSource(tc.sourceType, tc.sourceURL, tc.password)
+			if tc.contains == "" && got != "" {
+				t.Errorf("expected no error, got %q", got)
+			}
+			if tc.contains != "" && !strings.Contains(got, tc.contains) {
+				t.Errorf("expected error containing %q, got %q", tc.contains, got)
+			}
+		})
+	}
+}
+
+func TestAPICreateSourceProviderPresets(t *testing.T) {
+	create := func(t *testing.T, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		th := setupTestHandlers(t)
+		t.Cleanup(th.cleanup)
+		user, _ := th.db.GetOrCreateUser("test@example.com", "Test User")
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/sources", strings.NewReader(body))
+		setAuthContext(c, user.ID, "test@example.com")
+		th.handlers.APICreateSource(c)
+		return w
+	}
+
+	t.Run("blank icloud URL gets the iCloud default", func(t *testing.T) {
+		// Without the default the request would fail as missing
+		// fields; instead it reaches the provider checks, which accept
+		// the default URL and then reject the account password.
+		w := create(t, `{"name": "iCloud", "source_type": "icloud", "source_url": "",
+			"source_username": "me@icloud.com", "source_password": "account-password"}`)
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "app-specific password") {
+			t.Fatalf("expected the app-specific password error, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("rejects a non-iCloud URL for an icloud source", func(t *testing.T) {
+		w := create(t, `{"name": "iCloud", "source_type": "icloud", "source_url": "https://calendar.example.com/dav/",
+			"source_username": "me@icloud.com", "source_password": "abcd-efgh-ijkl-mnop"}`)
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "iCloud CalDAV URL") {
+			t.Fatalf("expected the iCloud URL error, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
+
 func TestAPIDiscoverCalendars(t *testing.T) {
 	t.Run("returns bad request for invalid JSON", func(t *testing.T) {
 		th := setupTestHandlers(t)
@@ -1535,6 +2507,77 @@ func TestAPIDiscoverCalendars(t *testing.T) {
 	})
 }
 
+func TestSourceCreationAllowList(t *testing.T) {
+	// Each request carries an invalid body, so a user past the
+	// allow-list gets 400 and a user stopped by it gets 403.
+	call := func(t *testing.T, th *testHandlers, handler gin.HandlerFunc, userID, email string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/sources", strings.NewReader("invalid json"))
+		setAuthContext(c, userID, email)
+		handler(c)
+		return w
+	}
+	restricted := &config.Config{Auth: config.AuthConfig{
+		AllowedEmails:  []string{"admin@example.com"},
+		AllowedDomains: []string{"corp.example"},
+	}}
+
+	for _, tt := range []struct {
+		name     string
+		cfg      *config.Config
+		email    string
+		wantCode int
+	}{
+		{"empty allow-list lets everyone in", &config.Config{}, "anyone@example.org", http.StatusBadRequest},
+		{"allowed email", restricted, "Admin@Example.com", http.StatusBadRequest},
+		{"allowed domain", restricted, "someone@corp.example", http.StatusBadRequest},
+		{"user outside the allow-list", restricted, "user@example.com", http.StatusForbidden},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			th := setupTestHandlers(t)
+			defer th.cleanup()
+			th.handlers.cfg = tt.cfg
+			user, _ := th.db.GetOrCreateUser(tt.email, "Test User")
+
+			handlers := map[string]gin.HandlerFunc{
+				"create":   th.handlers.APICreateSource,
+				"discover": th.handlers.APIDiscoverCalendars,
+			}
+			// The Google flow checks its own config right after the
+			// allow-list, so only the 403 is meaningful there.
+			if tt.wantCode == http.StatusForbidden {
+				handlers["google"] = th.handlers.APIPrepareGoogleSource
+			}
+			for name, handler := range handlers {
+				w := call(t, th, handler, user.ID, tt.email)
+				if w.Code != tt.wantCode {
+					t.Errorf("%s: expected status %d, got %d: %s", name, tt.wantCode, w.Code, w.Body.String())
+				}
+			}
+		})
+	}
+
+	t.Run("disallowed users still manage existing sources", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+		th.handlers.cfg = restricted
+		userID, source := createTestUserAndSource(t, th.db, "user@example.com", "Existing")
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/sources/"+source.ID, nil)
+		c.Params = gin.Params{{Key: "id", Value: source.ID}}
+		setAuthContext(c, userID, "user@example.com")
+		th.handlers.APIGetSource(c)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
+
 // Note: APILogout requires a session manager to be present.
 // Full testing would require mocking the session manager.
 // The handler is tested indirectly through integration tests.
@@ -1719,3 +2762,374 @@ func TestAPIUpdateAlertPreferences_EmailEnableRejectedWhenNoSMTP(t *testing.T) {
 		}
 	})
 }
+
+func TestValidateAttendeeRewrite(t *testing.T) {
+	t.Run("accepts nil and valid maps", func(t *testing.T) {
+		if msg := validateAttendeeRewrite(nil); msg != "" {
+			t.Errorf("expected nil map to be valid, got %q", msg)
+		}
+		if msg := validateAttendeeRewrite(map[string]string{"alice@work.example": "mailto:alice@home.example"}); msg != "" {
+			t.Errorf("expected valid map, got %q", msg)
+		}
+	})
+
+	t.Run("rejects empty addresses", func(t *testing.T) {
+		if msg := validateAttendeeRewrite(map[string]string{"alice@work.example": " "}); msg == "" {
+			t.Error("expected error for empty target address")
+		}
+	})
+
+	t.Run("rejects line breaks", func(t *testing.T) {
+		if msg := validateAttendeeRewrite(map[string]string{"a@x": "b@y\r\nX-INJECT:1"}); msg == "" {
+			t.Error("expected error for CRLF in address")
+		}
+	})
+
+	t.Run("rejects too many entries", func(t *testing.T) {
+		m := make(map[string]string)
+		for i := 0; i <= maxAttendeeRewriteEntries; i++ {
+			m[fmt.Sprintf("user%d@work.example", i)] = "me@home.example"
+		}
+		if msg := validateAttendeeRewrite(m); msg == "" {
+			t.Error("expected error for too many entries")
+		}
+	})
+}
+
+func TestValidateUIDPrefix(t *testing.T) {
+	tests := []struct {
+		name      string
+		prefix    string
+		direction string
+		wantErr   bool
+	}{
+		{"empty with two-way", "", "two_way", false},
+		{"valid one-way", "work", "one_way", false},
+		{"valid default direction", "work", "", false},
+		{"invalid characters", "work/home", "one_way", true},
+		{"two-way rejected", "work", "two_way", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := validateUIDPrefix(tt.prefix, tt.direction)
+			if (msg != "") != tt.wantErr {
+				t.Errorf("validateUIDPrefix(%q, %q) = %q, wantErr %v", tt.prefix, tt.direction, msg, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateResultWebhookURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{"empty disables", "", false},
+		{"public https", "https://hooks.example.com/calbridge", false},
+		{"plain http", "http://hooks.example.com/calbridge", true},
+		{"localhost", "https://localhost/hook", true},
+		{"loopback IP", "https://127.0.0.1/hook", true},
+		{"private IP", "https://192.168.1.10/hook", true},
+		{"metadata endpoint", "https://169.254.169.254/latest", true},
+		{"too long", "https://hooks.example.com/" + strings.Repeat("a", maxURLLength), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := validateResultWebhookURL(tt.url)
+			if (msg != "") != tt.wantErr {
+				t.Errorf("validateResultWebhookURL(%q) = %q, wantErr %v", tt.url, msg, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateMaxRequestsPerSecond(t *testing.T) {
+	for _, tt := range []struct {
+		rps     float64
+		wantErr bool
+	}{
+		{0, false},
+		{0.5, false},
+		{10, false},
+		{100, false},
+		{-1, true},
+		{101, true},
+	} {
+		if msg := validateMaxRequestsPerSecond(tt.rps); (msg != "") != tt.wantErr {
+			t.Errorf("validateMaxRequestsPerSecond(%v) = %q, wantErr %v", tt.rps, msg, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateMaxSyncDuration(t *testing.T) {
+	for _, tt := range []struct {
+		secs    int
+		wantErr bool
+	}{
+		{0, false},
+		{60, false},
+		{86400, false},
+		{59, true},
+		{86401, true},
+		{-1, true},
+	} {
+		if msg := validateMaxSyncDuration(tt.secs); (msg != "") != tt.wantErr {
+			t.Errorf("%d: validateMaxSyncDuration = %q, wantErr %v", tt.secs, msg, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateInitialSyncEventCap(t *testing.T) {
+	for _, tt := range []struct {
+		n       int
+		wantErr bool
+	}{
+		{0, false},
+		{500, false},
+		{caldav.MaxInitialSyncEventCap, false},
+		{caldav.MaxInitialSyncEventCap + 1, true},
+		{-1, true},
+	} {
+		if msg := validateInitialSyncEventCap(tt.n); (msg != "") != tt.wantErr {
+			t.Errorf("%d: validateInitialSyncEventCap = %q, wantErr %v", tt.n, msg, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateCustomHeaders(t *testing.T) {
+	tooMany := make(map[string]string)
+	for i := 0; i <= maxCustomHeaders; i++ {
+		tooMany[fmt.Sprintf("X-Header-%d", i)] = "v"
+	}
+	for _, tt := range []struct {
+		name    string
+		headers map[string]string
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"proxy header", map[string]string{"X-Proxy-Tenant": "acme", "Proxy-Authorization": "Basic abc"}, false},
+		{"user agent override", map[string]string{"User-Agent": "Picky/1.0"}, false},
+		{"authorization", map[string]string{"Authorization": "Bearer x"}, true},
+		{"authorization lowercase", map[string]string{"authorization": "Bearer x"}, true},
+		{"host", map[string]string{"Host": "evil.example"}, true},
+		{"bad name", map[string]string{"X Bad": "v"}, true},
+		{"empty name", map[string]string{"": "v"}, true},
+		{"line break in value", map[string]string{"X-Test": "a\r\nInjected: yes"}, true},
+		{"too many", tooMany, true},
+	} {
+		if msg := validateCustomHeaders(tt.headers); (msg != "") != tt.wantErr {
+			t.Errorf("%s: validateCustomHeaders = %q, wantErr %v", tt.name, msg, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateIncludeCategories(t *testing.T) {
+	tooMany := make([]string, maxIncludeCategories+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("Category %d", i)
+	}
+	for _, tt := range []struct {
+		name    string
+		cats    []string
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"valid", []string{"Work", "Client Meetings"}, false},
+		{"blank", []string{"Work", "  "}, true},
+		{"comma", []string{"Work,Home"}, true},
+		{"line break", []string{"Work\r\nX"}, true},
+		{"too long", []string{strings.Repeat("a", maxNameLength+1)}, true},
+		{"too many", tooMany, true},
+	} {
+		if msg := validateIncludeCategories(tt.cats); (msg != "") != tt.wantErr {
+			t.Errorf("%s: validateIncludeCategories = %q, wantErr %v", tt.name, msg, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	tooMany := make([]string, maxSourceTags+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("tag-%d", i)
+	}
+	for _, tt := range []struct {
+		name    string
+		tags    []string
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"valid", []string{"work", "Client A"}, false},
+		{"blank", []string{" "}, true},
+		{"comma", []string{"a,b"}, true},
+		{"duplicate", []string{"Work", "work"}, true},
+		{"too long", []string{strings.Repeat("a", maxTagLength+1)}, true},
+		{"too many", tooMany, true},
+	} {
+		if msg := validateTags(tt.tags); (msg != "") != tt.wantErr {
+			t.Errorf("%s: validateTags = %q, wantErr %v", tt.name, msg, tt.wantErr)
+		}
+	}
+}
+
+func TestValidatePreserveDestProps(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		props   []string
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"valid", []string{"X-NOTE", "categories"}, false},
+		{"space", []string{"X NOTE"}, true},
+		{"colon", []string{"X-NOTE:"}, true},
+		{"uid", []string{"uid"}, true},
+		{"recurrence id", []string{"RECURRENCE-ID"}, true},
+		{"too many", make([]string, maxPreserveDestProps+1), true},
+	} {
+		if msg := validatePreserveDestProps(tt.props); (msg != "") != tt.wantErr {
+			t.Errorf("%s: validatePreserveDestProps = %q, wantErr %v", tt.name, msg, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateTargetTimezone(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		wantErr bool
+	}{
+		{"", false},
+		{"America/New_York", false},
+		{"UTC", false},
+		{"Local", true},
+		{"Mars/Olympus_Mons", true},
+		{"GMT-0400", true},
+	} {
+		if msg := validateTargetTimezone(tt.name); (msg != "") != tt.wantErr {
+			t.Errorf("%q: validateTargetTimezone = %q, wantErr %v", tt.name, msg, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateInviteFilter(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		source  db.Source
+		wantErr bool
+	}{
+		{"off", db.Source{SourceUsername: "alice"}, false},
+		{"email username", db.Source{SourceUsername: "alice@work.example", SkipInvitedEvents: true}, false},
+		{"account email", db.Source{SourceUsername: "alice", AccountEmail: "alice@work.example", SkipInvitedEvents: true}, false},
+		{"no address", db.Source{SourceUsername: "alice", SkipInvitedEvents: true}, true},
+		{"invalid account email", db.Source{AccountEmail: "not an email"}, true},
+		{"display name", db.Source{AccountEmail: "Alice <alice@work.example>"}, true},
+	} {
+		if msg := validateInviteFilter(&tt.source); (msg != "") != tt.wantErr {
+			t.Errorf("%s: validateInviteFilter = %q, wantErr %v", tt.name, msg, tt.wantErr)
+		}
+	}
+}
+
+func TestAPIHealthDetailed(t *testing.T) {
+	th := setupTestHandlers(t)
+	defer th.cleanup()
+	sched := scheduler.New(th.db, nil, nil)
+	th.handlers.scheduler = sched
+
+	userID, source := createTestUserAndSource(t, th.db, "health@example.com", "Mine")
+	createTestUserAndSource(t, th.db, "other@example.com", "Theirs")
+	if err := th.db.CreateSyncLog(&db.SyncLog{SourceID: source.ID, Status: db.SyncStatusError}); err != nil {
+		t.Fatalf("failed to create sync log: %v", err)
+	}
+	if err := th.db.UpdateSourceSyncStatus(source.ID, db.SyncStatusError, "boom"); err != nil {
+		t.Fatalf("failed to update status: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/health/detailed", nil)
+	setAuthContext(c, userID, "health@example.com")
+
+	th.handlers.APIHealthDetailed(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var snap scheduler.HealthSnapshot
+	if err := json.Unmarshal(w.Body.Bytes(), &snap); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(snap.Sources) != 1 || snap.Sources[0].ID != source.ID {
+		t.Fatalf("expected only the caller's source, got %+v", snap.Sources)
+	}
+	if snap.Sources[0].LastSyncStatus != db.SyncStatusError || snap.FailedSyncs != 1 || snap.ErrorRate != 1 {
+		t.Errorf("expected the failed sync to be reported, got %+v", snap)
+	}
+	if strings.Contains(w.Body.String(), "user_id") {
+		t.Error("expected user IDs to be left out of the response")
+	}
+}
+
+func TestAPISourceActivity(t *testing.T) {
+	th := setupTestHandlers(t)
+	defer th.cleanup()
+	th.handlers.syncEngine = caldav.NewSyncEngine(th.db, nil)
+	tracker := th.handlers.syncEngine.GetActivityTracker()
+
+	userID, source := createTestUserAndSource(t, th.db, "test@example.com", "Test Source")
+	get := func(userID string) (*httptest.ResponseRecorder, activity.Snapshot) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/sources/"+source.ID+"/activity", nil)
+		c.Params = gin.Params{{Key: "id", Value: source.ID}}
+		if userID != "" {
+			setAuthContext(c, userID, "test@example.com")
+		}
+		th.handlers.APISourceActivity(c)
+		var snap activity.Snapshot
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &snap); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+		}
+		return w, snap
+	}
+
+	t.Run("idle when no sync is running", func(t *testing.T) {
+		w, snap := get(userID)
+		if w.Code != http.StatusOK || snap.Running || snap.Status != "idle" || snap.SourceID != source.ID {
+			t.Fatalf("expected an idle snapshot, got %d: %s", w.Code, w.Body.String())
+		}
+		if strings.Contains(w.Body.String(), "started_at") {
+			t.Errorf("expected no start time while idle: %s", w.Body.String())
+		}
+	})
+
+	t.Run("progress while a sync is running", func(t *testing.T) {
+		tracker.StartSync(source.ID, source.Name, 2)
+		defer tracker.FinishSync(source.ID, true, "", nil)
+		tracker.UpdateCalendar(source.ID, "Work", 1)
+		tracker.IncrementProgress(source.ID, 2, 1, 0, 0, 3)
+
+		w, snap := get(userID)
+		if w.Code != http.StatusOK || !snap.Running || snap.Status != "running" {
+			t.Fatalf("expected a running snapshot, got %d: %s", w.Code, w.Body.String())
+		}
+		if snap.CurrentCalendar != "Work" || snap.CalendarIndex != 1 || snap.TotalCalendars != 2 ||
+			snap.EventsCreated != 2 || snap.EventsUpdated != 1 || snap.StartedAt.IsZero() {
+			t.Errorf("unexpected snapshot %+v", snap)
+		}
+	})
+
+	t.Run("returns 404 for another user's source", func(t *testing.T) {
+		other, _ := th.db.GetOrCreateUser("other@example.com", "Other")
+		if w, _ := get(other.ID); w.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("returns unauthorized when not authenticated", func(t *testing.T) {
+		if w, _ := get(""); w.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", w.Code)
+		}
+	})
+}
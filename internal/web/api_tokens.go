@@ -0,0 +1,134 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/macjediwizard/calbridgesync/internal/auth"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// maxAPITokenNameLength caps the label a user gives a token.
+const maxAPITokenNameLength = 100
+
+// resolveAPIToken is the auth.TokenResolver backing bearer-token
+// access to /api. It looks the token up by hash, stamps last_used_at,
+// and returns session data for the owning user so handlers treat the
+// request exactly like a browser session for that user.
+func (h *Handlers) resolveAPIToken(token string) (*auth.SessionData, error) {
+	apiToken, err := h.db.GetAPITokenByHash(auth.HashAPIToken(token))
+	if err != nil {
+		return nil, err
+	}
+	user, err := h.db.GetUserByID(apiToken.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.db.TouchAPIToken(apiToken.ID); err != nil {
+		log.Printf("Failed to record API token use: %v", err)
+	}
+	return &auth.SessionData{
+		UserID:  user.ID,
+		Email:   user.Email,
+		Name:    user.Name,
+		IsAdmin: h.isAdmin(user.Email, nil),
+	}, nil
+}
+
+// APIListAPITokens returns the current user's API tokens. Hashes are
+// never included.
+func (h *Handlers) APIListAPITokens(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+	tokens, err := h.db.GetAPITokensByUserID(session.UserID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load API tokens")
+		return
+	}
+	if tokens == nil {
+		tokens = []*db.APIToken{}
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// APICreateAPIToken issues a new API token and returns its plaintext
+// once. Only a browser session may mint tokens: letting a token create
+// more tokens would make revoking a leaked one pointless.
+func (h *Handlers) APICreateAPIToken(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+	if auth.IsTokenAuth(c) {
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, "API tokens cannot create other API tokens")
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid request body")
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "Token name is required")
+		return
+	}
+	if len(req.Name) > maxAPITokenNameLength {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "Token name must be 100 characters or less")
+		return
+	}
+
+	plaintext, hash, err := auth.GenerateAPIToken()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, sanitizeError(err, "Failed to generate API token"))
+		return
+	}
+	token := &db.APIToken{
+		UserID:    session.UserID,
+		TokenHash: hash,
+		Name:      req.Name,
+	}
+	if err := h.db.CreateAPIToken(token); err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, sanitizeError(err, "Failed to create API token"))
+		return
+	}
+
+	h.audit(c, "api_token.create", "api_token", token.ID, req.Name)
+	c.JSON(http.StatusCreated, gin.H{
+		"id":         token.ID,
+		"name":       token.Name,
+		"created_at": token.CreatedAt,
+		"token":      plaintext,
+	})
+}
+
+// APIRevokeAPIToken deletes one of the current user's API tokens.
+func (h *Handlers) APIRevokeAPIToken(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+	tokenID := c.Param("id")
+	if err := h.db.DeleteAPIToken(tokenID, session.UserID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			respondError(c, http.StatusNotFound, ErrCodeNotFound, "API token not found")
+			return
+		}
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, sanitizeError(err, "Failed to revoke API token"))
+		return
+	}
+	h.audit(c, "api_token.revoke", "api_token", tokenID, "")
+	c.JSON(http.StatusOK, gin.H{"message": "API token revoked"})
+}
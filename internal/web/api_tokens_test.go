@@ -0,0 +1,180 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/macjediwizard/calbridgesync/internal/auth"
+)
+
+// tokenRouter wires the API token middleware and a few handlers the
+// way SetupRoutes does, without the rate limiters.
+func tokenRouter(th *testHandlers) *gin.Engine {
+	sm := auth.NewSessionManager("test-secret-key-at-least-32-chars", false, 86400, 300)
+	r := gin.New()
+	api := r.Group("/api")
+	api.Use(auth.RequireAuthOrToken(sm, th.handlers.resolveAPIToken))
+	api.Use(ValidateOrigin())
+	api.Use(auth.ValidateAPICSRF())
+	api.Use(RequireJSONContentType())
+	api.GET("/sources", th.handlers.APIListSources)
+	api.POST("/sources/:id/toggle", th.handlers.APIToggleSource)
+	api.GET("/tokens", th.handlers.APIListAPITokens)
+	api.POST("/tokens", th.handlers.APICreateAPIToken)
+	api.DELETE("/tokens/:id", th.handlers.APIRevokeAPIToken)
+	return r
+}
+
+// createAPIToken issues a token for userID through the handler and
+// returns its ID and plaintext.
+func createAPIToken(t *testing.T, th *testHandlers, userID, email, name string) (string, string) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/tokens", strings.NewReader(`{"name":"`+name+`"}`))
+	setAuthContext(c, userID, email)
+
+	th.handlers.APICreateAPIToken(c)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		ID    string `json:"id"`
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.ID == "" || !strings.HasPrefix(resp.Token, "cbs_") {
+		t.Fatalf("unexpected create response: %s", w.Body.String())
+	}
+	return resp.ID, resp.Token
+}
+
+func bearerRequest(method, path, token string) *http.Request {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestAPITokenAuthentication(t *testing.T) {
+	t.Run("valid token authenticates as its owner", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+		router := tokenRouter(th)
+
+		aliceID, aliceSource := createTestUserAndSource(t, th.db, "alice@example.com", "Alice Source")
+		createTestUserAndSource(t, th.db, "bob@example.com", "Bob Source")
+		_, token := createAPIToken(t, th, aliceID, "alice@example.com", "ci")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, bearerRequest(http.MethodGet, "/api/sources", token))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var sources []*APISource
+		if err := json.Unmarshal(w.Body.Bytes(), &sources); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		if len(sources) != 1 || sources[0].ID != aliceSource.ID {
+			t.Errorf("expected only Alice's source, got %+v", sources)
+		}
+
+		// State-changing calls work without an Origin header or CSRF token.
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, bearerRequest(http.MethodPost, "/api/sources/"+aliceSource.ID+"/toggle", token))
+		if w.Code != http.StatusOK {
+			t.Errorf("expected toggle via token to succeed, got %d: %s", w.Code, w.Body.String())
+		}
+
+		tokens, err := th.db.GetAPITokensByUserID(aliceID)
+		if err != nil {
+			t.Fatalf("failed to list tokens: %v", err)
+		}
+		if len(tokens) != 1 || tokens[0].LastUsedAt == nil {
+			t.Errorf("expected last_used_at to be recorded, got %+v", tokens)
+		}
+	})
+
+	t.Run("unknown token is rejected with 401", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		w := httptest.NewRecorder()
+		tokenRouter(th).ServeHTTP(w, bearerRequest(http.MethodGet, "/api/sources", "cbs_not-a-real-token"))
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected status 401, got %d", w.Code)
+		}
+	})
+
+	t.Run("revoked token is rejected with 401", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+		router := tokenRouter(th)
+
+		userID, _ := createTestUserAndSource(t, th.db, "alice@example.com", "Alice Source")
+		tokenID, token := createAPIToken(t, th, userID, "alice@example.com", "ci")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, bearerRequest(http.MethodDelete, "/api/tokens/"+tokenID, token))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected revoke to succeed, got %d: %s", w.Code, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, bearerRequest(http.MethodGet, "/api/sources", token))
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected status 401 after revoke, got %d", w.Code)
+		}
+	})
+
+	t.Run("tokens are scoped to their user", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+		router := tokenRouter(th)
+
+		aliceID, _ := createTestUserAndSource(t, th.db, "alice@example.com", "Alice Source")
+		bobID, _ := createTestUserAndSource(t, th.db, "bob@example.com", "Bob Source")
+		aliceTokenID, _ := createAPIToken(t, th, aliceID, "alice@example.com", "alice-ci")
+		_, bobToken := createAPIToken(t, th, bobID, "bob@example.com", "bob-ci")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, bearerRequest(http.MethodGet, "/api/tokens", bobToken))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+		if strings.Contains(w.Body.String(), aliceTokenID) {
+			t.Error("Bob's token list must not include Alice's token")
+		}
+		if strings.Contains(w.Body.String(), "hash") {
+			t.Error("token list must not expose hashes")
+		}
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, bearerRequest(http.MethodDelete, "/api/tokens/"+aliceTokenID, bobToken))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected 404 revoking another user's token, got %d", w.Code)
+		}
+	})
+
+	t.Run("token cannot mint another token", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+
+		userID, _ := createTestUserAndSource(t, th.db, "alice@example.com", "Alice Source")
+		_, token := createAPIToken(t, th, userID, "alice@example.com", "ci")
+
+		req := bearerRequest(http.MethodPost, "/api/tokens", token)
+		req.Body = http.NoBody
+		w := httptest.NewRecorder()
+		tokenRouter(th).ServeHTTP(w, req)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("expected status 403, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package web
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"sync"
+	"time"
+)
+
+// maxDiscoverCacheEntries bounds how many discovery results are kept.
+// When full, expired entries are swept and then the one closest to
+// expiry is evicted.
+const maxDiscoverCacheEntries = 256
+
+// discoverCacheEntry is one cached discovery result.
+type discoverCacheEntry struct {
+	// password is a salted hash of the password the result was
+	// discovered with; a different password misses and evicts it.
+	password  [sha256.Size]byte
+	calendars []*APICalendar
+	expires   time.Time
+}
+
+// discoverCache remembers APIDiscoverCalendars results for a short
+// while, keyed by user, server URL, username and CA bundle. Keys and passwords
+// are kept only as salted hashes. The zero value is ready to use.
+type discoverCache struct {
+	mu      sync.Mutex
+	salt    []byte
+	entries map[[sha256.Size]byte]discoverCacheEntry
+}
+
+// hash returns a salted hash of parts, generating the salt on first
+// use. The caller holds mu.
+func (d *discoverCache) hash(parts ...string) [sha256.Size]byte {
+	if d.salt == nil {
+		d.salt = make([]byte, 32)
+		_, _ = rand.Read(d.salt)
+	}
+	h := sha256.New()
+	h.Write(d.salt)
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// key returns the cache key for userID's discovery req. The caller
+// holds mu.
+func (d *discoverCache) key(userID string, req *APIDiscoverCalendarsRequest) [sha256.Size]byte {
+	return d.hash(userID, req.URL, req.Username, req.CABundle, req.ProxyURL)
+}
+
+// get returns the calendars cached for userID's discovery req, if they
+// haven't expired by now and were discovered with req's password. An
+// entry for a different password is dropped.
+func (d *discoverCache) get(userID string, req *APIDiscoverCalendarsRequest, now time.Time) ([]*APICalendar, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := d.key(userID, req)
+	entry, ok := d.entries[key]
+	if !ok {
+		return nil, false
+	}
+	pw := d.hash(req.Password)
+	if !now.Before(entry.expires) || subtle.ConstantTimeCompare(entry.password[:], pw[:]) != 1 {
+		delete(d.entries, key)
+		return nil, false
+	}
+	return entry.calendars, true
+}
+
+// put caches calendars for userID's discovery req until now plus ttl.
+func (d *discoverCache) put(userID string, req *APIDiscoverCalendarsRequest, calendars []*APICalendar, ttl time.Duration, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := d.key(userID, req)
+	if _, exists := d.entries[key]; !exists && len(d.entries) >= maxDiscoverCacheEntries {
+		var oldestKey [sha256.Size]byte
+		var oldest time.Time
+		for k, e := range d.entries {
+			if !now.Before(e.expires) {
+				delete(d.entries, k)
+				continue
+			}
+			if oldest.IsZero() || e.expires.Before(oldest) {
+				oldestKey, oldest = k, e.expires
+			}
+		}
+		if len(d.entries) >= maxDiscoverCacheEntries {
+			delete(d.entries, oldestKey)
+		}
+	}
+	if d.entries == nil {
+		d.entries = make(map[[sha256.Size]byte]discoverCacheEntry)
+	}
+	d.entries[key] = discoverCacheEntry{
+		password:  d.hash(req.Password),
+		calendars: calendars,
+		expires:   now.Add(ttl),
+	}
+}
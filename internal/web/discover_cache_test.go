@@ -0,0 +1,126 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/macjediwizard/calbridgesync/internal/config"
+)
+
+func TestAPIDiscoverCalendars_Cache(t *testing.T) {
+	th := setupTestHandlers(t)
+	defer th.cleanup()
+	th.handlers.cfg = &config.Config{CalDAV: config.CalDAVConfig{DiscoverCacheSecs: 60}}
+
+	srv := oneCalendarServer(t)
+	var hits atomic.Int64
+	inner := srv.Config.Handler
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		inner.ServeHTTP(w, r)
+	})
+
+	user, _ := th.db.GetOrCreateUser("test@example.com", "Test User")
+	discover := func(username, password string) {
+		t.Helper()
+		body := fmt.Sprintf(`{"url":%q,"username":%q,"password":%q}`, srv.URL+"/dav/", username, password)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/calendars/discover", strings.NewReader(body))
+		setAuthContext(c, user.ID, "test@example.com")
+		th.handlers.APIDiscoverCalendars(c)
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "/dav/calendars/work/") {
+			t.Fatalf("expected the Work calendar, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	discover("alice", "secret")
+	first := hits.Load()
+	if first == 0 {
+		t.Fatal("expected the first discovery to reach the server")
+	}
+
+	discover("alice", "secret")
+	if got := hits.Load(); got != first {
+		t.Errorf("expected a repeat discovery within the TTL to be cached, server saw %d more requests", got-first)
+	}
+
+	discover("bob", "secret")
+	if got := hits.Load(); got == first {
+		t.Error("expected a different username to bypass the cache")
+	}
+
+	before := hits.Load()
+	discover("alice", "changed")
+	if got := hits.Load(); got == before {
+		t.Error("expected a changed password to bypass the cache")
+	}
+}
+
+func TestDiscoverCache(t *testing.T) {
+	now := time.Now()
+	cals := []*APICalendar{{Name: "Work", Path: "/cal/work/"}}
+	alice := &APIDiscoverCalendarsRequest{URL: "https://dav.example.com/", Username: "alice", Password: "pw"}
+
+	t.Run("expires after the TTL", func(t *testing.T) {
+		var d discoverCache
+		d.put("u1", alice, cals, time.Minute, now)
+		if _, ok := d.get("u1", alice, now.Add(59*time.Second)); !ok {
+			t.Error("expected a hit within the TTL")
+		}
+		if _, ok := d.get("u1", alice, now.Add(time.Minute)); ok {
+			t.Error("expected a miss once the TTL has passed")
+		}
+	})
+
+	t.Run("is per user and CA bundle", func(t *testing.T) {
+		var d discoverCache
+		d.put("u1", alice, cals, time.Minute, now)
+		if _, ok := d.get("u2", alice, now); ok {
+			t.Error("expected another user to miss")
+		}
+		withCA := *alice
+		withCA.CABundle = "-----BEGIN CERTIFICATE-----"
+		if _, ok := d.get("u1", &withCA, now); ok {
+			t.Error("expected a different CA bundle to miss")
+		}
+	})
+
+	t.Run("a changed password evicts the entry", func(t *testing.T) {
+		var d discoverCache
+		d.put("u1", alice, cals, time.Minute, now)
+		changed := *alice
+		changed.Password = "other"
+		if _, ok := d.get("u1", &changed, now); ok {
+			t.Error("expected a different password to miss")
+		}
+		if _, ok := d.get("u1", alice, now); ok {
+			t.Error("expected the entry to be gone after the password changed")
+		}
+	})
+
+	t.Run("is bounded", func(t *testing.T) {
+		var d discoverCache
+		server := func(i int) *APIDiscoverCalendarsRequest {
+			return &APIDiscoverCalendarsRequest{URL: fmt.Sprintf("https://dav%d.example.com/", i), Username: "alice", Password: "pw"}
+		}
+		for i := 0; i < maxDiscoverCacheEntries+10; i++ {
+			d.put("u1", server(i), cals, time.Minute+time.Duration(i)*time.Second, now)
+		}
+		if len(d.entries) != maxDiscoverCacheEntries {
+			t.Errorf("expected %d entries, got %d", maxDiscoverCacheEntries, len(d.entries))
+		}
+		if _, ok := d.get("u1", server(0), now); ok {
+			t.Error("expected the entry closest to expiry to be evicted")
+		}
+		if _, ok := d.get("u1", server(maxDiscoverCacheEntries+9), now); !ok {
+			t.Error("expected the newest entry to be kept")
+		}
+	})
+}
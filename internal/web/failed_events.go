@@ -0,0 +1,105 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/macjediwizard/calbridgesync/internal/auth"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+// APIFailedEvent is a dead-lettered event in API responses: one whose
+// destination write failed so many syncs in a row that syncs leave it
+// out until it is retried.
+type APIFailedEvent struct {
+	CalendarHref  string    `json:"calendar_href"`
+	EventUID      string    `json:"event_uid"`
+	FailureCount  int       `json:"failure_count"`
+	LastError     string    `json:"last_error"`
+	FirstFailedAt time.Time `json:"first_failed_at"`
+	LastFailedAt  time.Time `json:"last_failed_at"`
+}
+
+func failedEventToAPI(f *db.FailedEvent) *APIFailedEvent {
+	return &APIFailedEvent{
+		CalendarHref:  f.CalendarHref,
+		EventUID:      f.EventUID,
+		FailureCount:  f.FailureCount,
+		LastError:     f.LastError,
+		FirstFailedAt: f.FirstFailedAt,
+		LastFailedAt:  f.UpdatedAt,
+	}
+}
+
+// APIRetryFailedEventsRequest picks the dead-lettered event to retry;
+// an empty body retries all of the source's.
+type APIRetryFailedEventsRequest struct {
+	CalendarHref string `json:"calendar_href"`
+	EventUID     string `json:"event_uid"`
+}
+
+// APIGetFailedEvents lists a source's dead-lettered events, most
+// recently failed first.
+func (h *Handlers) APIGetFailedEvents(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+	sourceID := c.Param("id")
+	if _, err := h.db.GetSourceByIDForUser(sourceID, session.UserID); err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
+		return
+	}
+
+	failed, err := h.db.GetDeadLetteredEvents(sourceID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load failed events")
+		return
+	}
+	events := make([]*APIFailedEvent, len(failed))
+	for i, f := range failed {
+		events[i] = failedEventToAPI(f)
+	}
+	c.JSON(http.StatusOK, events)
+}
+
+// APIRetryFailedEvents releases a source's dead-lettered events, or
+// the one the body names, so its next sync tries them again.
+func (h *Handlers) APIRetryFailedEvents(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+	sourceID := c.Param("id")
+	if _, err := h.db.GetSourceByIDForUser(sourceID, session.UserID); err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
+		return
+	}
+
+	var req APIRetryFailedEventsRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid request body")
+		return
+	}
+	if (req.EventUID == "") != (req.CalendarHref == "") {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "calendar_href and event_uid must be given together")
+		return
+	}
+
+	released, err := h.db.RetryDeadLetteredEvents(sourceID, req.CalendarHref, req.EventUID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to retry failed events")
+		return
+	}
+	if req.EventUID != "" && released == 0 {
+		respondError(c, http.StatusNotFound, ErrCodeNotFound, "Failed event not found")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Failed events will be retried on the next sync", "retried": released})
+}
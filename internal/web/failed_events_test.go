@@ -0,0 +1,88 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAPIFailedEvents(t *testing.T) {
+	th := setupTestHandlers(t)
+	defer th.cleanup()
+
+	userID, source := createTestUserAndSource(t, th.db, "alice@example.com", "Work")
+	for i := 0; i < 3; i++ {
+		if _, err := th.db.RecordEventFailure(source.ID, "/cal/", "stuck@example.com", "403 Forbidden", 3); err != nil {
+			t.Fatalf("RecordEventFailure: %v", err)
+		}
+	}
+	// Still being retried, so not listed.
+	if _, err := th.db.RecordEventFailure(source.ID, "/cal/", "flaky@example.com", "timeout", 3); err != nil {
+		t.Fatalf("RecordEventFailure: %v", err)
+	}
+	otherID, _ := createTestUserAndSource(t, th.db, "bob@example.com", "Bob's")
+
+	list := func(userID, email string) (*httptest.ResponseRecorder, []APIFailedEvent) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/sources/"+source.ID+"/failed-events", nil)
+		c.Params = gin.Params{{Key: "id", Value: source.ID}}
+		setAuthContext(c, userID, email)
+		th.handlers.APIGetFailedEvents(c)
+		var events []APIFailedEvent
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &events); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+		}
+		return w, events
+	}
+
+	t.Run("lists dead-lettered events", func(t *testing.T) {
+		w, events := list(userID, "alice@example.com")
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if len(events) != 1 {
+			t.Fatalf("expected one dead-lettered event, got %+v", events)
+		}
+		e := events[0]
+		if e.EventUID != "stuck@example.com" || e.CalendarHref != "/cal/" || e.FailureCount != 3 || e.LastError != "403 Forbidden" {
+			t.Errorf("unexpected event %+v", e)
+		}
+	})
+
+	t.Run("other users get 404", func(t *testing.T) {
+		if w, _ := list(otherID, "bob@example.com"); w.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("retry releases the event", func(t *testing.T) {
+		retry := func(body string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/sources/"+source.ID+"/failed-events/retry", strings.NewReader(body))
+			c.Params = gin.Params{{Key: "id", Value: source.ID}}
+			setAuthContext(c, userID, "alice@example.com")
+			th.handlers.APIRetryFailedEvents(c)
+			return w
+		}
+		if w := retry(`{"event_uid":"stuck@example.com"}`); w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400 without calendar_href, got %d", w.Code)
+		}
+		if w := retry(`{"calendar_href":"/cal/","event_uid":"flaky@example.com"}`); w.Code != http.StatusNotFound {
+			t.Errorf("expected 404 for an event that isn't dead-lettered, got %d", w.Code)
+		}
+		if w := retry(`{"calendar_href":"/cal/","event_uid":"stuck@example.com"}`); w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if _, events := list(userID, "alice@example.com"); len(events) != 0 {
+			t.Errorf("expected no dead-lettered events after the retry, got %+v", events)
+		}
+	})
+}
@@ -27,6 +27,13 @@ type Handlers struct {
 	scheduler  *scheduler.Scheduler
 	health     *health.Checker
 	notifier   *notify.Notifier
+
+	// triggerKeys holds the Idempotency-Key values APITriggerSync has
+	// seen recently.
+	triggerKeys idempotencyKeys
+
+	// discovered holds recent APIDiscoverCalendars results.
+	discovered discoverCache
 }
 
 // NewHandlers creates a new Handlers instance.
@@ -158,12 +165,14 @@ func (h *Handlers) Callback(c *gin.Context) {
 		return
 	}
 
-	// Create session
+	// Create session. CSRFToken is left empty so Set issues a fresh
+	// one: every login rotates it.
 	sessionData := &auth.SessionData{
 		UserID:  user.ID,
 		Email:   user.Email,
 		Name:    user.Name,
 		Picture: claims.AvatarURL,
+		IsAdmin: h.isAdmin(user.Email, claims),
 	}
 	if err := h.session.Set(c.Writer, c.Request, sessionData); err != nil {
 		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
@@ -185,6 +194,21 @@ func (h *Handlers) Callback(c *gin.Context) {
 	c.Redirect(http.StatusFound, redirectURL)
 }
 
+// isAdmin reports whether the user signed in as email may perform
+// instance-wide operations: listed in AUTH_ADMIN_EMAILS or, for an
+// OIDC login, carrying the AUTH_ADMIN_CLAIM value in claims (nil for
+// API tokens). It is decided when the session is made and recorded
+// as SessionData.IsAdmin for auth.RequireAdmin.
+func (h *Handlers) isAdmin(email string, claims *auth.OIDCClaims) bool {
+	if h.cfg == nil {
+		return false
+	}
+	if h.cfg.Auth.IsAdmin(email) {
+		return true
+	}
+	return claims != nil && claims.HasClaimValue(h.cfg.Auth.AdminClaim, h.cfg.Auth.AdminClaimValue)
+}
+
 // Logout clears the session.
 func (h *Handlers) Logout(c *gin.Context) {
 	if err := h.session.Clear(c.Writer, c.Request); err != nil {
@@ -226,7 +250,7 @@ func (h *Handlers) ListSources(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	sources, err := h.db.GetSourcesByUserID(session.UserID)
 	if err != nil {
-		h.respondError(c, http.StatusInternalServerError, "Failed to load sources")
+		h.respondPageError(c, http.StatusInternalServerError, "Failed to load sources")
 		return
 	}
 
@@ -301,10 +325,18 @@ func (f *sourceFormData) hasDestCredentials() bool {
 // AddSource creates a new source.
 func (h *Handlers) AddSource(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
+	if !h.canCreateSources(session) {
+		h.respondPageError(c, http.StatusForbidden, sourceCreationForbidden)
+		return
+	}
 	form := h.parseSourceForm(c)
 
 	if !form.validate() {
-		h.respondError(c, http.StatusBadRequest, "Missing required fields")
+		h.respondPageError(c, http.StatusBadRequest, "Missing required fields")
+		return
+	}
+	if validationErr := h.validateDestHost(form.DestURL); validationErr != "" {
+		h.respondPageError(c, http.StatusBadRequest, validationErr)
 		return
 	}
 
@@ -327,14 +359,14 @@ func (h *Handlers) testAndCreateSource(c *gin.Context, userID string, form *sour
 
 	// Test source connection
 	if err := h.syncEngine.TestConnection(ctx, form.SourceURL, form.SourceUsername, form.SourcePassword); err != nil {
-		h.respondError(c, http.StatusBadRequest, "Failed to connect to source: "+err.Error())
+		h.respondPageError(c, http.StatusBadRequest, "Failed to connect to source: "+err.Error())
 		return err
 	}
 
 	// Test destination if provided
 	if form.hasDestCredentials() {
 		if err := h.syncEngine.TestConnection(ctx, form.DestURL, form.DestUsername, form.DestPassword); err != nil {
-			h.respondError(c, http.StatusBadRequest, "Failed to connect to destination: "+err.Error())
+			h.respondPageError(c, http.StatusBadRequest, "Failed to connect to destination: "+err.Error())
 			return err
 		}
 	}
@@ -342,13 +374,13 @@ func (h *Handlers) testAndCreateSource(c *gin.Context, userID string, form *sour
 	// Encrypt passwords
 	encSourcePwd, err := h.encryptor.Encrypt(form.SourcePassword)
 	if err != nil {
-		h.respondError(c, http.StatusInternalServerError, "Failed to encrypt credentials")
+		h.respondPageError(c, http.StatusInternalServerError, "Failed to encrypt credentials")
 		return err
 	}
 
 	encDestPwd, err := h.encryptor.Encrypt(form.DestPassword)
 	if err != nil {
-		h.respondError(c, http.StatusInternalServerError, "Failed to encrypt credentials")
+		h.respondPageError(c, http.StatusInternalServerError, "Failed to encrypt credentials")
 		return err
 	}
 
@@ -365,10 +397,11 @@ func (h *Handlers) testAndCreateSource(c *gin.Context, userID string, form *sour
 		SyncInterval:     form.SyncInterval,
 		ConflictStrategy: form.ConflictStrategy,
 		Enabled:          true,
+		SyncAttachments:  true,
 	}
 
 	if err := h.db.CreateSource(source); err != nil {
-		h.respondError(c, http.StatusInternalServerError, "Failed to create source")
+		h.respondPageError(c, http.StatusInternalServerError, "Failed to create source")
 		return err
 	}
 
@@ -413,7 +446,7 @@ func (h *Handlers) UpdateSource(c *gin.Context) {
 	// rationale. (#109)
 	source, err := h.db.GetSourceByIDForUser(sourceID, session.UserID)
 	if err != nil {
-		h.respondError(c, http.StatusNotFound, "Source not found")
+		h.respondPageError(c, http.StatusNotFound, "Source not found")
 		return
 	}
 
@@ -422,7 +455,13 @@ func (h *Handlers) UpdateSource(c *gin.Context) {
 	source.SourceType = db.SourceType(c.PostForm("source_type"))
 	source.SourceURL = c.PostForm("source_url")
 	source.SourceUsername = c.PostForm("source_username")
-	source.DestURL = c.PostForm("dest_url")
+	if destURL := c.PostForm("dest_url"); destURL != source.DestURL {
+		if validationErr := h.validateDestHost(destURL); validationErr != "" {
+			h.respondPageError(c, http.StatusBadRequest, validationErr)
+			return
+		}
+		source.DestURL = destURL
+	}
 	source.DestUsername = c.PostForm("dest_username")
 	source.ConflictStrategy = db.ConflictStrategy(c.PostForm("conflict_strategy"))
 
@@ -435,7 +474,7 @@ func (h *Handlers) UpdateSource(c *gin.Context) {
 	if newSourcePassword := c.PostForm("source_password"); newSourcePassword != "" {
 		encPassword, err := h.encryptor.Encrypt(newSourcePassword)
 		if err != nil {
-			h.respondError(c, http.StatusInternalServerError, "Failed to encrypt credentials")
+			h.respondPageError(c, http.StatusInternalServerError, "Failed to encrypt credentials")
 			return
 		}
 		source.SourcePassword = encPassword
@@ -444,17 +483,21 @@ func (h *Handlers) UpdateSource(c *gin.Context) {
 	if newDestPassword := c.PostForm("dest_password"); newDestPassword != "" {
 		encPassword, err := h.encryptor.Encrypt(newDestPassword)
 		if err != nil {
-			h.respondError(c, http.StatusInternalServerError, "Failed to encrypt credentials")
+			h.respondPageError(c, http.StatusInternalServerError, "Failed to encrypt credentials")
 			return
 		}
 		source.DestPassword = encPassword
 	}
 
 	if err := h.db.UpdateSource(source); err != nil {
-		h.respondError(c, http.StatusInternalServerError, "Failed to update source")
+		h.respondPageError(c, http.StatusInternalServerError, "Failed to update source")
 		return
 	}
 
+	if h.syncEngine != nil {
+		h.syncEngine.ResetAuthCircuit(source.ID)
+	}
+
 	// Update scheduler
 	h.scheduler.UpdateJobInterval(source.ID, time.Duration(source.SyncInterval)*time.Second)
 
@@ -475,7 +518,7 @@ func (h *Handlers) DeleteSource(c *gin.Context) {
 	// Scoped lookup to avoid the 403/404 oracle. (#109)
 	_, err := h.db.GetSourceByIDForUser(sourceID, session.UserID)
 	if err != nil {
-		h.respondError(c, http.StatusNotFound, "Source not found")
+		h.respondPageError(c, http.StatusNotFound, "Source not found")
 		return
 	}
 
@@ -483,7 +526,7 @@ func (h *Handlers) DeleteSource(c *gin.Context) {
 	h.scheduler.RemoveJob(sourceID)
 
 	if err := h.db.DeleteSource(sourceID); err != nil {
-		h.respondError(c, http.StatusInternalServerError, "Failed to delete source")
+		h.respondPageError(c, http.StatusInternalServerError, "Failed to delete source")
 		return
 	}
 
@@ -504,7 +547,7 @@ func (h *Handlers) TriggerSync(c *gin.Context) {
 	// Scoped lookup to avoid the 403/404 oracle. (#109)
 	_, err := h.db.GetSourceByIDForUser(sourceID, session.UserID)
 	if err != nil {
-		h.respondError(c, http.StatusNotFound, "Source not found")
+		h.respondPageError(c, http.StatusNotFound, "Source not found")
 		return
 	}
 
@@ -529,14 +572,14 @@ func (h *Handlers) ToggleSource(c *gin.Context) {
 	// Scoped lookup to avoid the 403/404 oracle. (#109)
 	source, err := h.db.GetSourceByIDForUser(sourceID, session.UserID)
 	if err != nil {
-		h.respondError(c, http.StatusNotFound, "Source not found")
+		h.respondPageError(c, http.StatusNotFound, "Source not found")
 		return
 	}
 
 	// Toggle enabled status
 	source.Enabled = !source.Enabled
 	if err := h.db.UpdateSource(source); err != nil {
-		h.respondError(c, http.StatusInternalServerError, "Failed to update source")
+		h.respondPageError(c, http.StatusInternalServerError, "Failed to update source")
 		return
 	}
 
@@ -586,15 +629,16 @@ func (h *Handlers) ViewLogs(c *gin.Context) {
 	})
 }
 
-// respondError sends an error response appropriate for the request type.
-func (h *Handlers) respondError(c *gin.Context, status int, message string) {
+// respondPageError sends an error response appropriate for the
+// request type: the error partial for HTMX, an APIError otherwise.
+func (h *Handlers) respondPageError(c *gin.Context, status int, message string) {
 	if isHTMX(c) {
 		c.HTML(status, "partials/error.html", gin.H{
 			"error": message,
 		})
 		return
 	}
-	c.JSON(status, gin.H{"error": message})
+	respondError(c, status, errorCodeForStatus(status), message)
 }
 
 // isHTMX returns true if the request is an HTMX request.
@@ -189,7 +189,7 @@ func TestSourceFormDataStruct(t *testing.T) {
 	})
 }
 
-func TestRespondError(t *testing.T) {
+func TestRespondPageError(t *testing.T) {
 	handlers := &Handlers{}
 
 	t.Run("responds with JSON for non-HTMX request", func(t *testing.T) {
@@ -197,14 +197,14 @@ func TestRespondError(t *testing.T) {
 		c, _ := gin.CreateTestContext(w)
 		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
 
-		handlers.respondError(c, http.StatusBadRequest, "Test error")
+		handlers.respondPageError(c, http.StatusBadRequest, "Test error")
 
 		if w.Code != http.StatusBadRequest {
 			t.Errorf("expected status 400, got %d", w.Code)
 		}
 
 		body := w.Body.String()
-		if body != `{"error":"Test error"}` {
+		if body != `{"code":"VALIDATION_ERROR","message":"Test error","error":"Test error"}` {
 			t.Errorf("expected JSON error response, got %q", body)
 		}
 	})
@@ -0,0 +1,44 @@
+package web
+
+import (
+	"sync"
+	"time"
+)
+
+// idempotencyKeyTTL is how long a trigger-sync Idempotency-Key is
+// remembered. Long enough to cover a double-click or a client's
+// retries, short enough that reusing a key later still syncs.
+const idempotencyKeyTTL = 5 * time.Minute
+
+// maxIdempotencyKeyLen caps the header value kept in memory; longer
+// keys are ignored rather than stored.
+const maxIdempotencyKeyLen = 255
+
+// idempotencyKeys remembers recently seen Idempotency-Key values. The
+// zero value is ready to use.
+type idempotencyKeys struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// claim records key as seen at now and reports whether it is new, that
+// is, not seen within idempotencyKeyTTL. Expired keys are swept on
+// every call, which keeps the map at roughly one TTL's worth of keys.
+func (k *idempotencyKeys) claim(key string, now time.Time) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for seenKey, at := range k.seen {
+		if now.Sub(at) >= idempotencyKeyTTL {
+			delete(k.seen, seenKey)
+		}
+	}
+	if _, dup := k.seen[key]; dup {
+		return false
+	}
+	if k.seen == nil {
+		k.seen = make(map[string]time.Time)
+	}
+	k.seen[key] = now
+	return true
+}
@@ -0,0 +1,24 @@
+package web
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIdempotencyKeysClaim(t *testing.T) {
+	var keys idempotencyKeys
+	now := time.Now()
+
+	if !keys.claim("a", now) {
+		t.Fatal("expected the first use of a key to be new")
+	}
+	if keys.claim("a", now.Add(time.Second)) {
+		t.Error("expected a repeat within the TTL to be refused")
+	}
+	if !keys.claim("b", now.Add(time.Second)) {
+		t.Error("expected a different key to be new")
+	}
+	if !keys.claim("a", now.Add(idempotencyKeyTTL)) {
+		t.Error("expected the key to be new again once its TTL passed")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/macjediwizard/calbridgesync/internal/auth"
 	"golang.org/x/time/rate"
 )
 
@@ -186,9 +187,7 @@ func RateLimiter(rps float64, burst int) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		limiter := limiters.getLimiter(c.ClientIP())
 		if !limiter.Allow() {
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error": "rate limit exceeded",
-			})
+			auth.AbortWithError(c, http.StatusTooManyRequests, ErrCodeRateLimited, "rate limit exceeded")
 			return
 		}
 		c.Next()
@@ -221,9 +220,7 @@ func RequireJSONContentType() gin.HandlerFunc {
 			contentType := c.GetHeader("Content-Type")
 			// Allow empty content type for requests without body, or require JSON
 			if contentType != "" && !strings.HasPrefix(contentType, "application/json") {
-				c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{
-					"error": "Content-Type must be application/json",
-				})
+				auth.AbortWithError(c, http.StatusUnsupportedMediaType, ErrCodeInvalidRequest, "Content-Type must be application/json")
 				return
 			}
 		}
@@ -241,6 +238,13 @@ func ValidateOrigin() gin.HandlerFunc {
 			return
 		}
 
+		// Bearer tokens are never attached by the browser on its own,
+		// so token-authenticated requests can't be cross-site forged.
+		if auth.IsTokenAuth(c) {
+			c.Next()
+			return
+		}
+
 		origin := c.GetHeader("Origin")
 		referer := c.GetHeader("Referer")
 
@@ -259,9 +263,7 @@ func ValidateOrigin() gin.HandlerFunc {
 
 		// If still no origin, reject the request (browser should send one)
 		if origin == "" {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
-				"error": "Missing Origin header",
-			})
+			auth.AbortWithError(c, http.StatusForbidden, ErrCodeForbidden, "Missing Origin header")
 			return
 		}
 
@@ -279,9 +281,7 @@ func ValidateOrigin() gin.HandlerFunc {
 
 		if !originValid {
 			log.Printf("CSRF: rejected request from origin %s", origin)
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
-				"error": "Invalid origin",
-			})
+			auth.AbortWithError(c, http.StatusForbidden, ErrCodeForbidden, "Invalid origin")
 			return
 		}
 
@@ -125,47 +125,43 @@ type APIPrepareGoogleSourceResponse struct {
 func (h *Handlers) APIPrepareGoogleSource(c *gin.Context) {
 	session := auth.GetCurrentUser(c)
 	if session == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+	if !h.canCreateSources(session) {
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, sourceCreationForbidden)
 		return
 	}
 
 	if !h.cfg.GoogleOAuth.Enabled() {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"error": "Google OAuth is not configured on this server (BASE_URL / GOOGLE_OAUTH_REDIRECT_URL must be set).",
-		})
+		respondError(c, http.StatusServiceUnavailable, ErrCodeUnavailable, "Google OAuth is not configured on this server (BASE_URL / GOOGLE_OAUTH_REDIRECT_URL must be set).")
 		return
 	}
 
 	var req APIPrepareGoogleSourceRequest
 	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	// Validate required destination fields. Source fields are filled
 	// in from the OAuth response, not from the form.
 	if req.Name == "" || req.DestURL == "" || req.DestUsername == "" || req.DestPassword == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Name and destination URL/username/password are required",
-		})
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "Name and destination URL/username/password are required")
 		return
 	}
 
 	// Per-source Google OAuth credentials are required as of #79.
 	// Each user provides their own Google Cloud project credentials.
 	if req.GoogleClientID == "" || req.GoogleClientSecret == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Google OAuth client ID and client secret are required (from your Google Cloud project)",
-		})
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "Google OAuth client ID and client secret are required (from your Google Cloud project)")
 		return
 	}
 
 	// Build the per-request oauth2.Config from the form credentials.
 	cfg := h.buildGoogleOAuthConfig(req.GoogleClientID, req.GoogleClientSecret)
 	if cfg == nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid Google OAuth credentials in request",
-		})
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "Invalid Google OAuth credentials in request")
 		return
 	}
 
@@ -175,12 +171,16 @@ func (h *Handlers) APIPrepareGoogleSource(c *gin.Context) {
 		req.Name, string(db.SourceTypeGoogle), req.SyncDirection, req.ConflictStrategy,
 		"", req.DestURL, "", req.DestUsername,
 	); validationErr != "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr})
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
 		return
 	}
 
 	if len(req.DestPassword) > maxPasswordLength {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Destination password is too long"})
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "Destination password is too long")
+		return
+	}
+	if validationErr := h.validateDestHost(req.DestURL); validationErr != "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, validationErr)
 		return
 	}
 
@@ -189,15 +189,13 @@ func (h *Handlers) APIPrepareGoogleSource(c *gin.Context) {
 	// after they've clicked through consent.
 	if err := h.syncEngine.TestConnection(c.Request.Context(), req.DestURL, req.DestUsername, req.DestPassword); err != nil {
 		log.Printf("Google source prepare: destination connection test failed for %s: %v", req.DestURL, err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Failed to connect to destination: " + categorizeConnectionError(err),
-		})
+		respondError(c, http.StatusBadRequest, ErrCodeConnectionFailed, "Failed to connect to destination: "+categorizeConnectionError(err))
 		return
 	}
 
 	encDestPwd, err := h.encryptor.Encrypt(req.DestPassword)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt credentials"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to encrypt credentials")
 		return
 	}
 
@@ -207,13 +205,13 @@ func (h *Handlers) APIPrepareGoogleSource(c *gin.Context) {
 	// stolen cookie does not expose the plaintext secret. (#79)
 	encGoogleClientSecret, err := h.encryptor.Encrypt(req.GoogleClientSecret)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt Google client secret"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to encrypt Google client secret")
 		return
 	}
 
 	state, err := auth.GenerateState()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate state"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to generate state")
 		return
 	}
 
@@ -221,7 +219,7 @@ func (h *Handlers) APIPrepareGoogleSource(c *gin.Context) {
 	// separate so a stale pending-source cookie from a previous flow
 	// can't interfere with the OAuth state CSRF check.
 	if err := h.session.SetOAuthState(c.Writer, c.Request, googleOAuthStatePrefix+state); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save OAuth state"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to save OAuth state")
 		return
 	}
 
@@ -240,7 +238,7 @@ func (h *Handlers) APIPrepareGoogleSource(c *gin.Context) {
 		StripAlarms:           req.StripAlarms,
 	}
 	if err := h.session.SetPendingGoogleSource(c.Writer, c.Request, pending); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save pending source"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to save pending source")
 		return
 	}
 
@@ -465,6 +463,7 @@ func (h *Handlers) GoogleOAuthCallback(c *gin.Context) {
 		ConflictStrategy:   conflictStrategy,
 		Enabled:            true,
 		StripAlarms:        pending.StripAlarms,
+		SyncAttachments:    true,
 	}
 
 	if err := h.db.CreateSource(source); err != nil {
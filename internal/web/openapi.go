@@ -0,0 +1,465 @@
+package web
+
+import (
+	"encoding/json"
+	"go/token"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/macjediwizard/calbridgesync/internal/activity"
+	"github.com/macjediwizard/calbridgesync/internal/caldav"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+	"github.com/macjediwizard/calbridgesync/internal/scheduler"
+	"github.com/macjediwizard/calbridgesync/internal/version"
+)
+
+// apiOperation documents one /api route for the OpenAPI spec.
+// Request and Response are zero values of the body types; their
+// schemas are reflected from the structs' json tags, so renaming a
+// field in an API* type updates the spec automatically. Handler must
+// be the name of the method SetupRoutes registers for the route —
+// TestOpenAPISpecCoversRoutes holds the table to that.
+type apiOperation struct {
+	Method  string
+	Path    string // gin syntax, relative to /api
+	Handler string
+	Summary string
+	Public  bool // no session or API token required
+	Query   []apiQueryParam
+	Header  []apiQueryParam
+
+	Request  any
+	Response any
+	// Alternate is another body the success status may carry.
+	Alternate any
+	// Status defaults to 200. ContentType defaults to application/json.
+	Status      int
+	ContentType string
+}
+
+type apiQueryParam struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// messageResponse is the {"message": ...} body most mutating
+// endpoints return on success.
+type messageResponse struct {
+	Message string `json:"message"`
+}
+
+var pageParam = apiQueryParam{Name: "page", Type: "integer", Description: "1-based page number"}
+
+// apiOperations is the route table the spec is built from. Keep it in
+// the same order as SetupRoutes.
+var apiOperations = []apiOperation{
+	{Method: http.MethodGet, Path: "/auth/status", Handler: "APIAuthStatus", Summary: "Report whether the caller is logged in", Public: true,
+		Response: APIAuthStatus{}},
+	{Method: http.MethodGet, Path: "/version", Handler: "APIGetVersion", Summary: "Get the server version", Public: true,
+		Response: struct {
+			Version string `json:"version"`
+		}{}},
+	{Method: http.MethodPost, Path: "/auth/logout", Handler: "APILogout", Summary: "End the current session", Public: true,
+		Response: messageResponse{}},
+	{Method: http.MethodGet, Path: "/openapi.json", Handler: "APIOpenAPISpec", Summary: "Get this OpenAPI document", Public: true,
+		Response: map[string]any{}},
+
+	{Method: http.MethodGet, Path: "/dashboard/stats", Handler: "APIDashboardStats", Summary: "Get dashboard counters",
+		Response: APIDashboardStats{}},
+	{Method: http.MethodGet, Path: "/dashboard/overview", Handler: "APIDashboardOverview",
+		Summary: "Get aggregate stats across all your sources", Response: db.SyncOverview{}},
+	{Method: http.MethodGet, Path: "/dashboard/sync-history", Handler: "APISyncHistory", Summary: "Get daily sync history for charts",
+		Query:    []apiQueryParam{{Name: "days", Type: "integer", Description: "Number of days to include"}},
+		Response: APISyncHistory{}},
+	{Method: http.MethodGet, Path: "/sources", Handler: "APIListSources", Summary: "List sources",
+		Query: []apiQueryParam{
+			{Name: "tag", Type: "string", Description: "Only sources with this tag; repeat to match any of several"},
+		},
+		Response: []APISource{}},
+	{Method: http.MethodGet, Path: "/sources/:id", Handler: "APIGetSource", Summary: "Get a source",
+		Response: APISource{}},
+	{Method: http.MethodPut, Path: "/sources/:id", Handler: "APIUpdateSource", Summary: "Update a source",
+		Request: APIUpdateSourceRequest{}, Response: APISource{}},
+	{Method: http.MethodDelete, Path: "/sources/:id", Handler: "APIDeleteSource", Summary: "Delete a source",
+		Response: messageResponse{}},
+	{Method: http.MethodPost, Path: "/sources/:id/toggle", Handler: "APIToggleSource", Summary: "Enable or disable a source",
+		Response: APISource{}},
+	{Method: http.MethodPost, Path: "/sources/:id/sync", Handler: "APITriggerSync",
+		Summary: "Trigger a sync; with dry_run=true or calendar=<path>, run it synchronously and return the result",
+		Query: []apiQueryParam{
+			{Name: "dry_run", Type: "boolean", Description: "Preview the sync instead of queueing it"},
+			{Name: "calendar", Type: "string", Description: "Sync only this calendar path, which must be one the source syncs; refused with 409 while the source is syncing"},
+		},
+		Header: []apiQueryParam{
+			{Name: "Idempotency-Key", Type: "string", Description: "Repeats with the same key within 5 minutes don't queue the sync again"},
+		},
+		Response: messageResponse{}, Alternate: caldav.SyncResult{}},
+	{Method: http.MethodPost, Path: "/sources/:id/cancel", Handler: "APICancelSync",
+		Summary: "Stop the sync running for a source; a no-op when none is", Response: messageResponse{}},
+	{Method: http.MethodPost, Path: "/sources/:id/undo-last-sync", Handler: "APIUndoLastSync",
+		Summary: "Restore the destination events deleted by the source's last sync", Response: caldav.UndoResult{}},
+	{Method: http.MethodGet, Path: "/sources/:id/logs", Handler: "APIGetSourceLogs", Summary: "List a source's sync logs",
+		Query: []apiQueryParam{pageParam},
+		Response: struct {
+			Logs       []APISyncLog `json:"logs"`
+			Page       int          `json:"page"`
+			TotalPages int          `json:"total_pages"`
+		}{}},
+	{Method: http.MethodDelete, Path: "/sources/:id/logs", Handler: "APIDeleteSourceLogs", Summary: "Delete all of a source's sync logs",
+		Response: struct {
+			Message string `json:"message"`
+			Deleted int64  `json:"deleted"`
+		}{}},
+	{Method: http.MethodGet, Path: "/sources/:id/logs/:log_id", Handler: "APIGetSourceLog",
+		Summary: "Get one sync log with the conflicts and malformed events of its run", Response: APISyncLogDetail{}},
+	{Method: http.MethodGet, Path: "/sources/:id/stats", Handler: "APIGetSourceStats", Summary: "Get a source's health statistics",
+		Response: db.SourceStats{}},
+	{Method: http.MethodGet, Path: "/sources/:id/failed-events", Handler: "APIGetFailedEvents",
+		Summary: "List events left out of syncs after failing repeatedly", Response: []APIFailedEvent{}},
+	{Method: http.MethodPost, Path: "/sources/:id/failed-events/retry", Handler: "APIRetryFailedEvents",
+		Summary: "Retry dead-lettered events on the next sync", Request: APIRetryFailedEventsRequest{},
+		Response: struct {
+			Message string `json:"message"`
+			Retried int64  `json:"retried"`
+		}{}},
+	{Method: http.MethodDelete, Path: "/sources/:id/stamped-events", Handler: "APIDeleteStampedEvents",
+		Summary:  "Delete the destination events carrying a source stamp",
+		Query:    []apiQueryParam{{Name: "stamp", Type: "string", Description: "Stamp to clean up; defaults to the source's own"}},
+		Response: caldav.StampCleanupResult{}},
+	{Method: http.MethodPost, Path: "/sources/:id/purge-destination", Handler: "APIPurgeDestination",
+		Summary: "Delete every event the source synced to its destination", Request: APIPurgeDestinationRequest{},
+		Response: caldav.PurgeResult{}},
+	{Method: http.MethodGet, Path: "/malformed-events", Handler: "APIGetMalformedEvents", Summary: "List malformed events",
+		Response: []APIMalformedEvent{}},
+	{Method: http.MethodGet, Path: "/malformed-events/summary", Handler: "APIGetMalformedEventSummary",
+		Summary: "Count malformed events by category", Response: APIMalformedEventSummary{}},
+	{Method: http.MethodDelete, Path: "/malformed-events", Handler: "APIDeleteAllMalformedEvents", Summary: "Clear all malformed event records",
+		Response: struct {
+			Message string `json:"message"`
+			Deleted int64  `json:"deleted"`
+		}{}},
+	{Method: http.MethodDelete, Path: "/malformed-events/:id", Handler: "APIDeleteMalformedEvent",
+		Summary:  "Delete a malformed event from its source calendar and clear its record",
+		Response: messageResponse{}},
+	{Method: http.MethodGet, Path: "/search", Handler: "APISearch",
+		Summary: "Search your sync log messages and malformed event paths and errors",
+		Query: []apiQueryParam{
+			{Name: "q", Type: "string", Description: "Text to look for, 2 to 200 characters"},
+		},
+		Response: APISearchResult{}},
+	{Method: http.MethodGet, Path: "/settings/alerts", Handler: "APIGetAlertPreferences", Summary: "Get alert preferences",
+		Response: APIAlertPreferences{}},
+	{Method: http.MethodPut, Path: "/settings/alerts", Handler: "APIUpdateAlertPreferences", Summary: "Update alert preferences",
+		Request: APIAlertPreferences{}, Response: APIAlertPreferences{}},
+	{Method: http.MethodGet, Path: "/settings/log-stats", Handler: "APIGetLogStats", Summary: "Get sync log statistics",
+		Response: struct {
+			TotalLogs     int64  `json:"total_logs"`
+			OldestLog     string `json:"oldest_log"`
+			RetentionDays int    `json:"retention_days"`
+		}{}},
+	{Method: http.MethodGet, Path: "/audit-logs", Handler: "APIGetAuditLogs", Summary: "List audit log entries",
+		Query: []apiQueryParam{pageParam},
+		Response: struct {
+			Logs       []db.AuditLog `json:"logs"`
+			TotalPages int           `json:"total_pages"`
+			Page       int           `json:"page"`
+		}{}},
+	{Method: http.MethodGet, Path: "/sources/:id/destinations", Handler: "APIListDestinations", Summary: "List a source's additional destinations",
+		Response: []db.Destination{}},
+	{Method: http.MethodPost, Path: "/sources/:id/destinations", Handler: "APICreateDestination", Summary: "Add a destination to a source",
+		Request: struct {
+			Name         string `json:"name"`
+			DestURL      string `json:"dest_url"`
+			DestUsername string `json:"dest_username"`
+			DestPassword string `json:"dest_password"`
+		}{},
+		Response: db.Destination{}},
+	{Method: http.MethodDelete, Path: "/sources/:id/destinations/:destId", Handler: "APIDeleteDestination", Summary: "Remove a destination",
+		Response: messageResponse{}},
+	{Method: http.MethodGet, Path: "/sources/:id/shares", Handler: "APIListShareLinks", Summary: "List a source's active share links",
+		Response: []APIShareLink{}},
+	{Method: http.MethodPost, Path: "/sources/:id/shares", Handler: "APICreateShareLink", Summary: "Create a read-only share link",
+		Request: struct {
+			ExpiresInHours int `json:"expires_in_hours,omitempty"`
+		}{},
+		Response: APIShareLink{}, Status: http.StatusCreated},
+	{Method: http.MethodDelete, Path: "/sources/:id/shares/:shareId", Handler: "APIRevokeShareLink", Summary: "Revoke a share link",
+		Response: messageResponse{}},
+	{Method: http.MethodGet, Path: "/activity", Handler: "APIGetActivity", Summary: "Get running and recent syncs",
+		Response: struct {
+			Active []activity.SyncActivity `json:"active"`
+			Recent []activity.SyncActivity `json:"recent"`
+		}{}},
+	{Method: http.MethodGet, Path: "/sources/:id/activity", Handler: "APISourceActivity",
+		Summary: "Get a source's running sync progress, or an idle state", Response: activity.Snapshot{}},
+	{Method: http.MethodGet, Path: "/sources/:id/activity/stream", Handler: "APISourceActivityStream",
+		Summary:     "Stream a source's sync progress as Server-Sent Events (progress events, then one done event)",
+		ContentType: "text/event-stream"},
+	{Method: http.MethodGet, Path: "/health/detailed", Handler: "APIHealthDetailed",
+		Summary:  "Get the scheduler's health snapshot for your sources: status, staleness, in-flight syncs and 24h error rates",
+		Response: scheduler.HealthSnapshot{}},
+	{Method: http.MethodGet, Path: "/tokens", Handler: "APIListAPITokens", Summary: "List API tokens",
+		Response: []db.APIToken{}},
+	{Method: http.MethodPost, Path: "/tokens", Handler: "APICreateAPIToken",
+		Summary: "Create an API token; the plaintext is only returned here. Requires a browser session",
+		Request: struct {
+			Name string `json:"name"`
+		}{},
+		Response: struct {
+			ID        string    `json:"id"`
+			Name      string    `json:"name"`
+			CreatedAt time.Time `json:"created_at"`
+			Token     string    `json:"token"`
+		}{},
+		Status: http.StatusCreated},
+	{Method: http.MethodDelete, Path: "/tokens/:id", Handler: "APIRevokeAPIToken", Summary: "Revoke an API token",
+		Response: messageResponse{}},
+	{Method: http.MethodPost, Path: "/tools/validate-ics", Handler: "APIValidateICS",
+		Summary: "Check that an iCalendar payload parses and encodes cleanly, without syncing it",
+		Request: APIValidateICSRequest{}, Response: caldav.ICSCheckResult{}},
+
+	{Method: http.MethodPost, Path: "/sources", Handler: "APICreateSource", Summary: "Create a source after testing both connections",
+		Request: APICreateSourceRequest{}, Response: APISource{}, Status: http.StatusCreated},
+	{Method: http.MethodPost, Path: "/sources/google/prepare", Handler: "APIPrepareGoogleSource",
+		Summary: "Stash a pending Google source and return the consent URL",
+		Request: APIPrepareGoogleSourceRequest{}, Response: APIPrepareGoogleSourceResponse{}},
+	{Method: http.MethodPost, Path: "/sources/sync-all", Handler: "APISyncAllSources",
+		Summary: "Queue a sync for every enabled source you own", Response: APISyncAllResponse{}},
+	{Method: http.MethodPost, Path: "/calendars/discover", Handler: "APIDiscoverCalendars", Summary: "Discover calendars on a CalDAV server",
+		Request: APIDiscoverCalendarsRequest{}, Response: []APICalendar{}},
+	{Method: http.MethodPost, Path: "/settings/alerts/test-webhook", Handler: "APITestWebhook", Summary: "Send a test webhook",
+		Request: APITestWebhookRequest{}, Response: messageResponse{}},
+	{Method: http.MethodPost, Path: "/admin/alerts/reload", Handler: "APIReloadAlertConfig",
+		Summary: "Reload the ALERT_* settings without a restart (admins only)", Response: APIAlertReloadResponse{}},
+	{Method: http.MethodGet, Path: "/export/calendars", Handler: "APIExportCalendars", Summary: "Export all synced events as one iCalendar file",
+		ContentType: "text/calendar"},
+}
+
+var (
+	openAPISpecOnce sync.Once
+	openAPISpecJSON []byte
+)
+
+// APIOpenAPISpec serves the OpenAPI 3 document describing /api.
+func (h *Handlers) APIOpenAPISpec(c *gin.Context) {
+	openAPISpecOnce.Do(func() {
+		// The spec is built from static tables; a marshal error would be
+		// a programming error caught by the tests.
+		openAPISpecJSON, _ = json.Marshal(buildOpenAPISpec())
+	})
+	c.Data(http.StatusOK, "application/json; charset=utf-8", openAPISpecJSON)
+}
+
+// buildOpenAPISpec assembles the OpenAPI 3.0 document from
+// apiOperations.
+func buildOpenAPISpec() map[string]any {
+	schemas := map[string]any{}
+	paths := map[string]map[string]any{}
+
+	errorResponse := map[string]any{
+		"description": "Error",
+		"content": map[string]any{"application/json": map[string]any{
+			"schema": openAPISchema(reflect.TypeOf(APIError{}), schemas),
+		}},
+	}
+
+	for _, op := range apiOperations {
+		path, pathParams := openAPIPath(op.Path)
+		params := make([]any, 0, len(pathParams)+len(op.Query)+len(op.Header))
+		for _, name := range pathParams {
+			params = append(params, map[string]any{
+				"name": name, "in": "path", "required": true,
+				"schema": map[string]any{"type": "string"},
+			})
+		}
+		for _, q := range op.Query {
+			params = append(params, map[string]any{
+				"name": q.Name, "in": "query", "description": q.Description,
+				"schema": map[string]any{"type": q.Type},
+			})
+		}
+		for _, hdr := range op.Header {
+			params = append(params, map[string]any{
+				"name": hdr.Name, "in": "header", "description": hdr.Description,
+				"schema": map[string]any{"type": hdr.Type},
+			})
+		}
+
+		status := op.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := map[string]any{"description": http.StatusText(status)}
+		switch {
+		case op.ContentType != "":
+			success["content"] = map[string]any{op.ContentType: map[string]any{
+				"schema": map[string]any{"type": "string"},
+			}}
+		case op.Response != nil:
+			schema := openAPISchema(reflect.TypeOf(op.Response), schemas)
+			if op.Alternate != nil {
+				schema = map[string]any{"oneOf": []any{schema, openAPISchema(reflect.TypeOf(op.Alternate), schemas)}}
+			}
+			success["content"] = map[string]any{"application/json": map[string]any{"schema": schema}}
+		}
+
+		operation := map[string]any{
+			"operationId": op.Handler,
+			"summary":     op.Summary,
+			"responses": map[string]any{
+				strconv.Itoa(status): success,
+				"default":            errorResponse,
+			},
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+		if op.Request != nil {
+			operation["requestBody"] = map[string]any{
+				"content": map[string]any{"application/json": map[string]any{
+					"schema": openAPISchema(reflect.TypeOf(op.Request), schemas),
+				}},
+			}
+		}
+		if op.Public {
+			operation["security"] = []any{}
+		}
+
+		if paths[path] == nil {
+			paths[path] = map[string]any{}
+		}
+		paths[path][strings.ToLower(op.Method)] = operation
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "CalBridgeSync API",
+			"version": version.Version,
+		},
+		"servers": []any{map[string]any{"url": "/api"}},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"session": map[string]any{"type": "apiKey", "in": "cookie", "name": "calbridgesync_session"},
+				"token":   map[string]any{"type": "http", "scheme": "bearer"},
+			},
+		},
+		"security": []any{
+			map[string]any{"session": []any{}},
+			map[string]any{"token": []any{}},
+		},
+	}
+}
+
+// openAPIPath converts a gin route ("/sources/:id") to OpenAPI syntax
+// ("/sources/{id}") and returns the parameter names.
+func openAPIPath(ginPath string) (string, []string) {
+	segments := strings.Split(ginPath, "/")
+	var params []string
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") {
+			params = append(params, s[1:])
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+var (
+	timeType         = reflect.TypeOf(time.Time{})
+	optionalBoolType = reflect.TypeOf(optionalBool{})
+)
+
+// openAPISchema returns the schema for t. Exported named structs are
+// registered in schemas and referenced by name; anonymous and
+// unexported ones are inlined.
+func openAPISchema(t reflect.Type, schemas map[string]any) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	if t == optionalBoolType {
+		return map[string]any{"type": "boolean", "nullable": true}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": openAPISchema(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": openAPISchema(t.Elem(), schemas)}
+	case reflect.Struct:
+		name := t.Name()
+		if name == "" || !token.IsExported(name) {
+			return openAPIStructSchema(t, schemas)
+		}
+		if _, ok := schemas[name]; !ok {
+			schemas[name] = nil // placeholder so recursive types terminate
+			schemas[name] = openAPIStructSchema(t, schemas)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	default:
+		return map[string]any{}
+	}
+}
+
+// openAPIStructSchema describes a struct's JSON encoding: fields
+// without omitempty or omitzero are required, pointer fields are
+// nullable.
+func openAPIStructSchema(t reflect.Type, schemas map[string]any) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+		schema := openAPISchema(f.Type, schemas)
+		if f.Type.Kind() == reflect.Pointer {
+			if _, isRef := schema["$ref"]; isRef {
+				schema = map[string]any{"allOf": []any{schema}, "nullable": true}
+			} else {
+				schema["nullable"] = true
+			}
+		}
+		properties[name] = schema
+		if o := "," + opts + ","; !strings.Contains(o, ",omitempty,") && !strings.Contains(o, ",omitzero,") {
+			required = append(required, name)
+		}
+	}
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
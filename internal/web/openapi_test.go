@@ -0,0 +1,141 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/macjediwizard/calbridgesync/internal/auth"
+)
+
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	r := gin.New()
+	sm := auth.NewSessionManager("test-secret-key-at-least-32-chars", false, 86400, 300)
+	SetupRoutes(r, &Handlers{}, sm)
+
+	documented := make(map[string]apiOperation, len(apiOperations))
+	for _, op := range apiOperations {
+		key := op.Method + " /api" + op.Path
+		if _, dup := documented[key]; dup {
+			t.Errorf("%s is documented twice", key)
+		}
+		documented[key] = op
+	}
+
+	registered := make(map[string]bool)
+	for _, route := range r.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/") {
+			continue
+		}
+		key := route.Method + " " + route.Path
+		registered[key] = true
+		op, ok := documented[key]
+		if !ok {
+			t.Errorf("%s is registered but missing from apiOperations", key)
+			continue
+		}
+		if !strings.HasSuffix(route.Handler, ".(*Handlers)."+op.Handler+"-fm") {
+			t.Errorf("%s is served by %s, but the spec names %s", key, route.Handler, op.Handler)
+		}
+	}
+	for key := range documented {
+		if !registered[key] {
+			t.Errorf("%s is documented but not registered in SetupRoutes", key)
+		}
+	}
+}
+
+func TestOpenAPISchemaMatchesStructs(t *testing.T) {
+	spec := buildOpenAPISpec()
+	schemas := spec["components"].(map[string]any)["schemas"].(map[string]any)
+
+	source, ok := schemas["APISource"].(map[string]any)
+	if !ok {
+		t.Fatal("expected an APISource component schema")
+	}
+	properties := source["properties"].(map[string]any)
+	typ := reflect.TypeOf(APISource{})
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if _, ok := properties[name]; !ok {
+			t.Errorf("APISource schema is missing %q", name)
+		}
+	}
+	if len(properties) != typ.NumField() {
+		t.Errorf("expected %d APISource properties, got %d", typ.NumField(), len(properties))
+	}
+	required := source["required"].([]string)
+	for _, name := range required {
+		if name == "custom_headers" {
+			t.Error("omitempty field custom_headers should not be required")
+		}
+	}
+	lastSync := properties["last_sync_at"].(map[string]any)
+	if lastSync["nullable"] != true {
+		t.Errorf("expected pointer field last_sync_at to be nullable, got %v", lastSync)
+	}
+
+	dest := schemas["Destination"].(map[string]any)["properties"].(map[string]any)
+	if _, leaked := dest["dest_password"]; leaked {
+		t.Error(`json:"-" field dest_password must not appear in the spec`)
+	}
+
+	paths := spec["paths"].(map[string]map[string]any)
+	put, ok := paths["/sources/{id}"]["put"].(map[string]any)
+	if !ok {
+		t.Fatal("expected PUT /sources/{id} in paths")
+	}
+	params := put["parameters"].([]any)
+	if len(params) != 1 || params[0].(map[string]any)["name"] != "id" {
+		t.Errorf("expected a single id path parameter, got %v", params)
+	}
+	if _, ok := put["requestBody"]; !ok {
+		t.Error("expected PUT /sources/{id} to document its request body")
+	}
+}
+
+func TestAPIOpenAPISpec(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+
+	(&Handlers{}).APIOpenAPISpec(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("spec is not valid JSON: %v", err)
+	}
+	if doc["openapi"] != "3.0.3" {
+		t.Errorf("expected openapi 3.0.3, got %v", doc["openapi"])
+	}
+
+	// Every $ref must point at a component that exists.
+	schemas := doc["components"].(map[string]any)["schemas"].(map[string]any)
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if ref, ok := v["$ref"].(string); ok {
+				name := strings.TrimPrefix(ref, "#/components/schemas/")
+				if _, ok := schemas[name]; !ok {
+					t.Errorf("dangling reference %s", ref)
+				}
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []any:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(doc)
+}
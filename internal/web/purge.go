@@ -0,0 +1,61 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/macjediwizard/calbridgesync/internal/auth"
+	"github.com/macjediwizard/calbridgesync/internal/caldav"
+)
+
+// APIPurgeDestinationRequest confirms a purge; without Confirm set
+// nothing is deleted.
+type APIPurgeDestinationRequest struct {
+	Confirm bool `json:"confirm"`
+}
+
+// APIPurgeDestination deletes every event a source has synced to its
+// destination, typically before removing the source, and forgets them.
+func (h *Handlers) APIPurgeDestination(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+	sourceID := c.Param("id")
+	source, err := h.db.GetSourceByIDForUser(sourceID, session.UserID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
+		return
+	}
+
+	var req APIPurgeDestinationRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid request body")
+		return
+	}
+	if !req.Confirm {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "Set confirm to true to delete the source's events from its destination")
+		return
+	}
+
+	result, err := h.syncEngine.PurgeDestination(c.Request.Context(), source)
+	switch {
+	case errors.Is(err, caldav.ErrDestReadOnly):
+		respondError(c, http.StatusConflict, ErrCodeDestReadOnly, "Source's destination is read-only")
+		return
+	case errors.Is(err, caldav.ErrCreateOnly):
+		respondError(c, http.StatusConflict, ErrCodeConflict, "Source is create-only and never deletes destination events")
+		return
+	case err != nil:
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, sanitizeError(err, "Failed to purge destination"))
+		return
+	}
+
+	h.audit(c, "source.purge_destination", "source", sourceID, fmt.Sprintf("deleted %d, failed %d", result.Deleted, result.Failed))
+	c.JSON(http.StatusOK, result)
+}
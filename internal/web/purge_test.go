@@ -0,0 +1,38 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAPIPurgeDestination(t *testing.T) {
+	th := setupTestHandlers(t)
+	defer th.cleanup()
+
+	userID, source := createTestUserAndSource(t, th.db, "alice@example.com", "Work")
+	otherID, _ := createTestUserAndSource(t, th.db, "bob@example.com", "Bob's")
+
+	purge := func(userID, email, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/sources/"+source.ID+"/purge-destination", strings.NewReader(body))
+		c.Params = gin.Params{{Key: "id", Value: source.ID}}
+		setAuthContext(c, userID, email)
+		th.handlers.APIPurgeDestination(c)
+		return w
+	}
+
+	if w := purge(otherID, "bob@example.com", `{"confirm":true}`); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for another user's source, got %d", w.Code)
+	}
+	if w := purge(userID, "alice@example.com", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without confirmation, got %d", w.Code)
+	}
+	if w := purge(userID, "alice@example.com", `{"confirm":false}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 with confirm false, got %d", w.Code)
+	}
+}
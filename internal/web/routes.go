@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/macjediwizard/calbridgesync/internal/auth"
@@ -44,6 +45,15 @@ func SetupRoutes(r *gin.Engine, h *Handlers, sm *auth.SessionManager) {
 		authGroup.GET("/oauth/google/callback", h.GoogleOAuthCallback)
 	}
 
+	// Read-only share links (no session; the signed token in the path is
+	// the credential). Same limiter budget as the auth endpoints, since
+	// each hit verifies a MAC and touches the database.
+	shareGroup := r.Group("/share")
+	shareGroup.Use(authRateLimiter)
+	{
+		shareGroup.GET("/:token", h.ShareView)
+	}
+
 	// General API routes - 30 req/s handles typical SPA usage (page loads fetch multiple endpoints)
 	apiRateLimiter := RateLimiter(30, 60)
 	apiGroup := r.Group("/api")
@@ -53,28 +63,43 @@ func SetupRoutes(r *gin.Engine, h *Handlers, sm *auth.SessionManager) {
 		apiGroup.GET("/auth/status", h.APIAuthStatus)
 		apiGroup.GET("/version", h.APIGetVersion)
 		apiGroup.POST("/auth/logout", h.APILogout)
+		apiGroup.GET("/openapi.json", h.APIOpenAPISpec) // Machine-readable API contract, see apiOperations
 	}
 
-	// Protected API routes with rate limiting, origin validation, and content-type validation
+	// Protected API routes with rate limiting, origin validation, and content-type validation.
+	// Besides the session cookie these accept "Authorization: Bearer <token>"
+	// for scripts and CI; see auth.RequireAuthOrToken.
+	requireAPIAuth := auth.RequireAuthOrToken(sm, h.resolveAPIToken)
 	protectedAPI := r.Group("/api")
 	protectedAPI.Use(apiRateLimiter)
-	protectedAPI.Use(auth.RequireAuth(sm))
+	protectedAPI.Use(requireAPIAuth)
 	protectedAPI.Use(ValidateOrigin())         // CSRF protection via origin check
+	protectedAPI.Use(auth.ValidateAPICSRF())   // ...and via the session's X-CSRF-Token
 	protectedAPI.Use(RequireJSONContentType()) // Validate Content-Type header
 	{
 		protectedAPI.GET("/dashboard/stats", h.APIDashboardStats)
+		protectedAPI.GET("/dashboard/overview", h.APIDashboardOverview)
 		protectedAPI.GET("/dashboard/sync-history", h.APISyncHistory)
 		protectedAPI.GET("/sources", h.APIListSources)
 		protectedAPI.GET("/sources/:id", h.APIGetSource)
 		protectedAPI.PUT("/sources/:id", h.APIUpdateSource)
 		protectedAPI.DELETE("/sources/:id", h.APIDeleteSource)
 		protectedAPI.POST("/sources/:id/toggle", h.APIToggleSource)
-		protectedAPI.POST("/sources/:id/sync", h.APITriggerSync)
+		protectedAPI.POST("/sources/:id/cancel", h.APICancelSync)
+		protectedAPI.POST("/sources/:id/undo-last-sync", h.APIUndoLastSync)
 		protectedAPI.GET("/sources/:id/logs", h.APIGetSourceLogs)
+		protectedAPI.DELETE("/sources/:id/logs", h.APIDeleteSourceLogs)
+		protectedAPI.GET("/sources/:id/logs/:log_id", h.APIGetSourceLog)
 		protectedAPI.GET("/sources/:id/stats", h.APIGetSourceStats)
+		protectedAPI.GET("/sources/:id/failed-events", h.APIGetFailedEvents)
+		protectedAPI.POST("/sources/:id/failed-events/retry", h.APIRetryFailedEvents)
+		protectedAPI.DELETE("/sources/:id/stamped-events", h.APIDeleteStampedEvents)
+		protectedAPI.POST("/sources/:id/purge-destination", h.APIPurgeDestination)
 		protectedAPI.GET("/malformed-events", h.APIGetMalformedEvents)
+		protectedAPI.GET("/malformed-events/summary", h.APIGetMalformedEventSummary)
 		protectedAPI.DELETE("/malformed-events", h.APIDeleteAllMalformedEvents)
 		protectedAPI.DELETE("/malformed-events/:id", h.APIDeleteMalformedEvent)
+		protectedAPI.GET("/search", h.APISearch)
 		protectedAPI.GET("/settings/alerts", h.APIGetAlertPreferences)
 		protectedAPI.PUT("/settings/alerts", h.APIUpdateAlertPreferences)
 		protectedAPI.GET("/settings/log-stats", h.APIGetLogStats)
@@ -82,7 +107,25 @@ func SetupRoutes(r *gin.Engine, h *Handlers, sm *auth.SessionManager) {
 		protectedAPI.GET("/sources/:id/destinations", h.APIListDestinations)
 		protectedAPI.POST("/sources/:id/destinations", h.APICreateDestination)
 		protectedAPI.DELETE("/sources/:id/destinations/:destId", h.APIDeleteDestination)
+		protectedAPI.GET("/sources/:id/shares", h.APIListShareLinks)
+		protectedAPI.POST("/sources/:id/shares", h.APICreateShareLink)
+		protectedAPI.DELETE("/sources/:id/shares/:shareId", h.APIRevokeShareLink)
 		protectedAPI.GET("/activity", h.APIGetActivity)
+		protectedAPI.GET("/sources/:id/activity", h.APISourceActivity)
+		protectedAPI.GET("/sources/:id/activity/stream", h.APISourceActivityStream)
+		protectedAPI.GET("/health/detailed", h.APIHealthDetailed)
+		protectedAPI.GET("/tokens", h.APIListAPITokens)
+		protectedAPI.POST("/tokens", h.APICreateAPIToken)
+		protectedAPI.DELETE("/tokens/:id", h.APIRevokeAPIToken)
+		protectedAPI.POST("/tools/validate-ics", h.APIValidateICS)
+	}
+
+	// Instance-wide operations, for admins only (AUTH_ADMIN_EMAILS or
+	// AUTH_ADMIN_CLAIM); everyone else gets 403.
+	adminAPI := protectedAPI.Group("/admin")
+	adminAPI.Use(auth.RequireAdmin())
+	{
+		adminAPI.POST("/alerts/reload", h.APIReloadAlertConfig)
 	}
 
 	// Expensive operations - 2 req/s prevents abuse of network-intensive operations
@@ -90,12 +133,15 @@ func SetupRoutes(r *gin.Engine, h *Handlers, sm *auth.SessionManager) {
 	expensiveRateLimiter := RateLimiter(2, 5)
 	expensiveAPI := r.Group("/api")
 	expensiveAPI.Use(expensiveRateLimiter)
-	expensiveAPI.Use(auth.RequireAuth(sm))
+	expensiveAPI.Use(requireAPIAuth)
 	expensiveAPI.Use(ValidateOrigin())
+	expensiveAPI.Use(auth.ValidateAPICSRF())
 	expensiveAPI.Use(RequireJSONContentType())
 	{
 		expensiveAPI.POST("/sources", h.APICreateSource)                       // Tests connections to CalDAV servers
 		expensiveAPI.POST("/sources/google/prepare", h.APIPrepareGoogleSource) // Tests dest + stashes pending Google source (#70)
+		expensiveAPI.POST("/sources/sync-all", h.APISyncAllSources)            // Queues a sync for every enabled source
+		expensiveAPI.POST("/sources/:id/sync", h.APITriggerSync)               // Queues a sync, or runs one calendar's
 		expensiveAPI.POST("/calendars/discover", h.APIDiscoverCalendars)       // Discovers calendars via network
 		expensiveAPI.POST("/settings/alerts/test-webhook", h.APITestWebhook)   // Tests webhook via network
 		expensiveAPI.GET("/export/calendars", h.APIExportCalendars)            // Exports all user calendars as ICS
@@ -125,17 +171,22 @@ func setupReactApp(r *gin.Engine) {
 	r.NoRoute(func(c *gin.Context) {
 		// Don't serve index.html for API routes
 		if len(c.Request.URL.Path) >= 4 && c.Request.URL.Path[:4] == "/api" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+			respondError(c, http.StatusNotFound, ErrCodeNotFound, "Not found")
 			return
 		}
 		// Don't serve index.html for auth routes
 		if len(c.Request.URL.Path) >= 5 && c.Request.URL.Path[:5] == "/auth" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+			respondError(c, http.StatusNotFound, ErrCodeNotFound, "Not found")
+			return
+		}
+		// Don't serve index.html for share links
+		if strings.HasPrefix(c.Request.URL.Path, "/share/") {
+			respondError(c, http.StatusNotFound, ErrCodeNotFound, "Not found")
 			return
 		}
 		// Don't serve index.html for health routes
 		if c.Request.URL.Path == "/health" || c.Request.URL.Path == "/healthz" || c.Request.URL.Path == "/ready" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+			respondError(c, http.StatusNotFound, ErrCodeNotFound, "Not found")
 			return
 		}
 
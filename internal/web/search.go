@@ -0,0 +1,96 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/macjediwizard/calbridgesync/internal/auth"
+)
+
+const (
+	// minSearchQueryLength keeps one-letter queries, which match
+	// nearly everything, from scanning every log.
+	minSearchQueryLength = 2
+	// maxSearchQueryLength bounds the LIKE pattern.
+	maxSearchQueryLength = 200
+	// searchResultLimit caps each kind of result a search returns.
+	searchResultLimit = 50
+)
+
+// APISearchSource is a source with at least one search hit.
+type APISearchSource struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// APISearchResult is what GET /api/search returns: the matching sync
+// logs and malformed events, newest first, and the sources they
+// belong to.
+type APISearchResult struct {
+	Query           string               `json:"query"`
+	Sources         []APISearchSource    `json:"sources"`
+	SyncLogs        []*APISyncLog        `json:"sync_logs"`
+	MalformedEvents []*APIMalformedEvent `json:"malformed_events"`
+	// Truncated is set when either list hit the result limit.
+	Truncated bool `json:"truncated"`
+}
+
+// APISearch searches the current user's sync log messages and details
+// and malformed event paths and errors for the text in q.
+func (h *Handlers) APISearch(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	q := strings.TrimSpace(c.Query("q"))
+	if n := utf8.RuneCountInString(q); n < minSearchQueryLength || n > maxSearchQueryLength {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "Search query must be between 2 and 200 characters")
+		return
+	}
+
+	logs, err := h.db.SearchSyncLogs(session.UserID, q, searchResultLimit)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to search sync logs")
+		return
+	}
+	events, err := h.db.SearchMalformedEvents(session.UserID, q, searchResultLimit)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to search malformed events")
+		return
+	}
+
+	result := APISearchResult{
+		Query:           q,
+		Sources:         []APISearchSource{},
+		SyncLogs:        make([]*APISyncLog, len(logs)),
+		MalformedEvents: make([]*APIMalformedEvent, len(events)),
+		Truncated:       len(logs) == searchResultLimit || len(events) == searchResultLimit,
+	}
+	hit := make(map[string]bool)
+	for i, l := range logs {
+		result.SyncLogs[i] = syncLogToAPI(l)
+		hit[l.SourceID] = true
+	}
+	for i, e := range events {
+		result.MalformedEvents[i] = malformedEventToAPI(e)
+		hit[e.SourceID] = true
+	}
+	if len(hit) > 0 {
+		sources, err := h.db.GetSourcesByUserID(session.UserID)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load sources")
+			return
+		}
+		for _, s := range sources {
+			if hit[s.ID] {
+				result.Sources = append(result.Sources, APISearchSource{ID: s.ID, Name: s.Name})
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,91 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+func TestAPISearch(t *testing.T) {
+	th := setupTestHandlers(t)
+	defer th.cleanup()
+
+	userID, source := createTestUserAndSource(t, th.db, "alice@example.com", "Work")
+	if err := th.db.SaveMalformedEvent(source.ID, "/cal/board-review.ics", "missing DTSTAMP"); err != nil {
+		t.Fatalf("SaveMalformedEvent: %v", err)
+	}
+	if err := th.db.SaveMalformedEvent(source.ID, "/cal/lunch.ics", "empty"); err != nil {
+		t.Fatalf("SaveMalformedEvent: %v", err)
+	}
+	if err := th.db.CreateSyncLog(&db.SyncLog{
+		SourceID: source.ID, Status: db.SyncStatusPartial,
+		Message: "Failed to create event on dest (UID=board-review): 507 Insufficient Storage",
+	}); err != nil {
+		t.Fatalf("CreateSyncLog: %v", err)
+	}
+	if err := th.db.CreateSyncLog(&db.SyncLog{
+		SourceID: source.ID, Status: db.SyncStatusSuccess, Message: "Synced 1 calendar(s)",
+	}); err != nil {
+		t.Fatalf("CreateSyncLog: %v", err)
+	}
+	// Another user's data with the same text must not show up.
+	_, other := createTestUserAndSource(t, th.db, "bob@example.com", "Bob's")
+	if err := th.db.SaveMalformedEvent(other.ID, "/cal/board-review.ics", "missing DTSTAMP"); err != nil {
+		t.Fatalf("SaveMalformedEvent: %v", err)
+	}
+	if err := th.db.CreateSyncLog(&db.SyncLog{
+		SourceID: other.ID, Status: db.SyncStatusError, Message: "board-review failed",
+	}); err != nil {
+		t.Fatalf("CreateSyncLog: %v", err)
+	}
+
+	search := func(q string) (*httptest.ResponseRecorder, APISearchResult) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/search?q="+url.QueryEscape(q), nil)
+		setAuthContext(c, userID, "alice@example.com")
+		th.handlers.APISearch(c)
+		var result APISearchResult
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+		}
+		return w, result
+	}
+
+	t.Run("matches a malformed event path and a log message", func(t *testing.T) {
+		w, result := search("board-review")
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if len(result.MalformedEvents) != 1 || result.MalformedEvents[0].SourceID != source.ID ||
+			result.MalformedEvents[0].EventPath != "/cal/board-review.ics" {
+			t.Errorf("expected only the user's malformed event, got %+v", result.MalformedEvents)
+		}
+		if len(result.SyncLogs) != 1 || result.SyncLogs[0].SourceID != source.ID {
+			t.Errorf("expected only the user's matching sync log, got %+v", result.SyncLogs)
+		}
+		if len(result.Sources) != 1 || result.Sources[0].Name != "Work" {
+			t.Errorf("expected the Work source, got %+v", result.Sources)
+		}
+	})
+
+	t.Run("LIKE wildcards match literally", func(t *testing.T) {
+		_, result := search("%_")
+		if len(result.SyncLogs) != 0 || len(result.MalformedEvents) != 0 {
+			t.Errorf("expected no matches, got %+v", result)
+		}
+	})
+
+	t.Run("rejects too-short queries", func(t *testing.T) {
+		if w, _ := search("b"); w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,198 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/macjediwizard/calbridgesync/internal/auth"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+const (
+	// defaultShareLinkHours is how long a share link lives when the
+	// request doesn't say.
+	defaultShareLinkHours = 24
+	// maxShareLinkHours caps share link lifetime at 30 days so a
+	// forgotten link doesn't stay live indefinitely.
+	maxShareLinkHours = 720
+)
+
+// APIShareLink is a share link as returned by the API, with its
+// signed URL.
+type APIShareLink struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// shareLinkToAPI signs link into a shareable URL. Tokens are
+// deterministic in (ID, expiry), so listing can rebuild the same URL
+// that creation returned without storing the token.
+func (h *Handlers) shareLinkToAPI(link *db.ShareLink) APIShareLink {
+	token := auth.SignShareToken(h.cfg.Security.SessionSecret, link.ID, link.ExpiresAt)
+	return APIShareLink{
+		ID:        link.ID,
+		URL:       strings.TrimRight(h.cfg.Server.BaseURL, "/") + "/share/" + token,
+		ExpiresAt: link.ExpiresAt,
+		CreatedAt: link.CreatedAt,
+	}
+}
+
+// APICreateShareLink issues a read-only, expiring status link for a
+// source the current user owns.
+func (h *Handlers) APICreateShareLink(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+	sourceID := c.Param("id")
+	if _, err := h.db.GetSourceByIDForUser(sourceID, session.UserID); err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
+		return
+	}
+
+	var req struct {
+		ExpiresInHours int `json:"expires_in_hours"`
+	}
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid request body")
+		return
+	}
+	if req.ExpiresInHours == 0 {
+		req.ExpiresInHours = defaultShareLinkHours
+	}
+	if req.ExpiresInHours < 1 || req.ExpiresInHours > maxShareLinkHours {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "expires_in_hours must be between 1 and 720")
+		return
+	}
+
+	link := &db.ShareLink{
+		SourceID:  sourceID,
+		ExpiresAt: time.Now().Add(time.Duration(req.ExpiresInHours) * time.Hour),
+	}
+	if err := h.db.CreateShareLink(link); err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, sanitizeError(err, "Failed to create share link"))
+		return
+	}
+
+	h.audit(c, "share_link.create", "share_link", link.ID, "source="+sourceID)
+	c.JSON(http.StatusCreated, h.shareLinkToAPI(link))
+}
+
+// APIListShareLinks returns a source's unexpired share links.
+func (h *Handlers) APIListShareLinks(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+	sourceID := c.Param("id")
+	if _, err := h.db.GetSourceByIDForUser(sourceID, session.UserID); err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
+		return
+	}
+	links, err := h.db.GetShareLinksBySourceID(sourceID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load share links")
+		return
+	}
+	result := make([]APIShareLink, 0, len(links))
+	for _, link := range links {
+		result = append(result, h.shareLinkToAPI(link))
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// APIRevokeShareLink deletes a share link so its URL stops working
+// before it expires.
+func (h *Handlers) APIRevokeShareLink(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+	sourceID := c.Param("id")
+	if _, err := h.db.GetSourceByIDForUser(sourceID, session.UserID); err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
+		return
+	}
+	shareID := c.Param("shareId")
+	if err := h.db.DeleteShareLink(shareID, sourceID); err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeNotFound, "Share link not found")
+		return
+	}
+	h.audit(c, "share_link.revoke", "share_link", shareID, "source="+sourceID)
+	c.JSON(http.StatusOK, gin.H{"message": "Share link revoked"})
+}
+
+// ShareView renders the read-only status page behind a share link. It
+// needs no session; the signed token is the credential. Only status
+// fields and the last run's counts are passed to the template — never
+// URLs, usernames or log messages, which can echo server paths.
+func (h *Handlers) ShareView(c *gin.Context) {
+	// The token is in the URL: keep it out of caches and out of the
+	// Referer header of any link followed from this page.
+	c.Header("Cache-Control", "no-store")
+	c.Header("Referrer-Policy", "no-referrer")
+
+	linkID, expiresAt, err := auth.VerifyShareToken(h.cfg.Security.SessionSecret, c.Param("token"), time.Now())
+	if errors.Is(err, auth.ErrShareTokenExpired) {
+		h.shareError(c, http.StatusGone, "This share link has expired.")
+		return
+	}
+	if err != nil {
+		h.shareError(c, http.StatusNotFound, "This share link is invalid or has been revoked.")
+		return
+	}
+
+	link, err := h.db.GetShareLinkByID(linkID)
+	if err != nil || !link.ExpiresAt.Equal(expiresAt) {
+		h.shareError(c, http.StatusNotFound, "This share link is invalid or has been revoked.")
+		return
+	}
+	source, err := h.db.GetSourceByID(link.SourceID)
+	if err != nil {
+		h.shareError(c, http.StatusNotFound, "This share link is invalid or has been revoked.")
+		return
+	}
+
+	data := gin.H{
+		"Title":          source.Name,
+		"SourceName":     source.Name,
+		"Enabled":        source.Enabled,
+		"LastSyncAt":     source.LastSyncAt,
+		"LastSyncStatus": string(source.LastSyncStatus),
+		"ExpiresAt":      link.ExpiresAt,
+	}
+	if stats, err := h.db.GetSourceStats(source.ID); err == nil {
+		data["SyncedEventCount"] = stats.SyncedEventCount
+		data["HealthLabel"] = stats.HealthLabel
+		data["SuccessRate"] = stats.SuccessRate
+	}
+	if logs, err := h.db.GetSyncLogs(source.ID, 1); err == nil && len(logs) > 0 {
+		last := logs[0]
+		data["LastLog"] = gin.H{
+			"EventsCreated": last.EventsCreated,
+			"EventsUpdated": last.EventsUpdated,
+			"EventsDeleted": last.EventsDeleted,
+			"EventsSkipped": last.EventsSkipped,
+			"Duration":      last.Duration,
+		}
+	}
+	c.HTML(http.StatusOK, "share.html", data)
+}
+
+func (h *Handlers) shareError(c *gin.Context, code int, message string) {
+	c.HTML(code, "error.html", gin.H{
+		"Title":   "Share link",
+		"Code":    code,
+		"Message": message,
+	})
+}
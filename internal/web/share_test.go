@@ -0,0 +1,197 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/macjediwizard/calbridgesync/internal/auth"
+	"github.com/macjediwizard/calbridgesync/internal/config"
+	"github.com/macjediwizard/calbridgesync/internal/db"
+)
+
+const testShareSecret = "test-secret-key-at-least-32-chars"
+
+// shareRouter serves /share/:token with real templates.
+func shareRouter(t *testing.T, th *testHandlers) *gin.Engine {
+	t.Helper()
+	th.handlers.cfg = &config.Config{
+		Server:   config.ServerConfig{BaseURL: "https://sync.example.com/"},
+		Security: config.SecurityConfig{SessionSecret: testShareSecret},
+	}
+	templates, err := LoadTemplates()
+	if err != nil {
+		t.Fatalf("failed to load templates: %v", err)
+	}
+	r := gin.New()
+	r.HTMLRender = templates
+	r.GET("/share/:token", th.handlers.ShareView)
+	return r
+}
+
+func TestShareLinks(t *testing.T) {
+	t.Run("share view shows status without credentials", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+		router := shareRouter(t, th)
+
+		userID, source := createTestUserAndSource(t, th.db, "alice@example.com", "Team Calendar")
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/sources/"+source.ID+"/shares", strings.NewReader(`{"expires_in_hours": 2}`))
+		c.Params = gin.Params{{Key: "id", Value: source.ID}}
+		setAuthContext(c, userID, "alice@example.com")
+		th.handlers.APICreateShareLink(c)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+		var link APIShareLink
+		if err := json.Unmarshal(w.Body.Bytes(), &link); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		if !strings.HasPrefix(link.URL, "https://sync.example.com/share/") {
+			t.Fatalf("unexpected share URL %q", link.URL)
+		}
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, strings.TrimPrefix(link.URL, "https://sync.example.com"), nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		body := w.Body.String()
+		if !strings.Contains(body, "Team Calendar") {
+			t.Error("share view should show the source name")
+		}
+		for _, secret := range []string{
+			source.SourceURL, source.SourceUsername, source.SourcePassword,
+			source.DestURL, source.DestUsername, source.DestPassword, "alice@example.com",
+		} {
+			if strings.Contains(body, secret) {
+				t.Errorf("share view leaks %q", secret)
+			}
+		}
+		if w.Header().Get("Cache-Control") != "no-store" {
+			t.Error("share view must not be cacheable")
+		}
+	})
+
+	t.Run("share view shows last run counts without its messages", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+		router := shareRouter(t, th)
+
+		_, source := createTestUserAndSource(t, th.db, "alice@example.com", "Team Calendar")
+		if err := th.db.CreateSyncLog(&db.SyncLog{
+			SourceID:      source.ID,
+			Status:        db.SyncStatusPartial,
+			Message:       "Failed to put /remote.php/dav/calendars/alice/work/x.ics",
+			Details:       "PROPFIND https://internal.example.com/dav/ returned 500",
+			EventsCreated: 7,
+			EventsSkipped: 3,
+			Duration:      1500 * time.Millisecond,
+		}); err != nil {
+			t.Fatalf("failed to create sync log: %v", err)
+		}
+		link := &db.ShareLink{SourceID: source.ID, ExpiresAt: time.Now().Add(time.Hour)}
+		if err := th.db.CreateShareLink(link); err != nil {
+			t.Fatalf("failed to create share link: %v", err)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/share/"+auth.SignShareToken(testShareSecret, link.ID, link.ExpiresAt), nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		body := w.Body.String()
+		for _, want := range []string{"7 created", "3 skipped", "1.50s"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("share view should show %q", want)
+			}
+		}
+		for _, leak := range []string{"/remote.php/dav/calendars/alice", "internal.example.com"} {
+			if strings.Contains(body, leak) {
+				t.Errorf("share view leaks %q", leak)
+			}
+		}
+	})
+
+	t.Run("revoked link is rejected", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+		router := shareRouter(t, th)
+
+		_, source := createTestUserAndSource(t, th.db, "alice@example.com", "Team Calendar")
+		link := &db.ShareLink{SourceID: source.ID, ExpiresAt: time.Now().Add(time.Hour)}
+		if err := th.db.CreateShareLink(link); err != nil {
+			t.Fatalf("failed to create share link: %v", err)
+		}
+		path := "/share/" + auth.SignShareToken(testShareSecret, link.ID, link.ExpiresAt)
+		if err := th.db.DeleteShareLink(link.ID, source.ID); err != nil {
+			t.Fatalf("failed to revoke: %v", err)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("expired link is rejected", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+		router := shareRouter(t, th)
+
+		_, source := createTestUserAndSource(t, th.db, "alice@example.com", "Team Calendar")
+		link := &db.ShareLink{SourceID: source.ID, ExpiresAt: time.Now().Add(-time.Minute)}
+		if err := th.db.CreateShareLink(link); err != nil {
+			t.Fatalf("failed to create share link: %v", err)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/share/"+auth.SignShareToken(testShareSecret, link.ID, link.ExpiresAt), nil))
+		if w.Code != http.StatusGone {
+			t.Errorf("expected status 410, got %d", w.Code)
+		}
+	})
+
+	t.Run("token signed with another secret is rejected", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+		router := shareRouter(t, th)
+
+		_, source := createTestUserAndSource(t, th.db, "alice@example.com", "Team Calendar")
+		link := &db.ShareLink{SourceID: source.ID, ExpiresAt: time.Now().Add(time.Hour)}
+		if err := th.db.CreateShareLink(link); err != nil {
+			t.Fatalf("failed to create share link: %v", err)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/share/"+auth.SignShareToken("some-other-secret-at-least-32-chars", link.ID, link.ExpiresAt), nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("expiry outside allowed range is rejected", func(t *testing.T) {
+		th := setupTestHandlers(t)
+		defer th.cleanup()
+		shareRouter(t, th)
+
+		userID, source := createTestUserAndSource(t, th.db, "alice@example.com", "Team Calendar")
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/sources/"+source.ID+"/shares", strings.NewReader(`{"expires_in_hours": 10000}`))
+		c.Params = gin.Params{{Key: "id", Value: source.ID}}
+		setAuthContext(c, userID, "alice@example.com")
+		th.handlers.APICreateShareLink(c)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/macjediwizard/calbridgesync/internal/auth"
+	"github.com/macjediwizard/calbridgesync/internal/caldav"
+)
+
+// APIDeleteStampedEvents deletes the events on a source's destination
+// calendar that carry a source stamp: the ?stamp= query parameter, or
+// the source's own stamp without one. Every source of the user's that
+// writes the stamp must be disabled first, or its next sync would see
+// the events as deleted on the destination; once they are cleaned up,
+// those sources forget what they synced, so re-enabling one syncs from
+// scratch.
+func (h *Handlers) APIDeleteStampedEvents(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+	sourceID := c.Param("id")
+	source, err := h.db.GetSourceByIDForUser(sourceID, session.UserID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeSourceNotFound, "Source not found")
+		return
+	}
+
+	stamp := c.Query("stamp")
+	if stamp == "" {
+		stamp = caldav.RenderSourceStamp(source)
+	}
+	if stamp == "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "Source has no stamp; pass one as ?stamp=")
+		return
+	}
+
+	sources, err := h.db.GetSourcesByUserID(session.UserID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load sources")
+		return
+	}
+	var owners []string
+	for _, s := range sources {
+		if caldav.RenderSourceStamp(s) != stamp {
+			continue
+		}
+		if s.Enabled {
+			respondErrorDetails(c, http.StatusConflict, ErrCodeConflict,
+				fmt.Sprintf("Disable source %q, which writes this stamp, before cleaning up its events", s.Name),
+				gin.H{"source_id": s.ID})
+			return
+		}
+		owners = append(owners, s.ID)
+	}
+
+	result, err := h.syncEngine.DeleteStampedEvents(c.Request.Context(), source, stamp)
+	if errors.Is(err, caldav.ErrDestReadOnly) {
+		respondError(c, http.StatusConflict, ErrCodeDestReadOnly, "Source's destination is read-only")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, sanitizeError(err, "Failed to delete stamped events"))
+		return
+	}
+	for _, ownerID := range owners {
+		if err := h.db.ResetSourceSyncState(ownerID); err != nil {
+			log.Printf("Failed to reset sync state of source %s after stamp cleanup: %v", ownerID, err)
+		}
+	}
+
+	h.audit(c, "source.delete_stamped_events", "source", sourceID, fmt.Sprintf("stamp %q: deleted %d, failed %d", stamp, result.Deleted, result.Failed))
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,60 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAPIDeleteStampedEvents(t *testing.T) {
+	th := setupTestHandlers(t)
+	defer th.cleanup()
+
+	userID, source := createTestUserAndSource(t, th.db, "alice@example.com", "Work")
+
+	del := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/api/sources/"+source.ID+"/stamped-events"+query, nil)
+		c.Params = gin.Params{{Key: "id", Value: source.ID}}
+		setAuthContext(c, userID, "alice@example.com")
+		th.handlers.APIDeleteStampedEvents(c)
+		return w
+	}
+
+	if w := del(""); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a source without a stamp, got %d", w.Code)
+	}
+
+	source.SourceStamp = "calbridge:{source_name}"
+	source.Enabled = true
+	if err := th.db.UpdateSource(source); err != nil {
+		t.Fatalf("UpdateSource: %v", err)
+	}
+	if w := del(""); w.Code != http.StatusConflict {
+		t.Errorf("expected 409 while the source writing the stamp is enabled, got %d", w.Code)
+	}
+	if w := del("?stamp=calbridge:Work"); w.Code != http.StatusConflict {
+		t.Errorf("expected 409 for the same stamp given explicitly, got %d", w.Code)
+	}
+}
+
+func TestValidateSourceStamp(t *testing.T) {
+	tests := []struct {
+		stamp   string
+		wantErr bool
+	}{
+		{"", false},
+		{"calbridge:{source_name}", false},
+		{"line\nbreak", true},
+		{strings.Repeat("a", maxSourceStampLength+1), true},
+	}
+	for _, tt := range tests {
+		if got := validateSourceStamp(tt.stamp); (got != "") != tt.wantErr {
+			t.Errorf("validateSourceStamp(%q) = %q, wantErr %v", tt.stamp, got, tt.wantErr)
+		}
+	}
+}
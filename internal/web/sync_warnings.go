@@ -0,0 +1,185 @@
+package web
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/macjediwizard/calbridgesync/internal/caldav"
+)
+
+// conflictWarningPrefix marks the structured conflict records the UI
+// renders itself; they carry only the event's UID and title, so they
+// are passed through as they are.
+const conflictWarningPrefix = "CONFLICT:"
+
+// Sync warning categories that don't concern a single event. Every
+// other category returned by categorizeSyncWarning is a per-event
+// phrase meant to follow a count ("3 events failed to upload ...").
+const (
+	warningSyncState        = "Sync state could not be saved; some events may be re-checked next sync"
+	warningSafetyHold       = "Some deletions or uploads were held back by a safety check"
+	warningDestUnreadable   = "The destination calendar could not be read"
+	warningAdditionalDest   = "An additional destination could not be synced"
+	warningUIDPrefixOneWay  = "A calendar was synced one-way because a UID prefix is set"
+	warningConflictResolved = "Conflict resolved"
+	warningOther            = "Other sync issue"
+)
+
+var nonEventWarnings = map[string]bool{
+	warningSyncState:        true,
+	warningSafetyHold:       true,
+	warningDestUnreadable:   true,
+	warningAdditionalDest:   true,
+	warningUIDPrefixOneWay:  true,
+	warningConflictResolved: true,
+	warningOther:            true,
+}
+
+var (
+	serverErrorStatus = regexp.MustCompile(`\b5\d\d\b`)
+	clientErrorStatus = regexp.MustCompile(`\b4\d\d\b`)
+)
+
+// categorizeSyncWarning maps a raw sync warning to a friendly category
+// that is safe to show in the UI. Like categorizeConnectionError it
+// keeps paths, server responses and credentials out of the result.
+func categorizeSyncWarning(w string) string {
+	lower := strings.ToLower(w)
+
+	switch {
+	case strings.HasPrefix(w, conflictWarningPrefix):
+		return warningConflictResolved
+	case strings.Contains(lower, "failed to create event on source"),
+		strings.Contains(lower, "failed to update event on source"),
+		strings.Contains(lower, "merged event to source"):
+		return "failed to write back to the source (" + warningReason(lower) + ")"
+	case strings.Contains(lower, "dead-lettered"):
+		return "set aside after failing repeatedly (retry from the source's failed events)"
+	case strings.Contains(lower, "failed to create event on dest"),
+		strings.Contains(lower, "failed to update event on dest"),
+		strings.Contains(lower, "failed to sync event"):
+		return "failed to upload (" + warningReason(lower) + ")"
+	case strings.Contains(lower, "failed to delete event"),
+		strings.Contains(lower, "failed to delete orphan event"),
+		strings.Contains(lower, "failed to delete duplicate event"):
+		return "failed to delete (" + warningReason(lower) + ")"
+	case strings.Contains(lower, "write verification failed"):
+		return "could not be verified after writing"
+	case strings.Contains(lower, "not converted to"):
+		return "could not be converted to the target timezone"
+	case strings.Contains(lower, "could not merge"):
+		return "could not be merged (source version kept)"
+	case strings.Contains(lower, "malformed"):
+		return "skipped (malformed calendar data)"
+	case strings.Contains(lower, "zombie recurring series"):
+		return "flagged as a corrupted recurring series on the source"
+	case strings.Contains(lower, "duplicate uid"):
+		return "found with a duplicate UID in the source calendar"
+	case strings.Contains(lower, "synced event record"),
+		strings.Contains(lower, "synced_events"),
+		strings.Contains(lower, "deletion confirmations"),
+		strings.Contains(lower, "sync status"),
+		strings.Contains(lower, "sync log"):
+		return warningSyncState
+	case strings.Contains(lower, "for safety"),
+		strings.Contains(lower, "safety threshold"):
+		return warningSafetyHold
+	case strings.Contains(lower, "failed to get destination events"),
+		strings.Contains(lower, "duplicate cleanup aborted"):
+		return warningDestUnreadable
+	case strings.Contains(lower, "additional dest"):
+		return warningAdditionalDest
+	case strings.Contains(lower, "uid prefix is set"):
+		return warningUIDPrefixOneWay
+	default:
+		return warningOther
+	}
+}
+
+// warningReason names why the server refused an event write.
+func warningReason(lower string) string {
+	switch {
+	case strings.Contains(lower, "401") || strings.Contains(lower, "unauthorized"):
+		return "authentication failed"
+	case strings.Contains(lower, "403") || strings.Contains(lower, "forbidden"):
+		return "permission denied"
+	case strings.Contains(lower, "412") || strings.Contains(lower, "precondition"):
+		return "changed on the server meanwhile"
+	case strings.Contains(lower, "413") || strings.Contains(lower, "too large"):
+		return "too large"
+	case strings.Contains(lower, "507") || strings.Contains(lower, "quota") || strings.Contains(lower, "insufficient storage"):
+		return "storage full"
+	case strings.Contains(lower, "timeout") || strings.Contains(lower, "deadline"):
+		return "timed out"
+	case strings.Contains(lower, "no such host") || strings.Contains(lower, "connection refused") ||
+		strings.Contains(lower, "connection reset"):
+		return "server unreachable"
+	case serverErrorStatus.MatchString(lower):
+		return "server error"
+	case clientErrorStatus.MatchString(lower) || strings.Contains(lower, "rejected"):
+		return "server rejected"
+	default:
+		return "unexpected error"
+	}
+}
+
+// summarizeSyncWarnings replaces raw sync warnings with one line per
+// category, with counts, in the order each category first appears.
+// Conflict records are kept as they are for the UI to render.
+func summarizeSyncWarnings(warnings []string) []string {
+	if len(warnings) == 0 {
+		return nil
+	}
+	var order []string
+	counts := make(map[string]int)
+	var conflicts []string
+	for _, w := range warnings {
+		if strings.HasPrefix(w, conflictWarningPrefix) {
+			conflicts = append(conflicts, w)
+			continue
+		}
+		category := categorizeSyncWarning(w)
+		if counts[category] == 0 {
+			order = append(order, category)
+		}
+		counts[category]++
+	}
+
+	summary := make([]string, 0, len(order)+len(conflicts))
+	for _, category := range order {
+		n := counts[category]
+		switch {
+		case !nonEventWarnings[category] && n == 1:
+			summary = append(summary, "1 event "+category)
+		case !nonEventWarnings[category]:
+			summary = append(summary, fmt.Sprintf("%d events %s", n, category))
+		case n == 1:
+			summary = append(summary, category)
+		default:
+			summary = append(summary, fmt.Sprintf("%s (%d times)", category, n))
+		}
+	}
+	return append(summary, conflicts...)
+}
+
+// summarizeSyncLogDetails rewrites the warnings section of a sync log's
+// details (everything after caldav.SyncLogWarningsHeader) into its
+// summarized form. The raw warnings stay in the database and the
+// server log. Logs written before warnings were stored one per line
+// hold them as a single bracketed list, which can't be split reliably;
+// that line is summarized as one warning.
+func summarizeSyncLogDetails(details string) string {
+	lines := strings.Split(details, "\n")
+	for i, line := range lines {
+		if line == caldav.SyncLogWarningsHeader {
+			summary := summarizeSyncWarnings(lines[i+1:])
+			return strings.Join(append(lines[:i+1], summary...), "\n")
+		}
+		if legacy, ok := strings.CutPrefix(line, caldav.SyncLogWarningsHeader+" "); ok {
+			summary := summarizeSyncWarnings([]string{legacy})
+			return strings.Join(append(append(lines[:i], caldav.SyncLogWarningsHeader), summary...), "\n")
+		}
+	}
+	return details
+}
@@ -0,0 +1,46 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/macjediwizard/calbridgesync/internal/auth"
+	"github.com/macjediwizard/calbridgesync/internal/caldav"
+)
+
+// APIValidateICSRequest carries the iCalendar data to check. The API
+// only takes JSON, so the .ics content goes in a string field.
+type APIValidateICSRequest struct {
+	ICS string `json:"ics"`
+}
+
+// APIValidateICS reports whether an iCalendar payload would parse and
+// encode cleanly in a sync, with the issues found in each entry. A
+// payload that doesn't parse is still a 200; the diagnostics say why.
+// Nothing is stored.
+func (h *Handlers) APIValidateICS(c *gin.Context) {
+	session := auth.GetCurrentUser(c)
+	if session == nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// JSON escaping can double the size of line breaks and quotes.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 2*caldav.MaxICSCheckBytes)
+	var req APIValidateICSRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid request body")
+		return
+	}
+	if req.ICS == "" {
+		respondError(c, http.StatusBadRequest, ErrCodeValidation, "ics is required")
+		return
+	}
+	if len(req.ICS) > caldav.MaxICSCheckBytes {
+		respondError(c, http.StatusRequestEntityTooLarge, ErrCodePayloadTooLarge, "ics is too large")
+		return
+	}
+
+	c.JSON(http.StatusOK, caldav.CheckICS(req.ICS))
+}
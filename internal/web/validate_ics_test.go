@@ -0,0 +1,77 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/macjediwizard/calbridgesync/internal/caldav"
+)
+
+func TestAPIValidateICS(t *testing.T) {
+	th := setupTestHandlers(t)
+	defer th.cleanup()
+	userID, _ := createTestUserAndSource(t, th.db, "alice@example.com", "Work")
+
+	validate := func(ics string) (*httptest.ResponseRecorder, caldav.ICSCheckResult) {
+		body, _ := json.Marshal(APIValidateICSRequest{ICS: ics})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/tools/validate-ics", strings.NewReader(string(body)))
+		setAuthContext(c, userID, "alice@example.com")
+		th.handlers.APIValidateICS(c)
+		var result caldav.ICSCheckResult
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+		}
+		return w, result
+	}
+	const event = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VEVENT\r\n" +
+		"UID:standup@example.com\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\n" +
+		"SUMMARY:Standup\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	t.Run("valid", func(t *testing.T) {
+		w, result := validate(event)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if !result.Parsed || !result.Encodes || len(result.Components) != 1 || result.Components[0].UID != "standup@example.com" {
+			t.Errorf("unexpected result %+v", result)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		w, result := validate("BEGIN:VCALENDAR\r\nnot ical\r\n")
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200 with diagnostics, got %d: %s", w.Code, w.Body.String())
+		}
+		if result.Parsed || result.ParseError == "" {
+			t.Errorf("expected a parse error, got %+v", result)
+		}
+	})
+
+	t.Run("missing DTSTAMP", func(t *testing.T) {
+		w, result := validate(strings.Replace(event, "DTSTAMP:20260101T000000Z\r\n", "", 1))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if result.Encodes || result.EncodeError == "" || !result.EncodesAfterRepair {
+			t.Errorf("expected an encode error a sync would repair, got %+v", result)
+		}
+		if len(result.Components) != 1 || len(result.Components[0].Issues) != 1 ||
+			!strings.Contains(result.Components[0].Issues[0], "DTSTAMP") {
+			t.Errorf("expected a DTSTAMP issue on the event, got %+v", result.Components)
+		}
+	})
+
+	t.Run("empty payload", func(t *testing.T) {
+		if w, _ := validate(""); w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", w.Code)
+		}
+	})
+}
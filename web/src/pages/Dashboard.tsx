@@ -464,7 +464,7 @@ export default function Dashboard() {
                                   ? 'bg-green-900/50 text-green-400'
                                   : source.sync_status === 'partial'
                                   ? 'bg-yellow-900/50 text-yellow-400'
-                                  : source.sync_status === 'error'
+                                  : source.sync_status === 'error' || source.sync_status === 'auth_error'
                                   ? 'bg-red-900/50 text-red-400'
                                   : source.sync_status === 'running'
                                   ? 'bg-blue-900/50 text-blue-400 animate-pulse'
@@ -480,6 +480,8 @@ export default function Dashboard() {
                                 ? 'Partial'
                                 : source.sync_status === 'error'
                                 ? 'Error'
+                                : source.sync_status === 'auth_error'
+                                ? 'Auth failed'
                                 : source.sync_status === 'running'
                                 ? 'Syncing'
                                 : 'Pending'}
@@ -499,7 +501,12 @@ export default function Dashboard() {
                           )}
                         </div>
                       </td>
-                      <td className="px-4 py-3 text-gray-400">{formatDate(source.last_sync_at)}</td>
+                      <td className="px-4 py-3 text-gray-400">
+                        {formatDate(source.last_sync_at)}
+                        {source.last_success_at && source.last_success_at !== source.last_sync_at && (
+                          <div className="text-xs text-gray-500">Last success {formatDate(source.last_success_at)}</div>
+                        )}
+                      </td>
                       <td className="px-4 py-3 text-gray-400">
                         {source.enabled ? formatDate(source.next_sync_at) : '-'}
                       </td>
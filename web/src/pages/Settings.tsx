@@ -14,6 +14,7 @@ export default function Settings() {
     webhook_enabled: null,
     webhook_url: '',
     cooldown_minutes: null,
+    alert_on_partial: false,
   });
   const [logStats, setLogStats] = useState<LogStatsData | null>(null);
   const [loading, setLoading] = useState(true);
@@ -205,6 +206,23 @@ export default function Settings() {
               <option value="1440">24 hours</option>
             </select>
           </div>
+
+          {/* Partial syncs */}
+          <div className="flex items-center justify-between">
+            <div>
+              <label className="text-sm font-medium text-white">Alert on Partial Syncs</label>
+              <p className="text-xs text-gray-500 mt-1">Treat syncs that finish with warnings (such as a single failed event) as failures</p>
+            </div>
+            <label className="relative inline-flex items-center cursor-pointer">
+              <input
+                type="checkbox"
+                checked={preferences.alert_on_partial}
+                onChange={(e) => setPreferences({ ...preferences, alert_on_partial: e.target.checked })}
+                className="sr-only peer"
+              />
+              <div className="w-11 h-6 bg-zinc-700 peer-focus:outline-none peer-focus:ring-2 peer-focus:ring-red-500 rounded-full peer peer-checked:after:translate-x-full peer-checked:after:border-white after:content-[''] after:absolute after:top-[2px] after:left-[2px] after:bg-white after:rounded-full after:h-5 after:w-5 after:transition-all peer-checked:bg-red-600"></div>
+            </label>
+          </div>
         </div>
       </div>
 
@@ -72,7 +72,7 @@ export default function SourceAdd() {
       const discovered = await discoverCalendars(form.source_url, form.source_username, form.source_password);
       setCalendars(discovered);
       // By default, select all calendars with source default sync direction
-      setForm(prev => ({ ...prev, selected_calendars: discovered.map(c => ({ path: c.path, sync_direction: '' })) }));
+      setForm(prev => ({ ...prev, selected_calendars: discovered.map(c => ({ path: c.path, name: c.name, sync_direction: '' })) }));
     } catch (err: unknown) {
       if (err && typeof err === 'object' && 'response' in err) {
         const axiosErr = err as { response?: { data?: { error?: string } } };
@@ -92,12 +92,12 @@ export default function SourceAdd() {
         ...prev,
         selected_calendars: isSelected
           ? prev.selected_calendars.filter(c => c.path !== path)
-          : [...prev.selected_calendars, { path, sync_direction: '' }]
+          : [...prev.selected_calendars, { path, name: calendars.find(c => c.path === path)?.name, sync_direction: '' }]
       };
     });
   };
 
-  const handleCalendarSyncDirection = (path: string, direction: '' | 'one_way' | 'two_way') => {
+  const handleCalendarSyncDirection = (path: string, direction: '' | 'one_way' | 'one_way_create_only' | 'two_way') => {
     setForm(prev => ({
       ...prev,
       selected_calendars: prev.selected_calendars.map(c =>
@@ -118,6 +118,14 @@ export default function SourceAdd() {
   // password fields are hidden because they come from Google after
   // the user approves consent, not from the form.
   const isGoogleOAuth = form.source_type === 'google';
+  // Providers with a fixed CalDAV endpoint; the server fills it in when
+  // the URL is left blank (mirrors db.SourcePresets).
+  const presetURLs: Record<string, string> = {
+    icloud: 'https://caldav.icloud.com/',
+    fastmail: 'https://caldav.fastmail.com/dav/',
+    outlook: 'https://outlook.office365.com/caldav/',
+  };
+  const presetURL = presetURLs[form.source_type] || '';
 
   const handleChange = (e: React.ChangeEvent<HTMLInputElement | HTMLSelectElement>) => {
     const { name, value, type } = e.target;
@@ -278,6 +286,7 @@ export default function SourceAdd() {
                     </label>
                     <select name="sync_direction" id="sync_direction" value={form.sync_direction} onChange={handleChange} required disabled={isICS} className="w-full">
                       <option value="one_way">One-way (Source to Dest)</option>
+                      <option value="one_way_create_only">Create only (new events, never update or delete)</option>
                       <option value="two_way">Two-way (Bidirectional)</option>
                     </select>
                     {isICS && <p className="text-xs text-gray-500 mt-1">ICS feeds are read-only (one-way only)</p>}
@@ -290,6 +299,7 @@ export default function SourceAdd() {
                       <option value="source_wins">Source wins</option>
                       <option value="dest_wins">Dest wins</option>
                       <option value="latest_wins">Newest wins</option>
+                      <option value="merge">Merge fields</option>
                     </select>
                   </div>
                 </div>
@@ -379,10 +389,11 @@ export default function SourceAdd() {
                         id="source_url"
                         value={form.source_url}
                         onChange={handleChange}
-                        required
-                        placeholder={isICS ? 'https://example.com/calendar.ics' : 'https://caldav.example.com/calendars/user/'}
+                        required={!presetURL}
+                        placeholder={isICS ? 'https://example.com/calendar.ics' : presetURL || 'https://caldav.example.com/calendars/user/'}
                         className="w-full"
                       />
+                      {presetURL && <p className="text-xs text-gray-500 mt-1">Leave blank to use {presetURL}</p>}
                     </div>
                     <div className="grid grid-cols-1 sm:grid-cols-2 gap-4">
                       <div>
@@ -411,8 +422,12 @@ export default function SourceAdd() {
                           value={form.source_password}
                           onChange={handleChange}
                           required={!isICS}
+                          placeholder={form.source_type === 'icloud' ? 'xxxx-xxxx-xxxx-xxxx' : undefined}
                           className="w-full"
                         />
+                        {form.source_type === 'icloud' && (
+                          <p className="text-xs text-gray-500 mt-1">App-specific password from appleid.apple.com</p>
+                        )}
                       </div>
                     </div>
                   </>
@@ -463,12 +478,13 @@ export default function SourceAdd() {
                                 {selected && (
                                   <select
                                     value={getCalendarSyncDirection(cal.path)}
-                                    onChange={(e) => { e.stopPropagation(); handleCalendarSyncDirection(cal.path, e.target.value as '' | 'one_way' | 'two_way'); }}
+                                    onChange={(e) => { e.stopPropagation(); handleCalendarSyncDirection(cal.path, e.target.value as '' | 'one_way' | 'one_way_create_only' | 'two_way'); }}
                                     onClick={(e) => e.stopPropagation()}
                                     className="text-xs bg-zinc-800 border-zinc-600 rounded px-2 py-0.5"
                                   >
                                     <option value="">Source default</option>
                                     <option value="one_way">One-way</option>
+                                    <option value="one_way_create_only">Create only</option>
                                     <option value="two_way">Two-way</option>
                                   </select>
                                 )}
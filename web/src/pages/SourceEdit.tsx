@@ -25,7 +25,7 @@ export default function SourceEdit() {
     dest_password: '',
     sync_interval: 3600,
     sync_days_past: 30,
-    sync_direction: 'one_way' as 'one_way' | 'two_way',
+    sync_direction: 'one_way' as 'one_way' | 'one_way_create_only' | 'two_way',
     conflict_strategy: 'source_wins',
     selected_calendars: [] as CalendarConfig[],
     strip_alarms: false,
@@ -87,7 +87,7 @@ export default function SourceEdit() {
       setCalendars(discovered);
       // If no calendars selected yet, select all by default with source default sync direction
       if (form.selected_calendars.length === 0) {
-        setForm(prev => ({ ...prev, selected_calendars: discovered.map(c => ({ path: c.path, sync_direction: '' })) }));
+        setForm(prev => ({ ...prev, selected_calendars: discovered.map(c => ({ path: c.path, name: c.name, sync_direction: '' })) }));
       }
     } catch (err: unknown) {
       if (err && typeof err === 'object' && 'response' in err) {
@@ -108,12 +108,12 @@ export default function SourceEdit() {
         ...prev,
         selected_calendars: isSelected
           ? prev.selected_calendars.filter(c => c.path !== path)
-          : [...prev.selected_calendars, { path, sync_direction: '' }]
+          : [...prev.selected_calendars, { path, name: calendars.find(c => c.path === path)?.name, sync_direction: '' }]
       };
     });
   };
 
-  const handleCalendarSyncDirection = (path: string, direction: '' | 'one_way' | 'two_way') => {
+  const handleCalendarSyncDirection = (path: string, direction: '' | 'one_way' | 'one_way_create_only' | 'two_way') => {
     setForm(prev => ({
       ...prev,
       selected_calendars: prev.selected_calendars.map(c =>
@@ -294,6 +294,7 @@ export default function SourceEdit() {
                 </label>
                 <select name="sync_direction" id="sync_direction" value={form.sync_direction} onChange={handleChange} required disabled={isICS} className="w-full">
                   <option value="one_way">One-way (Source to Dest)</option>
+                  <option value="one_way_create_only">Create only (new events, never update or delete)</option>
                   <option value="two_way">Two-way (Bidirectional)</option>
                 </select>
                 {isICS && <p className="text-xs text-gray-500 mt-1">ICS feeds are read-only (one-way only)</p>}
@@ -306,6 +307,7 @@ export default function SourceEdit() {
                   <option value="source_wins">Source wins</option>
                   <option value="dest_wins">Dest wins</option>
                   <option value="latest_wins">Newest wins</option>
+                  <option value="merge">Merge fields</option>
                 </select>
               </div>
             </div>
@@ -407,12 +409,13 @@ export default function SourceEdit() {
                             {selected && (
                               <select
                                 value={getCalendarSyncDirection(cal.path)}
-                                onChange={(e) => { e.stopPropagation(); handleCalendarSyncDirection(cal.path, e.target.value as '' | 'one_way' | 'two_way'); }}
+                                onChange={(e) => { e.stopPropagation(); handleCalendarSyncDirection(cal.path, e.target.value as '' | 'one_way' | 'one_way_create_only' | 'two_way'); }}
                                 onClick={(e) => e.stopPropagation()}
                                 className="text-xs bg-zinc-800 border-zinc-600 rounded px-2 py-0.5"
                               >
                                 <option value="">Source default</option>
                                 <option value="one_way">One-way</option>
+                                <option value="one_way_create_only">Create only</option>
                                 <option value="two_way">Two-way</option>
                               </select>
                             )}
@@ -485,7 +488,7 @@ export default function SourceEdit() {
                         ? 'text-green-400'
                         : source.sync_status === 'partial'
                         ? 'text-yellow-400'
-                        : source.sync_status === 'error'
+                        : source.sync_status === 'error' || source.sync_status === 'auth_error'
                         ? 'text-red-400'
                         : 'text-gray-400'
                     }
@@ -496,6 +499,8 @@ export default function SourceEdit() {
                       ? 'Partial'
                       : source.sync_status === 'error'
                       ? 'Error'
+                      : source.sync_status === 'auth_error'
+                      ? 'Auth failed'
                       : source.sync_status === 'running'
                       ? 'Running'
                       : 'Pending'}
@@ -505,6 +510,10 @@ export default function SourceEdit() {
                   <p className="text-gray-500 text-xs">Last Sync</p>
                   <p className="text-white">{formatDate(source.last_sync_at)}</p>
                 </div>
+                <div>
+                  <p className="text-gray-500 text-xs">Last Successful Sync</p>
+                  <p className="text-white">{formatDate(source.last_success_at)}</p>
+                </div>
                 <div>
                   <p className="text-gray-500 text-xs">Created</p>
                   <p className="text-white">{formatDate(source.created_at)}</p>
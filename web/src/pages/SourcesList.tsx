@@ -150,7 +150,7 @@ export default function SourcesList() {
                                   ? 'text-green-400'
                                   : source.sync_status === 'partial'
                                   ? 'text-yellow-400'
-                                  : source.sync_status === 'error'
+                                  : source.sync_status === 'error' || source.sync_status === 'auth_error'
                                   ? 'text-red-400'
                                   : source.sync_status === 'running'
                                   ? 'text-blue-400'
@@ -163,6 +163,8 @@ export default function SourcesList() {
                                 ? 'Warn'
                                 : source.sync_status === 'error'
                                 ? 'Err'
+                                : source.sync_status === 'auth_error'
+                                ? 'Auth'
                                 : source.sync_status === 'running'
                                 ? '...'
                                 : '-'}
@@ -1,5 +1,5 @@
 import axios from 'axios';
-import type { Source, SyncLog, DashboardStats, SourceFormData, AuthStatus, SyncHistory, MalformedEvent, Calendar, AlertPreferences, ActivityData, Destination } from '../types';
+import type { Source, SyncLog, DashboardStats, DashboardOverview, SourceFormData, AuthStatus, SyncHistory, MalformedEvent, MalformedEventSummary, FailedEvent, StampCleanupResult, PurgeResult, ICSCheckResult, SearchResult, Calendar, SyncLogDetail, AlertPreferences, ActivityData, SourceActivitySnapshot, Destination, SchedulerHealth } from '../types';
 
 const api = axios.create({
   baseURL: '/api',
@@ -8,9 +8,22 @@ const api = axios.create({
   },
 });
 
+// The session's CSRF token, from the last auth status. The server refuses
+// state-changing requests without it.
+let csrfToken = '';
+
+api.interceptors.request.use((config) => {
+  const method = (config.method ?? 'get').toLowerCase();
+  if (csrfToken && !['get', 'head', 'options'].includes(method)) {
+    config.headers.set('X-CSRF-Token', csrfToken);
+  }
+  return config;
+});
+
 // Auth
 export const getAuthStatus = async (): Promise<AuthStatus> => {
   const response = await api.get('/auth/status');
+  csrfToken = response.data.csrf_token ?? '';
   return response.data;
 };
 
@@ -29,14 +42,23 @@ export const getDashboardStats = async (): Promise<DashboardStats> => {
   return response.data;
 };
 
+export const getDashboardOverview = async (): Promise<DashboardOverview> => {
+  const response = await api.get('/dashboard/overview');
+  return response.data;
+};
+
 export const getSyncHistory = async (days: number = 7): Promise<SyncHistory> => {
   const response = await api.get('/dashboard/sync-history', { params: { days } });
   return response.data;
 };
 
 // Sources
-export const getSources = async (): Promise<Source[]> => {
-  const response = await api.get('/sources');
+// With tags, only sources carrying at least one of them are returned.
+export const getSources = async (tags: string[] = []): Promise<Source[]> => {
+  const params = new URLSearchParams();
+  tags.forEach((tag) => params.append('tag', tag));
+  const query = params.toString();
+  const response = await api.get(query ? `/sources?${query}` : '/sources');
   return response.data;
 };
 
@@ -101,6 +123,26 @@ export const triggerSync = async (id: string): Promise<void> => {
   await api.post(`/sources/${id}/sync`);
 };
 
+export const cancelSync = async (id: string): Promise<{ message: string }> => {
+  const response = await api.post(`/sources/${id}/cancel`);
+  return response.data;
+};
+
+export const syncAllSources = async (): Promise<{ message: string; source_ids: string[] }> => {
+  const response = await api.post('/sources/sync-all');
+  return response.data;
+};
+
+export const undoLastSync = async (id: string): Promise<{
+  deleted_at: string;
+  restored: number;
+  failed: number;
+  errors?: string[];
+}> => {
+  const response = await api.post(`/sources/${id}/undo-last-sync`);
+  return response.data;
+};
+
 export const dryRunSync = async (id: string): Promise<{
   success: boolean;
   created: number;
@@ -115,6 +157,21 @@ export const dryRunSync = async (id: string): Promise<{
   return response.data;
 };
 
+export const syncCalendar = async (id: string, calendarPath: string): Promise<{
+  success: boolean;
+  created: number;
+  updated: number;
+  deleted: number;
+  skipped: number;
+  calendar: string;
+  message: string;
+  errors?: string[];
+  warnings?: string[];
+}> => {
+  const response = await api.post(`/sources/${id}/sync`, null, { params: { calendar: calendarPath } });
+  return response.data;
+};
+
 export const getSourceStats = async (id: string): Promise<{
   synced_event_count: number;
   malformed_count: number;
@@ -127,18 +184,67 @@ export const getSourceStats = async (id: string): Promise<{
   return response.data;
 };
 
+export const getFailedEvents = async (id: string): Promise<FailedEvent[]> => {
+  const response = await api.get(`/sources/${id}/failed-events`);
+  return response.data;
+};
+
+// Retries one dead-lettered event, or all of the source's when none is given.
+export const retryFailedEvents = async (
+  id: string,
+  event?: { calendar_href: string; event_uid: string },
+): Promise<{ message: string; retried: number }> => {
+  const response = await api.post(`/sources/${id}/failed-events/retry`, event ?? {});
+  return response.data;
+};
+
+// Deletes the destination events carrying a stamp, the source's own when none
+// is given. Every source writing the stamp must be disabled.
+export const deleteStampedEvents = async (id: string, stamp?: string): Promise<StampCleanupResult> => {
+  const response = await api.delete(`/sources/${id}/stamped-events`, { params: stamp ? { stamp } : undefined });
+  return response.data;
+};
+
+// Deletes every event the source synced to its destination and forgets them.
+export const purgeDestination = async (id: string): Promise<PurgeResult> => {
+  const response = await api.post(`/sources/${id}/purge-destination`, { confirm: true });
+  return response.data;
+};
+
+// Checks that an .ics payload would parse and encode cleanly in a sync.
+// Nothing is stored.
+export const validateICS = async (ics: string): Promise<ICSCheckResult> => {
+  const response = await api.post('/tools/validate-ics', { ics });
+  return response.data;
+};
+
 // Logs
 export const getSourceLogs = async (sourceId: string, page: number = 1): Promise<{ logs: SyncLog[]; total_pages: number; page: number }> => {
   const response = await api.get(`/sources/${sourceId}/logs`, { params: { page } });
   return response.data;
 };
 
+export const deleteSourceLogs = async (sourceId: string): Promise<{ message: string; deleted: number }> => {
+  const response = await api.delete(`/sources/${sourceId}/logs`);
+  return response.data;
+};
+
+export const getSourceLog = async (sourceId: string, logId: string): Promise<SyncLogDetail> => {
+  const response = await api.get(`/sources/${sourceId}/logs/${logId}`);
+  return response.data;
+};
+
 // Malformed Events
 export const getMalformedEvents = async (): Promise<MalformedEvent[]> => {
   const response = await api.get('/malformed-events');
   return response.data;
 };
 
+export const getMalformedEventSummary = async (): Promise<MalformedEventSummary> => {
+  const response = await api.get('/malformed-events/summary');
+  return response.data;
+};
+
 export const deleteMalformedEvent = async (id: string): Promise<void> => {
   await api.delete(`/malformed-events/${id}`);
 };
@@ -148,9 +254,15 @@ export const deleteAllMalformedEvents = async (): Promise<{ deleted: number }> =
   return response.data;
 };
 
+// Search
+export const search = async (q: string): Promise<SearchResult> => {
+  const response = await api.get('/search', { params: { q } });
+  return response.data;
+};
+
 // Calendar Discovery
-export const discoverCalendars = async (url: string, username: string, password: string): Promise<Calendar[]> => {
-  const response = await api.post('/calendars/discover', { url, username, password });
+export const discoverCalendars = async (url: string, username: string, password: string, caBundle?: string, proxyURL?: string): Promise<Calendar[]> => {
+  const response = await api.post('/calendars/discover', { url, username, password, ca_bundle: caBundle, proxy_url: proxyURL });
   return response.data;
 };
 
@@ -169,6 +281,11 @@ export const testWebhook = async (url: string): Promise<void> => {
   await api.post('/settings/alerts/test-webhook', { webhook_url: url });
 };
 
+export const reloadAlertConfig = async (): Promise<{ webhook_enabled: boolean; email_enabled: boolean }> => {
+  const response = await api.post('/admin/alerts/reload');
+  return response.data;
+};
+
 export const getAuditLogs = async (page: number = 1): Promise<{ logs: { id: string; action: string; resource_type: string; resource_id: string; details: string; ip_address: string; created_at: string }[]; total_pages: number }> => {
   const response = await api.get(`/audit-logs?page=${page}`);
   return response.data;
@@ -190,6 +307,21 @@ export const getActivity = async (): Promise<ActivityData> => {
   return response.data;
 };
 
+export const getSourceActivity = async (sourceId: string): Promise<SourceActivitySnapshot> => {
+  const response = await api.get(`/sources/${sourceId}/activity`);
+  return response.data;
+};
+
+// Server-Sent Events stream of one source's sync progress. The caller
+// listens for "progress" and "done" events and closes the source.
+export const openSourceActivityStream = (sourceId: string): EventSource =>
+  new EventSource(`/api/sources/${sourceId}/activity/stream`);
+
+export const getHealthDetailed = async (): Promise<SchedulerHealth> => {
+  const response = await api.get('/health/detailed');
+  return response.data;
+};
+
 // Destinations (multi-destination sync #156)
 export const getDestinations = async (sourceId: string): Promise<Destination[]> => {
   const response = await api.get(`/sources/${sourceId}/destinations`);
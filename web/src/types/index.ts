@@ -1,8 +1,18 @@
+// A find/replace step applied to event summaries or descriptions before
+// they are written. Find is literal unless regex is set; "^" prefixes.
+export interface TextRule {
+  field: 'summary' | 'description';
+  find: string;
+  replace: string;
+  regex?: boolean;
+}
+
 export interface User {
   id: string;
   email: string;
   name: string;
   avatar?: string;
+  is_admin?: boolean;
 }
 
 export interface Source {
@@ -15,13 +25,45 @@ export interface Source {
   dest_username: string;
   sync_interval: number;
   sync_days_past: number;
-  sync_direction: 'one_way' | 'two_way';
+  sync_direction: 'one_way' | 'one_way_create_only' | 'two_way';
   conflict_strategy: string;
   selected_calendars: CalendarConfig[];
   enabled: boolean;
   strip_alarms: boolean;
+  attendee_rewrite?: Record<string, string>;
+  uid_prefix?: string;
+  result_webhook_url?: string;
+  max_requests_per_second?: number;
+  max_sync_duration_secs?: number;
+  initial_sync_event_cap?: number;
+  custom_headers?: Record<string, string>;
+  verify_writes?: boolean;
+  include_categories?: string[];
+  tags?: string[];
+  preserve_dest_props?: string[];
+  text_rules?: TextRule[];
+  source_stamp?: string;
+  ca_bundle?: string;
+  proxy_url?: string;
+  target_timezone?: string;
+  sync_journals?: boolean;
+  dest_read_only?: boolean;
+  sync_attachments?: boolean;
+  generate_missing_uids?: boolean;
+  skip_cancelled?: boolean;
+  skip_invited_events?: boolean;
+  account_email?: string;
+  minimize_timezones?: boolean;
+  // Per-source alert channel overrides; null follows the user's
+  // alert preferences.
+  alert_email_enabled?: boolean | null;
+  alert_webhook_enabled?: boolean | null;
   sync_status: string;
   last_sync_at: string | null;
+  last_success_at: string | null;
+  last_error_detail?: string;
+  // Source clock minus destination clock, as last measured.
+  clock_skew_seconds?: number;
   next_sync_at: string | null;
   is_stale: boolean;
   created_at: string;
@@ -37,7 +79,8 @@ export interface Calendar {
 
 export interface CalendarConfig {
   path: string;
-  sync_direction?: 'one_way' | 'two_way' | ''; // empty = use source default
+  name?: string; // display name; lets the server re-match a calendar whose path changed
+  sync_direction?: 'one_way' | 'one_way_create_only' | 'two_way' | ''; // empty = use source default
 }
 
 export interface SyncLog {
@@ -56,6 +99,19 @@ export interface SyncLog {
   created_at: string;
 }
 
+export interface SyncConflict {
+  uid: string;
+  summary: string;
+  winner: string;
+  strategy: string;
+}
+
+export interface SyncLogDetail {
+  log: SyncLog;
+  conflicts: SyncConflict[];
+  malformed_events: MalformedEvent[];
+}
+
 export interface DashboardStats {
   total_sources: number;
   active_sources: number;
@@ -63,6 +119,20 @@ export interface DashboardStats {
   failed_syncs: number;
 }
 
+export interface DashboardOverview {
+  total_sources: number;
+  active_sources: number;
+  paused_sources: number;
+  failing_sources: number;
+  syncs_today: number;
+  events_synced_today: number;
+  events_synced_7d: number;
+  syncs_7d: number;
+  // Percentage of the last 7 days' runs that did not fail.
+  success_rate: number;
+  stale_sources: number;
+}
+
 export interface SourceFormData {
   name: string;
   source_type: string;
@@ -74,10 +144,64 @@ export interface SourceFormData {
   dest_password: string;
   sync_interval: number;
   sync_days_past: number;
-  sync_direction: 'one_way' | 'two_way';
+  sync_direction: 'one_way' | 'one_way_create_only' | 'two_way';
   conflict_strategy: string;
   selected_calendars: CalendarConfig[];
   strip_alarms: boolean;
+  // ORGANIZER/ATTENDEE address rewrites (from → to). Omit to keep
+  // the stored mapping; send {} to clear it.
+  attendee_rewrite?: Record<string, string>;
+  uid_prefix?: string;
+  result_webhook_url?: string;
+  max_requests_per_second?: number;
+  // Seconds one sync may run before it is canceled as timed out;
+  // 0 uses the server default (SYNC_MAX_DURATION_SECS).
+  max_sync_duration_secs?: number;
+  // Most source events a calendar's first sync fetches, keeping the
+  // most recent; 0 fetches them all.
+  initial_sync_event_cap?: number;
+  custom_headers?: Record<string, string>;
+  verify_writes?: boolean;
+  // Only sync events tagged with one of these CATEGORIES. Omit to keep
+  // the stored filter; send [] to remove it.
+  include_categories?: string[];
+  tags?: string[];
+  // iCalendar properties (e.g. X-NOTE) an update keeps from the
+  // destination copy. Omit to keep the stored list; send [] to clear it.
+  preserve_dest_props?: string[];
+  // Summary/description rewrites, applied in order. Omit to keep the
+  // stored rules; send [] to clear them.
+  text_rules?: TextRule[];
+  // X-CALBRIDGE-SOURCE value written to synced events; {source_name}
+  // and {source_id} are expanded. Omit to keep it; send "" to stop stamping.
+  source_stamp?: string;
+  // PEM CA certificates trusted on top of the system store, for servers
+  // behind an internal CA. Omit to keep it; send "" to remove it.
+  ca_bundle?: string;
+  // HTTP(S) proxy for this source's CalDAV requests. Omit to keep it; send
+  // "" to use the server's.
+  proxy_url?: string;
+  // IANA zone to rewrite timed events into, e.g. "America/New_York".
+  // Omit to keep the stored value; send "" to turn conversion off.
+  target_timezone?: string;
+  // Copy VJOURNAL entries instead of skipping them.
+  sync_journals?: boolean;
+  // Never write to the destination; the sync runs one way with
+  // deletions off.
+  dest_read_only?: boolean;
+  // Keep inline attachments of any size; off drops large ones.
+  // Defaults to true when creating a source.
+  sync_attachments?: boolean;
+  generate_missing_uids?: boolean;
+  skip_cancelled?: boolean;
+  // Sync only events account_email organizes. account_email defaults to
+  // the source username when that is an email address.
+  skip_invited_events?: boolean;
+  account_email?: string;
+  minimize_timezones?: boolean;
+  // null (on update) resets to the user's alert preferences.
+  alert_email_enabled?: boolean | null;
+  alert_webhook_enabled?: boolean | null;
   // Per-source Google OAuth credentials (#79). Only populated when
   // source_type === 'google'. Empty for all other source types.
   google_client_id?: string;
@@ -92,6 +216,8 @@ export interface ApiResponse<T> {
 export interface AuthStatus {
   authenticated: boolean;
   user?: User;
+  // Sent back as X-CSRF-Token on state-changing requests.
+  csrf_token?: string;
 }
 
 export interface SyncHistoryPoint {
@@ -124,14 +250,76 @@ export interface MalformedEvent {
   source_name: string;
   event_path: string;
   error_message: string;
+  // Cause derived from error_message, e.g. "missing_dtstamp" or "empty".
+  category: string;
   discovered_at: string;
 }
 
+// An event left out of syncs after its destination write failed too
+// many times in a row, until it is retried.
+export interface FailedEvent {
+  calendar_href: string;
+  event_uid: string;
+  failure_count: number;
+  last_error: string;
+  first_failed_at: string;
+  last_failed_at: string;
+}
+
+// What deleting a source stamp's destination events removed.
+export interface StampCleanupResult {
+  stamp: string;
+  deleted: number;
+  failed: number;
+  errors?: string[];
+}
+
+// What purging a source's events from its destination removed.
+export interface PurgeResult {
+  deleted: number;
+  not_found: number;
+  failed: number;
+  errors?: string[];
+}
+
+export interface ICSComponentCheck {
+  component: string;
+  uid?: string;
+  recurrence_id?: string;
+  summary?: string;
+  issues: string[];
+}
+
+export interface ICSCheckResult {
+  parsed: boolean;
+  parse_error?: string;
+  encodes: boolean;
+  encode_error?: string;
+  // Whether it encodes once a sync fills in missing DTSTAMPs.
+  encodes_after_repair: boolean;
+  components: ICSComponentCheck[];
+}
+
+export interface MalformedEventSummary {
+  total: number;
+  categories: Record<string, number>;
+}
+
+export interface SearchResult {
+  query: string;
+  sources: { id: string; name: string }[];
+  sync_logs: SyncLog[];
+  malformed_events: MalformedEvent[];
+  // Set when either list hit the server's result limit.
+  truncated: boolean;
+}
+
 export interface AlertPreferences {
   email_enabled: boolean | null;
   webhook_enabled: boolean | null;
   webhook_url: string;
   cooldown_minutes: number | null;
+  alert_on_partial: boolean;
 }
 
 export interface SyncActivity {
@@ -158,6 +346,49 @@ export interface ActivityData {
   recent: SyncActivity[];
 }
 
+export interface SourceActivitySnapshot {
+  source_id: string;
+  status: 'running' | 'idle';
+  running: boolean;
+  current_calendar?: string;
+  calendar_index: number;
+  total_calendars: number;
+  events_processed: number;
+  events_created: number;
+  events_updated: number;
+  events_deleted: number;
+  events_skipped: number;
+  started_at?: string;
+  duration?: string;
+}
+
+export interface SourceHealth {
+  id: string;
+  name: string;
+  last_sync_status: string;
+  last_sync_at?: string;
+  last_success_at?: string;
+  next_sync_at?: string;
+  stale: boolean;
+  syncing_since?: string;
+  syncs: number;
+  failed_syncs: number;
+  error_rate: number;
+}
+
+export interface SchedulerHealth {
+  generated_at: string;
+  active_jobs: number;
+  in_flight: number;
+  max_concurrent: number;
+  error_window_hours: number;
+  syncs: number;
+  failed_syncs: number;
+  error_rate: number;
+  stale: string[];
+  sources: SourceHealth[];
+}
+
 export interface Destination {
   id: string;
   source_id: string;
@@ -168,3 +399,13 @@ export interface Destination {
   created_at: string;
   updated_at: string;
 }
+
+// Body of every API error response. code is stable (e.g.
+// SOURCE_NOT_FOUND, VALIDATION_ERROR); message is for display. error
+// repeats message for older clients.
+export interface APIError {
+  code: string;
+  message: string;
+  details?: unknown;
+  error: string;
+}